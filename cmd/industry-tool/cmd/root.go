@@ -11,7 +11,9 @@ import (
 
 	"github.com/annymsMthd/industry-tool/internal/client"
 	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/events"
 	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/notifications"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/runners"
 	"github.com/annymsMthd/industry-tool/internal/updaters"
@@ -67,36 +69,95 @@ var rootCmd = &cobra.Command{
 		playerCorporationRepostiory := repositories.NewPlayerCorporations(db)
 		playerCorporationAssetsRepository := repositories.NewCorporationAssets(db)
 		stockpileMarkersRepository := repositories.NewStockpileMarkers(db)
+		stockpileTemplatesRepository := repositories.NewStockpileTemplates(db)
 		marketPricesRepository := repositories.NewMarketPrices(db)
 		contactsRepository := repositories.NewContacts(db)
 		contactPermissionsRepository := repositories.NewContactPermissions(db)
+		contactGroupsRepository := repositories.NewContactGroups(db)
+		contactInvitesRepository := repositories.NewContactInvites(db)
 		forSaleItemsRepository := repositories.NewForSaleItems(db)
 		purchaseTransactionsRepository := repositories.NewPurchaseTransactions(db)
 		buyOrdersRepository := repositories.NewBuyOrders(db)
+		buildRequestsRepository := repositories.NewBuildRequests(db)
+		haulingRequestsRepository := repositories.NewHaulingRequests(db)
 		salesAnalyticsRepository := repositories.NewSalesAnalytics(db)
+		purchaseExpirySettingsRepository := repositories.NewPurchaseExpirySettings(db)
+		reputationRepository := repositories.NewReputation(db)
+		appraisalsRepository := repositories.NewAppraisals(db)
+		stockpileBuyOrdersRepository := repositories.NewStockpileBuyOrders(db)
+		stockpileAlertsRepository := repositories.NewStockpileAlerts(db)
+		stockpileHistoryRepository := repositories.NewStockpileHistory(db)
+		itemMetadataRepository := repositories.NewItemMetadata(db)
+		notificationsRepository := repositories.NewNotifications(db)
+		notificationSettingsRepository := repositories.NewNotificationSettings(db)
+		webhooksRepository := repositories.NewWebhooks(db)
+		characterAffiliationsRepository := repositories.NewCharacterAffiliations(db)
+		affiliationPermissionsRepository := repositories.NewAffiliationPermissions(db)
 
 		esiClient := client.NewEsiClient(settings.OAuthClientID, settings.OAuthClientSecret)
 
-		assetUpdater := updaters.NewAssets(charactersAssetRepository, charactersRepository, stationsRepository, playerCorporationRepostiory, playerCorporationAssetsRepository, esiClient)
-		staticUpdater := updaters.NewStatic(fuzzWorks, itemTypesRepository, regionsRepository, constellationsRepository, systemRepository, stationsRepository)
+		notificationDispatcher := notifications.NewDispatcher(
+			notifications.NewInApp(notificationsRepository),
+			notifications.NewWebhook(notifications.NewWebhookHTTPClient(10*time.Second)),
+			notifications.NewEveMail(charactersRepository, esiClient),
+		)
+
+		eventBus := events.NewBus()
+
+		forSaleStockUpdater := updaters.NewForSaleStock(forSaleItemsRepository)
+		stockpileBuyOrdersUpdater := updaters.NewStockpileBuyOrders(stockpileBuyOrdersRepository, buyOrdersRepository).
+			WithEvents(eventBus)
+		stockpileAlertsUpdater := updaters.NewStockpileAlerts(stockpileAlertsRepository, notificationSettingsRepository, notificationDispatcher)
+		stockpileHistoryUpdater := updaters.NewStockpileHistory(stockpileHistoryRepository)
+		assetUpdater := updaters.NewAssets(charactersAssetRepository, charactersRepository, stationsRepository, playerCorporationRepostiory, playerCorporationAssetsRepository, esiClient).
+			WithSyncHook(forSaleStockUpdater.ReconcileUserListings).
+			WithSyncHook(stockpileBuyOrdersUpdater.SyncUserBuyOrders).
+			WithSyncHook(stockpileAlertsUpdater.CheckUserAlerts).
+			WithSyncHook(stockpileHistoryUpdater.RecordUserHistory)
+		staticUpdater := updaters.NewStatic(fuzzWorks, itemTypesRepository, itemMetadataRepository, regionsRepository, constellationsRepository, systemRepository, stationsRepository)
 		marketPricesUpdater := updaters.NewMarketPrices(marketPricesRepository, esiClient)
+		affiliationsUpdater := updaters.NewAffiliations(characterAffiliationsRepository, esiClient)
+
+		purchaseExpiryUpdater := updaters.NewPurchaseExpiry(db, purchaseTransactionsRepository, forSaleItemsRepository, settings.PurchaseExpiryDefaultHours).
+			WithEvents(eventBus)
+		webhookDeliveriesUpdater := updaters.NewWebhookDeliveries(webhooksRepository, notifications.NewWebhookHTTPClient(10*time.Second))
+		buyOrderMatchesUpdater := updaters.NewBuyOrderMatches(contactPermissionsRepository, forSaleItemsRepository, buyOrdersRepository, eventBus)
+		buyOrderMatchesQueue := events.NewQueue("buy order matches", buyOrderMatchesUpdater.Handle, 1000)
+		eventBus.Subscribe(webhookDeliveriesUpdater.Enqueue)
+		eventBus.Subscribe(buyOrderMatchesQueue.Handle)
 
 		controllers.NewStatic(router, staticUpdater)
-		controllers.NewCharacters(router, charactersRepository)
-		controllers.NewUsers(router, usersRepository, assetUpdater)
+		controllers.NewCharacters(router, charactersRepository, contactInvitesRepository)
+		controllers.NewUsers(router, usersRepository, assetUpdater, contactInvitesRepository)
 		controllers.NewAssets(router, assetsRepository)
 		controllers.NewCorporations(router, esiClient, playerCorporationRepostiory)
 		controllers.NewStockpileMarkers(router, stockpileMarkersRepository)
-		controllers.NewStockpiles(router, assetsRepository)
+		controllers.NewStockpileTemplates(router, stockpileTemplatesRepository, itemTypesRepository, stockpileMarkersRepository)
+		controllers.NewReplenishment(router, assetsRepository, forSaleItemsRepository, contactPermissionsRepository, itemTypesRepository, marketPricesRepository)
+		controllers.NewStockpiles(router, assetsRepository, stockpileHistoryRepository)
 		controllers.NewMarketPrices(router, marketPricesUpdater)
-		controllers.NewJanice(router)
-		controllers.NewContacts(router, contactsRepository, contactPermissionsRepository, db)
+		controllers.NewAppraisals(router, appraisalsRepository, itemTypesRepository, marketPricesRepository)
+		controllers.NewContacts(router, contactsRepository, contactPermissionsRepository, reputationRepository, contactInvitesRepository, esiClient, db).
+			WithEvents(eventBus)
 		controllers.NewContactPermissions(router, contactPermissionsRepository)
-		controllers.NewForSaleItems(router, forSaleItemsRepository, contactPermissionsRepository)
-		controllers.NewPurchases(router, db, purchaseTransactionsRepository, forSaleItemsRepository, contactPermissionsRepository)
-		controllers.NewBuyOrders(router, buyOrdersRepository, contactPermissionsRepository)
-		controllers.NewItemTypes(router, itemTypesRepository)
+		controllers.NewContactGroups(router, contactGroupsRepository)
+		controllers.NewContactAssets(router, contactsRepository, contactPermissionsRepository, assetsRepository)
+		controllers.NewAffiliationPermissions(router, affiliationPermissionsRepository)
+		controllers.NewForSaleItems(router, forSaleItemsRepository, contactPermissionsRepository, reputationRepository, contactGroupsRepository).
+			WithEvents(eventBus)
+		controllers.NewForSaleImport(router, forSaleItemsRepository, itemTypesRepository)
+		controllers.NewPurchases(router, db, purchaseTransactionsRepository, forSaleItemsRepository, contactPermissionsRepository).
+			WithEvents(eventBus)
+		controllers.NewPurchaseExpirySettings(router, purchaseExpirySettingsRepository, settings.PurchaseExpiryDefaultHours)
+		controllers.NewBuyOrders(router, buyOrdersRepository, contactPermissionsRepository, contactGroupsRepository).
+			WithEvents(eventBus)
+		controllers.NewBuildRequests(router, buildRequestsRepository, contactPermissionsRepository)
+		controllers.NewHaulingRequests(router, haulingRequestsRepository, contactPermissionsRepository)
+		controllers.NewItemTypes(router, itemTypesRepository, itemMetadataRepository)
 		controllers.NewAnalytics(router, salesAnalyticsRepository)
+		controllers.NewNotifications(router, notificationsRepository, notificationSettingsRepository, charactersRepository)
+		controllers.NewWebhooks(router, webhooksRepository)
+		controllers.NewExports(router, purchaseTransactionsRepository, salesAnalyticsRepository, assetsRepository)
 
 		group.Go(router.Run(ctx))
 
@@ -106,6 +167,29 @@ var rootCmd = &cobra.Command{
 			return marketPricesRunner.Run(ctx)
 		})
 
+		// Start stale purchase expiry sweep
+		purchaseExpiryRunner := runners.NewPurchaseExpiryRunner(purchaseExpiryUpdater, 15*time.Minute)
+		group.Go(func() error {
+			return purchaseExpiryRunner.Run(ctx)
+		})
+
+		// Send queued webhook deliveries and retry failed ones
+		webhookDeliveriesRunner := runners.NewWebhookDeliveriesRunner(webhookDeliveriesUpdater, 15*time.Second)
+		group.Go(func() error {
+			return webhookDeliveriesRunner.Run(ctx)
+		})
+
+		// Look for buy order matches outside the requests that published the changes
+		group.Go(func() error {
+			return buyOrderMatchesQueue.Run(ctx)
+		})
+
+		// Keep character corporation/alliance membership current for affiliation grants
+		affiliationsRunner := runners.NewAffiliationsRunner(affiliationsUpdater, time.Hour)
+		group.Go(func() error {
+			return affiliationsRunner.Run(ctx)
+		})
+
 		log.Info("services started")
 
 		eventChan := make(chan os.Signal, 1)
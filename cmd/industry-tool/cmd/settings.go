@@ -15,6 +15,9 @@ type Settings struct {
 	DatabaseSettings  database.PostgresDatabaseSettings
 	OAuthClientID     string
 	OAuthClientSecret string
+
+	// PurchaseExpiryDefaultHours applies to sellers that have not set their own timeout
+	PurchaseExpiryDefaultHours int
 }
 
 func GetSettings() (*Settings, error) {
@@ -43,5 +46,17 @@ func GetSettings() (*Settings, error) {
 		return nil, errors.Wrapf(err, "failed to convert database port '%s' to number", s)
 	}
 
+	settings.PurchaseExpiryDefaultHours = 72
+	s = os.Getenv("PURCHASE_EXPIRY_DEFAULT_HOURS")
+	if s != "" {
+		settings.PurchaseExpiryDefaultHours, err = strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "purchase expiry default hours '%s' is not a number", s)
+		}
+		if settings.PurchaseExpiryDefaultHours < 0 {
+			return nil, errors.Errorf("purchase expiry default hours must not be negative, got %d", settings.PurchaseExpiryDefaultHours)
+		}
+	}
+
 	return settings, nil
 }
@@ -46,6 +46,8 @@ let scopesArray = [
   "esi-corporations.read_freelance_jobs.v1",
 ];
 let playerScope = scopesArray.join(" ");
+// Only requested when a user turns on EVE mail alerts for the character
+let mailScope = "esi-mail.send_mail.v1";
 let corpScopesArray = [
   "esi-wallet.read_corporation_wallet.v1",
   "esi-search.search_structures.v1",
@@ -87,11 +89,18 @@ export async function verifyToken(
   };
 }
 
-export default async function getAuthUrl(isCorp: boolean): Promise<string> {
+export default async function getAuthUrl(
+  isCorp: boolean,
+  withMail: boolean = false,
+): Promise<string> {
   let code_challenge: string =
     await client.calculatePKCECodeChallenge(code_verifier);
 
-  let scope = isCorp ? corpScope : playerScope;
+  let scope = isCorp
+    ? corpScope
+    : withMail
+      ? `${playerScope} ${mailScope}`
+      : playerScope;
   let parameters: Record<string, string> = {
     redirect_uri: redirect_uri,
     scope,
@@ -101,7 +110,7 @@ export default async function getAuthUrl(isCorp: boolean): Promise<string> {
 
   state = client.randomState();
   parameters.state = state;
-  stateMaps[state] = isCorp ? "corp" : "char";
+  stateMaps[state] = isCorp ? "corp" : withMail ? "mail" : "char";
 
   let redirectTo: URL = client.buildAuthorizationUrl(config, parameters);
   return redirectTo.toString();
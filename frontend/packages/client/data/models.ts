@@ -58,6 +58,7 @@ export type AssetsResponse = {
 };
 
 export type StockpileMarker = {
+  id?: number;
   userId: number;
   typeId: number;
   ownerType: string;
@@ -67,4 +68,135 @@ export type StockpileMarker = {
   divisionNumber?: number;
   desiredQuantity: number;
   notes?: string;
+  scope?: string;
+  autoBuyOrder?: boolean;
+  buyOrderMarkupPercent?: number;
+  alertBelowQuantity?: number;
+  alertDeficitValue?: number;
+};
+
+export type Notification = {
+  id: number;
+  userId: number;
+  kind: string;
+  title: string;
+  message: string;
+  stockpileMarkerId?: number;
+  createdAt: string;
+  readAt?: string;
+};
+
+export type NotificationSettings = {
+  userId: number;
+  webhookUrl?: string | null;
+  mailCharacterId?: number | null;
+};
+
+export type Webhook = {
+  id: number;
+  userId: number;
+  url: string;
+  secret: string;
+  eventTypes: string[];
+  isActive: boolean;
+  createdAt: string;
+  updatedAt: string;
+};
+
+export type WebhookDelivery = {
+  id: number;
+  webhookId: number;
+  eventId: string;
+  eventType: string;
+  payload: unknown;
+  status: 'pending' | 'delivered' | 'failed';
+  attempts: number;
+  nextAttemptAt: string;
+  lastStatusCode?: number | null;
+  lastError?: string | null;
+  createdAt: string;
+  deliveredAt?: string | null;
+};
+
+export type StockpileMarkerBulkResult = {
+  operation: "upsert" | "delete";
+  index: number;
+  id?: number;
+  success: boolean;
+  error?: string;
+};
+
+export type StockpileHistorySample = {
+  markerId: number;
+  recordedAt: string;
+  quantity: number;
+};
+
+export type StockpileHistory = {
+  markerId: number;
+  samples: StockpileHistorySample[];
+  burnRatePerDay: number | null;
+  daysUntilEmpty: number | null;
+  recommendedQuantity: number | null;
+};
+
+export type ItemCategory = {
+  id: number;
+  name: string;
+  published: boolean;
+};
+
+export type ItemGroup = {
+  id: number;
+  categoryId: number;
+  name: string;
+  published: boolean;
+};
+
+export type MarketGroup = {
+  id: number;
+  parentId: number | null;
+  name: string;
+  hasTypes: boolean;
+};
+
+export type MetaGroup = {
+  id: number;
+  name: string;
+};
+
+export type ItemTypeFilter = {
+  groupId?: number[];
+  categoryId?: number[];
+  marketGroupId?: number[];
+  metaGroupId?: number[];
+};
+
+export type ServiceRequestStatus = "pending" | "accepted" | "rejected" | "completed" | "cancelled";
+
+export type BuildRequest = {
+  id: number;
+  requesterUserId: number;
+  builderUserId: number;
+  typeId: number;
+  typeName: string;
+  quantity: number;
+  notes: string | null;
+  status: ServiceRequestStatus;
+  createdAt: string;
+  updatedAt: string;
+};
+
+export type HaulingRequest = {
+  id: number;
+  requesterUserId: number;
+  haulerUserId: number;
+  originLocationId: number;
+  destinationLocationId: number;
+  volumeM3: number;
+  collateral: number;
+  notes: string | null;
+  status: ServiceRequestStatus;
+  createdAt: string;
+  updatedAt: string;
 };
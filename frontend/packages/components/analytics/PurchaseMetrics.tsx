@@ -0,0 +1,297 @@
+import React, { useState, useEffect } from 'react';
+import {
+  Box,
+  Card,
+  CardContent,
+  Typography,
+  Button,
+  ButtonGroup,
+  Table,
+  TableBody,
+  TableCell,
+  TableContainer,
+  TableHead,
+  TableRow,
+  Paper,
+  CircularProgress,
+  Alert,
+} from '@mui/material';
+import AttachMoneyIcon from '@mui/icons-material/AttachMoney';
+import ShoppingCartIcon from '@mui/icons-material/ShoppingCart';
+import TrendingDownIcon from '@mui/icons-material/TrendingDown';
+import PeopleIcon from '@mui/icons-material/People';
+
+interface PurchaseTimeSeriesData {
+  date: string;
+  spent: number;
+  purchases: number;
+  quantityBought: number;
+  jitaValue: number;
+}
+
+interface ItemPurchaseData {
+  typeId: number;
+  typeName: string;
+  quantityBought: number;
+  totalSpent: number;
+  purchaseCount: number;
+  averagePricePaid: number;
+  averageJitaPrice: number;
+  jitaValue: number;
+  savingsVsJita: number;
+  priceVsJita: number;
+  unpricedSpend: number;
+}
+
+interface SellerAnalytics {
+  sellerUserId: number;
+  sellerName: string;
+  totalSpent: number;
+  totalPurchases: number;
+  totalQuantity: number;
+  firstPurchaseDate: string;
+  lastPurchaseDate: string;
+  jitaValue: number;
+  savingsVsJita: number;
+  priceVsJita: number;
+  unpricedSpend: number;
+}
+
+interface PurchaseMetrics {
+  totalSpent: number;
+  totalPurchases: number;
+  totalQuantityBought: number;
+  uniqueItemTypes: number;
+  uniqueSellers: number;
+  jitaValue: number;
+  savingsVsJita: number;
+  priceVsJita: number;
+  unpricedSpend: number;
+  timeSeriesData: PurchaseTimeSeriesData[];
+  topItems: ItemPurchaseData[];
+  topSellers: SellerAnalytics[];
+}
+
+type TimePeriod = '7d' | '30d' | '90d' | '1y' | 'all';
+
+const formatISK = (value: number): string => {
+  const abs = Math.abs(value);
+  const sign = value < 0 ? '-' : '';
+  if (abs >= 1_000_000_000) {
+    return `${sign}${(abs / 1_000_000_000).toFixed(2)}B ISK`;
+  } else if (abs >= 1_000_000) {
+    return `${sign}${(abs / 1_000_000).toFixed(2)}M ISK`;
+  } else if (abs >= 1_000) {
+    return `${sign}${(abs / 1_000).toFixed(2)}K ISK`;
+  }
+  return `${sign}${abs.toFixed(2)} ISK`;
+};
+
+const formatNumber = (value: number): string => {
+  return value.toLocaleString();
+};
+
+// priceVsJita is spend over Jita value; 0 means no Jita price was known
+const formatVsJita = (priceVsJita: number): string => {
+  if (priceVsJita === 0) return '—';
+  return `${(priceVsJita * 100).toFixed(1)}% of Jita`;
+};
+
+export default function PurchaseMetrics() {
+  const [period, setPeriod] = useState<TimePeriod>('30d');
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+  const [metrics, setMetrics] = useState<PurchaseMetrics | null>(null);
+
+  useEffect(() => {
+    fetchMetrics();
+  }, [period]);
+
+  const fetchMetrics = async () => {
+    setLoading(true);
+    setError(null);
+
+    try {
+      const response = await fetch(`/api/analytics/purchases?period=${period}`);
+      if (!response.ok) {
+        throw new Error('Failed to fetch purchase metrics');
+      }
+      const data = await response.json();
+      setMetrics(data);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'An error occurred');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (loading) {
+    return (
+      <Box display="flex" justifyContent="center" alignItems="center" minHeight="400px">
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  if (error) {
+    return (
+      <Alert severity="error" sx={{ mb: 2 }}>
+        {error}
+      </Alert>
+    );
+  }
+
+  if (!metrics) {
+    return (
+      <Alert severity="info" sx={{ mb: 2 }}>
+        No purchase data available
+      </Alert>
+    );
+  }
+
+  const summaryCards = [
+    { label: 'Total Spent', value: formatISK(metrics.totalSpent), icon: <AttachMoneyIcon color="primary" sx={{ mr: 1 }} /> },
+    { label: 'Purchases', value: formatNumber(metrics.totalPurchases), icon: <ShoppingCartIcon color="primary" sx={{ mr: 1 }} /> },
+    { label: 'Sellers Used', value: formatNumber(metrics.uniqueSellers), icon: <PeopleIcon color="primary" sx={{ mr: 1 }} /> },
+    { label: 'Saved vs Jita', value: formatISK(metrics.savingsVsJita), icon: <TrendingDownIcon color="primary" sx={{ mr: 1 }} /> },
+    { label: 'Price vs Jita', value: formatVsJita(metrics.priceVsJita), icon: <TrendingDownIcon color="primary" sx={{ mr: 1 }} /> },
+  ];
+
+  return (
+    <Box>
+      <Box display="flex" justifyContent="space-between" alignItems="center" mb={3}>
+        <Typography variant="h4">Spending Analytics</Typography>
+        <ButtonGroup variant="outlined" size="small">
+          {(['7d', '30d', '90d', '1y', 'all'] as TimePeriod[]).map((p) => (
+            <Button
+              key={p}
+              onClick={() => setPeriod(p)}
+              variant={period === p ? 'contained' : 'outlined'}
+            >
+              {p === 'all' ? 'All Time' : p.toUpperCase()}
+            </Button>
+          ))}
+        </ButtonGroup>
+      </Box>
+
+      {metrics.unpricedSpend > 0 && (
+        <Alert severity="info" sx={{ mb: 3 }}>
+          {formatISK(metrics.unpricedSpend)} of purchases had no Jita price on record and are left out of the Jita comparison.
+        </Alert>
+      )}
+
+      <Box
+        sx={{
+          display: 'grid',
+          gridTemplateColumns: { xs: '1fr', sm: 'repeat(2, 1fr)', md: 'repeat(5, 1fr)' },
+          gap: 3,
+          mb: 4,
+        }}
+      >
+        {summaryCards.map((card) => (
+          <Card key={card.label}>
+            <CardContent>
+              <Box display="flex" alignItems="center" mb={1}>
+                {card.icon}
+                <Typography color="textSecondary" variant="body2">
+                  {card.label}
+                </Typography>
+              </Box>
+              <Typography variant="h5">{card.value}</Typography>
+            </CardContent>
+          </Card>
+        ))}
+      </Box>
+
+      <Card sx={{ mb: 4 }}>
+        <CardContent>
+          <Typography variant="h6" mb={2}>
+            Top Items Purchased
+          </Typography>
+          <TableContainer component={Paper} variant="outlined">
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Item Name</TableCell>
+                  <TableCell align="right">Quantity</TableCell>
+                  <TableCell align="right">Spent</TableCell>
+                  <TableCell align="right">Avg Paid/Unit</TableCell>
+                  <TableCell align="right">Avg Jita/Unit</TableCell>
+                  <TableCell align="right">vs Jita</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {metrics.topItems.length === 0 ? (
+                  <TableRow>
+                    <TableCell colSpan={6} align="center">
+                      No purchases in this period
+                    </TableCell>
+                  </TableRow>
+                ) : (
+                  metrics.topItems.map((item) => (
+                    <TableRow key={item.typeId}>
+                      <TableCell>{item.typeName}</TableCell>
+                      <TableCell align="right">{formatNumber(item.quantityBought)}</TableCell>
+                      <TableCell align="right">{formatISK(item.totalSpent)}</TableCell>
+                      <TableCell align="right">{formatISK(item.averagePricePaid)}</TableCell>
+                      <TableCell align="right">
+                        {item.averageJitaPrice > 0 ? formatISK(item.averageJitaPrice) : '—'}
+                      </TableCell>
+                      <TableCell align="right">{formatVsJita(item.priceVsJita)}</TableCell>
+                    </TableRow>
+                  ))
+                )}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </CardContent>
+      </Card>
+
+      <Card>
+        <CardContent>
+          <Typography variant="h6" mb={2}>
+            Top Sellers
+          </Typography>
+          <TableContainer component={Paper} variant="outlined">
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Seller</TableCell>
+                  <TableCell align="right">Purchases</TableCell>
+                  <TableCell align="right">Spent</TableCell>
+                  <TableCell align="right">Saved vs Jita</TableCell>
+                  <TableCell align="right">vs Jita</TableCell>
+                  <TableCell align="right">Last Purchase</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {metrics.topSellers.length === 0 ? (
+                  <TableRow>
+                    <TableCell colSpan={6} align="center">
+                      No purchases in this period
+                    </TableCell>
+                  </TableRow>
+                ) : (
+                  metrics.topSellers.map((seller) => (
+                    <TableRow key={seller.sellerUserId}>
+                      <TableCell>{seller.sellerName}</TableCell>
+                      <TableCell align="right">{formatNumber(seller.totalPurchases)}</TableCell>
+                      <TableCell align="right">{formatISK(seller.totalSpent)}</TableCell>
+                      <TableCell align="right">
+                        {seller.jitaValue > 0 ? formatISK(seller.savingsVsJita) : '—'}
+                      </TableCell>
+                      <TableCell align="right">{formatVsJita(seller.priceVsJita)}</TableCell>
+                      <TableCell align="right">
+                        {new Date(seller.lastPurchaseDate).toLocaleDateString()}
+                      </TableCell>
+                    </TableRow>
+                  ))
+                )}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </CardContent>
+      </Card>
+    </Box>
+  );
+}
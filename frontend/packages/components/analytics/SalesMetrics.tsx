@@ -29,6 +29,8 @@ interface TimeSeriesData {
   revenue: number;
   transactions: number;
   quantitySold: number;
+  cost: number;
+  grossProfit: number;
 }
 
 interface ItemSalesData {
@@ -38,6 +40,10 @@ interface ItemSalesData {
   revenue: number;
   transactionCount: number;
   averagePricePerUnit: number;
+  totalCost: number;
+  grossProfit: number;
+  profitMargin: number;
+  uncostedRevenue: number;
 }
 
 interface SalesMetrics {
@@ -46,11 +52,30 @@ interface SalesMetrics {
   totalQuantitySold: number;
   uniqueItemTypes: number;
   uniqueBuyers: number;
+  totalCost: number;
+  grossProfit: number;
+  profitMargin: number;
+  uncostedRevenue: number;
+  bucket: string;
   timeSeriesData: TimeSeriesData[];
   topItems: ItemSalesData[];
+  previous?: {
+    totalRevenue: number;
+    totalTransactions: number;
+    grossProfit: number;
+  };
 }
 
 type TimePeriod = '7d' | '30d' | '90d' | '1y' | 'all';
+type Bucket = 'day' | 'week' | 'month';
+type TimeZoneMode = 'eve' | 'local';
+
+const formatChange = (current: number, previous?: number): string | null => {
+  if (previous === undefined) return null;
+  if (previous === 0) return current === 0 ? 'No change vs previous period' : 'New vs previous period';
+  const change = (current - previous) / Math.abs(previous);
+  return `${change >= 0 ? '+' : ''}${(change * 100).toFixed(1)}% vs previous period`;
+};
 
 const formatISK = (value: number): string => {
   if (value >= 1_000_000_000) {
@@ -67,22 +92,32 @@ const formatNumber = (value: number): string => {
   return value.toLocaleString();
 };
 
+const formatPercent = (value: number): string => {
+  return `${(value * 100).toFixed(1)}%`;
+};
+
 export default function SalesMetrics() {
   const [period, setPeriod] = useState<TimePeriod>('30d');
+  const [bucket, setBucket] = useState<Bucket>('day');
+  const [timeZone, setTimeZone] = useState<TimeZoneMode>('eve');
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [metrics, setMetrics] = useState<SalesMetrics | null>(null);
 
   useEffect(() => {
     fetchMetrics();
-  }, [period]);
+  }, [period, bucket, timeZone]);
 
   const fetchMetrics = async () => {
     setLoading(true);
     setError(null);
 
     try {
-      const response = await fetch(`/api/analytics/sales?period=${period}`);
+      const params = new URLSearchParams({ period, bucket, compare: 'previous' });
+      if (timeZone === 'local') {
+        params.append('tz', Intl.DateTimeFormat().resolvedOptions().timeZone);
+      }
+      const response = await fetch(`/api/analytics/sales?${params.toString()}`);
       if (!response.ok) {
         throw new Error('Failed to fetch sales metrics');
       }
@@ -100,10 +135,12 @@ export default function SalesMetrics() {
 
     // Export time series data
     const csvRows = [
-      ['Date', 'Revenue (ISK)', 'Transactions', 'Quantity Sold'],
+      ['Date', 'Revenue (ISK)', 'Cost (ISK)', 'Gross Profit (ISK)', 'Transactions', 'Quantity Sold'],
       ...metrics.timeSeriesData.map(row => [
         row.date,
         row.revenue.toString(),
+        row.cost.toString(),
+        row.grossProfit.toString(),
         row.transactions.toString(),
         row.quantitySold.toString(),
       ]),
@@ -114,7 +151,7 @@ export default function SalesMetrics() {
     const url = window.URL.createObjectURL(blob);
     const a = document.createElement('a');
     a.href = url;
-    a.download = `sales-metrics-${period}-${new Date().toISOString().split('T')[0]}.csv`;
+    a.download = `sales-metrics-${period}-${bucket}-${new Date().toISOString().split('T')[0]}.csv`;
     document.body.appendChild(a);
     a.click();
     document.body.removeChild(a);
@@ -162,6 +199,28 @@ export default function SalesMetrics() {
               </Button>
             ))}
           </ButtonGroup>
+          <ButtonGroup variant="outlined" size="small">
+            {(['day', 'week', 'month'] as Bucket[]).map((b) => (
+              <Button
+                key={b}
+                onClick={() => setBucket(b)}
+                variant={bucket === b ? 'contained' : 'outlined'}
+              >
+                {b === 'day' ? 'Daily' : b === 'week' ? 'Weekly' : 'Monthly'}
+              </Button>
+            ))}
+          </ButtonGroup>
+          <ButtonGroup variant="outlined" size="small">
+            {(['eve', 'local'] as TimeZoneMode[]).map((tz) => (
+              <Button
+                key={tz}
+                onClick={() => setTimeZone(tz)}
+                variant={timeZone === tz ? 'contained' : 'outlined'}
+              >
+                {tz === 'eve' ? 'EVE Time' : 'Local Time'}
+              </Button>
+            ))}
+          </ButtonGroup>
           <Button
             variant="outlined"
             startIcon={<DownloadIcon />}
@@ -169,6 +228,18 @@ export default function SalesMetrics() {
           >
             Export CSV
           </Button>
+          <Button
+            variant="outlined"
+            startIcon={<DownloadIcon />}
+            href={`/api/exports/analytics/sales?${new URLSearchParams({
+              period,
+              bucket,
+              format: 'xlsx',
+              ...(timeZone === 'local' ? { tz: Intl.DateTimeFormat().resolvedOptions().timeZone } : {}),
+            }).toString()}`}
+          >
+            Export XLSX
+          </Button>
         </Box>
       </Box>
 
@@ -196,6 +267,11 @@ export default function SalesMetrics() {
                 </Typography>
               </Box>
               <Typography variant="h5">{formatISK(metrics.totalRevenue)}</Typography>
+              {formatChange(metrics.totalRevenue, metrics.previous?.totalRevenue) && (
+                <Typography variant="caption" color="textSecondary">
+                  {formatChange(metrics.totalRevenue, metrics.previous?.totalRevenue)}
+                </Typography>
+              )}
             </CardContent>
           </Card>
         </Box>
@@ -210,6 +286,11 @@ export default function SalesMetrics() {
                 </Typography>
               </Box>
               <Typography variant="h5">{formatNumber(metrics.totalTransactions)}</Typography>
+              {formatChange(metrics.totalTransactions, metrics.previous?.totalTransactions) && (
+                <Typography variant="caption" color="textSecondary">
+                  {formatChange(metrics.totalTransactions, metrics.previous?.totalTransactions)}
+                </Typography>
+              )}
             </CardContent>
           </Card>
         </Box>
@@ -273,6 +354,39 @@ export default function SalesMetrics() {
             </CardContent>
           </Card>
         </Box>
+
+        <Box>
+          <Card>
+            <CardContent>
+              <Box display="flex" alignItems="center" mb={1}>
+                <TrendingUpIcon color="primary" sx={{ mr: 1 }} />
+                <Typography color="textSecondary" variant="body2">
+                  Gross Profit
+                </Typography>
+              </Box>
+              <Typography variant="h5">{formatISK(metrics.grossProfit)}</Typography>
+              {metrics.uncostedRevenue > 0 && (
+                <Typography color="textSecondary" variant="caption">
+                  Excludes {formatISK(metrics.uncostedRevenue)} of sales with no known cost
+                </Typography>
+              )}
+            </CardContent>
+          </Card>
+        </Box>
+
+        <Box>
+          <Card>
+            <CardContent>
+              <Box display="flex" alignItems="center" mb={1}>
+                <TrendingUpIcon color="primary" sx={{ mr: 1 }} />
+                <Typography color="textSecondary" variant="body2">
+                  Profit Margin
+                </Typography>
+              </Box>
+              <Typography variant="h5">{formatPercent(metrics.profitMargin)}</Typography>
+            </CardContent>
+          </Card>
+        </Box>
       </Box>
 
       {/* Top Selling Items */}
@@ -290,12 +404,14 @@ export default function SalesMetrics() {
                   <TableCell align="right">Revenue</TableCell>
                   <TableCell align="right">Transactions</TableCell>
                   <TableCell align="right">Avg Price/Unit</TableCell>
+                  <TableCell align="right">Gross Profit</TableCell>
+                  <TableCell align="right">Margin</TableCell>
                 </TableRow>
               </TableHead>
               <TableBody>
                 {metrics.topItems.length === 0 ? (
                   <TableRow>
-                    <TableCell colSpan={5} align="center">
+                    <TableCell colSpan={7} align="center">
                       No sales data available
                     </TableCell>
                   </TableRow>
@@ -307,6 +423,10 @@ export default function SalesMetrics() {
                       <TableCell align="right">{formatISK(item.revenue)}</TableCell>
                       <TableCell align="right">{formatNumber(item.transactionCount)}</TableCell>
                       <TableCell align="right">{formatISK(item.averagePricePerUnit)}</TableCell>
+                      <TableCell align="right">{formatISK(item.grossProfit)}</TableCell>
+                      <TableCell align="right">
+                        {item.revenue > item.uncostedRevenue ? formatPercent(item.profitMargin) : '—'}
+                      </TableCell>
                     </TableRow>
                   ))
                 )}
@@ -328,6 +448,7 @@ export default function SalesMetrics() {
                 <TableRow>
                   <TableCell>Date</TableCell>
                   <TableCell align="right">Revenue</TableCell>
+                  <TableCell align="right">Gross Profit</TableCell>
                   <TableCell align="right">Transactions</TableCell>
                   <TableCell align="right">Quantity Sold</TableCell>
                 </TableRow>
@@ -335,7 +456,7 @@ export default function SalesMetrics() {
               <TableBody>
                 {metrics.timeSeriesData.length === 0 ? (
                   <TableRow>
-                    <TableCell colSpan={4} align="center">
+                    <TableCell colSpan={5} align="center">
                       No time series data available
                     </TableCell>
                   </TableRow>
@@ -344,6 +465,7 @@ export default function SalesMetrics() {
                     <TableRow key={row.date}>
                       <TableCell>{row.date}</TableCell>
                       <TableCell align="right">{formatISK(row.revenue)}</TableCell>
+                      <TableCell align="right">{formatISK(row.grossProfit)}</TableCell>
                       <TableCell align="right">{formatNumber(row.transactions)}</TableCell>
                       <TableCell align="right">{formatNumber(row.quantitySold)}</TableCell>
                     </TableRow>
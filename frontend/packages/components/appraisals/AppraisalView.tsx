@@ -0,0 +1,155 @@
+import { useState, useEffect } from 'react';
+import Box from '@mui/material/Box';
+import Typography from '@mui/material/Typography';
+import Table from '@mui/material/Table';
+import TableBody from '@mui/material/TableBody';
+import TableCell from '@mui/material/TableCell';
+import TableContainer from '@mui/material/TableContainer';
+import TableHead from '@mui/material/TableHead';
+import TableRow from '@mui/material/TableRow';
+import Paper from '@mui/material/Paper';
+import Alert from '@mui/material/Alert';
+import CircularProgress from '@mui/material/CircularProgress';
+
+type AppraisalItem = {
+  typeId: number;
+  typeName: string;
+  quantity: number;
+  buyPrice?: number;
+  sellPrice?: number;
+  splitPrice?: number;
+  buyTotal: number;
+  sellTotal: number;
+  splitTotal: number;
+  volume: number;
+  totalVolume: number;
+};
+
+type Appraisal = {
+  code: string;
+  regionId: number;
+  items: AppraisalItem[];
+  unresolved: string[];
+  totalBuy: number;
+  totalSell: number;
+  totalSplit: number;
+  totalVolume: number;
+  createdAt: string;
+};
+
+const formatISK = (value?: number) => {
+  if (value === undefined || value === null) {
+    return '-';
+  }
+  return `${value.toLocaleString(undefined, { maximumFractionDigits: 2 })} ISK`;
+};
+
+export default function AppraisalView({ code }: { code: string }) {
+  const [appraisal, setAppraisal] = useState<Appraisal | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState<string | null>(null);
+
+  useEffect(() => {
+    if (code) {
+      fetchAppraisal();
+    }
+  }, [code]);
+
+  const fetchAppraisal = async () => {
+    setLoading(true);
+    try {
+      const response = await fetch(`/api/appraisals/${encodeURIComponent(code)}`);
+      if (response.status === 404) {
+        setError('Appraisal not found');
+        return;
+      }
+      if (!response.ok) {
+        setError('Failed to load appraisal');
+        return;
+      }
+      setAppraisal(await response.json());
+    } catch (err) {
+      console.error('Failed to fetch appraisal:', err);
+      setError('Failed to load appraisal');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (loading) {
+    return (
+      <Box sx={{ display: 'flex', justifyContent: 'center', alignItems: 'center', minHeight: 400 }}>
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  if (error || !appraisal) {
+    return <Alert severity="error">{error || 'Failed to load appraisal'}</Alert>;
+  }
+
+  const summary = [
+    { label: 'Buy', value: formatISK(appraisal.totalBuy) },
+    { label: 'Split', value: formatISK(appraisal.totalSplit) },
+    { label: 'Sell', value: formatISK(appraisal.totalSell) },
+    { label: 'Volume', value: `${appraisal.totalVolume.toLocaleString(undefined, { maximumFractionDigits: 2 })} m³` },
+  ];
+
+  return (
+    <Box>
+      <Typography variant="h5" gutterBottom>
+        Appraisal {appraisal.code}
+      </Typography>
+      <Typography variant="body2" color="text.secondary" sx={{ mb: 3 }}>
+        Jita prices as of {new Date(appraisal.createdAt).toLocaleString()}
+      </Typography>
+
+      <Box sx={{ display: 'flex', flexWrap: 'wrap', gap: 2, mb: 3 }}>
+        {summary.map((entry) => (
+          <Paper key={entry.label} sx={{ p: 2, flex: '1 1 200px' }}>
+            <Typography variant="body2" color="text.secondary">
+              {entry.label}
+            </Typography>
+            <Typography variant="h6">{entry.value}</Typography>
+          </Paper>
+        ))}
+      </Box>
+
+      {appraisal.unresolved.length > 0 && (
+        <Alert severity="warning" sx={{ mb: 3 }}>
+          Could not appraise {appraisal.unresolved.length} line{appraisal.unresolved.length === 1 ? '' : 's'}:{' '}
+          {appraisal.unresolved.join(', ')}
+        </Alert>
+      )}
+
+      <TableContainer component={Paper}>
+        <Table size="small">
+          <TableHead>
+            <TableRow>
+              <TableCell>Item</TableCell>
+              <TableCell align="right">Quantity</TableCell>
+              <TableCell align="right">Buy</TableCell>
+              <TableCell align="right">Sell</TableCell>
+              <TableCell align="right">Split Total</TableCell>
+              <TableCell align="right">Volume (m³)</TableCell>
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            {appraisal.items.map((item) => (
+              <TableRow key={item.typeId}>
+                <TableCell>{item.typeName}</TableCell>
+                <TableCell align="right">{item.quantity.toLocaleString()}</TableCell>
+                <TableCell align="right">{formatISK(item.buyPrice)}</TableCell>
+                <TableCell align="right">{formatISK(item.sellPrice)}</TableCell>
+                <TableCell align="right">{formatISK(item.splitTotal)}</TableCell>
+                <TableCell align="right">
+                  {item.totalVolume.toLocaleString(undefined, { maximumFractionDigits: 2 })}
+                </TableCell>
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      </TableContainer>
+    </Box>
+  );
+}
@@ -123,6 +123,10 @@ export default function AssetsList(props: AssetsListProps) {
   } | null>(null);
   const [desiredQuantity, setDesiredQuantity] = useState('');
   const [notes, setNotes] = useState('');
+  const [autoBuyOrder, setAutoBuyOrder] = useState(false);
+  const [buyOrderMarkup, setBuyOrderMarkup] = useState('0');
+  const [alertBelowQuantity, setAlertBelowQuantity] = useState('');
+  const [alertDeficitValue, setAlertDeficitValue] = useState('');
   const desiredQuantityInputRef = useRef<HTMLInputElement>(null);
   const [refreshingPrices, setRefreshingPrices] = useState(false);
 
@@ -491,7 +495,39 @@ export default function AssetsList(props: AssetsListProps) {
     setSelectedAsset({ asset, locationId, containerId, divisionNumber });
     setDesiredQuantity(asset.desiredQuantity?.toLocaleString() || '');
     setNotes('');
+    setAutoBuyOrder(false);
+    setBuyOrderMarkup('0');
+    setAlertBelowQuantity('');
+    setAlertDeficitValue('');
     setStockpileModalOpen(true);
+
+    // Saving replaces the whole marker, so load the settings the asset tree doesn't carry
+    if (asset.desiredQuantity) {
+      loadExistingMarker(asset, locationId, containerId, divisionNumber);
+    }
+  };
+
+  const loadExistingMarker = async (asset: Asset, locationId: number, containerId?: number, divisionNumber?: number) => {
+    const response = await fetch('/api/stockpiles');
+    if (!response.ok) return;
+
+    const markers: StockpileMarker[] = await response.json();
+    const existing = markers.find((m) =>
+      (m.scope ?? 'location') === 'location' &&
+      m.typeId === asset.typeId &&
+      m.ownerType === asset.ownerType &&
+      m.ownerId === asset.ownerId &&
+      m.locationId === locationId &&
+      (m.containerId ?? undefined) === containerId &&
+      (m.divisionNumber ?? undefined) === divisionNumber
+    );
+    if (existing) {
+      setNotes(existing.notes || '');
+      setAutoBuyOrder(!!existing.autoBuyOrder);
+      setBuyOrderMarkup(String(existing.buyOrderMarkupPercent ?? 0));
+      setAlertBelowQuantity(existing.alertBelowQuantity ? existing.alertBelowQuantity.toLocaleString() : '');
+      setAlertDeficitValue(existing.alertDeficitValue ? existing.alertDeficitValue.toLocaleString() : '');
+    }
   };
 
   const handleSaveStockpile = async () => {
@@ -508,6 +544,10 @@ export default function AssetsList(props: AssetsListProps) {
       divisionNumber: selectedAsset.divisionNumber,
       desiredQuantity: desiredQty,
       notes: notes || undefined,
+      autoBuyOrder,
+      buyOrderMarkupPercent: parseFloat(buyOrderMarkup) || 0,
+      alertBelowQuantity: parseInt(alertBelowQuantity.replace(/,/g, '')) || undefined,
+      alertDeficitValue: parseFloat(alertDeficitValue.replace(/,/g, '')) || undefined,
     };
 
     await fetch('/api/stockpiles/upsert', {
@@ -1446,6 +1486,52 @@ export default function AssetsList(props: AssetsListProps) {
                 value={notes}
                 onChange={(e) => setNotes(e.target.value)}
               />
+              <FormControlLabel
+                sx={{ mt: 1 }}
+                control={
+                  <Switch
+                    checked={autoBuyOrder}
+                    onChange={(e) => setAutoBuyOrder(e.target.checked)}
+                  />
+                }
+                label="Keep a buy order open for the deficit"
+              />
+              {autoBuyOrder && (
+                <TextField
+                  fullWidth
+                  label="Markup over Jita buy"
+                  type="number"
+                  value={buyOrderMarkup}
+                  onChange={(e) => setBuyOrderMarkup(e.target.value)}
+                  helperText="Updated after every asset refresh and visible to contacts who can see your buy orders"
+                  InputProps={{
+                    endAdornment: <InputAdornment position="end">%</InputAdornment>,
+                  }}
+                  sx={{ mt: 1 }}
+                />
+              )}
+              <Typography variant="subtitle2" sx={{ mt: 2, mb: 1 }}>
+                Alerts (optional)
+              </Typography>
+              <Box sx={{ display: 'flex', gap: 2 }}>
+                <TextField
+                  fullWidth
+                  label="Alert below quantity"
+                  value={alertBelowQuantity}
+                  onChange={(e) => setAlertBelowQuantity(e.target.value)}
+                  placeholder="Off"
+                />
+                <TextField
+                  fullWidth
+                  label="Alert when deficit exceeds"
+                  value={alertDeficitValue}
+                  onChange={(e) => setAlertDeficitValue(e.target.value)}
+                  placeholder="Off"
+                  InputProps={{
+                    endAdornment: <InputAdornment position="end">ISK</InputAdornment>,
+                  }}
+                />
+              </Box>
             </Box>
           </DialogContent>
           <DialogActions>
@@ -27,7 +27,11 @@ type ContactPermission = {
   grantingUserId: number;
   receivingUserId: number;
   serviceType: string;
-  canAccess: boolean;
+  // null until decided here; group and corporation/alliance grants then apply
+  canAccess: boolean | null;
+  // scoped services only; null is unrestricted
+  stationIds: number[] | null;
+  ownerIds: number[] | null;
 };
 
 type PermissionsDialogProps = {
@@ -37,9 +41,12 @@ type PermissionsDialogProps = {
   currentUserId: number;
 };
 
-const SERVICE_TYPES = [
-  { type: 'for_sale_browse', label: 'Browse For-Sale Items' },
-];
+type ServiceType = {
+  type: string;
+  label: string;
+  description: string;
+  scoped: boolean;
+};
 
 export default function PermissionsDialog({
   open,
@@ -48,6 +55,7 @@ export default function PermissionsDialog({
   currentUserId,
 }: PermissionsDialogProps) {
   const [permissions, setPermissions] = useState<ContactPermission[]>([]);
+  const [serviceTypes, setServiceTypes] = useState<ServiceType[]>([]);
   const [loading, setLoading] = useState(true);
   const [saving, setSaving] = useState(false);
   const [error, setError] = useState<string | null>(null);
@@ -62,11 +70,27 @@ export default function PermissionsDialog({
 
   useEffect(() => {
     if (open) {
+      fetchServiceTypes();
       fetchPermissions();
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [open, contact.id]);
 
+  // The backend registry decides which services can be granted
+  const fetchServiceTypes = async () => {
+    try {
+      const response = await fetch('/api/permissions/service-types');
+      if (response.ok) {
+        const data: ServiceType[] = await response.json();
+        setServiceTypes(data || []);
+      } else {
+        setError('Failed to load services');
+      }
+    } catch (err) {
+      setError('Failed to load services');
+    }
+  };
+
   const fetchPermissions = async () => {
     setLoading(true);
     setError(null);
@@ -150,7 +174,7 @@ export default function PermissionsDialog({
               <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
                 What {otherUserName} can access from you:
               </Typography>
-              {SERVICE_TYPES.map((service) => {
+              {serviceTypes.map((service) => {
                 const granted = getPermission(currentUserId, otherUserId, service.type);
                 return (
                   <FormControlLabel
@@ -178,7 +202,7 @@ export default function PermissionsDialog({
               <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
                 What you can access from {otherUserName}:
               </Typography>
-              {SERVICE_TYPES.map((service) => {
+              {serviceTypes.map((service) => {
                 const granted = getPermission(otherUserId, currentUserId, service.type);
                 return (
                   <FormControlLabel
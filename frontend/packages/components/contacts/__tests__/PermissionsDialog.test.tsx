@@ -39,6 +39,21 @@ describe('PermissionsDialog Component', () => {
     },
   ];
 
+  const mockServiceTypes = [
+    { type: 'for_sale_browse', label: 'Browse For-Sale Items', description: 'See and purchase your for-sale listings' },
+    { type: 'hauling_request', label: 'Submit Hauling Requests', description: 'Ask you to move items between locations' },
+  ];
+
+  // Answers the service registry request and hands every other request to permissionsFetch
+  const withServiceTypes = (permissionsFetch: jest.Mock) => {
+    (global.fetch as jest.Mock).mockImplementation((url: string, init?: RequestInit) =>
+      url === '/api/permissions/service-types'
+        ? Promise.resolve({ ok: true, json: async () => mockServiceTypes })
+        : permissionsFetch(url, init)
+    );
+    return permissionsFetch;
+  };
+
   it('should not render when closed', () => {
     const onClose = jest.fn();
     const { container } = render(
@@ -54,10 +69,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should render when open', () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -73,10 +88,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should display other user name in title', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -99,10 +114,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should fetch permissions on open', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -138,10 +153,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should display permissions with correct toggle states', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -164,7 +179,7 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should toggle permission when switch is clicked', async () => {
-    (global.fetch as jest.Mock)
+    withServiceTypes(jest.fn())
       .mockResolvedValueOnce({
         ok: true,
         json: async () => mockPermissions,
@@ -224,7 +239,7 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should handle permission update errors', async () => {
-    (global.fetch as jest.Mock)
+    withServiceTypes(jest.fn())
       .mockResolvedValueOnce({
         ok: true,
         json: async () => mockPermissions,
@@ -264,10 +279,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should call onClose when close button is clicked', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -289,10 +304,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should display correct other user name when current user is recipient', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const reversedContact: Contact = {
       ...mockContact,
@@ -320,10 +335,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should display formatted service type names', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => mockPermissions,
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -342,10 +357,10 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should handle empty permissions response', async () => {
-    (global.fetch as jest.Mock).mockResolvedValue({
+    withServiceTypes(jest.fn().mockResolvedValue({
       ok: true,
       json: async () => [],
-    });
+    }));
 
     const onClose = jest.fn();
     render(
@@ -361,7 +376,7 @@ describe('PermissionsDialog Component', () => {
       expect(screen.getByText(/Manage Permissions/)).toBeInTheDocument();
     });
 
-    // Should still render service type labels (they're hardcoded)
+    // Should still render service type labels (they come from the registry)
     await waitFor(() => {
       const labels = screen.getAllByText('Browse For-Sale Items');
       expect(labels.length).toBeGreaterThan(0);
@@ -369,7 +384,7 @@ describe('PermissionsDialog Component', () => {
   });
 
   it('should refetch permissions after successful update', async () => {
-    (global.fetch as jest.Mock)
+    const permissionsFetch = withServiceTypes(jest.fn())
       .mockResolvedValueOnce({
         ok: true,
         json: async () => mockPermissions,
@@ -397,7 +412,7 @@ describe('PermissionsDialog Component', () => {
     );
 
     await waitFor(() => {
-      expect(global.fetch).toHaveBeenCalledTimes(1);
+      expect(permissionsFetch).toHaveBeenCalledTimes(1);
     });
 
     // Wait for permissions to load and toggle
@@ -414,7 +429,29 @@ describe('PermissionsDialog Component', () => {
 
     await waitFor(() => {
       // Should have called: initial fetch, update, refetch
-      expect(global.fetch).toHaveBeenCalledTimes(3);
+      expect(permissionsFetch).toHaveBeenCalledTimes(3);
+    });
+  });
+
+  it('should list the services from the backend registry', async () => {
+    withServiceTypes(jest.fn().mockResolvedValue({
+      ok: true,
+      json: async () => mockPermissions,
+    }));
+
+    render(
+      <PermissionsDialog
+        open={true}
+        onClose={jest.fn()}
+        contact={mockContact}
+        currentUserId={123}
+      />
+    );
+
+    await waitFor(() => {
+      expect(screen.getAllByText('Submit Hauling Requests')).toHaveLength(2);
     });
+    expect(global.fetch).toHaveBeenCalledWith('/api/permissions/service-types');
+    expect(screen.queryByText('View Assets')).not.toBeInTheDocument();
   });
 });
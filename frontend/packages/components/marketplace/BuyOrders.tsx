@@ -42,6 +42,7 @@ export type BuyOrder = {
   isActive: boolean;
   createdAt: string;
   updatedAt: string;
+  stockpileMarkerId?: number;
 };
 
 type BuyOrderFormData = {
@@ -273,6 +274,9 @@ export default function BuyOrders() {
                             color={order.isActive ? 'success' : 'default'}
                             size="small"
                           />
+                          {order.stockpileMarkerId && (
+                            <Chip label="Stockpile" color="info" variant="outlined" size="small" sx={{ ml: 1 }} />
+                          )}
                         </TableCell>
                         <TableCell>{order.notes || '-'}</TableCell>
                         <TableCell>{formatDate(order.createdAt)}</TableCell>
@@ -34,6 +34,7 @@ type ForSaleListing = {
   containerId?: number;
   divisionNumber?: number;
   quantityAvailable: number;
+  volume: number;
   pricePerUnit: number;
   notes?: string;
 };
@@ -169,6 +170,7 @@ export default function MarketplaceBrowser() {
                 <TableCell>Seller</TableCell>
                 <TableCell>Location</TableCell>
                 <TableCell align="right">Quantity</TableCell>
+                <TableCell align="right">Volume (m³)</TableCell>
                 <TableCell align="right">Price per Unit</TableCell>
                 <TableCell align="right">Total Value</TableCell>
                 <TableCell>Notes</TableCell>
@@ -184,6 +186,9 @@ export default function MarketplaceBrowser() {
                   </TableCell>
                   <TableCell>{listing.locationName}</TableCell>
                   <TableCell align="right">{listing.quantityAvailable.toLocaleString()}</TableCell>
+                  <TableCell align="right">
+                    {listing.volume.toLocaleString(undefined, { maximumFractionDigits: 2 })}
+                  </TableCell>
                   <TableCell align="right">{listing.pricePerUnit.toLocaleString()} ISK</TableCell>
                   <TableCell align="right">
                     {(listing.quantityAvailable * listing.pricePerUnit).toLocaleString()} ISK
@@ -42,6 +42,7 @@ export type ForSaleItem = {
   divisionNumber?: number;
   quantityAvailable: number;
   pricePerUnit: number;
+  buildCostPerUnit?: number;
   notes?: string;
   isActive: boolean;
   createdAt: string;
@@ -57,6 +58,7 @@ type ListingFormData = {
   divisionNumber?: number;
   quantityAvailable: number;
   pricePerUnit: number;
+  buildCostPerUnit?: number;
   notes?: string;
 };
 
@@ -101,6 +103,7 @@ export default function MyListings() {
     setFormData({
       quantityAvailable: listing.quantityAvailable,
       pricePerUnit: listing.pricePerUnit,
+      buildCostPerUnit: listing.buildCostPerUnit,
       notes: listing.notes,
     });
     setEditDialogOpen(true);
@@ -341,6 +344,19 @@ export default function MyListings() {
                 InputProps={{ inputProps: { min: 0 } }}
               />
 
+              <TextField
+                label="Build Cost Per Unit (ISK, optional)"
+                type="number"
+                fullWidth
+                value={formData.buildCostPerUnit ?? ''}
+                onChange={(e) => setFormData({
+                  ...formData,
+                  buildCostPerUnit: e.target.value === '' ? undefined : parseInt(e.target.value),
+                })}
+                helperText="Used as the cost basis for profit in sales analytics"
+                InputProps={{ inputProps: { min: 0 } }}
+              />
+
               <TextField
                 label="Notes (optional)"
                 multiline
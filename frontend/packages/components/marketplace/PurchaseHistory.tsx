@@ -20,6 +20,7 @@ import Alert from '@mui/material/Alert';
 import CheckCircleIcon from '@mui/icons-material/CheckCircle';
 import CancelIcon from '@mui/icons-material/Cancel';
 import AssignmentIcon from '@mui/icons-material/Assignment';
+import DownloadIcon from '@mui/icons-material/Download';
 
 type PurchaseTransaction = {
   id: number;
@@ -292,11 +293,22 @@ export default function PurchaseHistory() {
 
   return (
     <Box>
-      <Box sx={{ borderBottom: 1, borderColor: 'divider', mb: 3 }}>
+      <Box sx={{ borderBottom: 1, borderColor: 'divider', mb: 3, display: 'flex', justifyContent: 'space-between', alignItems: 'center' }}>
         <Tabs value={activeTab} onChange={(_, newValue) => setActiveTab(newValue)}>
           <Tab label={`My Purchases (${buyerHistory.length})`} />
           <Tab label={`My Sales (${sellerHistory.length})`} />
         </Tabs>
+        <ButtonGroup size="small" variant="outlined">
+          {(['csv', 'xlsx'] as const).map((format) => (
+            <Button
+              key={format}
+              startIcon={<DownloadIcon />}
+              href={`/api/exports/purchases/${activeTab === 0 ? 'buyer' : 'seller'}?format=${format}`}
+            >
+              {format.toUpperCase()}
+            </Button>
+          ))}
+        </ButtonGroup>
       </Box>
 
       {activeTab === 0 && renderTransactionsTable(buyerHistory, true)}
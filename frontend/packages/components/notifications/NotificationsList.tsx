@@ -0,0 +1,218 @@
+import { useState, useEffect } from 'react';
+import { useSession } from 'next-auth/react';
+import Box from '@mui/material/Box';
+import Typography from '@mui/material/Typography';
+import Card from '@mui/material/Card';
+import CardContent from '@mui/material/CardContent';
+import Chip from '@mui/material/Chip';
+import List from '@mui/material/List';
+import ListItem from '@mui/material/ListItem';
+import ListItemText from '@mui/material/ListItemText';
+import Button from '@mui/material/Button';
+import TextField from '@mui/material/TextField';
+import MenuItem from '@mui/material/MenuItem';
+import CircularProgress from '@mui/material/CircularProgress';
+import NotificationsIcon from '@mui/icons-material/Notifications';
+import { Notification, NotificationSettings } from '@industry-tool/client/data/models';
+
+type CharacterOption = {
+  id: number;
+  name: string;
+};
+
+export default function NotificationsList() {
+  const { data: session } = useSession();
+  const [notifications, setNotifications] = useState<Notification[]>([]);
+  const [characters, setCharacters] = useState<CharacterOption[]>([]);
+  const [webhookUrl, setWebhookUrl] = useState('');
+  const [mailCharacterId, setMailCharacterId] = useState('');
+  const [loading, setLoading] = useState(true);
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState('');
+  const [saved, setSaved] = useState(false);
+
+  useEffect(() => {
+    if (session) {
+      load();
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [session]);
+
+  const load = async () => {
+    setLoading(true);
+    try {
+      const [notificationsRes, settingsRes, charactersRes] = await Promise.all([
+        fetch('/api/notifications'),
+        fetch('/api/notifications/settings'),
+        fetch('/api/characters'),
+      ]);
+      if (notificationsRes.ok) {
+        setNotifications(await notificationsRes.json());
+      }
+      if (settingsRes.ok) {
+        const settings: NotificationSettings = await settingsRes.json();
+        setWebhookUrl(settings.webhookUrl || '');
+        setMailCharacterId(settings.mailCharacterId ? String(settings.mailCharacterId) : '');
+      }
+      if (charactersRes.ok) {
+        setCharacters(await charactersRes.json());
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleSaveSettings = async () => {
+    setSaving(true);
+    setError('');
+    setSaved(false);
+    try {
+      const response = await fetch('/api/notifications/settings', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          webhookUrl: webhookUrl.trim() || null,
+          mailCharacterId: mailCharacterId ? parseInt(mailCharacterId) : null,
+        }),
+      });
+      if (!response.ok) {
+        const body = await response.json().catch(() => ({}));
+        throw new Error(body.error || `Failed to save settings: ${response.status}`);
+      }
+      setSaved(true);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Unknown error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleMarkRead = async (id: number) => {
+    const response = await fetch(`/api/notifications/${id}/read`, { method: 'POST' });
+    if (response.ok) {
+      setNotifications(prev => prev.map(n => n.id === id ? { ...n, readAt: new Date().toISOString() } : n));
+    }
+  };
+
+  const handleMarkAllRead = async () => {
+    const response = await fetch('/api/notifications/read-all', { method: 'POST' });
+    if (response.ok) {
+      const now = new Date().toISOString();
+      setNotifications(prev => prev.map(n => n.readAt ? n : { ...n, readAt: now }));
+    }
+  };
+
+  if (loading) {
+    return (
+      <Box sx={{ display: 'flex', justifyContent: 'center', mt: 4 }}>
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  const unreadCount = notifications.filter(n => !n.readAt).length;
+
+  return (
+    <>
+      <Box sx={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', mb: 2 }}>
+        <Typography variant="h4" sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+          <NotificationsIcon fontSize="large" />
+          Notifications
+        </Typography>
+        <Box sx={{ display: 'flex', gap: 2 }}>
+          <Button variant="outlined" href="/stockpiles">Back to Stockpiles</Button>
+          <Button variant="outlined" href="/webhooks">Webhooks</Button>
+          <Button variant="contained" onClick={handleMarkAllRead} disabled={unreadCount === 0}>
+            Mark All Read
+          </Button>
+        </Box>
+      </Box>
+
+      <Card sx={{ mb: 3 }}>
+        <CardContent>
+          <Typography variant="h6" gutterBottom>Delivery</Typography>
+          <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+            Stockpile alerts always appear here. They can also be posted to a Discord or Slack webhook,
+            and mailed in game to one of your characters.
+          </Typography>
+          <Box sx={{ display: 'flex', gap: 2, alignItems: 'flex-start' }}>
+            <TextField
+              fullWidth
+              label="Webhook URL"
+              value={webhookUrl}
+              onChange={(e) => setWebhookUrl(e.target.value)}
+              placeholder="https://discord.com/api/webhooks/..."
+            />
+            <TextField
+              select
+              label="EVE mail to"
+              value={mailCharacterId}
+              onChange={(e) => setMailCharacterId(e.target.value)}
+              sx={{ minWidth: 220 }}
+            >
+              <MenuItem value="">Off</MenuItem>
+              {characters.map(c => (
+                <MenuItem key={c.id} value={String(c.id)}>{c.name}</MenuItem>
+              ))}
+            </TextField>
+            <Button variant="contained" onClick={handleSaveSettings} disabled={saving} sx={{ mt: 1 }}>
+              Save
+            </Button>
+          </Box>
+          {mailCharacterId && (
+            <Box sx={{ display: 'flex', gap: 2, alignItems: 'center', mt: 2 }}>
+              <Typography variant="body2" color="text.secondary">
+                Mailing needs the character&apos;s permission to send EVE mail. Log in as that character once to grant it.
+              </Typography>
+              <Button variant="outlined" size="small" href="/api/characters/add?mail=true">
+                Authorize Mail
+              </Button>
+            </Box>
+          )}
+          {error && <Typography color="error" sx={{ mt: 1 }}>{error}</Typography>}
+          {saved && <Typography color="success.main" sx={{ mt: 1 }}>Settings saved</Typography>}
+        </CardContent>
+      </Card>
+
+      {notifications.length === 0 ? (
+        <Card>
+          <CardContent>
+            <Typography variant="h6" align="center" color="text.secondary">
+              No notifications yet. Set alert thresholds on your stockpile markers to get one.
+            </Typography>
+          </CardContent>
+        </Card>
+      ) : (
+        <Card>
+          <List>
+            {notifications.map(n => (
+              <ListItem
+                key={n.id}
+                divider
+                secondaryAction={!n.readAt && (
+                  <Button size="small" onClick={() => handleMarkRead(n.id)}>Mark read</Button>
+                )}
+              >
+                <ListItemText
+                  primary={
+                    <Box sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+                      <Typography fontWeight={n.readAt ? 'normal' : 'bold'}>{n.title}</Typography>
+                      {!n.readAt && <Chip label="New" size="small" color="primary" />}
+                    </Box>
+                  }
+                  secondary={
+                    <>
+                      {n.message}
+                      <br />
+                      {new Date(n.createdAt).toLocaleString()}
+                    </>
+                  }
+                />
+              </ListItem>
+            ))}
+          </List>
+        </Card>
+      )}
+    </>
+  );
+}
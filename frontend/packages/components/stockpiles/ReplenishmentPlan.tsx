@@ -0,0 +1,234 @@
+import { useState, useEffect } from 'react';
+import { useSession } from 'next-auth/react';
+import Box from '@mui/material/Box';
+import Typography from '@mui/material/Typography';
+import Card from '@mui/material/Card';
+import CardContent from '@mui/material/CardContent';
+import Chip from '@mui/material/Chip';
+import Table from '@mui/material/Table';
+import TableBody from '@mui/material/TableBody';
+import TableCell from '@mui/material/TableCell';
+import TableContainer from '@mui/material/TableContainer';
+import TableHead from '@mui/material/TableHead';
+import TableRow from '@mui/material/TableRow';
+import Paper from '@mui/material/Paper';
+import Button from '@mui/material/Button';
+import CircularProgress from '@mui/material/CircularProgress';
+import LocalShippingIcon from '@mui/icons-material/LocalShipping';
+
+export type ReplenishmentStep = {
+  rank: number;
+  action: 'move' | 'buy_contact' | 'buy_market';
+  typeId: number;
+  typeName: string;
+  quantity: number;
+  volume: number;
+  unitPrice: number;
+  cost: number;
+  marketCost: number;
+  fromLocationId: number;
+  fromName: string;
+  fromOwnerName: string;
+  fromContainer?: string;
+  listingId?: number;
+  toLocationId: number;
+  toName: string;
+  toScope: string;
+  toOwnerName: string;
+  toContainer?: string;
+};
+
+export type ReplenishmentRoute = {
+  action: ReplenishmentStep['action'];
+  fromLocationId: number;
+  fromName: string;
+  toLocationId: number;
+  toName: string;
+  steps: number;
+  volume: number;
+  cost: number;
+};
+
+export type ReplenishmentPlanResponse = {
+  steps: ReplenishmentStep[];
+  routes: ReplenishmentRoute[];
+  totalVolume: number;
+  totalCost: number;
+};
+
+const actionLabels: Record<ReplenishmentStep['action'], { label: string; color: 'success' | 'info' | 'warning' }> = {
+  move: { label: 'Haul', color: 'success' },
+  buy_contact: { label: 'Buy from contact', color: 'info' },
+  buy_market: { label: 'Buy in Jita', color: 'warning' },
+};
+
+const formatISK = (value: number) => value.toLocaleString(undefined, { maximumFractionDigits: 0 });
+const formatVolume = (value: number) => `${value.toLocaleString(undefined, { maximumFractionDigits: 2 })} m³`;
+
+export default function ReplenishmentPlan() {
+  const { data: session } = useSession();
+  const [plan, setPlan] = useState<ReplenishmentPlanResponse | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (session) {
+      fetchPlan();
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [session]);
+
+  const fetchPlan = async () => {
+    setLoading(true);
+    setError('');
+    try {
+      const response = await fetch('/api/stockpiles/replenishment-plan');
+      if (!response.ok) {
+        throw new Error(`Failed to load plan: ${response.status}`);
+      }
+      setPlan(await response.json());
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Unknown error');
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  if (loading) {
+    return (
+      <Box sx={{ display: 'flex', justifyContent: 'center', mt: 4 }}>
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  return (
+    <>
+      <Box sx={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', mb: 2 }}>
+        <Typography variant="h4" sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+          <LocalShippingIcon fontSize="large" />
+          Replenishment Plan
+        </Typography>
+        <Box sx={{ display: 'flex', gap: 2 }}>
+          <Button variant="outlined" href="/stockpiles">Back to Stockpiles</Button>
+          <Button variant="contained" onClick={fetchPlan}>Refresh</Button>
+        </Box>
+      </Box>
+
+      {error && (
+        <Typography color="error" sx={{ mb: 2 }}>{error}</Typography>
+      )}
+
+      {plan && plan.steps.length === 0 ? (
+        <Card>
+          <CardContent>
+            <Typography variant="h6" align="center" color="text.secondary">
+              No stockpiles need replenishment!
+            </Typography>
+          </CardContent>
+        </Card>
+      ) : plan && (
+        <>
+          <Box sx={{ display: 'flex', gap: 2, mb: 3 }}>
+            <Card sx={{ flex: 1 }}>
+              <CardContent>
+                <Typography variant="h6" color="text.secondary" gutterBottom>Steps</Typography>
+                <Typography variant="h3">{plan.steps.length}</Typography>
+              </CardContent>
+            </Card>
+            <Card sx={{ flex: 1 }}>
+              <CardContent>
+                <Typography variant="h6" color="text.secondary" gutterBottom>Total Volume</Typography>
+                <Typography variant="h3">{formatVolume(plan.totalVolume)}</Typography>
+              </CardContent>
+            </Card>
+            <Card sx={{ flex: 1 }}>
+              <CardContent>
+                <Typography variant="h6" color="text.secondary" gutterBottom>Total Cost (ISK)</Typography>
+                <Typography variant="h3" color="error.main">{formatISK(plan.totalCost)}</Typography>
+              </CardContent>
+            </Card>
+          </Box>
+
+          <Typography variant="h6" gutterBottom>Routes</Typography>
+          <TableContainer component={Paper} variant="outlined" sx={{ mb: 3 }}>
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>Action</TableCell>
+                  <TableCell>From</TableCell>
+                  <TableCell>To</TableCell>
+                  <TableCell align="right">Steps</TableCell>
+                  <TableCell align="right">Volume</TableCell>
+                  <TableCell align="right">Cost (ISK)</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {plan.routes.map((route, idx) => (
+                  <TableRow key={idx} hover>
+                    <TableCell>
+                      <Chip size="small" label={actionLabels[route.action].label} color={actionLabels[route.action].color} />
+                    </TableCell>
+                    <TableCell>{route.fromName}</TableCell>
+                    <TableCell>{route.toName}</TableCell>
+                    <TableCell align="right">{route.steps}</TableCell>
+                    <TableCell align="right">{formatVolume(route.volume)}</TableCell>
+                    <TableCell align="right">{formatISK(route.cost)}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+
+          <Typography variant="h6" gutterBottom>Steps</Typography>
+          <TableContainer component={Paper} variant="outlined">
+            <Table size="small">
+              <TableHead>
+                <TableRow>
+                  <TableCell>#</TableCell>
+                  <TableCell>Action</TableCell>
+                  <TableCell>Item</TableCell>
+                  <TableCell align="right">Quantity</TableCell>
+                  <TableCell>From</TableCell>
+                  <TableCell>To</TableCell>
+                  <TableCell align="right">Volume</TableCell>
+                  <TableCell align="right">Cost (ISK)</TableCell>
+                  <TableCell align="right">Jita Cost (ISK)</TableCell>
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {plan.steps.map((step) => (
+                  <TableRow key={step.rank} hover>
+                    <TableCell>{step.rank}</TableCell>
+                    <TableCell>
+                      <Chip size="small" label={actionLabels[step.action].label} color={actionLabels[step.action].color} />
+                    </TableCell>
+                    <TableCell sx={{ fontWeight: 600 }}>{step.typeName}</TableCell>
+                    <TableCell align="right">{step.quantity.toLocaleString()}</TableCell>
+                    <TableCell>
+                      {step.fromName}
+                      {(step.fromOwnerName || step.fromContainer) && (
+                        <Typography variant="caption" display="block" color="text.secondary">
+                          {[step.fromOwnerName, step.fromContainer].filter(Boolean).join(' · ')}
+                        </Typography>
+                      )}
+                    </TableCell>
+                    <TableCell>
+                      {step.toName}
+                      <Typography variant="caption" display="block" color="text.secondary">
+                        {[step.toOwnerName, step.toContainer].filter(Boolean).join(' · ')}
+                      </Typography>
+                    </TableCell>
+                    <TableCell align="right">{formatVolume(step.volume)}</TableCell>
+                    <TableCell align="right">{formatISK(step.cost)}</TableCell>
+                    <TableCell align="right">{formatISK(step.marketCost)}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </>
+      )}
+    </>
+  );
+}
@@ -0,0 +1,467 @@
+import { useState, useEffect } from 'react';
+import { useSession } from 'next-auth/react';
+import Box from '@mui/material/Box';
+import Typography from '@mui/material/Typography';
+import Card from '@mui/material/Card';
+import CardContent from '@mui/material/CardContent';
+import CardActions from '@mui/material/CardActions';
+import Button from '@mui/material/Button';
+import TextField from '@mui/material/TextField';
+import MenuItem from '@mui/material/MenuItem';
+import Chip from '@mui/material/Chip';
+import Table from '@mui/material/Table';
+import TableBody from '@mui/material/TableBody';
+import TableCell from '@mui/material/TableCell';
+import TableHead from '@mui/material/TableHead';
+import TableRow from '@mui/material/TableRow';
+import Dialog from '@mui/material/Dialog';
+import DialogTitle from '@mui/material/DialogTitle';
+import DialogContent from '@mui/material/DialogContent';
+import DialogActions from '@mui/material/DialogActions';
+import Snackbar from '@mui/material/Snackbar';
+import Alert from '@mui/material/Alert';
+import CircularProgress from '@mui/material/CircularProgress';
+import AddIcon from '@mui/icons-material/Add';
+import DeleteIcon from '@mui/icons-material/Delete';
+
+export type StockpileTemplateItem = {
+  typeId: number;
+  typeName: string;
+  quantity: number;
+};
+
+export type StockpileTemplateApplication = {
+  id: number;
+  templateId: number;
+  ownerType: string;
+  ownerId: number;
+  locationId: number;
+  containerId?: number;
+  divisionNumber?: number;
+  multiplier: number;
+};
+
+export type StockpileTemplate = {
+  id: number;
+  name: string;
+  description?: string;
+  items: StockpileTemplateItem[];
+  applications: StockpileTemplateApplication[];
+};
+
+type TemplateForm = {
+  id?: number;
+  name: string;
+  description: string;
+  items: StockpileTemplateItem[];
+  importText: string;
+};
+
+type ApplyForm = {
+  ownerType: string;
+  ownerId: string;
+  locationId: string;
+  containerId: string;
+  divisionNumber: string;
+  multiplier: string;
+};
+
+const emptyTemplateForm: TemplateForm = { name: '', description: '', items: [], importText: '' };
+const emptyApplyForm: ApplyForm = {
+  ownerType: 'character',
+  ownerId: '',
+  locationId: '',
+  containerId: '',
+  divisionNumber: '',
+  multiplier: '1',
+};
+
+const optionalNumber = (value: string) => (value.trim() === '' ? undefined : Number(value));
+
+export default function StockpileTemplates() {
+  const { data: session } = useSession();
+  const [templates, setTemplates] = useState<StockpileTemplate[]>([]);
+  const [loading, setLoading] = useState(true);
+  const [templateForm, setTemplateForm] = useState<TemplateForm | null>(null);
+  const [applyTemplate, setApplyTemplate] = useState<StockpileTemplate | null>(null);
+  const [applyForm, setApplyForm] = useState<ApplyForm>(emptyApplyForm);
+  const [saving, setSaving] = useState(false);
+  const [snackbar, setSnackbar] = useState<{ open: boolean; message: string; severity?: 'success' | 'error' | 'warning' }>({
+    open: false,
+    message: '',
+    severity: 'success',
+  });
+
+  useEffect(() => {
+    if (session) {
+      fetchTemplates();
+    }
+  }, [session]);
+
+  const fetchTemplates = async () => {
+    setLoading(true);
+    try {
+      const response = await fetch('/api/stockpile-templates');
+      if (response.ok) {
+        const data = await response.json();
+        setTemplates(data || []);
+      }
+    } catch (error) {
+      console.error('Failed to fetch stockpile templates:', error);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleSaveTemplate = async () => {
+    if (!templateForm) return;
+
+    setSaving(true);
+    try {
+      const url = templateForm.id ? `/api/stockpile-templates/${templateForm.id}` : '/api/stockpile-templates';
+      const response = await fetch(url, {
+        method: templateForm.id ? 'PUT' : 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          name: templateForm.name,
+          description: templateForm.description || null,
+          items: templateForm.items.map((item) => ({ typeId: item.typeId, quantity: item.quantity })),
+          import: templateForm.importText,
+        }),
+      });
+
+      if (!response.ok) {
+        const error = await response.json();
+        setSnackbar({ open: true, message: error.error || 'Failed to save template', severity: 'error' });
+        return;
+      }
+
+      const data = await response.json();
+      setTemplateForm(null);
+      await fetchTemplates();
+      if (data.unresolved && data.unresolved.length > 0) {
+        setSnackbar({ open: true, message: `Saved. Could not match: ${data.unresolved.join(', ')}`, severity: 'warning' });
+      } else {
+        setSnackbar({ open: true, message: 'Template saved', severity: 'success' });
+      }
+    } catch (error) {
+      console.error('Failed to save template:', error);
+      setSnackbar({ open: true, message: 'Failed to save template', severity: 'error' });
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleDeleteTemplate = async (template: StockpileTemplate) => {
+    if (!confirm(`Delete "${template.name}"? Markers it created at ${template.applications.length} location(s) will be removed.`)) {
+      return;
+    }
+
+    const response = await fetch(`/api/stockpile-templates/${template.id}`, { method: 'DELETE' });
+    if (response.ok) {
+      await fetchTemplates();
+      setSnackbar({ open: true, message: 'Template deleted', severity: 'success' });
+    } else {
+      setSnackbar({ open: true, message: 'Failed to delete template', severity: 'error' });
+    }
+  };
+
+  const handleApply = async () => {
+    if (!applyTemplate) return;
+
+    setSaving(true);
+    try {
+      const response = await fetch(`/api/stockpile-templates/${applyTemplate.id}/applications`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({
+          ownerType: applyForm.ownerType,
+          ownerId: Number(applyForm.ownerId),
+          locationId: Number(applyForm.locationId),
+          containerId: optionalNumber(applyForm.containerId),
+          divisionNumber: optionalNumber(applyForm.divisionNumber),
+          multiplier: Number(applyForm.multiplier) || 1,
+        }),
+      });
+
+      if (!response.ok) {
+        const error = await response.json();
+        setSnackbar({ open: true, message: error.error || 'Failed to apply template', severity: 'error' });
+        return;
+      }
+
+      setApplyTemplate(null);
+      await fetchTemplates();
+      setSnackbar({ open: true, message: 'Template applied', severity: 'success' });
+    } catch (error) {
+      console.error('Failed to apply template:', error);
+      setSnackbar({ open: true, message: 'Failed to apply template', severity: 'error' });
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleUnapply = async (application: StockpileTemplateApplication) => {
+    if (!confirm('Remove this template from the location? Its markers there will be deleted.')) {
+      return;
+    }
+
+    const response = await fetch(
+      `/api/stockpile-templates/${application.templateId}/applications/${application.id}`,
+      { method: 'DELETE' },
+    );
+    if (response.ok) {
+      await fetchTemplates();
+      setSnackbar({ open: true, message: 'Template removed from location', severity: 'success' });
+    } else {
+      setSnackbar({ open: true, message: 'Failed to remove template from location', severity: 'error' });
+    }
+  };
+
+  const describeApplication = (application: StockpileTemplateApplication) => {
+    const parts = [`${application.ownerType} ${application.ownerId}`, `location ${application.locationId}`];
+    if (application.containerId) parts.push(`container ${application.containerId}`);
+    if (application.divisionNumber) parts.push(`division ${application.divisionNumber}`);
+    return `${parts.join(' · ')} ×${application.multiplier}`;
+  };
+
+  if (loading) {
+    return (
+      <Box sx={{ display: 'flex', justifyContent: 'center', alignItems: 'center', minHeight: 400 }}>
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  return (
+    <Box>
+      <Box sx={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', mb: 3 }}>
+        <Typography variant="h4">Stockpile Templates</Typography>
+        <Button variant="contained" startIcon={<AddIcon />} onClick={() => setTemplateForm({ ...emptyTemplateForm })}>
+          New Template
+        </Button>
+      </Box>
+
+      {templates.length === 0 && (
+        <Card>
+          <CardContent>
+            <Typography variant="h6" align="center" color="text.secondary">
+              No templates yet
+            </Typography>
+            <Typography variant="body2" align="center" color="text.secondary">
+              Paste an EFT fit or multibuy list to create one, then apply it to any hangar, container or division.
+            </Typography>
+          </CardContent>
+        </Card>
+      )}
+
+      <Box sx={{ display: 'flex', flexDirection: 'column', gap: 2 }}>
+        {templates.map((template) => (
+          <Card key={template.id}>
+            <CardContent>
+              <Typography variant="h6">{template.name}</Typography>
+              {template.description && (
+                <Typography variant="body2" color="text.secondary" gutterBottom>
+                  {template.description}
+                </Typography>
+              )}
+
+              <Table size="small" sx={{ mb: 2 }}>
+                <TableHead>
+                  <TableRow>
+                    <TableCell>Item</TableCell>
+                    <TableCell align="right">Quantity</TableCell>
+                  </TableRow>
+                </TableHead>
+                <TableBody>
+                  {template.items.map((item) => (
+                    <TableRow key={item.typeId}>
+                      <TableCell>{item.typeName}</TableCell>
+                      <TableCell align="right">{item.quantity.toLocaleString()}</TableCell>
+                    </TableRow>
+                  ))}
+                </TableBody>
+              </Table>
+
+              <Typography variant="subtitle2" gutterBottom>
+                Applied to
+              </Typography>
+              <Box sx={{ display: 'flex', flexWrap: 'wrap', gap: 1 }}>
+                {template.applications.length === 0 && (
+                  <Typography variant="body2" color="text.secondary">
+                    Not applied anywhere yet
+                  </Typography>
+                )}
+                {template.applications.map((application) => (
+                  <Chip
+                    key={application.id}
+                    label={describeApplication(application)}
+                    onDelete={() => handleUnapply(application)}
+                  />
+                ))}
+              </Box>
+            </CardContent>
+            <CardActions>
+              <Button
+                onClick={() => {
+                  setApplyTemplate(template);
+                  setApplyForm({ ...emptyApplyForm });
+                }}
+              >
+                Apply
+              </Button>
+              <Button
+                onClick={() =>
+                  setTemplateForm({
+                    id: template.id,
+                    name: template.name,
+                    description: template.description || '',
+                    items: template.items,
+                    importText: '',
+                  })
+                }
+              >
+                Edit
+              </Button>
+              <Button color="error" startIcon={<DeleteIcon />} onClick={() => handleDeleteTemplate(template)}>
+                Delete
+              </Button>
+            </CardActions>
+          </Card>
+        ))}
+      </Box>
+
+      {/* Create / Edit Dialog */}
+      <Dialog open={templateForm !== null} onClose={() => setTemplateForm(null)} maxWidth="sm" fullWidth>
+        <DialogTitle>{templateForm?.id ? 'Edit Template' : 'New Template'}</DialogTitle>
+        <DialogContent>
+          {templateForm && (
+            <Box sx={{ display: 'flex', flexDirection: 'column', gap: 2, pt: 1 }}>
+              <TextField
+                label="Name"
+                value={templateForm.name}
+                onChange={(e) => setTemplateForm({ ...templateForm, name: e.target.value })}
+                fullWidth
+              />
+              <TextField
+                label="Description"
+                value={templateForm.description}
+                onChange={(e) => setTemplateForm({ ...templateForm, description: e.target.value })}
+                fullWidth
+              />
+              {templateForm.items.map((item, index) => (
+                <Box key={item.typeId} sx={{ display: 'flex', gap: 1, alignItems: 'center' }}>
+                  <Typography sx={{ flex: 1 }}>{item.typeName}</Typography>
+                  <TextField
+                    size="small"
+                    type="number"
+                    label="Quantity"
+                    value={item.quantity}
+                    onChange={(e) => {
+                      const items = [...templateForm.items];
+                      items[index] = { ...item, quantity: Number(e.target.value) };
+                      setTemplateForm({ ...templateForm, items });
+                    }}
+                  />
+                  <Button
+                    color="error"
+                    onClick={() =>
+                      setTemplateForm({ ...templateForm, items: templateForm.items.filter((_, i) => i !== index) })
+                    }
+                  >
+                    Remove
+                  </Button>
+                </Box>
+              ))}
+              <TextField
+                label={templateForm.items.length > 0 ? 'Add items (EFT fit or multibuy)' : 'EFT fit or multibuy list'}
+                value={templateForm.importText}
+                onChange={(e) => setTemplateForm({ ...templateForm, importText: e.target.value })}
+                multiline
+                minRows={6}
+                fullWidth
+              />
+            </Box>
+          )}
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setTemplateForm(null)}>Cancel</Button>
+          <Button variant="contained" onClick={handleSaveTemplate} disabled={saving || !templateForm?.name.trim()}>
+            Save
+          </Button>
+        </DialogActions>
+      </Dialog>
+
+      {/* Apply Dialog */}
+      <Dialog open={applyTemplate !== null} onClose={() => setApplyTemplate(null)} maxWidth="sm" fullWidth>
+        <DialogTitle>Apply {applyTemplate?.name}</DialogTitle>
+        <DialogContent>
+          <Box sx={{ display: 'flex', flexDirection: 'column', gap: 2, pt: 1 }}>
+            <TextField
+              select
+              label="Owner Type"
+              value={applyForm.ownerType}
+              onChange={(e) => setApplyForm({ ...applyForm, ownerType: e.target.value })}
+            >
+              <MenuItem value="character">Character</MenuItem>
+              <MenuItem value="corporation">Corporation</MenuItem>
+            </TextField>
+            <TextField
+              label="Owner ID"
+              type="number"
+              value={applyForm.ownerId}
+              onChange={(e) => setApplyForm({ ...applyForm, ownerId: e.target.value })}
+            />
+            <TextField
+              label="Location ID"
+              type="number"
+              value={applyForm.locationId}
+              onChange={(e) => setApplyForm({ ...applyForm, locationId: e.target.value })}
+            />
+            <TextField
+              label="Container ID (optional)"
+              type="number"
+              value={applyForm.containerId}
+              onChange={(e) => setApplyForm({ ...applyForm, containerId: e.target.value })}
+            />
+            {applyForm.ownerType === 'corporation' && (
+              <TextField
+                label="Division (optional)"
+                type="number"
+                value={applyForm.divisionNumber}
+                onChange={(e) => setApplyForm({ ...applyForm, divisionNumber: e.target.value })}
+              />
+            )}
+            <TextField
+              label="Multiplier"
+              type="number"
+              helperText="Each item quantity is multiplied by this, e.g. 20 for twenty fits"
+              value={applyForm.multiplier}
+              onChange={(e) => setApplyForm({ ...applyForm, multiplier: e.target.value })}
+            />
+          </Box>
+        </DialogContent>
+        <DialogActions>
+          <Button onClick={() => setApplyTemplate(null)}>Cancel</Button>
+          <Button
+            variant="contained"
+            onClick={handleApply}
+            disabled={saving || !applyForm.ownerId || !applyForm.locationId}
+          >
+            Apply
+          </Button>
+        </DialogActions>
+      </Dialog>
+
+      <Snackbar
+        open={snackbar.open}
+        autoHideDuration={6000}
+        onClose={() => setSnackbar({ ...snackbar, open: false })}
+      >
+        <Alert severity={snackbar.severity} onClose={() => setSnackbar({ ...snackbar, open: false })}>
+          {snackbar.message}
+        </Alert>
+      </Snackbar>
+    </Box>
+  );
+}
@@ -14,6 +14,7 @@ import AttachMoneyIcon from '@mui/icons-material/AttachMoney';
 import Button from '@mui/material/Button';
 import ContentCopyIcon from '@mui/icons-material/ContentCopy';
 import OpenInNewIcon from '@mui/icons-material/OpenInNew';
+import DownloadIcon from '@mui/icons-material/Download';
 import Snackbar from '@mui/material/Snackbar';
 import Alert from '@mui/material/Alert';
 import Table from '@mui/material/Table';
@@ -23,6 +24,7 @@ import TableContainer from '@mui/material/TableContainer';
 import TableHead from '@mui/material/TableHead';
 import TableRow from '@mui/material/TableRow';
 import Paper from '@mui/material/Paper';
+import Chip from '@mui/material/Chip';
 import WarningAmberIcon from '@mui/icons-material/WarningAmber';
 
 export type StockpileItem = {
@@ -40,6 +42,17 @@ export type StockpileItem = {
   solarSystem: string;
   region: string;
   containerName?: string;
+  scope?: string;
+  markerId?: number;
+  burnRatePerDay?: number | null;
+  daysUntilEmpty?: number | null;
+  recommendedQuantity?: number | null;
+};
+
+const scopeLabels: Record<string, string> = {
+  solar_system: 'Whole system',
+  region: 'Whole region',
+  anywhere: 'Anywhere',
 };
 
 export type StockpilesResponse = {
@@ -133,49 +146,43 @@ export default function StockpilesList() {
     }
   };
 
-  const handleOpenJanice = async () => {
+  const handleCreateAppraisal = async () => {
     if (!session) return;
 
-    // Format items as "ItemName quantity" for Janice
-    const janiceText = filteredItems
+    const appraisalText = filteredItems
       .map((item) => `${item.name} ${Math.abs(item.stockpileDelta)}`)
       .join('\n');
 
     setCreatingAppraisal(true);
     try {
-      // POST to our Next.js API route which proxies to the backend
-      const response = await fetch('/api/janice/appraisal', {
+      const response = await fetch('/api/appraisals', {
         method: 'POST',
         headers: {
           'Content-Type': 'application/json',
         },
         body: JSON.stringify({
-          items: janiceText,
+          items: appraisalText,
         }),
       });
 
       if (!response.ok) {
         const errorText = await response.text();
-        console.error('Janice API error response:', response.status, errorText);
-        throw new Error(`Failed to create Janice appraisal: ${response.status} - ${errorText}`);
+        throw new Error(`Failed to create appraisal: ${response.status} - ${errorText}`);
       }
 
       const data = await response.json();
-      console.log('Janice API response:', data);
-
-      // Open the appraisal in a new tab
       if (data.code) {
-        window.open(`https://janice.e-351.com/a/${data.code}`, '_blank');
-        setSnackbarMessage('Janice appraisal created and opened!');
+        window.open(`/appraisals/${data.code}`, '_blank');
+        setSnackbarMessage('Appraisal created and opened!');
         setSnackbarOpen(true);
       } else {
-        throw new Error('Janice response missing appraisal code');
+        throw new Error('Appraisal response missing code');
       }
     } catch (err) {
       const errorMessage = err instanceof Error ? err.message : 'Unknown error';
       setSnackbarMessage(`Failed: ${errorMessage}`);
       setSnackbarOpen(true);
-      console.error('Janice API error:', err);
+      console.error('Appraisal error:', err);
     } finally {
       setCreatingAppraisal(false);
     }
@@ -264,10 +271,36 @@ export default function StockpilesList() {
           <Button
             variant="contained"
             startIcon={<OpenInNewIcon />}
-            onClick={handleOpenJanice}
+            onClick={handleCreateAppraisal}
             disabled={filteredItems.length === 0 || creatingAppraisal}
           >
-            {creatingAppraisal ? 'Creating...' : 'Create Janice Appraisal'}
+            {creatingAppraisal ? 'Creating...' : 'Create Appraisal'}
+          </Button>
+          <Button
+            variant="outlined"
+            startIcon={<DownloadIcon />}
+            href="/api/exports/stockpiles/deficits?format=xlsx"
+          >
+            Export XLSX
+          </Button>
+          <Button
+            variant="outlined"
+            href="/stockpile-replenishment"
+            disabled={stockpileItems.length === 0}
+          >
+            Plan Replenishment
+          </Button>
+          <Button
+            variant="outlined"
+            href="/notifications"
+          >
+            Alerts
+          </Button>
+          <Button
+            variant="outlined"
+            href="/stockpile-templates"
+          >
+            Templates
           </Button>
         </Box>
 
@@ -316,6 +349,9 @@ export default function StockpilesList() {
                       <TableCell align="right">Target</TableCell>
                       <TableCell align="right">Deficit</TableCell>
                       <TableCell align="right">Cost (ISK)</TableCell>
+                      <TableCell align="right">Burn / Day</TableCell>
+                      <TableCell align="right">Days Left</TableCell>
+                      <TableCell align="right">Suggested Target</TableCell>
                       <TableCell>Owner</TableCell>
                     </TableRow>
                   </TableHead>
@@ -334,7 +370,11 @@ export default function StockpilesList() {
                         <TableCell sx={{ fontWeight: 600 }}>{item.name}</TableCell>
                         <TableCell>{item.structureName}</TableCell>
                         <TableCell>{item.solarSystem}, {item.region}</TableCell>
-                        <TableCell>{item.containerName || '-'}</TableCell>
+                        <TableCell>
+                          {item.scope && scopeLabels[item.scope]
+                            ? <Chip label={scopeLabels[item.scope]} size="small" color="info" variant="outlined" />
+                            : item.containerName || '-'}
+                        </TableCell>
                         <TableCell align="right">{item.quantity.toLocaleString()}</TableCell>
                         <TableCell align="right">{item.desiredQuantity.toLocaleString()}</TableCell>
                         <TableCell align="right">
@@ -347,6 +387,24 @@ export default function StockpilesList() {
                             {item.deficitValue.toLocaleString(undefined, { maximumFractionDigits: 0 })}
                           </Typography>
                         </TableCell>
+                        <TableCell align="right">
+                          {item.burnRatePerDay != null
+                            ? item.burnRatePerDay.toLocaleString(undefined, { maximumFractionDigits: 1 })
+                            : '-'}
+                        </TableCell>
+                        <TableCell align="right">
+                          {item.daysUntilEmpty != null ? (
+                            <Typography
+                              variant="body2"
+                              sx={{ color: item.daysUntilEmpty < 3 ? 'error.main' : 'inherit', fontWeight: item.daysUntilEmpty < 3 ? 600 : 400 }}
+                            >
+                              {item.daysUntilEmpty.toLocaleString(undefined, { maximumFractionDigits: 1 })}
+                            </Typography>
+                          ) : '-'}
+                        </TableCell>
+                        <TableCell align="right">
+                          {item.recommendedQuantity != null ? item.recommendedQuantity.toLocaleString() : '-'}
+                        </TableCell>
                         <TableCell>{item.ownerName}</TableCell>
                       </TableRow>
                     ))}
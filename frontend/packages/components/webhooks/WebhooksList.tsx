@@ -0,0 +1,260 @@
+import { useState, useEffect } from 'react';
+import { useSession } from 'next-auth/react';
+import Box from '@mui/material/Box';
+import Typography from '@mui/material/Typography';
+import Card from '@mui/material/Card';
+import CardContent from '@mui/material/CardContent';
+import Chip from '@mui/material/Chip';
+import Button from '@mui/material/Button';
+import TextField from '@mui/material/TextField';
+import Switch from '@mui/material/Switch';
+import FormControlLabel from '@mui/material/FormControlLabel';
+import Autocomplete from '@mui/material/Autocomplete';
+import Table from '@mui/material/Table';
+import TableBody from '@mui/material/TableBody';
+import TableCell from '@mui/material/TableCell';
+import TableHead from '@mui/material/TableHead';
+import TableRow from '@mui/material/TableRow';
+import CircularProgress from '@mui/material/CircularProgress';
+import WebhookIcon from '@mui/icons-material/Webhook';
+import { Webhook, WebhookDelivery } from '@industry-tool/client/data/models';
+
+const statusColors: Record<WebhookDelivery['status'], 'default' | 'success' | 'error'> = {
+  pending: 'default',
+  delivered: 'success',
+  failed: 'error',
+};
+
+export default function WebhooksList() {
+  const { data: session } = useSession();
+  const [webhooks, setWebhooks] = useState<Webhook[]>([]);
+  const [eventTypes, setEventTypes] = useState<string[]>([]);
+  const [newUrl, setNewUrl] = useState('');
+  const [newEventTypes, setNewEventTypes] = useState<string[]>([]);
+  const [deliveries, setDeliveries] = useState<Record<number, WebhookDelivery[]>>({});
+  const [loading, setLoading] = useState(true);
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState('');
+  // Full secrets are only returned on create and rotate, so they are kept here until the page is left
+  const [revealedSecrets, setRevealedSecrets] = useState<Record<number, string>>({});
+
+  useEffect(() => {
+    if (session) {
+      load();
+    }
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [session]);
+
+  const load = async () => {
+    setLoading(true);
+    try {
+      const [webhooksRes, eventTypesRes] = await Promise.all([
+        fetch('/api/webhooks'),
+        fetch('/api/webhooks/event-types'),
+      ]);
+      if (webhooksRes.ok) {
+        setWebhooks(await webhooksRes.json());
+      }
+      if (eventTypesRes.ok) {
+        setEventTypes(await eventTypesRes.json());
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleCreate = async () => {
+    setSaving(true);
+    setError('');
+    try {
+      const response = await fetch('/api/webhooks', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ url: newUrl.trim(), eventTypes: newEventTypes }),
+      });
+      if (!response.ok) {
+        const body = await response.json().catch(() => ({}));
+        throw new Error(body.error || `Failed to create webhook: ${response.status}`);
+      }
+      const webhook: Webhook = await response.json();
+      setWebhooks(prev => [...prev, webhook]);
+      setRevealedSecrets(prev => ({ ...prev, [webhook.id]: webhook.secret }));
+      setNewUrl('');
+      setNewEventTypes([]);
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Unknown error');
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleToggle = async (webhook: Webhook) => {
+    const response = await fetch(`/api/webhooks/${webhook.id}`, {
+      method: 'PUT',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ url: webhook.url, eventTypes: webhook.eventTypes, isActive: !webhook.isActive }),
+    });
+    if (response.ok) {
+      const updated: Webhook = await response.json();
+      setWebhooks(prev => prev.map(w => w.id === updated.id ? updated : w));
+    }
+  };
+
+  const handleDelete = async (id: number) => {
+    if (!confirm('Delete this webhook and its delivery log?')) {
+      return;
+    }
+    const response = await fetch(`/api/webhooks/${id}`, { method: 'DELETE' });
+    if (response.ok) {
+      setWebhooks(prev => prev.filter(w => w.id !== id));
+    }
+  };
+
+  const handleRotateSecret = async (id: number) => {
+    if (!confirm('Rotate this webhook\'s secret? Requests are signed with the new secret from now on.')) {
+      return;
+    }
+    const response = await fetch(`/api/webhooks/${id}/rotate-secret`, { method: 'POST' });
+    if (response.ok) {
+      const rotated: Webhook = await response.json();
+      setWebhooks(prev => prev.map(w => w.id === rotated.id ? rotated : w));
+      setRevealedSecrets(prev => ({ ...prev, [rotated.id]: rotated.secret }));
+    }
+  };
+
+  const handlePing = async (id: number) => {
+    await fetch(`/api/webhooks/${id}/ping`, { method: 'POST' });
+    await handleShowDeliveries(id);
+  };
+
+  const handleShowDeliveries = async (id: number) => {
+    const response = await fetch(`/api/webhooks/${id}/deliveries`);
+    if (response.ok) {
+      const data: WebhookDelivery[] = await response.json();
+      setDeliveries(prev => ({ ...prev, [id]: data }));
+    }
+  };
+
+  if (loading) {
+    return (
+      <Box sx={{ display: 'flex', justifyContent: 'center', mt: 4 }}>
+        <CircularProgress />
+      </Box>
+    );
+  }
+
+  return (
+    <>
+      <Box sx={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', mb: 2 }}>
+        <Typography variant="h4" sx={{ display: 'flex', alignItems: 'center', gap: 1 }}>
+          <WebhookIcon fontSize="large" />
+          Webhooks
+        </Typography>
+        <Button variant="outlined" href="/notifications">Back to Notifications</Button>
+      </Box>
+
+      <Card sx={{ mb: 3 }}>
+        <CardContent>
+          <Typography variant="h6" gutterBottom>Add Webhook</Typography>
+          <Typography variant="body2" color="text.secondary" sx={{ mb: 2 }}>
+            Marketplace events are POSTed as JSON and signed with the webhook&apos;s secret in the
+            X-Industry-Tool-Signature header. Leave the event list empty to receive every event.
+          </Typography>
+          <Box sx={{ display: 'flex', gap: 2, alignItems: 'flex-start' }}>
+            <TextField
+              fullWidth
+              label="URL"
+              value={newUrl}
+              onChange={(e) => setNewUrl(e.target.value)}
+              placeholder="https://bot.example.com/industry-tool"
+            />
+            <Autocomplete
+              multiple
+              options={eventTypes}
+              value={newEventTypes}
+              onChange={(_, value) => setNewEventTypes(value)}
+              renderInput={(params) => <TextField {...params} label="Events" placeholder="All events" />}
+              sx={{ minWidth: 320 }}
+            />
+            <Button variant="contained" onClick={handleCreate} disabled={saving || !newUrl.trim()} sx={{ mt: 1 }}>
+              Add
+            </Button>
+          </Box>
+          {error && <Typography color="error" sx={{ mt: 1 }}>{error}</Typography>}
+        </CardContent>
+      </Card>
+
+      {webhooks.length === 0 ? (
+        <Card>
+          <CardContent>
+            <Typography variant="h6" align="center" color="text.secondary">
+              No webhooks yet.
+            </Typography>
+          </CardContent>
+        </Card>
+      ) : webhooks.map(webhook => (
+        <Card key={webhook.id} sx={{ mb: 2 }}>
+          <CardContent>
+            <Box sx={{ display: 'flex', alignItems: 'center', justifyContent: 'space-between', mb: 1 }}>
+              <Typography variant="h6" sx={{ wordBreak: 'break-all' }}>{webhook.url}</Typography>
+              <FormControlLabel
+                control={<Switch checked={webhook.isActive} onChange={() => handleToggle(webhook)} />}
+                label="Active"
+              />
+            </Box>
+            <Box sx={{ display: 'flex', flexWrap: 'wrap', gap: 1, mb: 1 }}>
+              {webhook.eventTypes.length === 0
+                ? <Chip label="All events" size="small" />
+                : webhook.eventTypes.map(t => <Chip key={t} label={t} size="small" />)}
+            </Box>
+            <Typography variant="body2" color="text.secondary" sx={{ mb: 1, fontFamily: 'monospace', wordBreak: 'break-all' }}>
+              Secret: {revealedSecrets[webhook.id] ?? webhook.secret}
+            </Typography>
+            {revealedSecrets[webhook.id] && (
+              <Typography variant="body2" color="warning.main" sx={{ mb: 1 }}>
+                Copy this secret now. It is only shown in full once.
+              </Typography>
+            )}
+            <Box sx={{ display: 'flex', gap: 1 }}>
+              <Button size="small" onClick={() => handlePing(webhook.id)}>Send Ping</Button>
+              <Button size="small" onClick={() => handleShowDeliveries(webhook.id)}>Delivery Log</Button>
+              <Button size="small" onClick={() => handleRotateSecret(webhook.id)}>Rotate Secret</Button>
+              <Button size="small" color="error" onClick={() => handleDelete(webhook.id)}>Delete</Button>
+            </Box>
+
+            {deliveries[webhook.id] && (
+              deliveries[webhook.id].length === 0 ? (
+                <Typography variant="body2" color="text.secondary" sx={{ mt: 2 }}>No deliveries yet.</Typography>
+              ) : (
+                <Table size="small" sx={{ mt: 2 }}>
+                  <TableHead>
+                    <TableRow>
+                      <TableCell>Event</TableCell>
+                      <TableCell>Status</TableCell>
+                      <TableCell align="right">Attempts</TableCell>
+                      <TableCell>Last Response</TableCell>
+                      <TableCell>Created</TableCell>
+                    </TableRow>
+                  </TableHead>
+                  <TableBody>
+                    {deliveries[webhook.id].map(d => (
+                      <TableRow key={d.id}>
+                        <TableCell>{d.eventType}</TableCell>
+                        <TableCell>
+                          <Chip label={d.status} size="small" color={statusColors[d.status]} />
+                        </TableCell>
+                        <TableCell align="right">{d.attempts}</TableCell>
+                        <TableCell>{d.lastError || (d.lastStatusCode ? `HTTP ${d.lastStatusCode}` : '')}</TableCell>
+                        <TableCell>{new Date(d.createdAt).toLocaleString()}</TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              )
+            )}
+          </CardContent>
+        </Card>
+      ))}
+    </>
+  );
+}
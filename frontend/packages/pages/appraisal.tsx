@@ -0,0 +1,30 @@
+import { useRouter } from "next/router";
+import { useSession } from "next-auth/react";
+import Loading from "@industry-tool/components/loading";
+import Unauthorized from "@industry-tool/components/unauthorized";
+import Navbar from "@industry-tool/components/Navbar";
+import Container from '@mui/material/Container';
+import AppraisalView from "@industry-tool/components/appraisals/AppraisalView";
+
+export default function Appraisal() {
+  const { status } = useSession();
+  const router = useRouter();
+  const { code } = router.query;
+
+  if (status === "loading" || !router.isReady) {
+    return <Loading />;
+  }
+
+  if (status !== "authenticated") {
+    return <Unauthorized />;
+  }
+
+  return (
+    <>
+      <Navbar />
+      <Container maxWidth={false} sx={{ mt: 4, mb: 4 }}>
+        <AppraisalView code={code as string} />
+      </Container>
+    </>
+  );
+}
@@ -14,6 +14,7 @@ import PendingSales from "@industry-tool/components/marketplace/PendingSales";
 import BuyOrders from "@industry-tool/components/marketplace/BuyOrders";
 import DemandViewer from "@industry-tool/components/marketplace/DemandViewer";
 import SalesMetrics from "@industry-tool/components/analytics/SalesMetrics";
+import PurchaseMetrics from "@industry-tool/components/analytics/PurchaseMetrics";
 
 export default function Marketplace() {
   const { status } = useSession();
@@ -52,6 +53,7 @@ export default function Marketplace() {
             <Tab label="My Buy Orders" />
             <Tab label="Demand" />
             <Tab label="Analytics" />
+            <Tab label="Spending" />
           </Tabs>
         </Box>
 
@@ -62,6 +64,7 @@ export default function Marketplace() {
         {tabIndex === 4 && <BuyOrders />}
         {tabIndex === 5 && <DemandViewer />}
         {tabIndex === 6 && <SalesMetrics />}
+        {tabIndex === 7 && <PurchaseMetrics />}
       </Container>
     </>
   );
@@ -0,0 +1,27 @@
+import { useSession } from "next-auth/react";
+import Loading from "@industry-tool/components/loading";
+import Unauthorized from "@industry-tool/components/unauthorized";
+import Navbar from "@industry-tool/components/Navbar";
+import Container from '@mui/material/Container';
+import ReplenishmentPlan from "@industry-tool/components/stockpiles/ReplenishmentPlan";
+
+export default function StockpileReplenishmentPage() {
+  const { status } = useSession();
+
+  if (status === "loading") {
+    return <Loading />;
+  }
+
+  if (status !== "authenticated") {
+    return <Unauthorized />;
+  }
+
+  return (
+    <>
+      <Navbar />
+      <Container maxWidth={false} sx={{ mt: 4, mb: 4 }}>
+        <ReplenishmentPlan />
+      </Container>
+    </>
+  );
+}
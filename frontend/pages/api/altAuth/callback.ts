@@ -13,6 +13,7 @@ let handleCharAuthFlow = async (
   res: NextApiResponse,
   session: { providerAccountId: string },
   tokenResponse: TokenEndpointResponse,
+  redirectTo: string = "/characters",
 ) => {
   let jwtDecoded = jwtDecode(tokenResponse.access_token);
   let altId = jwtDecoded.sub?.split(":")[2];
@@ -30,7 +31,7 @@ let handleCharAuthFlow = async (
     throw `error adding alt ${response.error}`;
   }
 
-  res.redirect("/characters");
+  res.redirect(redirectTo);
 };
 
 let handleCorpAuthFlow = async (
@@ -78,6 +79,9 @@ export default async function handler(
     case "corp":
       await handleCorpAuthFlow(res, session, tokenResponse);
       return;
+    case "mail":
+      await handleCharAuthFlow(res, session, tokenResponse, "/notifications");
+      return;
     default:
       throw `unknown redirect type ${redirectType}`;
   }
@@ -22,8 +22,12 @@ export default async function handler(
   }
 
   if (req.method === "GET") {
-    const { period } = req.query;
-    const queryParam = period ? `?period=${period}` : "";
+    const params = new URLSearchParams();
+    for (const key of ["period", "from", "to", "bucket", "tz", "compare"]) {
+      const value = req.query[key];
+      if (value) params.append(key, value as string);
+    }
+    const queryParam = params.toString() ? `?${params.toString()}` : "";
 
     const response = await fetch(backend + `v1/analytics/sales${queryParam}`, {
       method: "GET",
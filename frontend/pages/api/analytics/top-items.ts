@@ -22,10 +22,11 @@ export default async function handler(
   }
 
   if (req.method === "GET") {
-    const { period, limit } = req.query;
     const params = new URLSearchParams();
-    if (period) params.append("period", period as string);
-    if (limit) params.append("limit", limit as string);
+    for (const key of ["period", "from", "to", "tz", "limit"]) {
+      const value = req.query[key];
+      if (value) params.append(key, value as string);
+    }
     const queryParam = params.toString() ? `?${params.toString()}` : "";
 
     const response = await fetch(backend + `v1/analytics/top-items${queryParam}`, {
@@ -18,7 +18,15 @@ export default async function handler(
 ) {
   const session = await getServerSession(req, res, authOptions);
 
-  let path = backend + "v1/assets/";
+  const params = new URLSearchParams();
+  for (const key of ["groupId", "categoryId", "marketGroupId", "metaGroupId"]) {
+    const value = req.query[key];
+    if (value) {
+      params.set(key, Array.isArray(value) ? value.join(",") : value);
+    }
+  }
+
+  let path = backend + "v1/assets/" + (params.toString() ? `?${params.toString()}` : "");
   const response = await fetch(path, {
     method: "GET",
     headers: getHeaders(session.providerAccountId),
@@ -5,7 +5,8 @@ export default async function handler(
   req: NextApiRequest,
   res: NextApiResponse,
 ) {
-  let redirectTo = await getAuthUrl(false);
+  // ?mail=true also asks for permission to send EVE mail, for stockpile alerts
+  let redirectTo = await getAuthUrl(false, req.query["mail"] === "true");
 
   res.redirect(redirectTo);
 }
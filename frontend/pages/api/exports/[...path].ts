@@ -0,0 +1,73 @@
+import type { NextApiRequest, NextApiResponse } from "next";
+import { getServerSession } from "next-auth/next";
+import { Readable } from "stream";
+import type { ReadableStream } from "stream/web";
+import { authOptions } from "../auth/[...nextauth]";
+
+let backend = process.env.BACKEND_URL as string;
+
+const exportPaths = [
+  "purchases/buyer",
+  "purchases/seller",
+  "analytics/sales",
+  "analytics/top-items",
+  "assets",
+  "stockpiles/deficits",
+];
+
+const getHeaders = (id: string) => {
+  return {
+    "USER-ID": id,
+    "BACKEND-KEY": process.env.BACKEND_KEY as string,
+  };
+};
+
+export default async function handler(
+  req: NextApiRequest,
+  res: NextApiResponse
+) {
+  const session = await getServerSession(req, res, authOptions);
+  if (!session) {
+    return res.status(401).json({ error: "Unauthorized" });
+  }
+
+  if (req.method !== "GET") {
+    return res.status(405).json({ error: "Method not allowed" });
+  }
+
+  const { path, ...query } = req.query;
+  const exportPath = ([] as string[]).concat(path ?? []).join("/");
+  if (!exportPaths.includes(exportPath)) {
+    return res.status(404).json({ error: "Unknown export" });
+  }
+
+  const params = new URLSearchParams();
+  for (const [key, value] of Object.entries(query)) {
+    if (typeof value === "string") params.append(key, value);
+  }
+  const queryParam = params.toString() ? `?${params.toString()}` : "";
+
+  const response = await fetch(backend + `v1/exports/${exportPath}${queryParam}`, {
+    method: "GET",
+    headers: getHeaders(session.providerAccountId),
+  });
+
+  if (response.status !== 200 || !response.body) {
+    return res.status(response.status).json({ error: "Failed to export" });
+  }
+
+  // Pass the file through as it arrives rather than buffering it
+  res.status(200);
+  res.setHeader("Content-Type", response.headers.get("Content-Type") ?? "application/octet-stream");
+  const disposition = response.headers.get("Content-Disposition");
+  if (disposition) {
+    res.setHeader("Content-Disposition", disposition);
+  }
+  Readable.fromWeb(response.body as ReadableStream).pipe(res);
+}
+
+export const config = {
+  api: {
+    responseLimit: false,
+  },
+};
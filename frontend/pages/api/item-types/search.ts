@@ -22,8 +22,14 @@ export default async function handler(
   }
 
   if (req.method === "GET") {
-    const { q } = req.query;
-    const queryParam = q ? `?q=${encodeURIComponent(q as string)}` : "";
+    const params = new URLSearchParams();
+    for (const key of ["q", "groupId", "categoryId", "marketGroupId", "metaGroupId"]) {
+      const value = req.query[key];
+      if (value) {
+        params.set(key, Array.isArray(value) ? value.join(",") : value);
+      }
+    }
+    const queryParam = params.toString() ? `?${params.toString()}` : "";
 
     const response = await fetch(backend + `v1/item-types/search${queryParam}`, {
       method: "GET",
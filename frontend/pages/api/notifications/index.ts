@@ -0,0 +1,48 @@
+import type { NextApiRequest, NextApiResponse } from "next";
+import { getServerSession } from "next-auth/next";
+import { authOptions } from "../auth/[...nextauth]";
+
+let backend = process.env.BACKEND_URL as string;
+
+const getHeaders = (id: string) => {
+  return {
+    "Content-Type": "application/json",
+    "USER-ID": id,
+    "BACKEND-KEY": process.env.BACKEND_KEY as string,
+  };
+};
+
+export default async function handler(
+  req: NextApiRequest,
+  res: NextApiResponse
+) {
+  if (req.method !== "GET") {
+    return res.status(405).json({ error: "Method not allowed" });
+  }
+
+  const session = await getServerSession(req, res, authOptions);
+  if (!session) {
+    return res.status(401).json({ error: "Unauthorized" });
+  }
+
+  const params = new URLSearchParams();
+  if (req.query.unread) {
+    params.set("unread", req.query.unread as string);
+  }
+  if (req.query.limit) {
+    params.set("limit", req.query.limit as string);
+  }
+  const query = params.toString();
+
+  const response = await fetch(backend + "v1/notifications" + (query ? `?${query}` : ""), {
+    method: "GET",
+    headers: getHeaders(session.providerAccountId),
+  });
+
+  if (response.status !== 200) {
+    return res.status(response.status).json({ error: "Failed to get notifications" });
+  }
+
+  const data = await response.json();
+  res.status(200).json(data || []);
+}
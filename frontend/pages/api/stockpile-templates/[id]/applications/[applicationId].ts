@@ -0,0 +1,44 @@
+import type { NextApiRequest, NextApiResponse } from "next";
+import { getServerSession } from "next-auth/next";
+import { authOptions } from "../../../auth/[...nextauth]";
+
+let backend = process.env.BACKEND_URL as string;
+
+const getHeaders = (id: string) => {
+  return {
+    "Content-Type": "application/json",
+    "USER-ID": id,
+    "BACKEND-KEY": process.env.BACKEND_KEY as string,
+  };
+};
+
+export default async function handler(
+  req: NextApiRequest,
+  res: NextApiResponse
+) {
+  if (req.method !== "DELETE") {
+    return res.status(405).json({ error: "Method not allowed" });
+  }
+
+  const session = await getServerSession(req, res, authOptions);
+  if (!session) {
+    return res.status(401).json({ error: "Unauthorized" });
+  }
+
+  const { id, applicationId } = req.query;
+
+  if (!id || typeof id !== "string" || !applicationId || typeof applicationId !== "string") {
+    return res.status(400).json({ error: "Invalid template or application ID" });
+  }
+
+  const response = await fetch(backend + `v1/stockpile-templates/${id}/applications/${applicationId}`, {
+    method: "DELETE",
+    headers: getHeaders(session.providerAccountId),
+  });
+
+  if (response.status !== 200) {
+    return res.status(response.status).json({ error: "Failed to remove stockpile template application" });
+  }
+
+  res.status(200).json({ success: true });
+}
@@ -0,0 +1,47 @@
+import type { NextApiRequest, NextApiResponse } from "next";
+import { getServerSession } from "next-auth/next";
+import { authOptions } from "../../auth/[...nextauth]";
+
+let backend = process.env.BACKEND_URL as string;
+
+const getHeaders = (id: string) => {
+  return {
+    "Content-Type": "application/json",
+    "USER-ID": id,
+    "BACKEND-KEY": process.env.BACKEND_KEY as string,
+  };
+};
+
+export default async function handler(
+  req: NextApiRequest,
+  res: NextApiResponse
+) {
+  const session = await getServerSession(req, res, authOptions);
+  if (!session) {
+    return res.status(401).json({ error: "Unauthorized" });
+  }
+
+  const { id } = req.query;
+
+  if (!id || typeof id !== "string") {
+    return res.status(400).json({ error: "Invalid template ID" });
+  }
+
+  if (req.method === "GET" || req.method === "PUT" || req.method === "DELETE") {
+    const response = await fetch(backend + `v1/stockpile-templates/${id}`, {
+      method: req.method,
+      headers: getHeaders(session.providerAccountId),
+      body: req.method === "PUT" ? JSON.stringify(req.body) : undefined,
+    });
+
+    if (response.status !== 200) {
+      const errorText = await response.text();
+      return res.status(response.status).json({ error: errorText || "Stockpile template request failed" });
+    }
+
+    const text = await response.text();
+    return res.status(200).json(text ? JSON.parse(text) : { success: true });
+  }
+
+  return res.status(405).json({ error: "Method not allowed" });
+}
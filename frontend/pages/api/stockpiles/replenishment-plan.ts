@@ -9,7 +9,7 @@ export default async function handler(
   req: NextApiRequest,
   res: NextApiResponse,
 ) {
-  if (req.method !== 'POST') {
+  if (req.method !== 'GET') {
     return res.status(405).json({ error: 'Method not allowed' });
   }
 
@@ -19,14 +19,13 @@ export default async function handler(
   }
 
   try {
-    const response = await fetch(`${backend}v1/janice/appraisal`, {
-      method: 'POST',
+    const response = await fetch(`${backend}v1/stockpiles/replenishment-plan`, {
+      method: 'GET',
       headers: {
         'Content-Type': 'application/json',
         'USER-ID': session.providerAccountId,
         'BACKEND-KEY': backendKey,
       },
-      body: JSON.stringify(req.body),
     });
 
     if (!response.ok) {
@@ -37,7 +36,7 @@ export default async function handler(
     const data = await response.json();
     return res.status(200).json(data);
   } catch (error) {
-    console.error('Janice API error:', error);
-    return res.status(500).json({ error: 'Failed to create appraisal' });
+    console.error('Replenishment plan API error:', error);
+    return res.status(500).json({ error: 'Failed to fetch replenishment plan' });
   }
 }
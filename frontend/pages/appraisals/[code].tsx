@@ -0,0 +1,3 @@
+import Appraisal from "@industry-tool/pages/appraisal";
+
+export default Appraisal;
@@ -0,0 +1,3 @@
+import Notifications from "@industry-tool/pages/notifications";
+
+export default Notifications;
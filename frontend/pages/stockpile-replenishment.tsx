@@ -0,0 +1,3 @@
+import StockpileReplenishment from "@industry-tool/pages/stockpile-replenishment";
+
+export default StockpileReplenishment;
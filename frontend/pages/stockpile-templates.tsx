@@ -0,0 +1,3 @@
+import StockpileTemplates from "@industry-tool/pages/stockpile-templates";
+
+export default StockpileTemplates;
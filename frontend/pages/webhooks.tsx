@@ -0,0 +1,3 @@
+import Webhooks from "@industry-tool/pages/webhooks";
+
+export default Webhooks;
@@ -304,6 +304,13 @@ func (c *EsiClient) GetPlayerOwnedStationInformation(ctx context.Context, token,
 	return stations, nil
 }
 
+type universeIDs struct {
+	Characters []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"characters"`
+}
+
 type characterAffiliation struct {
 	CorporationID int64 `json:"corporation_id"`
 	FactionID     int64 `json:"faction_id"`
@@ -593,6 +600,127 @@ func (c *EsiClient) GetCorporationDivisions(ctx context.Context, corpID int64, t
 	}, nil
 }
 
+// GetCharacterAffiliations resolves the current corporation and alliance of public characters.
+// The endpoint is unauthenticated and accepts up to 1000 IDs per request.
+func (c *EsiClient) GetCharacterAffiliations(ctx context.Context, characterIDs []int64) ([]*models.CharacterAffiliation, error) {
+	var client HTTPDoer
+	if c.httpClient != nil {
+		client = c.httpClient
+	} else {
+		client = &http.Client{}
+	}
+
+	affiliations := []*models.CharacterAffiliation{}
+	for start := 0; start < len(characterIDs); start += 1000 {
+		end := min(start+1000, len(characterIDs))
+
+		body, err := json.Marshal(characterIDs[start:end])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal character ids")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://esi.evetech.net/characters/affiliation", bytes.NewBuffer(body))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create request")
+		}
+		req.Header = c.getCommonHeaders()
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to do character affiliation")
+		}
+
+		if res.StatusCode != 200 {
+			errText, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			return nil, errors.New(fmt.Sprintf("failed to get character affiliation, expected statusCode 200 got %d, %s", res.StatusCode, errText))
+		}
+
+		charAffiliation := []characterAffiliation{}
+		j, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read character affiliation body")
+		}
+		err = json.Unmarshal(j, &charAffiliation)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal character affiliation json")
+		}
+
+		for _, a := range charAffiliation {
+			affiliation := &models.CharacterAffiliation{
+				CharacterID:   a.CharacterID,
+				CorporationID: a.CorporationID,
+			}
+			if a.AllianceID != 0 {
+				allianceID := a.AllianceID
+				affiliation.AllianceID = &allianceID
+			}
+			affiliations = append(affiliations, affiliation)
+		}
+	}
+
+	return affiliations, nil
+}
+
+// GetCharacterIDs resolves exact character names (case-insensitive) to IDs via /universe/ids.
+// Names that match no character are left out of the result.
+func (c *EsiClient) GetCharacterIDs(ctx context.Context, names []string) ([]*models.CharacterName, error) {
+	var client HTTPDoer
+	if c.httpClient != nil {
+		client = c.httpClient
+	} else {
+		client = &http.Client{}
+	}
+
+	characters := []*models.CharacterName{}
+	for start := 0; start < len(names); start += 500 {
+		end := min(start+500, len(names))
+
+		body, err := json.Marshal(names[start:end])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal names")
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://esi.evetech.net/universe/ids", bytes.NewBuffer(body))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create request")
+		}
+		req.Header = c.getCommonHeaders()
+
+		res, err := client.Do(req)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to do universe ids")
+		}
+
+		if res.StatusCode != 200 {
+			errText, _ := io.ReadAll(res.Body)
+			res.Body.Close()
+			return nil, errors.New(fmt.Sprintf("failed to get universe ids, expected statusCode 200 got %d, %s", res.StatusCode, errText))
+		}
+
+		ids := universeIDs{}
+		j, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read universe ids body")
+		}
+		err = json.Unmarshal(j, &ids)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal universe ids json")
+		}
+
+		for _, character := range ids.Characters {
+			characters = append(characters, &models.CharacterName{
+				ID:   character.ID,
+				Name: character.Name,
+			})
+		}
+	}
+
+	return characters, nil
+}
+
 func (c *EsiClient) GetMarketOrders(ctx context.Context, regionID int64) ([]*MarketOrder, error) {
 	var client HTTPDoer
 	if c.httpClient != nil {
@@ -661,6 +789,71 @@ func (c *EsiClient) GetMarketOrders(ctx context.Context, regionID int64) ([]*Mar
 	return orders, nil
 }
 
+// MailRecipient addresses an EVE mail to a character, corporation, alliance or mailing list
+type MailRecipient struct {
+	RecipientID   int64  `json:"recipient_id"`
+	RecipientType string `json:"recipient_type"`
+}
+
+// Mail is an EVE mail to send through ESI
+type Mail struct {
+	Recipients   []MailRecipient `json:"recipients"`
+	Subject      string          `json:"subject"`
+	Body         string          `json:"body"`
+	ApprovedCost int64           `json:"approved_cost"`
+}
+
+// SendMail sends an EVE mail from the character, returning the new mail's ID. It needs the
+// esi-mail.send_mail.v1 scope.
+func (c *EsiClient) SendMail(ctx context.Context, characterID int64, token, refresh string, expire time.Time, mail *Mail) (int64, error) {
+	var client HTTPDoer
+	if c.httpClient != nil {
+		client = c.httpClient
+	} else {
+		t := new(oauth2.Token)
+		t.AccessToken = token
+		t.RefreshToken = refresh
+		t.TokenType = ""
+		t.Expiry = expire
+		client = c.oauthConfig.Client(ctx, t)
+	}
+
+	body, err := json.Marshal(mail)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to marshal mail")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("https://esi.evetech.net/characters/%d/mail", characterID), bytes.NewBuffer(body))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create request")
+	}
+	req.Header = c.getCommonHeaders()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to send mail")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 201 {
+		errText, _ := io.ReadAll(res.Body)
+		return 0, errors.New(fmt.Sprintf("failed to send mail, expected statusCode 201 got %d, %s", res.StatusCode, errText))
+	}
+
+	j, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read mail response")
+	}
+
+	var mailID int64
+	err = json.Unmarshal(j, &mailID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal mail id")
+	}
+
+	return mailID, nil
+}
+
 func (c *EsiClient) getCommonHeaders() http.Header {
 	headers := http.Header{}
 	headers.Add("X-Compatibility-Date", "2025-12-16")
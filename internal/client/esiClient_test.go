@@ -127,3 +127,130 @@ func Test_ClientShouldGetMarketOrders(t *testing.T) {
 	assert.Equal(t, 5.45, orders[1].Price)
 	assert.True(t, orders[1].IsBuyOrder)
 }
+
+func Test_ClientShouldGetCharacterAffiliations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTPClient := NewMockHTTPDoer(ctrl)
+
+	affiliationsJSON := []byte(`[
+		{"character_id": 1001, "corporation_id": 98000001, "alliance_id": 99000001},
+		{"character_id": 1002, "corporation_id": 98000002}
+	]`)
+
+	mockResponse := &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(affiliationsJSON)),
+	}
+
+	mockHTTPClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, "POST", req.Method)
+			assert.Equal(t, "[1001,1002]", string(body))
+			return mockResponse, nil
+		}).
+		Times(1)
+
+	esiClient := client.NewEsiClientWithHTTPClient("test-client-id", "test-client-secret", mockHTTPClient)
+
+	affiliations, err := esiClient.GetCharacterAffiliations(context.Background(), []int64{1001, 1002})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(affiliations))
+	assert.Equal(t, int64(98000001), affiliations[0].CorporationID)
+	assert.Equal(t, int64(99000001), *affiliations[0].AllianceID)
+	assert.Nil(t, affiliations[1].AllianceID)
+}
+
+func Test_ClientShouldGetCharacterIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTPClient := NewMockHTTPDoer(ctrl)
+
+	idsJSON := []byte(`{
+		"characters": [{"id": 2112625428, "name": "CCP Zoetrope"}],
+		"corporations": [{"id": 109299958, "name": "C C P"}]
+	}`)
+
+	mockHTTPClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, "POST", req.Method)
+			assert.Equal(t, "/universe/ids", req.URL.Path)
+			assert.Equal(t, `["ccp zoetrope"]`, string(body))
+			return &http.Response{
+				StatusCode: 200,
+				Body:       io.NopCloser(bytes.NewReader(idsJSON)),
+			}, nil
+		}).
+		Times(1)
+
+	esiClient := client.NewEsiClientWithHTTPClient("test-client-id", "test-client-secret", mockHTTPClient)
+
+	characters, err := esiClient.GetCharacterIDs(context.Background(), []string{"ccp zoetrope"})
+	assert.NoError(t, err)
+	assert.Len(t, characters, 1)
+	assert.Equal(t, int64(2112625428), characters[0].ID)
+	assert.Equal(t, "CCP Zoetrope", characters[0].Name)
+}
+
+func Test_ClientShouldSendMail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTPClient := NewMockHTTPDoer(ctrl)
+
+	mockHTTPClient.EXPECT().
+		Do(gomock.Any()).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			assert.Equal(t, "POST", req.Method)
+			assert.Equal(t, "/characters/12345/mail", req.URL.Path)
+			assert.JSONEq(t, `{
+				"recipients": [{"recipient_id": 12345, "recipient_type": "character"}],
+				"subject": "Low stock",
+				"body": "Tritanium is low",
+				"approved_cost": 0
+			}`, string(body))
+			return &http.Response{
+				StatusCode: 201,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`987654`))),
+			}, nil
+		}).
+		Times(1)
+
+	esiClient := client.NewEsiClientWithHTTPClient("test-client-id", "test-client-secret", mockHTTPClient)
+
+	mailID, err := esiClient.SendMail(context.Background(), 12345, "test-token", "test-refresh", time.Now(), &client.Mail{
+		Recipients: []client.MailRecipient{{RecipientID: 12345, RecipientType: "character"}},
+		Subject:    "Low stock",
+		Body:       "Tritanium is low",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(987654), mailID)
+}
+
+func Test_ClientShouldFailToSendMailWithoutScope(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHTTPClient := NewMockHTTPDoer(ctrl)
+
+	mockHTTPClient.EXPECT().
+		Do(gomock.Any()).
+		Return(&http.Response{
+			StatusCode: 403,
+			Body:       io.NopCloser(bytes.NewReader([]byte(`{"error":"token not valid for scope(s): esi-mail.send_mail.v1"}`))),
+		}, nil).
+		Times(1)
+
+	esiClient := client.NewEsiClientWithHTTPClient("test-client-id", "test-client-secret", mockHTTPClient)
+
+	_, err := esiClient.SendMail(context.Background(), 12345, "test-token", "test-refresh", time.Now(), &client.Mail{Subject: "Low stock"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "403")
+}
@@ -58,6 +58,9 @@ func (f *FuzzWorks) GetInventoryTypes(ctx context.Context) ([]models.EveInventor
 	typeNamePos := headerToPostion["typeName"]
 	volPosition := headerToPostion["volume"]
 	iconPosition := headerToPostion["iconID"]
+	groupPosition, hasGroup := headerToPostion["groupID"]
+	marketGroupPosition, hasMarketGroup := headerToPostion["marketGroupID"]
+	publishedPosition, hasPublished := headerToPostion["published"]
 
 	invTypes := []models.EveInventoryType{}
 	for {
@@ -89,12 +92,32 @@ func (f *FuzzWorks) GetInventoryTypes(ctx context.Context) ([]models.EveInventor
 			icon = &ic
 		}
 
-		invTypes = append(invTypes, models.EveInventoryType{
+		invType := models.EveInventoryType{
 			TypeID:   id,
 			TypeName: cols[typeNamePos],
 			Volume:   vol,
 			IconID:   icon,
-		})
+		}
+
+		if hasGroup {
+			invType.GroupID, err = parseOptionalInt(cols[groupPosition])
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse group id")
+			}
+		}
+
+		if hasMarketGroup {
+			invType.MarketGroupID, err = parseOptionalInt(cols[marketGroupPosition])
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse market group id")
+			}
+		}
+
+		if hasPublished {
+			invType.Published = parseBool(cols[publishedPosition])
+		}
+
+		invTypes = append(invTypes, invType)
 	}
 
 	return invTypes, nil
@@ -324,3 +347,217 @@ func (f *FuzzWorks) GetNPCStations(ctx context.Context) ([]models.Station, error
 
 	return invTypes, nil
 }
+
+func (f *FuzzWorks) GetItemCategories(ctx context.Context) ([]models.ItemCategory, error) {
+	categories := []models.ItemCategory{}
+	err := f.readTable("invCategories", func(cols []string, pos map[string]int) error {
+		id, err := strconv.ParseInt(cols[pos["categoryID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse category id")
+		}
+
+		categories = append(categories, models.ItemCategory{
+			ID:        id,
+			Name:      cols[pos["categoryName"]],
+			Published: parseBool(cols[pos["published"]]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (f *FuzzWorks) GetItemGroups(ctx context.Context) ([]models.ItemGroup, error) {
+	groups := []models.ItemGroup{}
+	err := f.readTable("invGroups", func(cols []string, pos map[string]int) error {
+		id, err := strconv.ParseInt(cols[pos["groupID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse group id")
+		}
+
+		categoryID, err := strconv.ParseInt(cols[pos["categoryID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse group category id")
+		}
+
+		groups = append(groups, models.ItemGroup{
+			ID:         id,
+			CategoryID: categoryID,
+			Name:       cols[pos["groupName"]],
+			Published:  parseBool(cols[pos["published"]]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (f *FuzzWorks) GetMarketGroups(ctx context.Context) ([]models.MarketGroup, error) {
+	marketGroups := []models.MarketGroup{}
+	err := f.readTable("invMarketGroups", func(cols []string, pos map[string]int) error {
+		id, err := strconv.ParseInt(cols[pos["marketGroupID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse market group id")
+		}
+
+		parentID, err := parseOptionalInt(cols[pos["parentGroupID"]])
+		if err != nil {
+			return errors.Wrap(err, "failed to parse market group parent id")
+		}
+
+		marketGroups = append(marketGroups, models.MarketGroup{
+			ID:       id,
+			ParentID: parentID,
+			Name:     cols[pos["marketGroupName"]],
+			HasTypes: parseBool(cols[pos["hasTypes"]]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return marketGroups, nil
+}
+
+func (f *FuzzWorks) GetMetaGroups(ctx context.Context) ([]models.MetaGroup, error) {
+	metaGroups := []models.MetaGroup{}
+	err := f.readTable("invMetaGroups", func(cols []string, pos map[string]int) error {
+		id, err := strconv.ParseInt(cols[pos["metaGroupID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse meta group id")
+		}
+
+		metaGroups = append(metaGroups, models.MetaGroup{
+			ID:   id,
+			Name: cols[pos["metaGroupName"]],
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return metaGroups, nil
+}
+
+// GetMetaTypes returns the meta group and parent of every variant type, keyed by type ID
+func (f *FuzzWorks) GetMetaTypes(ctx context.Context) (map[int64]models.ItemMetaType, error) {
+	metaTypes := map[int64]models.ItemMetaType{}
+	err := f.readTable("invMetaTypes", func(cols []string, pos map[string]int) error {
+		typeID, err := strconv.ParseInt(cols[pos["typeID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse meta type id")
+		}
+
+		metaGroupID, err := strconv.ParseInt(cols[pos["metaGroupID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse meta type group id")
+		}
+
+		parentTypeID, err := parseOptionalInt(cols[pos["parentTypeID"]])
+		if err != nil {
+			return errors.Wrap(err, "failed to parse meta type parent id")
+		}
+
+		metaTypes[typeID] = models.ItemMetaType{
+			TypeID:       typeID,
+			ParentTypeID: parentTypeID,
+			MetaGroupID:  metaGroupID,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return metaTypes, nil
+}
+
+// GetPackagedVolumes returns the repackaged volume of ships, containers and the other types whose
+// volume shrinks when packaged, keyed by type ID
+func (f *FuzzWorks) GetPackagedVolumes(ctx context.Context) (map[int64]float64, error) {
+	volumes := map[int64]float64{}
+	err := f.readTable("invVolumes", func(cols []string, pos map[string]int) error {
+		typeID, err := strconv.ParseInt(cols[pos["typeID"]], 10, 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse packaged volume type id")
+		}
+
+		volume, err := strconv.ParseFloat(cols[pos["volume"]], 64)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse packaged volume")
+		}
+
+		volumes[typeID] = volume
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return volumes, nil
+}
+
+// readTable downloads one table of the dump and calls row for each record, with every header
+// mapped to its column
+func (f *FuzzWorks) readTable(table string, row func(cols []string, pos map[string]int) error) error {
+	res, err := f.client.Get(fmt.Sprintf("%s/%s.csv.bz2", f.baseUrl, table))
+	if err != nil {
+		return errors.Wrap(err, "failed to pull data from fuzzworks")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != 200 {
+		return errors.New(fmt.Sprintf("pulling %s from fuzzworks failed, expected status code 200 got %d", table, res.StatusCode))
+	}
+
+	csvR := csv.NewReader(bzip2.NewReader(res.Body))
+
+	headers, err := csvR.Read()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s headers", table)
+	}
+
+	headerToPostion := map[string]int{}
+	for i, header := range headers {
+		headerToPostion[header] = i
+	}
+
+	for {
+		cols, err := csvR.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s row", table)
+		}
+
+		if err := row(cols, headerToPostion); err != nil {
+			return errors.Wrapf(err, "failed to parse %s", table)
+		}
+	}
+}
+
+// parseOptionalInt reads an ID column where missing values are written as None
+func parseOptionalInt(value string) (*int64, error) {
+	if value == "None" || value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func parseBool(value string) bool {
+	return value == "1" || value == "True" || value == "true"
+}
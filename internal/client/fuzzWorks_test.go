@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/annymsMthd/industry-tool/internal/client"
+	"github.com/annymsMthd/industry-tool/internal/models"
 )
 
 func Test_ItemType(t *testing.T) {
@@ -50,3 +51,146 @@ func Test_ItemType(t *testing.T) {
 	assert.Equal(t, int64(34), items[0].TypeID)
 	assert.Equal(t, "Tritanium", items[0].TypeName)
 }
+
+func fuzzWorksResponse(t *testing.T, csvData string) *http.Response {
+	var compressed bytes.Buffer
+	writer, err := bzip2.NewWriter(&compressed, &bzip2.WriterConfig{Level: bzip2.DefaultCompression})
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte(csvData))
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	return &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewReader(compressed.Bytes())),
+	}
+}
+
+func Test_ItemTypeMetadataColumns(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	httpClient := NewMockHttpGetter(ctrl)
+
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invTypes.csv.bz2").
+		Return(fuzzWorksResponse(t, "typeID,groupID,typeName,volume,published,marketGroupID,iconID\n"+
+			"34,18,Tritanium,0.01,1,1857,22\n"+
+			"670,29,Capsule,500,0,None,None\n"), nil)
+
+	items, err := client.NewFuzzWorks(httpClient).GetInventoryTypes(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	assert.Equal(t, int64(18), *items[0].GroupID)
+	assert.Equal(t, int64(1857), *items[0].MarketGroupID)
+	assert.True(t, items[0].Published)
+
+	assert.Equal(t, int64(29), *items[1].GroupID)
+	assert.Nil(t, items[1].MarketGroupID)
+	assert.Nil(t, items[1].IconID)
+	assert.False(t, items[1].Published)
+}
+
+func Test_ItemGroupsAndCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	httpClient := NewMockHttpGetter(ctrl)
+
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invCategories.csv.bz2").
+		Return(fuzzWorksResponse(t, "categoryID,categoryName,iconID,published\n4,Material,22,1\n7,Module,None,1\n"), nil)
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invGroups.csv.bz2").
+		Return(fuzzWorksResponse(t, "groupID,categoryID,groupName,iconID,useBasePrice,anchored,anchorable,fittableNonSingleton,published\n18,4,Mineral,22,1,0,0,0,1\n"), nil)
+
+	fuzzWorks := client.NewFuzzWorks(httpClient)
+
+	categories, err := fuzzWorks.GetItemCategories(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ItemCategory{
+		{ID: 4, Name: "Material", Published: true},
+		{ID: 7, Name: "Module", Published: true},
+	}, categories)
+
+	groups, err := fuzzWorks.GetItemGroups(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ItemGroup{
+		{ID: 18, CategoryID: 4, Name: "Mineral", Published: true},
+	}, groups)
+}
+
+func Test_MarketGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	httpClient := NewMockHttpGetter(ctrl)
+
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invMarketGroups.csv.bz2").
+		Return(fuzzWorksResponse(t, "marketGroupID,parentGroupID,marketGroupName,description,iconID,hasTypes\n"+
+			"533,None,Materials,\"Raw, refined and salvaged materials\",1277,0\n"+
+			"1857,533,Minerals,Refined minerals,22,1\n"), nil)
+
+	marketGroups, err := client.NewFuzzWorks(httpClient).GetMarketGroups(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, marketGroups, 2)
+
+	assert.Equal(t, "Materials", marketGroups[0].Name)
+	assert.Nil(t, marketGroups[0].ParentID)
+	assert.False(t, marketGroups[0].HasTypes)
+
+	assert.Equal(t, int64(533), *marketGroups[1].ParentID)
+	assert.True(t, marketGroups[1].HasTypes)
+}
+
+func Test_MetaTypesAndPackagedVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	httpClient := NewMockHttpGetter(ctrl)
+
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invMetaGroups.csv.bz2").
+		Return(fuzzWorksResponse(t, "metaGroupID,metaGroupName,description,iconID\n1,Tech I,None,None\n2,Tech II,None,None\n"), nil)
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invMetaTypes.csv.bz2").
+		Return(fuzzWorksResponse(t, "typeID,parentTypeID,metaGroupID\n2048,438,2\n33076,None,1\n"), nil)
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invVolumes.csv.bz2").
+		Return(fuzzWorksResponse(t, "typeID,volume\n587,2500\n3293,33\n"), nil)
+
+	fuzzWorks := client.NewFuzzWorks(httpClient)
+
+	metaGroups, err := fuzzWorks.GetMetaGroups(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []models.MetaGroup{{ID: 1, Name: "Tech I"}, {ID: 2, Name: "Tech II"}}, metaGroups)
+
+	metaTypes, err := fuzzWorks.GetMetaTypes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), metaTypes[2048].MetaGroupID)
+	assert.Equal(t, int64(438), *metaTypes[2048].ParentTypeID)
+	assert.Nil(t, metaTypes[33076].ParentTypeID)
+
+	volumes, err := fuzzWorks.GetPackagedVolumes(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]float64{587: 2500, 3293: 33}, volumes)
+}
+
+func Test_FuzzWorksTableErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	httpClient := NewMockHttpGetter(ctrl)
+
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invGroups.csv.bz2").
+		Return(&http.Response{StatusCode: 503, Body: io.NopCloser(bytes.NewReader(nil))}, nil)
+	httpClient.EXPECT().
+		Get("https://www.fuzzwork.co.uk/dump/latest//invVolumes.csv.bz2").
+		Return(fuzzWorksResponse(t, "typeID,volume\n587,large\n"), nil)
+
+	fuzzWorks := client.NewFuzzWorks(httpClient)
+
+	_, err := fuzzWorks.GetItemGroups(context.Background())
+	assert.ErrorContains(t, err, "got 503")
+
+	_, err = fuzzWorks.GetPackagedVolumes(context.Background())
+	assert.ErrorContains(t, err, "failed to parse packaged volume")
+}
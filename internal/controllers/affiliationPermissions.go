@@ -0,0 +1,126 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type AffiliationPermissionsRepository interface {
+	GetByGrantingUser(ctx context.Context, userID int64) ([]*models.AffiliationPermission, error)
+	Upsert(ctx context.Context, perm *models.AffiliationPermission) error
+	Delete(ctx context.Context, id int64, userID int64) error
+}
+
+type AffiliationPermissions struct {
+	repository AffiliationPermissionsRepository
+}
+
+func NewAffiliationPermissions(router Routerer, repository AffiliationPermissionsRepository) *AffiliationPermissions {
+	controller := &AffiliationPermissions{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/permissions/affiliations", web.AuthAccessUser, controller.GetPermissions, "GET")
+	router.RegisterRestAPIRoute("/v1/permissions/affiliations", web.AuthAccessUser, controller.UpdatePermission, "POST")
+	router.RegisterRestAPIRoute("/v1/permissions/affiliations/{id}", web.AuthAccessUser, controller.DeletePermission, "DELETE")
+
+	return controller
+}
+
+// GetPermissions returns the corporation and alliance grants made by the user
+func (c *AffiliationPermissions) GetPermissions(args *web.HandlerArgs) (any, *web.HttpError) {
+	permissions, err := c.repository.GetByGrantingUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get affiliation permissions"),
+		}
+	}
+
+	return permissions, nil
+}
+
+// UpdatePermission grants or revokes a service for every member of a corporation or alliance
+func (c *AffiliationPermissions) UpdatePermission(args *web.HandlerArgs) (any, *web.HttpError) {
+	d := json.NewDecoder(args.Request.Body)
+	var req struct {
+		ReceiverType string `json:"receiverType"`
+		ReceiverID   int64  `json:"receiverId"`
+		ServiceType  string `json:"serviceType"`
+		CanAccess    bool   `json:"canAccess"`
+	}
+	err := d.Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	if req.ReceiverType != "corporation" && req.ReceiverType != "alliance" {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("receiverType must be corporation or alliance"),
+		}
+	}
+	if req.ReceiverID <= 0 {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("receiverId is required"),
+		}
+	}
+	if !models.IsValidServiceType(req.ServiceType) {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("unknown service type %q", req.ServiceType),
+		}
+	}
+
+	perm := &models.AffiliationPermission{
+		GrantingUserID: *args.User,
+		ReceiverType:   req.ReceiverType,
+		ReceiverID:     req.ReceiverID,
+		ServiceType:    req.ServiceType,
+		CanAccess:      req.CanAccess,
+	}
+
+	err = c.repository.Upsert(args.Request.Context(), perm)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to update affiliation permission"),
+		}
+	}
+
+	return perm, nil
+}
+
+// DeletePermission removes a corporation or alliance grant
+func (c *AffiliationPermissions) DeletePermission(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid permission ID"),
+		}
+	}
+
+	err = c.repository.Delete(args.Request.Context(), id, *args.User)
+	if err != nil {
+		if errors.Is(err, repositories.ErrAffiliationPermissionNotFound) {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to delete affiliation permission"),
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,163 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAffiliationPermissionsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAffiliationPermissionsRepository) GetByGrantingUser(ctx context.Context, userID int64) ([]*models.AffiliationPermission, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AffiliationPermission), args.Error(1)
+}
+
+func (m *MockAffiliationPermissionsRepository) Upsert(ctx context.Context, perm *models.AffiliationPermission) error {
+	args := m.Called(ctx, perm)
+	return args.Error(0)
+}
+
+func (m *MockAffiliationPermissionsRepository) Delete(ctx context.Context, id int64, userID int64) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func Test_AffiliationPermissionsController_GetPermissions_Success(t *testing.T) {
+	mockRepo := new(MockAffiliationPermissionsRepository)
+	userID := int64(123)
+
+	expected := []*models.AffiliationPermission{
+		{ID: 1, GrantingUserID: userID, ReceiverType: "corporation", ReceiverID: 98000001, ServiceType: "for_sale_browse", CanAccess: true},
+	}
+	mockRepo.On("GetByGrantingUser", mock.Anything, userID).Return(expected, nil)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/permissions/affiliations", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewAffiliationPermissions(&MockRouter{}, mockRepo)
+	result, httpErr := controller.GetPermissions(args)
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, expected, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_AffiliationPermissionsController_UpdatePermission_Success(t *testing.T) {
+	mockRepo := new(MockAffiliationPermissionsRepository)
+	userID := int64(123)
+
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(p *models.AffiliationPermission) bool {
+		return p.GrantingUserID == userID &&
+			p.ReceiverType == "alliance" &&
+			p.ReceiverID == 99000001 &&
+			p.ServiceType == "for_sale_browse" &&
+			p.CanAccess
+	})).Return(nil)
+
+	body, _ := json.Marshal(map[string]any{
+		"receiverType": "alliance",
+		"receiverId":   99000001,
+		"serviceType":  "for_sale_browse",
+		"canAccess":    true,
+	})
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/permissions/affiliations", bytes.NewReader(body)),
+		User:    &userID,
+	}
+
+	controller := controllers.NewAffiliationPermissions(&MockRouter{}, mockRepo)
+	result, httpErr := controller.UpdatePermission(args)
+
+	assert.Nil(t, httpErr)
+	assert.NotNil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_AffiliationPermissionsController_UpdatePermission_Validation(t *testing.T) {
+	userID := int64(123)
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{"invalid receiver type", map[string]any{"receiverType": "character", "receiverId": 1, "serviceType": "for_sale_browse"}},
+		{"missing receiver id", map[string]any{"receiverType": "corporation", "serviceType": "for_sale_browse"}},
+		{"missing service type", map[string]any{"receiverType": "corporation", "receiverId": 1}},
+		{"unknown service type", map[string]any{"receiverType": "corporation", "receiverId": 1, "serviceType": "manufacturing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAffiliationPermissionsRepository)
+			body, _ := json.Marshal(tt.body)
+			args := &web.HandlerArgs{
+				Request: httptest.NewRequest("POST", "/v1/permissions/affiliations", bytes.NewReader(body)),
+				User:    &userID,
+			}
+
+			controller := controllers.NewAffiliationPermissions(&MockRouter{}, mockRepo)
+			result, httpErr := controller.UpdatePermission(args)
+
+			assert.Nil(t, result)
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_AffiliationPermissionsController_DeletePermission_NotFound(t *testing.T) {
+	mockRepo := new(MockAffiliationPermissionsRepository)
+	userID := int64(123)
+
+	mockRepo.On("Delete", mock.Anything, int64(5), userID).Return(repositories.ErrAffiliationPermissionNotFound)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("DELETE", "/v1/permissions/affiliations/5", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "5"},
+	}
+
+	controller := controllers.NewAffiliationPermissions(&MockRouter{}, mockRepo)
+	_, httpErr := controller.DeletePermission(args)
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_AffiliationPermissionsController_DeletePermission_Success(t *testing.T) {
+	mockRepo := new(MockAffiliationPermissionsRepository)
+	userID := int64(123)
+
+	mockRepo.On("Delete", mock.Anything, int64(5), userID).Return(nil)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("DELETE", "/v1/permissions/affiliations/5", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "5"},
+	}
+
+	controller := controllers.NewAffiliationPermissions(&MockRouter{}, mockRepo)
+	_, httpErr := controller.DeletePermission(args)
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
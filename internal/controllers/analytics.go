@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
@@ -22,16 +23,21 @@ func NewAnalytics(router Routerer, repository *repositories.SalesAnalytics) *Ana
 	router.RegisterRestAPIRoute("/v1/analytics/top-items", web.AuthAccessUser, c.GetTopItems, "GET")
 	router.RegisterRestAPIRoute("/v1/analytics/buyers", web.AuthAccessUser, c.GetBuyerAnalytics, "GET")
 	router.RegisterRestAPIRoute("/v1/analytics/item-history", web.AuthAccessUser, c.GetItemSalesHistory, "GET")
+	router.RegisterRestAPIRoute("/v1/analytics/purchases", web.AuthAccessUser, c.GetPurchaseMetrics, "GET")
+	router.RegisterRestAPIRoute("/v1/analytics/purchases/top-items", web.AuthAccessUser, c.GetTopItemsPurchased, "GET")
+	router.RegisterRestAPIRoute("/v1/analytics/purchases/sellers", web.AuthAccessUser, c.GetSellerAnalytics, "GET")
 
 	return c
 }
 
 // GetSalesMetrics returns sales metrics with time series data
 func (c *Analytics) GetSalesMetrics(args *web.HandlerArgs) (any, *web.HttpError) {
-	periodStr := args.Request.URL.Query().Get("period")
-	period := parsePeriod(periodStr)
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
 
-	metrics, err := c.repository.GetSalesMetrics(args.Request.Context(), *args.User, period)
+	metrics, err := c.repository.GetSalesMetrics(args.Request.Context(), *args.User, rng)
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: http.StatusInternalServerError,
@@ -42,20 +48,27 @@ func (c *Analytics) GetSalesMetrics(args *web.HandlerArgs) (any, *web.HttpError)
 	return metrics, nil
 }
 
-// GetTopItems returns the top selling items
+// GetTopItems returns the top selling items, by revenue unless sort asks for profit or margin
 func (c *Analytics) GetTopItems(args *web.HandlerArgs) (any, *web.HttpError) {
-	periodStr := args.Request.URL.Query().Get("period")
-	period := parsePeriod(periodStr)
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
 
-	limitStr := args.Request.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
+	limit := parseLimit(args.Request.URL.Query().Get("limit"))
+
+	sortBy := args.Request.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = repositories.TopItemsByRevenue
+	}
+	if !repositories.IsValidTopItemsOrdering(sortBy) {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Errorf("invalid sort %q, expected revenue, profit or margin", sortBy),
 		}
 	}
 
-	items, err := c.repository.GetTopItems(args.Request.Context(), *args.User, period, limit)
+	items, err := c.repository.GetTopItems(args.Request.Context(), *args.User, rng, limit, sortBy)
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: http.StatusInternalServerError,
@@ -68,18 +81,14 @@ func (c *Analytics) GetTopItems(args *web.HandlerArgs) (any, *web.HttpError) {
 
 // GetBuyerAnalytics returns analytics about buyers
 func (c *Analytics) GetBuyerAnalytics(args *web.HandlerArgs) (any, *web.HttpError) {
-	periodStr := args.Request.URL.Query().Get("period")
-	period := parsePeriod(periodStr)
-
-	limitStr := args.Request.URL.Query().Get("limit")
-	limit := 10
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
 	}
 
-	buyers, err := c.repository.GetBuyerAnalytics(args.Request.Context(), *args.User, period, limit)
+	limit := parseLimit(args.Request.URL.Query().Get("limit"))
+
+	buyers, err := c.repository.GetBuyerAnalytics(args.Request.Context(), *args.User, rng, limit)
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: http.StatusInternalServerError,
@@ -108,10 +117,12 @@ func (c *Analytics) GetItemSalesHistory(args *web.HandlerArgs) (any, *web.HttpEr
 		}
 	}
 
-	periodStr := args.Request.URL.Query().Get("period")
-	period := parsePeriod(periodStr)
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
 
-	item, err := c.repository.GetItemSalesHistory(args.Request.Context(), *args.User, typeID, period)
+	item, err := c.repository.GetItemSalesHistory(args.Request.Context(), *args.User, typeID, rng)
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: http.StatusInternalServerError,
@@ -122,6 +133,159 @@ func (c *Analytics) GetItemSalesHistory(args *web.HandlerArgs) (any, *web.HttpEr
 	return item, nil
 }
 
+// GetPurchaseMetrics returns the buyer's spending metrics with time series data
+func (c *Analytics) GetPurchaseMetrics(args *web.HandlerArgs) (any, *web.HttpError) {
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	metrics, err := c.repository.GetPurchaseMetrics(args.Request.Context(), *args.User, rng)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get purchase metrics"),
+		}
+	}
+
+	return metrics, nil
+}
+
+// GetTopItemsPurchased returns the items the buyer spent the most on
+func (c *Analytics) GetTopItemsPurchased(args *web.HandlerArgs) (any, *web.HttpError) {
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	limit := parseLimit(args.Request.URL.Query().Get("limit"))
+
+	items, err := c.repository.GetTopItemsPurchased(args.Request.Context(), *args.User, rng, limit)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get top items purchased"),
+		}
+	}
+
+	return items, nil
+}
+
+// GetSellerAnalytics returns the sellers the buyer spent the most with
+func (c *Analytics) GetSellerAnalytics(args *web.HandlerArgs) (any, *web.HttpError) {
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	limit := parseLimit(args.Request.URL.Query().Get("limit"))
+
+	sellers, err := c.repository.GetSellerAnalytics(args.Request.Context(), *args.User, rng, limit)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get seller analytics"),
+		}
+	}
+
+	return sellers, nil
+}
+
+// parseAnalyticsRange reads the reporting window. from and to (exclusive) accept RFC 3339
+// timestamps or YYYY-MM-DD dates in tz and take precedence over period. bucket is hour, day,
+// week or month (default day); tz is "EVE" (UTC, the default) or an IANA zone such as
+// "Europe/Berlin"; compare=previous adds the totals of the preceding period.
+func parseAnalyticsRange(args *web.HandlerArgs) (repositories.AnalyticsRange, *web.HttpError) {
+	query := args.Request.URL.Query()
+
+	rng := repositories.LastDays(parsePeriod(query.Get("period")))
+
+	switch tz := query.Get("tz"); tz {
+	case "", "EVE", "UTC":
+	default:
+		loc, err := time.LoadLocation(tz)
+		if err != nil || tz == "Local" {
+			return rng, &web.HttpError{
+				StatusCode: http.StatusBadRequest,
+				Error:      errors.Errorf("invalid tz %q", tz),
+			}
+		}
+		rng.Location = loc
+	}
+
+	if bucket := query.Get("bucket"); bucket != "" {
+		rng.Bucket = bucket
+	}
+
+	from, err := parseAnalyticsTime(query.Get("from"), rng.Location)
+	if err != nil {
+		return rng, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Wrap(err, "invalid from"),
+		}
+	}
+	to, err := parseAnalyticsTime(query.Get("to"), rng.Location)
+	if err != nil {
+		return rng, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Wrap(err, "invalid to"),
+		}
+	}
+	if from != nil || to != nil {
+		rng.From = from
+		rng.To = to
+	}
+
+	switch compare := query.Get("compare"); compare {
+	case "":
+	case "previous":
+		rng.ComparePrevious = true
+	default:
+		return rng, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Errorf("invalid compare %q, expected previous", compare),
+		}
+	}
+
+	err = rng.Validate()
+	if err != nil {
+		return rng, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      err,
+		}
+	}
+
+	return rng, nil
+}
+
+// parseAnalyticsTime parses an RFC 3339 timestamp, or a date taken as midnight in loc
+func parseAnalyticsTime(value string, loc *time.Location) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			return nil, errors.Errorf("%q is not an RFC 3339 timestamp or YYYY-MM-DD date", value)
+		}
+	}
+
+	return &t, nil
+}
+
+// parseLimit reads a result limit between 1 and 100, defaulting to 10
+func parseLimit(limitStr string) int {
+	limit := 10
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+	return limit
+}
+
 // parsePeriod converts period string to number of days
 // Supported formats: "7d", "30d", "90d", "1y", "all" (0 = all time)
 func parsePeriod(periodStr string) int {
@@ -186,6 +186,38 @@ func TestAnalyticsController_GetTopItems(t *testing.T) {
 		items := result.([]models.ItemSalesData)
 		assert.Len(t, items, 1)
 	})
+
+	t.Run("Get top items by profit", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/analytics/top-items?sort=profit", nil)
+
+		testUserID := sellerUser.ID
+		args := &web.HandlerArgs{
+			Request: req,
+			User:    &testUserID,
+		}
+
+		result, httpErr := controller.GetTopItems(args)
+		require.Nil(t, httpErr)
+		require.NotNil(t, result)
+
+		items := result.([]models.ItemSalesData)
+		assert.Len(t, items, 2)
+	})
+
+	t.Run("Get top items with invalid sort", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/v1/analytics/top-items?sort=turnover", nil)
+
+		testUserID := sellerUser.ID
+		args := &web.HandlerArgs{
+			Request: req,
+			User:    &testUserID,
+		}
+
+		result, httpErr := controller.GetTopItems(args)
+		assert.Nil(t, result)
+		require.NotNil(t, httpErr)
+		assert.Equal(t, 400, httpErr.StatusCode)
+	})
 }
 
 func TestAnalyticsController_GetItemSalesHistory(t *testing.T) {
@@ -280,3 +312,127 @@ func TestAnalyticsController_GetItemSalesHistory(t *testing.T) {
 		assert.Equal(t, 400, httpErr.StatusCode)
 	})
 }
+
+func TestAnalyticsController_GetPurchaseMetrics(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	itemTypes := []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, IconID: nil},
+	}
+	require.NoError(t, itemTypeRepo.UpsertItemTypes(ctx, itemTypes))
+
+	userRepo := repositories.NewUserRepository(db)
+	sellerUser := &repositories.User{ID: 998010, Name: "SellerTest5"}
+	buyerUser := &repositories.User{ID: 998011, Name: "BuyerTest5"}
+	require.NoError(t, userRepo.Add(ctx, sellerUser))
+	require.NoError(t, userRepo.Add(ctx, buyerUser))
+
+	txRepo := repositories.NewPurchaseTransactions(db)
+	dbTx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, txRepo.Create(ctx, dbTx, &models.PurchaseTransaction{
+		ForSaleItemID:     110,
+		BuyerUserID:       buyerUser.ID,
+		SellerUserID:      sellerUser.ID,
+		TypeID:            34,
+		QuantityPurchased: 100,
+		PricePerUnit:      10,
+		TotalPrice:        1000,
+		Status:            "completed",
+	}))
+	require.NoError(t, dbTx.Commit())
+
+	analyticsRepo := repositories.NewSalesAnalytics(db)
+	controller := controllers.NewAnalytics(&MockRouter{}, analyticsRepo)
+
+	testUserID := buyerUser.ID
+
+	t.Run("Get purchase metrics", func(t *testing.T) {
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("GET", "/v1/analytics/purchases?period=all", nil),
+			User:    &testUserID,
+		}
+
+		result, httpErr := controller.GetPurchaseMetrics(args)
+		require.Nil(t, httpErr)
+
+		metrics := result.(*models.PurchaseMetrics)
+		assert.Equal(t, int64(1000), metrics.TotalSpent)
+		assert.Equal(t, int64(1000), metrics.UnpricedSpend)
+		assert.Len(t, metrics.TopSellers, 1)
+	})
+
+	t.Run("Get sellers", func(t *testing.T) {
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("GET", "/v1/analytics/purchases/sellers?limit=5", nil),
+			User:    &testUserID,
+		}
+
+		result, httpErr := controller.GetSellerAnalytics(args)
+		require.Nil(t, httpErr)
+
+		sellers := result.([]models.SellerAnalytics)
+		require.Len(t, sellers, 1)
+		assert.Equal(t, sellerUser.ID, sellers[0].SellerUserID)
+	})
+
+	t.Run("Sellers see nothing on the buyer side", func(t *testing.T) {
+		sellerID := sellerUser.ID
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("GET", "/v1/analytics/purchases/top-items", nil),
+			User:    &sellerID,
+		}
+
+		result, httpErr := controller.GetTopItemsPurchased(args)
+		require.Nil(t, httpErr)
+		assert.Empty(t, result.([]models.ItemPurchaseData))
+	})
+}
+
+func TestAnalyticsController_InvalidRanges(t *testing.T) {
+	// Invalid ranges are rejected before the repository is queried
+	controller := controllers.NewAnalytics(&MockRouter{}, repositories.NewSalesAnalytics(nil))
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unparseable from", query: "from=yesterday"},
+		{name: "unparseable to", query: "from=2026-03-01&to=03/05/2026"},
+		{name: "from after to", query: "from=2026-03-05&to=2026-03-01"},
+		{name: "empty range", query: "from=2026-03-01&to=2026-03-01"},
+		{name: "unknown bucket", query: "period=7d&bucket=quarter"},
+		{name: "unknown timezone", query: "period=7d&tz=Mars/Olympus"},
+		{name: "server local timezone", query: "period=7d&tz=Local"},
+		{name: "unknown comparison", query: "period=7d&compare=lastyear"},
+		{name: "hourly buckets without a start", query: "period=all&bucket=hour"},
+		{name: "too many buckets", query: "from=2020-01-01&to=2026-01-01&bucket=hour"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/v1/analytics/sales?"+tt.query, nil)
+
+			testUserID := int64(1)
+			args := &web.HandlerArgs{
+				Request: req,
+				User:    &testUserID,
+			}
+
+			result, httpErr := controller.GetSalesMetrics(args)
+			assert.Nil(t, result)
+			require.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+
+			result, httpErr = controller.GetPurchaseMetrics(args)
+			assert.Nil(t, result)
+			require.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+		})
+	}
+}
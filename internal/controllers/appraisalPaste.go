@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"regexp"
+	"strings"
+)
+
+// appraisalLine is one item type found in a paste, with stacks of it summed
+type appraisalLine struct {
+	Name     string
+	Quantity int64
+}
+
+var (
+	// [Rifter, My Rifter] opens an EFT fit; [Empty High slot] marks an unfitted slot
+	eftHeaderPattern    = regexp.MustCompile(`^\[([^,\]]+),[^\]]*\]$`)
+	eftEmptySlotPattern = regexp.MustCompile(`(?i)^\[empty .+ slot\]$`)
+	// Hobgoblin I x5
+	suffixCountPattern = regexp.MustCompile(`^(.*\S)\s+x\s?([\d,.' ]*\d)$`)
+	// 5 Hobgoblin I, 5x Hobgoblin I
+	prefixCountPattern = regexp.MustCompile(`^(\d[\d,.']*)\s*x?\s+(\S.*)$`)
+	// Tritanium 1000
+	trailingCountPattern = regexp.MustCompile(`^(.*\D)\s+(\d[\d,.']*)$`)
+	// D-scan rows start with the type ID of the object
+	dscanIDPattern = regexp.MustCompile(`^\d+$`)
+)
+
+// parseAppraisalPaste reads the paste formats players copy out of the client:
+//   - inventory and contract windows: name and quantity in the first two tab-separated columns
+//   - D-scan: type ID, object name, type name and distance, one object per row
+//   - EFT fits: a [Ship, Name] header, then modules ("Module, Charge"), drones and cargo ("Item x5")
+//   - cargo scans and hand-written lists: "5 Item", "5x Item", "Item x5" or "Item 5"
+//
+// Quantities for the same item are summed across lines. Lines that cannot be read are
+// returned as unparsed so the caller can report them.
+func parseAppraisalPaste(text string) ([]*appraisalLine, []string) {
+	lines := []*appraisalLine{}
+	byName := map[string]*appraisalLine{}
+	unparsed := []string{}
+
+	add := func(name string, quantity int64) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		key := strings.ToLower(name)
+		if existing, ok := byName[key]; ok {
+			existing.Quantity += quantity
+			return
+		}
+		line := &appraisalLine{Name: name, Quantity: quantity}
+		byName[key] = line
+		lines = append(lines, line)
+	}
+
+	inFit := false
+	for _, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		trimmed := strings.TrimSpace(raw)
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.Contains(raw, "\t") {
+			columns := strings.Split(raw, "\t")
+			first := strings.TrimSpace(columns[0])
+
+			if len(columns) >= 3 && dscanIDPattern.MatchString(first) {
+				add(columns[2], 1)
+				continue
+			}
+
+			quantity := int64(1)
+			if len(columns) > 1 {
+				var ok bool
+				quantity, ok = parsePastedQuantity(columns[1])
+				if !ok {
+					unparsed = append(unparsed, trimmed)
+					continue
+				}
+			}
+			add(first, quantity)
+			continue
+		}
+
+		if eftEmptySlotPattern.MatchString(trimmed) {
+			continue
+		}
+		if match := eftHeaderPattern.FindStringSubmatch(trimmed); match != nil {
+			inFit = true
+			add(match[1], 1)
+			continue
+		}
+
+		name, quantity, ok := parseFreeformLine(trimmed)
+		if !ok {
+			unparsed = append(unparsed, trimmed)
+			continue
+		}
+		if inFit && quantity == 1 {
+			// Fitted modules may carry a loaded charge or an offline marker
+			name = strings.TrimSuffix(name, " /OFFLINE")
+			if i := strings.Index(name, ", "); i >= 0 {
+				name = name[:i]
+			}
+		}
+		add(name, quantity)
+	}
+
+	return lines, unparsed
+}
+
+// parseFreeformLine reads a single item with an optional count before or after its name
+func parseFreeformLine(line string) (string, int64, bool) {
+	if match := suffixCountPattern.FindStringSubmatch(line); match != nil {
+		quantity, ok := parsePastedQuantity(match[2])
+		return match[1], quantity, ok
+	}
+	if match := prefixCountPattern.FindStringSubmatch(line); match != nil {
+		quantity, ok := parsePastedQuantity(match[1])
+		return match[2], quantity, ok
+	}
+	if match := trailingCountPattern.FindStringSubmatch(line); match != nil {
+		quantity, ok := parsePastedQuantity(match[2])
+		return match[1], quantity, ok
+	}
+	return line, 1, true
+}
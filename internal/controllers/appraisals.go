@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	// appraisalRegionID prices appraisals in The Forge, where market_prices holds Jita orders
+	appraisalRegionID = 10000002
+
+	maxAppraisalLines = 1000
+)
+
+type AppraisalsRepository interface {
+	Create(ctx context.Context, appraisal *models.Appraisal) error
+	GetByCode(ctx context.Context, code string) (*models.Appraisal, error)
+}
+
+type AppraisalItemTypeRepository interface {
+	GetItemTypesByNames(ctx context.Context, names []string) (map[string]models.EveInventoryType, error)
+}
+
+type AppraisalPricesRepository interface {
+	GetPricesForTypes(ctx context.Context, typeIDs []int64, regionID int64) (map[int64]*models.MarketPrice, error)
+}
+
+// Appraisals values pasted item lists against our own market prices
+type Appraisals struct {
+	repository         AppraisalsRepository
+	itemTypeRepository AppraisalItemTypeRepository
+	pricesRepository   AppraisalPricesRepository
+}
+
+func NewAppraisals(router Routerer, repository AppraisalsRepository, itemTypeRepository AppraisalItemTypeRepository, pricesRepository AppraisalPricesRepository) *Appraisals {
+	controller := &Appraisals{
+		repository:         repository,
+		itemTypeRepository: itemTypeRepository,
+		pricesRepository:   pricesRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/appraisals", web.AuthAccessUser, controller.CreateAppraisal, "POST")
+	router.RegisterRestAPIRoute("/v1/appraisals/{code}", web.AuthAccessUser, controller.GetAppraisal, "GET")
+
+	return controller
+}
+
+type AppraisalRequest struct {
+	Items string `json:"items"`
+}
+
+// CreateAppraisal parses the pasted items, prices them and stores the result under a share code
+func (c *Appraisals) CreateAppraisal(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	var req AppraisalRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "failed to decode request body")}
+	}
+
+	lines, unparsed := parseAppraisalPaste(req.Items)
+	if len(lines) == 0 && len(unparsed) == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("items is required")}
+	}
+	if len(lines)+len(unparsed) > maxAppraisalLines {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("too many lines; at most %d item types can be appraised at once", maxAppraisalLines)}
+	}
+
+	names := make([]string, len(lines))
+	for i, line := range lines {
+		names[i] = line.Name
+	}
+
+	itemTypes, err := c.itemTypeRepository.GetItemTypesByNames(ctx, names)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to resolve item types")}
+	}
+
+	appraisal := &models.Appraisal{
+		UserID:     *args.User,
+		RegionID:   appraisalRegionID,
+		Items:      []*models.AppraisalItem{},
+		Unresolved: unparsed,
+	}
+
+	// Names can differ only in case, so merge lines that resolve to the same type
+	byType := map[int64]*models.AppraisalItem{}
+	typeIDs := []int64{}
+	for _, line := range lines {
+		itemType, ok := itemTypes[strings.ToLower(line.Name)]
+		if !ok {
+			appraisal.Unresolved = append(appraisal.Unresolved, line.Name)
+			continue
+		}
+
+		if item, ok := byType[itemType.TypeID]; ok {
+			item.Quantity += line.Quantity
+			continue
+		}
+
+		item := &models.AppraisalItem{
+			TypeID:   itemType.TypeID,
+			TypeName: itemType.TypeName,
+			Quantity: line.Quantity,
+			Volume:   itemType.UnitPackagedVolume(),
+		}
+		byType[itemType.TypeID] = item
+		typeIDs = append(typeIDs, itemType.TypeID)
+		appraisal.Items = append(appraisal.Items, item)
+	}
+
+	if len(appraisal.Items) == 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("no known items found in the paste")}
+	}
+
+	prices, err := c.pricesRepository.GetPricesForTypes(ctx, typeIDs, appraisalRegionID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get market prices")}
+	}
+	for _, item := range appraisal.Items {
+		if price, ok := prices[item.TypeID]; ok {
+			item.BuyPrice = price.BuyPrice
+			item.SellPrice = price.SellPrice
+		}
+	}
+
+	err = c.repository.Create(ctx, appraisal)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to save appraisal")}
+	}
+
+	summarizeAppraisal(appraisal)
+	return appraisal, nil
+}
+
+// GetAppraisal returns a stored appraisal; any user with the code can view it
+func (c *Appraisals) GetAppraisal(args *web.HandlerArgs) (any, *web.HttpError) {
+	appraisal, err := c.repository.GetByCode(args.Request.Context(), args.Params["code"])
+	if err != nil {
+		if errors.Is(err, repositories.ErrAppraisalNotFound) {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get appraisal")}
+	}
+
+	summarizeAppraisal(appraisal)
+	return appraisal, nil
+}
+
+// summarizeAppraisal fills in per-item and overall buy, sell, split and volume totals
+func summarizeAppraisal(appraisal *models.Appraisal) {
+	appraisal.TotalBuy = 0
+	appraisal.TotalSell = 0
+	appraisal.TotalSplit = 0
+	appraisal.TotalVolume = 0
+
+	for _, item := range appraisal.Items {
+		quantity := float64(item.Quantity)
+
+		item.SplitPrice = splitPrice(item.BuyPrice, item.SellPrice)
+		item.BuyTotal = quantity * valueOrZero(item.BuyPrice)
+		item.SellTotal = quantity * valueOrZero(item.SellPrice)
+		item.SplitTotal = quantity * valueOrZero(item.SplitPrice)
+		item.TotalVolume = quantity * item.Volume
+
+		appraisal.TotalBuy += item.BuyTotal
+		appraisal.TotalSell += item.SellTotal
+		appraisal.TotalSplit += item.SplitTotal
+		appraisal.TotalVolume += item.TotalVolume
+	}
+}
+
+// splitPrice is the midpoint of buy and sell, or whichever side exists
+func splitPrice(buy, sell *float64) *float64 {
+	switch {
+	case buy != nil && sell != nil:
+		split := (*buy + *sell) / 2
+		return &split
+	case buy != nil:
+		return buy
+	default:
+		return sell
+	}
+}
+
+func valueOrZero(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -0,0 +1,302 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAppraisalsRepository struct {
+	mock.Mock
+}
+
+func (m *MockAppraisalsRepository) Create(ctx context.Context, appraisal *models.Appraisal) error {
+	args := m.Called(ctx, appraisal)
+	return args.Error(0)
+}
+
+func (m *MockAppraisalsRepository) GetByCode(ctx context.Context, code string) (*models.Appraisal, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Appraisal), args.Error(1)
+}
+
+type MockAppraisalItemTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAppraisalItemTypeRepository) GetItemTypesByNames(ctx context.Context, names []string) (map[string]models.EveInventoryType, error) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[string]models.EveInventoryType), args.Error(1)
+}
+
+type MockAppraisalPricesRepository struct {
+	mock.Mock
+}
+
+func (m *MockAppraisalPricesRepository) GetPricesForTypes(ctx context.Context, typeIDs []int64, regionID int64) (map[int64]*models.MarketPrice, error) {
+	args := m.Called(ctx, typeIDs, regionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*models.MarketPrice), args.Error(1)
+}
+
+// rifterPackagedVolume is far below a Rifter's assembled 27,289 m³
+var rifterPackagedVolume = 2500.0
+
+// appraisalCatalog stands in for asset_item_types, keyed by lower-cased name
+var appraisalCatalog = map[string]models.EveInventoryType{
+	"tritanium":          {TypeID: 34, TypeName: "Tritanium", Volume: 0.01},
+	"pyerite":            {TypeID: 35, TypeName: "Pyerite", Volume: 0.01},
+	"rifter":             {TypeID: 587, TypeName: "Rifter", Volume: 27289, PackagedVolume: &rifterPackagedVolume},
+	"200mm autocannon i": {TypeID: 2889, TypeName: "200mm AutoCannon I", Volume: 5},
+	"damage control i":   {TypeID: 2046, TypeName: "Damage Control I", Volume: 5},
+	"hobgoblin i":        {TypeID: 2454, TypeName: "Hobgoblin I", Volume: 5},
+	"emp s":              {TypeID: 185, TypeName: "EMP S", Volume: 0.0025},
+}
+
+type appraisalMocks struct {
+	appraisals *MockAppraisalsRepository
+	itemTypes  *MockAppraisalItemTypeRepository
+	prices     *MockAppraisalPricesRepository
+}
+
+func setupAppraisalsController() (*controllers.Appraisals, appraisalMocks) {
+	mocks := appraisalMocks{
+		appraisals: new(MockAppraisalsRepository),
+		itemTypes:  new(MockAppraisalItemTypeRepository),
+		prices:     new(MockAppraisalPricesRepository),
+	}
+	controller := controllers.NewAppraisals(&MockRouter{}, mocks.appraisals, mocks.itemTypes, mocks.prices)
+	return controller, mocks
+}
+
+func appraisalArgs(t *testing.T, items string) *web.HandlerArgs {
+	body, err := json.Marshal(controllers.AppraisalRequest{Items: items})
+	require.NoError(t, err)
+
+	userID := int64(42)
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/appraisals", bytes.NewReader(body)),
+		User:    &userID,
+	}
+}
+
+func price(value float64) *float64 {
+	return &value
+}
+
+// expectAppraisal resolves names from the catalog, prices every type found and saves under code abc
+func expectAppraisal(mocks appraisalMocks, names []string, prices map[int64]*models.MarketPrice) {
+	mocks.itemTypes.On("GetItemTypesByNames", mock.Anything, names).Return(appraisalCatalog, nil)
+	mocks.prices.On("GetPricesForTypes", mock.Anything, mock.Anything, int64(10000002)).Return(prices, nil)
+	mocks.appraisals.On("Create", mock.Anything, mock.AnythingOfType("*models.Appraisal")).
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Appraisal).Code = "abc"
+		}).
+		Return(nil)
+}
+
+func itemsByName(appraisal *models.Appraisal) map[string]*models.AppraisalItem {
+	items := map[string]*models.AppraisalItem{}
+	for _, item := range appraisal.Items {
+		items[item.TypeName] = item
+	}
+	return items
+}
+
+func Test_AppraisalsController_InventoryAndFreeform(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	paste := "Tritanium\t1,000\tMineral\tMaterial\n" +
+		"Pyerite\t500\n" +
+		"tritanium 250\n" +
+		"Unobtainium x 3\n"
+	expectAppraisal(mocks, []string{"Tritanium", "Pyerite", "Unobtainium"}, map[int64]*models.MarketPrice{
+		34: {TypeID: 34, BuyPrice: price(4), SellPrice: price(6)},
+		35: {TypeID: 35, SellPrice: price(10)},
+	})
+
+	result, httpErr := controller.CreateAppraisal(appraisalArgs(t, paste))
+	require.Nil(t, httpErr)
+
+	appraisal := result.(*models.Appraisal)
+	assert.Equal(t, "abc", appraisal.Code)
+	assert.Equal(t, int64(42), appraisal.UserID)
+	assert.Equal(t, []string{"Unobtainium"}, appraisal.Unresolved)
+	require.Len(t, appraisal.Items, 2)
+
+	items := itemsByName(appraisal)
+	tritanium := items["Tritanium"]
+	assert.Equal(t, int64(1250), tritanium.Quantity)
+	assert.Equal(t, 5.0, *tritanium.SplitPrice)
+	assert.Equal(t, 5000.0, tritanium.BuyTotal)
+	assert.Equal(t, 7500.0, tritanium.SellTotal)
+	assert.Equal(t, 6250.0, tritanium.SplitTotal)
+	assert.InDelta(t, 12.5, tritanium.TotalVolume, 0.0001)
+
+	// Only a sell price: split falls back to it
+	pyerite := items["Pyerite"]
+	assert.Nil(t, pyerite.BuyPrice)
+	assert.Equal(t, 10.0, *pyerite.SplitPrice)
+	assert.Equal(t, 0.0, pyerite.BuyTotal)
+
+	assert.Equal(t, 5000.0, appraisal.TotalBuy)
+	assert.Equal(t, 12500.0, appraisal.TotalSell)
+	assert.Equal(t, 11250.0, appraisal.TotalSplit)
+	assert.InDelta(t, 17.5, appraisal.TotalVolume, 0.0001)
+}
+
+func Test_AppraisalsController_EFTFit(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	paste := "[Rifter, Tackle Rifter]\n" +
+		"Damage Control I\n" +
+		"[Empty Low slot]\n" +
+		"\n" +
+		"200mm AutoCannon I, EMP S\n" +
+		"200mm AutoCannon I, EMP S\n" +
+		"200mm AutoCannon I /OFFLINE\n" +
+		"\n" +
+		"Hobgoblin I x5\n" +
+		"\n" +
+		"EMP S x200\n"
+	expectAppraisal(mocks, []string{"Rifter", "Damage Control I", "200mm AutoCannon I", "Hobgoblin I", "EMP S"}, map[int64]*models.MarketPrice{})
+
+	result, httpErr := controller.CreateAppraisal(appraisalArgs(t, paste))
+	require.Nil(t, httpErr)
+
+	items := itemsByName(result.(*models.Appraisal))
+	assert.Equal(t, int64(1), items["Rifter"].Quantity)
+	assert.Equal(t, int64(1), items["Damage Control I"].Quantity)
+	assert.Equal(t, int64(3), items["200mm AutoCannon I"].Quantity)
+	assert.Equal(t, int64(5), items["Hobgoblin I"].Quantity)
+	assert.Equal(t, int64(200), items["EMP S"].Quantity)
+
+	// Ships are priced and hauled packaged
+	assert.Equal(t, 2500.0, items["Rifter"].Volume)
+
+	// Without market data every total is zero and prices are left empty
+	assert.Nil(t, items["Rifter"].SplitPrice)
+	assert.Equal(t, 0.0, result.(*models.Appraisal).TotalSplit)
+}
+
+func Test_AppraisalsController_DScanAndCargoScan(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	paste := "587\tSomeone's Rifter\tRifter\t1,204 km\n" +
+		"587\tAnother Rifter\tRifter\t-\n" +
+		"5 Hobgoblin I\n" +
+		"10x Hobgoblin I\n"
+	expectAppraisal(mocks, []string{"Rifter", "Hobgoblin I"}, map[int64]*models.MarketPrice{})
+
+	result, httpErr := controller.CreateAppraisal(appraisalArgs(t, paste))
+	require.Nil(t, httpErr)
+
+	items := itemsByName(result.(*models.Appraisal))
+	assert.Equal(t, int64(2), items["Rifter"].Quantity)
+	assert.Equal(t, int64(15), items["Hobgoblin I"].Quantity)
+}
+
+func Test_AppraisalsController_InvalidQuantityIsUnresolved(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	expectAppraisal(mocks, []string{"Tritanium"}, map[int64]*models.MarketPrice{})
+
+	result, httpErr := controller.CreateAppraisal(appraisalArgs(t, "Tritanium\t100\nPyerite\tlots"))
+	require.Nil(t, httpErr)
+
+	appraisal := result.(*models.Appraisal)
+	assert.Equal(t, []string{"Pyerite\tlots"}, appraisal.Unresolved)
+	require.Len(t, appraisal.Items, 1)
+}
+
+func Test_AppraisalsController_Validation(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	result, httpErr := controller.CreateAppraisal(appraisalArgs(t, "  \n\n"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	userID := int64(42)
+	result, httpErr = controller.CreateAppraisal(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/appraisals", bytes.NewReader([]byte("not json"))),
+		User:    &userID,
+	})
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	mocks.itemTypes.On("GetItemTypesByNames", mock.Anything, []string{"Unobtainium"}).Return(appraisalCatalog, nil)
+	result, httpErr = controller.CreateAppraisal(appraisalArgs(t, "Unobtainium 5"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mocks.appraisals.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_AppraisalsController_SaveError(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	mocks.itemTypes.On("GetItemTypesByNames", mock.Anything, mock.Anything).Return(appraisalCatalog, nil)
+	mocks.prices.On("GetPricesForTypes", mock.Anything, mock.Anything, mock.Anything).Return(map[int64]*models.MarketPrice{}, nil)
+	mocks.appraisals.On("Create", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	result, httpErr := controller.CreateAppraisal(appraisalArgs(t, "Tritanium 5"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
+func Test_AppraisalsController_GetAppraisal(t *testing.T) {
+	controller, mocks := setupAppraisalsController()
+
+	stored := &models.Appraisal{
+		Code: "abc",
+		Items: []*models.AppraisalItem{
+			{TypeID: 34, TypeName: "Tritanium", Quantity: 100, BuyPrice: price(4), SellPrice: price(6), Volume: 0.01},
+		},
+	}
+	mocks.appraisals.On("GetByCode", mock.Anything, "abc").Return(stored, nil)
+	mocks.appraisals.On("GetByCode", mock.Anything, "missing").Return(nil, repositories.ErrAppraisalNotFound)
+
+	userID := int64(7)
+	result, httpErr := controller.GetAppraisal(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/appraisals/abc", nil),
+		Params:  map[string]string{"code": "abc"},
+		User:    &userID,
+	})
+	require.Nil(t, httpErr)
+
+	appraisal := result.(*models.Appraisal)
+	assert.Equal(t, 400.0, appraisal.TotalBuy)
+	assert.Equal(t, 600.0, appraisal.TotalSell)
+	assert.Equal(t, 500.0, appraisal.TotalSplit)
+
+	result, httpErr = controller.GetAppraisal(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/appraisals/missing", nil),
+		Params:  map[string]string{"code": "missing"},
+		User:    &userID,
+	})
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
@@ -10,6 +11,7 @@ import (
 
 type AssetsRepository interface {
 	GetUserAssets(ctx context.Context, user int64) (*repositories.AssetsResponse, error)
+	GetFilteredUserAssets(ctx context.Context, user int64, filter *models.ItemTypeFilter) (*repositories.AssetsResponse, error)
 	GetUserAssetsSummary(ctx context.Context, user int64) (*repositories.AssetsSummary, error)
 }
 
@@ -28,8 +30,21 @@ func NewAssets(router Routerer, repository AssetsRepository) *Assets {
 	return controller
 }
 
+// GetUserAssets returns the user's assets, limited to item types matching the optional groupId,
+// categoryId, marketGroupId and metaGroupId filters
 func (c *Assets) GetUserAssets(args *web.HandlerArgs) (any, *web.HttpError) {
-	assets, err := c.repository.GetUserAssets(args.Request.Context(), *args.User)
+	filter, httpErr := parseItemTypeFilter(args.Request.URL.Query())
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var assets *repositories.AssetsResponse
+	var err error
+	if filter.IsEmpty() {
+		assets, err = c.repository.GetUserAssets(args.Request.Context(), *args.User)
+	} else {
+		assets, err = c.repository.GetFilteredUserAssets(args.Request.Context(), *args.User, filter)
+	}
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: 500,
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,14 @@ func (m *MockAssetsRepository) GetUserAssets(ctx context.Context, user int64) (*
 	return args.Get(0).(*repositories.AssetsResponse), args.Error(1)
 }
 
+func (m *MockAssetsRepository) GetFilteredUserAssets(ctx context.Context, user int64, filter *models.ItemTypeFilter) (*repositories.AssetsResponse, error) {
+	args := m.Called(ctx, user, filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.AssetsResponse), args.Error(1)
+}
+
 func (m *MockAssetsRepository) GetUserAssetsSummary(ctx context.Context, user int64) (*repositories.AssetsSummary, error) {
 	args := m.Called(ctx, user)
 	if args.Get(0) == nil {
@@ -220,3 +229,56 @@ func Test_AssetsController_GetUserAssetsSummary_ZeroValues(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func Test_AssetsController_GetUserAssets_Filtered(t *testing.T) {
+	mockRepo := new(MockAssetsRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewAssets(mockRouter, mockRepo)
+
+	userID := int64(42)
+	expectedFilter := &models.ItemTypeFilter{
+		GroupIDs:       []int64{18},
+		CategoryIDs:    []int64{4},
+		MarketGroupIDs: []int64{1857, 1858},
+	}
+	expectedResponse := &repositories.AssetsResponse{Structures: []*repositories.AssetStructure{}}
+
+	mockRepo.On("GetFilteredUserAssets", mock.Anything, userID, expectedFilter).Return(expectedResponse, nil)
+
+	req := httptest.NewRequest("GET", "/v1/assets/?groupId=18&categoryId=4&marketGroupId=1858,1857", nil)
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.GetUserAssets(args)
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, expectedResponse, result)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetUserAssets", mock.Anything, mock.Anything)
+}
+
+func Test_AssetsController_GetUserAssets_InvalidFilter(t *testing.T) {
+	mockRepo := new(MockAssetsRepository)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewAssets(mockRouter, mockRepo)
+
+	userID := int64(42)
+	req := httptest.NewRequest("GET", "/v1/assets/?categoryId=minerals", nil)
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.GetUserAssets(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	mockRepo.AssertExpectations(t)
+}
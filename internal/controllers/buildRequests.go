@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type BuildRequestsRepository interface {
+	Create(ctx context.Context, request *models.BuildRequest) error
+	GetByID(ctx context.Context, id int64) (*models.BuildRequest, error)
+	GetByUser(ctx context.Context, userID int64) ([]*models.BuildRequest, error)
+	UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error
+}
+
+type BuildRequestsController struct {
+	repository BuildRequestsRepository
+	permRepo   ContactPermissionsRepository
+}
+
+func NewBuildRequests(router Routerer, repository BuildRequestsRepository, permRepo ContactPermissionsRepository) *BuildRequestsController {
+	controller := &BuildRequestsController{
+		repository: repository,
+		permRepo:   permRepo,
+	}
+
+	router.RegisterRestAPIRoute("/v1/build-requests", web.AuthAccessUser, controller.GetRequests, "GET")
+	router.RegisterRestAPIRoute("/v1/build-requests", web.AuthAccessUser, controller.CreateRequest, "POST")
+	router.RegisterRestAPIRoute("/v1/build-requests/{id}/status", web.AuthAccessUser, controller.UpdateStatus, "POST")
+
+	log.Info("registering build requests controller", "endpoints", []string{
+		"/v1/build-requests GET",
+		"/v1/build-requests POST",
+		"/v1/build-requests/{id}/status POST",
+	})
+
+	return controller
+}
+
+// GetRequests returns the build requests the user sent or received
+func (c *BuildRequestsController) GetRequests(args *web.HandlerArgs) (any, *web.HttpError) {
+	requests, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get build requests"),
+		}
+	}
+
+	return requests, nil
+}
+
+// CreateRequest sends a build request to a user who granted build_request to the caller
+func (c *BuildRequestsController) CreateRequest(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	var req struct {
+		BuilderUserID int64   `json:"builderUserId"`
+		TypeID        int64   `json:"typeId"`
+		Quantity      int64   `json:"quantity"`
+		Notes         *string `json:"notes"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	if req.BuilderUserID == 0 || req.BuilderUserID == *args.User {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("builderUserId must be another user"),
+		}
+	}
+	if req.TypeID == 0 {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("typeId is required"),
+		}
+	}
+	if req.Quantity <= 0 {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("quantity must be positive"),
+		}
+	}
+
+	hasPermission, err := c.permRepo.CheckPermission(ctx, req.BuilderUserID, *args.User, models.ServiceBuildRequest)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to check permission"),
+		}
+	}
+	if !hasPermission {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusForbidden,
+			Error:      errors.New("you do not have permission to send build requests to this user"),
+		}
+	}
+
+	request := &models.BuildRequest{
+		RequesterUserID: *args.User,
+		BuilderUserID:   req.BuilderUserID,
+		TypeID:          req.TypeID,
+		Quantity:        req.Quantity,
+		Notes:           req.Notes,
+	}
+	if err := c.repository.Create(ctx, request); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to create build request"),
+		}
+	}
+
+	return request, nil
+}
+
+// UpdateStatus moves a build request along; see serviceRequestTransitionAllowed for who may do what
+func (c *BuildRequestsController) UpdateStatus(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("invalid build request ID"),
+		}
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	request, err := c.repository.GetByID(ctx, id)
+	if errors.Is(err, repositories.ErrBuildRequestNotFound) {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: err}
+	}
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get build request"),
+		}
+	}
+
+	// Requests between other users are reported as missing
+	if request.RequesterUserID != *args.User && request.BuilderUserID != *args.User {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: repositories.ErrBuildRequestNotFound}
+	}
+
+	if !serviceRequestTransitionAllowed(request.Status, req.Status, request.BuilderUserID == *args.User) {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Errorf("cannot move a %s build request to %q", request.Status, req.Status),
+		}
+	}
+
+	err = c.repository.UpdateStatus(ctx, id, request.Status, req.Status)
+	if errors.Is(err, repositories.ErrBuildRequestStatusChanged) {
+		return nil, &web.HttpError{StatusCode: http.StatusConflict, Error: err}
+	}
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to update build request"),
+		}
+	}
+
+	request.Status = req.Status
+	return request, nil
+}
+
+// serviceRequestTransitionAllowed reports whether a build or hauling request may move from one status
+// to another. The provider accepts or rejects a pending request and completes an accepted one; the
+// requester can cancel it until then.
+func serviceRequestTransitionAllowed(from, to string, byProvider bool) bool {
+	switch {
+	case byProvider && from == models.ServiceRequestPending:
+		return to == models.ServiceRequestAccepted || to == models.ServiceRequestRejected
+	case byProvider && from == models.ServiceRequestAccepted:
+		return to == models.ServiceRequestCompleted
+	case !byProvider && (from == models.ServiceRequestPending || from == models.ServiceRequestAccepted):
+		return to == models.ServiceRequestCancelled
+	}
+	return false
+}
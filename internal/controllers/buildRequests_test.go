@@ -0,0 +1,189 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBuildRequestsRepository struct {
+	mock.Mock
+}
+
+func (m *MockBuildRequestsRepository) Create(ctx context.Context, request *models.BuildRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockBuildRequestsRepository) GetByID(ctx context.Context, id int64) (*models.BuildRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BuildRequest), args.Error(1)
+}
+
+func (m *MockBuildRequestsRepository) GetByUser(ctx context.Context, userID int64) ([]*models.BuildRequest, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.BuildRequest), args.Error(1)
+}
+
+func (m *MockBuildRequestsRepository) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	args := m.Called(ctx, id, fromStatus, toStatus)
+	return args.Error(0)
+}
+
+func buildRequestArgs(userID int64, body any, params map[string]string) *web.HandlerArgs {
+	bodyBytes, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/v1/build-requests", bytes.NewReader(bodyBytes))
+	return &web.HandlerArgs{Request: req, User: &userID, Params: params}
+}
+
+func Test_BuildRequests_CreateRequest_Success(t *testing.T) {
+	repo := new(MockBuildRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	perms.On("CheckPermission", mock.Anything, int64(200), int64(100), models.ServiceBuildRequest).Return(true, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r *models.BuildRequest) bool {
+		return r.RequesterUserID == 100 && r.BuilderUserID == 200 && r.TypeID == 587 && r.Quantity == 5
+	})).Return(nil)
+
+	controller := controllers.NewBuildRequests(&MockRouter{}, repo, perms)
+	result, httpErr := controller.CreateRequest(buildRequestArgs(100, map[string]any{
+		"builderUserId": 200,
+		"typeId":        587,
+		"quantity":      5,
+	}, nil))
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(200), result.(*models.BuildRequest).BuilderUserID)
+	repo.AssertExpectations(t)
+	perms.AssertExpectations(t)
+}
+
+func Test_BuildRequests_CreateRequest_WithoutPermission(t *testing.T) {
+	repo := new(MockBuildRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	perms.On("CheckPermission", mock.Anything, int64(200), int64(100), models.ServiceBuildRequest).Return(false, nil)
+
+	controller := controllers.NewBuildRequests(&MockRouter{}, repo, perms)
+	_, httpErr := controller.CreateRequest(buildRequestArgs(100, map[string]any{
+		"builderUserId": 200,
+		"typeId":        587,
+		"quantity":      5,
+	}, nil))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_BuildRequests_CreateRequest_Validation(t *testing.T) {
+	cases := map[string]map[string]any{
+		"self":          {"builderUserId": 100, "typeId": 587, "quantity": 5},
+		"missing type":  {"builderUserId": 200, "quantity": 5},
+		"zero quantity": {"builderUserId": 200, "typeId": 587, "quantity": 0},
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockBuildRequestsRepository)
+			perms := new(MockContactPermissionsRepository)
+
+			controller := controllers.NewBuildRequests(&MockRouter{}, repo, perms)
+			_, httpErr := controller.CreateRequest(buildRequestArgs(100, body, nil))
+
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			perms.AssertNotCalled(t, "CheckPermission", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_BuildRequests_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   int64
+		from     string
+		to       string
+		expected int
+	}{
+		{"builder accepts", 200, models.ServiceRequestPending, models.ServiceRequestAccepted, 0},
+		{"builder completes", 200, models.ServiceRequestAccepted, models.ServiceRequestCompleted, 0},
+		{"requester cancels", 100, models.ServiceRequestAccepted, models.ServiceRequestCancelled, 0},
+		{"requester cannot accept", 100, models.ServiceRequestPending, models.ServiceRequestAccepted, 400},
+		{"builder cannot reopen", 200, models.ServiceRequestRejected, models.ServiceRequestAccepted, 400},
+		{"outsider", 300, models.ServiceRequestPending, models.ServiceRequestCancelled, 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockBuildRequestsRepository)
+			perms := new(MockContactPermissionsRepository)
+
+			repo.On("GetByID", mock.Anything, int64(7)).Return(&models.BuildRequest{
+				ID:              7,
+				RequesterUserID: 100,
+				BuilderUserID:   200,
+				Status:          tt.from,
+			}, nil)
+			repo.On("UpdateStatus", mock.Anything, int64(7), tt.from, tt.to).Return(nil)
+
+			controller := controllers.NewBuildRequests(&MockRouter{}, repo, perms)
+			result, httpErr := controller.UpdateStatus(buildRequestArgs(tt.userID, map[string]any{"status": tt.to}, map[string]string{"id": "7"}))
+
+			if tt.expected == 0 {
+				assert.Nil(t, httpErr)
+				assert.Equal(t, tt.to, result.(*models.BuildRequest).Status)
+				repo.AssertCalled(t, "UpdateStatus", mock.Anything, int64(7), tt.from, tt.to)
+			} else {
+				assert.NotNil(t, httpErr)
+				assert.Equal(t, tt.expected, httpErr.StatusCode)
+				repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func Test_BuildRequests_UpdateStatus_Conflict(t *testing.T) {
+	repo := new(MockBuildRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	repo.On("GetByID", mock.Anything, int64(7)).Return(&models.BuildRequest{
+		ID:              7,
+		RequesterUserID: 100,
+		BuilderUserID:   200,
+		Status:          models.ServiceRequestPending,
+	}, nil)
+	repo.On("UpdateStatus", mock.Anything, int64(7), models.ServiceRequestPending, models.ServiceRequestAccepted).
+		Return(repositories.ErrBuildRequestStatusChanged)
+
+	controller := controllers.NewBuildRequests(&MockRouter{}, repo, perms)
+	_, httpErr := controller.UpdateStatus(buildRequestArgs(200, map[string]any{"status": models.ServiceRequestAccepted}, map[string]string{"id": "7"}))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 409, httpErr.StatusCode)
+}
+
+func Test_BuildRequests_UpdateStatus_NotFound(t *testing.T) {
+	repo := new(MockBuildRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	repo.On("GetByID", mock.Anything, int64(7)).Return(nil, repositories.ErrBuildRequestNotFound)
+
+	controller := controllers.NewBuildRequests(&MockRouter{}, repo, perms)
+	_, httpErr := controller.UpdateStatus(buildRequestArgs(200, map[string]any{"status": models.ServiceRequestAccepted}, map[string]string{"id": "7"}))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/annymsMthd/industry-tool/internal/events"
 	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
@@ -16,20 +17,23 @@ type BuyOrdersRepository interface {
 	Create(ctx context.Context, order *models.BuyOrder) error
 	GetByID(ctx context.Context, id int64) (*models.BuyOrder, error)
 	GetByUser(ctx context.Context, userID int64) ([]*models.BuyOrder, error)
-	GetDemandForSeller(ctx context.Context, sellerUserID int64) ([]*models.BuyOrder, error)
+	GetActiveByBuyers(ctx context.Context, buyerUserIDs []int64) ([]*models.BuyOrder, error)
 	Update(ctx context.Context, order *models.BuyOrder) error
 	Delete(ctx context.Context, id int64, userID int64) error
 }
 
 type BuyOrdersController struct {
-	repository BuyOrdersRepository
-	permRepo   ContactPermissionsRepository
+	repository  BuyOrdersRepository
+	permRepo    ContactPermissionsRepository
+	groupFilter ContactGroupFilter
+	events      EventPublisher
 }
 
-func NewBuyOrders(router Routerer, repository BuyOrdersRepository, permRepo ContactPermissionsRepository) *BuyOrdersController {
+func NewBuyOrders(router Routerer, repository BuyOrdersRepository, permRepo ContactPermissionsRepository, groupFilter ContactGroupFilter) *BuyOrdersController {
 	controller := &BuyOrdersController{
-		repository: repository,
-		permRepo:   permRepo,
+		repository:  repository,
+		permRepo:    permRepo,
+		groupFilter: groupFilter,
 	}
 
 	router.RegisterRestAPIRoute("/v1/buy-orders", web.AuthAccessUser, controller.GetMyOrders, "GET")
@@ -49,6 +53,12 @@ func NewBuyOrders(router Routerer, repository BuyOrdersRepository, permRepo Cont
 	return controller
 }
 
+// WithEvents publishes buy order changes to the order owner
+func (c *BuyOrdersController) WithEvents(publisher EventPublisher) *BuyOrdersController {
+	c.events = publisher
+	return c
+}
+
 // GetMyOrders returns all buy orders for the authenticated user
 func (c *BuyOrdersController) GetMyOrders(args *web.HandlerArgs) (any, *web.HttpError) {
 	ctx := args.Request.Context()
@@ -70,10 +80,11 @@ func (c *BuyOrdersController) CreateOrder(args *web.HandlerArgs) (any, *web.Http
 	ctx := args.Request.Context()
 
 	var req struct {
-		TypeID          int64   `json:"typeId"`
-		QuantityDesired int64   `json:"quantityDesired"`
-		MaxPricePerUnit int64   `json:"maxPricePerUnit"`
-		Notes           *string `json:"notes"`
+		TypeID          int64               `json:"typeId"`
+		QuantityDesired int64               `json:"quantityDesired"`
+		MaxPricePerUnit int64               `json:"maxPricePerUnit"`
+		PricingRule     *models.PricingRule `json:"pricingRule"`
+		Notes           *string             `json:"notes"`
 	}
 
 	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
@@ -106,11 +117,19 @@ func (c *BuyOrdersController) CreateOrder(args *web.HandlerArgs) (any, *web.Http
 		}
 	}
 
+	if err := validatePricingRule(req.PricingRule, req.MaxPricePerUnit, "maxPricePerUnit"); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      err,
+		}
+	}
+
 	order := &models.BuyOrder{
 		BuyerUserID:     *args.User,
 		TypeID:          req.TypeID,
 		QuantityDesired: req.QuantityDesired,
 		MaxPricePerUnit: req.MaxPricePerUnit,
+		PricingRule:     req.PricingRule,
 		Notes:           req.Notes,
 		IsActive:        true,
 	}
@@ -125,6 +144,8 @@ func (c *BuyOrdersController) CreateOrder(args *web.HandlerArgs) (any, *web.Http
 
 	log.Info("buy order created", "orderId", order.ID, "userId", *args.User, "typeId", req.TypeID)
 
+	publishEvent(ctx, c.events, events.BuyOrderCreated, *args.User, order)
+
 	return order, nil
 }
 
@@ -160,10 +181,11 @@ func (c *BuyOrdersController) UpdateOrder(args *web.HandlerArgs) (any, *web.Http
 	}
 
 	var req struct {
-		QuantityDesired int64   `json:"quantityDesired"`
-		MaxPricePerUnit int64   `json:"maxPricePerUnit"`
-		Notes           *string `json:"notes"`
-		IsActive        *bool   `json:"isActive"`
+		QuantityDesired int64             `json:"quantityDesired"`
+		MaxPricePerUnit int64             `json:"maxPricePerUnit"`
+		PricingRule     pricingRuleUpdate `json:"pricingRule"`
+		Notes           *string           `json:"notes"`
+		IsActive        *bool             `json:"isActive"`
 	}
 
 	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
@@ -189,9 +211,17 @@ func (c *BuyOrdersController) UpdateOrder(args *web.HandlerArgs) (any, *web.Http
 		}
 	}
 
+	if err := validatePricingRule(req.PricingRule.apply(order.PricingRule), req.MaxPricePerUnit, "maxPricePerUnit"); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      err,
+		}
+	}
+
 	// Update fields
 	order.QuantityDesired = req.QuantityDesired
 	order.MaxPricePerUnit = req.MaxPricePerUnit
+	order.PricingRule = req.PricingRule.apply(order.PricingRule)
 	order.Notes = req.Notes
 	if req.IsActive != nil {
 		order.IsActive = *req.IsActive
@@ -207,6 +237,8 @@ func (c *BuyOrdersController) UpdateOrder(args *web.HandlerArgs) (any, *web.Http
 
 	log.Info("buy order updated", "orderId", id, "userId", *args.User)
 
+	publishEvent(ctx, c.events, events.BuyOrderUpdated, *args.User, order)
+
 	return order, nil
 }
 
@@ -233,14 +265,31 @@ func (c *BuyOrdersController) DeleteOrder(args *web.HandlerArgs) (any, *web.Http
 
 	log.Info("buy order deleted", "orderId", id, "userId", *args.User)
 
+	publishEvent(ctx, c.events, events.BuyOrderDeleted, *args.User, map[string]int64{"id": id})
+
 	return map[string]string{"status": "deleted"}, nil
 }
 
-// GetDemand returns active buy orders from contacts who have granted permission
+// GetDemand returns active buy orders from users who granted buy order visibility,
+// optionally limited to a contact group with ?groupId=
 func (c *BuyOrdersController) GetDemand(args *web.HandlerArgs) (any, *web.HttpError) {
 	ctx := args.Request.Context()
 
-	orders, err := c.repository.GetDemandForSeller(ctx, *args.User)
+	buyerUserIDs, err := c.permRepo.GetUserPermissionsForService(ctx, *args.User, models.ServiceBuyOrderBrowse)
+	if err != nil {
+		log.Error("failed to get buy order permissions", "error", err.Error())
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      err,
+		}
+	}
+
+	buyerUserIDs, httpErr := applyContactGroupFilter(args, c.groupFilter, *args.User, buyerUserIDs)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	orders, err := c.repository.GetActiveByBuyers(ctx, buyerUserIDs)
 	if err != nil {
 		log.Error("failed to get demand", "error", err.Error())
 		return nil, &web.HttpError{
@@ -34,7 +34,7 @@ func Test_BuyOrders_CreateOrder_Success(t *testing.T) {
 	}
 	assert.NoError(t, itemTypesRepo.UpsertItemTypes(context.Background(), itemTypes))
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	reqBody := map[string]interface{}{
 		"typeId":          70,
@@ -70,7 +70,7 @@ func Test_BuyOrders_CreateOrder_InvalidQuantity(t *testing.T) {
 	buyOrdersRepo := repositories.NewBuyOrders(db)
 	permRepo := &MockContactPermissionsRepository{}
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	reqBody := map[string]interface{}{
 		"typeId":          70,
@@ -92,6 +92,65 @@ func Test_BuyOrders_CreateOrder_InvalidQuantity(t *testing.T) {
 	assert.Contains(t, httpErr.Error.Error(), "quantityDesired must be positive")
 }
 
+func Test_BuyOrders_CreateOrder_WithPricingRule(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	userID := int64(6020)
+
+	userRepo := repositories.NewUserRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	marketPricesRepo := repositories.NewMarketPrices(db)
+	buyOrdersRepo := repositories.NewBuyOrders(db)
+	permRepo := &MockContactPermissionsRepository{}
+
+	user := &repositories.User{ID: userID, Name: "Test User"}
+	assert.NoError(t, userRepo.Add(context.Background(), user))
+
+	itemTypes := []models.EveInventoryType{
+		{TypeID: 70, TypeName: "Tritanium", Volume: 0.01},
+	}
+	assert.NoError(t, itemTypesRepo.UpsertItemTypes(context.Background(), itemTypes))
+
+	buy, sell := 4.0, 6.0
+	assert.NoError(t, marketPricesRepo.UpsertPrices(context.Background(), []models.MarketPrice{
+		{TypeID: 70, RegionID: 10000002, BuyPrice: &buy, SellPrice: &sell},
+	}))
+
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
+
+	reqBody := map[string]interface{}{
+		"typeId":          70,
+		"quantityDesired": 100000,
+		"maxPricePerUnit": 3,
+		"pricingRule": map[string]interface{}{
+			"basis":   "buy",
+			"percent": 150,
+		},
+	}
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest("POST", "/v1/buy-orders", bytes.NewReader(body))
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+	}
+
+	result, httpErr := controller.CreateOrder(args)
+	assert.Nil(t, httpErr)
+
+	order := result.(*models.BuyOrder)
+	assert.Equal(t, int64(6), order.MaxPricePerUnit)
+	assert.Equal(t, int64(3), order.FixedMaxPricePerUnit)
+
+	fetched, err := buyOrdersRepo.GetByID(context.Background(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), fetched.MaxPricePerUnit)
+	assert.Equal(t, int64(3), fetched.FixedMaxPricePerUnit)
+	assert.Equal(t, "buy", fetched.PricingRule.Basis)
+	assert.Equal(t, 150.0, fetched.PricingRule.Percent)
+}
+
 func Test_BuyOrders_GetMyOrders(t *testing.T) {
 	db, err := setupDatabase()
 	assert.NoError(t, err)
@@ -124,7 +183,7 @@ func Test_BuyOrders_GetMyOrders(t *testing.T) {
 		assert.NoError(t, buyOrdersRepo.Create(context.Background(), order))
 	}
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	req := httptest.NewRequest("GET", "/v1/buy-orders", nil)
 	args := &web.HandlerArgs{
@@ -170,7 +229,7 @@ func Test_BuyOrders_UpdateOrder_Success(t *testing.T) {
 	}
 	assert.NoError(t, buyOrdersRepo.Create(context.Background(), order))
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	// Update order
 	reqBody := map[string]interface{}{
@@ -195,6 +254,61 @@ func Test_BuyOrders_UpdateOrder_Success(t *testing.T) {
 	assert.Equal(t, int64(25), updated.MaxPricePerUnit)
 }
 
+func Test_BuyOrders_UpdateOrder_KeepsPricingRuleUnlessCleared(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	userID := int64(6035)
+
+	userRepo := repositories.NewUserRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	buyOrdersRepo := repositories.NewBuyOrders(db)
+
+	assert.NoError(t, userRepo.Add(context.Background(), &repositories.User{ID: userID, Name: "Test User"}))
+	assert.NoError(t, itemTypesRepo.UpsertItemTypes(context.Background(), []models.EveInventoryType{
+		{TypeID: 74, TypeName: "Nocxium", Volume: 0.01},
+	}))
+
+	order := &models.BuyOrder{
+		BuyerUserID:     userID,
+		TypeID:          74,
+		QuantityDesired: 1000,
+		MaxPricePerUnit: 500,
+		PricingRule:     &models.PricingRule{Basis: "buy", Percent: 95},
+		IsActive:        true,
+	}
+	assert.NoError(t, buyOrdersRepo.Create(context.Background(), order))
+
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, &MockContactPermissionsRepository{}, repositories.NewContactGroups(db))
+	update := func(body string) *models.BuyOrder {
+		req := httptest.NewRequest("PUT", "/v1/buy-orders/"+strconv.FormatInt(order.ID, 10), bytes.NewReader([]byte(body)))
+		result, httpErr := controller.UpdateOrder(&web.HandlerArgs{
+			Request: req,
+			User:    &userID,
+			Params:  map[string]string{"id": strconv.FormatInt(order.ID, 10)},
+		})
+		assert.Nil(t, httpErr)
+		return result.(*models.BuyOrder)
+	}
+
+	// An edit that doesn't mention the rule keeps it
+	updated := update(`{"quantityDesired": 2000, "maxPricePerUnit": 500}`)
+	assert.Equal(t, int64(2000), updated.QuantityDesired)
+	assert.NotNil(t, updated.PricingRule)
+
+	fetched, err := buyOrdersRepo.GetByID(context.Background(), order.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "buy", fetched.PricingRule.Basis)
+
+	// null clears it
+	updated = update(`{"quantityDesired": 2000, "maxPricePerUnit": 500, "pricingRule": null}`)
+	assert.Nil(t, updated.PricingRule)
+
+	fetched, err = buyOrdersRepo.GetByID(context.Background(), order.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, fetched.PricingRule)
+}
+
 func Test_BuyOrders_UpdateOrder_NotOwner(t *testing.T) {
 	db, err := setupDatabase()
 	assert.NoError(t, err)
@@ -229,7 +343,7 @@ func Test_BuyOrders_UpdateOrder_NotOwner(t *testing.T) {
 	}
 	assert.NoError(t, buyOrdersRepo.Create(context.Background(), order))
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	// Try to update as different user
 	reqBody := map[string]interface{}{
@@ -281,7 +395,7 @@ func Test_BuyOrders_DeleteOrder_Success(t *testing.T) {
 	}
 	assert.NoError(t, buyOrdersRepo.Create(context.Background(), order))
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	req := httptest.NewRequest("DELETE", "/v1/buy-orders/"+strconv.FormatInt(order.ID, 10), nil)
 	args := &web.HandlerArgs{
@@ -341,8 +455,8 @@ func Test_BuyOrders_GetDemand(t *testing.T) {
 		ContactID:       contact.ID,
 		GrantingUserID:  buyerID,
 		ReceivingUserID: sellerID,
-		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		ServiceType:     "buy_order_browse",
+		CanAccess:       boolPtr(true),
 	}
 	assert.NoError(t, permRepo.Upsert(context.Background(), perm))
 
@@ -364,7 +478,7 @@ func Test_BuyOrders_GetDemand(t *testing.T) {
 		assert.NoError(t, buyOrdersRepo.Create(context.Background(), order))
 	}
 
-	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo)
+	controller := controllers.NewBuyOrders(&MockRouter{}, buyOrdersRepo, permRepo, repositories.NewContactGroups(db))
 
 	req := httptest.NewRequest("GET", "/v1/buy-orders/demand", nil)
 	args := &web.HandlerArgs{
@@ -23,11 +23,13 @@ type CharacterRepository interface {
 
 type Characters struct {
 	repository CharacterRepository
+	invites    ContactInviteActivator
 }
 
-func NewCharacters(router Routerer, repository CharacterRepository) *Characters {
+func NewCharacters(router Routerer, repository CharacterRepository, invites ContactInviteActivator) *Characters {
 	controller := &Characters{
 		repository: repository,
+		invites:    invites,
 	}
 
 	router.RegisterRestAPIRoute("/v1/characters/{id}", web.AuthAccessUser, controller.GetCharacter, "GET")
@@ -103,5 +105,7 @@ func (c *Characters) AddCharacter(args *web.HandlerArgs) (interface{}, *web.Http
 		}
 	}
 
+	activateContactInvites(args.Request.Context(), c.invites, character.ID, character.UserID)
+
 	return nil, nil
 }
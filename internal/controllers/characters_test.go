@@ -45,7 +45,7 @@ func Test_CharactersController_GetAllCharacters_Success(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 	expectedChars := []*repositories.Character{
@@ -78,7 +78,7 @@ func Test_CharactersController_GetAllCharacters_RepositoryError(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 	mockRepo.On("GetAll", mock.Anything, userID).Return(nil, errors.New("database error"))
@@ -102,7 +102,7 @@ func Test_CharactersController_GetCharacter_Success(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 	expectedChar := &repositories.Character{
@@ -135,7 +135,7 @@ func Test_CharactersController_GetCharacter_MissingID(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 
@@ -157,7 +157,7 @@ func Test_CharactersController_GetCharacter_NotFound(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 	mockRepo.On("Get", mock.Anything, "99999").Return(nil, nil)
@@ -180,9 +180,10 @@ func Test_CharactersController_GetCharacter_NotFound(t *testing.T) {
 
 func Test_CharactersController_AddCharacter_Success(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
+	mockInvites := new(MockContactInviteActivator)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, mockInvites)
 
 	userID := int64(42)
 	character := repositories.Character{
@@ -193,6 +194,7 @@ func Test_CharactersController_AddCharacter_Success(t *testing.T) {
 	mockRepo.On("Add", mock.Anything, mock.MatchedBy(func(c *repositories.Character) bool {
 		return c.UserID == userID && c.ID == 12345
 	})).Return(nil)
+	mockInvites.On("ActivateForCharacter", mock.Anything, int64(12345), userID).Return(int64(1), nil)
 
 	body, _ := json.Marshal(character)
 	req := httptest.NewRequest("POST", "/v1/characters/", bytes.NewReader(body))
@@ -207,13 +209,14 @@ func Test_CharactersController_AddCharacter_Success(t *testing.T) {
 	assert.Nil(t, result)
 
 	mockRepo.AssertExpectations(t)
+	mockInvites.AssertExpectations(t)
 }
 
 func Test_CharactersController_AddCharacter_InvalidJSON(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 
@@ -234,7 +237,7 @@ func Test_CharactersController_AddCharacter_RepositoryError(t *testing.T) {
 	mockRepo := new(MockCharacterRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewCharacters(mockRouter, mockRepo)
+	controller := controllers.NewCharacters(mockRouter, mockRepo, &MockContactInviteActivator{})
 
 	userID := int64(42)
 	character := repositories.Character{
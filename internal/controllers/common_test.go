@@ -95,3 +95,56 @@ func (m *MockContactPermissionsRepository) CheckPermission(ctx context.Context,
 	args := m.Called(ctx, grantingUserID, receivingUserID, serviceType)
 	return args.Bool(0), args.Error(1)
 }
+
+func (m *MockContactPermissionsRepository) SetScope(ctx context.Context, perm *models.ContactPermission) error {
+	args := m.Called(ctx, perm)
+	return args.Error(0)
+}
+
+func (m *MockContactPermissionsRepository) GetScope(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) ([]int64, []int64, error) {
+	args := m.Called(ctx, grantingUserID, receivingUserID, serviceType)
+	var stationIDs, ownerIDs []int64
+	if args.Get(0) != nil {
+		stationIDs = args.Get(0).([]int64)
+	}
+	if args.Get(1) != nil {
+		ownerIDs = args.Get(1).([]int64)
+	}
+	return stationIDs, ownerIDs, args.Error(2)
+}
+
+// MockReputationRepository is a mock implementation of ReputationRepository
+type MockReputationRepository struct {
+	mock.Mock
+}
+
+func (m *MockReputationRepository) GetForUsers(ctx context.Context, userIDs []int64) (map[int64]*models.Reputation, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]*models.Reputation), args.Error(1)
+}
+
+// MockContactGroupFilter is a mock implementation of ContactGroupFilter
+type MockContactGroupFilter struct {
+	mock.Mock
+}
+
+func (m *MockContactGroupFilter) GetMemberUserIDs(ctx context.Context, groupID, userID int64) ([]int64, error) {
+	args := m.Called(ctx, groupID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+// MockContactInviteActivator is a mock implementation of ContactInviteActivator
+type MockContactInviteActivator struct {
+	mock.Mock
+}
+
+func (m *MockContactInviteActivator) ActivateForCharacter(ctx context.Context, characterID, userID int64) (int64, error) {
+	args := m.Called(ctx, characterID, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,311 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type ContactLookupRepository interface {
+	GetByID(ctx context.Context, contactID int64, userID int64) (*models.Contact, error)
+}
+
+type ContactAssetsRepository interface {
+	GetUserAssets(ctx context.Context, user int64) (*repositories.AssetsResponse, error)
+	GetStockpileDeficits(ctx context.Context, user int64) (*repositories.StockpilesResponse, error)
+}
+
+// ContactAssets serves read-only views of a contact's assets and stockpiles
+type ContactAssets struct {
+	contactsRepository    ContactLookupRepository
+	permissionsRepository ContactPermissionsRepository
+	assetsRepository      ContactAssetsRepository
+}
+
+func NewContactAssets(router Routerer, contactsRepository ContactLookupRepository, permissionsRepository ContactPermissionsRepository, assetsRepository ContactAssetsRepository) *ContactAssets {
+	controller := &ContactAssets{
+		contactsRepository:    contactsRepository,
+		permissionsRepository: permissionsRepository,
+		assetsRepository:      assetsRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/contacts/{id}/assets", web.AuthAccessUser, controller.GetAssets, "GET")
+	router.RegisterRestAPIRoute("/v1/contacts/{id}/stockpiles/deficits", web.AuthAccessUser, controller.GetStockpileDeficits, "GET")
+
+	return controller
+}
+
+// GetAssets returns the contact's assets if they granted asset_view, limited to the
+// stations and owners of the grant. Optional stationId and ownerId query parameters
+// narrow the result further.
+func (c *ContactAssets) GetAssets(args *web.HandlerArgs) (any, *web.HttpError) {
+	grantingUserID, httpErr := c.authorize(args, models.ServiceAssetView)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	stations, owners, httpErr := c.resolveScope(args, grantingUserID, models.ServiceAssetView)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	assets, err := c.assetsRepository.GetUserAssets(args.Request.Context(), grantingUserID)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact assets"),
+		}
+	}
+
+	return filterAssets(assets, stations, owners), nil
+}
+
+// GetStockpileDeficits returns the contact's stockpile deficits if they granted stockpile_view,
+// limited like GetAssets.
+func (c *ContactAssets) GetStockpileDeficits(args *web.HandlerArgs) (any, *web.HttpError) {
+	grantingUserID, httpErr := c.authorize(args, models.ServiceStockpileView)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	stations, owners, httpErr := c.resolveScope(args, grantingUserID, models.ServiceStockpileView)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	stockpiles, err := c.assetsRepository.GetStockpileDeficits(args.Request.Context(), grantingUserID)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact stockpile deficits"),
+		}
+	}
+
+	return filterStockpileItems(stockpiles, stations, owners), nil
+}
+
+// authorize resolves the other side of an accepted contact and checks they granted serviceType
+func (c *ContactAssets) authorize(args *web.HandlerArgs, serviceType string) (int64, *web.HttpError) {
+	contactID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return 0, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid contact ID"),
+		}
+	}
+
+	contact, err := c.contactsRepository.GetByID(args.Request.Context(), contactID, *args.User)
+	if err != nil {
+		if errors.Is(err, repositories.ErrContactNotFound) {
+			return 0, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return 0, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact"),
+		}
+	}
+
+	if contact.Status != "accepted" {
+		return 0, &web.HttpError{
+			StatusCode: 404,
+			Error:      errors.New("contact not found"),
+		}
+	}
+
+	grantingUserID := contactOtherUserID(contact, *args.User)
+
+	hasPermission, err := c.permissionsRepository.CheckPermission(args.Request.Context(), grantingUserID, *args.User, serviceType)
+	if err != nil {
+		return 0, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to check permission"),
+		}
+	}
+	if !hasPermission {
+		return 0, &web.HttpError{
+			StatusCode: 403,
+			Error:      errors.Errorf("contact has not granted %s", serviceType),
+		}
+	}
+
+	return grantingUserID, nil
+}
+
+// resolveScope combines the scope stored with the grant and the viewer's own filters. The
+// viewer can only narrow what the granter shared, never widen it
+func (c *ContactAssets) resolveScope(args *web.HandlerArgs, grantingUserID int64, serviceType string) (map[int64]bool, map[int64]bool, *web.HttpError) {
+	stations, owners, httpErr := parseAssetScope(args)
+	if httpErr != nil {
+		return nil, nil, httpErr
+	}
+
+	grantedStations, grantedOwners, err := c.permissionsRepository.GetScope(args.Request.Context(), grantingUserID, *args.User, serviceType)
+	if err != nil {
+		return nil, nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get permission scope"),
+		}
+	}
+
+	return intersectScope(idSet(grantedStations), stations), intersectScope(idSet(grantedOwners), owners), nil
+}
+
+// parseAssetScope reads the optional stationId and ownerId filters. Each accepts
+// repeated parameters or a comma-separated list; a nil set means unrestricted.
+func parseAssetScope(args *web.HandlerArgs) (map[int64]bool, map[int64]bool, *web.HttpError) {
+	query := args.Request.URL.Query()
+
+	stations, err := parseIDSet(query["stationId"])
+	if err != nil {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid stationId"),
+		}
+	}
+
+	owners, err := parseIDSet(query["ownerId"])
+	if err != nil {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid ownerId"),
+		}
+	}
+
+	return stations, owners, nil
+}
+
+func parseIDSet(values []string) (map[int64]bool, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	ids := map[int64]bool{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+			ids[id] = true
+		}
+	}
+
+	return ids, nil
+}
+
+func idSet(ids []int64) map[int64]bool {
+	if ids == nil {
+		return nil
+	}
+
+	set := map[int64]bool{}
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+// intersectScope keeps the requested IDs the grant allows; nil on either side is unrestricted
+func intersectScope(granted, requested map[int64]bool) map[int64]bool {
+	if granted == nil {
+		return requested
+	}
+	if requested == nil {
+		return granted
+	}
+
+	scope := map[int64]bool{}
+	for id := range requested {
+		if granted[id] {
+			scope[id] = true
+		}
+	}
+	return scope
+}
+
+func inScope(scope map[int64]bool, id int64) bool {
+	return scope == nil || scope[id]
+}
+
+func filterAssetList(assets []*repositories.Asset, owners map[int64]bool) []*repositories.Asset {
+	if owners == nil {
+		return assets
+	}
+
+	filtered := []*repositories.Asset{}
+	for _, asset := range assets {
+		if owners[asset.OwnerID] {
+			filtered = append(filtered, asset)
+		}
+	}
+	return filtered
+}
+
+// filterAssets keeps structures in stations and assets held by owners, dropping anything left empty
+func filterAssets(assets *repositories.AssetsResponse, stations, owners map[int64]bool) *repositories.AssetsResponse {
+	if stations == nil && owners == nil {
+		return assets
+	}
+
+	filtered := &repositories.AssetsResponse{Structures: []*repositories.AssetStructure{}}
+	for _, structure := range assets.Structures {
+		if !inScope(stations, structure.ID) {
+			continue
+		}
+
+		scoped := *structure
+		scoped.HangarAssets = filterAssetList(structure.HangarAssets, owners)
+		scoped.Deliveries = filterAssetList(structure.Deliveries, owners)
+		scoped.AssetSafety = filterAssetList(structure.AssetSafety, owners)
+
+		scoped.HangarContainers = []*repositories.AssetContainer{}
+		for _, container := range structure.HangarContainers {
+			if inScope(owners, container.OwnerID) {
+				scoped.HangarContainers = append(scoped.HangarContainers, container)
+			}
+		}
+
+		scoped.CorporationHangers = []*repositories.CorporationHanger{}
+		for _, hanger := range structure.CorporationHangers {
+			if inScope(owners, hanger.CorporationID) {
+				scoped.CorporationHangers = append(scoped.CorporationHangers, hanger)
+			}
+		}
+
+		if len(scoped.HangarAssets) == 0 &&
+			len(scoped.Deliveries) == 0 &&
+			len(scoped.AssetSafety) == 0 &&
+			len(scoped.HangarContainers) == 0 &&
+			len(scoped.CorporationHangers) == 0 {
+			continue
+		}
+
+		filtered.Structures = append(filtered.Structures, &scoped)
+	}
+
+	return filtered
+}
+
+func filterStockpileItems(stockpiles *repositories.StockpilesResponse, stations, owners map[int64]bool) *repositories.StockpilesResponse {
+	if stations == nil && owners == nil {
+		return stockpiles
+	}
+
+	filtered := &repositories.StockpilesResponse{Items: []*repositories.StockpileItem{}}
+	for _, item := range stockpiles.Items {
+		if inScope(stations, item.LocationID) && inScope(owners, item.OwnerID) {
+			filtered.Items = append(filtered.Items, item)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,335 @@
+package controllers_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockContactLookupRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactLookupRepository) GetByID(ctx context.Context, contactID int64, userID int64) (*models.Contact, error) {
+	args := m.Called(ctx, contactID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Contact), args.Error(1)
+}
+
+type MockContactAssetsRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactAssetsRepository) GetUserAssets(ctx context.Context, user int64) (*repositories.AssetsResponse, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.AssetsResponse), args.Error(1)
+}
+
+func (m *MockContactAssetsRepository) GetStockpileDeficits(ctx context.Context, user int64) (*repositories.StockpilesResponse, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.StockpilesResponse), args.Error(1)
+}
+
+func contactAssetsArgs(url string, userID *int64) *web.HandlerArgs {
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", url, nil),
+		User:    userID,
+		Params:  map[string]string{"id": "7"},
+	}
+}
+
+func Test_ContactAssets_GetAssets_Success(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: partnerID,
+		RecipientUserID: viewerID,
+		Status:          "accepted",
+	}, nil)
+	mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "asset_view").Return(true, nil)
+	mockPerms.On("GetScope", mock.Anything, partnerID, viewerID, "asset_view").Return(nil, nil, nil)
+
+	assets := &repositories.AssetsResponse{
+		Structures: []*repositories.AssetStructure{
+			{ID: 60003760, Name: "Jita IV - Moon 4", HangarAssets: []*repositories.Asset{{Name: "Tritanium", OwnerID: 2001}}},
+		},
+	}
+	mockAssets.On("GetUserAssets", mock.Anything, partnerID).Return(assets, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	result, httpErr := controller.GetAssets(contactAssetsArgs("/v1/contacts/7/assets", &viewerID))
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, assets, result)
+	mockContacts.AssertExpectations(t)
+	mockPerms.AssertExpectations(t)
+	mockAssets.AssertExpectations(t)
+}
+
+func Test_ContactAssets_GetAssets_FiltersByStationAndOwner(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: viewerID,
+		RecipientUserID: partnerID,
+		Status:          "accepted",
+	}, nil)
+	mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "asset_view").Return(true, nil)
+	mockPerms.On("GetScope", mock.Anything, partnerID, viewerID, "asset_view").Return(nil, nil, nil)
+
+	mockAssets.On("GetUserAssets", mock.Anything, partnerID).Return(&repositories.AssetsResponse{
+		Structures: []*repositories.AssetStructure{
+			{
+				ID:           60003760,
+				HangarAssets: []*repositories.Asset{{Name: "Tritanium", OwnerID: 2001}, {Name: "Pyerite", OwnerID: 2002}},
+				CorporationHangers: []*repositories.CorporationHanger{
+					{ID: 1, CorporationID: 98000001},
+				},
+			},
+			{
+				ID:           60008494,
+				HangarAssets: []*repositories.Asset{{Name: "Mexallon", OwnerID: 2001}},
+			},
+		},
+	}, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	result, httpErr := controller.GetAssets(contactAssetsArgs("/v1/contacts/7/assets?stationId=60003760&ownerId=2001", &viewerID))
+
+	assert.Nil(t, httpErr)
+	filtered := result.(*repositories.AssetsResponse)
+	assert.Len(t, filtered.Structures, 1)
+	assert.Equal(t, int64(60003760), filtered.Structures[0].ID)
+	assert.Len(t, filtered.Structures[0].HangarAssets, 1)
+	assert.Equal(t, "Tritanium", filtered.Structures[0].HangarAssets[0].Name)
+	assert.Empty(t, filtered.Structures[0].CorporationHangers)
+}
+
+func Test_ContactAssets_GetAssets_Forbidden(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: partnerID,
+		RecipientUserID: viewerID,
+		Status:          "accepted",
+	}, nil)
+	mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "asset_view").Return(false, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	result, httpErr := controller.GetAssets(contactAssetsArgs("/v1/contacts/7/assets", &viewerID))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+	mockAssets.AssertNotCalled(t, "GetUserAssets", mock.Anything, mock.Anything)
+}
+
+func Test_ContactAssets_GetAssets_PendingContact(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: 200,
+		RecipientUserID: viewerID,
+		Status:          "pending",
+	}, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	_, httpErr := controller.GetAssets(contactAssetsArgs("/v1/contacts/7/assets", &viewerID))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+	mockPerms.AssertNotCalled(t, "CheckPermission", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_ContactAssets_GetAssets_ContactNotFound(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(nil, repositories.ErrContactNotFound)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	_, httpErr := controller.GetAssets(contactAssetsArgs("/v1/contacts/7/assets", &viewerID))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_ContactAssets_GetAssets_InvalidFilter(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: partnerID,
+		RecipientUserID: viewerID,
+		Status:          "accepted",
+	}, nil)
+	mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "asset_view").Return(true, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	_, httpErr := controller.GetAssets(contactAssetsArgs("/v1/contacts/7/assets?stationId=jita", &viewerID))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ContactAssets_GetStockpileDeficits_FiltersByStation(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: partnerID,
+		RecipientUserID: viewerID,
+		Status:          "accepted",
+	}, nil)
+	mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "stockpile_view").Return(true, nil)
+	mockPerms.On("GetScope", mock.Anything, partnerID, viewerID, "stockpile_view").Return(nil, nil, nil)
+
+	mockAssets.On("GetStockpileDeficits", mock.Anything, partnerID).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Tritanium", LocationID: 60003760, OwnerID: 2001},
+			{Name: "Pyerite", LocationID: 60008494, OwnerID: 2001},
+			{Name: "Mexallon", LocationID: 60003760, OwnerID: 98000001},
+		},
+	}, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	result, httpErr := controller.GetStockpileDeficits(contactAssetsArgs("/v1/contacts/7/stockpiles/deficits?stationId=60003760,60000001", &viewerID))
+
+	assert.Nil(t, httpErr)
+	filtered := result.(*repositories.StockpilesResponse)
+	assert.Len(t, filtered.Items, 2)
+	assert.Equal(t, "Tritanium", filtered.Items[0].Name)
+	assert.Equal(t, "Mexallon", filtered.Items[1].Name)
+	mockPerms.AssertExpectations(t)
+}
+
+func Test_ContactAssets_GetAssets_ViewerCannotWidenGrantedScope(t *testing.T) {
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	tests := []struct {
+		name     string
+		url      string
+		expected []int64
+	}{
+		{"no filter returns the granted scope", "/v1/contacts/7/assets", []int64{60003760}},
+		{"filter outside the scope returns nothing", "/v1/contacts/7/assets?stationId=60008494", []int64{}},
+		{"filter overlapping the scope keeps the overlap", "/v1/contacts/7/assets?stationId=60003760,60008494", []int64{60003760}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContacts := new(MockContactLookupRepository)
+			mockPerms := new(MockContactPermissionsRepository)
+			mockAssets := new(MockContactAssetsRepository)
+
+			mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+				ID:              7,
+				RequesterUserID: partnerID,
+				RecipientUserID: viewerID,
+				Status:          "accepted",
+			}, nil)
+			mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "asset_view").Return(true, nil)
+			mockPerms.On("GetScope", mock.Anything, partnerID, viewerID, "asset_view").Return([]int64{60003760}, nil, nil)
+
+			mockAssets.On("GetUserAssets", mock.Anything, partnerID).Return(&repositories.AssetsResponse{
+				Structures: []*repositories.AssetStructure{
+					{ID: 60003760, HangarAssets: []*repositories.Asset{{Name: "Tritanium", OwnerID: 2001}}},
+					{ID: 60008494, HangarAssets: []*repositories.Asset{{Name: "Mexallon", OwnerID: 2001}}},
+				},
+			}, nil)
+
+			controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+			result, httpErr := controller.GetAssets(contactAssetsArgs(tt.url, &viewerID))
+
+			assert.Nil(t, httpErr)
+			stations := []int64{}
+			for _, structure := range result.(*repositories.AssetsResponse).Structures {
+				stations = append(stations, structure.ID)
+			}
+			assert.Equal(t, tt.expected, stations)
+		})
+	}
+}
+
+func Test_ContactAssets_GetStockpileDeficits_ViewerCannotWidenGrantedOwners(t *testing.T) {
+	mockContacts := new(MockContactLookupRepository)
+	mockPerms := new(MockContactPermissionsRepository)
+	mockAssets := new(MockContactAssetsRepository)
+
+	viewerID := int64(100)
+	partnerID := int64(200)
+
+	mockContacts.On("GetByID", mock.Anything, int64(7), viewerID).Return(&models.Contact{
+		ID:              7,
+		RequesterUserID: partnerID,
+		RecipientUserID: viewerID,
+		Status:          "accepted",
+	}, nil)
+	mockPerms.On("CheckPermission", mock.Anything, partnerID, viewerID, "stockpile_view").Return(true, nil)
+	mockPerms.On("GetScope", mock.Anything, partnerID, viewerID, "stockpile_view").Return(nil, []int64{2001}, nil)
+
+	mockAssets.On("GetStockpileDeficits", mock.Anything, partnerID).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Tritanium", LocationID: 60003760, OwnerID: 2001},
+			{Name: "Mexallon", LocationID: 60003760, OwnerID: 98000001},
+		},
+	}, nil)
+
+	controller := controllers.NewContactAssets(&MockRouter{}, mockContacts, mockPerms, mockAssets)
+	result, httpErr := controller.GetStockpileDeficits(contactAssetsArgs("/v1/contacts/7/stockpiles/deficits?ownerId=98000001", &viewerID))
+
+	assert.Nil(t, httpErr)
+	assert.Empty(t, result.(*repositories.StockpilesResponse).Items)
+}
@@ -0,0 +1,327 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const maxContactGroupNameLength = 100
+
+type ContactGroupsRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.ContactGroup, error)
+	Create(ctx context.Context, group *models.ContactGroup) error
+	Rename(ctx context.Context, groupID, userID int64, name string) error
+	Delete(ctx context.Context, groupID, userID int64) error
+	AddMember(ctx context.Context, groupID, userID, contactID int64) error
+	RemoveMember(ctx context.Context, groupID, userID, contactID int64) error
+	GetPermissions(ctx context.Context, groupID, userID int64) ([]*models.ContactGroupPermission, error)
+	SetPermission(ctx context.Context, userID int64, perm *models.ContactGroupPermission) error
+}
+
+// ContactGroupFilter narrows permission-filtered results to the members of one of the user's groups
+type ContactGroupFilter interface {
+	GetMemberUserIDs(ctx context.Context, groupID, userID int64) ([]int64, error)
+}
+
+type ContactGroups struct {
+	repository ContactGroupsRepository
+}
+
+func NewContactGroups(router Routerer, repository ContactGroupsRepository) *ContactGroups {
+	controller := &ContactGroups{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/contact-groups", web.AuthAccessUser, controller.GetGroups, "GET")
+	router.RegisterRestAPIRoute("/v1/contact-groups", web.AuthAccessUser, controller.CreateGroup, "POST")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}", web.AuthAccessUser, controller.RenameGroup, "PUT")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}", web.AuthAccessUser, controller.DeleteGroup, "DELETE")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/members", web.AuthAccessUser, controller.AddMember, "POST")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/members/{contactId}", web.AuthAccessUser, controller.RemoveMember, "DELETE")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/permissions", web.AuthAccessUser, controller.GetPermissions, "GET")
+	router.RegisterRestAPIRoute("/v1/contact-groups/{id}/permissions", web.AuthAccessUser, controller.UpdatePermission, "POST")
+
+	return controller
+}
+
+// GetGroups returns the user's contact groups and their members
+func (c *ContactGroups) GetGroups(args *web.HandlerArgs) (any, *web.HttpError) {
+	groups, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact groups"),
+		}
+	}
+
+	return groups, nil
+}
+
+// CreateGroup creates an empty named group
+func (c *ContactGroups) CreateGroup(args *web.HandlerArgs) (any, *web.HttpError) {
+	name, httpErr := decodeContactGroupName(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	group := &models.ContactGroup{
+		UserID: *args.User,
+		Name:   name,
+	}
+
+	err := c.repository.Create(args.Request.Context(), group)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to create contact group"),
+		}
+	}
+
+	return group, nil
+}
+
+// RenameGroup changes a group's name
+func (c *ContactGroups) RenameGroup(args *web.HandlerArgs) (any, *web.HttpError) {
+	groupID, httpErr := parseContactGroupID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	name, httpErr := decodeContactGroupName(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	err := c.repository.Rename(args.Request.Context(), groupID, *args.User, name)
+	if err != nil {
+		return nil, contactGroupError(err, "failed to rename contact group")
+	}
+
+	return nil, nil
+}
+
+// DeleteGroup removes a group; members keep only the access granted to them some other way
+func (c *ContactGroups) DeleteGroup(args *web.HandlerArgs) (any, *web.HttpError) {
+	groupID, httpErr := parseContactGroupID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	err := c.repository.Delete(args.Request.Context(), groupID, *args.User)
+	if err != nil {
+		return nil, contactGroupError(err, "failed to delete contact group")
+	}
+
+	return nil, nil
+}
+
+// AddMember adds an accepted contact to the group
+func (c *ContactGroups) AddMember(args *web.HandlerArgs) (any, *web.HttpError) {
+	groupID, httpErr := parseContactGroupID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req struct {
+		ContactID int64 `json:"contactId"`
+	}
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+	if req.ContactID <= 0 {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("contactId is required"),
+		}
+	}
+
+	err = c.repository.AddMember(args.Request.Context(), groupID, *args.User, req.ContactID)
+	if err != nil {
+		return nil, contactGroupError(err, "failed to add contact group member")
+	}
+
+	return nil, nil
+}
+
+// RemoveMember removes a contact from the group
+func (c *ContactGroups) RemoveMember(args *web.HandlerArgs) (any, *web.HttpError) {
+	groupID, httpErr := parseContactGroupID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	contactID, err := strconv.ParseInt(args.Params["contactId"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid contact ID"),
+		}
+	}
+
+	err = c.repository.RemoveMember(args.Request.Context(), groupID, *args.User, contactID)
+	if err != nil {
+		return nil, contactGroupError(err, "failed to remove contact group member")
+	}
+
+	return nil, nil
+}
+
+// GetPermissions returns the services set at group level
+func (c *ContactGroups) GetPermissions(args *web.HandlerArgs) (any, *web.HttpError) {
+	groupID, httpErr := parseContactGroupID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	permissions, err := c.repository.GetPermissions(args.Request.Context(), groupID, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact group permissions"),
+		}
+	}
+
+	return permissions, nil
+}
+
+// UpdatePermission grants or revokes a service for every member of the group
+func (c *ContactGroups) UpdatePermission(args *web.HandlerArgs) (any, *web.HttpError) {
+	groupID, httpErr := parseContactGroupID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var req struct {
+		ServiceType string `json:"serviceType"`
+		CanAccess   bool   `json:"canAccess"`
+	}
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	if !models.IsValidServiceType(req.ServiceType) {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("unknown service type %q", req.ServiceType),
+		}
+	}
+
+	perm := &models.ContactGroupPermission{
+		GroupID:     groupID,
+		ServiceType: req.ServiceType,
+		CanAccess:   req.CanAccess,
+	}
+
+	err = c.repository.SetPermission(args.Request.Context(), *args.User, perm)
+	if err != nil {
+		return nil, contactGroupError(err, "failed to update contact group permission")
+	}
+
+	return perm, nil
+}
+
+func parseContactGroupID(args *web.HandlerArgs) (int64, *web.HttpError) {
+	groupID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return 0, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid contact group ID"),
+		}
+	}
+	return groupID, nil
+}
+
+func decodeContactGroupName(args *web.HandlerArgs) (string, *web.HttpError) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return "", &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("name is required"),
+		}
+	}
+	if len(name) > maxContactGroupNameLength {
+		return "", &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("name must be at most %d characters", maxContactGroupNameLength),
+		}
+	}
+
+	return name, nil
+}
+
+// contactGroupError maps repository not-found errors to 404
+func contactGroupError(err error, message string) *web.HttpError {
+	if errors.Is(err, repositories.ErrContactGroupNotFound) ||
+		errors.Is(err, repositories.ErrContactNotFound) ||
+		errors.Is(err, repositories.ErrContactGroupMemberNotFound) {
+		return &web.HttpError{StatusCode: 404, Error: err}
+	}
+	return &web.HttpError{
+		StatusCode: 500,
+		Error:      errors.Wrap(err, message),
+	}
+}
+
+// applyContactGroupFilter restricts userIDs to the members of groupId when the query asks for it
+func applyContactGroupFilter(args *web.HandlerArgs, groups ContactGroupFilter, userID int64, userIDs []int64) ([]int64, *web.HttpError) {
+	groupIDStr := args.Request.URL.Query().Get("groupId")
+	if groupIDStr == "" {
+		return userIDs, nil
+	}
+
+	groupID, err := strconv.ParseInt(groupIDStr, 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid groupId"),
+		}
+	}
+
+	members, err := groups.GetMemberUserIDs(args.Request.Context(), groupID, userID)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact group members"),
+		}
+	}
+
+	inGroup := map[int64]bool{}
+	for _, member := range members {
+		inGroup[member] = true
+	}
+
+	filtered := []int64{}
+	for _, id := range userIDs {
+		if inGroup[id] {
+			filtered = append(filtered, id)
+		}
+	}
+
+	return filtered, nil
+}
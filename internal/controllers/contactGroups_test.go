@@ -0,0 +1,205 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockContactGroupsRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactGroupsRepository) GetByUser(ctx context.Context, userID int64) ([]*models.ContactGroup, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ContactGroup), args.Error(1)
+}
+
+func (m *MockContactGroupsRepository) Create(ctx context.Context, group *models.ContactGroup) error {
+	args := m.Called(ctx, group)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) Rename(ctx context.Context, groupID, userID int64, name string) error {
+	args := m.Called(ctx, groupID, userID, name)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) Delete(ctx context.Context, groupID, userID int64) error {
+	args := m.Called(ctx, groupID, userID)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) AddMember(ctx context.Context, groupID, userID, contactID int64) error {
+	args := m.Called(ctx, groupID, userID, contactID)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) RemoveMember(ctx context.Context, groupID, userID, contactID int64) error {
+	args := m.Called(ctx, groupID, userID, contactID)
+	return args.Error(0)
+}
+
+func (m *MockContactGroupsRepository) GetPermissions(ctx context.Context, groupID, userID int64) ([]*models.ContactGroupPermission, error) {
+	args := m.Called(ctx, groupID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ContactGroupPermission), args.Error(1)
+}
+
+func (m *MockContactGroupsRepository) SetPermission(ctx context.Context, userID int64, perm *models.ContactGroupPermission) error {
+	args := m.Called(ctx, userID, perm)
+	return args.Error(0)
+}
+
+func contactGroupArgs(method, url string, body any, userID *int64, params map[string]string) *web.HandlerArgs {
+	var reader *bytes.Reader
+	if body != nil {
+		b, _ := json.Marshal(body)
+		reader = bytes.NewReader(b)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest(method, url, reader),
+		User:    userID,
+		Params:  params,
+	}
+}
+
+func Test_ContactGroupsController_CreateGroup_Success(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(g *models.ContactGroup) bool {
+		return g.UserID == userID && g.Name == "Haulers"
+	})).Return(nil)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	result, httpErr := controller.CreateGroup(contactGroupArgs("POST", "/v1/contact-groups", map[string]any{"name": "  Haulers "}, &userID, nil))
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, "Haulers", result.(*models.ContactGroup).Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactGroupsController_CreateGroup_Validation(t *testing.T) {
+	userID := int64(123)
+
+	tests := []struct {
+		name string
+		body map[string]any
+	}{
+		{"empty name", map[string]any{"name": "   "}},
+		{"name too long", map[string]any{"name": strings.Repeat("a", 101)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockContactGroupsRepository)
+			controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+			_, httpErr := controller.CreateGroup(contactGroupArgs("POST", "/v1/contact-groups", tt.body, &userID, nil))
+
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_ContactGroupsController_AddMember_Success(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	mockRepo.On("AddMember", mock.Anything, int64(4), userID, int64(55)).Return(nil)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	_, httpErr := controller.AddMember(contactGroupArgs("POST", "/v1/contact-groups/4/members", map[string]any{"contactId": 55}, &userID, map[string]string{"id": "4"}))
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactGroupsController_AddMember_ContactNotFound(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	mockRepo.On("AddMember", mock.Anything, int64(4), userID, int64(55)).Return(repositories.ErrContactNotFound)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	_, httpErr := controller.AddMember(contactGroupArgs("POST", "/v1/contact-groups/4/members", map[string]any{"contactId": 55}, &userID, map[string]string{"id": "4"}))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_ContactGroupsController_RemoveMember_Success(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	mockRepo.On("RemoveMember", mock.Anything, int64(4), userID, int64(55)).Return(nil)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	_, httpErr := controller.RemoveMember(contactGroupArgs("DELETE", "/v1/contact-groups/4/members/55", nil, &userID, map[string]string{"id": "4", "contactId": "55"}))
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactGroupsController_UpdatePermission_Success(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	mockRepo.On("SetPermission", mock.Anything, userID, &models.ContactGroupPermission{
+		GroupID:     4,
+		ServiceType: "stockpile_view",
+		CanAccess:   true,
+	}).Return(nil)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	_, httpErr := controller.UpdatePermission(contactGroupArgs("POST", "/v1/contact-groups/4/permissions",
+		map[string]any{"serviceType": "stockpile_view", "canAccess": true}, &userID, map[string]string{"id": "4"}))
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactGroupsController_UpdatePermission_UnknownService(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	_, httpErr := controller.UpdatePermission(contactGroupArgs("POST", "/v1/contact-groups/4/permissions",
+		map[string]any{"serviceType": "reactions", "canAccess": true}, &userID, map[string]string{"id": "4"}))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "SetPermission", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_ContactGroupsController_DeleteGroup_NotFound(t *testing.T) {
+	mockRepo := new(MockContactGroupsRepository)
+	userID := int64(123)
+
+	mockRepo.On("Delete", mock.Anything, int64(4), userID).Return(repositories.ErrContactGroupNotFound)
+
+	controller := controllers.NewContactGroups(&MockRouter{}, mockRepo)
+	_, httpErr := controller.DeleteGroup(contactGroupArgs("DELETE", "/v1/contact-groups/4", nil, &userID, map[string]string{"id": "4"}))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
@@ -15,6 +15,8 @@ type ContactPermissionsRepository interface {
 	Upsert(ctx context.Context, perm *models.ContactPermission) error
 	GetUserPermissionsForService(ctx context.Context, viewerUserID int64, serviceType string) ([]int64, error)
 	CheckPermission(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) (bool, error)
+	SetScope(ctx context.Context, perm *models.ContactPermission) error
+	GetScope(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) ([]int64, []int64, error)
 }
 
 type ContactPermissions struct {
@@ -28,6 +30,8 @@ func NewContactPermissions(router Routerer, repository ContactPermissionsReposit
 
 	router.RegisterRestAPIRoute("/v1/contacts/{id}/permissions", web.AuthAccessUser, controller.GetPermissions, "GET")
 	router.RegisterRestAPIRoute("/v1/contacts/{id}/permissions", web.AuthAccessUser, controller.UpdatePermission, "POST")
+	router.RegisterRestAPIRoute("/v1/contacts/{id}/permissions/scope", web.AuthAccessUser, controller.UpdateScope, "POST")
+	router.RegisterRestAPIRoute("/v1/permissions/service-types", web.AuthAccessUser, controller.GetServiceTypes, "GET")
 
 	return controller
 }
@@ -66,7 +70,7 @@ func (c *ContactPermissions) UpdatePermission(args *web.HandlerArgs) (any, *web.
 	d := json.NewDecoder(args.Request.Body)
 	var req struct {
 		ServiceType     string `json:"serviceType"`
-		CanAccess       bool   `json:"canAccess"`
+		CanAccess       *bool  `json:"canAccess"`
 		ReceivingUserID int64  `json:"receivingUserId"`
 	}
 	err = d.Decode(&req)
@@ -77,7 +81,14 @@ func (c *ContactPermissions) UpdatePermission(args *web.HandlerArgs) (any, *web.
 		}
 	}
 
-	// User is granting permission to another user
+	if !models.IsValidServiceType(req.ServiceType) {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("unknown service type %q", req.ServiceType),
+		}
+	}
+
+	// User is granting permission to another user; null hands the decision back to groups and affiliations
 	perm := &models.ContactPermission{
 		ContactID:       contactID,
 		GrantingUserID:  *args.User,
@@ -96,3 +107,60 @@ func (c *ContactPermissions) UpdatePermission(args *web.HandlerArgs) (any, *web.
 
 	return nil, nil
 }
+
+// UpdateScope limits a scoped grant to the given stations and owners. The scope is
+// enforced when the contact reads the data, so they cannot widen it with their own filters
+func (c *ContactPermissions) UpdateScope(args *web.HandlerArgs) (any, *web.HttpError) {
+	contactID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid contact ID"),
+		}
+	}
+
+	var req struct {
+		ServiceType     string  `json:"serviceType"`
+		ReceivingUserID int64   `json:"receivingUserId"`
+		StationIDs      []int64 `json:"stationIds"`
+		OwnerIDs        []int64 `json:"ownerIds"`
+	}
+	err = json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	if !models.IsScopedServiceType(req.ServiceType) {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("service type %q cannot be scoped", req.ServiceType),
+		}
+	}
+
+	perm := &models.ContactPermission{
+		ContactID:       contactID,
+		GrantingUserID:  *args.User,
+		ReceivingUserID: req.ReceivingUserID,
+		ServiceType:     req.ServiceType,
+		StationIDs:      req.StationIDs,
+		OwnerIDs:        req.OwnerIDs,
+	}
+
+	err = c.repository.SetScope(args.Request.Context(), perm)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to update permission scope"),
+		}
+	}
+
+	return nil, nil
+}
+
+// GetServiceTypes returns the registry of services that can be granted
+func (c *ContactPermissions) GetServiceTypes(args *web.HandlerArgs) (any, *web.HttpError) {
+	return models.ServiceTypes, nil
+}
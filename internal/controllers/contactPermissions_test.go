@@ -28,7 +28,7 @@ func Test_ContactPermissionsController_GetPermissions_Success(t *testing.T) {
 			GrantingUserID:  123,
 			ReceivingUserID: 456,
 			ServiceType:     "for_sale_browse",
-			CanAccess:       true,
+			CanAccess:       boolPtr(true),
 		},
 		{
 			ID:              2,
@@ -36,7 +36,7 @@ func Test_ContactPermissionsController_GetPermissions_Success(t *testing.T) {
 			GrantingUserID:  456,
 			ReceivingUserID: 123,
 			ServiceType:     "for_sale_browse",
-			CanAccess:       false,
+			CanAccess:       boolPtr(false),
 		},
 	}
 
@@ -58,8 +58,8 @@ func Test_ContactPermissionsController_GetPermissions_Success(t *testing.T) {
 	permissions := result.([]*models.ContactPermission)
 	assert.Len(t, permissions, 2)
 	assert.Equal(t, int64(1), permissions[0].ID)
-	assert.True(t, permissions[0].CanAccess)
-	assert.False(t, permissions[1].CanAccess)
+	assert.True(t, *permissions[0].CanAccess)
+	assert.False(t, *permissions[1].CanAccess)
 
 	mockRepo.AssertExpectations(t)
 }
@@ -126,7 +126,7 @@ func Test_ContactPermissionsController_UpdatePermission_Success(t *testing.T) {
 		GrantingUserID:  userID,
 		ReceivingUserID: receivingUserID,
 		ServiceType:     serviceType,
-		CanAccess:       canAccess,
+		CanAccess:       &canAccess,
 	}
 
 	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(perm *models.ContactPermission) bool {
@@ -134,7 +134,7 @@ func Test_ContactPermissionsController_UpdatePermission_Success(t *testing.T) {
 			perm.GrantingUserID == expectedPerm.GrantingUserID &&
 			perm.ReceivingUserID == expectedPerm.ReceivingUserID &&
 			perm.ServiceType == expectedPerm.ServiceType &&
-			perm.CanAccess == expectedPerm.CanAccess
+			perm.CanAccess != nil && *perm.CanAccess == *expectedPerm.CanAccess
 	})).Return(nil)
 
 	body := map[string]interface{}{
@@ -256,7 +256,7 @@ func Test_ContactPermissionsController_UpdatePermission_GrantAccess(t *testing.T
 	userID := int64(123)
 
 	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(perm *models.ContactPermission) bool {
-		return perm.CanAccess == true
+		return perm.CanAccess != nil && *perm.CanAccess
 	})).Return(nil)
 
 	body := map[string]interface{}{
@@ -291,7 +291,7 @@ func Test_ContactPermissionsController_UpdatePermission_RevokeAccess(t *testing.
 	userID := int64(123)
 
 	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(perm *models.ContactPermission) bool {
-		return perm.CanAccess == false
+		return perm.CanAccess != nil && !*perm.CanAccess
 	})).Return(nil)
 
 	body := map[string]interface{}{
@@ -318,3 +318,155 @@ func Test_ContactPermissionsController_UpdatePermission_RevokeAccess(t *testing.
 
 	mockRepo.AssertExpectations(t)
 }
+
+func Test_ContactPermissionsController_UpdatePermission_ClearDecision(t *testing.T) {
+	mockRepo := new(MockContactPermissionsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(perm *models.ContactPermission) bool {
+		return perm.CanAccess == nil
+	})).Return(nil)
+
+	body := map[string]interface{}{
+		"serviceType":     "for_sale_browse",
+		"receivingUserId": 456,
+		"canAccess":       nil,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/v1/contacts/1/permissions", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "1"},
+	}
+
+	controller := controllers.NewContactPermissions(mockRouter, mockRepo)
+	result, httpErr := controller.UpdatePermission(args)
+
+	assert.Nil(t, httpErr)
+	assert.Nil(t, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactPermissionsController_UpdatePermission_UnknownServiceType(t *testing.T) {
+	mockRepo := new(MockContactPermissionsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	body := map[string]interface{}{
+		"serviceType":     "manufacturing",
+		"receivingUserId": 456,
+		"canAccess":       true,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/v1/contacts/1/permissions", bytes.NewReader(bodyBytes))
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "1"},
+	}
+
+	controller := controllers.NewContactPermissions(mockRouter, mockRepo)
+	result, httpErr := controller.UpdatePermission(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "unknown service type")
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_ContactPermissionsController_GetServiceTypes(t *testing.T) {
+	mockRepo := new(MockContactPermissionsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/permissions/service-types", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContactPermissions(mockRouter, mockRepo)
+	result, httpErr := controller.GetServiceTypes(args)
+
+	assert.Nil(t, httpErr)
+	serviceTypes := result.([]models.ServiceType)
+	assert.Len(t, serviceTypes, 6)
+	assert.Equal(t, models.ServiceForSaleBrowse, serviceTypes[0].Type)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func Test_ContactPermissionsController_UpdateScope_Success(t *testing.T) {
+	mockRepo := new(MockContactPermissionsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("SetScope", mock.Anything, mock.MatchedBy(func(perm *models.ContactPermission) bool {
+		return perm.ContactID == 1 &&
+			perm.GrantingUserID == 123 &&
+			perm.ReceivingUserID == 456 &&
+			perm.ServiceType == "asset_view" &&
+			assert.ObjectsAreEqual([]int64{60003760}, perm.StationIDs) &&
+			perm.OwnerIDs == nil
+	})).Return(nil)
+
+	body := map[string]interface{}{
+		"serviceType":     "asset_view",
+		"receivingUserId": 456,
+		"stationIds":      []int64{60003760},
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/contacts/1/permissions/scope", bytes.NewReader(bodyBytes)),
+		User:    &userID,
+		Params:  map[string]string{"id": "1"},
+	}
+
+	controller := controllers.NewContactPermissions(mockRouter, mockRepo)
+	result, httpErr := controller.UpdateScope(args)
+
+	assert.Nil(t, httpErr)
+	assert.Nil(t, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ContactPermissionsController_UpdateScope_UnscopedService(t *testing.T) {
+	mockRepo := new(MockContactPermissionsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	body := map[string]interface{}{
+		"serviceType":     "for_sale_browse",
+		"receivingUserId": 456,
+		"stationIds":      []int64{60003760},
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/contacts/1/permissions/scope", bytes.NewReader(bodyBytes)),
+		User:    &userID,
+		Params:  map[string]string{"id": "1"},
+	}
+
+	controller := controllers.NewContactPermissions(mockRouter, mockRepo)
+	_, httpErr := controller.UpdateScope(args)
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "SetScope", mock.Anything, mock.Anything)
+}
@@ -5,12 +5,21 @@ import (
 	"database/sql"
 	"encoding/json"
 	"strconv"
+	"strings"
 
+	"github.com/annymsMthd/industry-tool/internal/events"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
 )
 
+const (
+	minCharacterSearchLength  = 3
+	maxCharacterSearchResults = 20
+)
+
 type ContactsRepository interface {
 	GetByUser(ctx context.Context, userID int64) ([]*models.Contact, error)
 	Create(ctx context.Context, requesterID, recipientID int64) (*models.Contact, error)
@@ -18,6 +27,24 @@ type ContactsRepository interface {
 	Delete(ctx context.Context, contactID int64, userID int64) error
 	GetUserIDByCharacterName(ctx context.Context, characterName string) (int64, error)
 	GetUserIDByCharacterID(ctx context.Context, characterID int64) (int64, error)
+	SearchCharacters(ctx context.Context, query string, limit int) ([]*models.CharacterSearchResult, error)
+}
+
+type ContactInvitesRepository interface {
+	Create(ctx context.Context, invite *models.ContactInvite) error
+	GetByRequester(ctx context.Context, userID int64) ([]*models.ContactInvite, error)
+	Delete(ctx context.Context, id, userID int64) error
+}
+
+// ContactInviteActivator turns invitations for a newly linked character into contact requests
+type ContactInviteActivator interface {
+	ActivateForCharacter(ctx context.Context, characterID, userID int64) (int64, error)
+}
+
+// ContactsEsiClient resolves characters that are not registered with the tool
+type ContactsEsiClient interface {
+	GetCharacterIDs(ctx context.Context, names []string) ([]*models.CharacterName, error)
+	GetCharacterAffiliations(ctx context.Context, characterIDs []int64) ([]*models.CharacterAffiliation, error)
 }
 
 type ContactPermissionsInitializer interface {
@@ -27,13 +54,20 @@ type ContactPermissionsInitializer interface {
 type Contacts struct {
 	repository            ContactsRepository
 	permissionsRepository ContactPermissionsInitializer
+	reputationRepository  ReputationRepository
+	invitesRepository     ContactInvitesRepository
+	esiClient             ContactsEsiClient
 	db                    *sql.DB
+	events                EventPublisher
 }
 
-func NewContacts(router Routerer, repository ContactsRepository, permissionsRepository ContactPermissionsInitializer, db *sql.DB) *Contacts {
+func NewContacts(router Routerer, repository ContactsRepository, permissionsRepository ContactPermissionsInitializer, reputationRepository ReputationRepository, invitesRepository ContactInvitesRepository, esiClient ContactsEsiClient, db *sql.DB) *Contacts {
 	controller := &Contacts{
 		repository:            repository,
 		permissionsRepository: permissionsRepository,
+		reputationRepository:  reputationRepository,
+		invitesRepository:     invitesRepository,
+		esiClient:             esiClient,
 		db:                    db,
 	}
 
@@ -42,10 +76,19 @@ func NewContacts(router Routerer, repository ContactsRepository, permissionsRepo
 	router.RegisterRestAPIRoute("/v1/contacts/{id}/accept", web.AuthAccessUser, controller.AcceptContact, "POST")
 	router.RegisterRestAPIRoute("/v1/contacts/{id}/reject", web.AuthAccessUser, controller.RejectContact, "POST")
 	router.RegisterRestAPIRoute("/v1/contacts/{id}", web.AuthAccessUser, controller.DeleteContact, "DELETE")
+	router.RegisterRestAPIRoute("/v1/contacts/search", web.AuthAccessUser, controller.SearchCharacters, "GET")
+	router.RegisterRestAPIRoute("/v1/contacts/invites", web.AuthAccessUser, controller.GetInvites, "GET")
+	router.RegisterRestAPIRoute("/v1/contacts/invites/{id}", web.AuthAccessUser, controller.DeleteInvite, "DELETE")
 
 	return controller
 }
 
+// WithEvents publishes contact requests and responses to the other party
+func (c *Contacts) WithEvents(publisher EventPublisher) *Contacts {
+	c.events = publisher
+	return c
+}
+
 // getUserID converts the session user (which is the main character's EVE ID) to a user ID
 // Since users.id IS the main character's EVE character ID, we can use it directly
 func (c *Contacts) getUserID(ctx context.Context, sessionUser int64) (int64, error) {
@@ -70,12 +113,36 @@ func (c *Contacts) GetContacts(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
+	if len(contacts) > 0 {
+		// Attach the other party's trading reputation to each contact
+		otherUserIDs := []int64{}
+		for _, contact := range contacts {
+			otherUserIDs = append(otherUserIDs, contactOtherUserID(contact, userID))
+		}
+
+		reputations, err := c.reputationRepository.GetForUsers(args.Request.Context(), otherUserIDs)
+		if err != nil {
+			return nil, &web.HttpError{
+				StatusCode: 500,
+				Error:      errors.Wrap(err, "failed to get contact reputation"),
+			}
+		}
+
+		for _, contact := range contacts {
+			contact.Reputation = reputations[contactOtherUserID(contact, userID)]
+		}
+	}
+
 	return contacts, nil
 }
 
+// CreateContact sends a contact request by characterId or exact characterName. Characters that
+// are not registered are resolved through ESI and stored as an invite that becomes a pending
+// contact request once they link their account.
 func (c *Contacts) CreateContact(args *web.HandlerArgs) (any, *web.HttpError) {
 	d := json.NewDecoder(args.Request.Body)
 	var req struct {
+		CharacterID   int64  `json:"characterId"`
 		CharacterName string `json:"characterName"`
 	}
 	err := d.Decode(&req)
@@ -86,6 +153,14 @@ func (c *Contacts) CreateContact(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
+	req.CharacterName = strings.TrimSpace(req.CharacterName)
+	if req.CharacterID <= 0 && req.CharacterName == "" {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("characterId or characterName is required"),
+		}
+	}
+
 	// Get requester user ID
 	requesterUserID, err := c.getUserID(args.Request.Context(), *args.User)
 	if err != nil {
@@ -95,15 +170,27 @@ func (c *Contacts) CreateContact(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
-	// Look up recipient user ID by character name
-	recipientUserID, err := c.repository.GetUserIDByCharacterName(args.Request.Context(), req.CharacterName)
+	// Look up recipient user ID among registered characters
+	var recipientUserID int64
+	if req.CharacterID > 0 {
+		recipientUserID, err = c.repository.GetUserIDByCharacterID(args.Request.Context(), req.CharacterID)
+	} else {
+		recipientUserID, err = c.repository.GetUserIDByCharacterName(args.Request.Context(), req.CharacterName)
+	}
 	if err != nil {
-		return nil, &web.HttpError{
-			StatusCode: 404,
-			Error:      errors.Wrap(err, "character not found"),
+		if !errors.Is(err, repositories.ErrCharacterNotFound) {
+			return nil, &web.HttpError{
+				StatusCode: 500,
+				Error:      errors.Wrap(err, "failed to look up character"),
+			}
 		}
+		return c.inviteCharacter(args.Request.Context(), requesterUserID, req.CharacterID, req.CharacterName)
 	}
 
+	return c.requestContact(args.Request.Context(), requesterUserID, recipientUserID)
+}
+
+func (c *Contacts) requestContact(ctx context.Context, requesterUserID, recipientUserID int64) (any, *web.HttpError) {
 	// Prevent self-contact
 	if recipientUserID == requesterUserID {
 		return nil, &web.HttpError{
@@ -112,7 +199,7 @@ func (c *Contacts) CreateContact(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
-	contact, err := c.repository.Create(args.Request.Context(), requesterUserID, recipientUserID)
+	contact, err := c.repository.Create(ctx, requesterUserID, recipientUserID)
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: 500,
@@ -120,9 +207,197 @@ func (c *Contacts) CreateContact(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
+	publishEvent(ctx, c.events, events.ContactRequested, recipientUserID, contact)
+
 	return contact, nil
 }
 
+// inviteCharacter resolves an unregistered character through ESI and stores an invite for it
+func (c *Contacts) inviteCharacter(ctx context.Context, requesterUserID, characterID int64, characterName string) (any, *web.HttpError) {
+	var name *string
+	if characterID <= 0 {
+		characters, err := c.esiClient.GetCharacterIDs(ctx, []string{characterName})
+		if err != nil {
+			return nil, &web.HttpError{
+				StatusCode: 502,
+				Error:      errors.Wrap(err, "failed to resolve character name"),
+			}
+		}
+		if len(characters) == 0 {
+			return nil, &web.HttpError{
+				StatusCode: 404,
+				Error:      errors.Errorf("character %q not found", characterName),
+			}
+		}
+		characterID = characters[0].ID
+		name = &characters[0].Name
+
+		// The character may be registered under an older name
+		recipientUserID, err := c.repository.GetUserIDByCharacterID(ctx, characterID)
+		if err == nil {
+			return c.requestContact(ctx, requesterUserID, recipientUserID)
+		}
+		if !errors.Is(err, repositories.ErrCharacterNotFound) {
+			return nil, &web.HttpError{
+				StatusCode: 500,
+				Error:      errors.Wrap(err, "failed to look up character"),
+			}
+		}
+	} else {
+		// Affiliation lookup confirms the ID belongs to a real character
+		affiliations, err := c.esiClient.GetCharacterAffiliations(ctx, []int64{characterID})
+		if err != nil {
+			return nil, &web.HttpError{
+				StatusCode: 502,
+				Error:      errors.Wrap(err, "failed to look up character affiliation"),
+			}
+		}
+		if len(affiliations) == 0 {
+			return nil, &web.HttpError{
+				StatusCode: 404,
+				Error:      errors.Errorf("character %d not found", characterID),
+			}
+		}
+	}
+
+	invite := &models.ContactInvite{
+		RequesterUserID: requesterUserID,
+		CharacterID:     characterID,
+		CharacterName:   name,
+	}
+
+	err := c.invitesRepository.Create(ctx, invite)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to create contact invite"),
+		}
+	}
+
+	return invite, nil
+}
+
+// SearchCharacters finds invitation candidates by partial name among registered characters,
+// plus an exact-name match from ESI for characters that have not registered yet
+func (c *Contacts) SearchCharacters(args *web.HandlerArgs) (any, *web.HttpError) {
+	query := strings.TrimSpace(args.Request.URL.Query().Get("q"))
+	if len(query) < minCharacterSearchLength {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("q must be at least %d characters", minCharacterSearchLength),
+		}
+	}
+
+	ctx := args.Request.Context()
+
+	results, err := c.repository.SearchCharacters(ctx, query, maxCharacterSearchResults)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to search characters"),
+		}
+	}
+
+	// ESI search needs a character token, so unregistered characters can only be found by exact name
+	seen := map[int64]bool{}
+	for _, result := range results {
+		seen[result.CharacterID] = true
+	}
+
+	characters, err := c.esiClient.GetCharacterIDs(ctx, []string{query})
+	if err != nil {
+		log.Warn("failed to resolve character name through esi", "query", query, "error", err)
+	} else {
+		for _, character := range characters {
+			if !seen[character.ID] {
+				results = append(results, &models.CharacterSearchResult{
+					CharacterID: character.ID,
+					Name:        character.Name,
+				})
+			}
+		}
+	}
+
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	characterIDs := []int64{}
+	for _, result := range results {
+		characterIDs = append(characterIDs, result.CharacterID)
+	}
+
+	affiliations, err := c.esiClient.GetCharacterAffiliations(ctx, characterIDs)
+	if err != nil {
+		log.Warn("failed to get character affiliations for search", "error", err)
+		return results, nil
+	}
+
+	byCharacter := map[int64]*models.CharacterAffiliation{}
+	for _, affiliation := range affiliations {
+		byCharacter[affiliation.CharacterID] = affiliation
+	}
+	for _, result := range results {
+		if affiliation, ok := byCharacter[result.CharacterID]; ok {
+			corporationID := affiliation.CorporationID
+			result.CorporationID = &corporationID
+			result.AllianceID = affiliation.AllianceID
+		}
+	}
+
+	return results, nil
+}
+
+// GetInvites returns invitations the user has sent to characters that have not registered yet
+func (c *Contacts) GetInvites(args *web.HandlerArgs) (any, *web.HttpError) {
+	invites, err := c.invitesRepository.GetByRequester(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get contact invites"),
+		}
+	}
+
+	return invites, nil
+}
+
+// DeleteInvite cancels an outstanding invitation
+func (c *Contacts) DeleteInvite(args *web.HandlerArgs) (any, *web.HttpError) {
+	inviteID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid invite ID"),
+		}
+	}
+
+	err = c.invitesRepository.Delete(args.Request.Context(), inviteID, *args.User)
+	if err != nil {
+		if errors.Is(err, repositories.ErrContactInviteNotFound) {
+			return nil, &web.HttpError{StatusCode: 404, Error: err}
+		}
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to delete contact invite"),
+		}
+	}
+
+	return nil, nil
+}
+
+// activateContactInvites is called when a character is linked to a user. Failures are only
+// logged so registration still succeeds; the invites stay in place for the next login.
+func activateContactInvites(ctx context.Context, invites ContactInviteActivator, characterID, userID int64) {
+	activated, err := invites.ActivateForCharacter(ctx, characterID, userID)
+	if err != nil {
+		log.Error("failed to activate contact invites", "character_id", characterID, "user_id", userID, "error", err)
+		return
+	}
+	if activated > 0 {
+		log.Info("activated contact invites", "character_id", characterID, "user_id", userID, "count", activated)
+	}
+}
+
 func (c *Contacts) AcceptContact(args *web.HandlerArgs) (any, *web.HttpError) {
 	contactIDStr := args.Params["id"]
 	contactID, err := strconv.ParseInt(contactIDStr, 10, 64)
@@ -183,6 +458,8 @@ func (c *Contacts) AcceptContact(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
+	publishEvent(args.Request.Context(), c.events, events.ContactAccepted, contact.RequesterUserID, contact)
+
 	return contact, nil
 }
 
@@ -213,6 +490,8 @@ func (c *Contacts) RejectContact(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
+	publishEvent(args.Request.Context(), c.events, events.ContactRejected, contact.RequesterUserID, contact)
+
 	return contact, nil
 }
 
@@ -245,3 +524,11 @@ func (c *Contacts) DeleteContact(args *web.HandlerArgs) (any, *web.HttpError) {
 
 	return nil, nil
 }
+
+// contactOtherUserID returns the user on the other side of a contact from userID
+func contactOtherUserID(contact *models.Contact, userID int64) int64 {
+	if contact.RequesterUserID == userID {
+		return contact.RecipientUserID
+	}
+	return contact.RequesterUserID
+}
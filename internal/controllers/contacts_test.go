@@ -11,6 +11,7 @@ import (
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -60,6 +61,56 @@ func (m *MockContactsRepository) GetUserIDByCharacterID(ctx context.Context, cha
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockContactsRepository) SearchCharacters(ctx context.Context, query string, limit int) ([]*models.CharacterSearchResult, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CharacterSearchResult), args.Error(1)
+}
+
+type MockContactInvitesRepository struct {
+	mock.Mock
+}
+
+func (m *MockContactInvitesRepository) Create(ctx context.Context, invite *models.ContactInvite) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *MockContactInvitesRepository) GetByRequester(ctx context.Context, userID int64) ([]*models.ContactInvite, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ContactInvite), args.Error(1)
+}
+
+func (m *MockContactInvitesRepository) Delete(ctx context.Context, id, userID int64) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+type MockContactsEsiClient struct {
+	mock.Mock
+}
+
+func (m *MockContactsEsiClient) GetCharacterIDs(ctx context.Context, names []string) ([]*models.CharacterName, error) {
+	args := m.Called(ctx, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CharacterName), args.Error(1)
+}
+
+func (m *MockContactsEsiClient) GetCharacterAffiliations(ctx context.Context, characterIDs []int64) ([]*models.CharacterAffiliation, error) {
+	args := m.Called(ctx, characterIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.CharacterAffiliation), args.Error(1)
+}
+
 type MockContactPermissionsInitializer struct {
 	mock.Mock
 }
@@ -84,9 +135,10 @@ func (m *MockDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, err
 func Test_ContactsController_GetContacts_Success(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
-	controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 
 	userID := int64(123)
 	expectedContacts := []*models.Contact{
@@ -102,6 +154,14 @@ func Test_ContactsController_GetContacts_Success(t *testing.T) {
 
 	mockRepo.On("GetByUser", mock.Anything, userID).Return(expectedContacts, nil)
 
+	completionHours := 6.5
+	mockReputation.On("GetForUsers", mock.Anything, []int64{456}).Return(map[int64]*models.Reputation{
+		456: {
+			UserID:   456,
+			AsSeller: models.ReputationStats{Transactions: 10, Completed: 9, CompletionRate: 0.9, CancelledBySellerRate: 0.1, MedianCompletionHours: &completionHours},
+		},
+	}, nil)
+
 	req := httptest.NewRequest("GET", "/v1/contacts", nil)
 	args := &web.HandlerArgs{
 		Request: req,
@@ -110,7 +170,7 @@ func Test_ContactsController_GetContacts_Success(t *testing.T) {
 
 	// Get handler from mockRouter - we need to test the handler directly
 	// Since we can't easily capture the handler from NewContacts, we'll test via creating a new instance
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.GetContacts(args)
 
 	assert.Nil(t, httpErr)
@@ -120,13 +180,18 @@ func Test_ContactsController_GetContacts_Success(t *testing.T) {
 	assert.Len(t, contacts, 1)
 	assert.Equal(t, int64(1), contacts[0].ID)
 	assert.Equal(t, "User 1", contacts[0].RequesterName)
+	assert.NotNil(t, contacts[0].Reputation)
+	assert.Equal(t, int64(456), contacts[0].Reputation.UserID)
+	assert.Equal(t, 0.9, contacts[0].Reputation.AsSeller.CompletionRate)
 
 	mockRepo.AssertExpectations(t)
+	mockReputation.AssertExpectations(t)
 }
 
 func Test_ContactsController_GetContacts_RepositoryError(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
@@ -139,7 +204,7 @@ func Test_ContactsController_GetContacts_RepositoryError(t *testing.T) {
 		User:    &userID,
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.GetContacts(args)
 
 	assert.Nil(t, result)
@@ -152,6 +217,7 @@ func Test_ContactsController_GetContacts_RepositoryError(t *testing.T) {
 func Test_ContactsController_CreateContact_Success(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
@@ -179,7 +245,7 @@ func Test_ContactsController_CreateContact_Success(t *testing.T) {
 		User:    &userID,
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.CreateContact(args)
 
 	assert.Nil(t, httpErr)
@@ -196,6 +262,7 @@ func Test_ContactsController_CreateContact_Success(t *testing.T) {
 func Test_ContactsController_CreateContact_SelfContactError(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
@@ -214,7 +281,7 @@ func Test_ContactsController_CreateContact_SelfContactError(t *testing.T) {
 		User:    &userID,
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.CreateContact(args)
 
 	assert.Nil(t, result)
@@ -227,12 +294,15 @@ func Test_ContactsController_CreateContact_SelfContactError(t *testing.T) {
 func Test_ContactsController_CreateContact_CharacterNotFound(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
+	mockEsi := new(MockContactsEsiClient)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
 	characterName := "Nonexistent Character"
 
-	mockRepo.On("GetUserIDByCharacterName", mock.Anything, characterName).Return(int64(0), errors.New("character not found"))
+	mockRepo.On("GetUserIDByCharacterName", mock.Anything, characterName).Return(int64(0), repositories.ErrCharacterNotFound)
+	mockEsi.On("GetCharacterIDs", mock.Anything, []string{characterName}).Return([]*models.CharacterName{}, nil)
 
 	body := map[string]string{"characterName": characterName}
 	bodyBytes, _ := json.Marshal(body)
@@ -245,7 +315,7 @@ func Test_ContactsController_CreateContact_CharacterNotFound(t *testing.T) {
 		User:    &userID,
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, mockEsi, nil)
 	result, httpErr := controller.CreateContact(args)
 
 	assert.Nil(t, result)
@@ -253,11 +323,13 @@ func Test_ContactsController_CreateContact_CharacterNotFound(t *testing.T) {
 	assert.Equal(t, 404, httpErr.StatusCode)
 
 	mockRepo.AssertExpectations(t)
+	mockEsi.AssertExpectations(t)
 }
 
 func Test_ContactsController_AcceptContact_Success(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(456)
@@ -281,7 +353,7 @@ func Test_ContactsController_AcceptContact_Success(t *testing.T) {
 
 	// Note: Passing nil DB will cause a panic when BeginTx is called
 	// This test verifies UpdateStatus is called correctly before that point
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 
 	// This will panic on nil DB BeginTx - recover to verify the UpdateStatus was called
 	defer func() {
@@ -300,6 +372,7 @@ func Test_ContactsController_AcceptContact_Success(t *testing.T) {
 func Test_ContactsController_RejectContact_Success(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(456)
@@ -321,7 +394,7 @@ func Test_ContactsController_RejectContact_Success(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.RejectContact(args)
 
 	assert.Nil(t, httpErr)
@@ -336,6 +409,7 @@ func Test_ContactsController_RejectContact_Success(t *testing.T) {
 func Test_ContactsController_DeleteContact_Success(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
@@ -350,7 +424,7 @@ mockRepo.On("Delete", mock.Anything, contactID, userID).Return(nil)
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.DeleteContact(args)
 
 	assert.Nil(t, httpErr)
@@ -362,6 +436,7 @@ mockRepo.On("Delete", mock.Anything, contactID, userID).Return(nil)
 func Test_ContactsController_DeleteContact_InvalidID(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
@@ -373,7 +448,7 @@ func Test_ContactsController_DeleteContact_InvalidID(t *testing.T) {
 		Params:  map[string]string{"id": "invalid"},
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.DeleteContact(args)
 
 	assert.Nil(t, result)
@@ -384,6 +459,7 @@ func Test_ContactsController_DeleteContact_InvalidID(t *testing.T) {
 func Test_ContactsController_DeleteContact_RepositoryError(t *testing.T) {
 	mockRepo := new(MockContactsRepository)
 	mockPermissions := new(MockContactPermissionsInitializer)
+	mockReputation := new(MockReputationRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
@@ -398,7 +474,7 @@ mockRepo.On("Delete", mock.Anything, contactID, userID).Return(errors.New("datab
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, nil)
+	controller := controllers.NewContacts(mockRouter, mockRepo, mockPermissions, mockReputation, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
 	result, httpErr := controller.DeleteContact(args)
 
 	assert.Nil(t, result)
@@ -407,3 +483,239 @@ mockRepo.On("Delete", mock.Anything, contactID, userID).Return(errors.New("datab
 
 	mockRepo.AssertExpectations(t)
 }
+
+func Test_ContactsController_CreateContact_InvitesUnregisteredCharacterByName(t *testing.T) {
+	mockRepo := new(MockContactsRepository)
+	mockInvites := new(MockContactInvitesRepository)
+	mockEsi := new(MockContactsEsiClient)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	characterName := "Unregistered Pilot"
+
+	mockRepo.On("GetUserIDByCharacterName", mock.Anything, characterName).Return(int64(0), repositories.ErrCharacterNotFound)
+	mockEsi.On("GetCharacterIDs", mock.Anything, []string{characterName}).Return([]*models.CharacterName{
+		{ID: 90000001, Name: characterName},
+	}, nil)
+	mockRepo.On("GetUserIDByCharacterID", mock.Anything, int64(90000001)).Return(int64(0), repositories.ErrCharacterNotFound)
+	mockInvites.On("Create", mock.Anything, mock.MatchedBy(func(invite *models.ContactInvite) bool {
+		return invite.RequesterUserID == userID &&
+			invite.CharacterID == 90000001 &&
+			invite.CharacterName != nil && *invite.CharacterName == characterName
+	})).Return(nil)
+
+	bodyBytes, _ := json.Marshal(map[string]string{"characterName": characterName})
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/contacts", bytes.NewReader(bodyBytes)),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContacts(mockRouter, mockRepo, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, mockInvites, mockEsi, nil)
+	result, httpErr := controller.CreateContact(args)
+
+	assert.Nil(t, httpErr)
+	invite := result.(*models.ContactInvite)
+	assert.Equal(t, int64(90000001), invite.CharacterID)
+
+	mockRepo.AssertExpectations(t)
+	mockInvites.AssertExpectations(t)
+	mockEsi.AssertExpectations(t)
+}
+
+func Test_ContactsController_CreateContact_RenamedCharacterBecomesContactRequest(t *testing.T) {
+	mockRepo := new(MockContactsRepository)
+	mockInvites := new(MockContactInvitesRepository)
+	mockEsi := new(MockContactsEsiClient)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	characterName := "New Name"
+
+	mockRepo.On("GetUserIDByCharacterName", mock.Anything, characterName).Return(int64(0), repositories.ErrCharacterNotFound)
+	mockEsi.On("GetCharacterIDs", mock.Anything, []string{characterName}).Return([]*models.CharacterName{
+		{ID: 456, Name: characterName},
+	}, nil)
+	mockRepo.On("GetUserIDByCharacterID", mock.Anything, int64(456)).Return(int64(456), nil)
+	mockRepo.On("Create", mock.Anything, userID, int64(456)).Return(&models.Contact{ID: 7, RequesterUserID: userID, RecipientUserID: 456, Status: "pending"}, nil)
+
+	bodyBytes, _ := json.Marshal(map[string]string{"characterName": characterName})
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/contacts", bytes.NewReader(bodyBytes)),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContacts(mockRouter, mockRepo, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, mockInvites, mockEsi, nil)
+	result, httpErr := controller.CreateContact(args)
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(7), result.(*models.Contact).ID)
+
+	mockRepo.AssertExpectations(t)
+	mockInvites.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_ContactsController_CreateContact_InvitesUnregisteredCharacterByID(t *testing.T) {
+	mockRepo := new(MockContactsRepository)
+	mockInvites := new(MockContactInvitesRepository)
+	mockEsi := new(MockContactsEsiClient)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	characterID := int64(90000002)
+
+	mockRepo.On("GetUserIDByCharacterID", mock.Anything, characterID).Return(int64(0), repositories.ErrCharacterNotFound)
+	mockEsi.On("GetCharacterAffiliations", mock.Anything, []int64{characterID}).Return([]*models.CharacterAffiliation{
+		{CharacterID: characterID, CorporationID: 98000001},
+	}, nil)
+	mockInvites.On("Create", mock.Anything, mock.MatchedBy(func(invite *models.ContactInvite) bool {
+		return invite.RequesterUserID == userID && invite.CharacterID == characterID && invite.CharacterName == nil
+	})).Return(nil)
+
+	bodyBytes, _ := json.Marshal(map[string]int64{"characterId": characterID})
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/contacts", bytes.NewReader(bodyBytes)),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContacts(mockRouter, mockRepo, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, mockInvites, mockEsi, nil)
+	result, httpErr := controller.CreateContact(args)
+
+	assert.Nil(t, httpErr)
+	assert.IsType(t, &models.ContactInvite{}, result)
+
+	mockRepo.AssertExpectations(t)
+	mockInvites.AssertExpectations(t)
+	mockEsi.AssertExpectations(t)
+}
+
+func Test_ContactsController_CreateContact_InviteByIDReportsEsiFailure(t *testing.T) {
+	userID := int64(123)
+	characterID := int64(90000002)
+
+	tests := []struct {
+		name         string
+		affiliations []*models.CharacterAffiliation
+		esiErr       error
+		expected     int
+	}{
+		{"esi unavailable", nil, errors.New("esi returned 503"), 502},
+		{"no such character", []*models.CharacterAffiliation{}, nil, 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockContactsRepository)
+			mockInvites := new(MockContactInvitesRepository)
+			mockEsi := new(MockContactsEsiClient)
+
+			mockRepo.On("GetUserIDByCharacterID", mock.Anything, characterID).Return(int64(0), repositories.ErrCharacterNotFound)
+			mockEsi.On("GetCharacterAffiliations", mock.Anything, []int64{characterID}).Return(tt.affiliations, tt.esiErr)
+
+			bodyBytes, _ := json.Marshal(map[string]int64{"characterId": characterID})
+			args := &web.HandlerArgs{
+				Request: httptest.NewRequest("POST", "/v1/contacts", bytes.NewReader(bodyBytes)),
+				User:    &userID,
+			}
+
+			controller := controllers.NewContacts(&MockRouter{}, mockRepo, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, mockInvites, mockEsi, nil)
+			_, httpErr := controller.CreateContact(args)
+
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, tt.expected, httpErr.StatusCode)
+			mockInvites.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_ContactsController_CreateContact_RequiresCharacter(t *testing.T) {
+	userID := int64(123)
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/contacts", bytes.NewReader([]byte(`{"characterName":"  "}`))),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContacts(&MockRouter{}, &MockContactsRepository{}, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
+	result, httpErr := controller.CreateContact(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ContactsController_SearchCharacters_MergesLocalAndEsiResults(t *testing.T) {
+	mockRepo := new(MockContactsRepository)
+	mockEsi := new(MockContactsEsiClient)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	allianceID := int64(99000001)
+
+	mockRepo.On("SearchCharacters", mock.Anything, "Pilot", 20).Return([]*models.CharacterSearchResult{
+		{CharacterID: 456, Name: "Pilot One", Registered: true},
+	}, nil)
+	mockEsi.On("GetCharacterIDs", mock.Anything, []string{"Pilot"}).Return([]*models.CharacterName{
+		{ID: 789, Name: "Pilot"},
+	}, nil)
+	mockEsi.On("GetCharacterAffiliations", mock.Anything, []int64{456, 789}).Return([]*models.CharacterAffiliation{
+		{CharacterID: 456, CorporationID: 98000001, AllianceID: &allianceID},
+		{CharacterID: 789, CorporationID: 98000002},
+	}, nil)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/contacts/search?q=Pilot", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContacts(mockRouter, mockRepo, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, &MockContactInvitesRepository{}, mockEsi, nil)
+	result, httpErr := controller.SearchCharacters(args)
+
+	assert.Nil(t, httpErr)
+	results := result.([]*models.CharacterSearchResult)
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].Registered)
+	assert.Equal(t, int64(98000001), *results[0].CorporationID)
+	assert.Equal(t, allianceID, *results[0].AllianceID)
+	assert.False(t, results[1].Registered)
+	assert.Equal(t, int64(789), results[1].CharacterID)
+	assert.Equal(t, int64(98000002), *results[1].CorporationID)
+
+	mockRepo.AssertExpectations(t)
+	mockEsi.AssertExpectations(t)
+}
+
+func Test_ContactsController_SearchCharacters_QueryTooShort(t *testing.T) {
+	userID := int64(123)
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/contacts/search?q=ab", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewContacts(&MockRouter{}, &MockContactsRepository{}, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil)
+	result, httpErr := controller.SearchCharacters(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ContactsController_DeleteInvite_NotFound(t *testing.T) {
+	mockInvites := new(MockContactInvitesRepository)
+	userID := int64(123)
+
+	mockInvites.On("Delete", mock.Anything, int64(5), userID).Return(repositories.ErrContactInviteNotFound)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("DELETE", "/v1/contacts/invites/5", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "5"},
+	}
+
+	controller := controllers.NewContacts(&MockRouter{}, &MockContactsRepository{}, &MockContactPermissionsInitializer{}, &MockReputationRepository{}, mockInvites, &MockContactsEsiClient{}, nil)
+	result, httpErr := controller.DeleteInvite(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+
+	mockInvites.AssertExpectations(t)
+}
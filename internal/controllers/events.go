@@ -0,0 +1,20 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+)
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event *events.Event)
+}
+
+// publishEvent tells the user about something that happened on the marketplace. Controllers
+// built without an event publisher skip it.
+func publishEvent(ctx context.Context, publisher EventPublisher, eventType string, userID int64, data any) {
+	if publisher == nil {
+		return
+	}
+	publisher.Publish(ctx, events.New(eventType, userID, data))
+}
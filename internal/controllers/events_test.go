@@ -0,0 +1,95 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEventPublisher struct {
+	mock.Mock
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event *events.Event) {
+	m.Called(ctx, event)
+}
+
+func Test_ForSaleItemsController_CreateListing_PublishesEvent(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockEvents := new(MockEventPublisher)
+
+	userID := int64(123)
+
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(1500), nil)
+	mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	mockEvents.On("Publish", mock.Anything, mock.MatchedBy(func(event *events.Event) bool {
+		item, ok := event.Data.(*models.ForSaleItem)
+		return event.Type == events.ListingCreated && event.UserID == userID && ok && item.TypeID == 34
+	})).Return()
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"typeId":            34,
+		"ownerType":         "character",
+		"ownerId":           456,
+		"locationId":        30000142,
+		"quantityAvailable": 1000,
+		"pricePerUnit":      50,
+	})
+	req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(body))
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{}).
+		WithEvents(mockEvents)
+	_, httpErr := controller.CreateListing(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{}})
+
+	assert.Nil(t, httpErr)
+	mockEvents.AssertExpectations(t)
+}
+
+func Test_ForSaleItemsController_DeleteListing_FailureDoesNotPublish(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockEvents := new(MockEventPublisher)
+
+	userID := int64(123)
+	mockRepo.On("Delete", mock.Anything, int64(1), userID).Return(errors.New("not found"))
+
+	req := httptest.NewRequest("DELETE", "/v1/for-sale/1", nil)
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{}).
+		WithEvents(mockEvents)
+	_, httpErr := controller.DeleteListing(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "1"}})
+
+	assert.NotNil(t, httpErr)
+	mockEvents.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func Test_ContactsController_RejectContact_PublishesToRequester(t *testing.T) {
+	mockRepo := new(MockContactsRepository)
+	mockEvents := new(MockEventPublisher)
+
+	userID := int64(456)
+	contact := &models.Contact{ID: 1, RequesterUserID: 123, RecipientUserID: userID, Status: "rejected"}
+
+	mockRepo.On("UpdateStatus", mock.Anything, int64(1), userID, "rejected").Return(contact, nil)
+	mockEvents.On("Publish", mock.Anything, mock.MatchedBy(func(event *events.Event) bool {
+		return event.Type == events.ContactRejected && event.UserID == 123 && event.Data == contact
+	})).Return()
+
+	req := httptest.NewRequest("POST", "/v1/contacts/1/reject", nil)
+
+	controller := controllers.NewContacts(&MockRouter{}, mockRepo, new(MockContactPermissionsInitializer), new(MockReputationRepository), &MockContactInvitesRepository{}, &MockContactsEsiClient{}, nil).
+		WithEvents(mockEvents)
+	_, httpErr := controller.RejectContact(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "1"}})
+
+	assert.Nil(t, httpErr)
+	mockEvents.AssertExpectations(t)
+}
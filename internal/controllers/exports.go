@@ -0,0 +1,349 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/export"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+// exportTopItemsLimit caps the item rows in an analytics export
+const exportTopItemsLimit = 10000
+
+type ExportPurchasesRepository interface {
+	StreamByBuyer(ctx context.Context, buyerUserID int64, fn func(*models.PurchaseTransaction) error) error
+	StreamBySeller(ctx context.Context, sellerUserID int64, fn func(*models.PurchaseTransaction) error) error
+}
+
+type ExportAnalyticsRepository interface {
+	GetSalesMetrics(ctx context.Context, sellerUserID int64, rng repositories.AnalyticsRange) (*models.SalesMetrics, error)
+	GetTopItems(ctx context.Context, sellerUserID int64, rng repositories.AnalyticsRange, limit int, sortBy string) ([]models.ItemSalesData, error)
+}
+
+type ExportAssetsRepository interface {
+	GetUserAssets(ctx context.Context, user int64) (*repositories.AssetsResponse, error)
+	GetStockpileDeficits(ctx context.Context, user int64) (*repositories.StockpilesResponse, error)
+}
+
+// Exports serves transactions, analytics, assets and stockpile deficits as CSV or XLSX
+// downloads. Every endpoint takes ?format=csv (default) or ?format=xlsx.
+type Exports struct {
+	purchasesRepository ExportPurchasesRepository
+	analyticsRepository ExportAnalyticsRepository
+	assetsRepository    ExportAssetsRepository
+}
+
+func NewExports(router Routerer, purchasesRepository ExportPurchasesRepository, analyticsRepository ExportAnalyticsRepository, assetsRepository ExportAssetsRepository) *Exports {
+	controller := &Exports{
+		purchasesRepository: purchasesRepository,
+		analyticsRepository: analyticsRepository,
+		assetsRepository:    assetsRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/exports/purchases/buyer", web.AuthAccessUser, controller.ExportBuyerHistory, "GET")
+	router.RegisterRestAPIRoute("/v1/exports/purchases/seller", web.AuthAccessUser, controller.ExportSellerHistory, "GET")
+	router.RegisterRestAPIRoute("/v1/exports/analytics/sales", web.AuthAccessUser, controller.ExportSalesTimeSeries, "GET")
+	router.RegisterRestAPIRoute("/v1/exports/analytics/top-items", web.AuthAccessUser, controller.ExportTopItems, "GET")
+	router.RegisterRestAPIRoute("/v1/exports/assets", web.AuthAccessUser, controller.ExportAssets, "GET")
+	router.RegisterRestAPIRoute("/v1/exports/stockpiles/deficits", web.AuthAccessUser, controller.ExportStockpileDeficits, "GET")
+
+	return controller
+}
+
+var purchaseExportColumns = []string{
+	"Purchased At (EVE)",
+	"Transaction ID",
+	"Listing ID",
+	"Type ID",
+	"Item",
+	"Quantity",
+	"Price Per Unit (ISK)",
+	"Total Price (ISK)",
+	"Status",
+	"Buyer User ID",
+	"Seller User ID",
+	"Contract Key",
+	"Notes",
+	"Cancellation Reason",
+	"Cancelled At (EVE)",
+}
+
+func purchaseExportRow(tx *models.PurchaseTransaction) []any {
+	return []any{
+		tx.PurchasedAt,
+		tx.ID,
+		tx.ForSaleItemID,
+		tx.TypeID,
+		tx.TypeName,
+		tx.QuantityPurchased,
+		tx.PricePerUnit,
+		tx.TotalPrice,
+		tx.Status,
+		tx.BuyerUserID,
+		tx.SellerUserID,
+		tx.ContractKey,
+		tx.TransactionNotes,
+		tx.CancellationReason,
+		tx.CancelledAt,
+	}
+}
+
+// ExportBuyerHistory streams the user's purchase history
+func (c *Exports) ExportBuyerHistory(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+	userID := *args.User
+
+	return exportResponse(args, "purchases", "Purchases", purchaseExportColumns, func(write func(values ...any) error) error {
+		return c.purchasesRepository.StreamByBuyer(ctx, userID, func(tx *models.PurchaseTransaction) error {
+			return write(purchaseExportRow(tx)...)
+		})
+	})
+}
+
+// ExportSellerHistory streams the user's sales history
+func (c *Exports) ExportSellerHistory(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+	userID := *args.User
+
+	return exportResponse(args, "sales", "Sales", purchaseExportColumns, func(write func(values ...any) error) error {
+		return c.purchasesRepository.StreamBySeller(ctx, userID, func(tx *models.PurchaseTransaction) error {
+			return write(purchaseExportRow(tx)...)
+		})
+	})
+}
+
+// ExportSalesTimeSeries exports the bucketed sales time series; it takes the same range
+// parameters as /v1/analytics/sales
+func (c *Exports) ExportSalesTimeSeries(args *web.HandlerArgs) (any, *web.HttpError) {
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	metrics, err := c.analyticsRepository.GetSalesMetrics(args.Request.Context(), *args.User, rng)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get sales metrics"),
+		}
+	}
+
+	columns := []string{"Period", "Revenue (ISK)", "Cost (ISK)", "Gross Profit (ISK)", "Transactions", "Quantity Sold"}
+	return exportResponse(args, "sales-analytics", "Sales Analytics", columns, func(write func(values ...any) error) error {
+		for _, ts := range metrics.TimeSeriesData {
+			err := write(ts.Date, ts.Revenue, ts.Cost, ts.GrossProfit, ts.Transactions, ts.QuantitySold)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExportTopItems exports per item sales totals for a range, ordered by ?sort=revenue|profit|margin
+func (c *Exports) ExportTopItems(args *web.HandlerArgs) (any, *web.HttpError) {
+	rng, httpErr := parseAnalyticsRange(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	sortBy := args.Request.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = repositories.TopItemsByRevenue
+	}
+	if !repositories.IsValidTopItemsOrdering(sortBy) {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Errorf("invalid sort %q, expected revenue, profit or margin", sortBy),
+		}
+	}
+
+	items, err := c.analyticsRepository.GetTopItems(args.Request.Context(), *args.User, rng, exportTopItemsLimit, sortBy)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get top items"),
+		}
+	}
+
+	columns := []string{
+		"Type ID",
+		"Item",
+		"Quantity Sold",
+		"Revenue (ISK)",
+		"Transactions",
+		"Average Price (ISK)",
+		"Cost (ISK)",
+		"Gross Profit (ISK)",
+		"Profit Margin",
+		"Uncosted Revenue (ISK)",
+	}
+	return exportResponse(args, "sales-by-item", "Sales by Item", columns, func(write func(values ...any) error) error {
+		for _, item := range items {
+			err := write(item.TypeID, item.TypeName, item.QuantitySold, item.Revenue, item.TransactionCount,
+				item.AveragePricePerUnit, item.TotalCost, item.GrossProfit, item.ProfitMargin, item.UncostedRevenue)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExportAssets exports every asset as one row, with its structure and where in it the asset sits
+func (c *Exports) ExportAssets(args *web.HandlerArgs) (any, *web.HttpError) {
+	assets, err := c.assetsRepository.GetUserAssets(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get user assets"),
+		}
+	}
+
+	columns := []string{
+		"Structure",
+		"Solar System",
+		"Region",
+		"Location",
+		"Type ID",
+		"Item",
+		"Quantity",
+		"Volume (m3)",
+		"Owner Type",
+		"Owner",
+		"Desired Quantity",
+		"Stockpile Delta",
+		"Unit Price (ISK)",
+		"Total Value (ISK)",
+		"Deficit Value (ISK)",
+	}
+	return exportResponse(args, "assets", "Assets", columns, func(write func(values ...any) error) error {
+		for _, structure := range assets.Structures {
+			writeAssets := func(location string, list []*repositories.Asset) error {
+				for _, asset := range list {
+					err := write(structure.Name, structure.SolarSystem, structure.Region, location,
+						asset.TypeID, asset.Name, asset.Quantity, asset.Volume, asset.OwnerType, asset.OwnerName,
+						asset.DesiredQuantity, asset.StockpileDelta, asset.UnitPrice, asset.TotalValue, asset.DeficitValue)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			err := writeAssets("Hangar", structure.HangarAssets)
+			if err != nil {
+				return err
+			}
+			for _, container := range structure.HangarContainers {
+				err = writeAssets("Hangar / "+container.Name, container.Assets)
+				if err != nil {
+					return err
+				}
+			}
+			err = writeAssets("Deliveries", structure.Deliveries)
+			if err != nil {
+				return err
+			}
+			err = writeAssets("Asset Safety", structure.AssetSafety)
+			if err != nil {
+				return err
+			}
+			for _, hanger := range structure.CorporationHangers {
+				location := hanger.CorporationName + " / " + hanger.Name
+				err = writeAssets(location, hanger.Assets)
+				if err != nil {
+					return err
+				}
+				for _, container := range hanger.HangarContainers {
+					err = writeAssets(location+" / "+container.Name, container.Assets)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// ExportStockpileDeficits exports the items below their stockpile markers
+func (c *Exports) ExportStockpileDeficits(args *web.HandlerArgs) (any, *web.HttpError) {
+	stockpiles, err := c.assetsRepository.GetStockpileDeficits(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get stockpile deficits"),
+		}
+	}
+
+	columns := []string{
+		"Scope",
+		"Structure",
+		"Solar System",
+		"Region",
+		"Location ID",
+		"Container",
+		"Type ID",
+		"Item",
+		"Owner Type",
+		"Owner",
+		"Quantity",
+		"Desired Quantity",
+		"Stockpile Delta",
+		"Volume (m3)",
+		"Deficit Value (ISK)",
+	}
+	return exportResponse(args, "stockpile-deficits", "Stockpile Deficits", columns, func(write func(values ...any) error) error {
+		for _, item := range stockpiles.Items {
+			err := write(item.Scope, item.StructureName, item.SolarSystem, item.Region, item.LocationID, item.ContainerName,
+				item.TypeID, item.Name, item.OwnerType, item.OwnerName, item.Quantity, item.DesiredQuantity,
+				item.StockpileDelta, item.Volume, item.DeficitValue)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// exportResponse validates ?format= and returns a stream that writes the header followed by
+// whatever rows produces
+func exportResponse(args *web.HandlerArgs, name, sheet string, columns []string, rows func(write func(values ...any) error) error) (any, *web.HttpError) {
+	format, err := export.ParseFormat(args.Request.URL.Query().Get("format"))
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      err,
+		}
+	}
+
+	return &web.StreamResponse{
+		ContentType: export.ContentType(format),
+		Filename:    export.Filename(name, format, time.Now()),
+		Write: func(w io.Writer) error {
+			writer, err := export.NewWriter(format, w, sheet)
+			if err != nil {
+				return err
+			}
+
+			err = writer.WriteHeader(columns...)
+			if err != nil {
+				return err
+			}
+
+			err = rows(writer.WriteRow)
+			if err != nil {
+				return errors.Wrapf(err, "failed to export %s", name)
+			}
+
+			return writer.Close()
+		},
+	}, nil
+}
@@ -0,0 +1,288 @@
+package controllers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockExportPurchasesRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportPurchasesRepository) StreamByBuyer(ctx context.Context, buyerUserID int64, fn func(*models.PurchaseTransaction) error) error {
+	args := m.Called(ctx, buyerUserID, fn)
+	return args.Error(0)
+}
+
+func (m *MockExportPurchasesRepository) StreamBySeller(ctx context.Context, sellerUserID int64, fn func(*models.PurchaseTransaction) error) error {
+	args := m.Called(ctx, sellerUserID, fn)
+	return args.Error(0)
+}
+
+type MockExportAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockExportAnalyticsRepository) GetSalesMetrics(ctx context.Context, sellerUserID int64, rng repositories.AnalyticsRange) (*models.SalesMetrics, error) {
+	args := m.Called(ctx, sellerUserID, rng)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesMetrics), args.Error(1)
+}
+
+func (m *MockExportAnalyticsRepository) GetTopItems(ctx context.Context, sellerUserID int64, rng repositories.AnalyticsRange, limit int, sortBy string) ([]models.ItemSalesData, error) {
+	args := m.Called(ctx, sellerUserID, rng, limit, sortBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ItemSalesData), args.Error(1)
+}
+
+type exportMocks struct {
+	purchases *MockExportPurchasesRepository
+	analytics *MockExportAnalyticsRepository
+	assets    *MockContactAssetsRepository
+}
+
+func setupExportsController() (*controllers.Exports, exportMocks) {
+	mocks := exportMocks{
+		purchases: new(MockExportPurchasesRepository),
+		analytics: new(MockExportAnalyticsRepository),
+		assets:    new(MockContactAssetsRepository),
+	}
+	controller := controllers.NewExports(&MockRouter{}, mocks.purchases, mocks.analytics, mocks.assets)
+	return controller, mocks
+}
+
+func exportArgs(url string) *web.HandlerArgs {
+	userID := int64(42)
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", url, nil),
+		User:    &userID,
+	}
+}
+
+// streamBody runs a handler's stream into a buffer
+func streamBody(t *testing.T, result any) (*web.StreamResponse, string, error) {
+	stream, ok := result.(*web.StreamResponse)
+	require.True(t, ok, "expected a stream response")
+
+	var buf bytes.Buffer
+	err := stream.Write(&buf)
+	return stream, buf.String(), err
+}
+
+func Test_ExportsController_BuyerHistoryCSV(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	notes := "for the Rorqual"
+	purchases := []*models.PurchaseTransaction{
+		{ID: 2, ForSaleItemID: 20, BuyerUserID: 42, SellerUserID: 7, TypeID: 35, TypeName: "Pyerite", QuantityPurchased: 10, PricePerUnit: 30, TotalPrice: 300, Status: "completed", TransactionNotes: &notes, PurchasedAt: time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC)},
+		{ID: 1, ForSaleItemID: 10, BuyerUserID: 42, SellerUserID: 7, TypeID: 34, TypeName: "Tritanium", QuantityPurchased: 100, PricePerUnit: 5, TotalPrice: 500, Status: "pending", PurchasedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	mocks.purchases.On("StreamByBuyer", mock.Anything, int64(42), mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(*models.PurchaseTransaction) error)
+			for _, purchase := range purchases {
+				require.NoError(t, fn(purchase))
+			}
+		}).
+		Return(nil)
+
+	result, httpErr := controller.ExportBuyerHistory(exportArgs("/v1/exports/purchases/buyer"))
+	require.Nil(t, httpErr)
+
+	stream, body, err := streamBody(t, result)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", stream.ContentType)
+	assert.True(t, strings.HasPrefix(stream.Filename, "purchases-"))
+	assert.True(t, strings.HasSuffix(stream.Filename, ".csv"))
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "Purchased At (EVE),Transaction ID,Listing ID,Type ID,Item,Quantity,Price Per Unit (ISK),Total Price (ISK),Status,Buyer User ID,Seller User ID,Contract Key,Notes,Cancellation Reason,Cancelled At (EVE)", lines[0])
+	assert.Equal(t, "2026-03-02 08:00:00,2,20,35,Pyerite,10,30,300,completed,42,7,,for the Rorqual,,", lines[1])
+	assert.Equal(t, "2026-03-01 12:00:00,1,10,34,Tritanium,100,5,500,pending,42,7,,,,", lines[2])
+	mocks.purchases.AssertExpectations(t)
+}
+
+func Test_ExportsController_SellerHistoryXLSX(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	mocks.purchases.On("StreamBySeller", mock.Anything, int64(42), mock.Anything).Return(nil)
+
+	result, httpErr := controller.ExportSellerHistory(exportArgs("/v1/exports/purchases/seller?format=xlsx"))
+	require.Nil(t, httpErr)
+
+	stream, body, err := streamBody(t, result)
+	require.NoError(t, err)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", stream.ContentType)
+	assert.True(t, strings.HasSuffix(stream.Filename, ".xlsx"))
+
+	archive, err := zip.NewReader(strings.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	assert.Len(t, archive.File, 6)
+}
+
+func Test_ExportsController_InvalidFormat(t *testing.T) {
+	controller, _ := setupExportsController()
+
+	result, httpErr := controller.ExportBuyerHistory(exportArgs("/v1/exports/purchases/buyer?format=pdf"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ExportsController_StreamErrorIsReturned(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	mocks.purchases.On("StreamByBuyer", mock.Anything, int64(42), mock.Anything).Return(errors.New("db down"))
+
+	result, httpErr := controller.ExportBuyerHistory(exportArgs("/v1/exports/purchases/buyer"))
+	require.Nil(t, httpErr)
+
+	_, _, err := streamBody(t, result)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "db down")
+}
+
+func Test_ExportsController_SalesTimeSeries(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	metrics := &models.SalesMetrics{
+		TimeSeriesData: []models.TimeSeriesData{
+			{Date: "2026-03-02", Revenue: 1000, Cost: 600, GrossProfit: 400, Transactions: 2, QuantitySold: 20},
+			{Date: "2026-03-09"},
+		},
+	}
+	mocks.analytics.On("GetSalesMetrics", mock.Anything, int64(42), mock.MatchedBy(func(rng repositories.AnalyticsRange) bool {
+		return rng.Bucket == repositories.BucketWeek && rng.From != nil
+	})).Return(metrics, nil)
+
+	result, httpErr := controller.ExportSalesTimeSeries(exportArgs("/v1/exports/analytics/sales?from=2026-03-01&to=2026-03-15&bucket=week"))
+	require.Nil(t, httpErr)
+
+	_, body, err := streamBody(t, result)
+	require.NoError(t, err)
+	assert.Equal(t, "Period,Revenue (ISK),Cost (ISK),Gross Profit (ISK),Transactions,Quantity Sold\n"+
+		"2026-03-02,1000,600,400,2,20\n"+
+		"2026-03-09,0,0,0,0,0\n", body)
+}
+
+func Test_ExportsController_SalesTimeSeriesInvalidRange(t *testing.T) {
+	controller, _ := setupExportsController()
+
+	result, httpErr := controller.ExportSalesTimeSeries(exportArgs("/v1/exports/analytics/sales?bucket=quarter"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ExportsController_TopItems(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	items := []models.ItemSalesData{
+		{TypeID: 34, TypeName: "Tritanium", QuantitySold: 100, Revenue: 1000, TransactionCount: 2, AveragePricePerUnit: 10, TotalCost: 800, GrossProfit: 200, ProfitMargin: 0.2},
+	}
+	mocks.analytics.On("GetTopItems", mock.Anything, int64(42), mock.Anything, 10000, repositories.TopItemsByProfit).Return(items, nil)
+
+	result, httpErr := controller.ExportTopItems(exportArgs("/v1/exports/analytics/top-items?sort=profit"))
+	require.Nil(t, httpErr)
+
+	_, body, err := streamBody(t, result)
+	require.NoError(t, err)
+	assert.Contains(t, body, "34,Tritanium,100,1000,2,10,800,200,0.2,0\n")
+
+	result, httpErr = controller.ExportTopItems(exportArgs("/v1/exports/analytics/top-items?sort=volume"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ExportsController_Assets(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	unitPrice := 5.5
+	assets := &repositories.AssetsResponse{
+		Structures: []*repositories.AssetStructure{
+			{
+				Name:        "Jita IV - Moon 4",
+				SolarSystem: "Jita",
+				Region:      "The Forge",
+				HangarAssets: []*repositories.Asset{
+					{Name: "Tritanium", TypeID: 34, Quantity: 100, Volume: 1, OwnerType: "character", OwnerName: "Pilot", UnitPrice: &unitPrice},
+				},
+				HangarContainers: []*repositories.AssetContainer{
+					{Name: "Minerals", Assets: []*repositories.Asset{
+						{Name: "Pyerite", TypeID: 35, Quantity: 50, Volume: 0.5, OwnerType: "character", OwnerName: "Pilot"},
+					}},
+				},
+				CorporationHangers: []*repositories.CorporationHanger{
+					{Name: "Division 1", CorporationName: "Corp", Assets: []*repositories.Asset{
+						{Name: "Mexallon", TypeID: 36, Quantity: 10, Volume: 0.1, OwnerType: "corporation", OwnerName: "Corp"},
+					}},
+				},
+			},
+		},
+	}
+	mocks.assets.On("GetUserAssets", mock.Anything, int64(42)).Return(assets, nil)
+
+	result, httpErr := controller.ExportAssets(exportArgs("/v1/exports/assets"))
+	require.Nil(t, httpErr)
+
+	_, body, err := streamBody(t, result)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "Jita IV - Moon 4,Jita,The Forge,Hangar,34,Tritanium,100,1,character,Pilot,,,5.5,,", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "Jita IV - Moon 4,Jita,The Forge,Hangar / Minerals,35,Pyerite,"))
+	assert.True(t, strings.HasPrefix(lines[3], "Jita IV - Moon 4,Jita,The Forge,Corp / Division 1,36,Mexallon,"))
+}
+
+func Test_ExportsController_AssetsError(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	mocks.assets.On("GetUserAssets", mock.Anything, int64(42)).Return(nil, errors.New("db down"))
+
+	result, httpErr := controller.ExportAssets(exportArgs("/v1/exports/assets"))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
+func Test_ExportsController_StockpileDeficits(t *testing.T) {
+	controller, mocks := setupExportsController()
+
+	container := "Fuel"
+	stockpiles := &repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Helium Isotopes", TypeID: 16274, Quantity: 100, Volume: 3, OwnerType: "character", OwnerName: "Pilot", DesiredQuantity: 1000, StockpileDelta: -900, DeficitValue: 450000, StructureName: "Home", SolarSystem: "Amarr", Region: "Domain", LocationID: 60008494, ContainerName: &container, Scope: "location"},
+			{Name: "Tritanium", TypeID: 34, Quantity: 8000, Volume: 80, OwnerType: "all", OwnerName: "All owners", DesiredQuantity: 10000, StockpileDelta: -2000, DeficitValue: 10000, StructureName: "Jita", SolarSystem: "Jita", Region: "The Forge", LocationID: 30000142, Scope: "solar_system"},
+		},
+	}
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(stockpiles, nil)
+
+	result, httpErr := controller.ExportStockpileDeficits(exportArgs("/v1/exports/stockpiles/deficits"))
+	require.Nil(t, httpErr)
+
+	_, body, err := streamBody(t, result)
+	require.NoError(t, err)
+	assert.Contains(t, body, "location,Home,Amarr,Domain,60008494,Fuel,16274,Helium Isotopes,character,Pilot,100,1000,-900,3,450000\n")
+	assert.Contains(t, body, "solar_system,Jita,Jita,The Forge,30000142,,34,Tritanium,all,All owners,8000,10000,-2000,80,10000\n")
+}
@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+// maxImportLines bounds a single paste so a preview stays responsive
+const maxImportLines = 500
+
+type ForSaleImportRepository interface {
+	GetAvailableStock(ctx context.Context, item *models.ForSaleItem) (int64, error)
+	UpsertMany(ctx context.Context, items []*models.ForSaleItem) error
+}
+
+type ForSaleImport struct {
+	repository         ForSaleImportRepository
+	itemTypeRepository ItemTypeRepository
+}
+
+func NewForSaleImport(router Routerer, repository ForSaleImportRepository, itemTypeRepository ItemTypeRepository) *ForSaleImport {
+	controller := &ForSaleImport{
+		repository:         repository,
+		itemTypeRepository: itemTypeRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/for-sale/import/preview", web.AuthAccessUser, controller.PreviewImport, "POST")
+	router.RegisterRestAPIRoute("/v1/for-sale/import", web.AuthAccessUser, controller.Import, "POST")
+
+	return controller
+}
+
+// ForSaleImportRequest lists every pasted item at one location with a shared price
+type ForSaleImportRequest struct {
+	OwnerType      string              `json:"ownerType"`
+	OwnerID        int64               `json:"ownerId"`
+	LocationID     int64               `json:"locationId"`
+	ContainerID    *int64              `json:"containerId"`
+	DivisionNumber *int                `json:"divisionNumber"`
+	PricePerUnit   int64               `json:"pricePerUnit"`
+	PricingRule    *models.PricingRule `json:"pricingRule"`
+	Notes          *string             `json:"notes"`
+	Items          string              `json:"items"`
+}
+
+// PreviewImport resolves and validates a pasted inventory without creating listings
+func (c *ForSaleImport) PreviewImport(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	preview, _, httpErr := c.buildImport(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	return preview, nil
+}
+
+// Import creates listings for every pasted line in one transaction. Nothing is
+// created if any line fails validation.
+func (c *ForSaleImport) Import(args *web.HandlerArgs) (any, *web.HttpError) {
+	if args.User == nil {
+		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
+	}
+
+	preview, items, httpErr := c.buildImport(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	if preview.ErrorCount > 0 {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("%d of %d lines have errors; preview the import to see them", preview.ErrorCount, len(preview.Lines)),
+		}
+	}
+
+	if err := c.repository.UpsertMany(args.Request.Context(), items); err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to import listings")}
+	}
+
+	return items, nil
+}
+
+// buildImport decodes the request and resolves each pasted line against item types
+// and the seller's held stock. Listings are returned only for lines without errors.
+func (c *ForSaleImport) buildImport(args *web.HandlerArgs) (*models.ForSaleImportPreview, []*models.ForSaleItem, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	var req ForSaleImportRequest
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.OwnerType == "" {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.New("ownerType is required")}
+	}
+	if req.OwnerID == 0 {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.New("ownerId is required")}
+	}
+	if req.LocationID == 0 {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.New("locationId is required")}
+	}
+	if req.PricePerUnit < 0 {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.New("pricePerUnit must be non-negative")}
+	}
+	if err := validatePricingRule(req.PricingRule, req.PricePerUnit, "pricePerUnit"); err != nil {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+
+	lines := parseInventoryPaste(req.Items)
+	if len(lines) == 0 {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.New("items is required")}
+	}
+	if len(lines) > maxImportLines {
+		return nil, nil, &web.HttpError{StatusCode: 400, Error: errors.Errorf("too many lines; at most %d item types can be imported at once", maxImportLines)}
+	}
+
+	preview := &models.ForSaleImportPreview{Lines: lines}
+	items := []*models.ForSaleItem{}
+	for _, line := range lines {
+		if line.Error != nil {
+			preview.ErrorCount++
+			continue
+		}
+
+		itemType, err := c.itemTypeRepository.GetItemTypeByName(ctx, line.Name)
+		if err != nil {
+			if errors.Is(err, repositories.ErrItemTypeNotFound) {
+				line.Error = importLineError("unknown item type")
+				preview.ErrorCount++
+				continue
+			}
+			return nil, nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to resolve item type")}
+		}
+		line.TypeID = itemType.TypeID
+
+		item := &models.ForSaleItem{
+			UserID:            *args.User,
+			TypeID:            itemType.TypeID,
+			TypeName:          itemType.TypeName,
+			OwnerType:         req.OwnerType,
+			OwnerID:           req.OwnerID,
+			LocationID:        req.LocationID,
+			ContainerID:       req.ContainerID,
+			DivisionNumber:    req.DivisionNumber,
+			QuantityAvailable: line.Quantity,
+			PricePerUnit:      req.PricePerUnit,
+			PricingRule:       req.PricingRule,
+			Notes:             req.Notes,
+			IsActive:          true,
+		}
+
+		available, err := c.repository.GetAvailableStock(ctx, item)
+		if err != nil {
+			return nil, nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check held stock")}
+		}
+		if available < 0 {
+			available = 0
+		}
+		line.AvailableQuantity = available
+
+		if available == 0 {
+			line.Error = importLineError("not held at this location")
+			preview.ErrorCount++
+			continue
+		}
+		if line.Quantity > available {
+			line.Error = importLineError("only " + strconv.FormatInt(available, 10) + " available at this location")
+			preview.ErrorCount++
+			continue
+		}
+
+		preview.ValidCount++
+		items = append(items, item)
+	}
+
+	return preview, items, nil
+}
+
+// parseInventoryPaste reads the in-game inventory copy format: one item per line
+// with the name and quantity as the first two tab-separated columns. Stacks of
+// the same item are merged, and a blank quantity (e.g. assembled ships) counts as 1.
+func parseInventoryPaste(text string) []*models.ForSaleImportLine {
+	lines := []*models.ForSaleImportLine{}
+	byName := map[string]*models.ForSaleImportLine{}
+
+	for i, raw := range strings.Split(text, "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		columns := strings.Split(raw, "\t")
+		name := strings.TrimSpace(columns[0])
+		line := &models.ForSaleImportLine{
+			LineNumber: i + 1,
+			Name:       name,
+			Quantity:   1,
+		}
+
+		if name == "" {
+			line.Error = importLineError("missing item name")
+			lines = append(lines, line)
+			continue
+		}
+
+		if len(columns) > 1 {
+			quantity, ok := parsePastedQuantity(columns[1])
+			if !ok {
+				line.Error = importLineError("invalid quantity \"" + strings.TrimSpace(columns[1]) + "\"")
+				lines = append(lines, line)
+				continue
+			}
+			line.Quantity = quantity
+		}
+
+		if existing, ok := byName[name]; ok {
+			existing.Quantity += line.Quantity
+			continue
+		}
+
+		byName[name] = line
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// parsePastedQuantity accepts the client's locale-formatted integers ("1,000", "1.000", "1 000")
+func parsePastedQuantity(value string) (int64, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '.', ' ', '\u00a0', '\'':
+			return -1
+		}
+		return r
+	}, value)
+
+	if cleaned == "" {
+		return 1, true
+	}
+
+	quantity, err := strconv.ParseInt(cleaned, 10, 64)
+	if err != nil || quantity <= 0 {
+		return 0, false
+	}
+
+	return quantity, true
+}
+
+func importLineError(message string) *string {
+	return &message
+}
@@ -0,0 +1,195 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockForSaleImportRepository struct {
+	mock.Mock
+}
+
+func (m *MockForSaleImportRepository) GetAvailableStock(ctx context.Context, item *models.ForSaleItem) (int64, error) {
+	args := m.Called(ctx, item)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockForSaleImportRepository) UpsertMany(ctx context.Context, items []*models.ForSaleItem) error {
+	args := m.Called(ctx, items)
+	return args.Error(0)
+}
+
+type MockItemTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockItemTypeRepository) SearchItemTypes(ctx context.Context, query string, filter *models.ItemTypeFilter, limit int) ([]models.EveInventoryType, error) {
+	args := m.Called(ctx, query, filter, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EveInventoryType), args.Error(1)
+}
+
+func (m *MockItemTypeRepository) GetItemTypeByName(ctx context.Context, typeName string) (*models.EveInventoryType, error) {
+	args := m.Called(ctx, typeName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EveInventoryType), args.Error(1)
+}
+
+func importArgs(userID *int64, items string) *web.HandlerArgs {
+	body := map[string]interface{}{
+		"ownerType":    "character",
+		"ownerId":      456,
+		"locationId":   60003760,
+		"pricePerUnit": 10,
+		"items":        items,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/for-sale/import", bytes.NewReader(bodyBytes)),
+		User:    userID,
+		Params:  map[string]string{},
+	}
+}
+
+func typeIDIs(typeID int64) interface{} {
+	return mock.MatchedBy(func(item *models.ForSaleItem) bool {
+		return item.TypeID == typeID
+	})
+}
+
+func Test_ForSaleImport_Preview_ResolvesAndValidatesLines(t *testing.T) {
+	mockRepo := new(MockForSaleImportRepository)
+	mockItemTypes := new(MockItemTypeRepository)
+
+	userID := int64(123)
+
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Tritanium").Return(&models.EveInventoryType{TypeID: 34, TypeName: "Tritanium"}, nil)
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Pyerite").Return(&models.EveInventoryType{TypeID: 35, TypeName: "Pyerite"}, nil)
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Rifter").Return(&models.EveInventoryType{TypeID: 587, TypeName: "Rifter"}, nil)
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Not An Item").Return(nil, repositories.ErrItemTypeNotFound)
+
+	mockRepo.On("GetAvailableStock", mock.Anything, typeIDIs(34)).Return(int64(5000), nil)
+	mockRepo.On("GetAvailableStock", mock.Anything, typeIDIs(35)).Return(int64(100), nil)
+	mockRepo.On("GetAvailableStock", mock.Anything, typeIDIs(587)).Return(int64(0), nil)
+
+	paste := "Tritanium\t1,000\tMineral\t\t\t10 m3\n" +
+		"Pyerite\t500\tMineral\n" +
+		"Not An Item\t5\n" +
+		"Tritanium\t2.000\tMineral\r\n" +
+		"\n" +
+		"Rifter\t\tFrigate\n" +
+		"Mexallon\tlots\n"
+
+	controller := controllers.NewForSaleImport(&MockRouter{}, mockRepo, mockItemTypes)
+	result, httpErr := controller.PreviewImport(importArgs(&userID, paste))
+
+	assert.Nil(t, httpErr)
+	preview := result.(*models.ForSaleImportPreview)
+	assert.Len(t, preview.Lines, 5)
+	assert.Equal(t, 1, preview.ValidCount)
+	assert.Equal(t, 4, preview.ErrorCount)
+
+	// Stacks of the same item are merged
+	assert.Equal(t, "Tritanium", preview.Lines[0].Name)
+	assert.Equal(t, int64(34), preview.Lines[0].TypeID)
+	assert.Equal(t, int64(3000), preview.Lines[0].Quantity)
+	assert.Equal(t, int64(5000), preview.Lines[0].AvailableQuantity)
+	assert.Nil(t, preview.Lines[0].Error)
+
+	assert.Equal(t, "Pyerite", preview.Lines[1].Name)
+	assert.Contains(t, *preview.Lines[1].Error, "only 100 available")
+
+	assert.Equal(t, "Not An Item", preview.Lines[2].Name)
+	assert.Equal(t, "unknown item type", *preview.Lines[2].Error)
+
+	// Blank quantity counts as a single item
+	assert.Equal(t, "Rifter", preview.Lines[3].Name)
+	assert.Equal(t, int64(1), preview.Lines[3].Quantity)
+	assert.Equal(t, "not held at this location", *preview.Lines[3].Error)
+
+	assert.Equal(t, "Mexallon", preview.Lines[4].Name)
+	assert.Equal(t, 7, preview.Lines[4].LineNumber)
+	assert.Contains(t, *preview.Lines[4].Error, "invalid quantity")
+
+	mockRepo.AssertNotCalled(t, "UpsertMany", mock.Anything, mock.Anything)
+}
+
+func Test_ForSaleImport_Import_CreatesAllListings(t *testing.T) {
+	mockRepo := new(MockForSaleImportRepository)
+	mockItemTypes := new(MockItemTypeRepository)
+
+	userID := int64(123)
+
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Tritanium").Return(&models.EveInventoryType{TypeID: 34, TypeName: "Tritanium"}, nil)
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Pyerite").Return(&models.EveInventoryType{TypeID: 35, TypeName: "Pyerite"}, nil)
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(5000), nil)
+	mockRepo.On("UpsertMany", mock.Anything, mock.MatchedBy(func(items []*models.ForSaleItem) bool {
+		return len(items) == 2 &&
+			items[0].TypeID == 34 && items[0].QuantityAvailable == 1000 &&
+			items[1].TypeID == 35 && items[1].QuantityAvailable == 250 &&
+			items[0].UserID == userID && items[0].PricePerUnit == 10 && items[0].LocationID == 60003760
+	})).Return(nil)
+
+	controller := controllers.NewForSaleImport(&MockRouter{}, mockRepo, mockItemTypes)
+	result, httpErr := controller.Import(importArgs(&userID, "Tritanium\t1000\nPyerite\t250\n"))
+
+	assert.Nil(t, httpErr)
+	assert.Len(t, result.([]*models.ForSaleItem), 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ForSaleImport_Import_RejectsWhenAnyLineInvalid(t *testing.T) {
+	mockRepo := new(MockForSaleImportRepository)
+	mockItemTypes := new(MockItemTypeRepository)
+
+	userID := int64(123)
+
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Tritanium").Return(&models.EveInventoryType{TypeID: 34, TypeName: "Tritanium"}, nil)
+	mockItemTypes.On("GetItemTypeByName", mock.Anything, "Not An Item").Return(nil, repositories.ErrItemTypeNotFound)
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(5000), nil)
+
+	controller := controllers.NewForSaleImport(&MockRouter{}, mockRepo, mockItemTypes)
+	result, httpErr := controller.Import(importArgs(&userID, "Tritanium\t1000\nNot An Item\t5\n"))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "1 of 2 lines have errors")
+	mockRepo.AssertNotCalled(t, "UpsertMany", mock.Anything, mock.Anything)
+}
+
+func Test_ForSaleImport_Preview_RequiresItems(t *testing.T) {
+	userID := int64(123)
+
+	controller := controllers.NewForSaleImport(&MockRouter{}, new(MockForSaleImportRepository), new(MockItemTypeRepository))
+	result, httpErr := controller.PreviewImport(importArgs(&userID, "  \n\n"))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "items is required")
+}
+
+func Test_ForSaleImport_Preview_Unauthorized(t *testing.T) {
+	controller := controllers.NewForSaleImport(&MockRouter{}, new(MockForSaleImportRepository), new(MockItemTypeRepository))
+	result, httpErr := controller.PreviewImport(importArgs(nil, "Tritanium\t1"))
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 401, httpErr.StatusCode)
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"strconv"
 
+	"github.com/annymsMthd/industry-tool/internal/events"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
@@ -18,17 +19,27 @@ type ForSaleItemsRepository interface {
 	Delete(ctx context.Context, itemID int64, userID int64) error
 	GetByID(ctx context.Context, itemID int64) (*models.ForSaleItem, error)
 	GetUserIDByCharacterID(ctx context.Context, characterID int64) (int64, error)
+	GetAvailableStock(ctx context.Context, item *models.ForSaleItem) (int64, error)
+}
+
+type ReputationRepository interface {
+	GetForUsers(ctx context.Context, userIDs []int64) (map[int64]*models.Reputation, error)
 }
 
 type ForSaleItems struct {
 	repository            ForSaleItemsRepository
 	permissionsRepository ContactPermissionsRepository
+	reputationRepository  ReputationRepository
+	groupFilter           ContactGroupFilter
+	events                EventPublisher
 }
 
-func NewForSaleItems(router Routerer, repository ForSaleItemsRepository, permissionsRepository ContactPermissionsRepository) *ForSaleItems {
+func NewForSaleItems(router Routerer, repository ForSaleItemsRepository, permissionsRepository ContactPermissionsRepository, reputationRepository ReputationRepository, groupFilter ContactGroupFilter) *ForSaleItems {
 	controller := &ForSaleItems{
 		repository:            repository,
 		permissionsRepository: permissionsRepository,
+		reputationRepository:  reputationRepository,
+		groupFilter:           groupFilter,
 	}
 
 	router.RegisterRestAPIRoute("/v1/for-sale", web.AuthAccessUser, controller.GetMyListings, "GET")
@@ -40,6 +51,12 @@ func NewForSaleItems(router Routerer, repository ForSaleItemsRepository, permiss
 	return controller
 }
 
+// WithEvents publishes listing changes to the listing owner
+func (c *ForSaleItems) WithEvents(publisher EventPublisher) *ForSaleItems {
+	c.events = publisher
+	return c
+}
+
 // getUserID converts the session user (which is the main character's EVE ID) to a user ID
 // Since users.id IS the main character's EVE character ID, we can use it directly
 func (c *ForSaleItems) getUserID(ctx context.Context, sessionUser int64) (int64, error) {
@@ -66,7 +83,8 @@ func (c *ForSaleItems) GetMyListings(args *web.HandlerArgs) (any, *web.HttpError
 	return items, nil
 }
 
-// BrowseListings returns for-sale items from contacts who granted browse permission
+// BrowseListings returns for-sale items from contacts who granted browse permission,
+// optionally limited to a contact group with ?groupId=
 func (c *ForSaleItems) BrowseListings(args *web.HandlerArgs) (any, *web.HttpError) {
 	if args.User == nil {
 		return nil, &web.HttpError{StatusCode: 401, Error: errors.New("unauthorized")}
@@ -78,17 +96,36 @@ func (c *ForSaleItems) BrowseListings(args *web.HandlerArgs) (any, *web.HttpErro
 	}
 
 	// Get list of users who granted this user permission to browse their for-sale items
-	sellerUserIDs, err := c.permissionsRepository.GetUserPermissionsForService(args.Request.Context(), userID, "for_sale_browse")
+	sellerUserIDs, err := c.permissionsRepository.GetUserPermissionsForService(args.Request.Context(), userID, models.ServiceForSaleBrowse)
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get permissions")}
 	}
 
+	// Optionally narrow to the members of one of the user's contact groups
+	sellerUserIDs, httpErr := applyContactGroupFilter(args, c.groupFilter, userID, sellerUserIDs)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
 	// Get browsable items from those sellers
 	items, err := c.repository.GetBrowsableItems(args.Request.Context(), userID, sellerUserIDs)
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get browsable items")}
 	}
 
+	if len(items) > 0 {
+		reputations, err := c.reputationRepository.GetForUsers(args.Request.Context(), sellerUserIDs)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get seller reputation")}
+		}
+
+		for _, item := range items {
+			if reputation, ok := reputations[item.UserID]; ok {
+				item.SellerReputation = &reputation.AsSeller
+			}
+		}
+	}
+
 	return items, nil
 }
 
@@ -104,15 +141,17 @@ func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError
 	}
 
 	var req struct {
-		TypeID            int64   `json:"typeId"`
-		OwnerType         string  `json:"ownerType"`
-		OwnerID           int64   `json:"ownerId"`
-		LocationID        int64   `json:"locationId"`
-		ContainerID       *int64  `json:"containerId"`
-		DivisionNumber    *int    `json:"divisionNumber"`
-		QuantityAvailable int64   `json:"quantityAvailable"`
-		PricePerUnit      int64   `json:"pricePerUnit"`
-		Notes             *string `json:"notes"`
+		TypeID            int64               `json:"typeId"`
+		OwnerType         string              `json:"ownerType"`
+		OwnerID           int64               `json:"ownerId"`
+		LocationID        int64               `json:"locationId"`
+		ContainerID       *int64              `json:"containerId"`
+		DivisionNumber    *int                `json:"divisionNumber"`
+		QuantityAvailable int64               `json:"quantityAvailable"`
+		PricePerUnit      int64               `json:"pricePerUnit"`
+		PricingRule       *models.PricingRule `json:"pricingRule"`
+		BuildCostPerUnit  *int64              `json:"buildCostPerUnit"`
+		Notes             *string             `json:"notes"`
 	}
 
 	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
@@ -138,6 +177,12 @@ func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError
 	if req.PricePerUnit < 0 {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("pricePerUnit must be non-negative")}
 	}
+	if err := validatePricingRule(req.PricingRule, req.PricePerUnit, "pricePerUnit"); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+	if req.BuildCostPerUnit != nil && *req.BuildCostPerUnit < 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("buildCostPerUnit must be non-negative")}
+	}
 
 	item := &models.ForSaleItem{
 		UserID:            userID,
@@ -149,14 +194,22 @@ func (c *ForSaleItems) CreateListing(args *web.HandlerArgs) (any, *web.HttpError
 		DivisionNumber:    req.DivisionNumber,
 		QuantityAvailable: req.QuantityAvailable,
 		PricePerUnit:      req.PricePerUnit,
+		PricingRule:       req.PricingRule,
+		BuildCostPerUnit:  req.BuildCostPerUnit,
 		Notes:             req.Notes,
 		IsActive:          true,
 	}
 
+	if httpErr := c.checkStock(args.Request.Context(), item); httpErr != nil {
+		return nil, httpErr
+	}
+
 	if err := c.repository.Upsert(args.Request.Context(), item); err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to create listing")}
 	}
 
+	publishEvent(args.Request.Context(), c.events, events.ListingCreated, userID, item)
+
 	return item, nil
 }
 
@@ -195,10 +248,12 @@ func (c *ForSaleItems) UpdateListing(args *web.HandlerArgs) (any, *web.HttpError
 	}
 
 	var req struct {
-		QuantityAvailable int64   `json:"quantityAvailable"`
-		PricePerUnit      int64   `json:"pricePerUnit"`
-		Notes             *string `json:"notes"`
-		IsActive          *bool   `json:"isActive"`
+		QuantityAvailable int64              `json:"quantityAvailable"`
+		PricePerUnit      int64              `json:"pricePerUnit"`
+		PricingRule       pricingRuleUpdate  `json:"pricingRule"`
+		BuildCostPerUnit  fieldUpdate[int64] `json:"buildCostPerUnit"`
+		Notes             *string            `json:"notes"`
+		IsActive          *bool              `json:"isActive"`
 	}
 
 	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
@@ -212,19 +267,39 @@ func (c *ForSaleItems) UpdateListing(args *web.HandlerArgs) (any, *web.HttpError
 	if req.PricePerUnit < 0 {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("pricePerUnit must be non-negative")}
 	}
+	if err := validatePricingRule(req.PricingRule.apply(existingItem.PricingRule), req.PricePerUnit, "pricePerUnit"); err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: err}
+	}
+	if req.BuildCostPerUnit.value != nil && *req.BuildCostPerUnit.value < 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("buildCostPerUnit must be non-negative")}
+	}
+
+	// Only re-check stock when more is being promised; price and note edits
+	// shouldn't be blocked by a listing the last asset sync already trimmed
+	quantityIncreased := req.QuantityAvailable > existingItem.QuantityAvailable
 
 	// Update fields
 	existingItem.QuantityAvailable = req.QuantityAvailable
 	existingItem.PricePerUnit = req.PricePerUnit
+	existingItem.PricingRule = req.PricingRule.apply(existingItem.PricingRule)
+	existingItem.BuildCostPerUnit = req.BuildCostPerUnit.apply(existingItem.BuildCostPerUnit)
 	existingItem.Notes = req.Notes
 	if req.IsActive != nil {
 		existingItem.IsActive = *req.IsActive
 	}
 
+	if quantityIncreased {
+		if httpErr := c.checkStock(args.Request.Context(), existingItem); httpErr != nil {
+			return nil, httpErr
+		}
+	}
+
 	if err := c.repository.Upsert(args.Request.Context(), existingItem); err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update listing")}
 	}
 
+	publishEvent(args.Request.Context(), c.events, events.ListingUpdated, userID, existingItem)
+
 	return existingItem, nil
 }
 
@@ -256,5 +331,24 @@ func (c *ForSaleItems) DeleteListing(args *web.HandlerArgs) (any, *web.HttpError
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to delete listing")}
 	}
 
+	publishEvent(args.Request.Context(), c.events, events.ListingDeleted, userID, map[string]int64{"id": itemID})
+
 	return nil, nil
 }
+
+// checkStock rejects listings that promise more than the owner actually holds at the location
+func (c *ForSaleItems) checkStock(ctx context.Context, item *models.ForSaleItem) *web.HttpError {
+	available, err := c.repository.GetAvailableStock(ctx, item)
+	if err != nil {
+		return &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check held stock")}
+	}
+
+	if item.QuantityAvailable > available {
+		if available < 0 {
+			available = 0
+		}
+		return &web.HttpError{StatusCode: 400, Error: errors.Errorf("quantityAvailable exceeds stock held at this location (%d available)", available)}
+	}
+
+	return nil
+}
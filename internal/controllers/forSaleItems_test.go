@@ -59,6 +59,11 @@ func (m *MockForSaleItemsRepository) GetUserIDByCharacterID(ctx context.Context,
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockForSaleItemsRepository) GetAvailableStock(ctx context.Context, item *models.ForSaleItem) (int64, error) {
+	args := m.Called(ctx, item)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func Test_ForSaleItemsController_GetMyListings_Success(t *testing.T) {
 	mockRepo := new(MockForSaleItemsRepository)
 	mockRouter := &MockRouter{}
@@ -91,7 +96,7 @@ func Test_ForSaleItemsController_GetMyListings_Success(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.GetMyListings(args)
 
 	assert.Nil(t, httpErr)
@@ -120,7 +125,7 @@ func Test_ForSaleItemsController_GetMyListings_RepositoryError(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.GetMyListings(args)
 
 	assert.Nil(t, result)
@@ -130,12 +135,97 @@ func Test_ForSaleItemsController_GetMyListings_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func Test_ForSaleItemsController_BrowseListings_IncludesSellerReputation(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockPermissions := new(MockContactPermissionsRepository)
+	mockReputation := new(MockReputationRepository)
+
+	userID := int64(123)
+	sellerIDs := []int64{456, 789}
+
+	mockPermissions.On("GetUserPermissionsForService", mock.Anything, userID, "for_sale_browse").Return(sellerIDs, nil)
+	mockRepo.On("GetBrowsableItems", mock.Anything, userID, sellerIDs).Return([]*models.ForSaleItem{
+		{ID: 1, UserID: 456, TypeID: 34, QuantityAvailable: 100, PricePerUnit: 5},
+		{ID: 2, UserID: 789, TypeID: 35, QuantityAvailable: 50, PricePerUnit: 10},
+	}, nil)
+	mockReputation.On("GetForUsers", mock.Anything, sellerIDs).Return(map[int64]*models.Reputation{
+		456: {UserID: 456, AsSeller: models.ReputationStats{Transactions: 4, Completed: 3, CompletionRate: 0.75, ExpiredRate: 0.25}},
+		789: {UserID: 789},
+	}, nil)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/for-sale/browse", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, mockPermissions, mockReputation, &MockContactGroupFilter{})
+	result, httpErr := controller.BrowseListings(args)
+
+	assert.Nil(t, httpErr)
+	items := result.([]*models.ForSaleItem)
+	assert.Len(t, items, 2)
+	assert.Equal(t, 0.75, items[0].SellerReputation.CompletionRate)
+	assert.Equal(t, int64(4), items[0].SellerReputation.Transactions)
+	assert.Equal(t, int64(0), items[1].SellerReputation.Transactions)
+
+	mockRepo.AssertExpectations(t)
+	mockReputation.AssertExpectations(t)
+}
+
+func Test_ForSaleItemsController_BrowseListings_FiltersByContactGroup(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockPermissions := new(MockContactPermissionsRepository)
+	mockReputation := new(MockReputationRepository)
+	mockGroups := new(MockContactGroupFilter)
+
+	userID := int64(123)
+
+	mockPermissions.On("GetUserPermissionsForService", mock.Anything, userID, "for_sale_browse").Return([]int64{456, 789}, nil)
+	mockGroups.On("GetMemberUserIDs", mock.Anything, int64(9), userID).Return([]int64{789, 999}, nil)
+	mockRepo.On("GetBrowsableItems", mock.Anything, userID, []int64{789}).Return([]*models.ForSaleItem{}, nil)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/for-sale/browse?groupId=9", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, mockPermissions, mockReputation, mockGroups)
+	result, httpErr := controller.BrowseListings(args)
+
+	assert.Nil(t, httpErr)
+	assert.Empty(t, result)
+	mockRepo.AssertExpectations(t)
+	mockGroups.AssertExpectations(t)
+}
+
+func Test_ForSaleItemsController_BrowseListings_InvalidGroupID(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockPermissions := new(MockContactPermissionsRepository)
+
+	userID := int64(123)
+
+	mockPermissions.On("GetUserPermissionsForService", mock.Anything, userID, "for_sale_browse").Return([]int64{456}, nil)
+
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/for-sale/browse?groupId=abc", nil),
+		User:    &userID,
+	}
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, mockPermissions, &MockReputationRepository{}, &MockContactGroupFilter{})
+	_, httpErr := controller.BrowseListings(args)
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "GetBrowsableItems", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func Test_ForSaleItemsController_CreateListing_Success(t *testing.T) {
 	mockRepo := new(MockForSaleItemsRepository)
 	mockRouter := &MockRouter{}
 
 	userID := int64(123)
 
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(1500), nil)
 	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
 		return item.UserID == userID &&
 			item.TypeID == 34 &&
@@ -162,7 +252,7 @@ func Test_ForSaleItemsController_CreateListing_Success(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, httpErr)
@@ -186,7 +276,7 @@ func Test_ForSaleItemsController_CreateListing_InvalidJSON(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, result)
@@ -220,7 +310,7 @@ func Test_ForSaleItemsController_CreateListing_MissingRequiredFields(t *testing.
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, result)
@@ -256,7 +346,7 @@ func Test_ForSaleItemsController_CreateListing_InvalidQuantity(t *testing.T) {
 		Params:  map[string]string{},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.CreateListing(args)
 
 	assert.Nil(t, result)
@@ -265,6 +355,306 @@ func Test_ForSaleItemsController_CreateListing_InvalidQuantity(t *testing.T) {
 	assert.Contains(t, httpErr.Error.Error(), "quantityAvailable must be greater than 0")
 }
 
+func Test_ForSaleItemsController_CreateListing_ExceedsHeldStock(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
+		return item.OwnerID == 456 && item.LocationID == 30000142 && item.TypeID == 34
+	})).Return(int64(400), nil)
+
+	body := map[string]interface{}{
+		"typeId":            34,
+		"ownerType":         "character",
+		"ownerId":           456,
+		"locationId":        30000142,
+		"quantityAvailable": 1000,
+		"pricePerUnit":      50,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+	result, httpErr := controller.CreateListing(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "400 available")
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_ForSaleItemsController_CreateListing_WithPricingRule(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(1500), nil)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
+		return item.PricingRule != nil &&
+			item.PricingRule.Basis == "split" &&
+			item.PricingRule.Percent == 95 &&
+			item.PricingRule.Floor != nil && *item.PricingRule.Floor == 40 &&
+			item.PricingRule.Ceiling == nil &&
+			item.PricePerUnit == 50
+	})).Return(nil)
+
+	body := map[string]interface{}{
+		"typeId":            34,
+		"ownerType":         "character",
+		"ownerId":           456,
+		"locationId":        30000142,
+		"quantityAvailable": 1000,
+		"pricePerUnit":      50,
+		"pricingRule": map[string]interface{}{
+			"basis":   "split",
+			"percent": 95,
+			"floor":   40,
+		},
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+	result, httpErr := controller.CreateListing(args)
+
+	assert.Nil(t, httpErr)
+	assert.NotNil(t, result)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ForSaleItemsController_CreateListing_InvalidPricingRule(t *testing.T) {
+	userID := int64(123)
+
+	testCases := []struct {
+		name        string
+		rule        map[string]interface{}
+		expectedErr string
+	}{
+		{"unknown basis", map[string]interface{}{"basis": "average", "percent": 90}, "basis must be one of"},
+		{"zero percent", map[string]interface{}{"basis": "sell", "percent": 0}, "percent must be greater than 0"},
+		{"percent too high", map[string]interface{}{"basis": "sell", "percent": 5000}, "at most 1000"},
+		{"floor above ceiling", map[string]interface{}{"basis": "buy", "percent": 90, "floor": 100, "ceiling": 50}, "floor must not exceed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockForSaleItemsRepository)
+
+			body := map[string]interface{}{
+				"typeId":            34,
+				"ownerType":         "character",
+				"ownerId":           456,
+				"locationId":        30000142,
+				"quantityAvailable": 1000,
+				"pricePerUnit":      50,
+				"pricingRule":       tc.rule,
+			}
+			bodyBytes, _ := json.Marshal(body)
+
+			req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(bodyBytes))
+			args := &web.HandlerArgs{
+				Request: req,
+				User:    &userID,
+				Params:  map[string]string{},
+			}
+
+			controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+			result, httpErr := controller.CreateListing(args)
+
+			assert.Nil(t, result)
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			assert.Contains(t, httpErr.Error.Error(), tc.expectedErr)
+			mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_ForSaleItemsController_CreateListing_PricingRuleNeedsFallback(t *testing.T) {
+	userID := int64(123)
+
+	testCases := []struct {
+		name     string
+		rule     map[string]interface{}
+		expected int
+	}{
+		{"no price and no floor", map[string]interface{}{"basis": "sell", "percent": 90}, 400},
+		{"zero floor", map[string]interface{}{"basis": "sell", "percent": 90, "floor": 0}, 400},
+		{"floor", map[string]interface{}{"basis": "sell", "percent": 90, "floor": 40}, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockForSaleItemsRepository)
+			mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(1000), nil)
+			mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+			body := map[string]interface{}{
+				"typeId":            34,
+				"ownerType":         "character",
+				"ownerId":           456,
+				"locationId":        30000142,
+				"quantityAvailable": 1000,
+				"pricePerUnit":      0,
+				"pricingRule":       tc.rule,
+			}
+			bodyBytes, _ := json.Marshal(body)
+
+			args := &web.HandlerArgs{
+				Request: httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(bodyBytes)),
+				User:    &userID,
+				Params:  map[string]string{},
+			}
+
+			controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+			_, httpErr := controller.CreateListing(args)
+
+			if tc.expected == 0 {
+				assert.Nil(t, httpErr)
+				mockRepo.AssertCalled(t, "Upsert", mock.Anything, mock.Anything)
+			} else {
+				assert.NotNil(t, httpErr)
+				assert.Equal(t, tc.expected, httpErr.StatusCode)
+				assert.Contains(t, httpErr.Error.Error(), "positive pricePerUnit or pricingRule.floor")
+				mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func Test_ForSaleItemsController_UpdateListing_KeptRuleNeedsFallback(t *testing.T) {
+	userID := int64(123)
+	itemID := int64(1)
+	mockRepo := new(MockForSaleItemsRepository)
+
+	mockRepo.On("GetByID", mock.Anything, itemID).Return(&models.ForSaleItem{
+		ID:                itemID,
+		UserID:            userID,
+		TypeID:            34,
+		QuantityAvailable: 1000,
+		PricePerUnit:      50,
+		PricingRule:       &models.PricingRule{Basis: "sell", Percent: 90},
+		IsActive:          true,
+	}, nil)
+
+	// The stored rule stays, so dropping the fixed price to 0 leaves it nothing to fall back on
+	req := httptest.NewRequest("PUT", "/v1/for-sale/1", bytes.NewReader([]byte(`{"quantityAvailable": 1000, "pricePerUnit": 0}`)))
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "1"},
+	}
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+	_, httpErr := controller.UpdateListing(args)
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_ForSaleItemsController_CreateListing_NegativeBuildCost(t *testing.T) {
+	userID := int64(123)
+	mockRepo := new(MockForSaleItemsRepository)
+
+	body := map[string]interface{}{
+		"typeId":            34,
+		"ownerType":         "character",
+		"ownerId":           456,
+		"locationId":        30000142,
+		"quantityAvailable": 1000,
+		"pricePerUnit":      50,
+		"buildCostPerUnit":  -1,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("POST", "/v1/for-sale", bytes.NewReader(bodyBytes))
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{},
+	}
+
+	controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+	result, httpErr := controller.CreateListing(args)
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.Contains(t, httpErr.Error.Error(), "buildCostPerUnit")
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_ForSaleItemsController_UpdateListing_DecreaseSkipsStockCheck(t *testing.T) {
+	mockRepo := new(MockForSaleItemsRepository)
+	mockRouter := &MockRouter{}
+
+	userID := int64(123)
+	itemID := int64(1)
+
+	existingItem := &models.ForSaleItem{
+		ID:                itemID,
+		UserID:            userID,
+		TypeID:            34,
+		OwnerType:         "character",
+		OwnerID:           456,
+		LocationID:        30000142,
+		QuantityAvailable: 1000,
+		PricePerUnit:      50,
+		IsActive:          true,
+	}
+
+	mockRepo.On("GetByID", mock.Anything, itemID).Return(existingItem, nil)
+	mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+
+	body := map[string]interface{}{
+		"quantityAvailable": 500,
+		"pricePerUnit":      60,
+	}
+	bodyBytes, _ := json.Marshal(body)
+
+	req := httptest.NewRequest("PUT", "/v1/for-sale/1", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	args := &web.HandlerArgs{
+		Request: req,
+		User:    &userID,
+		Params:  map[string]string{"id": "1"},
+	}
+
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+	result, httpErr := controller.UpdateListing(args)
+
+	assert.Nil(t, httpErr)
+	assert.NotNil(t, result)
+
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetAvailableStock", mock.Anything, mock.Anything)
+}
+
 func Test_ForSaleItemsController_UpdateListing_Success(t *testing.T) {
 	mockRepo := new(MockForSaleItemsRepository)
 	mockRouter := &MockRouter{}
@@ -286,6 +676,7 @@ func Test_ForSaleItemsController_UpdateListing_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetByID", mock.Anything, itemID).Return(existingItem, nil)
+	mockRepo.On("GetAvailableStock", mock.Anything, mock.Anything).Return(int64(2000), nil)
 	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
 		return item.ID == itemID &&
 			item.QuantityAvailable == 2000 &&
@@ -307,7 +698,7 @@ func Test_ForSaleItemsController_UpdateListing_Success(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.UpdateListing(args)
 
 	assert.Nil(t, httpErr)
@@ -316,6 +707,107 @@ func Test_ForSaleItemsController_UpdateListing_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func Test_ForSaleItemsController_UpdateListing_PricingRule(t *testing.T) {
+	userID := int64(123)
+	itemID := int64(1)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected *models.PricingRule
+	}{
+		{"omitted keeps the rule", `{"quantityAvailable": 1000, "pricePerUnit": 60}`, &models.PricingRule{Basis: "sell", Percent: 90}},
+		{"null clears the rule", `{"quantityAvailable": 1000, "pricePerUnit": 60, "pricingRule": null}`, nil},
+		{"replaced", `{"quantityAvailable": 1000, "pricePerUnit": 60, "pricingRule": {"basis": "buy", "percent": 110}}`, &models.PricingRule{Basis: "buy", Percent: 110}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockForSaleItemsRepository)
+
+			existingItem := &models.ForSaleItem{
+				ID:                itemID,
+				UserID:            userID,
+				TypeID:            34,
+				QuantityAvailable: 1000,
+				PricePerUnit:      50,
+				PricingRule:       &models.PricingRule{Basis: "sell", Percent: 90},
+				IsActive:          true,
+			}
+
+			mockRepo.On("GetByID", mock.Anything, itemID).Return(existingItem, nil)
+			mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
+				return assert.ObjectsAreEqual(tt.expected, item.PricingRule)
+			})).Return(nil)
+
+			req := httptest.NewRequest("PUT", "/v1/for-sale/1", bytes.NewReader([]byte(tt.body)))
+			args := &web.HandlerArgs{
+				Request: req,
+				User:    &userID,
+				Params:  map[string]string{"id": "1"},
+			}
+
+			controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+			_, httpErr := controller.UpdateListing(args)
+
+			assert.Nil(t, httpErr)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_ForSaleItemsController_UpdateListing_BuildCost(t *testing.T) {
+	userID := int64(123)
+	itemID := int64(1)
+	storedCost := int64(40)
+	newCost := int64(45)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected *int64
+	}{
+		{"omitted keeps the build cost", `{"quantityAvailable": 1000, "pricePerUnit": 60}`, &storedCost},
+		{"null clears the build cost", `{"quantityAvailable": 1000, "pricePerUnit": 60, "buildCostPerUnit": null}`, nil},
+		{"replaced", `{"quantityAvailable": 1000, "pricePerUnit": 60, "buildCostPerUnit": 45}`, &newCost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockForSaleItemsRepository)
+
+			cost := storedCost
+			existingItem := &models.ForSaleItem{
+				ID:                itemID,
+				UserID:            userID,
+				TypeID:            34,
+				QuantityAvailable: 1000,
+				PricePerUnit:      50,
+				BuildCostPerUnit:  &cost,
+				IsActive:          true,
+			}
+
+			mockRepo.On("GetByID", mock.Anything, itemID).Return(existingItem, nil)
+			mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(item *models.ForSaleItem) bool {
+				return assert.ObjectsAreEqual(tt.expected, item.BuildCostPerUnit)
+			})).Return(nil)
+
+			req := httptest.NewRequest("PUT", "/v1/for-sale/1", bytes.NewReader([]byte(tt.body)))
+			args := &web.HandlerArgs{
+				Request: req,
+				User:    &userID,
+				Params:  map[string]string{"id": "1"},
+			}
+
+			controller := controllers.NewForSaleItems(&MockRouter{}, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
+			_, httpErr := controller.UpdateListing(args)
+
+			assert.Nil(t, httpErr)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func Test_ForSaleItemsController_UpdateListing_NotFound(t *testing.T) {
 	mockRepo := new(MockForSaleItemsRepository)
 	mockRouter := &MockRouter{}
@@ -340,7 +832,7 @@ func Test_ForSaleItemsController_UpdateListing_NotFound(t *testing.T) {
 		Params:  map[string]string{"id": "999"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.UpdateListing(args)
 
 	assert.Nil(t, result)
@@ -383,7 +875,7 @@ func Test_ForSaleItemsController_UpdateListing_NotOwner(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.UpdateListing(args)
 
 	assert.Nil(t, result)
@@ -410,7 +902,7 @@ func Test_ForSaleItemsController_DeleteListing_Success(t *testing.T) {
 		Params:  map[string]string{"id": "1"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.DeleteListing(args)
 
 	assert.Nil(t, httpErr)
@@ -436,7 +928,7 @@ func Test_ForSaleItemsController_DeleteListing_NotFound(t *testing.T) {
 		Params:  map[string]string{"id": "999"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.DeleteListing(args)
 
 	assert.Nil(t, result)
@@ -461,7 +953,7 @@ func Test_ForSaleItemsController_DeleteListing_InvalidID(t *testing.T) {
 		Params:  map[string]string{"id": "invalid"},
 	}
 
-	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{})
+	controller := controllers.NewForSaleItems(mockRouter, mockRepo, &MockContactPermissionsRepository{}, &MockReputationRepository{}, &MockContactGroupFilter{})
 	result, httpErr := controller.DeleteListing(args)
 
 	assert.Nil(t, result)
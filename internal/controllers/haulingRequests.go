@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type HaulingRequestsRepository interface {
+	Create(ctx context.Context, request *models.HaulingRequest) error
+	GetByID(ctx context.Context, id int64) (*models.HaulingRequest, error)
+	GetByUser(ctx context.Context, userID int64) ([]*models.HaulingRequest, error)
+	UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error
+}
+
+type HaulingRequestsController struct {
+	repository HaulingRequestsRepository
+	permRepo   ContactPermissionsRepository
+}
+
+func NewHaulingRequests(router Routerer, repository HaulingRequestsRepository, permRepo ContactPermissionsRepository) *HaulingRequestsController {
+	controller := &HaulingRequestsController{
+		repository: repository,
+		permRepo:   permRepo,
+	}
+
+	router.RegisterRestAPIRoute("/v1/hauling-requests", web.AuthAccessUser, controller.GetRequests, "GET")
+	router.RegisterRestAPIRoute("/v1/hauling-requests", web.AuthAccessUser, controller.CreateRequest, "POST")
+	router.RegisterRestAPIRoute("/v1/hauling-requests/{id}/status", web.AuthAccessUser, controller.UpdateStatus, "POST")
+
+	log.Info("registering hauling requests controller", "endpoints", []string{
+		"/v1/hauling-requests GET",
+		"/v1/hauling-requests POST",
+		"/v1/hauling-requests/{id}/status POST",
+	})
+
+	return controller
+}
+
+// GetRequests returns the hauling requests the user sent or received
+func (c *HaulingRequestsController) GetRequests(args *web.HandlerArgs) (any, *web.HttpError) {
+	requests, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get hauling requests"),
+		}
+	}
+
+	return requests, nil
+}
+
+// CreateRequest sends a hauling request to a user who granted hauling_request to the caller
+func (c *HaulingRequestsController) CreateRequest(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	var req struct {
+		HaulerUserID          int64   `json:"haulerUserId"`
+		OriginLocationID      int64   `json:"originLocationId"`
+		DestinationLocationID int64   `json:"destinationLocationId"`
+		VolumeM3              float64 `json:"volumeM3"`
+		Collateral            int64   `json:"collateral"`
+		Notes                 *string `json:"notes"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	if req.HaulerUserID == 0 || req.HaulerUserID == *args.User {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("haulerUserId must be another user"),
+		}
+	}
+	if req.OriginLocationID == 0 || req.DestinationLocationID == 0 || req.OriginLocationID == req.DestinationLocationID {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("originLocationId and destinationLocationId must be two different locations"),
+		}
+	}
+	if req.VolumeM3 <= 0 {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("volumeM3 must be positive"),
+		}
+	}
+	if req.Collateral < 0 {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("collateral must be non-negative"),
+		}
+	}
+
+	hasPermission, err := c.permRepo.CheckPermission(ctx, req.HaulerUserID, *args.User, models.ServiceHaulingRequest)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to check permission"),
+		}
+	}
+	if !hasPermission {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusForbidden,
+			Error:      errors.New("you do not have permission to send hauling requests to this user"),
+		}
+	}
+
+	request := &models.HaulingRequest{
+		RequesterUserID:       *args.User,
+		HaulerUserID:          req.HaulerUserID,
+		OriginLocationID:      req.OriginLocationID,
+		DestinationLocationID: req.DestinationLocationID,
+		VolumeM3:              req.VolumeM3,
+		Collateral:            req.Collateral,
+		Notes:                 req.Notes,
+	}
+	if err := c.repository.Create(ctx, request); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to create hauling request"),
+		}
+	}
+
+	return request, nil
+}
+
+// UpdateStatus moves a hauling request along, following the same rules as build requests
+func (c *HaulingRequestsController) UpdateStatus(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.New("invalid hauling request ID"),
+		}
+	}
+
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(args.Request.Body).Decode(&req); err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	request, err := c.repository.GetByID(ctx, id)
+	if errors.Is(err, repositories.ErrHaulingRequestNotFound) {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: err}
+	}
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to get hauling request"),
+		}
+	}
+
+	// Requests between other users are reported as missing
+	if request.RequesterUserID != *args.User && request.HaulerUserID != *args.User {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: repositories.ErrHaulingRequestNotFound}
+	}
+
+	if !serviceRequestTransitionAllowed(request.Status, req.Status, request.HaulerUserID == *args.User) {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Errorf("cannot move a %s hauling request to %q", request.Status, req.Status),
+		}
+	}
+
+	err = c.repository.UpdateStatus(ctx, id, request.Status, req.Status)
+	if errors.Is(err, repositories.ErrHaulingRequestStatusChanged) {
+		return nil, &web.HttpError{StatusCode: http.StatusConflict, Error: err}
+	}
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusInternalServerError,
+			Error:      errors.Wrap(err, "failed to update hauling request"),
+		}
+	}
+
+	request.Status = req.Status
+	return request, nil
+}
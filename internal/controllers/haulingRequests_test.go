@@ -0,0 +1,156 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockHaulingRequestsRepository struct {
+	mock.Mock
+}
+
+func (m *MockHaulingRequestsRepository) Create(ctx context.Context, request *models.HaulingRequest) error {
+	args := m.Called(ctx, request)
+	return args.Error(0)
+}
+
+func (m *MockHaulingRequestsRepository) GetByID(ctx context.Context, id int64) (*models.HaulingRequest, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HaulingRequest), args.Error(1)
+}
+
+func (m *MockHaulingRequestsRepository) GetByUser(ctx context.Context, userID int64) ([]*models.HaulingRequest, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.HaulingRequest), args.Error(1)
+}
+
+func (m *MockHaulingRequestsRepository) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	args := m.Called(ctx, id, fromStatus, toStatus)
+	return args.Error(0)
+}
+
+func haulingRequestArgs(userID int64, body any, params map[string]string) *web.HandlerArgs {
+	bodyBytes, _ := json.Marshal(body)
+	req := httptest.NewRequest("POST", "/v1/hauling-requests", bytes.NewReader(bodyBytes))
+	return &web.HandlerArgs{Request: req, User: &userID, Params: params}
+}
+
+func Test_HaulingRequests_CreateRequest_Success(t *testing.T) {
+	repo := new(MockHaulingRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	perms.On("CheckPermission", mock.Anything, int64(200), int64(100), models.ServiceHaulingRequest).Return(true, nil)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(r *models.HaulingRequest) bool {
+		return r.RequesterUserID == 100 && r.HaulerUserID == 200 &&
+			r.OriginLocationID == 60003760 && r.DestinationLocationID == 60008494 &&
+			r.VolumeM3 == 12500 && r.Collateral == 1000000000
+	})).Return(nil)
+
+	controller := controllers.NewHaulingRequests(&MockRouter{}, repo, perms)
+	result, httpErr := controller.CreateRequest(haulingRequestArgs(100, map[string]any{
+		"haulerUserId":          200,
+		"originLocationId":      60003760,
+		"destinationLocationId": 60008494,
+		"volumeM3":              12500,
+		"collateral":            1000000000,
+	}, nil))
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(200), result.(*models.HaulingRequest).HaulerUserID)
+	repo.AssertExpectations(t)
+	perms.AssertExpectations(t)
+}
+
+func Test_HaulingRequests_CreateRequest_WithoutPermission(t *testing.T) {
+	repo := new(MockHaulingRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	perms.On("CheckPermission", mock.Anything, int64(200), int64(100), models.ServiceHaulingRequest).Return(false, nil)
+
+	controller := controllers.NewHaulingRequests(&MockRouter{}, repo, perms)
+	_, httpErr := controller.CreateRequest(haulingRequestArgs(100, map[string]any{
+		"haulerUserId":          200,
+		"originLocationId":      60003760,
+		"destinationLocationId": 60008494,
+		"volumeM3":              12500,
+	}, nil))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 403, httpErr.StatusCode)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_HaulingRequests_CreateRequest_Validation(t *testing.T) {
+	cases := map[string]map[string]any{
+		"self":                {"haulerUserId": 100, "originLocationId": 60003760, "destinationLocationId": 60008494, "volumeM3": 10},
+		"same locations":      {"haulerUserId": 200, "originLocationId": 60003760, "destinationLocationId": 60003760, "volumeM3": 10},
+		"missing destination": {"haulerUserId": 200, "originLocationId": 60003760, "volumeM3": 10},
+		"zero volume":         {"haulerUserId": 200, "originLocationId": 60003760, "destinationLocationId": 60008494},
+		"negative collateral": {"haulerUserId": 200, "originLocationId": 60003760, "destinationLocationId": 60008494, "volumeM3": 10, "collateral": -1},
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			repo := new(MockHaulingRequestsRepository)
+			perms := new(MockContactPermissionsRepository)
+
+			controller := controllers.NewHaulingRequests(&MockRouter{}, repo, perms)
+			_, httpErr := controller.CreateRequest(haulingRequestArgs(100, body, nil))
+
+			assert.NotNil(t, httpErr)
+			assert.Equal(t, 400, httpErr.StatusCode)
+			perms.AssertNotCalled(t, "CheckPermission", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func Test_HaulingRequests_UpdateStatus(t *testing.T) {
+	repo := new(MockHaulingRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	repo.On("GetByID", mock.Anything, int64(9)).Return(&models.HaulingRequest{
+		ID:              9,
+		RequesterUserID: 100,
+		HaulerUserID:    200,
+		Status:          models.ServiceRequestPending,
+	}, nil)
+	repo.On("UpdateStatus", mock.Anything, int64(9), models.ServiceRequestPending, models.ServiceRequestRejected).Return(nil)
+
+	controller := controllers.NewHaulingRequests(&MockRouter{}, repo, perms)
+
+	// Only the hauler decides on a request
+	_, httpErr := controller.UpdateStatus(haulingRequestArgs(100, map[string]any{"status": models.ServiceRequestRejected}, map[string]string{"id": "9"}))
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	result, httpErr := controller.UpdateStatus(haulingRequestArgs(200, map[string]any{"status": models.ServiceRequestRejected}, map[string]string{"id": "9"}))
+	assert.Nil(t, httpErr)
+	assert.Equal(t, models.ServiceRequestRejected, result.(*models.HaulingRequest).Status)
+	repo.AssertNumberOfCalls(t, "UpdateStatus", 1)
+}
+
+func Test_HaulingRequests_UpdateStatus_NotFound(t *testing.T) {
+	repo := new(MockHaulingRequestsRepository)
+	perms := new(MockContactPermissionsRepository)
+
+	repo.On("GetByID", mock.Anything, int64(9)).Return(nil, repositories.ErrHaulingRequestNotFound)
+
+	controller := controllers.NewHaulingRequests(&MockRouter{}, repo, perms)
+	_, httpErr := controller.UpdateStatus(haulingRequestArgs(200, map[string]any{"status": models.ServiceRequestAccepted}, map[string]string{"id": "9"}))
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
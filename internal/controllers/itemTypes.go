@@ -2,38 +2,60 @@ package controllers
 
 import (
 	"context"
+	"net/url"
+	"sort"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
 )
 
 type ItemTypeRepository interface {
-	SearchItemTypes(ctx context.Context, query string, limit int) ([]models.EveInventoryType, error)
+	SearchItemTypes(ctx context.Context, query string, filter *models.ItemTypeFilter, limit int) ([]models.EveInventoryType, error)
 	GetItemTypeByName(ctx context.Context, typeName string) (*models.EveInventoryType, error)
 }
 
+type ItemMetadataRepository interface {
+	GetCategories(ctx context.Context) ([]*models.ItemCategory, error)
+	GetGroups(ctx context.Context, categoryIDs []int64) ([]*models.ItemGroup, error)
+	GetMarketGroups(ctx context.Context) ([]*models.MarketGroup, error)
+	GetMetaGroups(ctx context.Context) ([]*models.MetaGroup, error)
+}
+
 type ItemTypes struct {
-	repository ItemTypeRepository
+	repository         ItemTypeRepository
+	metadataRepository ItemMetadataRepository
 }
 
-func NewItemTypes(router Routerer, repository ItemTypeRepository) *ItemTypes {
+func NewItemTypes(router Routerer, repository ItemTypeRepository, metadataRepository ItemMetadataRepository) *ItemTypes {
 	c := &ItemTypes{
-		repository: repository,
+		repository:         repository,
+		metadataRepository: metadataRepository,
 	}
 
 	router.RegisterRestAPIRoute("/v1/item-types/search", web.AuthAccessUser, c.SearchItemTypes, "GET")
+	router.RegisterRestAPIRoute("/v1/item-types/categories", web.AuthAccessUser, c.GetCategories, "GET")
+	router.RegisterRestAPIRoute("/v1/item-types/groups", web.AuthAccessUser, c.GetGroups, "GET")
+	router.RegisterRestAPIRoute("/v1/item-types/market-groups", web.AuthAccessUser, c.GetMarketGroups, "GET")
+	router.RegisterRestAPIRoute("/v1/item-types/meta-groups", web.AuthAccessUser, c.GetMetaGroups, "GET")
 
 	return c
 }
 
-// SearchItemTypes searches for item types by name
+// SearchItemTypes searches for item types by name, narrowed by the groupId, categoryId,
+// marketGroupId and metaGroupId filters. The name can be left out when filtering.
 func (c *ItemTypes) SearchItemTypes(args *web.HandlerArgs) (any, *web.HttpError) {
+	filter, httpErr := parseItemTypeFilter(args.Request.URL.Query())
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
 	query := args.Request.URL.Query().Get("q")
-	if query == "" {
+	if query == "" && filter.IsEmpty() {
 		return []models.EveInventoryType{}, nil
 	}
 
-	items, err := c.repository.SearchItemTypes(args.Request.Context(), query, 20)
+	items, err := c.repository.SearchItemTypes(args.Request.Context(), query, filter, 20)
 	if err != nil {
 		return nil, &web.HttpError{
 			StatusCode: 500,
@@ -47,3 +69,81 @@ func (c *ItemTypes) SearchItemTypes(args *web.HandlerArgs) (any, *web.HttpError)
 
 	return items, nil
 }
+
+func (c *ItemTypes) GetCategories(args *web.HandlerArgs) (any, *web.HttpError) {
+	categories, err := c.metadataRepository.GetCategories(args.Request.Context())
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get item categories")}
+	}
+
+	return categories, nil
+}
+
+// GetGroups returns item groups, limited to ?categoryId= if given
+func (c *ItemTypes) GetGroups(args *web.HandlerArgs) (any, *web.HttpError) {
+	categoryIDs, err := parseIDList(args.Request.URL.Query()["categoryId"])
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid categoryId")}
+	}
+
+	groups, err := c.metadataRepository.GetGroups(args.Request.Context(), categoryIDs)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get item groups")}
+	}
+
+	return groups, nil
+}
+
+func (c *ItemTypes) GetMarketGroups(args *web.HandlerArgs) (any, *web.HttpError) {
+	marketGroups, err := c.metadataRepository.GetMarketGroups(args.Request.Context())
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get market groups")}
+	}
+
+	return marketGroups, nil
+}
+
+func (c *ItemTypes) GetMetaGroups(args *web.HandlerArgs) (any, *web.HttpError) {
+	metaGroups, err := c.metadataRepository.GetMetaGroups(args.Request.Context())
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get meta groups")}
+	}
+
+	return metaGroups, nil
+}
+
+// parseItemTypeFilter reads the groupId, categoryId, marketGroupId and metaGroupId query
+// parameters, each a comma separated or repeated list of IDs
+func parseItemTypeFilter(query url.Values) (*models.ItemTypeFilter, *web.HttpError) {
+	filter := &models.ItemTypeFilter{}
+	for param, ids := range map[string]*[]int64{
+		"groupId":       &filter.GroupIDs,
+		"categoryId":    &filter.CategoryIDs,
+		"marketGroupId": &filter.MarketGroupIDs,
+		"metaGroupId":   &filter.MetaGroupIDs,
+	} {
+		parsed, err := parseIDList(query[param])
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrapf(err, "invalid %s", param)}
+		}
+		*ids = parsed
+	}
+
+	return filter, nil
+}
+
+// parseIDList is parseIDSet as a sorted list
+func parseIDList(values []string) ([]int64, error) {
+	set, err := parseIDSet(values)
+	if err != nil || set == nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids, nil
+}
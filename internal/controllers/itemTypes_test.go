@@ -0,0 +1,152 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockItemMetadataRepository struct {
+	mock.Mock
+}
+
+func (m *MockItemMetadataRepository) GetCategories(ctx context.Context) ([]*models.ItemCategory, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ItemCategory), args.Error(1)
+}
+
+func (m *MockItemMetadataRepository) GetGroups(ctx context.Context, categoryIDs []int64) ([]*models.ItemGroup, error) {
+	args := m.Called(ctx, categoryIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ItemGroup), args.Error(1)
+}
+
+func (m *MockItemMetadataRepository) GetMarketGroups(ctx context.Context) ([]*models.MarketGroup, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MarketGroup), args.Error(1)
+}
+
+func (m *MockItemMetadataRepository) GetMetaGroups(ctx context.Context) ([]*models.MetaGroup, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MetaGroup), args.Error(1)
+}
+
+func Test_ItemTypesController_Search_ByName(t *testing.T) {
+	mockRepo := new(MockItemTypeRepository)
+	controller := controllers.NewItemTypes(&MockRouter{}, mockRepo, new(MockItemMetadataRepository))
+
+	items := []models.EveInventoryType{{TypeID: 34, TypeName: "Tritanium"}}
+	mockRepo.On("SearchItemTypes", mock.Anything, "trit", &models.ItemTypeFilter{}, 20).Return(items, nil)
+
+	userID := int64(42)
+	result, httpErr := controller.SearchItemTypes(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/item-types/search?q=trit", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, items, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ItemTypesController_Search_ByFilterOnly(t *testing.T) {
+	mockRepo := new(MockItemTypeRepository)
+	controller := controllers.NewItemTypes(&MockRouter{}, mockRepo, new(MockItemMetadataRepository))
+
+	filter := &models.ItemTypeFilter{GroupIDs: []int64{18}, MetaGroupIDs: []int64{2}}
+	mockRepo.On("SearchItemTypes", mock.Anything, "", filter, 20).Return(nil, nil)
+
+	userID := int64(42)
+	result, httpErr := controller.SearchItemTypes(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/item-types/search?groupId=18&metaGroupId=2", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, []models.EveInventoryType{}, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_ItemTypesController_Search_Empty(t *testing.T) {
+	mockRepo := new(MockItemTypeRepository)
+	controller := controllers.NewItemTypes(&MockRouter{}, mockRepo, new(MockItemMetadataRepository))
+
+	userID := int64(42)
+	result, httpErr := controller.SearchItemTypes(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/item-types/search", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, []models.EveInventoryType{}, result)
+	mockRepo.AssertNotCalled(t, "SearchItemTypes", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_ItemTypesController_Search_InvalidFilter(t *testing.T) {
+	mockRepo := new(MockItemTypeRepository)
+	controller := controllers.NewItemTypes(&MockRouter{}, mockRepo, new(MockItemMetadataRepository))
+
+	userID := int64(42)
+	result, httpErr := controller.SearchItemTypes(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/item-types/search?q=trit&marketGroupId=abc", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+}
+
+func Test_ItemTypesController_GetGroups(t *testing.T) {
+	mockMetadata := new(MockItemMetadataRepository)
+	controller := controllers.NewItemTypes(&MockRouter{}, new(MockItemTypeRepository), mockMetadata)
+
+	groups := []*models.ItemGroup{{ID: 18, CategoryID: 4, Name: "Mineral", Published: true}}
+	mockMetadata.On("GetGroups", mock.Anything, []int64{4, 25}).Return(groups, nil)
+
+	userID := int64(42)
+	result, httpErr := controller.GetGroups(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/item-types/groups?categoryId=25,4", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, groups, result)
+	mockMetadata.AssertExpectations(t)
+}
+
+func Test_ItemTypesController_GetMarketGroups_Error(t *testing.T) {
+	mockMetadata := new(MockItemMetadataRepository)
+	controller := controllers.NewItemTypes(&MockRouter{}, new(MockItemTypeRepository), mockMetadata)
+
+	mockMetadata.On("GetMarketGroups", mock.Anything).Return(nil, errors.New("database error"))
+
+	userID := int64(42)
+	result, httpErr := controller.GetMarketGroups(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/item-types/market-groups", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+	mockMetadata.AssertExpectations(t)
+}
@@ -1,118 +0,0 @@
-package controllers_test
-
-import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-	"net/http/httptest"
-	"testing"
-
-	"github.com/annymsMthd/industry-tool/internal/controllers"
-	"github.com/annymsMthd/industry-tool/internal/web"
-	"github.com/stretchr/testify/assert"
-)
-
-func Test_JaniceController_CreateAppraisal_Success(t *testing.T) {
-	// Create a mock HTTP server to simulate Janice API
-	mockJaniceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Verify request
-		assert.Equal(t, "POST", r.Method)
-		assert.Equal(t, "text/plain", r.Header.Get("Content-Type"))
-		assert.Equal(t, "G9KwKq3465588VPd6747t95Zh94q3W2E", r.Header.Get("X-ApiKey"))
-
-		// Return mock response
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(controllers.JaniceAppraisalResponse{
-			Code: "test123",
-		})
-	}))
-	defer mockJaniceServer.Close()
-
-	mockRouter := &MockRouter{}
-	controller := controllers.NewJanice(mockRouter)
-
-	// Replace the httpClient to point to our mock server
-	// Note: This requires modifying the Janice struct to be testable
-	// For now, we'll test the request parsing logic
-
-	requestBody := controllers.JaniceAppraisalRequest{
-		Items: "Tritanium 1000\nPyerite 500",
-	}
-	body, _ := json.Marshal(requestBody)
-
-	req := httptest.NewRequest("POST", "/v1/janice/appraisal", bytes.NewReader(body))
-	args := &web.HandlerArgs{
-		Request: req,
-	}
-
-	// Note: This will call the real Janice API
-	// For true unit testing, we'd need to inject the HTTP client
-	result, httpErr := controller.CreateAppraisal(args)
-
-	// Since we can't mock the HTTP client without modifying the controller,
-	// we'll verify the request was processed correctly
-	// In a real scenario, you'd inject the HTTP client or use an interface
-	if httpErr != nil {
-		// If it fails due to network/API issues, that's expected in a test environment
-		assert.Contains(t, httpErr.Error.Error(), "janice")
-	} else {
-		assert.NotNil(t, result)
-		response := result.(controllers.JaniceAppraisalResponse)
-		assert.NotEmpty(t, response.Code)
-	}
-}
-
-func Test_JaniceController_CreateAppraisal_InvalidJSON(t *testing.T) {
-	mockRouter := &MockRouter{}
-	controller := controllers.NewJanice(mockRouter)
-
-	req := httptest.NewRequest("POST", "/v1/janice/appraisal", bytes.NewReader([]byte("invalid json")))
-	args := &web.HandlerArgs{
-		Request: req,
-	}
-
-	result, httpErr := controller.CreateAppraisal(args)
-
-	assert.Nil(t, result)
-	assert.NotNil(t, httpErr)
-	assert.Equal(t, 400, httpErr.StatusCode)
-	assert.Contains(t, httpErr.Error.Error(), "decode request body")
-}
-
-func Test_JaniceController_CreateAppraisal_EmptyItems(t *testing.T) {
-	mockRouter := &MockRouter{}
-	controller := controllers.NewJanice(mockRouter)
-
-	requestBody := controllers.JaniceAppraisalRequest{
-		Items: "",
-	}
-	body, _ := json.Marshal(requestBody)
-
-	req := httptest.NewRequest("POST", "/v1/janice/appraisal", bytes.NewReader(body))
-	args := &web.HandlerArgs{
-		Request: req,
-	}
-
-	// The controller doesn't validate empty items, it will forward to Janice
-	// Janice API will handle empty requests
-	result, httpErr := controller.CreateAppraisal(args)
-
-	// Result depends on Janice API response
-	// This test demonstrates the controller doesn't validate input
-	if httpErr != nil {
-		// Network errors are acceptable in tests
-		t.Skip("Skipping test that requires external API")
-	} else {
-		assert.NotNil(t, result)
-	}
-}
-
-func Test_JaniceController_Constructor_RegistersRoute(t *testing.T) {
-	mockRouter := &MockRouter{}
-
-	controller := controllers.NewJanice(mockRouter)
-
-	assert.NotNil(t, controller)
-	// In a more sophisticated test, we'd verify the route was registered
-	// by checking mockRouter's calls
-}
@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/notifications"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultNotificationsLimit = 50
+	maxNotificationsLimit     = 200
+)
+
+type NotificationsRepository interface {
+	GetByUser(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]*models.Notification, error)
+	MarkRead(ctx context.Context, userID, id int64) (bool, error)
+	MarkAllRead(ctx context.Context, userID int64) error
+}
+
+type NotificationSettingsRepository interface {
+	Get(ctx context.Context, userID int64) (*models.NotificationSettings, error)
+	Upsert(ctx context.Context, settings *models.NotificationSettings) error
+}
+
+type NotificationCharactersRepository interface {
+	GetAll(ctx context.Context, baseUserID int64) ([]*repositories.Character, error)
+}
+
+type Notifications struct {
+	repository         NotificationsRepository
+	settingsRepository NotificationSettingsRepository
+	characters         NotificationCharactersRepository
+}
+
+func NewNotifications(router Routerer, repository NotificationsRepository, settingsRepository NotificationSettingsRepository, characters NotificationCharactersRepository) *Notifications {
+	controller := &Notifications{
+		repository:         repository,
+		settingsRepository: settingsRepository,
+		characters:         characters,
+	}
+
+	router.RegisterRestAPIRoute("/v1/notifications", web.AuthAccessUser, controller.GetNotifications, "GET")
+	router.RegisterRestAPIRoute("/v1/notifications/read-all", web.AuthAccessUser, controller.MarkAllRead, "POST")
+	router.RegisterRestAPIRoute("/v1/notifications/{id}/read", web.AuthAccessUser, controller.MarkRead, "POST")
+	router.RegisterRestAPIRoute("/v1/notifications/settings", web.AuthAccessUser, controller.GetSettings, "GET")
+	router.RegisterRestAPIRoute("/v1/notifications/settings", web.AuthAccessUser, controller.UpdateSettings, "POST")
+
+	return controller
+}
+
+// GetNotifications returns the user's in-app notifications, newest first. ?unread=true leaves out
+// read ones and ?limit= caps the count (default 50, max 200).
+func (c *Notifications) GetNotifications(args *web.HandlerArgs) (any, *web.HttpError) {
+	query := args.Request.URL.Query()
+	unreadOnly := query.Get("unread") == "true"
+
+	limit := defaultNotificationsLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("limit must be a positive number")}
+		}
+		limit = min(l, maxNotificationsLimit)
+	}
+
+	notifications, err := c.repository.GetByUser(args.Request.Context(), *args.User, unreadOnly, limit)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get notifications")}
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks one notification as read
+func (c *Notifications) MarkRead(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid notification ID")}
+	}
+
+	found, err := c.repository.MarkRead(args.Request.Context(), *args.User, id)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to mark notification read")}
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("notification not found")}
+	}
+
+	return nil, nil
+}
+
+// MarkAllRead marks every unread notification as read
+func (c *Notifications) MarkAllRead(args *web.HandlerArgs) (any, *web.HttpError) {
+	err := c.repository.MarkAllRead(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to mark notifications read")}
+	}
+
+	return nil, nil
+}
+
+// GetSettings returns where the user's notifications are delivered besides the in-app list
+func (c *Notifications) GetSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	settings, err := c.settingsRepository.Get(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get notification settings")}
+	}
+
+	if settings == nil {
+		settings = &models.NotificationSettings{UserID: *args.User}
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings sets the webhook URL and the character that EVE mails the user. Either may be
+// null to turn that channel off.
+func (c *Notifications) UpdateSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req models.NotificationSettings
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	req.UserID = *args.User
+
+	if req.WebhookURL != nil {
+		trimmed := strings.TrimSpace(*req.WebhookURL)
+		if trimmed == "" {
+			req.WebhookURL = nil
+		} else {
+			err = notifications.ValidateWebhookURL(trimmed)
+			if err != nil {
+				return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: err}
+			}
+			req.WebhookURL = &trimmed
+		}
+	}
+
+	if req.MailCharacterID != nil {
+		characters, err := c.characters.GetAll(args.Request.Context(), *args.User)
+		if err != nil {
+			return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get characters")}
+		}
+
+		owned := false
+		for _, character := range characters {
+			if character.ID == *req.MailCharacterID {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("mail character must be one of your characters")}
+		}
+	}
+
+	err = c.settingsRepository.Upsert(args.Request.Context(), &req)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to update notification settings")}
+	}
+
+	return &req, nil
+}
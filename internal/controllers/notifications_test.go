@@ -0,0 +1,228 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationsRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationsRepository) GetByUser(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]*models.Notification, error) {
+	args := m.Called(ctx, userID, unreadOnly, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationsRepository) MarkRead(ctx context.Context, userID, id int64) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationsRepository) MarkAllRead(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type MockNotificationSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationSettingsRepository) Get(ctx context.Context, userID int64) (*models.NotificationSettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.NotificationSettings), args.Error(1)
+}
+
+func (m *MockNotificationSettingsRepository) Upsert(ctx context.Context, settings *models.NotificationSettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func newNotificationsController() (*controllers.Notifications, *MockNotificationsRepository, *MockNotificationSettingsRepository, *MockCharacterRepository) {
+	mockRepo := new(MockNotificationsRepository)
+	mockSettings := new(MockNotificationSettingsRepository)
+	mockCharacters := new(MockCharacterRepository)
+	controller := controllers.NewNotifications(&MockRouter{}, mockRepo, mockSettings, mockCharacters)
+	return controller, mockRepo, mockSettings, mockCharacters
+}
+
+func Test_NotificationsController_GetNotifications(t *testing.T) {
+	controller, mockRepo, _, _ := newNotificationsController()
+
+	userID := int64(42)
+	notifications := []*models.Notification{
+		{ID: 1, UserID: userID, Kind: models.NotificationKindStockpileAlert, Title: "Tritanium is low at Jita", CreatedAt: time.Now()},
+	}
+	mockRepo.On("GetByUser", mock.Anything, userID, false, 50).Return(notifications, nil)
+
+	req := httptest.NewRequest("GET", "/v1/notifications", nil)
+	result, httpErr := controller.GetNotifications(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, notifications, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_NotificationsController_GetNotifications_UnreadWithLimit(t *testing.T) {
+	controller, mockRepo, _, _ := newNotificationsController()
+
+	userID := int64(42)
+	mockRepo.On("GetByUser", mock.Anything, userID, true, 200).Return([]*models.Notification{}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/notifications?unread=true&limit=5000", nil)
+	_, httpErr := controller.GetNotifications(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_NotificationsController_GetNotifications_InvalidLimit(t *testing.T) {
+	controller, mockRepo, _, _ := newNotificationsController()
+
+	userID := int64(42)
+	req := httptest.NewRequest("GET", "/v1/notifications?limit=abc", nil)
+	_, httpErr := controller.GetNotifications(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "GetByUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_NotificationsController_MarkRead(t *testing.T) {
+	controller, mockRepo, _, _ := newNotificationsController()
+
+	userID := int64(42)
+	mockRepo.On("MarkRead", mock.Anything, userID, int64(7)).Return(true, nil)
+
+	req := httptest.NewRequest("POST", "/v1/notifications/7/read", nil)
+	_, httpErr := controller.MarkRead(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_NotificationsController_MarkRead_NotFound(t *testing.T) {
+	controller, mockRepo, _, _ := newNotificationsController()
+
+	userID := int64(42)
+	mockRepo.On("MarkRead", mock.Anything, userID, int64(7)).Return(false, nil)
+
+	req := httptest.NewRequest("POST", "/v1/notifications/7/read", nil)
+	_, httpErr := controller.MarkRead(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "7"}})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_NotificationsController_MarkAllRead_RepositoryError(t *testing.T) {
+	controller, mockRepo, _, _ := newNotificationsController()
+
+	userID := int64(42)
+	mockRepo.On("MarkAllRead", mock.Anything, userID).Return(errors.New("database error"))
+
+	req := httptest.NewRequest("POST", "/v1/notifications/read-all", nil)
+	_, httpErr := controller.MarkAllRead(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
+func Test_NotificationsController_GetSettings_Default(t *testing.T) {
+	controller, _, mockSettings, _ := newNotificationsController()
+
+	userID := int64(42)
+	mockSettings.On("Get", mock.Anything, userID).Return(nil, nil)
+
+	req := httptest.NewRequest("GET", "/v1/notifications/settings", nil)
+	result, httpErr := controller.GetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, &models.NotificationSettings{UserID: userID}, result)
+}
+
+func Test_NotificationsController_UpdateSettings(t *testing.T) {
+	controller, _, mockSettings, mockCharacters := newNotificationsController()
+
+	userID := int64(42)
+	mockCharacters.On("GetAll", mock.Anything, userID).Return([]*repositories.Character{{ID: 1001}, {ID: 1002}}, nil)
+
+	webhook := "https://discord.com/api/webhooks/1/abc"
+	characterID := int64(1002)
+	mockSettings.On("Upsert", mock.Anything, &models.NotificationSettings{UserID: userID, WebhookURL: &webhook, MailCharacterID: &characterID}).Return(nil)
+
+	body := `{"webhookUrl": " https://discord.com/api/webhooks/1/abc ", "mailCharacterId": 1002}`
+	req := httptest.NewRequest("POST", "/v1/notifications/settings", bytes.NewReader([]byte(body)))
+	result, httpErr := controller.UpdateSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, webhook, *result.(*models.NotificationSettings).WebhookURL)
+	mockSettings.AssertExpectations(t)
+}
+
+func Test_NotificationsController_UpdateSettings_ClearsChannels(t *testing.T) {
+	controller, _, mockSettings, _ := newNotificationsController()
+
+	userID := int64(42)
+	mockSettings.On("Upsert", mock.Anything, &models.NotificationSettings{UserID: userID}).Return(nil)
+
+	body := `{"webhookUrl": "", "mailCharacterId": null}`
+	req := httptest.NewRequest("POST", "/v1/notifications/settings", bytes.NewReader([]byte(body)))
+	_, httpErr := controller.UpdateSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	mockSettings.AssertExpectations(t)
+}
+
+func Test_NotificationsController_UpdateSettings_InvalidWebhook(t *testing.T) {
+	controller, _, mockSettings, _ := newNotificationsController()
+
+	userID := int64(42)
+	for _, body := range []string{
+		`{"webhookUrl": "http://discord.com/api/webhooks/1"}`,
+		`{"webhookUrl": "not a url"}`,
+		`{"webhookUrl": "https://localhost:8080/hook"}`,
+		`{"webhookUrl": "https://192.168.1.10/hook"}`,
+		`{"webhookUrl": "https://169.254.169.254/latest/meta-data"}`,
+	} {
+		req := httptest.NewRequest("POST", "/v1/notifications/settings", bytes.NewReader([]byte(body)))
+		_, httpErr := controller.UpdateSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+		if assert.NotNil(t, httpErr) {
+			assert.Equal(t, 400, httpErr.StatusCode)
+		}
+	}
+
+	mockSettings.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_NotificationsController_UpdateSettings_ForeignCharacter(t *testing.T) {
+	controller, _, mockSettings, mockCharacters := newNotificationsController()
+
+	userID := int64(42)
+	mockCharacters.On("GetAll", mock.Anything, userID).Return([]*repositories.Character{{ID: 1001}}, nil)
+
+	body := `{"mailCharacterId": 9999}`
+	req := httptest.NewRequest("POST", "/v1/notifications/settings", bytes.NewReader([]byte(body)))
+	_, httpErr := controller.UpdateSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockSettings.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
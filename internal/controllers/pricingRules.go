@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// maxPricingRulePercent caps market-relative prices at 10x the Jita reference
+const maxPricingRulePercent = 1000
+
+// validatePricingRule checks an optional market-relative pricing rule. A nil rule is valid.
+// The fixed price named fallbackField is used while Jita has no price, so a rule needs it or
+// a floor to be positive; otherwise the item would be priced at nothing.
+func validatePricingRule(rule *models.PricingRule, fallbackPrice int64, fallbackField string) error {
+	if rule == nil {
+		return nil
+	}
+
+	switch rule.Basis {
+	case "buy", "sell", "split":
+	default:
+		return errors.New("pricingRule.basis must be one of buy, sell or split")
+	}
+
+	if rule.Percent <= 0 || rule.Percent > maxPricingRulePercent {
+		return errors.Errorf("pricingRule.percent must be greater than 0 and at most %d", maxPricingRulePercent)
+	}
+	if rule.Floor != nil && *rule.Floor < 0 {
+		return errors.New("pricingRule.floor must be non-negative")
+	}
+	if rule.Ceiling != nil && *rule.Ceiling < 0 {
+		return errors.New("pricingRule.ceiling must be non-negative")
+	}
+	if rule.Floor != nil && rule.Ceiling != nil && *rule.Floor > *rule.Ceiling {
+		return errors.New("pricingRule.floor must not exceed pricingRule.ceiling")
+	}
+	if fallbackPrice <= 0 && (rule.Floor == nil || *rule.Floor <= 0) {
+		return errors.Errorf("a pricing rule needs a positive %s or pricingRule.floor to fall back on when Jita has no price", fallbackField)
+	}
+
+	return nil
+}
+
+// fieldUpdate is an optional field of an update request. Leaving the field out keeps the
+// stored value, so clients that don't know about the field can still edit; null clears it.
+type fieldUpdate[T any] struct {
+	set   bool
+	value *T
+}
+
+func (u *fieldUpdate[T]) UnmarshalJSON(data []byte) error {
+	u.set = true
+	if string(data) == "null" {
+		u.value = nil
+		return nil
+	}
+
+	u.value = new(T)
+	return json.Unmarshal(data, u.value)
+}
+
+// apply returns the value to store in place of current
+func (u fieldUpdate[T]) apply(current *T) *T {
+	if !u.set {
+		return current
+	}
+	return u.value
+}
+
+// pricingRuleUpdate is the pricing rule of an update request
+type pricingRuleUpdate = fieldUpdate[models.PricingRule]
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+type PurchaseExpirySettingsRepository interface {
+	Get(ctx context.Context, userID int64) (*models.PurchaseExpirySettings, error)
+	Upsert(ctx context.Context, settings *models.PurchaseExpirySettings) error
+}
+
+type PurchaseExpirySettings struct {
+	repository          PurchaseExpirySettingsRepository
+	defaultTimeoutHours int
+}
+
+func NewPurchaseExpirySettings(router Routerer, repository PurchaseExpirySettingsRepository, defaultTimeoutHours int) *PurchaseExpirySettings {
+	controller := &PurchaseExpirySettings{
+		repository:          repository,
+		defaultTimeoutHours: defaultTimeoutHours,
+	}
+
+	router.RegisterRestAPIRoute("/v1/purchases/expiry-settings", web.AuthAccessUser, controller.GetSettings, "GET")
+	router.RegisterRestAPIRoute("/v1/purchases/expiry-settings", web.AuthAccessUser, controller.UpdateSettings, "POST")
+
+	return controller
+}
+
+// GetSettings returns the seller's purchase expiry timeout, falling back to the server default
+func (c *PurchaseExpirySettings) GetSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	settings, err := c.repository.Get(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get expiry settings")}
+	}
+
+	if settings == nil {
+		settings = &models.PurchaseExpirySettings{
+			UserID:       *args.User,
+			TimeoutHours: c.defaultTimeoutHours,
+		}
+	}
+
+	return settings, nil
+}
+
+// UpdateSettings sets how long the seller's open purchases may sit before expiring (0 disables expiry)
+func (c *PurchaseExpirySettings) UpdateSettings(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req models.PurchaseExpirySettings
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	if req.TimeoutHours < 0 {
+		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("timeout hours cannot be negative")}
+	}
+
+	req.UserID = *args.User
+
+	err = c.repository.Upsert(args.Request.Context(), &req)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update expiry settings")}
+	}
+
+	return &req, nil
+}
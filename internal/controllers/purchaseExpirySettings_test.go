@@ -0,0 +1,113 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPurchaseExpirySettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseExpirySettingsRepository) Get(ctx context.Context, userID int64) (*models.PurchaseExpirySettings, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseExpirySettings), args.Error(1)
+}
+
+func (m *MockPurchaseExpirySettingsRepository) Upsert(ctx context.Context, settings *models.PurchaseExpirySettings) error {
+	args := m.Called(ctx, settings)
+	return args.Error(0)
+}
+
+func Test_PurchaseExpirySettingsController_GetSettings_Default(t *testing.T) {
+	mockRepo := new(MockPurchaseExpirySettingsRepository)
+	controller := controllers.NewPurchaseExpirySettings(&MockRouter{}, mockRepo, 72)
+
+	userID := int64(42)
+	mockRepo.On("Get", mock.Anything, userID).Return(nil, nil)
+
+	req := httptest.NewRequest("GET", "/v1/purchases/expiry-settings", nil)
+	result, httpErr := controller.GetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	settings := result.(*models.PurchaseExpirySettings)
+	assert.Equal(t, userID, settings.UserID)
+	assert.Equal(t, 72, settings.TimeoutHours)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_PurchaseExpirySettingsController_GetSettings_Stored(t *testing.T) {
+	mockRepo := new(MockPurchaseExpirySettingsRepository)
+	controller := controllers.NewPurchaseExpirySettings(&MockRouter{}, mockRepo, 72)
+
+	userID := int64(42)
+	mockRepo.On("Get", mock.Anything, userID).Return(&models.PurchaseExpirySettings{UserID: userID, TimeoutHours: 24}, nil)
+
+	req := httptest.NewRequest("GET", "/v1/purchases/expiry-settings", nil)
+	result, httpErr := controller.GetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, 24, result.(*models.PurchaseExpirySettings).TimeoutHours)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_PurchaseExpirySettingsController_GetSettings_RepositoryError(t *testing.T) {
+	mockRepo := new(MockPurchaseExpirySettingsRepository)
+	controller := controllers.NewPurchaseExpirySettings(&MockRouter{}, mockRepo, 72)
+
+	userID := int64(42)
+	mockRepo.On("Get", mock.Anything, userID).Return(nil, errors.New("database error"))
+
+	req := httptest.NewRequest("GET", "/v1/purchases/expiry-settings", nil)
+	result, httpErr := controller.GetSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
+func Test_PurchaseExpirySettingsController_UpdateSettings_Success(t *testing.T) {
+	mockRepo := new(MockPurchaseExpirySettingsRepository)
+	controller := controllers.NewPurchaseExpirySettings(&MockRouter{}, mockRepo, 72)
+
+	userID := int64(42)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(s *models.PurchaseExpirySettings) bool {
+		return s.UserID == userID && s.TimeoutHours == 12
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/v1/purchases/expiry-settings", bytes.NewReader([]byte(`{"timeoutHours":12}`)))
+	result, httpErr := controller.UpdateSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, 12, result.(*models.PurchaseExpirySettings).TimeoutHours)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_PurchaseExpirySettingsController_UpdateSettings_Negative(t *testing.T) {
+	mockRepo := new(MockPurchaseExpirySettingsRepository)
+	controller := controllers.NewPurchaseExpirySettings(&MockRouter{}, mockRepo, 72)
+
+	userID := int64(42)
+	req := httptest.NewRequest("POST", "/v1/purchases/expiry-settings", bytes.NewReader([]byte(`{"timeoutHours":-1}`)))
+	result, httpErr := controller.UpdateSettings(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
@@ -6,7 +6,10 @@ import (
 	"encoding/json"
 	"strconv"
 
+	"github.com/annymsMthd/industry-tool/internal/events"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
 )
@@ -18,12 +21,14 @@ type PurchaseTransactionsRepository interface {
 	GetPendingForSeller(ctx context.Context, sellerUserID int64) ([]*models.PurchaseTransaction, error)
 	GetByID(ctx context.Context, purchaseID int64) (*models.PurchaseTransaction, error)
 	UpdateStatus(ctx context.Context, purchaseID int64, newStatus string) error
+	CancelByParticipant(ctx context.Context, tx *sql.Tx, purchaseID int64, cancelledBy string) error
 	UpdateContractKeys(ctx context.Context, purchaseIDs []int64, contractKey string) error
 }
 
 type ForSaleItemsForPurchases interface {
 	GetByID(ctx context.Context, itemID int64) (*models.ForSaleItem, error)
 	UpdateQuantity(ctx context.Context, tx *sql.Tx, itemID int64, newQuantity int64) error
+	RestoreQuantity(ctx context.Context, tx *sql.Tx, itemID int64, quantity int64) (bool, error)
 }
 
 type Purchases struct {
@@ -31,6 +36,7 @@ type Purchases struct {
 	repository             PurchaseTransactionsRepository
 	forSaleRepository      ForSaleItemsForPurchases
 	permissionsRepository  ContactPermissionsRepository
+	events                 EventPublisher
 }
 
 func NewPurchases(router Routerer, db *sql.DB, repository PurchaseTransactionsRepository, forSaleRepository ForSaleItemsForPurchases, permissionsRepository ContactPermissionsRepository) *Purchases {
@@ -52,6 +58,12 @@ func NewPurchases(router Routerer, db *sql.DB, repository PurchaseTransactionsRe
 	return controller
 }
 
+// WithEvents publishes purchase activity to the other party of each purchase
+func (c *Purchases) WithEvents(publisher EventPublisher) *Purchases {
+	c.events = publisher
+	return c
+}
+
 // getUserID returns the session user directly (session user IS the user ID)
 func (c *Purchases) getUserID(ctx context.Context, sessionUser int64) (int64, error) {
 	return sessionUser, nil
@@ -92,7 +104,7 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 	}
 
 	// 2. Verify buyer has permission to browse from this seller
-	hasPermission, err := c.permissionsRepository.CheckPermission(args.Request.Context(), item.UserID, buyerUserID, "for_sale_browse")
+	hasPermission, err := c.permissionsRepository.CheckPermission(args.Request.Context(), item.UserID, buyerUserID, models.ServiceForSaleBrowse)
 	if err != nil {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to check permission")}
 	}
@@ -111,6 +123,11 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("requested quantity exceeds available quantity")}
 	}
 
+	// A rule with no Jita price and nothing to fall back on must not sell for free
+	if item.PricingRule != nil && item.PricePerUnit <= 0 {
+		return nil, &web.HttpError{StatusCode: 409, Error: errors.New("listing has no price to sell at until Jita prices it")}
+	}
+
 	// 5. Begin transaction
 	tx, err := c.db.BeginTx(args.Request.Context(), nil)
 	if err != nil {
@@ -125,7 +142,8 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update quantity")}
 	}
 
-	// 7. Create purchase record
+	// 7. Create purchase record. GetByID evaluated any pricing rule against current
+	// market prices, so the price the buyer saw is locked in here.
 	var notes *string
 	if req.Notes != "" {
 		notes = &req.Notes
@@ -154,6 +172,8 @@ func (c *Purchases) PurchaseItem(args *web.HandlerArgs) (any, *web.HttpError) {
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to commit transaction")}
 	}
 
+	publishEvent(args.Request.Context(), c.events, events.PurchaseCreated, purchase.SellerUserID, purchase)
+
 	return purchase, nil
 }
 
@@ -272,8 +292,12 @@ func (c *Purchases) MarkContractCreated(args *web.HandlerArgs) (any, *web.HttpEr
 		if err != nil {
 			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update contract key")}
 		}
+		purchase.ContractKey = req.ContractKey
 	}
 
+	purchase.Status = "contract_created"
+	publishEvent(args.Request.Context(), c.events, events.PurchaseContractCreated, purchase.BuyerUserID, purchase)
+
 	return map[string]string{"status": "contract_created"}, nil
 }
 
@@ -316,6 +340,9 @@ func (c *Purchases) CompletePurchase(args *web.HandlerArgs) (any, *web.HttpError
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to update purchase status")}
 	}
 
+	purchase.Status = "completed"
+	publishEvent(args.Request.Context(), c.events, events.PurchaseCompleted, purchase.SellerUserID, purchase)
+
 	return map[string]string{"status": "completed"}, nil
 }
 
@@ -352,6 +379,12 @@ func (c *Purchases) CancelPurchase(args *web.HandlerArgs) (any, *web.HttpError)
 		return nil, &web.HttpError{StatusCode: 400, Error: errors.New("cannot cancel completed purchase")}
 	}
 
+	// Record which side cancelled for reputation
+	cancelledBy := "buyer"
+	if purchase.SellerUserID == userID {
+		cancelledBy = "seller"
+	}
+
 	// If cancelling, restore the quantity to the for-sale item
 	tx, err := c.db.BeginTx(args.Request.Context(), nil)
 	if err != nil {
@@ -359,22 +392,24 @@ func (c *Purchases) CancelPurchase(args *web.HandlerArgs) (any, *web.HttpError)
 	}
 	defer tx.Rollback()
 
-	// Get current for-sale item
-	item, err := c.forSaleRepository.GetByID(args.Request.Context(), purchase.ForSaleItemID)
-	if err == nil {
-		// Item still exists, restore quantity
-		newQuantity := item.QuantityAvailable + purchase.QuantityPurchased
-		err = c.forSaleRepository.UpdateQuantity(args.Request.Context(), tx, item.ID, newQuantity)
-		if err != nil {
-			return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to restore quantity")}
+	// Cancel first so a purchase that is already closed can't restore quantity twice
+	err = c.repository.CancelByParticipant(args.Request.Context(), tx, purchaseID, cancelledBy)
+	if err != nil {
+		if errors.Is(err, repositories.ErrPurchaseNotOpen) {
+			return nil, &web.HttpError{StatusCode: 400, Error: errors.New("purchase is no longer open")}
 		}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to cancel purchase")}
 	}
-	// If item doesn't exist, that's OK - just cancel the purchase
 
-	// Update purchase status
-	err = c.repository.UpdateStatus(args.Request.Context(), purchaseID, "cancelled")
+	// Return the reserved quantity the same way expiry does, purchase row first and listing
+	// second, reactivating a listing the reservation sold out. A listing that was removed or
+	// replaced is left alone, and the purchase is cancelled anyway.
+	restored, err := c.forSaleRepository.RestoreQuantity(args.Request.Context(), tx, purchase.ForSaleItemID, purchase.QuantityPurchased)
 	if err != nil {
-		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to cancel purchase")}
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to restore quantity")}
+	}
+	if !restored {
+		log.Info("listing removed or replaced, quantity not restored", "purchase_id", purchase.ID, "for_sale_item_id", purchase.ForSaleItemID)
 	}
 
 	err = tx.Commit()
@@ -382,5 +417,12 @@ func (c *Purchases) CancelPurchase(args *web.HandlerArgs) (any, *web.HttpError)
 		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to commit transaction")}
 	}
 
+	otherUserID := purchase.BuyerUserID
+	if cancelledBy == "buyer" {
+		otherUserID = purchase.SellerUserID
+	}
+	purchase.Status = "cancelled"
+	publishEvent(args.Request.Context(), c.events, events.PurchaseCancelled, otherUserID, purchase)
+
 	return map[string]string{"status": "cancelled"}, nil
 }
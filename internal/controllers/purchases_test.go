@@ -78,7 +78,7 @@ func Test_PurchaseItem_Success(t *testing.T) {
 		GrantingUserID:  sellerID,
 		ReceivingUserID: buyerID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	assert.NoError(t, permRepo.Upsert(context.Background(), perm))
 
@@ -174,7 +174,7 @@ func Test_PurchaseItem_EntireQuantity_MarksInactive(t *testing.T) {
 		GrantingUserID:  sellerID,
 		ReceivingUserID: buyerID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	assert.NoError(t, permRepo.Upsert(context.Background(), perm))
 
@@ -370,7 +370,7 @@ func Test_PurchaseItem_QuantityExceeded(t *testing.T) {
 		GrantingUserID:  sellerID,
 		ReceivingUserID: buyerID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	assert.NoError(t, permRepo.Upsert(context.Background(), perm))
 
@@ -605,7 +605,7 @@ func Test_CancelPurchase_RestoresQuantity(t *testing.T) {
 		GrantingUserID:  sellerID,
 		ReceivingUserID: buyerID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	assert.NoError(t, permRepo.Upsert(context.Background(), perm))
 
@@ -664,4 +664,105 @@ func Test_CancelPurchase_RestoresQuantity(t *testing.T) {
 	cancelledPurchase, err := purchaseRepo.GetByID(context.Background(), purchase.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, "cancelled", cancelledPurchase.Status)
+
+	// Cancelling again must not restore the quantity a second time
+	req = httptest.NewRequest("POST", "/v1/purchases/"+strconv.FormatInt(purchase.ID, 10)+"/cancel", nil)
+	args = &web.HandlerArgs{
+		Request: req,
+		Params:  map[string]string{"id": strconv.FormatInt(purchase.ID, 10)},
+		User:    &buyerID,
+	}
+
+	result, httpErr = controller.CancelPurchase(args)
+	assert.Nil(t, result)
+	assert.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	restoredItem, err = forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), restoredItem.QuantityAvailable)
+}
+
+func Test_CancelPurchase_ReactivatesSoldOutListing(t *testing.T) {
+	db := setupPurchasesTestDB(t)
+
+	buyerID := int64(4080)
+	sellerID := int64(4081)
+	typeID := int64(58)
+
+	userRepo := repositories.NewUserRepository(db)
+	charRepo := repositories.NewCharacterRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	permRepo := repositories.NewContactPermissions(db)
+	purchaseRepo := repositories.NewPurchaseTransactions(db)
+	contactsRepo := repositories.NewContacts(db)
+
+	assert.NoError(t, userRepo.Add(context.Background(), &repositories.User{ID: buyerID, Name: "Buyer"}))
+	assert.NoError(t, userRepo.Add(context.Background(), &repositories.User{ID: sellerID, Name: "Seller"}))
+	assert.NoError(t, charRepo.Add(context.Background(), &repositories.Character{ID: buyerID * 10, Name: "Buyer Char", UserID: buyerID}))
+	assert.NoError(t, charRepo.Add(context.Background(), &repositories.Character{ID: sellerID * 10, Name: "Seller Char", UserID: sellerID}))
+
+	itemTypes := []models.EveInventoryType{{TypeID: typeID, TypeName: "Zydrine", Volume: 0.01}}
+	assert.NoError(t, itemTypesRepo.UpsertItemTypes(context.Background(), itemTypes))
+
+	contact, err := contactsRepo.Create(context.Background(), buyerID, sellerID)
+	assert.NoError(t, err)
+	_, err = contactsRepo.UpdateStatus(context.Background(), contact.ID, sellerID, "accepted")
+	assert.NoError(t, err)
+
+	assert.NoError(t, permRepo.Upsert(context.Background(), &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  sellerID,
+		ReceivingUserID: buyerID,
+		ServiceType:     "for_sale_browse",
+		CanAccess:       boolPtr(true),
+	}))
+
+	item := &models.ForSaleItem{
+		UserID:            sellerID,
+		TypeID:            typeID,
+		OwnerType:         "character",
+		OwnerID:           sellerID * 10,
+		LocationID:        30000142,
+		QuantityAvailable: 50,
+		PricePerUnit:      900,
+		IsActive:          true,
+	}
+	assert.NoError(t, forSaleRepo.Upsert(context.Background(), item))
+
+	controller := controllers.NewPurchases(&MockRouter{}, db, purchaseRepo, forSaleRepo, permRepo)
+
+	// Buying everything sells the listing out
+	body, _ := json.Marshal(map[string]interface{}{
+		"forSaleItemId":     item.ID,
+		"quantityPurchased": 50,
+	})
+	result, httpErr := controller.PurchaseItem(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases", bytes.NewReader(body)),
+		User:    &buyerID,
+	})
+	assert.Nil(t, httpErr)
+	purchase := result.(*models.PurchaseTransaction)
+
+	soldOut, err := forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.False(t, soldOut.IsActive)
+
+	// Cancelling brings the listing back rather than failing on the inactive row
+	_, httpErr = controller.CancelPurchase(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/purchases/"+strconv.FormatInt(purchase.ID, 10)+"/cancel", nil),
+		Params:  map[string]string{"id": strconv.FormatInt(purchase.ID, 10)},
+		User:    &sellerID,
+	})
+	assert.Nil(t, httpErr)
+
+	restored, err := forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.True(t, restored.IsActive)
+	assert.Equal(t, int64(50), restored.QuantityAvailable)
+
+	cancelled, err := purchaseRepo.GetByID(context.Background(), purchase.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", cancelled.Status)
 }
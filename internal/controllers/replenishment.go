@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	// replenishmentRegionID prices market buys in The Forge, where market_prices holds Jita orders
+	replenishmentRegionID = 10000002
+
+	// Market buys are routed from Jita IV - Moon 4 - Caldari Navy Assembly Plant
+	replenishmentMarketStationID   = 60003760
+	replenishmentMarketStationName = "Jita IV - Moon 4 - Caldari Navy Assembly Plant"
+)
+
+type ReplenishmentAssetsRepository interface {
+	GetStockpileDeficits(ctx context.Context, user int64) (*repositories.StockpilesResponse, error)
+	GetStockpileSurpluses(ctx context.Context, user int64, typeIDs []int64) ([]*repositories.StockpileSurplus, error)
+}
+
+type ReplenishmentListingsRepository interface {
+	GetBrowsableItems(ctx context.Context, buyerUserID int64, sellerUserIDs []int64) ([]*models.ForSaleItem, error)
+}
+
+type ReplenishmentPermissionsRepository interface {
+	GetUserPermissionsForService(ctx context.Context, viewerUserID int64, serviceType string) ([]int64, error)
+}
+
+type ReplenishmentItemTypeRepository interface {
+	GetItemTypesByIDs(ctx context.Context, typeIDs []int64) (map[int64]models.EveInventoryType, error)
+}
+
+type ReplenishmentPricesRepository interface {
+	GetPricesForTypes(ctx context.Context, typeIDs []int64, regionID int64) (map[int64]*models.MarketPrice, error)
+}
+
+// Replenishment turns stockpile deficits into a plan of hauls from surplus stock and purchases
+type Replenishment struct {
+	assetsRepository      ReplenishmentAssetsRepository
+	listingsRepository    ReplenishmentListingsRepository
+	permissionsRepository ReplenishmentPermissionsRepository
+	itemTypeRepository    ReplenishmentItemTypeRepository
+	pricesRepository      ReplenishmentPricesRepository
+}
+
+func NewReplenishment(router Routerer, assetsRepository ReplenishmentAssetsRepository, listingsRepository ReplenishmentListingsRepository, permissionsRepository ReplenishmentPermissionsRepository, itemTypeRepository ReplenishmentItemTypeRepository, pricesRepository ReplenishmentPricesRepository) *Replenishment {
+	controller := &Replenishment{
+		assetsRepository:      assetsRepository,
+		listingsRepository:    listingsRepository,
+		permissionsRepository: permissionsRepository,
+		itemTypeRepository:    itemTypeRepository,
+		pricesRepository:      pricesRepository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/stockpiles/replenishment-plan", web.AuthAccessUser, controller.GetPlan, "GET")
+
+	return controller
+}
+
+// GetPlan covers every stockpile deficit from, in order of preference, surplus stock the user
+// already owns elsewhere, contact listings cheaper than Jita, and the Jita market
+func (c *Replenishment) GetPlan(args *web.HandlerArgs) (any, *web.HttpError) {
+	ctx := args.Request.Context()
+
+	deficits, err := c.assetsRepository.GetStockpileDeficits(ctx, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile deficits")}
+	}
+
+	typeIDs := []int64{}
+	seen := map[int64]bool{}
+	for _, deficit := range deficits.Items {
+		if !seen[deficit.TypeID] {
+			seen[deficit.TypeID] = true
+			typeIDs = append(typeIDs, deficit.TypeID)
+		}
+	}
+
+	if len(typeIDs) == 0 {
+		return planReplenishment(nil, nil, nil, nil, nil), nil
+	}
+
+	surpluses, err := c.assetsRepository.GetStockpileSurpluses(ctx, *args.User, typeIDs)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile surpluses")}
+	}
+
+	itemTypes, err := c.itemTypeRepository.GetItemTypesByIDs(ctx, typeIDs)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get item types")}
+	}
+
+	prices, err := c.pricesRepository.GetPricesForTypes(ctx, typeIDs, replenishmentRegionID)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get market prices")}
+	}
+
+	sellerUserIDs, err := c.permissionsRepository.GetUserPermissionsForService(ctx, *args.User, models.ServiceForSaleBrowse)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get permissions")}
+	}
+
+	listings, err := c.listingsRepository.GetBrowsableItems(ctx, *args.User, sellerUserIDs)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get contact listings")}
+	}
+
+	return planReplenishment(deficits.Items, surpluses, listings, itemTypes, prices), nil
+}
+
+type surplusSource struct {
+	*repositories.StockpileSurplus
+	left int64
+}
+
+type listingSource struct {
+	*models.ForSaleItem
+	left int64
+}
+
+var replenishmentActionOrder = map[string]int{
+	models.ReplenishmentMove:       0,
+	models.ReplenishmentBuyContact: 1,
+	models.ReplenishmentBuyMarket:  2,
+}
+
+// planReplenishment fills each deficit, largest deficit value first, from the nearest surplus,
+// then contact listings priced below Jita cheapest first, then the market. Surplus and listing
+// quantities are shared across deficits so nothing is promised twice.
+func planReplenishment(deficits []*repositories.StockpileItem, surpluses []*repositories.StockpileSurplus, listings []*models.ForSaleItem, itemTypes map[int64]models.EveInventoryType, prices map[int64]*models.MarketPrice) *models.ReplenishmentPlan {
+	plan := &models.ReplenishmentPlan{
+		Steps:  []*models.ReplenishmentStep{},
+		Routes: []*models.ReplenishmentRoute{},
+	}
+
+	surplusByType := map[int64][]*surplusSource{}
+	for _, surplus := range surpluses {
+		if surplus.Surplus > 0 {
+			surplusByType[surplus.TypeID] = append(surplusByType[surplus.TypeID], &surplusSource{StockpileSurplus: surplus, left: surplus.Surplus})
+		}
+	}
+
+	listingsByType := map[int64][]*listingSource{}
+	for _, listing := range listings {
+		if listing.QuantityAvailable > 0 {
+			listingsByType[listing.TypeID] = append(listingsByType[listing.TypeID], &listingSource{ForSaleItem: listing, left: listing.QuantityAvailable})
+		}
+	}
+	for _, sources := range listingsByType {
+		sort.SliceStable(sources, func(i, j int) bool {
+			return sources[i].PricePerUnit < sources[j].PricePerUnit
+		})
+	}
+
+	ordered := make([]*repositories.StockpileItem, len(deficits))
+	copy(ordered, deficits)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].DeficitValue > ordered[j].DeficitValue
+	})
+
+	for _, deficit := range ordered {
+		need := deficit.DesiredQuantity - deficit.Quantity
+		if need <= 0 {
+			continue
+		}
+
+		unitVolume := itemTypes[deficit.TypeID].UnitPackagedVolume()
+		marketPrice := replenishmentMarketPrice(prices[deficit.TypeID])
+
+		newStep := func(action string, quantity int64, unitPrice float64) *models.ReplenishmentStep {
+			step := &models.ReplenishmentStep{
+				Action:       action,
+				TypeID:       deficit.TypeID,
+				TypeName:     deficit.Name,
+				Quantity:     quantity,
+				Volume:       float64(quantity) * unitVolume,
+				UnitPrice:    unitPrice,
+				Cost:         float64(quantity) * unitPrice,
+				MarketCost:   float64(quantity) * marketPrice,
+				ToLocationID: deficit.LocationID,
+				ToName:       deficit.StructureName,
+				ToScope:      deficit.Scope,
+				ToOwnerName:  deficit.OwnerName,
+				ToContainer:  deficit.ContainerName,
+			}
+			plan.Steps = append(plan.Steps, step)
+			return step
+		}
+
+		// Own surplus costs nothing but the haul, so use the closest stock first
+		sources := surplusByType[deficit.TypeID]
+		sort.SliceStable(sources, func(i, j int) bool {
+			return surplusDistance(sources[i], deficit) < surplusDistance(sources[j], deficit)
+		})
+		for _, source := range sources {
+			if need == 0 {
+				break
+			}
+			if source.left == 0 || surplusCountedByDeficit(source, deficit) {
+				continue
+			}
+
+			quantity := min(need, source.left)
+			source.left -= quantity
+			need -= quantity
+
+			step := newStep(models.ReplenishmentMove, quantity, 0)
+			step.FromLocationID = source.LocationID
+			step.FromName = source.StructureName
+			step.FromOwnerName = source.OwnerName
+			step.FromContainer = source.ContainerName
+		}
+
+		// Contacts only beat the market when they undercut it
+		for _, source := range listingsByType[deficit.TypeID] {
+			if need == 0 {
+				break
+			}
+			price := float64(source.PricePerUnit)
+			if source.left == 0 || (marketPrice > 0 && price >= marketPrice) {
+				continue
+			}
+
+			quantity := min(need, source.left)
+			source.left -= quantity
+			need -= quantity
+
+			listingID := source.ID
+			step := newStep(models.ReplenishmentBuyContact, quantity, price)
+			step.FromLocationID = source.LocationID
+			step.FromName = source.LocationName
+			step.FromOwnerName = source.OwnerName
+			step.ListingID = &listingID
+		}
+
+		if need > 0 {
+			step := newStep(models.ReplenishmentBuyMarket, need, marketPrice)
+			step.FromLocationID = replenishmentMarketStationID
+			step.FromName = replenishmentMarketStationName
+		}
+	}
+
+	sort.SliceStable(plan.Steps, func(i, j int) bool {
+		a, b := plan.Steps[i], plan.Steps[j]
+		if a.Action != b.Action {
+			return replenishmentActionOrder[a.Action] < replenishmentActionOrder[b.Action]
+		}
+		return a.MarketCost-a.Cost > b.MarketCost-b.Cost
+	})
+
+	type routeKey struct {
+		action   string
+		from, to int64
+		toName   string
+	}
+	routes := map[routeKey]*models.ReplenishmentRoute{}
+	for i, step := range plan.Steps {
+		step.Rank = i + 1
+		plan.TotalVolume += step.Volume
+		plan.TotalCost += step.Cost
+
+		key := routeKey{action: step.Action, from: step.FromLocationID, to: step.ToLocationID, toName: step.ToName}
+		route, ok := routes[key]
+		if !ok {
+			route = &models.ReplenishmentRoute{
+				Action:         step.Action,
+				FromLocationID: step.FromLocationID,
+				FromName:       step.FromName,
+				ToLocationID:   step.ToLocationID,
+				ToName:         step.ToName,
+			}
+			routes[key] = route
+			plan.Routes = append(plan.Routes, route)
+		}
+		route.Steps++
+		route.Volume += step.Volume
+		route.Cost += step.Cost
+	}
+
+	return plan
+}
+
+// replenishmentMarketPrice is what buying one unit in Jita costs, falling back to the buy price
+// for types without sell orders and 0 for types with no market data
+func replenishmentMarketPrice(price *models.MarketPrice) float64 {
+	if price == nil {
+		return 0
+	}
+	if price.SellPrice != nil && *price.SellPrice > 0 {
+		return *price.SellPrice
+	}
+	if price.BuyPrice != nil {
+		return *price.BuyPrice
+	}
+	return 0
+}
+
+// surplusDistance ranks a source as in the same structure, system, region or elsewhere
+func surplusDistance(source *surplusSource, deficit *repositories.StockpileItem) int {
+	switch {
+	case deficit.Scope == models.StockpileScopeLocation && source.LocationID == deficit.LocationID:
+		return 0
+	case deficit.SolarSystem != "" && source.SolarSystem == deficit.SolarSystem:
+		return 1
+	case deficit.Region != "" && source.Region == deficit.Region:
+		return 2
+	}
+	return 3
+}
+
+// surplusCountedByDeficit reports whether an aggregate marker already sums the source, in which
+// case moving it would not reduce the deficit
+func surplusCountedByDeficit(source *surplusSource, deficit *repositories.StockpileItem) bool {
+	if deficit.OwnerType != models.StockpileOwnerAll &&
+		(source.OwnerType != deficit.OwnerType || source.OwnerID != deficit.OwnerID) {
+		return false
+	}
+
+	switch deficit.Scope {
+	case models.StockpileScopeSolarSystem:
+		return source.SolarSystemID == deficit.LocationID
+	case models.StockpileScopeRegion:
+		return source.RegionID == deficit.LocationID
+	case models.StockpileScopeAnywhere:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReplenishmentAssetsRepository struct {
+	mock.Mock
+}
+
+func (m *MockReplenishmentAssetsRepository) GetStockpileDeficits(ctx context.Context, user int64) (*repositories.StockpilesResponse, error) {
+	args := m.Called(ctx, user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.StockpilesResponse), args.Error(1)
+}
+
+func (m *MockReplenishmentAssetsRepository) GetStockpileSurpluses(ctx context.Context, user int64, typeIDs []int64) ([]*repositories.StockpileSurplus, error) {
+	args := m.Called(ctx, user, typeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repositories.StockpileSurplus), args.Error(1)
+}
+
+type MockReplenishmentItemTypeRepository struct {
+	mock.Mock
+}
+
+func (m *MockReplenishmentItemTypeRepository) GetItemTypesByIDs(ctx context.Context, typeIDs []int64) (map[int64]models.EveInventoryType, error) {
+	args := m.Called(ctx, typeIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64]models.EveInventoryType), args.Error(1)
+}
+
+type replenishmentMocks struct {
+	assets      *MockReplenishmentAssetsRepository
+	listings    *MockForSaleItemsRepository
+	permissions *MockContactPermissionsRepository
+	itemTypes   *MockReplenishmentItemTypeRepository
+	prices      *MockAppraisalPricesRepository
+}
+
+func setupReplenishmentController() (*controllers.Replenishment, replenishmentMocks) {
+	mocks := replenishmentMocks{
+		assets:      new(MockReplenishmentAssetsRepository),
+		listings:    new(MockForSaleItemsRepository),
+		permissions: new(MockContactPermissionsRepository),
+		itemTypes:   new(MockReplenishmentItemTypeRepository),
+		prices:      new(MockAppraisalPricesRepository),
+	}
+	controller := controllers.NewReplenishment(&MockRouter{}, mocks.assets, mocks.listings, mocks.permissions, mocks.itemTypes, mocks.prices)
+	return controller, mocks
+}
+
+func replenishmentArgs() *web.HandlerArgs {
+	userID := int64(42)
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/stockpiles/replenishment-plan", nil),
+		User:    &userID,
+	}
+}
+
+// expectReplenishmentLookups stubs everything after the deficits with Jita selling tritanium at 5 ISK
+func expectReplenishmentLookups(mocks replenishmentMocks, typeIDs []int64, surpluses []*repositories.StockpileSurplus, listings []*models.ForSaleItem) {
+	mocks.assets.On("GetStockpileSurpluses", mock.Anything, int64(42), typeIDs).Return(surpluses, nil)
+	mocks.itemTypes.On("GetItemTypesByIDs", mock.Anything, typeIDs).Return(map[int64]models.EveInventoryType{
+		34: {TypeID: 34, TypeName: "Tritanium", Volume: 0.01},
+	}, nil)
+	mocks.prices.On("GetPricesForTypes", mock.Anything, typeIDs, int64(10000002)).Return(map[int64]*models.MarketPrice{
+		34: {TypeID: 34, BuyPrice: price(4), SellPrice: price(5)},
+	}, nil)
+	mocks.permissions.On("GetUserPermissionsForService", mock.Anything, int64(42), "for_sale_browse").Return([]int64{7}, nil)
+	mocks.listings.On("GetBrowsableItems", mock.Anything, int64(42), []int64{7}).Return(listings, nil)
+}
+
+func Test_ReplenishmentController_PrefersSurplusThenContactsThenMarket(t *testing.T) {
+	controller, mocks := setupReplenishmentController()
+
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Tritanium", TypeID: 34, Quantity: 1000, OwnerType: "character", OwnerID: 9001, OwnerName: "Pilot",
+				DesiredQuantity: 10000, StockpileDelta: -9000, DeficitValue: 36000,
+				StructureName: "Staging", SolarSystem: "Amarr", Region: "Domain", LocationID: 60008494, Scope: "location"},
+		},
+	}, nil)
+	expectReplenishmentLookups(mocks, []int64{34},
+		[]*repositories.StockpileSurplus{
+			{TypeID: 34, Name: "Tritanium", OwnerType: "character", OwnerID: 9001, OwnerName: "Pilot",
+				LocationID: 60003760, StructureName: "Jita 4-4", SolarSystem: "Jita", Region: "The Forge",
+				Quantity: 3000, Surplus: 3000},
+		},
+		[]*models.ForSaleItem{
+			{ID: 11, TypeID: 34, OwnerName: "Expensive", LocationID: 60008494, LocationName: "Staging", QuantityAvailable: 5000, PricePerUnit: 6},
+			{ID: 12, TypeID: 34, OwnerName: "Friend", LocationID: 60008494, LocationName: "Staging", QuantityAvailable: 4000, PricePerUnit: 4},
+		},
+	)
+
+	result, httpErr := controller.GetPlan(replenishmentArgs())
+	require.Nil(t, httpErr)
+
+	plan := result.(*models.ReplenishmentPlan)
+	require.Len(t, plan.Steps, 3)
+
+	move := plan.Steps[0]
+	assert.Equal(t, 1, move.Rank)
+	assert.Equal(t, models.ReplenishmentMove, move.Action)
+	assert.Equal(t, int64(3000), move.Quantity)
+	assert.Equal(t, int64(60003760), move.FromLocationID)
+	assert.Equal(t, int64(60008494), move.ToLocationID)
+	assert.Equal(t, 0.0, move.Cost)
+	assert.InDelta(t, 30.0, move.Volume, 0.0001)
+
+	// The 6 ISK listing is dearer than Jita and skipped
+	contact := plan.Steps[1]
+	assert.Equal(t, models.ReplenishmentBuyContact, contact.Action)
+	assert.Equal(t, int64(4000), contact.Quantity)
+	assert.Equal(t, int64(12), *contact.ListingID)
+	assert.Equal(t, 16000.0, contact.Cost)
+
+	market := plan.Steps[2]
+	assert.Equal(t, models.ReplenishmentBuyMarket, market.Action)
+	assert.Equal(t, int64(2000), market.Quantity)
+	assert.Equal(t, int64(60003760), market.FromLocationID)
+	assert.Equal(t, 10000.0, market.Cost)
+
+	assert.Equal(t, 26000.0, plan.TotalCost)
+	assert.InDelta(t, 90.0, plan.TotalVolume, 0.0001)
+	require.Len(t, plan.Routes, 3)
+	assert.Equal(t, "Jita 4-4", plan.Routes[0].FromName)
+	assert.Equal(t, "Staging", plan.Routes[0].ToName)
+}
+
+func Test_ReplenishmentController_SharesSurplusAcrossDeficits(t *testing.T) {
+	controller, mocks := setupReplenishmentController()
+
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Tritanium", TypeID: 34, Quantity: 0, OwnerType: "character", OwnerID: 9001, OwnerName: "Pilot",
+				DesiredQuantity: 500, StockpileDelta: -500, DeficitValue: 2000,
+				StructureName: "Small", SolarSystem: "Amarr", Region: "Domain", LocationID: 1, Scope: "location"},
+			{Name: "Tritanium", TypeID: 34, Quantity: 0, OwnerType: "character", OwnerID: 9001, OwnerName: "Pilot",
+				DesiredQuantity: 2000, StockpileDelta: -2000, DeficitValue: 8000,
+				StructureName: "Large", SolarSystem: "Amarr", Region: "Domain", LocationID: 2, Scope: "location"},
+		},
+	}, nil)
+	expectReplenishmentLookups(mocks, []int64{34},
+		[]*repositories.StockpileSurplus{
+			{TypeID: 34, OwnerType: "character", OwnerID: 9001, LocationID: 3, StructureName: "Spare", SolarSystem: "Jita", Quantity: 2200, Surplus: 2200},
+		},
+		[]*models.ForSaleItem{},
+	)
+
+	result, httpErr := controller.GetPlan(replenishmentArgs())
+	require.Nil(t, httpErr)
+
+	plan := result.(*models.ReplenishmentPlan)
+	moved := map[string]int64{}
+	bought := map[string]int64{}
+	for _, step := range plan.Steps {
+		if step.Action == models.ReplenishmentMove {
+			moved[step.ToName] += step.Quantity
+		} else {
+			bought[step.ToName] += step.Quantity
+		}
+	}
+
+	// The larger deficit is filled first; the rest of the surplus goes to the smaller one
+	assert.Equal(t, map[string]int64{"Large": 2000, "Small": 200}, moved)
+	assert.Equal(t, map[string]int64{"Small": 300}, bought)
+}
+
+func Test_ReplenishmentController_AggregateDeficitSkipsSurplusInScope(t *testing.T) {
+	controller, mocks := setupReplenishmentController()
+
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Tritanium", TypeID: 34, Quantity: 100, OwnerType: "all", OwnerName: "All owners",
+				DesiredQuantity: 1000, StockpileDelta: -900, DeficitValue: 3600,
+				StructureName: "Amarr", SolarSystem: "Amarr", Region: "Domain", LocationID: 30002187, Scope: "solar_system"},
+		},
+	}, nil)
+	expectReplenishmentLookups(mocks, []int64{34},
+		[]*repositories.StockpileSurplus{
+			{TypeID: 34, OwnerType: "corporation", OwnerID: 98000001, LocationID: 60008494, StructureName: "Amarr VIII",
+				SolarSystemID: 30002187, SolarSystem: "Amarr", Quantity: 5000, Surplus: 5000},
+			{TypeID: 34, OwnerType: "character", OwnerID: 9001, LocationID: 60003760, StructureName: "Jita 4-4",
+				SolarSystemID: 30000142, SolarSystem: "Jita", Quantity: 400, Surplus: 400},
+		},
+		[]*models.ForSaleItem{},
+	)
+
+	result, httpErr := controller.GetPlan(replenishmentArgs())
+	require.Nil(t, httpErr)
+
+	plan := result.(*models.ReplenishmentPlan)
+	require.Len(t, plan.Steps, 2)
+	assert.Equal(t, "Jita 4-4", plan.Steps[0].FromName)
+	assert.Equal(t, int64(400), plan.Steps[0].Quantity)
+	assert.Equal(t, models.ReplenishmentBuyMarket, plan.Steps[1].Action)
+	assert.Equal(t, int64(500), plan.Steps[1].Quantity)
+}
+
+func Test_ReplenishmentController_UsesPackagedVolume(t *testing.T) {
+	controller, mocks := setupReplenishmentController()
+
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{
+			{Name: "Rifter", TypeID: 587, Quantity: 0, OwnerType: "character", OwnerID: 9001, OwnerName: "Pilot",
+				DesiredQuantity: 4, StockpileDelta: -4, DeficitValue: 2000000,
+				StructureName: "Staging", SolarSystem: "Amarr", Region: "Domain", LocationID: 60008494, Scope: "location"},
+		},
+	}, nil)
+	packaged := 2500.0
+	mocks.assets.On("GetStockpileSurpluses", mock.Anything, int64(42), []int64{587}).Return([]*repositories.StockpileSurplus{}, nil)
+	mocks.itemTypes.On("GetItemTypesByIDs", mock.Anything, []int64{587}).Return(map[int64]models.EveInventoryType{
+		587: {TypeID: 587, TypeName: "Rifter", Volume: 27289, PackagedVolume: &packaged},
+	}, nil)
+	mocks.prices.On("GetPricesForTypes", mock.Anything, []int64{587}, int64(10000002)).Return(map[int64]*models.MarketPrice{
+		587: {TypeID: 587, BuyPrice: price(450000), SellPrice: price(500000)},
+	}, nil)
+	mocks.permissions.On("GetUserPermissionsForService", mock.Anything, int64(42), "for_sale_browse").Return([]int64{}, nil)
+	mocks.listings.On("GetBrowsableItems", mock.Anything, int64(42), []int64{}).Return([]*models.ForSaleItem{}, nil)
+
+	result, httpErr := controller.GetPlan(replenishmentArgs())
+	require.Nil(t, httpErr)
+
+	// Ships are hauled packaged, not at their assembled size
+	plan := result.(*models.ReplenishmentPlan)
+	require.Len(t, plan.Steps, 1)
+	assert.InDelta(t, 10000.0, plan.Steps[0].Volume, 0.0001)
+	assert.InDelta(t, 10000.0, plan.TotalVolume, 0.0001)
+}
+
+func Test_ReplenishmentController_NoDeficits(t *testing.T) {
+	controller, mocks := setupReplenishmentController()
+
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{},
+	}, nil)
+
+	result, httpErr := controller.GetPlan(replenishmentArgs())
+	require.Nil(t, httpErr)
+
+	plan := result.(*models.ReplenishmentPlan)
+	assert.Empty(t, plan.Steps)
+	assert.Empty(t, plan.Routes)
+	mocks.assets.AssertNotCalled(t, "GetStockpileSurpluses", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_ReplenishmentController_DeficitsError(t *testing.T) {
+	controller, mocks := setupReplenishmentController()
+
+	mocks.assets.On("GetStockpileDeficits", mock.Anything, int64(42)).Return(nil, errors.New("db down"))
+
+	result, httpErr := controller.GetPlan(replenishmentArgs())
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
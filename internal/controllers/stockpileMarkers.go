@@ -3,16 +3,33 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
 
+	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/pkg/errors"
 )
 
+// maxStockpileBulkItems caps how many upserts and deletes one bulk request can hold
+const maxStockpileBulkItems = 500
+
 type StockpileMarkersRepository interface {
 	GetByUser(ctx context.Context, userID int64) ([]*models.StockpileMarker, error)
+	GetByID(ctx context.Context, id int64, userID int64) (*models.StockpileMarker, error)
 	Upsert(ctx context.Context, marker *models.StockpileMarker) error
+	Update(ctx context.Context, marker *models.StockpileMarker) (bool, error)
 	Delete(ctx context.Context, marker *models.StockpileMarker) error
+	DeleteByID(ctx context.Context, id int64, userID int64) (bool, error)
+	CheckTarget(ctx context.Context, marker *models.StockpileMarker) (string, error)
+}
+
+type stockpileBulkRequest struct {
+	Upsert []*models.StockpileMarker `json:"upsert"`
+	Delete []int64                   `json:"delete"`
 }
 
 type StockpileMarkers struct {
@@ -27,6 +44,11 @@ func NewStockpileMarkers(router Routerer, repository StockpileMarkersRepository)
 	router.RegisterRestAPIRoute("/v1/stockpiles", web.AuthAccessUser, controller.GetStockpiles, "GET")
 	router.RegisterRestAPIRoute("/v1/stockpiles", web.AuthAccessUser, controller.UpsertStockpile, "POST")
 	router.RegisterRestAPIRoute("/v1/stockpiles", web.AuthAccessUser, controller.DeleteStockpile, "DELETE")
+	router.RegisterRestAPIRoute("/v1/stockpiles/bulk", web.AuthAccessUser, controller.BulkStockpiles, "POST")
+	// Numeric IDs only, so /v1/stockpiles/deficits and friends still reach their controllers
+	router.RegisterRestAPIRoute("/v1/stockpiles/{id:[0-9]+}", web.AuthAccessUser, controller.GetStockpile, "GET")
+	router.RegisterRestAPIRoute("/v1/stockpiles/{id:[0-9]+}", web.AuthAccessUser, controller.UpdateStockpile, "PUT")
+	router.RegisterRestAPIRoute("/v1/stockpiles/{id:[0-9]+}", web.AuthAccessUser, controller.DeleteStockpileByID, "DELETE")
 
 	return controller
 }
@@ -57,6 +79,10 @@ func (c *StockpileMarkers) UpsertStockpile(args *web.HandlerArgs) (any, *web.Htt
 	// Set user ID from auth context
 	marker.UserID = *args.User
 
+	if httpErr := c.validateMarker(args.Request.Context(), &marker); httpErr != nil {
+		return nil, httpErr
+	}
+
 	err = c.repository.Upsert(args.Request.Context(), &marker)
 	if err != nil {
 		return nil, &web.HttpError{
@@ -68,6 +94,213 @@ func (c *StockpileMarkers) UpsertStockpile(args *web.HandlerArgs) (any, *web.Htt
 	return nil, nil
 }
 
+// GetStockpile returns one of the user's markers by ID
+func (c *StockpileMarkers) GetStockpile(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid stockpile marker ID")}
+	}
+
+	marker, err := c.repository.GetByID(args.Request.Context(), id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get stockpile marker")}
+	}
+	if marker == nil {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("stockpile marker not found")}
+	}
+
+	return marker, nil
+}
+
+// UpdateStockpile replaces one of the user's markers by ID. The body is a full marker, and may
+// move it to another item or target.
+func (c *StockpileMarkers) UpdateStockpile(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid stockpile marker ID")}
+	}
+
+	var marker models.StockpileMarker
+	err = json.NewDecoder(args.Request.Body).Decode(&marker)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "failed to decode json")}
+	}
+
+	marker.ID = id
+	marker.UserID = *args.User
+
+	if httpErr := c.validateMarker(args.Request.Context(), &marker); httpErr != nil {
+		return nil, httpErr
+	}
+
+	if httpErr := c.update(args.Request.Context(), &marker); httpErr != nil {
+		return nil, httpErr
+	}
+
+	return &marker, nil
+}
+
+// DeleteStockpileByID removes one of the user's markers by ID
+func (c *StockpileMarkers) DeleteStockpileByID(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid stockpile marker ID")}
+	}
+
+	found, err := c.repository.DeleteByID(args.Request.Context(), id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to delete stockpile marker")}
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("stockpile marker not found")}
+	}
+
+	return nil, nil
+}
+
+// BulkStockpiles saves and deletes many markers in one request. Upserts with an ID update that
+// marker and the rest upsert by item and target, like POST /v1/stockpiles. Each item succeeds or
+// fails on its own and gets a result, so one bad marker doesn't stop the rest.
+func (c *StockpileMarkers) BulkStockpiles(args *web.HandlerArgs) (any, *web.HttpError) {
+	var req stockpileBulkRequest
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "failed to decode json")}
+	}
+
+	if len(req.Upsert)+len(req.Delete) > maxStockpileBulkItems {
+		return nil, &web.HttpError{
+			StatusCode: http.StatusBadRequest,
+			Error:      errors.Errorf("a bulk request can hold at most %d markers", maxStockpileBulkItems),
+		}
+	}
+
+	ctx := args.Request.Context()
+	results := []*models.StockpileMarkerBulkResult{}
+
+	for i, marker := range req.Upsert {
+		result := &models.StockpileMarkerBulkResult{Operation: "upsert", Index: i}
+		results = append(results, result)
+
+		if marker == nil {
+			result.Error = "marker is missing"
+			continue
+		}
+		marker.UserID = *args.User
+
+		httpErr := c.validateMarker(ctx, marker)
+		if httpErr == nil {
+			if marker.ID != 0 {
+				httpErr = c.update(ctx, marker)
+			} else if err := c.repository.Upsert(ctx, marker); err != nil {
+				httpErr = &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to upsert stockpile marker")}
+			}
+		}
+
+		result.ID = marker.ID
+		result.Success = httpErr == nil
+		if httpErr != nil {
+			result.Error = bulkStockpileError(httpErr, *args.User)
+		}
+	}
+
+	for i, id := range req.Delete {
+		result := &models.StockpileMarkerBulkResult{Operation: "delete", Index: i, ID: id}
+		results = append(results, result)
+
+		found, err := c.repository.DeleteByID(ctx, id, *args.User)
+		switch {
+		case err != nil:
+			result.Error = bulkStockpileError(&web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to delete stockpile marker")}, *args.User)
+		case !found:
+			result.Error = "stockpile marker not found"
+		default:
+			result.Success = true
+		}
+	}
+
+	return results, nil
+}
+
+// bulkStockpileError is the message shown for a failed bulk item. Server errors are logged and
+// reported generically rather than exposing database details.
+func bulkStockpileError(httpErr *web.HttpError, userID int64) string {
+	if httpErr.StatusCode >= http.StatusInternalServerError {
+		log.Error("bulk stockpile marker item failed", "user_id", userID, "error", httpErr.Error)
+		return "internal error"
+	}
+	return httpErr.Error.Error()
+}
+
+// update saves a marker by ID, mapping a missing marker to 404 and a clash with another marker
+// to 409
+func (c *StockpileMarkers) update(ctx context.Context, marker *models.StockpileMarker) *web.HttpError {
+	found, err := c.repository.Update(ctx, marker)
+	if errors.Is(err, repositories.ErrStockpileMarkerExists) {
+		return &web.HttpError{StatusCode: http.StatusConflict, Error: err}
+	}
+	if err != nil {
+		return &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to update stockpile marker")}
+	}
+	if !found {
+		return &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("stockpile marker not found")}
+	}
+	return nil
+}
+
+// validateMarker checks a marker before it is saved: its scope, buy order and alert settings, and
+// that its owner, location and container belong to the user
+func (c *StockpileMarkers) validateMarker(ctx context.Context, marker *models.StockpileMarker) *web.HttpError {
+	if httpErr := validateStockpileScope(marker); httpErr != nil {
+		return httpErr
+	}
+
+	// The markup becomes a pricing rule percent on the generated buy order, so it has the same bounds
+	if marker.BuyOrderMarkupPercent <= -100 || marker.BuyOrderMarkupPercent > maxPricingRulePercent-100 {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("buyOrderMarkupPercent must be greater than -100 and at most %d", maxPricingRulePercent-100),
+		}
+	}
+
+	if marker.AlertBelowQuantity != nil && *marker.AlertBelowQuantity <= 0 {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("alertBelowQuantity must be greater than 0"),
+		}
+	}
+
+	if marker.AlertDeficitValue != nil && *marker.AlertDeficitValue <= 0 {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("alertDeficitValue must be greater than 0"),
+		}
+	}
+
+	if marker.DivisionNumber != nil && (marker.OwnerType != "corporation" || *marker.DivisionNumber < 1 || *marker.DivisionNumber > 7) {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("divisionNumber must be 1 to 7 and is only for corporation markers"),
+		}
+	}
+
+	problem, err := c.repository.CheckTarget(ctx, marker)
+	if err != nil {
+		return &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to check stockpile marker target"),
+		}
+	}
+	if problem != "" {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New(problem),
+		}
+	}
+
+	return nil
+}
+
 func (c *StockpileMarkers) DeleteStockpile(args *web.HandlerArgs) (any, *web.HttpError) {
 	d := json.NewDecoder(args.Request.Body)
 	var marker models.StockpileMarker
@@ -92,3 +325,60 @@ func (c *StockpileMarkers) DeleteStockpile(args *web.HandlerArgs) (any, *web.Htt
 
 	return nil, nil
 }
+
+// validateStockpileScope defaults an empty scope to location and checks the marker's fields make
+// sense for its scope: aggregate markers name a solar system or region (nothing for anywhere),
+// have no container or division, and may count every owner.
+func validateStockpileScope(marker *models.StockpileMarker) *web.HttpError {
+	if marker.Scope == "" {
+		marker.Scope = models.StockpileScopeLocation
+	}
+	if !models.IsValidStockpileScope(marker.Scope) {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("unknown stockpile scope %q", marker.Scope),
+		}
+	}
+
+	if marker.Scope == models.StockpileScopeLocation {
+		if marker.OwnerType == models.StockpileOwnerAll {
+			return &web.HttpError{
+				StatusCode: 400,
+				Error:      errors.New("location markers need a character or corporation owner"),
+			}
+		}
+		return nil
+	}
+
+	if marker.ContainerID != nil || marker.DivisionNumber != nil {
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("%s markers cannot target a container or division", marker.Scope),
+		}
+	}
+
+	switch marker.Scope {
+	case models.StockpileScopeAnywhere:
+		marker.LocationID = 0
+	default:
+		if marker.LocationID <= 0 {
+			return &web.HttpError{
+				StatusCode: 400,
+				Error:      errors.Errorf("%s markers need the %s ID as locationId", marker.Scope, strings.ReplaceAll(marker.Scope, "_", " ")),
+			}
+		}
+	}
+
+	switch marker.OwnerType {
+	case models.StockpileOwnerAll:
+		marker.OwnerID = 0
+	case "character", "corporation":
+	default:
+		return &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("ownerType must be character, corporation or all"),
+		}
+	}
+
+	return nil
+}
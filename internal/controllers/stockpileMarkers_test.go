@@ -10,6 +10,7 @@ import (
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -38,6 +39,29 @@ func (m *MockStockpileMarkersRepository) Delete(ctx context.Context, marker *mod
 	return args.Error(0)
 }
 
+func (m *MockStockpileMarkersRepository) GetByID(ctx context.Context, id int64, userID int64) (*models.StockpileMarker, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockpileMarker), args.Error(1)
+}
+
+func (m *MockStockpileMarkersRepository) Update(ctx context.Context, marker *models.StockpileMarker) (bool, error) {
+	args := m.Called(ctx, marker)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStockpileMarkersRepository) DeleteByID(ctx context.Context, id int64, userID int64) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStockpileMarkersRepository) CheckTarget(ctx context.Context, marker *models.StockpileMarker) (string, error) {
+	args := m.Called(ctx, marker)
+	return args.String(0), args.Error(1)
+}
+
 func Test_StockpileMarkersController_GetStockpiles_Success(t *testing.T) {
 	mockRepo := new(MockStockpileMarkersRepository)
 	mockRouter := &MockRouter{}
@@ -125,6 +149,7 @@ func Test_StockpileMarkersController_UpsertStockpile_Success(t *testing.T) {
 	}
 
 	// Expect the marker with UserID set
+	mockRepo.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
 	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool {
 		return m.UserID == userID && m.TypeID == 34
 	})).Return(nil)
@@ -180,6 +205,7 @@ func Test_StockpileMarkersController_UpsertStockpile_RepositoryError(t *testing.
 		DesiredQuantity: 1000,
 	}
 
+	mockRepo.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
 	mockRepo.On("Upsert", mock.Anything, mock.Anything).Return(errors.New("database error"))
 
 	body, _ := json.Marshal(marker)
@@ -286,3 +312,373 @@ func Test_StockpileMarkersController_DeleteStockpile_RepositoryError(t *testing.
 
 	mockRepo.AssertExpectations(t)
 }
+
+func Test_StockpileMarkersController_UpsertStockpile_AggregateScope(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool {
+		return m.Scope == models.StockpileScopeSolarSystem && m.OwnerType == models.StockpileOwnerAll && m.OwnerID == 0 && m.LocationID == 30000142
+	})).Return(nil)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool {
+		return m.Scope == models.StockpileScopeAnywhere && m.LocationID == 0 && m.OwnerID == 1337
+	})).Return(nil)
+
+	markers := []models.StockpileMarker{
+		{TypeID: 34, Scope: "solar_system", OwnerType: "all", OwnerID: 99, LocationID: 30000142, DesiredQuantity: 50000000},
+		{TypeID: 34, Scope: "anywhere", OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 1000},
+	}
+	for _, marker := range markers {
+		body, _ := json.Marshal(marker)
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+			User:    &userID,
+		}
+
+		_, httpErr := controller.UpsertStockpile(args)
+		assert.Nil(t, httpErr)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_InvalidScope(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	containerID := int64(1001)
+	markers := []models.StockpileMarker{
+		{TypeID: 34, Scope: "constellation", OwnerType: "character", OwnerID: 1337, LocationID: 20000020},
+		{TypeID: 34, Scope: "location", OwnerType: "all", LocationID: 60003760},
+		{TypeID: 34, Scope: "region", OwnerType: "all"},
+		{TypeID: 34, Scope: "solar_system", OwnerType: "all", LocationID: 30000142, ContainerID: &containerID},
+		{TypeID: 34, Scope: "anywhere", OwnerType: "alliance", OwnerID: 99000001},
+	}
+	for _, marker := range markers {
+		body, _ := json.Marshal(marker)
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+			User:    &userID,
+		}
+
+		result, httpErr := controller.UpsertStockpile(args)
+		assert.Nil(t, result)
+		if assert.NotNil(t, httpErr, marker.Scope) {
+			assert.Equal(t, 400, httpErr.StatusCode)
+		}
+	}
+
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_AutoBuyOrder(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	marker := models.StockpileMarker{
+		TypeID:                34,
+		OwnerType:             "character",
+		OwnerID:               1337,
+		LocationID:            60003760,
+		DesiredQuantity:       1000,
+		AutoBuyOrder:          true,
+		BuyOrderMarkupPercent: 5,
+	}
+	mockRepo.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool {
+		return m.AutoBuyOrder && m.BuyOrderMarkupPercent == 5
+	})).Return(nil)
+
+	body, _ := json.Marshal(marker)
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+		User:    &userID,
+	}
+
+	_, httpErr := controller.UpsertStockpile(args)
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_InvalidMarkup(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	for _, markup := range []float64{-100, 901} {
+		marker := models.StockpileMarker{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, AutoBuyOrder: true, BuyOrderMarkupPercent: markup}
+		body, _ := json.Marshal(marker)
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+			User:    &userID,
+		}
+
+		_, httpErr := controller.UpsertStockpile(args)
+		if assert.NotNil(t, httpErr) {
+			assert.Equal(t, 400, httpErr.StatusCode)
+		}
+	}
+
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_InvalidAlerts(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	zeroQuantity := int64(0)
+	negativeValue := -5.0
+	markers := []models.StockpileMarker{
+		{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 100, AlertBelowQuantity: &zeroQuantity},
+		{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 100, AlertDeficitValue: &negativeValue},
+	}
+	for _, marker := range markers {
+		body, _ := json.Marshal(marker)
+		args := &web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+			User:    &userID,
+		}
+
+		_, httpErr := controller.UpsertStockpile(args)
+		if assert.NotNil(t, httpErr) {
+			assert.Equal(t, 400, httpErr.StatusCode)
+		}
+	}
+
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileMarkersController_GetStockpile(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	marker := &models.StockpileMarker{ID: 7, UserID: userID, TypeID: 34}
+	mockRepo.On("GetByID", mock.Anything, int64(7), userID).Return(marker, nil)
+	mockRepo.On("GetByID", mock.Anything, int64(8), userID).Return(nil, nil)
+
+	result, httpErr := controller.GetStockpile(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/stockpiles/7", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "7"},
+	})
+	assert.Nil(t, httpErr)
+	assert.Equal(t, marker, result)
+
+	_, httpErr = controller.GetStockpile(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/stockpiles/8", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "8"},
+	})
+	if assert.NotNil(t, httpErr) {
+		assert.Equal(t, 404, httpErr.StatusCode)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileMarkersController_UpdateStockpile(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool {
+		return m.ID == 7 && m.UserID == userID && m.LocationID == 60008494 && m.DesiredQuantity == 500
+	})).Return(true, nil)
+
+	body, _ := json.Marshal(models.StockpileMarker{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60008494, DesiredQuantity: 500})
+	result, httpErr := controller.UpdateStockpile(&web.HandlerArgs{
+		Request: httptest.NewRequest("PUT", "/v1/stockpiles/7", bytes.NewReader(body)),
+		User:    &userID,
+		Params:  map[string]string{"id": "7"},
+	})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, int64(7), result.(*models.StockpileMarker).ID)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileMarkersController_UpdateStockpile_Errors(t *testing.T) {
+	userID := int64(42)
+	body, _ := json.Marshal(models.StockpileMarker{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 500})
+
+	tests := []struct {
+		name       string
+		setup      func(m *MockStockpileMarkersRepository)
+		statusCode int
+	}{
+		{"not found", func(m *MockStockpileMarkersRepository) {
+			m.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
+			m.On("Update", mock.Anything, mock.Anything).Return(false, nil)
+		}, 404},
+		{"clashes with another marker", func(m *MockStockpileMarkersRepository) {
+			m.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
+			m.On("Update", mock.Anything, mock.Anything).Return(false, repositories.ErrStockpileMarkerExists)
+		}, 409},
+		{"owner not the user's", func(m *MockStockpileMarkersRepository) {
+			m.On("CheckTarget", mock.Anything, mock.Anything).Return("owner is not one of your characters or corporations", nil)
+		}, 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockStockpileMarkersRepository)
+			tt.setup(mockRepo)
+			controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+			_, httpErr := controller.UpdateStockpile(&web.HandlerArgs{
+				Request: httptest.NewRequest("PUT", "/v1/stockpiles/7", bytes.NewReader(body)),
+				User:    &userID,
+				Params:  map[string]string{"id": "7"},
+			})
+			if assert.NotNil(t, httpErr) {
+				assert.Equal(t, tt.statusCode, httpErr.StatusCode)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_InvalidTarget(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("CheckTarget", mock.Anything, mock.Anything).Return("container is not one of the owner's items", nil)
+
+	containerID := int64(999)
+	body, _ := json.Marshal(models.StockpileMarker{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, ContainerID: &containerID, DesiredQuantity: 10})
+	_, httpErr := controller.UpsertStockpile(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	if assert.NotNil(t, httpErr) {
+		assert.Equal(t, 400, httpErr.StatusCode)
+		assert.Contains(t, httpErr.Error.Error(), "container")
+	}
+	mockRepo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileMarkersController_UpsertStockpile_InvalidDivision(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	division := 3
+	badDivision := 8
+	markers := []models.StockpileMarker{
+		{TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DivisionNumber: &division, DesiredQuantity: 10},
+		{TypeID: 34, OwnerType: "corporation", OwnerID: 2001, LocationID: 60003760, DivisionNumber: &badDivision, DesiredQuantity: 10},
+	}
+	for _, marker := range markers {
+		body, _ := json.Marshal(marker)
+		_, httpErr := controller.UpsertStockpile(&web.HandlerArgs{
+			Request: httptest.NewRequest("POST", "/v1/stockpiles", bytes.NewReader(body)),
+			User:    &userID,
+		})
+		if assert.NotNil(t, httpErr) {
+			assert.Equal(t, 400, httpErr.StatusCode)
+		}
+	}
+
+	mockRepo.AssertNotCalled(t, "CheckTarget", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileMarkersController_DeleteStockpileByID(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("DeleteByID", mock.Anything, int64(7), userID).Return(true, nil)
+	mockRepo.On("DeleteByID", mock.Anything, int64(8), userID).Return(false, nil)
+
+	_, httpErr := controller.DeleteStockpileByID(&web.HandlerArgs{
+		Request: httptest.NewRequest("DELETE", "/v1/stockpiles/7", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "7"},
+	})
+	assert.Nil(t, httpErr)
+
+	_, httpErr = controller.DeleteStockpileByID(&web.HandlerArgs{
+		Request: httptest.NewRequest("DELETE", "/v1/stockpiles/8", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "8"},
+	})
+	if assert.NotNil(t, httpErr) {
+		assert.Equal(t, 404, httpErr.StatusCode)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileMarkersController_BulkStockpiles(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+
+	mockRepo.On("CheckTarget", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool { return m.OwnerID == 1337 })).Return("", nil)
+	mockRepo.On("CheckTarget", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool { return m.OwnerID == 9999 })).
+		Return("owner is not one of your characters or corporations", nil)
+	mockRepo.On("Upsert", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool { return m.TypeID == 34 && m.UserID == userID })).
+		Run(func(args mock.Arguments) { args.Get(1).(*models.StockpileMarker).ID = 11 }).
+		Return(nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool { return m.ID == 5 })).Return(true, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(m *models.StockpileMarker) bool { return m.ID == 6 })).Return(false, errors.New("connection reset"))
+	mockRepo.On("DeleteByID", mock.Anything, int64(20), userID).Return(true, nil)
+	mockRepo.On("DeleteByID", mock.Anything, int64(21), userID).Return(false, nil)
+
+	body := []byte(`{
+		"upsert": [
+			{"typeId": 34, "ownerType": "character", "ownerId": 1337, "locationId": 60003760, "desiredQuantity": 100},
+			{"id": 5, "typeId": 35, "ownerType": "character", "ownerId": 1337, "locationId": 60003760, "desiredQuantity": 200},
+			{"typeId": 36, "ownerType": "character", "ownerId": 9999, "locationId": 60003760, "desiredQuantity": 300},
+			{"typeId": 37, "scope": "constellation", "ownerType": "character", "ownerId": 1337, "locationId": 20000020},
+			{"id": 6, "typeId": 38, "ownerType": "character", "ownerId": 1337, "locationId": 60003760, "desiredQuantity": 400}
+		],
+		"delete": [20, 21]
+	}`)
+	result, httpErr := controller.BulkStockpiles(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/stockpiles/bulk", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	results := result.([]*models.StockpileMarkerBulkResult)
+	expected := []*models.StockpileMarkerBulkResult{
+		{Operation: "upsert", Index: 0, ID: 11, Success: true},
+		{Operation: "upsert", Index: 1, ID: 5, Success: true},
+		{Operation: "upsert", Index: 2, Error: "owner is not one of your characters or corporations"},
+		{Operation: "upsert", Index: 3, Error: `unknown stockpile scope "constellation"`},
+		{Operation: "upsert", Index: 4, ID: 6, Error: "internal error"},
+		{Operation: "delete", Index: 0, ID: 20, Success: true},
+		{Operation: "delete", Index: 1, ID: 21, Error: "stockpile marker not found"},
+	}
+	assert.Equal(t, expected, results)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_StockpileMarkersController_BulkStockpiles_TooMany(t *testing.T) {
+	mockRepo := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileMarkers(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	ids := make([]int64, 501)
+	body, _ := json.Marshal(map[string][]int64{"delete": ids})
+	_, httpErr := controller.BulkStockpiles(&web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/stockpiles/bulk", bytes.NewReader(body)),
+		User:    &userID,
+	})
+
+	if assert.NotNil(t, httpErr) {
+		assert.Equal(t, 400, httpErr.StatusCode)
+	}
+	mockRepo.AssertNotCalled(t, "DeleteByID", mock.Anything, mock.Anything, mock.Anything)
+}
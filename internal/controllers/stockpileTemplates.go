@@ -0,0 +1,391 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	maxStockpileTemplateNameLength = 100
+	maxStockpileTemplateItems      = 1000
+)
+
+type StockpileTemplatesRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.StockpileTemplate, error)
+	GetByID(ctx context.Context, templateID, userID int64) (*models.StockpileTemplate, error)
+	Create(ctx context.Context, template *models.StockpileTemplate) error
+	Update(ctx context.Context, template *models.StockpileTemplate) error
+	Delete(ctx context.Context, templateID, userID int64) error
+	Apply(ctx context.Context, userID int64, application *models.StockpileTemplateApplication) error
+	Unapply(ctx context.Context, templateID, applicationID, userID int64) error
+}
+
+type StockpileTemplateItemTypeRepository interface {
+	GetItemTypesByNames(ctx context.Context, names []string) (map[string]models.EveInventoryType, error)
+}
+
+// StockpileTemplateTargetChecker validates where a template is applied the same way markers are
+type StockpileTemplateTargetChecker interface {
+	CheckTarget(ctx context.Context, marker *models.StockpileMarker) (string, error)
+}
+
+// StockpileTemplates manages reusable sets of stockpile markers such as doctrine fits
+type StockpileTemplates struct {
+	repository         StockpileTemplatesRepository
+	itemTypeRepository StockpileTemplateItemTypeRepository
+	targetChecker      StockpileTemplateTargetChecker
+}
+
+func NewStockpileTemplates(router Routerer, repository StockpileTemplatesRepository, itemTypeRepository StockpileTemplateItemTypeRepository, targetChecker StockpileTemplateTargetChecker) *StockpileTemplates {
+	controller := &StockpileTemplates{
+		repository:         repository,
+		itemTypeRepository: itemTypeRepository,
+		targetChecker:      targetChecker,
+	}
+
+	router.RegisterRestAPIRoute("/v1/stockpile-templates", web.AuthAccessUser, controller.GetTemplates, "GET")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates", web.AuthAccessUser, controller.CreateTemplate, "POST")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}", web.AuthAccessUser, controller.GetTemplate, "GET")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}", web.AuthAccessUser, controller.UpdateTemplate, "PUT")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}", web.AuthAccessUser, controller.DeleteTemplate, "DELETE")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}/applications", web.AuthAccessUser, controller.ApplyTemplate, "POST")
+	router.RegisterRestAPIRoute("/v1/stockpile-templates/{id}/applications/{applicationId}", web.AuthAccessUser, controller.UnapplyTemplate, "DELETE")
+
+	return controller
+}
+
+// StockpileTemplateRequest creates or replaces a template. Items and the EFT fit or
+// multibuy text in Import are combined, summing quantities of the same type.
+type StockpileTemplateRequest struct {
+	Name        string                          `json:"name"`
+	Description *string                         `json:"description"`
+	Items       []*models.StockpileTemplateItem `json:"items"`
+	Import      string                          `json:"import"`
+}
+
+// StockpileTemplateResponse is a saved template plus any import lines that could not be matched to a type
+type StockpileTemplateResponse struct {
+	*models.StockpileTemplate
+	Unresolved []string `json:"unresolved"`
+}
+
+// GetTemplates returns the user's templates with their items and where they are applied
+func (c *StockpileTemplates) GetTemplates(args *web.HandlerArgs) (any, *web.HttpError) {
+	templates, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get stockpile templates"),
+		}
+	}
+
+	return templates, nil
+}
+
+// GetTemplate returns one of the user's templates
+func (c *StockpileTemplates) GetTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	templateID, httpErr := parseStockpileTemplateID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	template, err := c.repository.GetByID(args.Request.Context(), templateID, *args.User)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to get stockpile template")
+	}
+
+	return template, nil
+}
+
+// CreateTemplate saves a new template from explicit items and/or imported text
+func (c *StockpileTemplates) CreateTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	template, unresolved, httpErr := c.decodeTemplate(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	ctx := args.Request.Context()
+	err := c.repository.Create(ctx, template)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to create stockpile template"),
+		}
+	}
+
+	saved, err := c.repository.GetByID(ctx, template.ID, *args.User)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to get stockpile template")
+	}
+
+	return &StockpileTemplateResponse{StockpileTemplate: saved, Unresolved: unresolved}, nil
+}
+
+// UpdateTemplate replaces a template's contents; every location it is applied to is updated to match
+func (c *StockpileTemplates) UpdateTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	templateID, httpErr := parseStockpileTemplateID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	template, unresolved, httpErr := c.decodeTemplate(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+	template.ID = templateID
+
+	ctx := args.Request.Context()
+	err := c.repository.Update(ctx, template)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to update stockpile template")
+	}
+
+	saved, err := c.repository.GetByID(ctx, templateID, *args.User)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to get stockpile template")
+	}
+
+	return &StockpileTemplateResponse{StockpileTemplate: saved, Unresolved: unresolved}, nil
+}
+
+// DeleteTemplate removes a template and the markers it generated
+func (c *StockpileTemplates) DeleteTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	templateID, httpErr := parseStockpileTemplateID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	err := c.repository.Delete(args.Request.Context(), templateID, *args.User)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to delete stockpile template")
+	}
+
+	return nil, nil
+}
+
+// ApplyTemplate generates markers for the template at an owner/location/container/division.
+// Applying again to the same place changes its multiplier.
+func (c *StockpileTemplates) ApplyTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	templateID, httpErr := parseStockpileTemplateID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	var application models.StockpileTemplateApplication
+	err := json.NewDecoder(args.Request.Body).Decode(&application)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+	application.TemplateID = templateID
+
+	if application.OwnerType != "character" && application.OwnerType != "corporation" {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("ownerType must be character or corporation"),
+		}
+	}
+	if application.OwnerID <= 0 || application.LocationID <= 0 {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("ownerId and locationId are required"),
+		}
+	}
+	if application.Multiplier == 0 {
+		application.Multiplier = 1
+	}
+	if application.Multiplier < 0 {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("multiplier must be positive"),
+		}
+	}
+
+	ctx := args.Request.Context()
+	problem, err := c.targetChecker.CheckTarget(ctx, &models.StockpileMarker{
+		UserID:         *args.User,
+		OwnerType:      application.OwnerType,
+		OwnerID:        application.OwnerID,
+		LocationID:     application.LocationID,
+		ContainerID:    application.ContainerID,
+		DivisionNumber: application.DivisionNumber,
+		Scope:          models.StockpileScopeLocation,
+	})
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to check stockpile template target"),
+		}
+	}
+	if problem != "" {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New(problem),
+		}
+	}
+
+	err = c.repository.Apply(ctx, *args.User, &application)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to apply stockpile template")
+	}
+
+	return &application, nil
+}
+
+// UnapplyTemplate removes the template from one location along with its generated markers
+func (c *StockpileTemplates) UnapplyTemplate(args *web.HandlerArgs) (any, *web.HttpError) {
+	templateID, httpErr := parseStockpileTemplateID(args)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	applicationID, err := strconv.ParseInt(args.Params["applicationId"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid application ID"),
+		}
+	}
+
+	err = c.repository.Unapply(args.Request.Context(), templateID, applicationID, *args.User)
+	if err != nil {
+		return nil, stockpileTemplateError(err, "failed to remove stockpile template application")
+	}
+
+	return nil, nil
+}
+
+// decodeTemplate validates a template request and resolves its imported text into items
+func (c *StockpileTemplates) decodeTemplate(args *web.HandlerArgs) (*models.StockpileTemplate, []string, *web.HttpError) {
+	var req StockpileTemplateRequest
+	err := json.NewDecoder(args.Request.Body).Decode(&req)
+	if err != nil {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "failed to decode json"),
+		}
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("name is required"),
+		}
+	}
+	if len(name) > maxStockpileTemplateNameLength {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("name must be at most %d characters", maxStockpileTemplateNameLength),
+		}
+	}
+
+	template := &models.StockpileTemplate{
+		UserID:      *args.User,
+		Name:        name,
+		Description: req.Description,
+		Items:       []*models.StockpileTemplateItem{},
+	}
+
+	byType := map[int64]*models.StockpileTemplateItem{}
+	addItem := func(typeID int64, typeName string, quantity int64) {
+		if item, ok := byType[typeID]; ok {
+			item.Quantity += quantity
+			return
+		}
+		item := &models.StockpileTemplateItem{TypeID: typeID, TypeName: typeName, Quantity: quantity}
+		byType[typeID] = item
+		template.Items = append(template.Items, item)
+	}
+
+	for _, item := range req.Items {
+		if item == nil || item.TypeID <= 0 || item.Quantity <= 0 {
+			return nil, nil, &web.HttpError{
+				StatusCode: 400,
+				Error:      errors.New("items need a typeId and a positive quantity"),
+			}
+		}
+		addItem(item.TypeID, item.TypeName, item.Quantity)
+	}
+
+	unresolved := []string{}
+	if strings.TrimSpace(req.Import) != "" {
+		lines, unparsed := parseAppraisalPaste(req.Import)
+		unresolved = append(unresolved, unparsed...)
+
+		names := make([]string, len(lines))
+		for i, line := range lines {
+			names[i] = line.Name
+		}
+
+		itemTypes := map[string]models.EveInventoryType{}
+		if len(names) > 0 {
+			itemTypes, err = c.itemTypeRepository.GetItemTypesByNames(args.Request.Context(), names)
+			if err != nil {
+				return nil, nil, &web.HttpError{
+					StatusCode: 500,
+					Error:      errors.Wrap(err, "failed to resolve item types"),
+				}
+			}
+		}
+
+		for _, line := range lines {
+			itemType, ok := itemTypes[strings.ToLower(line.Name)]
+			if !ok {
+				unresolved = append(unresolved, line.Name)
+				continue
+			}
+			addItem(itemType.TypeID, itemType.TypeName, line.Quantity)
+		}
+	}
+
+	if len(template.Items) == 0 {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.New("template must contain at least one known item"),
+		}
+	}
+	if len(template.Items) > maxStockpileTemplateItems {
+		return nil, nil, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Errorf("templates can hold at most %d item types", maxStockpileTemplateItems),
+		}
+	}
+
+	return template, unresolved, nil
+}
+
+func parseStockpileTemplateID(args *web.HandlerArgs) (int64, *web.HttpError) {
+	templateID, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return 0, &web.HttpError{
+			StatusCode: 400,
+			Error:      errors.Wrap(err, "invalid stockpile template ID"),
+		}
+	}
+	return templateID, nil
+}
+
+// stockpileTemplateError maps repository not-found errors to 404 and overlapping markers to 409
+func stockpileTemplateError(err error, message string) *web.HttpError {
+	switch {
+	case errors.Is(err, repositories.ErrStockpileTemplateNotFound),
+		errors.Is(err, repositories.ErrStockpileTemplateApplicationNotFound):
+		return &web.HttpError{StatusCode: 404, Error: err}
+	case errors.Is(err, repositories.ErrStockpileTemplateConflict):
+		return &web.HttpError{StatusCode: 409, Error: err}
+	}
+	return &web.HttpError{
+		StatusCode: 500,
+		Error:      errors.Wrap(err, message),
+	}
+}
@@ -0,0 +1,303 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockStockpileTemplatesRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockpileTemplatesRepository) GetByUser(ctx context.Context, userID int64) ([]*models.StockpileTemplate, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockpileTemplate), args.Error(1)
+}
+
+func (m *MockStockpileTemplatesRepository) GetByID(ctx context.Context, templateID, userID int64) (*models.StockpileTemplate, error) {
+	args := m.Called(ctx, templateID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockpileTemplate), args.Error(1)
+}
+
+func (m *MockStockpileTemplatesRepository) Create(ctx context.Context, template *models.StockpileTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) Update(ctx context.Context, template *models.StockpileTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) Delete(ctx context.Context, templateID, userID int64) error {
+	args := m.Called(ctx, templateID, userID)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) Apply(ctx context.Context, userID int64, application *models.StockpileTemplateApplication) error {
+	args := m.Called(ctx, userID, application)
+	return args.Error(0)
+}
+
+func (m *MockStockpileTemplatesRepository) Unapply(ctx context.Context, templateID, applicationID, userID int64) error {
+	args := m.Called(ctx, templateID, applicationID, userID)
+	return args.Error(0)
+}
+
+func stockpileTemplateArgs(t *testing.T, method string, body any, params map[string]string) *web.HandlerArgs {
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	userID := int64(42)
+	return &web.HandlerArgs{
+		Request: httptest.NewRequest(method, "/v1/stockpile-templates", bytes.NewReader(payload)),
+		User:    &userID,
+		Params:  params,
+	}
+}
+
+func Test_StockpileTemplatesController_CreateFromEFTFit(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	itemTypes := new(MockAppraisalItemTypeRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, itemTypes, new(MockStockpileMarkersRepository))
+
+	fit := "[Rifter, Tackle Rifter]\n" +
+		"Damage Control I\n" +
+		"[Empty Low slot]\n" +
+		"200mm AutoCannon I, EMP S\n" +
+		"200mm AutoCannon I, EMP S\n" +
+		"Warp Scrambler II\n" +
+		"\n" +
+		"EMP S x400\n"
+
+	itemTypes.On("GetItemTypesByNames", mock.Anything, []string{"Rifter", "Damage Control I", "200mm AutoCannon I", "Warp Scrambler II", "EMP S"}).
+		Return(appraisalCatalog, nil)
+
+	var created *models.StockpileTemplate
+	repo.On("Create", mock.Anything, mock.AnythingOfType("*models.StockpileTemplate")).
+		Run(func(args mock.Arguments) {
+			created = args.Get(1).(*models.StockpileTemplate)
+			created.ID = 7
+		}).
+		Return(nil)
+	repo.On("GetByID", mock.Anything, int64(7), int64(42)).Return(&models.StockpileTemplate{ID: 7, Name: "Rifter doctrine"}, nil)
+
+	result, httpErr := controller.CreateTemplate(stockpileTemplateArgs(t, "POST", controllers.StockpileTemplateRequest{
+		Name:   "  Rifter doctrine ",
+		Items:  []*models.StockpileTemplateItem{{TypeID: 185, Quantity: 100}},
+		Import: fit,
+	}, nil))
+	require.Nil(t, httpErr)
+
+	response := result.(*controllers.StockpileTemplateResponse)
+	assert.Equal(t, int64(7), response.ID)
+	assert.Equal(t, []string{"Warp Scrambler II"}, response.Unresolved)
+
+	require.NotNil(t, created)
+	assert.Equal(t, "Rifter doctrine", created.Name)
+	assert.Equal(t, int64(42), created.UserID)
+
+	quantities := map[int64]int64{}
+	for _, item := range created.Items {
+		quantities[item.TypeID] = item.Quantity
+	}
+	assert.Equal(t, map[int64]int64{
+		185:  500, // explicit 100 plus 400 from the cargo line
+		587:  1,
+		2046: 1,
+		2889: 2,
+	}, quantities)
+}
+
+func Test_StockpileTemplatesController_CreateValidation(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	itemTypes := new(MockAppraisalItemTypeRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, itemTypes, new(MockStockpileMarkersRepository))
+
+	cases := []controllers.StockpileTemplateRequest{
+		{Name: " ", Items: []*models.StockpileTemplateItem{{TypeID: 34, Quantity: 1}}},
+		{Name: "Empty"},
+		{Name: "Bad quantity", Items: []*models.StockpileTemplateItem{{TypeID: 34, Quantity: 0}}},
+	}
+	for _, req := range cases {
+		result, httpErr := controller.CreateTemplate(stockpileTemplateArgs(t, "POST", req, nil))
+		assert.Nil(t, result)
+		require.NotNil(t, httpErr)
+		assert.Equal(t, 400, httpErr.StatusCode)
+	}
+
+	// Nothing in the import resolves
+	itemTypes.On("GetItemTypesByNames", mock.Anything, []string{"Unobtainium"}).Return(appraisalCatalog, nil)
+	result, httpErr := controller.CreateTemplate(stockpileTemplateArgs(t, "POST", controllers.StockpileTemplateRequest{
+		Name:   "Unknown",
+		Import: "Unobtainium x5",
+	}, nil))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_StockpileTemplatesController_UpdateTemplate(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), new(MockStockpileMarkersRepository))
+
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(template *models.StockpileTemplate) bool {
+		return template.ID == 7 && template.UserID == 42 && len(template.Items) == 1 && template.Items[0].Quantity == 3000
+	})).Return(nil)
+	repo.On("GetByID", mock.Anything, int64(7), int64(42)).Return(&models.StockpileTemplate{ID: 7}, nil)
+
+	result, httpErr := controller.UpdateTemplate(stockpileTemplateArgs(t, "PUT", controllers.StockpileTemplateRequest{
+		Name:  "Capital fuel month",
+		Items: []*models.StockpileTemplateItem{{TypeID: 16274, Quantity: 1000}, {TypeID: 16274, Quantity: 2000}},
+	}, map[string]string{"id": "7"}))
+	require.Nil(t, httpErr)
+	assert.Equal(t, int64(7), result.(*controllers.StockpileTemplateResponse).ID)
+	repo.AssertExpectations(t)
+}
+
+func Test_StockpileTemplatesController_UpdateNotFound(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), new(MockStockpileMarkersRepository))
+
+	repo.On("Update", mock.Anything, mock.Anything).Return(repositories.ErrStockpileTemplateNotFound)
+
+	result, httpErr := controller.UpdateTemplate(stockpileTemplateArgs(t, "PUT", controllers.StockpileTemplateRequest{
+		Name:  "Someone else's",
+		Items: []*models.StockpileTemplateItem{{TypeID: 34, Quantity: 1}},
+	}, map[string]string{"id": "99"}))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_StockpileTemplatesController_ApplyTemplate(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	targets := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), targets)
+
+	division := 2
+	targets.On("CheckTarget", mock.Anything, mock.MatchedBy(func(marker *models.StockpileMarker) bool {
+		return marker.UserID == 42 &&
+			marker.OwnerType == "corporation" &&
+			marker.OwnerID == 98000001 &&
+			marker.LocationID == 60003760 &&
+			*marker.DivisionNumber == division
+	})).Return("", nil)
+	repo.On("Apply", mock.Anything, int64(42), mock.MatchedBy(func(application *models.StockpileTemplateApplication) bool {
+		return application.TemplateID == 7 &&
+			application.OwnerType == "corporation" &&
+			application.LocationID == 60003760 &&
+			*application.DivisionNumber == division &&
+			application.Multiplier == 1
+	})).Return(nil)
+
+	result, httpErr := controller.ApplyTemplate(stockpileTemplateArgs(t, "POST", models.StockpileTemplateApplication{
+		OwnerType:      "corporation",
+		OwnerID:        98000001,
+		LocationID:     60003760,
+		DivisionNumber: &division,
+	}, map[string]string{"id": "7"}))
+	require.Nil(t, httpErr)
+	assert.Equal(t, int64(1), result.(*models.StockpileTemplateApplication).Multiplier)
+	repo.AssertExpectations(t)
+	targets.AssertExpectations(t)
+}
+
+func Test_StockpileTemplatesController_ApplyConflict(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	targets := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), targets)
+
+	targets.On("CheckTarget", mock.Anything, mock.Anything).Return("", nil)
+	repo.On("Apply", mock.Anything, int64(42), mock.Anything).Return(repositories.ErrStockpileTemplateConflict)
+
+	result, httpErr := controller.ApplyTemplate(stockpileTemplateArgs(t, "POST", models.StockpileTemplateApplication{
+		OwnerType:  "character",
+		OwnerID:    90000001,
+		LocationID: 60003760,
+	}, map[string]string{"id": "7"}))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 409, httpErr.StatusCode)
+}
+
+func Test_StockpileTemplatesController_ApplyToForeignTarget(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	targets := new(MockStockpileMarkersRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), targets)
+
+	targets.On("CheckTarget", mock.Anything, mock.Anything).Return("owner is not one of your characters or corporations", nil)
+
+	result, httpErr := controller.ApplyTemplate(stockpileTemplateArgs(t, "POST", models.StockpileTemplateApplication{
+		OwnerType:  "character",
+		OwnerID:    90000001,
+		LocationID: 60003760,
+	}, map[string]string{"id": "7"}))
+	assert.Nil(t, result)
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+	assert.EqualError(t, httpErr.Error, "owner is not one of your characters or corporations")
+	repo.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_StockpileTemplatesController_ApplyValidation(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), new(MockStockpileMarkersRepository))
+
+	cases := []models.StockpileTemplateApplication{
+		{OwnerType: "alliance", OwnerID: 1, LocationID: 1},
+		{OwnerType: "character", LocationID: 1},
+		{OwnerType: "character", OwnerID: 1, LocationID: 1, Multiplier: -2},
+	}
+	for _, application := range cases {
+		result, httpErr := controller.ApplyTemplate(stockpileTemplateArgs(t, "POST", application, map[string]string{"id": "7"}))
+		assert.Nil(t, result)
+		require.NotNil(t, httpErr)
+		assert.Equal(t, 400, httpErr.StatusCode)
+	}
+
+	repo.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func Test_StockpileTemplatesController_UnapplyAndDelete(t *testing.T) {
+	repo := new(MockStockpileTemplatesRepository)
+	controller := controllers.NewStockpileTemplates(&MockRouter{}, repo, new(MockAppraisalItemTypeRepository), new(MockStockpileMarkersRepository))
+
+	repo.On("Unapply", mock.Anything, int64(7), int64(3), int64(42)).Return(nil)
+	repo.On("Unapply", mock.Anything, int64(7), int64(4), int64(42)).Return(repositories.ErrStockpileTemplateApplicationNotFound)
+	repo.On("Delete", mock.Anything, int64(7), int64(42)).Return(nil)
+
+	_, httpErr := controller.UnapplyTemplate(stockpileTemplateArgs(t, "DELETE", nil, map[string]string{"id": "7", "applicationId": "3"}))
+	assert.Nil(t, httpErr)
+
+	_, httpErr = controller.UnapplyTemplate(stockpileTemplateArgs(t, "DELETE", nil, map[string]string{"id": "7", "applicationId": "4"}))
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+
+	_, httpErr = controller.UnapplyTemplate(stockpileTemplateArgs(t, "DELETE", nil, map[string]string{"id": "7", "applicationId": "x"}))
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 400, httpErr.StatusCode)
+
+	_, httpErr = controller.DeleteTemplate(stockpileTemplateArgs(t, "DELETE", nil, map[string]string{"id": "7"}))
+	assert.Nil(t, httpErr)
+	repo.AssertExpectations(t)
+}
@@ -2,29 +2,55 @@ package controllers
 
 import (
 	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Burn rates are computed over the last 30 days of samples, and need a day of history
+	stockpileBurnWindowDays = 30
+	stockpileMinHistory     = 24 * time.Hour
+
+	// stockpileCoverDays is how many days of consumption the recommended desired quantity holds
+	stockpileCoverDays = 14
+
+	maxStockpileHistoryDays = 90
 )
 
 type StockpilesRepository interface {
 	GetStockpileDeficits(ctx context.Context, user int64) (*repositories.StockpilesResponse, error)
 }
 
+type StockpileHistoryRepository interface {
+	GetSince(ctx context.Context, userID int64, since time.Time) (map[int64][]*models.StockpileHistorySample, error)
+	GetByMarker(ctx context.Context, markerID, userID int64, since time.Time) ([]*models.StockpileHistorySample, error)
+}
+
 type Stockpiles struct {
 	stockpilesRepo StockpilesRepository
+	historyRepo    StockpileHistoryRepository
 }
 
-func NewStockpiles(router Routerer, stockpilesRepo StockpilesRepository) *Stockpiles {
+func NewStockpiles(router Routerer, stockpilesRepo StockpilesRepository, historyRepo StockpileHistoryRepository) *Stockpiles {
 	controller := &Stockpiles{
 		stockpilesRepo: stockpilesRepo,
+		historyRepo:    historyRepo,
 	}
 
 	router.RegisterRestAPIRoute("/v1/stockpiles/deficits", web.AuthAccessUser, controller.GetDeficits, "GET")
+	router.RegisterRestAPIRoute("/v1/stockpiles/{id:[0-9]+}/history", web.AuthAccessUser, controller.GetHistory, "GET")
 
 	return controller
 }
 
+// GetDeficits returns the user's stockpile deficits, each with the burn rate of its marker
 func (c *Stockpiles) GetDeficits(args *web.HandlerArgs) (interface{}, *web.HttpError) {
 	stockpiles, err := c.stockpilesRepo.GetStockpileDeficits(args.Request.Context(), *args.User)
 	if err != nil {
@@ -34,5 +60,87 @@ func (c *Stockpiles) GetDeficits(args *web.HandlerArgs) (interface{}, *web.HttpE
 		}
 	}
 
+	if len(stockpiles.Items) == 0 {
+		return stockpiles, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -stockpileBurnWindowDays)
+	history, err := c.historyRepo.GetSince(args.Request.Context(), *args.User, since)
+	if err != nil {
+		return nil, &web.HttpError{
+			StatusCode: 500,
+			Error:      errors.Wrap(err, "failed to get stockpile history"),
+		}
+	}
+
+	for _, item := range stockpiles.Items {
+		item.StockpileConsumption = stockpileConsumption(history[item.MarkerID])
+	}
+
 	return stockpiles, nil
 }
+
+// GetHistory returns the samples recorded for one of the user's markers over the last ?days=
+// (default 30) and the burn rate they give
+func (c *Stockpiles) GetHistory(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid stockpile marker ID")}
+	}
+
+	days := stockpileBurnWindowDays
+	if daysStr := args.Request.URL.Query().Get("days"); daysStr != "" {
+		days, err = strconv.Atoi(daysStr)
+		if err != nil || days < 1 || days > maxStockpileHistoryDays {
+			return nil, &web.HttpError{
+				StatusCode: http.StatusBadRequest,
+				Error:      errors.Errorf("days must be between 1 and %d", maxStockpileHistoryDays),
+			}
+		}
+	}
+
+	samples, err := c.historyRepo.GetByMarker(args.Request.Context(), id, *args.User, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: 500, Error: errors.Wrap(err, "failed to get stockpile marker history")}
+	}
+
+	return &models.StockpileHistory{
+		MarkerID:             id,
+		Samples:              samples,
+		StockpileConsumption: stockpileConsumption(samples),
+	}, nil
+}
+
+// stockpileConsumption derives a burn rate from samples ordered oldest first. Only drops between
+// samples count as consumption, so restocking does not hide what was used. Markers with less than
+// a day of history have no burn rate.
+func stockpileConsumption(samples []*models.StockpileHistorySample) models.StockpileConsumption {
+	consumption := models.StockpileConsumption{}
+	if len(samples) < 2 {
+		return consumption
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	span := last.RecordedAt.Sub(first.RecordedAt)
+	if span < stockpileMinHistory {
+		return consumption
+	}
+
+	consumed := int64(0)
+	for i := 1; i < len(samples); i++ {
+		if drop := samples[i-1].Quantity - samples[i].Quantity; drop > 0 {
+			consumed += drop
+		}
+	}
+
+	burnRate := float64(consumed) / span.Hours() * 24
+	consumption.BurnRatePerDay = &burnRate
+	if burnRate > 0 {
+		daysUntilEmpty := float64(last.Quantity) / burnRate
+		recommended := int64(math.Ceil(burnRate * stockpileCoverDays))
+		consumption.DaysUntilEmpty = &daysUntilEmpty
+		consumption.RecommendedQuantity = &recommended
+	}
+
+	return consumption
+}
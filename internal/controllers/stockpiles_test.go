@@ -5,8 +5,10 @@ import (
 	"errors"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 	"github.com/annymsMthd/industry-tool/internal/web"
 	"github.com/stretchr/testify/assert"
@@ -26,11 +28,33 @@ func (m *MockStockpilesRepository) GetStockpileDeficits(ctx context.Context, use
 	return args.Get(0).(*repositories.StockpilesResponse), args.Error(1)
 }
 
+// MockStockpileHistoryRepository mocks the StockpileHistoryRepository interface
+type MockStockpileHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockpileHistoryRepository) GetSince(ctx context.Context, userID int64, since time.Time) (map[int64][]*models.StockpileHistorySample, error) {
+	args := m.Called(ctx, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int64][]*models.StockpileHistorySample), args.Error(1)
+}
+
+func (m *MockStockpileHistoryRepository) GetByMarker(ctx context.Context, markerID, userID int64, since time.Time) ([]*models.StockpileHistorySample, error) {
+	args := m.Called(ctx, markerID, userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.StockpileHistorySample), args.Error(1)
+}
+
 func Test_StockpilesController_GetDeficits_Success(t *testing.T) {
 	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewStockpiles(mockRouter, mockRepo)
+	controller := controllers.NewStockpiles(mockRouter, mockRepo, mockHistory)
 
 	userID := int64(42)
 	expectedResponse := &repositories.StockpilesResponse{
@@ -55,6 +79,7 @@ func Test_StockpilesController_GetDeficits_Success(t *testing.T) {
 	}
 
 	mockRepo.On("GetStockpileDeficits", mock.Anything, userID).Return(expectedResponse, nil)
+	mockHistory.On("GetSince", mock.Anything, userID, mock.Anything).Return(map[int64][]*models.StockpileHistorySample{}, nil)
 
 	req := httptest.NewRequest("GET", "/v1/stockpiles/deficits", nil)
 	args := &web.HandlerArgs{
@@ -78,9 +103,10 @@ func Test_StockpilesController_GetDeficits_Success(t *testing.T) {
 
 func Test_StockpilesController_GetDeficits_EmptyResult(t *testing.T) {
 	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewStockpiles(mockRouter, mockRepo)
+	controller := controllers.NewStockpiles(mockRouter, mockRepo, mockHistory)
 
 	userID := int64(42)
 	expectedResponse := &repositories.StockpilesResponse{
@@ -108,9 +134,10 @@ func Test_StockpilesController_GetDeficits_EmptyResult(t *testing.T) {
 
 func Test_StockpilesController_GetDeficits_RepositoryError(t *testing.T) {
 	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewStockpiles(mockRouter, mockRepo)
+	controller := controllers.NewStockpiles(mockRouter, mockRepo, mockHistory)
 
 	userID := int64(42)
 
@@ -134,9 +161,10 @@ func Test_StockpilesController_GetDeficits_RepositoryError(t *testing.T) {
 
 func Test_StockpilesController_GetDeficits_MultipleDeficits(t *testing.T) {
 	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewStockpiles(mockRouter, mockRepo)
+	controller := controllers.NewStockpiles(mockRouter, mockRepo, mockHistory)
 
 	userID := int64(42)
 	containerName := "Station Warehouse Container"
@@ -163,6 +191,7 @@ func Test_StockpilesController_GetDeficits_MultipleDeficits(t *testing.T) {
 	}
 
 	mockRepo.On("GetStockpileDeficits", mock.Anything, userID).Return(expectedResponse, nil)
+	mockHistory.On("GetSince", mock.Anything, userID, mock.Anything).Return(map[int64][]*models.StockpileHistorySample{}, nil)
 
 	req := httptest.NewRequest("GET", "/v1/stockpiles/deficits", nil)
 	args := &web.HandlerArgs{
@@ -187,9 +216,10 @@ func Test_StockpilesController_GetDeficits_MultipleDeficits(t *testing.T) {
 
 func Test_StockpilesController_Constructor_RegistersRoute(t *testing.T) {
 	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewStockpiles(mockRouter, mockRepo)
+	controller := controllers.NewStockpiles(mockRouter, mockRepo, mockHistory)
 
 	assert.NotNil(t, controller)
 	// Route registration is verified by the existence of the controller
@@ -197,9 +227,10 @@ func Test_StockpilesController_Constructor_RegistersRoute(t *testing.T) {
 
 func Test_StockpilesController_GetDeficits_WithContext(t *testing.T) {
 	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewStockpiles(mockRouter, mockRepo)
+	controller := controllers.NewStockpiles(mockRouter, mockRepo, mockHistory)
 
 	userID := int64(42)
 	expectedResponse := &repositories.StockpilesResponse{
@@ -226,3 +257,153 @@ func Test_StockpilesController_GetDeficits_WithContext(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockRepo.AssertNumberOfCalls(t, "GetStockpileDeficits", 1)
 }
+
+func Test_StockpilesController_GetDeficits_BurnRate(t *testing.T) {
+	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
+	controller := controllers.NewStockpiles(&MockRouter{}, mockRepo, mockHistory)
+
+	userID := int64(42)
+	fuel := &repositories.StockpileItem{Name: "Nitrogen Fuel Block", TypeID: 4051, MarkerID: 7, Quantity: 400, DesiredQuantity: 2000, StockpileDelta: -1600}
+	ammo := &repositories.StockpileItem{Name: "Scourge Heavy Missile", TypeID: 209, MarkerID: 8, Quantity: 100, DesiredQuantity: 500, StockpileDelta: -400}
+	fresh := &repositories.StockpileItem{Name: "Tritanium", TypeID: 34, MarkerID: 9, Quantity: 10, DesiredQuantity: 500, StockpileDelta: -490}
+	unused := &repositories.StockpileItem{Name: "Pyerite", TypeID: 35, MarkerID: 10, Quantity: 10, DesiredQuantity: 500, StockpileDelta: -490}
+
+	mockRepo.On("GetStockpileDeficits", mock.Anything, userID).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{fuel, ammo, fresh, unused},
+	}, nil)
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	mockHistory.On("GetSince", mock.Anything, userID, mock.Anything).Return(map[int64][]*models.StockpileHistorySample{
+		// 1,200 used over 4 days, with a restock of 600 that is not counted
+		7: {
+			{MarkerID: 7, RecordedAt: start, Quantity: 1000},
+			{MarkerID: 7, RecordedAt: start.Add(day), Quantity: 800},
+			{MarkerID: 7, RecordedAt: start.Add(2 * day), Quantity: 1400},
+			{MarkerID: 7, RecordedAt: start.Add(4 * day), Quantity: 400},
+		},
+		// 30 used over 2 days
+		8: {
+			{MarkerID: 8, RecordedAt: start, Quantity: 130},
+			{MarkerID: 8, RecordedAt: start.Add(2 * day), Quantity: 100},
+		},
+		// Less than a day of history
+		9: {
+			{MarkerID: 9, RecordedAt: start, Quantity: 50},
+			{MarkerID: 9, RecordedAt: start.Add(12 * time.Hour), Quantity: 10},
+		},
+		10: {
+			{MarkerID: 10, RecordedAt: start, Quantity: 10},
+			{MarkerID: 10, RecordedAt: start.Add(3 * day), Quantity: 10},
+		},
+	}, nil)
+
+	result, httpErr := controller.GetDeficits(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/stockpiles/deficits", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, httpErr)
+	response := result.(*repositories.StockpilesResponse)
+
+	if assert.NotNil(t, response.Items[0].BurnRatePerDay) {
+		assert.InDelta(t, 300.0, *response.Items[0].BurnRatePerDay, 0.001)
+		assert.InDelta(t, 1.333, *response.Items[0].DaysUntilEmpty, 0.001)
+		assert.Equal(t, int64(4200), *response.Items[0].RecommendedQuantity)
+	}
+
+	if assert.NotNil(t, response.Items[1].BurnRatePerDay) {
+		assert.InDelta(t, 15.0, *response.Items[1].BurnRatePerDay, 0.001)
+		assert.InDelta(t, 6.667, *response.Items[1].DaysUntilEmpty, 0.001)
+		assert.Equal(t, int64(210), *response.Items[1].RecommendedQuantity)
+	}
+
+	assert.Nil(t, response.Items[2].BurnRatePerDay)
+	assert.Nil(t, response.Items[2].DaysUntilEmpty)
+	assert.Nil(t, response.Items[2].RecommendedQuantity)
+
+	if assert.NotNil(t, response.Items[3].BurnRatePerDay) {
+		assert.Equal(t, 0.0, *response.Items[3].BurnRatePerDay)
+	}
+	assert.Nil(t, response.Items[3].DaysUntilEmpty)
+	assert.Nil(t, response.Items[3].RecommendedQuantity)
+
+	mockRepo.AssertExpectations(t)
+	mockHistory.AssertExpectations(t)
+}
+
+func Test_StockpilesController_GetDeficits_HistoryError(t *testing.T) {
+	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
+	controller := controllers.NewStockpiles(&MockRouter{}, mockRepo, mockHistory)
+
+	userID := int64(42)
+	mockRepo.On("GetStockpileDeficits", mock.Anything, userID).Return(&repositories.StockpilesResponse{
+		Items: []*repositories.StockpileItem{{Name: "Tritanium", TypeID: 34, MarkerID: 7}},
+	}, nil)
+	mockHistory.On("GetSince", mock.Anything, userID, mock.Anything).Return(nil, errors.New("database error"))
+
+	result, httpErr := controller.GetDeficits(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/stockpiles/deficits", nil),
+		User:    &userID,
+	})
+
+	assert.Nil(t, result)
+	if assert.NotNil(t, httpErr) {
+		assert.Equal(t, 500, httpErr.StatusCode)
+	}
+}
+
+func Test_StockpilesController_GetHistory(t *testing.T) {
+	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
+	controller := controllers.NewStockpiles(&MockRouter{}, mockRepo, mockHistory)
+
+	userID := int64(42)
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	samples := []*models.StockpileHistorySample{
+		{MarkerID: 7, RecordedAt: start, Quantity: 300},
+		{MarkerID: 7, RecordedAt: start.Add(48 * time.Hour), Quantity: 100},
+	}
+	mockHistory.On("GetByMarker", mock.Anything, int64(7), userID, mock.MatchedBy(func(since time.Time) bool {
+		return time.Since(since) > 6*24*time.Hour && time.Since(since) < 8*24*time.Hour
+	})).Return(samples, nil)
+
+	result, httpErr := controller.GetHistory(&web.HandlerArgs{
+		Request: httptest.NewRequest("GET", "/v1/stockpiles/7/history?days=7", nil),
+		User:    &userID,
+		Params:  map[string]string{"id": "7"},
+	})
+
+	assert.Nil(t, httpErr)
+	history := result.(*models.StockpileHistory)
+	assert.Equal(t, int64(7), history.MarkerID)
+	assert.Len(t, history.Samples, 2)
+	if assert.NotNil(t, history.BurnRatePerDay) {
+		assert.InDelta(t, 100.0, *history.BurnRatePerDay, 0.001)
+		assert.InDelta(t, 1.0, *history.DaysUntilEmpty, 0.001)
+		assert.Equal(t, int64(1400), *history.RecommendedQuantity)
+	}
+	mockHistory.AssertExpectations(t)
+}
+
+func Test_StockpilesController_GetHistory_InvalidDays(t *testing.T) {
+	mockRepo := new(MockStockpilesRepository)
+	mockHistory := new(MockStockpileHistoryRepository)
+	controller := controllers.NewStockpiles(&MockRouter{}, mockRepo, mockHistory)
+
+	userID := int64(42)
+	for _, days := range []string{"0", "91", "week"} {
+		_, httpErr := controller.GetHistory(&web.HandlerArgs{
+			Request: httptest.NewRequest("GET", "/v1/stockpiles/7/history?days="+days, nil),
+			User:    &userID,
+			Params:  map[string]string{"id": "7"},
+		})
+		if assert.NotNil(t, httpErr, days) {
+			assert.Equal(t, 400, httpErr.StatusCode)
+		}
+	}
+
+	mockHistory.AssertNotCalled(t, "GetByMarker", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
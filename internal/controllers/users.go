@@ -22,12 +22,14 @@ type Updater interface {
 type Users struct {
 	repository UserRepository
 	updater    Updater
+	invites    ContactInviteActivator
 }
 
-func NewUsers(router Routerer, repository UserRepository, updater Updater) *Users {
+func NewUsers(router Routerer, repository UserRepository, updater Updater, invites ContactInviteActivator) *Users {
 	controller := &Users{
 		repository: repository,
 		updater:    updater,
+		invites:    invites,
 	}
 
 	router.RegisterRestAPIRoute("/v1/users/refreshAssets", web.AuthAccessUser, controller.RefreshAssets, "GET")
@@ -84,6 +86,9 @@ func (c *Users) AddUser(args *web.HandlerArgs) (any, *web.HttpError) {
 		}
 	}
 
+	// The user ID is the main character's ID
+	activateContactInvites(args.Request.Context(), c.invites, user.ID, user.ID)
+
 	return nil, nil
 }
 
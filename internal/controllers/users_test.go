@@ -47,7 +47,7 @@ func Test_UsersController_GetUser_Success(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	expectedUser := &repositories.User{
 		ID:   42,
@@ -79,7 +79,7 @@ func Test_UsersController_GetUser_MissingID(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	req := httptest.NewRequest("GET", "/v1/users/", nil)
 	args := &web.HandlerArgs{
@@ -100,7 +100,7 @@ func Test_UsersController_GetUser_InvalidID(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	req := httptest.NewRequest("GET", "/v1/users/invalid", nil)
 	args := &web.HandlerArgs{
@@ -121,7 +121,7 @@ func Test_UsersController_GetUser_RepositoryError(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	mockRepo.On("Get", mock.Anything, int64(42)).Return(nil, errors.New("database error"))
 
@@ -143,9 +143,10 @@ func Test_UsersController_GetUser_RepositoryError(t *testing.T) {
 func Test_UsersController_AddUser_Success(t *testing.T) {
 	mockRepo := new(MockUserRepository)
 	mockUpdater := new(MockUpdater)
+	mockInvites := new(MockContactInviteActivator)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, mockInvites)
 
 	user := repositories.User{
 		ID:   42,
@@ -155,6 +156,7 @@ func Test_UsersController_AddUser_Success(t *testing.T) {
 	mockRepo.On("Add", mock.Anything, mock.MatchedBy(func(u *repositories.User) bool {
 		return u.ID == 42 && u.Name == "New User"
 	})).Return(nil)
+	mockInvites.On("ActivateForCharacter", mock.Anything, int64(42), int64(42)).Return(int64(0), nil)
 
 	body, _ := json.Marshal(user)
 	req := httptest.NewRequest("POST", "/v1/users/", bytes.NewReader(body))
@@ -168,6 +170,7 @@ func Test_UsersController_AddUser_Success(t *testing.T) {
 	assert.Nil(t, result)
 
 	mockRepo.AssertExpectations(t)
+	mockInvites.AssertExpectations(t)
 }
 
 func Test_UsersController_AddUser_InvalidJSON(t *testing.T) {
@@ -175,7 +178,7 @@ func Test_UsersController_AddUser_InvalidJSON(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	req := httptest.NewRequest("POST", "/v1/users/", bytes.NewReader([]byte("invalid json")))
 	args := &web.HandlerArgs{
@@ -194,7 +197,7 @@ func Test_UsersController_AddUser_RepositoryError(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	user := repositories.User{
 		ID:   42,
@@ -223,7 +226,7 @@ func Test_UsersController_RefreshAssets_Success(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	userID := int64(42)
 
@@ -248,7 +251,7 @@ func Test_UsersController_RefreshAssets_UpdaterError(t *testing.T) {
 	mockUpdater := new(MockUpdater)
 	mockRouter := &MockRouter{}
 
-	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater)
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, &MockContactInviteActivator{})
 
 	userID := int64(42)
 
@@ -268,3 +271,25 @@ func Test_UsersController_RefreshAssets_UpdaterError(t *testing.T) {
 
 	mockUpdater.AssertExpectations(t)
 }
+
+func Test_UsersController_AddUser_InviteActivationFailureDoesNotFail(t *testing.T) {
+	mockRepo := new(MockUserRepository)
+	mockUpdater := new(MockUpdater)
+	mockInvites := new(MockContactInviteActivator)
+	mockRouter := &MockRouter{}
+
+	controller := controllers.NewUsers(mockRouter, mockRepo, mockUpdater, mockInvites)
+
+	mockRepo.On("Add", mock.Anything, mock.Anything).Return(nil)
+	mockInvites.On("ActivateForCharacter", mock.Anything, int64(42), int64(42)).Return(int64(0), errors.New("database error"))
+
+	body, _ := json.Marshal(repositories.User{ID: 42, Name: "New User"})
+	args := &web.HandlerArgs{
+		Request: httptest.NewRequest("POST", "/v1/users/", bytes.NewReader(body)),
+	}
+
+	_, httpErr := controller.AddUser(args)
+
+	assert.Nil(t, httpErr)
+	mockInvites.AssertExpectations(t)
+}
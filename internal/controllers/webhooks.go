@@ -0,0 +1,266 @@
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/notifications"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultWebhookDeliveriesLimit = 50
+	maxWebhookDeliveriesLimit     = 200
+)
+
+type WebhooksRepository interface {
+	GetByUser(ctx context.Context, userID int64) ([]*models.Webhook, error)
+	Create(ctx context.Context, webhook *models.Webhook) error
+	Update(ctx context.Context, webhook *models.Webhook) (bool, error)
+	RotateSecret(ctx context.Context, webhook *models.Webhook) (bool, error)
+	Delete(ctx context.Context, id int64, userID int64) (bool, error)
+	EnqueueForWebhook(ctx context.Context, webhookID int64, userID int64, eventID string, eventType string, payload []byte) (bool, error)
+	GetDeliveries(ctx context.Context, webhookID int64, userID int64, limit int) ([]*models.WebhookDelivery, error)
+}
+
+type Webhooks struct {
+	repository WebhooksRepository
+}
+
+type webhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+	IsActive   *bool    `json:"isActive"`
+}
+
+func NewWebhooks(router Routerer, repository WebhooksRepository) *Webhooks {
+	controller := &Webhooks{
+		repository: repository,
+	}
+
+	router.RegisterRestAPIRoute("/v1/webhooks", web.AuthAccessUser, controller.GetWebhooks, "GET")
+	router.RegisterRestAPIRoute("/v1/webhooks", web.AuthAccessUser, controller.CreateWebhook, "POST")
+	router.RegisterRestAPIRoute("/v1/webhooks/event-types", web.AuthAccessUser, controller.GetEventTypes, "GET")
+	router.RegisterRestAPIRoute("/v1/webhooks/{id}", web.AuthAccessUser, controller.UpdateWebhook, "PUT")
+	router.RegisterRestAPIRoute("/v1/webhooks/{id}", web.AuthAccessUser, controller.DeleteWebhook, "DELETE")
+	router.RegisterRestAPIRoute("/v1/webhooks/{id}/deliveries", web.AuthAccessUser, controller.GetDeliveries, "GET")
+	router.RegisterRestAPIRoute("/v1/webhooks/{id}/ping", web.AuthAccessUser, controller.PingWebhook, "POST")
+	router.RegisterRestAPIRoute("/v1/webhooks/{id}/rotate-secret", web.AuthAccessUser, controller.RotateSecret, "POST")
+
+	return controller
+}
+
+// GetWebhooks returns the user's webhooks with their signing secrets masked
+func (c *Webhooks) GetWebhooks(args *web.HandlerArgs) (any, *web.HttpError) {
+	webhooks, err := c.repository.GetByUser(args.Request.Context(), *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get webhooks")}
+	}
+
+	return webhooks, nil
+}
+
+// GetEventTypes returns the event types a webhook can subscribe to
+func (c *Webhooks) GetEventTypes(args *web.HandlerArgs) (any, *web.HttpError) {
+	return events.Types, nil
+}
+
+// CreateWebhook adds a webhook with a newly generated signing secret. An empty eventTypes list
+// subscribes to every event.
+func (c *Webhooks) CreateWebhook(args *web.HandlerArgs) (any, *web.HttpError) {
+	req, httpErr := decodeWebhookRequest(args.Request)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to generate webhook secret")}
+	}
+
+	webhook := &models.Webhook{
+		UserID:     *args.User,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		IsActive:   req.IsActive == nil || *req.IsActive,
+	}
+
+	err = c.repository.Create(args.Request.Context(), webhook)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to create webhook")}
+	}
+
+	return webhook, nil
+}
+
+// UpdateWebhook changes a webhook's URL, event types and whether it is active. The secret is kept
+// and returned masked.
+func (c *Webhooks) UpdateWebhook(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid webhook ID")}
+	}
+
+	req, httpErr := decodeWebhookRequest(args.Request)
+	if httpErr != nil {
+		return nil, httpErr
+	}
+
+	webhook := &models.Webhook{
+		ID:         id,
+		UserID:     *args.User,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		IsActive:   req.IsActive == nil || *req.IsActive,
+	}
+
+	found, err := c.repository.Update(args.Request.Context(), webhook)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to update webhook")}
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("webhook not found")}
+	}
+
+	return webhook, nil
+}
+
+// RotateSecret replaces a webhook's signing secret with a newly generated one and returns the
+// webhook with it. This and creation are the only times the full secret is returned.
+func (c *Webhooks) RotateSecret(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid webhook ID")}
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to generate webhook secret")}
+	}
+
+	webhook := &models.Webhook{
+		ID:     id,
+		UserID: *args.User,
+		Secret: secret,
+	}
+
+	found, err := c.repository.RotateSecret(args.Request.Context(), webhook)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to rotate webhook secret")}
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("webhook not found")}
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log
+func (c *Webhooks) DeleteWebhook(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid webhook ID")}
+	}
+
+	found, err := c.repository.Delete(args.Request.Context(), id, *args.User)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to delete webhook")}
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("webhook not found")}
+	}
+
+	return nil, nil
+}
+
+// GetDeliveries returns a webhook's delivery log, newest first. ?limit= caps the count
+// (default 50, max 200).
+func (c *Webhooks) GetDeliveries(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid webhook ID")}
+	}
+
+	limit := defaultWebhookDeliveriesLimit
+	if limitStr := args.Request.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("limit must be a positive number")}
+		}
+		limit = min(l, maxWebhookDeliveriesLimit)
+	}
+
+	deliveries, err := c.repository.GetDeliveries(args.Request.Context(), id, *args.User, limit)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to get webhook deliveries")}
+	}
+
+	return deliveries, nil
+}
+
+// PingWebhook queues a ping event for the webhook, whatever it subscribes to, so the user can
+// check their endpoint and signature verification
+func (c *Webhooks) PingWebhook(args *web.HandlerArgs) (any, *web.HttpError) {
+	id, err := strconv.ParseInt(args.Params["id"], 10, 64)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.New("invalid webhook ID")}
+	}
+
+	event := events.New(events.Ping, *args.User, map[string]int64{"webhookId": id})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to marshal ping")}
+	}
+
+	found, err := c.repository.EnqueueForWebhook(args.Request.Context(), id, *args.User, event.ID, event.Type, payload)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusInternalServerError, Error: errors.Wrap(err, "failed to queue ping")}
+	}
+	if !found {
+		return nil, &web.HttpError{StatusCode: http.StatusNotFound, Error: errors.New("webhook not found")}
+	}
+
+	return event, nil
+}
+
+func decodeWebhookRequest(r *http.Request) (*webhookRequest, *web.HttpError) {
+	var req webhookRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Wrap(err, "invalid request body")}
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	err = notifications.ValidateWebhookURL(req.URL)
+	if err != nil {
+		return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: err}
+	}
+
+	if req.EventTypes == nil {
+		req.EventTypes = []string{}
+	}
+	for _, eventType := range req.EventTypes {
+		if !events.IsValidType(eventType) {
+			return nil, &web.HttpError{StatusCode: http.StatusBadRequest, Error: errors.Errorf("unknown event type %q", eventType)}
+		}
+	}
+
+	return &req, nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
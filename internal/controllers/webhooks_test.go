@@ -0,0 +1,256 @@
+package controllers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/controllers"
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/web"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockWebhooksRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhooksRepository) GetByUser(ctx context.Context, userID int64) ([]*models.Webhook, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Webhook), args.Error(1)
+}
+
+func (m *MockWebhooksRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhooksRepository) Update(ctx context.Context, webhook *models.Webhook) (bool, error) {
+	args := m.Called(ctx, webhook)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhooksRepository) RotateSecret(ctx context.Context, webhook *models.Webhook) (bool, error) {
+	args := m.Called(ctx, webhook)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhooksRepository) Delete(ctx context.Context, id int64, userID int64) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhooksRepository) EnqueueForWebhook(ctx context.Context, webhookID int64, userID int64, eventID string, eventType string, payload []byte) (bool, error) {
+	args := m.Called(ctx, webhookID, userID, eventID, eventType, payload)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockWebhooksRepository) GetDeliveries(ctx context.Context, webhookID int64, userID int64, limit int) ([]*models.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.WebhookDelivery), args.Error(1)
+}
+
+func Test_WebhooksController_GetWebhooks(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	webhooks := []*models.Webhook{{ID: 1, UserID: userID, URL: "https://example.com/hook", EventTypes: []string{}}}
+	mockRepo.On("GetByUser", mock.Anything, userID).Return(webhooks, nil)
+
+	req := httptest.NewRequest("GET", "/v1/webhooks", nil)
+	result, httpErr := controller.GetWebhooks(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, webhooks, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_WebhooksController_CreateWebhook(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+		return w.UserID == userID &&
+			w.URL == "https://discord.example.com/bot" &&
+			len(w.Secret) == 64 &&
+			w.IsActive &&
+			assert.ObjectsAreEqual([]string{events.PurchaseCreated}, w.EventTypes)
+	})).Return(nil)
+
+	body := []byte(`{"url": " https://discord.example.com/bot ", "eventTypes": ["purchase.created"]}`)
+	req := httptest.NewRequest("POST", "/v1/webhooks", bytes.NewReader(body))
+	result, httpErr := controller.CreateWebhook(&web.HandlerArgs{Request: req, User: &userID})
+
+	assert.Nil(t, httpErr)
+	webhook := result.(*models.Webhook)
+	assert.NotEmpty(t, webhook.Secret)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_WebhooksController_CreateWebhook_Invalid(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	for _, body := range []string{
+		`{"url": "http://example.com/hook"}`,
+		`{"url": "https://10.1.2.3/hook"}`,
+		`{"url": "https://localhost/hook"}`,
+		`{"url": ""}`,
+		`{"url": "https://example.com/hook", "eventTypes": ["purchase.exploded"]}`,
+		`not json`,
+	} {
+		req := httptest.NewRequest("POST", "/v1/webhooks", bytes.NewReader([]byte(body)))
+		_, httpErr := controller.CreateWebhook(&web.HandlerArgs{Request: req, User: &userID})
+
+		require.NotNil(t, httpErr, body)
+		assert.Equal(t, 400, httpErr.StatusCode, body)
+	}
+
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func Test_WebhooksController_UpdateWebhook(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+		return w.ID == 5 && w.UserID == userID && !w.IsActive && len(w.EventTypes) == 0
+	})).Return(true, nil)
+
+	body := []byte(`{"url": "https://example.com/hook", "isActive": false}`)
+	req := httptest.NewRequest("PUT", "/v1/webhooks/5", bytes.NewReader(body))
+	_, httpErr := controller.UpdateWebhook(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	assert.Nil(t, httpErr)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_WebhooksController_UpdateWebhook_NotFound(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(false, nil)
+
+	body := []byte(`{"url": "https://example.com/hook"}`)
+	req := httptest.NewRequest("PUT", "/v1/webhooks/5", bytes.NewReader(body))
+	_, httpErr := controller.UpdateWebhook(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_WebhooksController_RotateSecret(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("RotateSecret", mock.Anything, mock.MatchedBy(func(w *models.Webhook) bool {
+		return w.ID == 5 && w.UserID == userID && len(w.Secret) == 64
+	})).Return(true, nil)
+
+	req := httptest.NewRequest("POST", "/v1/webhooks/5/rotate-secret", nil)
+	result, httpErr := controller.RotateSecret(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	assert.Nil(t, httpErr)
+	assert.Len(t, result.(*models.Webhook).Secret, 64)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_WebhooksController_RotateSecret_NotFound(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("RotateSecret", mock.Anything, mock.Anything).Return(false, nil)
+
+	req := httptest.NewRequest("POST", "/v1/webhooks/5/rotate-secret", nil)
+	_, httpErr := controller.RotateSecret(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+}
+
+func Test_WebhooksController_DeleteWebhook(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("Delete", mock.Anything, int64(5), userID).Return(true, nil)
+	mockRepo.On("Delete", mock.Anything, int64(6), userID).Return(false, nil)
+
+	req := httptest.NewRequest("DELETE", "/v1/webhooks/5", nil)
+	_, httpErr := controller.DeleteWebhook(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+	assert.Nil(t, httpErr)
+
+	req = httptest.NewRequest("DELETE", "/v1/webhooks/6", nil)
+	_, httpErr = controller.DeleteWebhook(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "6"}})
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 404, httpErr.StatusCode)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_WebhooksController_GetDeliveries(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	deliveries := []*models.WebhookDelivery{{ID: 1, WebhookID: 5, Status: models.WebhookDeliveryDelivered}}
+	mockRepo.On("GetDeliveries", mock.Anything, int64(5), userID, 200).Return(deliveries, nil)
+
+	req := httptest.NewRequest("GET", "/v1/webhooks/5/deliveries?limit=1000", nil)
+	result, httpErr := controller.GetDeliveries(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, deliveries, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func Test_WebhooksController_GetDeliveries_Error(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("GetDeliveries", mock.Anything, int64(5), userID, 50).Return(nil, errors.New("db down"))
+
+	req := httptest.NewRequest("GET", "/v1/webhooks/5/deliveries", nil)
+	_, httpErr := controller.GetDeliveries(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	require.NotNil(t, httpErr)
+	assert.Equal(t, 500, httpErr.StatusCode)
+}
+
+func Test_WebhooksController_PingWebhook(t *testing.T) {
+	mockRepo := new(MockWebhooksRepository)
+	controller := controllers.NewWebhooks(&MockRouter{}, mockRepo)
+
+	userID := int64(42)
+	mockRepo.On("EnqueueForWebhook", mock.Anything, int64(5), userID, mock.Anything, events.Ping, mock.MatchedBy(func(payload []byte) bool {
+		var body map[string]any
+		return json.Unmarshal(payload, &body) == nil && body["type"] == events.Ping
+	})).Return(true, nil)
+
+	req := httptest.NewRequest("POST", "/v1/webhooks/5/ping", nil)
+	result, httpErr := controller.PingWebhook(&web.HandlerArgs{Request: req, User: &userID, Params: map[string]string{"id": "5"}})
+
+	assert.Nil(t, httpErr)
+	assert.Equal(t, events.Ping, result.(*events.Event).Type)
+	mockRepo.AssertExpectations(t)
+}
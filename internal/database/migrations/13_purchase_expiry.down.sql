@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_purchase_open;
+DROP TABLE IF EXISTS purchase_expiry_settings;
+
+ALTER TABLE purchase_transactions
+DROP COLUMN IF EXISTS cancelled_at,
+DROP COLUMN IF EXISTS cancellation_reason;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE purchase_transactions
+ADD COLUMN cancellation_reason TEXT,
+ADD COLUMN cancelled_at TIMESTAMP;
+
+-- Per-seller timeout for open purchases; sellers without a row use the server default
+CREATE TABLE purchase_expiry_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id),
+    timeout_hours INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT purchase_expiry_non_negative CHECK (timeout_hours >= 0)
+);
+
+CREATE INDEX idx_purchase_open ON purchase_transactions(status, purchased_at)
+WHERE status IN ('pending', 'contract_created');
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE for_sale_items
+DROP COLUMN IF EXISTS stock_checked_at,
+DROP COLUMN IF EXISTS held_quantity;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Stock actually held at the listing's location as of the last asset sync
+ALTER TABLE for_sale_items
+ADD COLUMN held_quantity BIGINT,
+ADD COLUMN stock_checked_at TIMESTAMP;
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+ALTER TABLE buy_orders
+DROP CONSTRAINT IF EXISTS buy_order_pricing_percent,
+DROP CONSTRAINT IF EXISTS buy_order_pricing_basis,
+DROP COLUMN IF EXISTS pricing_ceiling,
+DROP COLUMN IF EXISTS pricing_floor,
+DROP COLUMN IF EXISTS pricing_percent,
+DROP COLUMN IF EXISTS pricing_basis;
+
+ALTER TABLE for_sale_items
+DROP CONSTRAINT IF EXISTS for_sale_pricing_percent,
+DROP CONSTRAINT IF EXISTS for_sale_pricing_basis,
+DROP COLUMN IF EXISTS pricing_ceiling,
+DROP COLUMN IF EXISTS pricing_floor,
+DROP COLUMN IF EXISTS pricing_percent,
+DROP COLUMN IF EXISTS pricing_basis;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Optional market-relative pricing: percent of the Jita buy, sell or split price,
+-- clamped to floor/ceiling. The fixed price is used when no rule or no market data exists.
+ALTER TABLE for_sale_items
+ADD COLUMN pricing_basis VARCHAR(10),
+ADD COLUMN pricing_percent DOUBLE PRECISION,
+ADD COLUMN pricing_floor BIGINT,
+ADD COLUMN pricing_ceiling BIGINT,
+ADD CONSTRAINT for_sale_pricing_basis CHECK (pricing_basis IN ('buy', 'sell', 'split')),
+ADD CONSTRAINT for_sale_pricing_percent CHECK (pricing_basis IS NULL OR pricing_percent > 0);
+
+ALTER TABLE buy_orders
+ADD COLUMN pricing_basis VARCHAR(10),
+ADD COLUMN pricing_percent DOUBLE PRECISION,
+ADD COLUMN pricing_floor BIGINT,
+ADD COLUMN pricing_ceiling BIGINT,
+ADD CONSTRAINT buy_order_pricing_basis CHECK (pricing_basis IN ('buy', 'sell', 'split')),
+ADD CONSTRAINT buy_order_pricing_percent CHECK (pricing_basis IS NULL OR pricing_percent > 0);
+
+COMMIT;
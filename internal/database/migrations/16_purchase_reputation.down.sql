@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_purchase_closed_buyer;
+DROP INDEX IF EXISTS idx_purchase_closed_seller;
+
+ALTER TABLE purchase_transactions
+DROP CONSTRAINT IF EXISTS purchase_cancelled_by,
+DROP COLUMN IF EXISTS cancelled_by,
+DROP COLUMN IF EXISTS completed_at;
+
+COMMIT;
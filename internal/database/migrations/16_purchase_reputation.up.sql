@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Track who closed a purchase so reputation can be derived from purchase history
+ALTER TABLE purchase_transactions
+ADD COLUMN completed_at TIMESTAMP,
+ADD COLUMN cancelled_by VARCHAR(10),
+ADD CONSTRAINT purchase_cancelled_by CHECK (cancelled_by IN ('buyer', 'seller', 'system'));
+
+-- Purchases cancelled by the expiry job before this column existed
+UPDATE purchase_transactions
+SET cancelled_by = 'system'
+WHERE status = 'cancelled' AND cancellation_reason LIKE 'expired%';
+
+CREATE INDEX idx_purchase_closed_seller ON purchase_transactions(seller_user_id)
+WHERE status IN ('completed', 'cancelled');
+
+CREATE INDEX idx_purchase_closed_buyer ON purchase_transactions(buyer_user_id)
+WHERE status IN ('completed', 'cancelled');
+
+COMMIT;
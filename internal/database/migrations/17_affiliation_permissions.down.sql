@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS affiliation_permissions;
+DROP TABLE IF EXISTS character_affiliations;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+-- Current corporation/alliance of each registered character, refreshed from ESI
+CREATE TABLE character_affiliations (
+    character_id BIGINT PRIMARY KEY,
+    corporation_id BIGINT NOT NULL,
+    alliance_id BIGINT,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_character_affiliations_corporation ON character_affiliations(corporation_id);
+CREATE INDEX idx_character_affiliations_alliance ON character_affiliations(alliance_id);
+
+-- Grants to every member of a corporation or alliance, alongside per-contact contact_permissions
+CREATE TABLE affiliation_permissions (
+    id BIGSERIAL PRIMARY KEY,
+    granting_user_id BIGINT NOT NULL REFERENCES users(id),
+    receiver_type VARCHAR(20) NOT NULL,
+    receiver_id BIGINT NOT NULL,
+    service_type VARCHAR(50) NOT NULL,
+    can_access BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT affiliation_permission_receiver_type CHECK (receiver_type IN ('corporation', 'alliance')),
+    CONSTRAINT affiliation_permission_unique_grant UNIQUE (granting_user_id, receiver_type, receiver_id, service_type)
+);
+
+CREATE INDEX idx_affiliation_permission_receiver ON affiliation_permissions(receiver_type, receiver_id, service_type, can_access);
+
+COMMIT;
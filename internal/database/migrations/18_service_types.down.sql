@@ -0,0 +1,9 @@
+BEGIN;
+
+DELETE FROM contact_permissions
+WHERE service_type IN ('asset_view', 'stockpile_view', 'buy_order_browse', 'build_request', 'hauling_request');
+
+DELETE FROM affiliation_permissions
+WHERE service_type IN ('asset_view', 'stockpile_view', 'buy_order_browse', 'build_request', 'hauling_request');
+
+COMMIT;
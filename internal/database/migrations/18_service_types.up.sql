@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Buy orders used to be visible to anyone granted for_sale_browse; keep existing
+-- grants working now that buy order visibility is its own service
+INSERT INTO contact_permissions (contact_id, granting_user_id, receiving_user_id, service_type, can_access)
+SELECT contact_id, granting_user_id, receiving_user_id, 'buy_order_browse', can_access
+FROM contact_permissions
+WHERE service_type = 'for_sale_browse'
+ON CONFLICT (contact_id, granting_user_id, receiving_user_id, service_type) DO NOTHING;
+
+INSERT INTO affiliation_permissions (granting_user_id, receiver_type, receiver_id, service_type, can_access)
+SELECT granting_user_id, receiver_type, receiver_id, 'buy_order_browse', can_access
+FROM affiliation_permissions
+WHERE service_type = 'for_sale_browse'
+ON CONFLICT (granting_user_id, receiver_type, receiver_id, service_type) DO NOTHING;
+
+-- Seed the remaining services (denied) for contacts accepted before they existed
+INSERT INTO contact_permissions (contact_id, granting_user_id, receiving_user_id, service_type, can_access)
+SELECT p.contact_id, p.granting_user_id, p.receiving_user_id, s.service_type, false
+FROM contact_permissions p
+CROSS JOIN (VALUES ('asset_view'), ('stockpile_view'), ('build_request'), ('hauling_request')) AS s(service_type)
+WHERE p.service_type = 'for_sale_browse'
+ON CONFLICT (contact_id, granting_user_id, receiving_user_id, service_type) DO NOTHING;
+
+COMMIT;
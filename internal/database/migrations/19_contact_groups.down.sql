@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS contact_group_permissions;
+DROP TABLE IF EXISTS contact_group_members;
+DROP TABLE IF EXISTS contact_groups;
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+-- Named groups a user sorts their contacts into
+CREATE TABLE contact_groups (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT contact_group_unique_name UNIQUE (user_id, name)
+);
+
+CREATE TABLE contact_group_members (
+    group_id BIGINT NOT NULL REFERENCES contact_groups(id) ON DELETE CASCADE,
+    contact_id BIGINT NOT NULL REFERENCES contacts(id) ON DELETE CASCADE,
+    added_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, contact_id)
+);
+
+CREATE INDEX idx_contact_group_members_contact ON contact_group_members(contact_id);
+
+-- Services granted at group level; fanned out to contact_permissions for every member
+CREATE TABLE contact_group_permissions (
+    group_id BIGINT NOT NULL REFERENCES contact_groups(id) ON DELETE CASCADE,
+    service_type VARCHAR(50) NOT NULL,
+    can_access BOOLEAN NOT NULL DEFAULT false,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (group_id, service_type)
+);
+
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Contact requests to characters that have not registered yet
+CREATE TABLE contact_invites (
+    id BIGSERIAL PRIMARY KEY,
+    requester_user_id BIGINT NOT NULL REFERENCES users(id),
+    character_id BIGINT NOT NULL,
+    character_name VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT contact_invite_unique UNIQUE (requester_user_id, character_id)
+);
+
+CREATE INDEX idx_contact_invites_character ON contact_invites(character_id);
+
+COMMIT;
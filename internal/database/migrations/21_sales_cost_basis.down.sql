@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_purchase_buyer_type;
+
+ALTER TABLE for_sale_items
+DROP CONSTRAINT IF EXISTS for_sale_build_cost,
+DROP COLUMN IF EXISTS build_cost_per_unit;
+
+DROP TABLE IF EXISTS market_price_history;
+
+COMMIT;
@@ -0,0 +1,24 @@
+BEGIN;
+
+-- Daily Jita snapshots so a sale can be valued at the price when it was listed
+CREATE TABLE market_price_history (
+    type_id BIGINT NOT NULL,
+    region_id BIGINT NOT NULL,
+    recorded_on DATE NOT NULL,
+    buy_price DOUBLE PRECISION,
+    sell_price DOUBLE PRECISION,
+    PRIMARY KEY (type_id, region_id, recorded_on)
+);
+
+INSERT INTO market_price_history (type_id, region_id, recorded_on, buy_price, sell_price)
+SELECT type_id, region_id, updated_at::DATE, buy_price, sell_price
+FROM market_prices;
+
+-- Seller-supplied cost of building or acquiring the listed item, per unit
+ALTER TABLE for_sale_items
+ADD COLUMN build_cost_per_unit BIGINT,
+ADD CONSTRAINT for_sale_build_cost CHECK (build_cost_per_unit IS NULL OR build_cost_per_unit >= 0);
+
+CREATE INDEX idx_purchase_buyer_type ON purchase_transactions(buyer_user_id, type_id, purchased_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS appraisal_items;
+DROP TABLE IF EXISTS appraisals;
+DROP INDEX IF EXISTS idx_asset_item_types_lower_name;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+-- Appraisals freeze the prices of a pasted item list so they can be shared by code
+CREATE TABLE appraisals (
+    code VARCHAR(16) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    region_id BIGINT NOT NULL,
+    unresolved TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_appraisals_user ON appraisals(user_id, created_at DESC);
+
+CREATE TABLE appraisal_items (
+    appraisal_code VARCHAR(16) NOT NULL REFERENCES appraisals(code) ON DELETE CASCADE,
+    type_id BIGINT NOT NULL,
+    type_name VARCHAR(500) NOT NULL,
+    quantity BIGINT NOT NULL,
+    buy_price DOUBLE PRECISION,
+    sell_price DOUBLE PRECISION,
+    volume DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (appraisal_code, type_id),
+    CONSTRAINT appraisal_item_positive_quantity CHECK (quantity > 0)
+);
+
+-- Pasted names are matched case-insensitively
+CREATE INDEX idx_asset_item_types_lower_name ON asset_item_types(LOWER(type_name));
+
+COMMIT;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_stockpile_template_application;
+ALTER TABLE stockpile_markers DROP COLUMN IF EXISTS template_application_id;
+
+DROP TABLE IF EXISTS stockpile_template_applications;
+DROP TABLE IF EXISTS stockpile_template_items;
+DROP TABLE IF EXISTS stockpile_templates;
+
+COMMIT;
@@ -0,0 +1,48 @@
+BEGIN;
+
+-- Named lists of types and quantities, e.g. a doctrine fit, that can be stamped onto any location
+CREATE TABLE stockpile_templates (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT stockpile_template_unique_name UNIQUE (user_id, name)
+);
+
+CREATE TABLE stockpile_template_items (
+    template_id BIGINT NOT NULL REFERENCES stockpile_templates(id) ON DELETE CASCADE,
+    type_id BIGINT NOT NULL REFERENCES asset_item_types(type_id),
+    quantity BIGINT NOT NULL,
+    PRIMARY KEY (template_id, type_id),
+    CONSTRAINT stockpile_template_item_positive_quantity CHECK (quantity > 0)
+);
+
+-- Every place a template has been applied; markers are regenerated from these when the template changes
+CREATE TABLE stockpile_template_applications (
+    id BIGSERIAL PRIMARY KEY,
+    template_id BIGINT NOT NULL REFERENCES stockpile_templates(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    owner_type VARCHAR(20) NOT NULL,
+    owner_id BIGINT NOT NULL,
+    location_id BIGINT NOT NULL,
+    container_id BIGINT,
+    division_number INT,
+    multiplier BIGINT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT stockpile_template_application_positive_multiplier CHECK (multiplier > 0)
+);
+
+CREATE UNIQUE INDEX idx_stockpile_template_application_unique ON stockpile_template_applications(
+    template_id, owner_type, owner_id, location_id,
+    COALESCE(container_id, 0), COALESCE(division_number, 0)
+);
+
+ALTER TABLE stockpile_markers
+    ADD COLUMN template_application_id BIGINT REFERENCES stockpile_template_applications(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_stockpile_template_application ON stockpile_markers(template_application_id);
+
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+
+DELETE FROM stockpile_markers WHERE scope <> 'location';
+
+DROP INDEX IF EXISTS idx_stockpile_unique;
+
+CREATE UNIQUE INDEX idx_stockpile_unique ON stockpile_markers(
+    user_id, type_id, owner_type, owner_id, location_id,
+    COALESCE(container_id, 0), COALESCE(division_number, 0)
+);
+
+ALTER TABLE stockpile_markers
+    DROP CONSTRAINT IF EXISTS stockpile_marker_valid_scope,
+    DROP COLUMN IF EXISTS scope;
+
+COMMIT;
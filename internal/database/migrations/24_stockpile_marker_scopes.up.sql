@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Markers can cover a whole solar system, region or everything the user owns instead of one hangar.
+-- For those scopes location_id holds the solar system or region ID (0 for anywhere), and
+-- owner_type may be 'all' (owner_id 0) to count every character and corporation.
+ALTER TABLE stockpile_markers
+    ADD COLUMN scope VARCHAR(20) NOT NULL DEFAULT 'location',
+    ADD CONSTRAINT stockpile_marker_valid_scope CHECK (scope IN ('location', 'solar_system', 'region', 'anywhere'));
+
+DROP INDEX IF EXISTS idx_stockpile_unique;
+
+CREATE UNIQUE INDEX idx_stockpile_unique ON stockpile_markers(
+    user_id, type_id, scope, owner_type, owner_id, location_id,
+    COALESCE(container_id, 0), COALESCE(division_number, 0)
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_buy_orders_stockpile_marker;
+
+ALTER TABLE buy_orders
+    DROP COLUMN IF EXISTS auto_managed,
+    DROP COLUMN IF EXISTS stockpile_marker_id;
+
+ALTER TABLE stockpile_markers
+    DROP CONSTRAINT IF EXISTS stockpile_marker_buy_order_markup,
+    DROP COLUMN IF EXISTS buy_order_markup_percent,
+    DROP COLUMN IF EXISTS auto_buy_order;
+
+COMMIT;
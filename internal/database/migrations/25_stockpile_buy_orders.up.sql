@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Markers can opt in to a buy order that tracks their deficit after every asset sync,
+-- priced at the Jita buy price plus buy_order_markup_percent.
+ALTER TABLE stockpile_markers
+    ADD COLUMN auto_buy_order BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN buy_order_markup_percent DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD CONSTRAINT stockpile_marker_buy_order_markup CHECK (buy_order_markup_percent > -100);
+
+-- auto_managed stays set after the marker is deleted so the orphaned order can be closed
+ALTER TABLE buy_orders
+    ADD COLUMN stockpile_marker_id INT REFERENCES stockpile_markers(id) ON DELETE SET NULL,
+    ADD COLUMN auto_managed BOOLEAN NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX idx_buy_orders_stockpile_marker ON buy_orders(stockpile_marker_id)
+    WHERE stockpile_marker_id IS NOT NULL;
+
+COMMIT;
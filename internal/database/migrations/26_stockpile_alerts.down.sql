@@ -0,0 +1,13 @@
+BEGIN;
+
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_settings;
+
+ALTER TABLE stockpile_markers
+    DROP CONSTRAINT IF EXISTS stockpile_marker_alert_deficit_value,
+    DROP CONSTRAINT IF EXISTS stockpile_marker_alert_below_quantity,
+    DROP COLUMN IF EXISTS alert_triggered,
+    DROP COLUMN IF EXISTS alert_deficit_value,
+    DROP COLUMN IF EXISTS alert_below_quantity;
+
+COMMIT;
@@ -0,0 +1,35 @@
+BEGIN;
+
+-- A marker alerts when its held quantity drops below alert_below_quantity or its deficit is worth
+-- more than alert_deficit_value ISK. alert_triggered records that the alert was sent so it only
+-- fires again after the stockpile recovers.
+ALTER TABLE stockpile_markers
+    ADD COLUMN alert_below_quantity BIGINT,
+    ADD COLUMN alert_deficit_value DOUBLE PRECISION,
+    ADD COLUMN alert_triggered BOOLEAN NOT NULL DEFAULT false,
+    ADD CONSTRAINT stockpile_marker_alert_below_quantity CHECK (alert_below_quantity > 0),
+    ADD CONSTRAINT stockpile_marker_alert_deficit_value CHECK (alert_deficit_value > 0);
+
+-- Where notifications are delivered besides the in-app list
+CREATE TABLE notification_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id),
+    webhook_url TEXT,
+    mail_character_id BIGINT REFERENCES characters(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    message TEXT NOT NULL,
+    stockpile_marker_id INT REFERENCES stockpile_markers(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+
+COMMIT;
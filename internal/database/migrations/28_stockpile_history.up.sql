@@ -0,0 +1,15 @@
+BEGIN;
+
+-- Quantity held against each stockpile marker, recorded after every asset sync. Consumption and
+-- burn rate are derived from the drops between samples.
+CREATE TABLE stockpile_history (
+    stockpile_marker_id INT NOT NULL REFERENCES stockpile_markers(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    quantity BIGINT NOT NULL,
+    PRIMARY KEY (stockpile_marker_id, recorded_at)
+);
+
+CREATE INDEX idx_stockpile_history_user ON stockpile_history(user_id, recorded_at);
+
+COMMIT;
@@ -0,0 +1,19 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_asset_item_types_market_group;
+DROP INDEX IF EXISTS idx_asset_item_types_group;
+
+ALTER TABLE asset_item_types
+    DROP COLUMN IF EXISTS packaged_volume,
+    DROP COLUMN IF EXISTS published,
+    DROP COLUMN IF EXISTS parent_type_id,
+    DROP COLUMN IF EXISTS meta_group_id,
+    DROP COLUMN IF EXISTS market_group_id,
+    DROP COLUMN IF EXISTS group_id;
+
+DROP TABLE IF EXISTS meta_groups;
+DROP TABLE IF EXISTS market_groups;
+DROP TABLE IF EXISTS item_groups;
+DROP TABLE IF EXISTS item_categories;
+
+COMMIT;
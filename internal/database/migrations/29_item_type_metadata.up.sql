@@ -0,0 +1,46 @@
+BEGIN;
+
+-- Static data from the SDE used to group and filter item types
+CREATE TABLE item_categories (
+    category_id BIGINT PRIMARY KEY NOT NULL,
+    name VARCHAR(500) NOT NULL,
+    published BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE TABLE item_groups (
+    group_id BIGINT PRIMARY KEY NOT NULL,
+    category_id BIGINT NOT NULL,
+    name VARCHAR(500) NOT NULL,
+    published BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_item_groups_category ON item_groups(category_id);
+
+-- Market groups form a tree through parent_group_id; only leaves hold types
+CREATE TABLE market_groups (
+    market_group_id BIGINT PRIMARY KEY NOT NULL,
+    parent_group_id BIGINT,
+    name VARCHAR(500) NOT NULL,
+    has_types BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX idx_market_groups_parent ON market_groups(parent_group_id);
+
+-- Tech I, Tech II, Faction, Officer and so on
+CREATE TABLE meta_groups (
+    meta_group_id BIGINT PRIMARY KEY NOT NULL,
+    name VARCHAR(500) NOT NULL
+);
+
+ALTER TABLE asset_item_types
+    ADD COLUMN group_id BIGINT,
+    ADD COLUMN market_group_id BIGINT,
+    ADD COLUMN meta_group_id BIGINT,
+    ADD COLUMN parent_type_id BIGINT,
+    ADD COLUMN published BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN packaged_volume DOUBLE PRECISION;
+
+CREATE INDEX idx_asset_item_types_group ON asset_item_types(group_id);
+CREATE INDEX idx_asset_item_types_market_group ON asset_item_types(market_group_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE for_sale_items
+DROP COLUMN IF EXISTS deleted_at;
+
+COMMIT;
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- Set when the seller removes a listing, so listings deactivated by selling out can be told apart
+-- and brought back when a reservation expires
+ALTER TABLE for_sale_items
+ADD COLUMN deleted_at TIMESTAMP;
+
+COMMIT;
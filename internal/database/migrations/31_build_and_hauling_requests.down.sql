@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS hauling_requests;
+DROP TABLE IF EXISTS build_requests;
+
+COMMIT;
@@ -0,0 +1,39 @@
+BEGIN;
+
+-- Requests a contact holding the build_request service sends to a builder
+CREATE TABLE build_requests (
+    id BIGSERIAL PRIMARY KEY,
+    requester_user_id BIGINT NOT NULL REFERENCES users(id),
+    builder_user_id BIGINT NOT NULL REFERENCES users(id),
+    type_id BIGINT NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (requester_user_id != builder_user_id)
+);
+
+CREATE INDEX idx_build_requests_requester ON build_requests(requester_user_id);
+CREATE INDEX idx_build_requests_builder ON build_requests(builder_user_id);
+
+-- Requests a contact holding the hauling_request service sends to a hauler
+CREATE TABLE hauling_requests (
+    id BIGSERIAL PRIMARY KEY,
+    requester_user_id BIGINT NOT NULL REFERENCES users(id),
+    hauler_user_id BIGINT NOT NULL REFERENCES users(id),
+    origin_location_id BIGINT NOT NULL,
+    destination_location_id BIGINT NOT NULL,
+    volume_m3 DOUBLE PRECISION NOT NULL CHECK (volume_m3 > 0),
+    collateral BIGINT NOT NULL DEFAULT 0 CHECK (collateral >= 0),
+    notes TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (requester_user_id != hauler_user_id)
+);
+
+CREATE INDEX idx_hauling_requests_requester ON hauling_requests(requester_user_id);
+CREATE INDEX idx_hauling_requests_hauler ON hauling_requests(hauler_user_id);
+
+COMMIT;
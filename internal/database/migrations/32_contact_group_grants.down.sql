@@ -0,0 +1,17 @@
+BEGIN;
+
+UPDATE contact_permissions p
+SET can_access = true, updated_at = NOW()
+WHERE p.can_access = false
+  AND EXISTS (
+    SELECT 1
+    FROM contact_group_permissions gp
+    INNER JOIN contact_groups g ON g.id = gp.group_id
+    INNER JOIN contact_group_members m ON m.group_id = g.id
+    WHERE m.contact_id = p.contact_id
+      AND g.user_id = p.granting_user_id
+      AND gp.service_type = p.service_type
+      AND gp.can_access = true
+  );
+
+COMMIT;
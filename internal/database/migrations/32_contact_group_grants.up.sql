@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Group grants used to be written through to contact_permissions. They are now read
+-- from contact_group_permissions directly, so clear the copies; otherwise leaving a
+-- group would no longer revoke what it granted
+UPDATE contact_permissions p
+SET can_access = false, updated_at = NOW()
+WHERE p.can_access = true
+  AND EXISTS (
+    SELECT 1
+    FROM contact_group_permissions gp
+    INNER JOIN contact_groups g ON g.id = gp.group_id
+    INNER JOIN contact_group_members m ON m.group_id = g.id
+    WHERE m.contact_id = p.contact_id
+      AND g.user_id = p.granting_user_id
+      AND gp.service_type = p.service_type
+      AND gp.can_access = true
+  );
+
+COMMIT;
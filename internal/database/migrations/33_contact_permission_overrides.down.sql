@@ -0,0 +1,8 @@
+BEGIN;
+
+UPDATE contact_permissions SET can_access = false WHERE can_access IS NULL;
+
+ALTER TABLE contact_permissions ALTER COLUMN can_access SET DEFAULT false;
+ALTER TABLE contact_permissions ALTER COLUMN can_access SET NOT NULL;
+
+COMMIT;
@@ -0,0 +1,29 @@
+BEGIN;
+
+-- A NULL can_access means the granter never decided for this contact, so group and
+-- affiliation grants apply; only an explicit false overrides them
+ALTER TABLE contact_permissions ALTER COLUMN can_access DROP NOT NULL;
+ALTER TABLE contact_permissions ALTER COLUMN can_access DROP DEFAULT;
+
+-- Rows seeded as denied on accept and never edited were defaults, not decisions
+UPDATE contact_permissions
+SET can_access = NULL
+WHERE can_access = false
+  AND updated_at = created_at;
+
+-- Group copies cleared by 32_contact_group_grants were not decisions either
+UPDATE contact_permissions p
+SET can_access = NULL
+WHERE p.can_access = false
+  AND EXISTS (
+    SELECT 1
+    FROM contact_group_permissions gp
+    INNER JOIN contact_groups g ON g.id = gp.group_id
+    INNER JOIN contact_group_members m ON m.group_id = g.id
+    WHERE m.contact_id = p.contact_id
+      AND g.user_id = p.granting_user_id
+      AND gp.service_type = p.service_type
+      AND gp.can_access = true
+  );
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE contact_permissions DROP COLUMN owner_ids;
+ALTER TABLE contact_permissions DROP COLUMN station_ids;
+
+COMMIT;
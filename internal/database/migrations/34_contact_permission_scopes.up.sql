@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Limits a view grant to chosen stations and owners; NULL leaves it unrestricted
+ALTER TABLE contact_permissions ADD COLUMN station_ids BIGINT[];
+ALTER TABLE contact_permissions ADD COLUMN owner_ids BIGINT[];
+
+COMMIT;
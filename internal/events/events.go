@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+const (
+	PurchaseCreated         = "purchase.created"
+	PurchaseContractCreated = "purchase.contract_created"
+	PurchaseCompleted       = "purchase.completed"
+	PurchaseCancelled       = "purchase.cancelled"
+
+	ListingCreated = "listing.created"
+	ListingUpdated = "listing.updated"
+	ListingDeleted = "listing.deleted"
+
+	BuyOrderCreated = "buy_order.created"
+	BuyOrderUpdated = "buy_order.updated"
+	BuyOrderDeleted = "buy_order.deleted"
+	BuyOrderMatched = "buy_order.matched"
+
+	ContactRequested = "contact.requested"
+	ContactAccepted  = "contact.accepted"
+	ContactRejected  = "contact.rejected"
+
+	// Ping is only sent on request, to test a webhook
+	Ping = "ping"
+)
+
+// Types lists every event type a webhook can subscribe to
+var Types = []string{
+	PurchaseCreated, PurchaseContractCreated, PurchaseCompleted, PurchaseCancelled,
+	ListingCreated, ListingUpdated, ListingDeleted,
+	BuyOrderCreated, BuyOrderUpdated, BuyOrderDeleted, BuyOrderMatched,
+	ContactRequested, ContactAccepted, ContactRejected,
+}
+
+// IsValidType reports whether eventType is one of Types
+func IsValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is something that happened for UserID, who receives it through their webhooks
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	UserID    int64     `json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// New creates an event with a fresh ID
+func New(eventType string, userID int64, data any) *Event {
+	return &Event{
+		ID:        newID(),
+		Type:      eventType,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, event *Event) error
+
+// Bus passes events from where they happen to every subscribed handler
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a handler for every event published after it
+func (b *Bus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish runs every handler for the event. Handlers run with a context that outlives the
+// request the event came from, and their failures are logged rather than returned so that
+// publishing never fails the action that caused it.
+func (b *Bus) Publish(ctx context.Context, event *Event) {
+	if event.ID == "" {
+		event.ID = newID()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	ctx = context.WithoutCancel(ctx)
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			log.Error("event handler failed", "event_type", event.Type, "event_id", event.ID, "user_id", event.UserID, "error", err)
+		}
+	}
+}
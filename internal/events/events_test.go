@@ -0,0 +1,103 @@
+package events_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BusShouldDeliverToEveryHandler(t *testing.T) {
+	bus := events.NewBus()
+
+	received := []string{}
+	bus.Subscribe(func(ctx context.Context, event *events.Event) error {
+		received = append(received, "first:"+event.Type)
+		return errors.New("handler failed")
+	})
+	bus.Subscribe(func(ctx context.Context, event *events.Event) error {
+		received = append(received, "second:"+event.Type)
+		return nil
+	})
+
+	bus.Publish(context.Background(), &events.Event{Type: events.PurchaseCreated, UserID: 42})
+
+	assert.Equal(t, []string{"first:purchase.created", "second:purchase.created"}, received)
+}
+
+func Test_BusShouldFillIDAndTime(t *testing.T) {
+	bus := events.NewBus()
+
+	var got *events.Event
+	bus.Subscribe(func(ctx context.Context, event *events.Event) error {
+		got = event
+		return nil
+	})
+
+	bus.Publish(context.Background(), &events.Event{Type: events.ListingCreated})
+
+	require.NotNil(t, got)
+	assert.Len(t, got.ID, 32)
+	assert.False(t, got.CreatedAt.IsZero())
+}
+
+func Test_BusShouldOutliveRequestContext(t *testing.T) {
+	bus := events.NewBus()
+
+	var handlerErr error
+	bus.Subscribe(func(ctx context.Context, event *events.Event) error {
+		handlerErr = ctx.Err()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	bus.Publish(ctx, events.New(events.ContactRequested, 42, nil))
+
+	assert.NoError(t, handlerErr)
+}
+
+func Test_IsValidType(t *testing.T) {
+	assert.True(t, events.IsValidType(events.BuyOrderMatched))
+	assert.False(t, events.IsValidType(events.Ping))
+	assert.False(t, events.IsValidType("purchase.exploded"))
+}
+
+func Test_QueueShouldRunHandlerInBackground(t *testing.T) {
+	handled := make(chan string, 1)
+	queue := events.NewQueue("test", func(ctx context.Context, event *events.Event) error {
+		handled <- event.Type
+		return nil
+	}, 1)
+
+	bus := events.NewBus()
+	bus.Subscribe(queue.Handle)
+
+	// Publishing returns before anything is handled
+	bus.Publish(context.Background(), events.New(events.BuyOrderCreated, 42, nil))
+	assert.Len(t, handled, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- queue.Run(ctx) }()
+
+	assert.Equal(t, events.BuyOrderCreated, <-handled)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+func Test_QueueShouldDropEventsWhenFull(t *testing.T) {
+	queue := events.NewQueue("test", func(ctx context.Context, event *events.Event) error {
+		return nil
+	}, 1)
+
+	assert.NoError(t, queue.Handle(context.Background(), events.New(events.ListingCreated, 42, nil)))
+
+	err := queue.Handle(context.Background(), events.New(events.ListingUpdated, 42, nil))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "test queue is full")
+}
@@ -0,0 +1,50 @@
+package events
+
+import (
+	"context"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/pkg/errors"
+)
+
+// Queue runs a handler in the background so that slow handlers, such as ones that query the
+// database, don't hold up the request that published the event. Subscribe Queue.Handle to the
+// bus and start Run.
+type Queue struct {
+	name    string
+	handler Handler
+	events  chan *Event
+}
+
+func NewQueue(name string, handler Handler, size int) *Queue {
+	return &Queue{
+		name:    name,
+		handler: handler,
+		events:  make(chan *Event, size),
+	}
+}
+
+// Handle queues the event without waiting for the handler. A full queue drops the event and
+// reports it to the bus.
+func (q *Queue) Handle(ctx context.Context, event *Event) error {
+	select {
+	case q.events <- event:
+		return nil
+	default:
+		return errors.Errorf("%s queue is full, dropping event", q.name)
+	}
+}
+
+// Run passes queued events to the handler one at a time until ctx is done
+func (q *Queue) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-q.events:
+			if err := q.handler(ctx, event); err != nil {
+				log.Error("queued event handler failed", "queue", q.name, "event_type", event.Type, "event_id", event.ID, "user_id", event.UserID, "error", err)
+			}
+		}
+	}
+}
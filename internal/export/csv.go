@@ -0,0 +1,64 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type csvWriter struct {
+	writer *csv.Writer
+	rows   int
+}
+
+// NewCSVWriter returns a Writer producing comma separated UTF-8 with a header row
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteHeader(columns ...string) error {
+	return c.write(columns)
+}
+
+func (c *csvWriter) WriteRow(values ...any) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		value = cellValue(value)
+		text := cellText(value)
+		if _, ok := value.(string); ok {
+			text = escapeFormula(text)
+		}
+		record[i] = text
+	}
+	return c.write(record)
+}
+
+func (c *csvWriter) write(record []string) error {
+	err := c.writer.Write(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to write csv row")
+	}
+
+	// Flush periodically so large exports stream instead of buffering
+	c.rows++
+	if c.rows%500 == 0 {
+		c.writer.Flush()
+		return errors.Wrap(c.writer.Error(), "failed to flush csv")
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.writer.Flush()
+	return errors.Wrap(c.writer.Error(), "failed to flush csv")
+}
+
+// escapeFormula stops spreadsheet apps evaluating user supplied text such as notes as a formula
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
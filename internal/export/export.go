@@ -0,0 +1,126 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Supported export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// timeLayout is how timestamps are written as text; spreadsheet apps recognise it as a date
+const timeLayout = "2006-01-02 15:04:05"
+
+// Writer streams a table row by row. WriteHeader must be called once before any row,
+// and Close must be called to finish the file.
+type Writer interface {
+	WriteHeader(columns ...string) error
+	WriteRow(values ...any) error
+	Close() error
+}
+
+// ParseFormat validates a requested format, defaulting to CSV
+func ParseFormat(format string) (string, error) {
+	switch format {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", errors.Errorf("unknown export format %q, expected csv or xlsx", format)
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Filename builds a dated download name such as purchases-2026-03-01.csv
+func Filename(name, format string, now time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", name, now.UTC().Format("2006-01-02"), format)
+}
+
+// NewWriter returns a writer for format that streams to w. sheet names the worksheet in XLSX files.
+func NewWriter(format string, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w, sheet)
+	}
+	return nil, errors.Errorf("unknown export format %q", format)
+}
+
+// cellValue dereferences pointers and normalises a value to nil, string, int64, float64 or time.Time
+func cellValue(value any) any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return v
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	case *int64:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return v
+	case *float64:
+		if v == nil {
+			return nil
+		}
+		return cellValue(*v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC()
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC()
+	}
+	return fmt.Sprint(value)
+}
+
+// cellText formats a normalised value as text
+func cellText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(timeLayout)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,140 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/export"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseFormat(t *testing.T) {
+	format, err := export.ParseFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, export.FormatCSV, format)
+
+	format, err = export.ParseFormat("xlsx")
+	require.NoError(t, err)
+	assert.Equal(t, export.FormatXLSX, format)
+
+	_, err = export.ParseFormat("pdf")
+	assert.Error(t, err)
+}
+
+func Test_Filename(t *testing.T) {
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, "purchases-2026-03-01.xlsx", export.Filename("purchases", export.FormatXLSX, now))
+}
+
+func Test_CSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := export.NewCSVWriter(&buf)
+
+	notes := "=HYPERLINK(\"http://example.com\")"
+	var missing *string
+	purchasedAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	require.NoError(t, w.WriteHeader("Item", "Quantity", "Price", "Notes", "Contract", "Purchased At"))
+	require.NoError(t, w.WriteRow("Tritanium, compressed", int64(-5), 4.5, &notes, missing, purchasedAt))
+	require.NoError(t, w.Close())
+
+	expected := "Item,Quantity,Price,Notes,Contract,Purchased At\n" +
+		"\"Tritanium, compressed\",-5,4.5,\"'=HYPERLINK(\"\"http://example.com\"\")\",,2026-03-01 12:30:00\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Ref   string `xml:"r,attr"`
+		Cells []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Style  string `xml:"s,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readZipEntry(t *testing.T, archive *zip.Reader, name string) []byte {
+	for _, file := range archive.File {
+		if file.Name == name {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			defer reader.Close()
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			return content
+		}
+	}
+	t.Fatalf("missing %s", name)
+	return nil
+}
+
+func Test_XLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := export.NewWriter(export.FormatXLSX, &buf, "Purchases: buyer/seller")
+	require.NoError(t, err)
+
+	columns := make([]string, 28)
+	for i := range columns {
+		columns[i] = "Column"
+	}
+	columns[0] = "Item"
+	require.NoError(t, w.WriteHeader(columns...))
+
+	row := make([]any, 28)
+	row[0] = "Tritanium <ore> & \"rocks\""
+	row[1] = int64(100)
+	row[2] = 4.5
+	row[3] = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	row[27] = "last"
+	require.NoError(t, w.WriteRow(row...))
+	require.NoError(t, w.Close())
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	names := []string{}
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.ElementsMatch(t, []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+	}, names)
+
+	assert.Contains(t, string(readZipEntry(t, archive, "xl/workbook.xml")), `name="Purchases buyerseller"`)
+
+	var sheet xlsxSheet
+	require.NoError(t, xml.Unmarshal(readZipEntry(t, archive, "xl/worksheets/sheet1.xml"), &sheet))
+	require.Len(t, sheet.Rows, 2)
+
+	header := sheet.Rows[0]
+	assert.Equal(t, "1", header.Ref)
+	require.Len(t, header.Cells, 28)
+	assert.Equal(t, "Item", header.Cells[0].Inline)
+	assert.Equal(t, "1", header.Cells[0].Style)
+	assert.Equal(t, "AB1", header.Cells[27].Ref)
+
+	// Empty cells are omitted
+	data := sheet.Rows[1]
+	require.Len(t, data.Cells, 5)
+	assert.Equal(t, "inlineStr", data.Cells[0].Type)
+	assert.Equal(t, "Tritanium <ore> & \"rocks\"", data.Cells[0].Inline)
+	assert.Equal(t, "B2", data.Cells[1].Ref)
+	assert.Equal(t, "100", data.Cells[1].Value)
+	assert.Equal(t, "4.5", data.Cells[2].Value)
+	assert.Equal(t, "46082.5", data.Cells[3].Value)
+	assert.Equal(t, "2", data.Cells[3].Style)
+	assert.Equal(t, "AB2", data.Cells[4].Ref)
+}
@@ -0,0 +1,212 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	spreadsheetNamespace   = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	relationshipsNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// Cell styles defined in xlsxStyles
+const (
+	styleDefault = 0
+	styleHeader  = 1
+	styleDate    = 2
+)
+
+const xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="` + relationshipsNamespace + `/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRelationships = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="` + relationshipsNamespace + `/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="` + relationshipsNamespace + `/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles defines a plain style, a bold header and a date-time format (built-in 22)
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="` + spreadsheetNamespace + `">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="22" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
+
+// excelEpoch is day zero of the 1900 date system as spreadsheet apps count it
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int
+}
+
+// NewXLSXWriter returns a Writer producing a single-sheet Office Open XML workbook. Rows
+// are written straight into the compressed sheet, so memory use does not grow with the
+// number of rows.
+func NewXLSXWriter(w io.Writer, sheet string) (Writer, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRelationships},
+		{"xl/workbook.xml", xlsxWorkbook(sheet)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRelationships},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		entry, err := archive.Create(part.name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s", part.name)
+		}
+		_, err = io.WriteString(entry, part.content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s", part.name)
+		}
+	}
+
+	entry, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create worksheet")
+	}
+
+	x := &xlsxWriter{
+		archive: archive,
+		sheet:   bufio.NewWriter(entry),
+	}
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	x.sheet.WriteString(`<worksheet xmlns="` + spreadsheetNamespace + `"><sheetData>`)
+
+	return x, nil
+}
+
+func xlsxWorkbook(sheet string) string {
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheetName(sheet)))
+
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="` + spreadsheetNamespace + `" xmlns:r="` + relationshipsNamespace + `">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets>` +
+		`</workbook>`
+}
+
+// sheetName drops characters worksheet names may not contain and applies the 31 character limit
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	runes := []rune(strings.TrimSpace(name))
+	if len(runes) > 31 {
+		runes = runes[:31]
+	}
+	if len(runes) == 0 {
+		return "Sheet1"
+	}
+	return string(runes)
+}
+
+func (x *xlsxWriter) WriteHeader(columns ...string) error {
+	values := make([]any, len(columns))
+	for i, column := range columns {
+		values[i] = column
+	}
+	return x.writeRow(values, styleHeader)
+}
+
+func (x *xlsxWriter) WriteRow(values ...any) error {
+	return x.writeRow(values, styleDefault)
+}
+
+func (x *xlsxWriter) writeRow(values []any, style int) error {
+	x.rows++
+	row := strconv.Itoa(x.rows)
+
+	x.sheet.WriteString(`<row r="` + row + `">`)
+	for i, value := range values {
+		ref := columnName(i) + row
+
+		switch v := cellValue(value).(type) {
+		case nil:
+			continue
+		case int64:
+			x.writeCell(ref, style, "n", strconv.FormatInt(v, 10))
+		case float64:
+			x.writeCell(ref, style, "n", strconv.FormatFloat(v, 'f', -1, 64))
+		case time.Time:
+			serial := v.Sub(excelEpoch).Hours() / 24
+			x.writeCell(ref, styleDate, "n", strconv.FormatFloat(serial, 'f', -1, 64))
+		case string:
+			x.sheet.WriteString(`<c r="` + ref + `"` + styleAttribute(style) + ` t="inlineStr"><is><t xml:space="preserve">`)
+			xml.EscapeText(x.sheet, []byte(v))
+			x.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := x.sheet.WriteString(`</row>`)
+	if err != nil {
+		return errors.Wrap(err, "failed to write xlsx row")
+	}
+	return nil
+}
+
+func (x *xlsxWriter) writeCell(ref string, style int, cellType, value string) {
+	x.sheet.WriteString(`<c r="` + ref + `"` + styleAttribute(style) + ` t="` + cellType + `"><v>` + value + `</v></c>`)
+}
+
+func styleAttribute(style int) string {
+	if style == styleDefault {
+		return ""
+	}
+	return ` s="` + strconv.Itoa(style) + `"`
+}
+
+func (x *xlsxWriter) Close() error {
+	x.sheet.WriteString(`</sheetData></worksheet>`)
+	err := x.sheet.Flush()
+	if err != nil {
+		return errors.Wrap(err, "failed to write worksheet")
+	}
+	return errors.Wrap(x.archive.Close(), "failed to finish xlsx")
+}
+
+// columnName converts a zero based column index to its letters: 0 is A, 26 is AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
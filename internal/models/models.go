@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type EveAsset struct {
 	ItemID          int64  `json:"item_id"`
@@ -14,10 +17,74 @@ type EveAsset struct {
 }
 
 type EveInventoryType struct {
-	TypeID   int64
-	TypeName string
-	Volume   float64
-	IconID   *int64
+	TypeID         int64
+	TypeName       string
+	Volume         float64
+	IconID         *int64
+	GroupID        *int64
+	MarketGroupID  *int64
+	MetaGroupID    *int64
+	ParentTypeID   *int64
+	Published      bool
+	PackagedVolume *float64
+
+	// CategoryID comes from the type's group and is only filled in when reading
+	CategoryID *int64
+}
+
+// UnitPackagedVolume is the volume of one item packaged for hauling. Types the SDE gives no separate
+// packaged volume pack down to their assembled volume.
+func (t EveInventoryType) UnitPackagedVolume() float64 {
+	if t.PackagedVolume != nil {
+		return *t.PackagedVolume
+	}
+	return t.Volume
+}
+
+type ItemCategory struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Published bool   `json:"published"`
+}
+
+type ItemGroup struct {
+	ID         int64  `json:"id"`
+	CategoryID int64  `json:"categoryId"`
+	Name       string `json:"name"`
+	Published  bool   `json:"published"`
+}
+
+// MarketGroup is a node of the market browser tree. Top level groups have no parent.
+type MarketGroup struct {
+	ID       int64  `json:"id"`
+	ParentID *int64 `json:"parentId"`
+	Name     string `json:"name"`
+	HasTypes bool   `json:"hasTypes"`
+}
+
+type MetaGroup struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// ItemMetaType links a variant to its meta group and the Tech I type it derives from
+type ItemMetaType struct {
+	TypeID       int64
+	ParentTypeID *int64
+	MetaGroupID  int64
+}
+
+// ItemTypeFilter narrows item types to those in any of the listed groups, categories, market
+// groups (including their sub-groups) and meta groups. Empty lists don't filter.
+type ItemTypeFilter struct {
+	GroupIDs       []int64
+	CategoryIDs    []int64
+	MarketGroupIDs []int64
+	MetaGroupIDs   []int64
+}
+
+func (f *ItemTypeFilter) IsEmpty() bool {
+	return f == nil || len(f.GroupIDs)+len(f.CategoryIDs)+len(f.MarketGroupIDs)+len(f.MetaGroupIDs) == 0
 }
 
 type Region struct {
@@ -57,6 +124,7 @@ type CorporationDivisions struct {
 }
 
 type StockpileMarker struct {
+	ID              int64   `json:"id"`
 	UserID          int64   `json:"userId"`
 	TypeID          int64   `json:"typeId"`
 	OwnerType       string  `json:"ownerType"`
@@ -66,6 +134,154 @@ type StockpileMarker struct {
 	DivisionNumber  *int    `json:"divisionNumber"`
 	DesiredQuantity int64   `json:"desiredQuantity"`
 	Notes           *string `json:"notes"`
+
+	// Scope is one of the StockpileScope values. Aggregate scopes sum every matching stack, with
+	// LocationID holding the solar system or region ID (0 for anywhere) and OwnerType optionally
+	// StockpileOwnerAll to count every character and corporation the user has.
+	Scope string `json:"scope"`
+
+	// TemplateApplicationID is set on markers generated by a stockpile template
+	TemplateApplicationID *int64 `json:"templateApplicationId"`
+
+	// AutoBuyOrder keeps a buy order open for the marker's deficit, priced at the Jita buy
+	// price plus BuyOrderMarkupPercent
+	AutoBuyOrder          bool    `json:"autoBuyOrder"`
+	BuyOrderMarkupPercent float64 `json:"buyOrderMarkupPercent"`
+
+	// AlertBelowQuantity and AlertDeficitValue send a notification after an asset sync once the
+	// held quantity drops below the threshold or the deficit is worth more than the ISK limit
+	AlertBelowQuantity *int64   `json:"alertBelowQuantity"`
+	AlertDeficitValue  *float64 `json:"alertDeficitValue"`
+}
+
+// StockpileMarkerBulkResult is the outcome of one item of a bulk stockpile marker request. Index
+// is the item's position in the request's upsert or delete list.
+type StockpileMarkerBulkResult struct {
+	Operation string `json:"operation"`
+	Index     int    `json:"index"`
+	ID        int64  `json:"id,omitempty"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
+
+const (
+	StockpileScopeLocation    = "location"
+	StockpileScopeSolarSystem = "solar_system"
+	StockpileScopeRegion      = "region"
+	StockpileScopeAnywhere    = "anywhere"
+
+	StockpileOwnerAll = "all"
+)
+
+// IsValidStockpileScope reports whether scope is one of the StockpileScope values
+func IsValidStockpileScope(scope string) bool {
+	switch scope {
+	case StockpileScopeLocation, StockpileScopeSolarSystem, StockpileScopeRegion, StockpileScopeAnywhere:
+		return true
+	}
+	return false
+}
+
+// StockpileHistorySample is the quantity held against a stockpile marker at one asset sync
+type StockpileHistorySample struct {
+	MarkerID   int64     `json:"markerId"`
+	RecordedAt time.Time `json:"recordedAt"`
+	Quantity   int64     `json:"quantity"`
+}
+
+// StockpileConsumption is how fast a stockpile drains, derived from its history. Fields are nil
+// until there is enough history to tell.
+type StockpileConsumption struct {
+	BurnRatePerDay      *float64 `json:"burnRatePerDay"`
+	DaysUntilEmpty      *float64 `json:"daysUntilEmpty"`
+	RecommendedQuantity *int64   `json:"recommendedQuantity"`
+}
+
+type StockpileHistory struct {
+	MarkerID int64                     `json:"markerId"`
+	Samples  []*StockpileHistorySample `json:"samples"`
+	StockpileConsumption
+}
+
+// StockpileTemplate is a named set of types and quantities that can be applied to many locations
+type StockpileTemplate struct {
+	ID           int64                           `json:"id"`
+	UserID       int64                           `json:"userId"`
+	Name         string                          `json:"name"`
+	Description  *string                         `json:"description"`
+	Items        []*StockpileTemplateItem        `json:"items"`
+	Applications []*StockpileTemplateApplication `json:"applications"`
+	CreatedAt    time.Time                       `json:"createdAt"`
+	UpdatedAt    time.Time                       `json:"updatedAt"`
+}
+
+type StockpileTemplateItem struct {
+	TypeID   int64  `json:"typeId"`
+	TypeName string `json:"typeName"`
+	Quantity int64  `json:"quantity"`
+}
+
+// StockpileTemplateApplication is one location a template generates markers for, each item quantity times Multiplier
+type StockpileTemplateApplication struct {
+	ID             int64     `json:"id"`
+	TemplateID     int64     `json:"templateId"`
+	OwnerType      string    `json:"ownerType"`
+	OwnerID        int64     `json:"ownerId"`
+	LocationID     int64     `json:"locationId"`
+	ContainerID    *int64    `json:"containerId"`
+	DivisionNumber *int      `json:"divisionNumber"`
+	Multiplier     int64     `json:"multiplier"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+const (
+	ReplenishmentMove       = "move"
+	ReplenishmentBuyContact = "buy_contact"
+	ReplenishmentBuyMarket  = "buy_market"
+)
+
+// ReplenishmentPlan covers stockpile deficits with moves from surplus stock, contact listings and
+// market buys, ranked so the cheapest sources come first.
+type ReplenishmentPlan struct {
+	Steps       []*ReplenishmentStep  `json:"steps"`
+	Routes      []*ReplenishmentRoute `json:"routes"`
+	TotalVolume float64               `json:"totalVolume"`
+	TotalCost   float64               `json:"totalCost"`
+}
+
+// ReplenishmentStep is one source filling part of one deficit. Action is one of the Replenishment values.
+type ReplenishmentStep struct {
+	Rank           int     `json:"rank"`
+	Action         string  `json:"action"`
+	TypeID         int64   `json:"typeId"`
+	TypeName       string  `json:"typeName"`
+	Quantity       int64   `json:"quantity"`
+	Volume         float64 `json:"volume"`
+	UnitPrice      float64 `json:"unitPrice"`
+	Cost           float64 `json:"cost"`
+	MarketCost     float64 `json:"marketCost"`
+	FromLocationID int64   `json:"fromLocationId"`
+	FromName       string  `json:"fromName"`
+	FromOwnerName  string  `json:"fromOwnerName"`
+	FromContainer  *string `json:"fromContainer"`
+	ListingID      *int64  `json:"listingId"`
+	ToLocationID   int64   `json:"toLocationId"`
+	ToName         string  `json:"toName"`
+	ToScope        string  `json:"toScope"`
+	ToOwnerName    string  `json:"toOwnerName"`
+	ToContainer    *string `json:"toContainer"`
+}
+
+// ReplenishmentRoute totals the steps sharing an action, source and destination
+type ReplenishmentRoute struct {
+	Action         string  `json:"action"`
+	FromLocationID int64   `json:"fromLocationId"`
+	FromName       string  `json:"fromName"`
+	ToLocationID   int64   `json:"toLocationId"`
+	ToName         string  `json:"toName"`
+	Steps          int     `json:"steps"`
+	Volume         float64 `json:"volume"`
+	Cost           float64 `json:"cost"`
 }
 
 type MarketPrice struct {
@@ -78,14 +294,15 @@ type MarketPrice struct {
 }
 
 type Contact struct {
-	ID              int64      `json:"id"`
-	RequesterUserID int64      `json:"requesterUserId"`
-	RecipientUserID int64      `json:"recipientUserId"`
-	RequesterName   string     `json:"requesterName"`
-	RecipientName   string     `json:"recipientName"`
-	Status          string     `json:"status"`
-	RequestedAt     time.Time  `json:"requestedAt"`
-	RespondedAt     *time.Time `json:"respondedAt"`
+	ID              int64       `json:"id"`
+	RequesterUserID int64       `json:"requesterUserId"`
+	RecipientUserID int64       `json:"recipientUserId"`
+	RequesterName   string      `json:"requesterName"`
+	RecipientName   string      `json:"recipientName"`
+	Status          string      `json:"status"`
+	RequestedAt     time.Time   `json:"requestedAt"`
+	RespondedAt     *time.Time  `json:"respondedAt"`
+	Reputation      *Reputation `json:"reputation,omitempty"`
 }
 
 type ContactPermission struct {
@@ -94,71 +311,386 @@ type ContactPermission struct {
 	GrantingUserID  int64  `json:"grantingUserId"`
 	ReceivingUserID int64  `json:"receivingUserId"`
 	ServiceType     string `json:"serviceType"`
-	CanAccess       bool   `json:"canAccess"`
+	// CanAccess is nil until the granter decides for this contact; group and affiliation
+	// grants then apply, while an explicit false denies access despite them
+	CanAccess *bool `json:"canAccess"`
+	// StationIDs and OwnerIDs limit a scoped grant such as asset_view; nil is unrestricted
+	StationIDs []int64 `json:"stationIds"`
+	OwnerIDs   []int64 `json:"ownerIds"`
+}
+
+// ContactGroup is a named set of a user's contacts
+type ContactGroup struct {
+	ID               int64     `json:"id"`
+	UserID           int64     `json:"userId"`
+	Name             string    `json:"name"`
+	MemberContactIDs []int64   `json:"memberContactIds"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+// ContactGroupPermission grants or denies a service to every member of a group
+type ContactGroupPermission struct {
+	GroupID     int64  `json:"groupId"`
+	ServiceType string `json:"serviceType"`
+	CanAccess   bool   `json:"canAccess"`
+}
+
+// Contact service types. Each one is granted independently and enforced by the
+// controller that serves it.
+const (
+	ServiceForSaleBrowse  = "for_sale_browse"
+	ServiceAssetView      = "asset_view"
+	ServiceStockpileView  = "stockpile_view"
+	ServiceBuyOrderBrowse = "buy_order_browse"
+	ServiceBuildRequest   = "build_request"
+	ServiceHaulingRequest = "hauling_request"
+)
+
+// ServiceType describes a grantable contact service
+type ServiceType struct {
+	Type        string `json:"type"`
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	// Scoped services can be limited to chosen stations and owners
+	Scoped bool `json:"scoped"`
+}
+
+// ServiceTypes is the registry of services a user can grant to contacts,
+// corporations and alliances
+var ServiceTypes = []ServiceType{
+	{Type: ServiceForSaleBrowse, Label: "Browse For-Sale Items", Description: "See and purchase your for-sale listings"},
+	{Type: ServiceAssetView, Label: "View Assets", Description: "See your character and corporation assets", Scoped: true},
+	{Type: ServiceStockpileView, Label: "View Stockpiles", Description: "See your stockpile targets and deficits", Scoped: true},
+	{Type: ServiceBuyOrderBrowse, Label: "View Buy Orders", Description: "See your active buy orders as demand"},
+	{Type: ServiceBuildRequest, Label: "Submit Build Requests", Description: "Ask you to manufacture items"},
+	{Type: ServiceHaulingRequest, Label: "Submit Hauling Requests", Description: "Ask you to move items between locations"},
+}
+
+// IsValidServiceType reports whether serviceType is in the registry
+func IsValidServiceType(serviceType string) bool {
+	for _, s := range ServiceTypes {
+		if s.Type == serviceType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsScopedServiceType reports whether serviceType can be limited to stations and owners
+func IsScopedServiceType(serviceType string) bool {
+	for _, s := range ServiceTypes {
+		if s.Type == serviceType {
+			return s.Scoped
+		}
+	}
+	return false
+}
+
+// Build and hauling request statuses. The provider accepts, rejects and completes a
+// request; the requester can cancel it until it is completed.
+const (
+	ServiceRequestPending   = "pending"
+	ServiceRequestAccepted  = "accepted"
+	ServiceRequestRejected  = "rejected"
+	ServiceRequestCompleted = "completed"
+	ServiceRequestCancelled = "cancelled"
+)
+
+// BuildRequest asks a contact to manufacture items
+type BuildRequest struct {
+	ID              int64     `json:"id"`
+	RequesterUserID int64     `json:"requesterUserId"`
+	BuilderUserID   int64     `json:"builderUserId"`
+	TypeID          int64     `json:"typeId"`
+	TypeName        string    `json:"typeName"`
+	Quantity        int64     `json:"quantity"`
+	Notes           *string   `json:"notes"`
+	Status          string    `json:"status"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// HaulingRequest asks a contact to move items between two stations or structures
+type HaulingRequest struct {
+	ID                    int64     `json:"id"`
+	RequesterUserID       int64     `json:"requesterUserId"`
+	HaulerUserID          int64     `json:"haulerUserId"`
+	OriginLocationID      int64     `json:"originLocationId"`
+	DestinationLocationID int64     `json:"destinationLocationId"`
+	VolumeM3              float64   `json:"volumeM3"`
+	Collateral            int64     `json:"collateral"`
+	Notes                 *string   `json:"notes"`
+	Status                string    `json:"status"`
+	CreatedAt             time.Time `json:"createdAt"`
+	UpdatedAt             time.Time `json:"updatedAt"`
+}
+
+// AffiliationPermission grants a service to every member of a corporation or alliance
+type AffiliationPermission struct {
+	ID             int64  `json:"id"`
+	GrantingUserID int64  `json:"grantingUserId"`
+	ReceiverType   string `json:"receiverType"`
+	ReceiverID     int64  `json:"receiverId"`
+	ServiceType    string `json:"serviceType"`
+	CanAccess      bool   `json:"canAccess"`
 }
 
+type CharacterAffiliation struct {
+	CharacterID   int64
+	CorporationID int64
+	AllianceID    *int64
+}
+
+// CharacterName is an EVE character ID resolved from a name
+type CharacterName struct {
+	ID   int64
+	Name string
+}
+
+// CharacterSearchResult is a candidate recipient for a contact invitation
+type CharacterSearchResult struct {
+	CharacterID   int64  `json:"characterId"`
+	Name          string `json:"name"`
+	CorporationID *int64 `json:"corporationId"`
+	AllianceID    *int64 `json:"allianceId"`
+	Registered    bool   `json:"registered"`
+}
+
+// ContactInvite is a contact request to a character that has not registered yet.
+// It becomes a pending contact once the character is linked to an account.
+type ContactInvite struct {
+	ID              int64     `json:"id"`
+	RequesterUserID int64     `json:"requesterUserId"`
+	CharacterID     int64     `json:"characterId"`
+	CharacterName   *string   `json:"characterName"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+// ForSaleItem is a listing on the contact marketplace. Volume is the packaged volume of
+// QuantityAvailable, since listed items are sold and hauled packaged.
 type ForSaleItem struct {
-	ID                int64     `json:"id"`
-	UserID            int64     `json:"userId"`
-	TypeID            int64     `json:"typeId"`
-	TypeName          string    `json:"typeName"`
-	OwnerType         string    `json:"ownerType"`
-	OwnerID           int64     `json:"ownerId"`
-	OwnerName         string    `json:"ownerName"`
-	LocationID        int64     `json:"locationId"`
-	LocationName      string    `json:"locationName"`
-	ContainerID       *int64    `json:"containerId"`
-	DivisionNumber    *int      `json:"divisionNumber"`
-	QuantityAvailable int64     `json:"quantityAvailable"`
-	PricePerUnit      int64     `json:"pricePerUnit"`
-	Notes             *string   `json:"notes"`
-	IsActive          bool      `json:"isActive"`
-	CreatedAt         time.Time `json:"createdAt"`
-	UpdatedAt         time.Time `json:"updatedAt"`
+	ID                int64            `json:"id"`
+	UserID            int64            `json:"userId"`
+	TypeID            int64            `json:"typeId"`
+	TypeName          string           `json:"typeName"`
+	OwnerType         string           `json:"ownerType"`
+	OwnerID           int64            `json:"ownerId"`
+	OwnerName         string           `json:"ownerName"`
+	LocationID        int64            `json:"locationId"`
+	LocationName      string           `json:"locationName"`
+	ContainerID       *int64           `json:"containerId"`
+	DivisionNumber    *int             `json:"divisionNumber"`
+	QuantityAvailable int64            `json:"quantityAvailable"`
+	Volume            float64          `json:"volume"`
+	PricePerUnit      int64            `json:"pricePerUnit"`
+	FixedPricePerUnit int64            `json:"fixedPricePerUnit"`
+	PricingRule       *PricingRule     `json:"pricingRule,omitempty"`
+	BuildCostPerUnit  *int64           `json:"buildCostPerUnit,omitempty"`
+	Notes             *string          `json:"notes"`
+	IsActive          bool             `json:"isActive"`
+	HeldQuantity      *int64           `json:"heldQuantity,omitempty"`
+	StockCheckedAt    *time.Time       `json:"stockCheckedAt,omitempty"`
+	SellerReputation  *ReputationStats `json:"sellerReputation,omitempty"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	UpdatedAt         time.Time        `json:"updatedAt"`
+	DeletedAt         *time.Time       `json:"deletedAt,omitempty"`
+}
+
+// PricingRule prices a listing or buy order relative to Jita. Basis is
+// "buy", "sell" or "split"; Percent is applied to it and the result is
+// clamped to the optional Floor and Ceiling.
+type PricingRule struct {
+	Basis   string  `json:"basis"`
+	Percent float64 `json:"percent"`
+	Floor   *int64  `json:"floor,omitempty"`
+	Ceiling *int64  `json:"ceiling,omitempty"`
+}
+
+// ForSaleStockLevel compares a listing against the assets backing it
+type ForSaleStockLevel struct {
+	ForSaleItemID     int64
+	QuantityAvailable int64
+	HeldQuantity      int64
+	ReservedQuantity  int64
+}
+
+// ForSaleImportLine is one resolved line of a pasted bulk listing import
+type ForSaleImportLine struct {
+	LineNumber        int     `json:"lineNumber"`
+	Name              string  `json:"name"`
+	TypeID            int64   `json:"typeId,omitempty"`
+	Quantity          int64   `json:"quantity"`
+	AvailableQuantity int64   `json:"availableQuantity"`
+	Error             *string `json:"error,omitempty"`
+}
+
+// ForSaleImportPreview is the validated result of a bulk listing import
+type ForSaleImportPreview struct {
+	Lines      []*ForSaleImportLine `json:"lines"`
+	ValidCount int                  `json:"validCount"`
+	ErrorCount int                  `json:"errorCount"`
+}
+
+// Appraisal values a pasted item list at the market prices current when it was created.
+// Split is the midpoint of buy and sell; items without a price count as zero and are
+// flagged by a nil price.
+type Appraisal struct {
+	Code        string           `json:"code"`
+	UserID      int64            `json:"userId"`
+	RegionID    int64            `json:"regionId"`
+	Items       []*AppraisalItem `json:"items"`
+	Unresolved  []string         `json:"unresolved"`
+	TotalBuy    float64          `json:"totalBuy"`
+	TotalSell   float64          `json:"totalSell"`
+	TotalSplit  float64          `json:"totalSplit"`
+	TotalVolume float64          `json:"totalVolume"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+type AppraisalItem struct {
+	TypeID      int64    `json:"typeId"`
+	TypeName    string   `json:"typeName"`
+	Quantity    int64    `json:"quantity"`
+	BuyPrice    *float64 `json:"buyPrice"`
+	SellPrice   *float64 `json:"sellPrice"`
+	SplitPrice  *float64 `json:"splitPrice"`
+	BuyTotal    float64  `json:"buyTotal"`
+	SellTotal   float64  `json:"sellTotal"`
+	SplitTotal  float64  `json:"splitTotal"`
+	Volume      float64  `json:"volume"`
+	TotalVolume float64  `json:"totalVolume"`
 }
 
 type PurchaseTransaction struct {
-	ID                int64     `json:"id"`
-	ForSaleItemID     int64     `json:"forSaleItemId"`
-	BuyerUserID       int64     `json:"buyerUserId"`
-	BuyerName         string    `json:"buyerName"`
-	SellerUserID      int64     `json:"sellerUserId"`
-	TypeID            int64     `json:"typeId"`
-	TypeName          string    `json:"typeName"`
-	LocationID        int64     `json:"locationId"`
-	LocationName      string    `json:"locationName"`
-	QuantityPurchased int64     `json:"quantityPurchased"`
-	PricePerUnit      int64     `json:"pricePerUnit"`
-	TotalPrice        int64     `json:"totalPrice"`
-	Status            string    `json:"status"`
-	ContractKey       *string   `json:"contractKey,omitempty"`
-	TransactionNotes  *string   `json:"transactionNotes"`
-	PurchasedAt       time.Time `json:"purchasedAt"`
+	ID                 int64      `json:"id"`
+	ForSaleItemID      int64      `json:"forSaleItemId"`
+	BuyerUserID        int64      `json:"buyerUserId"`
+	BuyerName          string     `json:"buyerName"`
+	SellerUserID       int64      `json:"sellerUserId"`
+	TypeID             int64      `json:"typeId"`
+	TypeName           string     `json:"typeName"`
+	LocationID         int64      `json:"locationId"`
+	LocationName       string     `json:"locationName"`
+	QuantityPurchased  int64      `json:"quantityPurchased"`
+	PricePerUnit       int64      `json:"pricePerUnit"`
+	TotalPrice         int64      `json:"totalPrice"`
+	Status             string     `json:"status"`
+	ContractKey        *string    `json:"contractKey,omitempty"`
+	TransactionNotes   *string    `json:"transactionNotes"`
+	CancellationReason *string    `json:"cancellationReason,omitempty"`
+	CancelledAt        *time.Time `json:"cancelledAt,omitempty"`
+	PurchasedAt        time.Time  `json:"purchasedAt"`
+}
+
+type PurchaseExpirySettings struct {
+	UserID       int64 `json:"userId"`
+	TimeoutHours int   `json:"timeoutHours"`
 }
 
 type BuyOrder struct {
-	ID               int64     `json:"id"`
-	BuyerUserID      int64     `json:"buyerUserId"`
-	TypeID           int64     `json:"typeId"`
-	TypeName         string    `json:"typeName"`
-	QuantityDesired  int64     `json:"quantityDesired"`
-	MaxPricePerUnit  int64     `json:"maxPricePerUnit"`
-	Notes            *string   `json:"notes"`
-	IsActive         bool      `json:"isActive"`
-	CreatedAt        time.Time `json:"createdAt"`
-	UpdatedAt        time.Time `json:"updatedAt"`
+	ID                   int64        `json:"id"`
+	BuyerUserID          int64        `json:"buyerUserId"`
+	TypeID               int64        `json:"typeId"`
+	TypeName             string       `json:"typeName"`
+	QuantityDesired      int64        `json:"quantityDesired"`
+	MaxPricePerUnit      int64        `json:"maxPricePerUnit"`
+	FixedMaxPricePerUnit int64        `json:"fixedMaxPricePerUnit"`
+	PricingRule          *PricingRule `json:"pricingRule,omitempty"`
+	Notes                *string      `json:"notes"`
+	IsActive             bool         `json:"isActive"`
+	CreatedAt            time.Time    `json:"createdAt"`
+	UpdatedAt            time.Time    `json:"updatedAt"`
+
+	// StockpileMarkerID is set on orders kept in line with a stockpile deficit after each asset sync
+	StockpileMarkerID *int64 `json:"stockpileMarkerId,omitempty"`
+}
+
+// StockpileBuyOrderTarget is a marker opted in to an automatic buy order, with the quantity
+// held against it and its linked order, if one has been created
+type StockpileBuyOrderTarget struct {
+	MarkerID        int64
+	TypeID          int64
+	DesiredQuantity int64
+	HeldQuantity    int64
+	MarkupPercent   float64
+	// JitaBuyPrice is the current Jita buy price of the type, 0 when there is none
+	JitaBuyPrice float64
+	BuyOrder     *BuyOrder
+}
+
+// StockpileAlertState is a marker with an alert configured, or one whose alert last fired, with
+// what it holds now
+type StockpileAlertState struct {
+	MarkerID           int64
+	TypeID             int64
+	TypeName           string
+	Scope              string
+	LocationName       string
+	OwnerName          string
+	DesiredQuantity    int64
+	HeldQuantity       int64
+	DeficitValue       float64
+	AlertBelowQuantity *int64
+	AlertDeficitValue  *float64
+	Triggered          bool
+}
+
+const (
+	NotificationKindStockpileAlert = "stockpile_alert"
+)
+
+type Notification struct {
+	ID                int64      `json:"id"`
+	UserID            int64      `json:"userId"`
+	Kind              string     `json:"kind"`
+	Title             string     `json:"title"`
+	Message           string     `json:"message"`
+	StockpileMarkerID *int64     `json:"stockpileMarkerId,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	ReadAt            *time.Time `json:"readAt"`
+}
+
+// NotificationSettings holds the user's delivery channels besides the in-app list. Nil fields
+// leave the channel off.
+type NotificationSettings struct {
+	UserID          int64   `json:"userId"`
+	WebhookURL      *string `json:"webhookUrl"`
+	MailCharacterID *int64  `json:"mailCharacterId"`
 }
 
 // Sales Analytics Models
 
+// SalesMetrics aggregates a seller's completed sales. Cost and profit only cover
+// sales with a known cost basis; UncostedRevenue is the revenue left out, and
+// ProfitMargin is GrossProfit over the costed revenue.
 type SalesMetrics struct {
-	TotalRevenue      int64          `json:"totalRevenue"`
-	TotalTransactions int64          `json:"totalTransactions"`
-	TotalQuantitySold int64          `json:"totalQuantitySold"`
-	UniqueItemTypes   int64          `json:"uniqueItemTypes"`
-	UniqueBuyers      int64          `json:"uniqueBuyers"`
+	TotalRevenue      int64            `json:"totalRevenue"`
+	TotalTransactions int64            `json:"totalTransactions"`
+	TotalQuantitySold int64            `json:"totalQuantitySold"`
+	UniqueItemTypes   int64            `json:"uniqueItemTypes"`
+	UniqueBuyers      int64            `json:"uniqueBuyers"`
+	TotalCost         int64            `json:"totalCost"`
+	GrossProfit       int64            `json:"grossProfit"`
+	ProfitMargin      float64          `json:"profitMargin"`
+	UncostedRevenue   int64            `json:"uncostedRevenue"`
+	Bucket            string           `json:"bucket"`
 	TimeSeriesData    []TimeSeriesData `json:"timeSeriesData"`
 	TopItems          []ItemSalesData  `json:"topItems"`
+	Previous          *SalesSummary    `json:"previous,omitempty"`
+}
+
+// SalesSummary holds the headline sales totals of the period before the requested one
+type SalesSummary struct {
+	TotalRevenue      int64   `json:"totalRevenue"`
+	TotalTransactions int64   `json:"totalTransactions"`
+	TotalQuantitySold int64   `json:"totalQuantitySold"`
+	UniqueItemTypes   int64   `json:"uniqueItemTypes"`
+	UniqueBuyers      int64   `json:"uniqueBuyers"`
+	TotalCost         int64   `json:"totalCost"`
+	GrossProfit       int64   `json:"grossProfit"`
+	ProfitMargin      float64 `json:"profitMargin"`
+	UncostedRevenue   int64   `json:"uncostedRevenue"`
 }
 
 type TimeSeriesData struct {
@@ -166,6 +698,8 @@ type TimeSeriesData struct {
 	Revenue           int64  `json:"revenue"`
 	Transactions      int64  `json:"transactions"`
 	QuantitySold      int64  `json:"quantitySold"`
+	Cost              int64  `json:"cost"`
+	GrossProfit       int64  `json:"grossProfit"`
 }
 
 type ItemSalesData struct {
@@ -175,6 +709,10 @@ type ItemSalesData struct {
 	Revenue           int64   `json:"revenue"`
 	TransactionCount  int64   `json:"transactionCount"`
 	AveragePricePerUnit int64 `json:"averagePricePerUnit"`
+	TotalCost         int64   `json:"totalCost"`
+	GrossProfit       int64   `json:"grossProfit"`
+	ProfitMargin      float64 `json:"profitMargin"`
+	UncostedRevenue   int64   `json:"uncostedRevenue"`
 }
 
 type BuyerAnalytics struct {
@@ -187,3 +725,138 @@ type BuyerAnalytics struct {
 	LastPurchaseDate  time.Time `json:"lastPurchaseDate"`
 	RepeatCustomer    bool    `json:"repeatCustomer"`
 }
+
+// PurchaseMetrics mirrors SalesMetrics for the buyer. Jita comparisons only cover
+// purchases with a Jita sell price recorded on or before the purchase day;
+// UnpricedSpend is the spend left out. PriceVsJita is the priced spend over its
+// Jita value, so 0.9 means the buyer paid 90% of Jita.
+type PurchaseMetrics struct {
+	TotalSpent          int64                    `json:"totalSpent"`
+	TotalPurchases      int64                    `json:"totalPurchases"`
+	TotalQuantityBought int64                    `json:"totalQuantityBought"`
+	UniqueItemTypes     int64                    `json:"uniqueItemTypes"`
+	UniqueSellers       int64                    `json:"uniqueSellers"`
+	JitaValue           int64                    `json:"jitaValue"`
+	SavingsVsJita       int64                    `json:"savingsVsJita"`
+	PriceVsJita         float64                  `json:"priceVsJita"`
+	UnpricedSpend       int64                    `json:"unpricedSpend"`
+	Bucket              string                   `json:"bucket"`
+	TimeSeriesData      []PurchaseTimeSeriesData `json:"timeSeriesData"`
+	TopItems            []ItemPurchaseData       `json:"topItems"`
+	TopSellers          []SellerAnalytics        `json:"topSellers"`
+	Previous            *PurchaseSummary         `json:"previous,omitempty"`
+}
+
+// PurchaseSummary holds the headline spending totals of the period before the requested one
+type PurchaseSummary struct {
+	TotalSpent          int64   `json:"totalSpent"`
+	TotalPurchases      int64   `json:"totalPurchases"`
+	TotalQuantityBought int64   `json:"totalQuantityBought"`
+	UniqueItemTypes     int64   `json:"uniqueItemTypes"`
+	UniqueSellers       int64   `json:"uniqueSellers"`
+	JitaValue           int64   `json:"jitaValue"`
+	SavingsVsJita       int64   `json:"savingsVsJita"`
+	PriceVsJita         float64 `json:"priceVsJita"`
+	UnpricedSpend       int64   `json:"unpricedSpend"`
+}
+
+type PurchaseTimeSeriesData struct {
+	Date           string `json:"date"`
+	Spent          int64  `json:"spent"`
+	Purchases      int64  `json:"purchases"`
+	QuantityBought int64  `json:"quantityBought"`
+	JitaValue      int64  `json:"jitaValue"`
+}
+
+type ItemPurchaseData struct {
+	TypeID           int64   `json:"typeId"`
+	TypeName         string  `json:"typeName"`
+	QuantityBought   int64   `json:"quantityBought"`
+	TotalSpent       int64   `json:"totalSpent"`
+	PurchaseCount    int64   `json:"purchaseCount"`
+	AveragePricePaid int64   `json:"averagePricePaid"`
+	AverageJitaPrice int64   `json:"averageJitaPrice"`
+	JitaValue        int64   `json:"jitaValue"`
+	SavingsVsJita    int64   `json:"savingsVsJita"`
+	PriceVsJita      float64 `json:"priceVsJita"`
+	UnpricedSpend    int64   `json:"unpricedSpend"`
+}
+
+type SellerAnalytics struct {
+	SellerUserID      int64     `json:"sellerUserId"`
+	SellerName        string    `json:"sellerName"`
+	TotalSpent        int64     `json:"totalSpent"`
+	TotalPurchases    int64     `json:"totalPurchases"`
+	TotalQuantity     int64     `json:"totalQuantity"`
+	FirstPurchaseDate time.Time `json:"firstPurchaseDate"`
+	LastPurchaseDate  time.Time `json:"lastPurchaseDate"`
+	JitaValue         int64     `json:"jitaValue"`
+	SavingsVsJita     int64     `json:"savingsVsJita"`
+	PriceVsJita       float64   `json:"priceVsJita"`
+	UnpricedSpend     int64     `json:"unpricedSpend"`
+}
+
+// Reputation Models
+
+// ReputationStats summarizes a user's closed purchases in one role. Rates are
+// fractions of Transactions (completed plus cancelled purchases).
+type ReputationStats struct {
+	Transactions          int64    `json:"transactions"`
+	Completed             int64    `json:"completed"`
+	CompletionRate        float64  `json:"completionRate"`
+	CancelledByBuyerRate  float64  `json:"cancelledByBuyerRate"`
+	CancelledBySellerRate float64  `json:"cancelledBySellerRate"`
+	ExpiredRate           float64  `json:"expiredRate"`
+	MedianCompletionHours *float64 `json:"medianCompletionHours"`
+}
+
+type Reputation struct {
+	UserID   int64           `json:"userId"`
+	AsSeller ReputationStats `json:"asSeller"`
+	AsBuyer  ReputationStats `json:"asBuyer"`
+}
+
+// BuyOrderMatch pairs buy orders with listings that can fill them at or below the order's price
+type BuyOrderMatch struct {
+	BuyOrders []*BuyOrder    `json:"buyOrders"`
+	Listings  []*ForSaleItem `json:"listings"`
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is a user's endpoint for marketplace events. An empty EventTypes list receives every event.
+// Secret is only in full when the webhook is created or its secret rotated, and masked otherwise.
+type Webhook struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"userId"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret"`
+	EventTypes []string  `json:"eventTypes"`
+	IsActive   bool      `json:"isActive"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// WebhookDelivery is one event sent, or still to be sent, to a webhook
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhookId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"nextAttemptAt"`
+	LastStatusCode *int            `json:"lastStatusCode"`
+	LastError      *string         `json:"lastError"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	DeliveredAt    *time.Time      `json:"deliveredAt"`
+
+	// URL and Secret are only loaded for sending
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
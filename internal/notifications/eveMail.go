@@ -0,0 +1,69 @@
+package notifications
+
+import (
+	"context"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/client"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/pkg/errors"
+)
+
+type CharacterRepository interface {
+	GetAll(ctx context.Context, baseUserID int64) ([]*repositories.Character, error)
+}
+
+type MailSender interface {
+	SendMail(ctx context.Context, characterID int64, token, refresh string, expire time.Time, mail *client.Mail) (int64, error)
+}
+
+// EveMail mails notifications in game from the user's chosen character to itself
+type EveMail struct {
+	characters CharacterRepository
+	sender     MailSender
+}
+
+func NewEveMail(characters CharacterRepository, sender MailSender) *EveMail {
+	return &EveMail{
+		characters: characters,
+		sender:     sender,
+	}
+}
+
+func (c *EveMail) Name() string {
+	return "eve_mail"
+}
+
+func (c *EveMail) Send(ctx context.Context, settings *models.NotificationSettings, notification *models.Notification) error {
+	if settings.MailCharacterID == nil {
+		return nil
+	}
+
+	characters, err := c.characters.GetAll(ctx, settings.UserID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get characters")
+	}
+
+	var character *repositories.Character
+	for _, char := range characters {
+		if char.ID == *settings.MailCharacterID {
+			character = char
+			break
+		}
+	}
+	if character == nil {
+		return errors.Errorf("mail character %d does not belong to the user", *settings.MailCharacterID)
+	}
+
+	_, err = c.sender.SendMail(ctx, character.ID, character.EsiToken, character.EsiRefreshToken, character.EsiTokenExpiresOn, &client.Mail{
+		Recipients: []client.MailRecipient{{RecipientID: character.ID, RecipientType: "character"}},
+		Subject:    notification.Title,
+		Body:       notification.Message,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to send eve mail")
+	}
+
+	return nil
+}
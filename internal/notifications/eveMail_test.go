@@ -0,0 +1,66 @@
+package notifications_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/client"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/notifications"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EveMailShouldMailTheChosenCharacter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCharacters := NewMockCharacterRepository(ctrl)
+	mockSender := NewMockMailSender(ctrl)
+
+	expires := time.Now().Add(time.Hour)
+	mockCharacters.EXPECT().GetAll(gomock.Any(), int64(42)).Return([]*repositories.Character{
+		{ID: 1001, Name: "Main", EsiToken: "main-token"},
+		{ID: 1002, Name: "Hauler", EsiToken: "hauler-token", EsiRefreshToken: "hauler-refresh", EsiTokenExpiresOn: expires},
+	}, nil)
+
+	mockSender.EXPECT().SendMail(gomock.Any(), int64(1002), "hauler-token", "hauler-refresh", expires, &client.Mail{
+		Recipients: []client.MailRecipient{{RecipientID: 1002, RecipientType: "character"}},
+		Subject:    "Tritanium is low",
+		Body:       "Holding 100 of 1000",
+	}).Return(int64(5555), nil)
+
+	characterID := int64(1002)
+	channel := notifications.NewEveMail(mockCharacters, mockSender)
+	err := channel.Send(context.Background(), &models.NotificationSettings{UserID: 42, MailCharacterID: &characterID}, &models.Notification{
+		Title:   "Tritanium is low",
+		Message: "Holding 100 of 1000",
+	})
+	assert.NoError(t, err)
+}
+
+func Test_EveMailShouldRejectUnknownCharacter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCharacters := NewMockCharacterRepository(ctrl)
+	mockSender := NewMockMailSender(ctrl)
+
+	mockCharacters.EXPECT().GetAll(gomock.Any(), int64(42)).Return([]*repositories.Character{{ID: 1001}}, nil)
+
+	characterID := int64(9999)
+	channel := notifications.NewEveMail(mockCharacters, mockSender)
+	err := channel.Send(context.Background(), &models.NotificationSettings{UserID: 42, MailCharacterID: &characterID}, &models.Notification{})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not belong to the user")
+}
+
+func Test_EveMailShouldSkipWhenNotConfigured(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channel := notifications.NewEveMail(NewMockCharacterRepository(ctrl), NewMockMailSender(ctrl))
+	assert.NoError(t, channel.Send(context.Background(), &models.NotificationSettings{UserID: 42}, &models.Notification{}))
+}
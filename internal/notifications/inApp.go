@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+type NotificationsRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+}
+
+// InApp stores notifications for the GET /v1/notifications list. It is always on.
+type InApp struct {
+	repository NotificationsRepository
+}
+
+func NewInApp(repository NotificationsRepository) *InApp {
+	return &InApp{repository: repository}
+}
+
+func (c *InApp) Name() string {
+	return "in_app"
+}
+
+func (c *InApp) Send(ctx context.Context, settings *models.NotificationSettings, notification *models.Notification) error {
+	return c.repository.Create(ctx, notification)
+}
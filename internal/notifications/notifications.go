@@ -0,0 +1,47 @@
+package notifications
+
+import (
+	"context"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// Channel delivers a notification one way. Channels the user has not configured in their
+// settings do nothing.
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, settings *models.NotificationSettings, notification *models.Notification) error
+}
+
+type Dispatcher struct {
+	channels []Channel
+}
+
+func NewDispatcher(channels ...Channel) *Dispatcher {
+	return &Dispatcher{channels: channels}
+}
+
+// Dispatch sends the notification through every channel in order. A failing channel doesn't stop
+// the others; the first failure is returned once all have been tried.
+func (d *Dispatcher) Dispatch(ctx context.Context, settings *models.NotificationSettings, notification *models.Notification) error {
+	if settings == nil {
+		settings = &models.NotificationSettings{UserID: notification.UserID}
+	}
+
+	var firstErr error
+	for _, channel := range d.channels {
+		err := channel.Send(ctx, settings, notification)
+		if err == nil {
+			continue
+		}
+
+		log.Warn("notification delivery failed", "user_id", notification.UserID, "channel", channel.Name(), "error", err)
+		if firstErr == nil {
+			firstErr = errors.Wrapf(err, "failed to deliver notification through %s", channel.Name())
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,180 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/notifications (interfaces: Channel,NotificationsRepository,CharacterRepository,MailSender)
+
+// Package notifications_test is a generated GoMock package.
+package notifications_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	client "github.com/annymsMthd/industry-tool/internal/client"
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	repositories "github.com/annymsMthd/industry-tool/internal/repositories"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChannel is a mock of Channel interface.
+type MockChannel struct {
+	ctrl     *gomock.Controller
+	recorder *MockChannelMockRecorder
+}
+
+// MockChannelMockRecorder is the mock recorder for MockChannel.
+type MockChannelMockRecorder struct {
+	mock *MockChannel
+}
+
+// NewMockChannel creates a new mock instance.
+func NewMockChannel(ctrl *gomock.Controller) *MockChannel {
+	mock := &MockChannel{ctrl: ctrl}
+	mock.recorder = &MockChannelMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChannel) EXPECT() *MockChannelMockRecorder {
+	return m.recorder
+}
+
+// Name mocks base method.
+func (m *MockChannel) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockChannelMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockChannel)(nil).Name))
+}
+
+// Send mocks base method.
+func (m *MockChannel) Send(arg0 context.Context, arg1 *models.NotificationSettings, arg2 *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Send", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Send indicates an expected call of Send.
+func (mr *MockChannelMockRecorder) Send(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Send", reflect.TypeOf((*MockChannel)(nil).Send), arg0, arg1, arg2)
+}
+
+// MockNotificationsRepository is a mock of NotificationsRepository interface.
+type MockNotificationsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationsRepositoryMockRecorder
+}
+
+// MockNotificationsRepositoryMockRecorder is the mock recorder for MockNotificationsRepository.
+type MockNotificationsRepositoryMockRecorder struct {
+	mock *MockNotificationsRepository
+}
+
+// NewMockNotificationsRepository creates a new mock instance.
+func NewMockNotificationsRepository(ctrl *gomock.Controller) *MockNotificationsRepository {
+	mock := &MockNotificationsRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationsRepository) EXPECT() *MockNotificationsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockNotificationsRepository) Create(arg0 context.Context, arg1 *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockNotificationsRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockNotificationsRepository)(nil).Create), arg0, arg1)
+}
+
+// MockCharacterRepository is a mock of CharacterRepository interface.
+type MockCharacterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCharacterRepositoryMockRecorder
+}
+
+// MockCharacterRepositoryMockRecorder is the mock recorder for MockCharacterRepository.
+type MockCharacterRepositoryMockRecorder struct {
+	mock *MockCharacterRepository
+}
+
+// NewMockCharacterRepository creates a new mock instance.
+func NewMockCharacterRepository(ctrl *gomock.Controller) *MockCharacterRepository {
+	mock := &MockCharacterRepository{ctrl: ctrl}
+	mock.recorder = &MockCharacterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCharacterRepository) EXPECT() *MockCharacterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockCharacterRepository) GetAll(arg0 context.Context, arg1 int64) ([]*repositories.Character, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", arg0, arg1)
+	ret0, _ := ret[0].([]*repositories.Character)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockCharacterRepositoryMockRecorder) GetAll(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockCharacterRepository)(nil).GetAll), arg0, arg1)
+}
+
+// MockMailSender is a mock of MailSender interface.
+type MockMailSender struct {
+	ctrl     *gomock.Controller
+	recorder *MockMailSenderMockRecorder
+}
+
+// MockMailSenderMockRecorder is the mock recorder for MockMailSender.
+type MockMailSenderMockRecorder struct {
+	mock *MockMailSender
+}
+
+// NewMockMailSender creates a new mock instance.
+func NewMockMailSender(ctrl *gomock.Controller) *MockMailSender {
+	mock := &MockMailSender{ctrl: ctrl}
+	mock.recorder = &MockMailSenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMailSender) EXPECT() *MockMailSenderMockRecorder {
+	return m.recorder
+}
+
+// SendMail mocks base method.
+func (m *MockMailSender) SendMail(arg0 context.Context, arg1 int64, arg2, arg3 string, arg4 time.Time, arg5 *client.Mail) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMail", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMail indicates an expected call of SendMail.
+func (mr *MockMailSenderMockRecorder) SendMail(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMail", reflect.TypeOf((*MockMailSender)(nil).SendMail), arg0, arg1, arg2, arg3, arg4, arg5)
+}
@@ -0,0 +1,59 @@
+package notifications_test
+
+//go:generate mockgen -destination=notifications_mocks_test.go -package=notifications_test github.com/annymsMthd/industry-tool/internal/notifications Channel,NotificationsRepository,CharacterRepository,MailSender
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/notifications"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_DispatcherShouldTryEveryChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	failing := NewMockChannel(ctrl)
+	working := NewMockChannel(ctrl)
+
+	notification := &models.Notification{UserID: 42, Title: "Low stock"}
+
+	failing.EXPECT().Send(gomock.Any(), gomock.Any(), notification).Return(errors.New("connection refused"))
+	failing.EXPECT().Name().Return("webhook").AnyTimes()
+	working.EXPECT().Send(gomock.Any(), gomock.Any(), notification).Return(nil)
+
+	dispatcher := notifications.NewDispatcher(failing, working)
+	err := dispatcher.Dispatch(context.Background(), nil, notification)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to deliver notification through webhook")
+}
+
+func Test_DispatcherShouldDefaultSettings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channel := NewMockChannel(ctrl)
+	notification := &models.Notification{UserID: 42}
+
+	channel.EXPECT().Send(gomock.Any(), &models.NotificationSettings{UserID: 42}, notification).Return(nil)
+
+	dispatcher := notifications.NewDispatcher(channel)
+	assert.NoError(t, dispatcher.Dispatch(context.Background(), nil, notification))
+}
+
+func Test_InAppShouldStoreNotification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockNotificationsRepository(ctrl)
+	notification := &models.Notification{UserID: 42, Title: "Low stock"}
+
+	mockRepo.EXPECT().Create(gomock.Any(), notification).Return(nil)
+
+	channel := notifications.NewInApp(mockRepo)
+	assert.NoError(t, channel.Send(context.Background(), &models.NotificationSettings{UserID: 42}, notification))
+}
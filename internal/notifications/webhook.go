@@ -0,0 +1,70 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// webhookPayload works with both Discord and Slack incoming webhooks: Discord reads content and
+// Slack reads text, each ignoring the other
+type webhookPayload struct {
+	Content string `json:"content"`
+	Text    string `json:"text"`
+}
+
+// Webhook posts notifications to the user's Discord or Slack style webhook URL
+type Webhook struct {
+	client HTTPDoer
+}
+
+func NewWebhook(client HTTPDoer) *Webhook {
+	return &Webhook{client: client}
+}
+
+func (c *Webhook) Name() string {
+	return "webhook"
+}
+
+func (c *Webhook) Send(ctx context.Context, settings *models.NotificationSettings, notification *models.Notification) error {
+	if settings.WebhookURL == nil || *settings.WebhookURL == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(webhookPayload{
+		Content: fmt.Sprintf("**%s**\n%s", notification.Title, notification.Message),
+		Text:    fmt.Sprintf("*%s*\n%s", notification.Title, notification.Message),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook payload")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", *settings.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post webhook")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		errText, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return errors.New(fmt.Sprintf("webhook returned statusCode %d, %s", res.StatusCode, errText))
+	}
+
+	return nil
+}
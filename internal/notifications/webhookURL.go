@@ -0,0 +1,77 @@
+package notifications
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrPrivateWebhookAddress is returned when a webhook would reach a loopback, private, link-local
+// or otherwise non-public address, which would let users probe the server's own network
+var ErrPrivateWebhookAddress = errors.New("webhook URL must not point at a loopback or private address")
+
+// ValidateWebhookURL checks that rawURL is an https URL whose host is not obviously internal. Host
+// names are resolved when the webhook is sent, where NewWebhookHTTPClient refuses internal addresses.
+func ValidateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return errors.New("webhook URL must be an https URL")
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") || strings.HasSuffix(host, ".local") {
+		return ErrPrivateWebhookAddress
+	}
+
+	ip := net.ParseIP(host)
+	if ip != nil && !IsPublicIP(ip) {
+		return ErrPrivateWebhookAddress
+	}
+
+	return nil
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate() && !isSharedAddress(ip)
+}
+
+// isSharedAddress covers 100.64.0.0/10, the carrier-grade NAT range IsPrivate leaves out
+func isSharedAddress(ip net.IP) bool {
+	ip4 := ip.To4()
+	return ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64
+}
+
+// NewWebhookHTTPClient returns a client that refuses to connect to non-public addresses. The check
+// runs on the resolved address of every connection, so it also covers host names that resolve to
+// internal addresses and redirects.
+func NewWebhookHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return errors.Wrap(err, "invalid webhook address")
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !IsPublicIP(ip) {
+				return ErrPrivateWebhookAddress
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -0,0 +1,71 @@
+package notifications_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ValidateWebhookURL(t *testing.T) {
+	cases := []struct {
+		url     string
+		invalid bool
+		private bool
+	}{
+		{url: "https://discord.com/api/webhooks/1/abc"},
+		{url: "https://hooks.slack.com/services/T0/B0/x"},
+		{url: "https://203.0.113.10/hook"},
+		{url: "http://discord.com/api/webhooks/1/abc", invalid: true},
+		{url: "not a url", invalid: true},
+		{url: "https:///hook", invalid: true},
+		{url: "https://localhost/hook", private: true},
+		{url: "https://LOCALHOST./hook", private: true},
+		{url: "https://metadata.google.internal/computeMetadata/v1", private: true},
+		{url: "https://127.0.0.1/hook", private: true},
+		{url: "https://127.0.0.1:8443/hook", private: true},
+		{url: "https://10.0.0.5/hook", private: true},
+		{url: "https://172.16.4.1/hook", private: true},
+		{url: "https://192.168.1.1/hook", private: true},
+		{url: "https://100.64.0.1/hook", private: true},
+		{url: "https://169.254.169.254/latest/meta-data", private: true},
+		{url: "https://0.0.0.0/hook", private: true},
+		{url: "https://[::1]/hook", private: true},
+		{url: "https://[fd00::1]/hook", private: true},
+		{url: "https://[fe80::1]/hook", private: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			err := notifications.ValidateWebhookURL(c.url)
+			switch {
+			case c.private:
+				assert.ErrorIs(t, err, notifications.ErrPrivateWebhookAddress)
+			case c.invalid:
+				assert.EqualError(t, err, "webhook URL must be an https URL")
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_WebhookHTTPClientShouldRefuseLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	client := notifications.NewWebhookHTTPClient(5 * time.Second)
+
+	// The URL itself looks harmless once a name resolves to loopback; the dial is what is refused
+	url := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	_, err := client.Post(url, "application/json", strings.NewReader("{}"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, notifications.ErrPrivateWebhookAddress)
+}
@@ -0,0 +1,57 @@
+package notifications_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/notifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WebhookShouldPostNotification(t *testing.T) {
+	var received map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	url := server.URL
+	channel := notifications.NewWebhook(server.Client())
+	err := channel.Send(context.Background(), &models.NotificationSettings{UserID: 42, WebhookURL: &url}, &models.Notification{
+		Title:   "Tritanium is low",
+		Message: "Holding 100 of 1000",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "**Tritanium is low**\nHolding 100 of 1000", received["content"])
+	assert.Equal(t, "*Tritanium is low*\nHolding 100 of 1000", received["text"])
+}
+
+func Test_WebhookShouldReturnErrorOnFailureStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Unknown Webhook"))
+	}))
+	defer server.Close()
+
+	url := server.URL
+	channel := notifications.NewWebhook(server.Client())
+	err := channel.Send(context.Background(), &models.NotificationSettings{WebhookURL: &url}, &models.Notification{Title: "Low stock"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "404")
+	assert.Contains(t, err.Error(), "Unknown Webhook")
+}
+
+func Test_WebhookShouldSkipWhenNotConfigured(t *testing.T) {
+	channel := notifications.NewWebhook(http.DefaultClient)
+	err := channel.Send(context.Background(), &models.NotificationSettings{}, &models.Notification{Title: "Low stock"})
+	assert.NoError(t, err)
+}
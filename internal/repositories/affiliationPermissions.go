@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// ErrAffiliationPermissionNotFound is returned when deleting a grant that does not exist or belongs to another user
+var ErrAffiliationPermissionNotFound = errors.New("affiliation permission not found")
+
+type AffiliationPermissions struct {
+	db *sql.DB
+}
+
+func NewAffiliationPermissions(db *sql.DB) *AffiliationPermissions {
+	return &AffiliationPermissions{db: db}
+}
+
+// GetByGrantingUser returns every corporation and alliance grant a user has made
+func (r *AffiliationPermissions) GetByGrantingUser(ctx context.Context, userID int64) ([]*models.AffiliationPermission, error) {
+	query := `
+		SELECT id, granting_user_id, receiver_type, receiver_id, service_type, can_access
+		FROM affiliation_permissions
+		WHERE granting_user_id = $1
+		ORDER BY receiver_type, receiver_id, service_type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query affiliation permissions")
+	}
+	defer rows.Close()
+
+	permissions := []*models.AffiliationPermission{}
+	for rows.Next() {
+		var perm models.AffiliationPermission
+		err = rows.Scan(
+			&perm.ID,
+			&perm.GrantingUserID,
+			&perm.ReceiverType,
+			&perm.ReceiverID,
+			&perm.ServiceType,
+			&perm.CanAccess,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan affiliation permission")
+		}
+		permissions = append(permissions, &perm)
+	}
+
+	return permissions, nil
+}
+
+// Upsert creates or updates a corporation or alliance grant
+func (r *AffiliationPermissions) Upsert(ctx context.Context, perm *models.AffiliationPermission) error {
+	query := `
+		INSERT INTO affiliation_permissions
+		(granting_user_id, receiver_type, receiver_id, service_type, can_access, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (granting_user_id, receiver_type, receiver_id, service_type)
+		DO UPDATE SET
+			can_access = EXCLUDED.can_access,
+			updated_at = NOW()
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		perm.GrantingUserID,
+		perm.ReceiverType,
+		perm.ReceiverID,
+		perm.ServiceType,
+		perm.CanAccess,
+	).Scan(&perm.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert affiliation permission")
+	}
+
+	return nil
+}
+
+// Delete removes a grant owned by userID
+func (r *AffiliationPermissions) Delete(ctx context.Context, id int64, userID int64) error {
+	query := `
+		DELETE FROM affiliation_permissions
+		WHERE id = $1 AND granting_user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete affiliation permission")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return ErrAffiliationPermissionNotFound
+	}
+
+	return nil
+}
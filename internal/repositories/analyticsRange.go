@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Time series buckets accepted by AnalyticsRange
+const (
+	BucketHour  = "hour"
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+// MaxTimeSeriesBuckets bounds how many buckets a single time series may return
+const MaxTimeSeriesBuckets = 2000
+
+var bucketLabels = map[string]string{
+	BucketHour:  "2006-01-02T15:00",
+	BucketDay:   "2006-01-02",
+	BucketWeek:  "2006-01-02",
+	BucketMonth: "2006-01",
+}
+
+// IsValidBucket reports whether bucket is a supported time series bucket
+func IsValidBucket(bucket string) bool {
+	_, ok := bucketLabels[bucket]
+	return ok
+}
+
+// AnalyticsRange selects purchases made in [From, To); a nil bound is open. Bucket and
+// Location shape time series: purchases are grouped on hour, day, week (from Monday) or
+// month boundaries in Location, and every bucket in the range is returned even when
+// empty. ComparePrevious adds totals for the equally long period ending at From.
+type AnalyticsRange struct {
+	From            *time.Time
+	To              *time.Time
+	Bucket          string
+	Location        *time.Location
+	ComparePrevious bool
+}
+
+// LastDays returns a range over the last days days bucketed daily in EVE time, or all time for 0
+func LastDays(days int) AnalyticsRange {
+	rng := AnalyticsRange{Bucket: BucketDay, Location: time.UTC}
+	if days > 0 {
+		from := time.Now().AddDate(0, 0, -days)
+		rng.From = &from
+	}
+	return rng
+}
+
+// Validate rejects empty or inverted ranges and ranges too long for their bucket
+func (rng AnalyticsRange) Validate() error {
+	if !IsValidBucket(rng.bucket()) {
+		return errors.Errorf("invalid bucket %q, expected hour, day, week or month", rng.Bucket)
+	}
+	if rng.From != nil && !rng.From.Before(rng.end()) {
+		return errors.New("from must be before to")
+	}
+	if rng.From == nil {
+		if rng.bucket() == BucketHour {
+			return errors.New("hourly buckets require a start date")
+		}
+		return nil
+	}
+
+	_, err := rng.bucketLabels(nil)
+	return err
+}
+
+func (rng AnalyticsRange) bucket() string {
+	if rng.Bucket == "" {
+		return BucketDay
+	}
+	return rng.Bucket
+}
+
+func (rng AnalyticsRange) location() *time.Location {
+	if rng.Location == nil {
+		return time.UTC
+	}
+	return rng.Location
+}
+
+func (rng AnalyticsRange) end() time.Time {
+	if rng.To != nil {
+		return *rng.To
+	}
+	return time.Now()
+}
+
+// filters appends the range bounds on pt.purchased_at to args and returns the SQL for them
+func (rng AnalyticsRange) filters(args []interface{}) (string, []interface{}) {
+	filters := ""
+	if rng.From != nil {
+		args = append(args, rng.From.UTC())
+		filters += " AND pt.purchased_at >= $" + strconv.Itoa(len(args))
+	}
+	if rng.To != nil {
+		args = append(args, rng.To.UTC())
+		filters += " AND pt.purchased_at < $" + strconv.Itoa(len(args))
+	}
+	return filters, args
+}
+
+// bucketExpression truncates column, which holds EVE time, to the start of its bucket in
+// the range's timezone
+func (rng AnalyticsRange) bucketExpression(column string, args []interface{}) (string, []interface{}) {
+	args = append(args, rng.location().String())
+	return fmt.Sprintf("date_trunc('%s', (%s AT TIME ZONE 'UTC') AT TIME ZONE $%d)", rng.bucket(), column, len(args)), args
+}
+
+// previous returns the equally long range that ends where this one starts
+func (rng AnalyticsRange) previous() (AnalyticsRange, bool) {
+	if rng.From == nil {
+		return rng, false
+	}
+
+	end := *rng.From
+	start := end.Add(-rng.end().Sub(end))
+
+	prev := rng
+	prev.From = &start
+	prev.To = &end
+	prev.ComparePrevious = false
+	return prev, true
+}
+
+// wallClock returns t's local time in loc labelled as UTC, matching the timestamps
+// Postgres returns for AT TIME ZONE expressions
+func wallClock(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), time.UTC)
+}
+
+// truncateToBucket mirrors Postgres date_trunc on a wall clock time
+func truncateToBucket(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, time.UTC)
+	case BucketWeek:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextBucket(t time.Time, bucket string) time.Time {
+	switch bucket {
+	case BucketHour:
+		return t.Add(time.Hour)
+	case BucketWeek:
+		return t.AddDate(0, 0, 7)
+	case BucketMonth:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// bucketLabel formats a bucket start returned by bucketExpression
+func (rng AnalyticsRange) bucketLabel(start time.Time) string {
+	return truncateToBucket(start, rng.bucket()).Format(bucketLabels[rng.bucket()])
+}
+
+// bucketLabels lists every bucket from the start of the range, or from first when the range
+// is open, through its end. first is the earliest bucket that has data, or nil.
+func (rng AnalyticsRange) bucketLabels(first *time.Time) ([]string, error) {
+	bucket := rng.bucket()
+
+	var start time.Time
+	switch {
+	case rng.From != nil:
+		start = truncateToBucket(wallClock(*rng.From, rng.location()), bucket)
+	case first != nil:
+		start = truncateToBucket(*first, bucket)
+	default:
+		return []string{}, nil
+	}
+
+	// To is exclusive
+	end := wallClock(rng.end(), rng.location())
+	if rng.To != nil {
+		end = end.Add(-time.Nanosecond)
+	}
+
+	labels := []string{}
+	for current := start; !current.After(end); current = nextBucket(current, bucket) {
+		if len(labels) == MaxTimeSeriesBuckets {
+			return nil, errors.Errorf("range spans more than %d %s buckets", MaxTimeSeriesBuckets, bucket)
+		}
+		labels = append(labels, current.Format(bucketLabels[bucket]))
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"math/big"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+const (
+	appraisalCodeLength   = 10
+	appraisalCodeAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// ErrAppraisalNotFound is returned when no appraisal has the requested code
+var ErrAppraisalNotFound = errors.New("appraisal not found")
+
+type Appraisals struct {
+	db *sql.DB
+}
+
+func NewAppraisals(db *sql.DB) *Appraisals {
+	return &Appraisals{db: db}
+}
+
+// newAppraisalCode returns a random share code that is impractical to guess
+func newAppraisalCode() (string, error) {
+	code := make([]byte, appraisalCodeLength)
+	max := big.NewInt(int64(len(appraisalCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = appraisalCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// Create stores an appraisal and its items under a new share code, which is set on appraisal
+func (r *Appraisals) Create(ctx context.Context, appraisal *models.Appraisal) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	// Retry the rare code collision rather than failing the appraisal
+	inserted := false
+	for attempt := 0; attempt < 3 && !inserted; attempt++ {
+		code, err := newAppraisalCode()
+		if err != nil {
+			return errors.Wrap(err, "failed to generate appraisal code")
+		}
+
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO appraisals (code, user_id, region_id, unresolved)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (code) DO NOTHING
+			RETURNING created_at
+		`, code, appraisal.UserID, appraisal.RegionID, pq.Array(appraisal.Unresolved)).Scan(&appraisal.CreatedAt)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to insert appraisal")
+		}
+
+		appraisal.Code = code
+		inserted = true
+	}
+	if !inserted {
+		return errors.New("failed to allocate an appraisal code")
+	}
+
+	for _, item := range appraisal.Items {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO appraisal_items (appraisal_code, type_id, type_name, quantity, buy_price, sell_price, volume)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`, appraisal.Code, item.TypeID, item.TypeName, item.Quantity, item.BuyPrice, item.SellPrice, item.Volume)
+		if err != nil {
+			return errors.Wrap(err, "failed to insert appraisal item")
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit appraisal")
+	}
+
+	return nil
+}
+
+// GetByCode returns a stored appraisal with its items, most valuable first
+func (r *Appraisals) GetByCode(ctx context.Context, code string) (*models.Appraisal, error) {
+	appraisal := &models.Appraisal{
+		Code:  code,
+		Items: []*models.AppraisalItem{},
+	}
+
+	var unresolved pq.StringArray
+	err := r.db.QueryRowContext(ctx, `
+		SELECT user_id, region_id, unresolved, created_at
+		FROM appraisals
+		WHERE code = $1
+	`, code).Scan(&appraisal.UserID, &appraisal.RegionID, &unresolved, &appraisal.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrAppraisalNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get appraisal")
+	}
+	appraisal.Unresolved = []string(unresolved)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT type_id, type_name, quantity, buy_price, sell_price, volume
+		FROM appraisal_items
+		WHERE appraisal_code = $1
+		ORDER BY quantity * COALESCE(sell_price, buy_price, 0) DESC, type_name
+	`, code)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query appraisal items")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.AppraisalItem
+		err = rows.Scan(
+			&item.TypeID,
+			&item.TypeName,
+			&item.Quantity,
+			&item.BuyPrice,
+			&item.SellPrice,
+			&item.Volume,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan appraisal item")
+		}
+		appraisal.Items = append(appraisal.Items, &item)
+	}
+
+	return appraisal, nil
+}
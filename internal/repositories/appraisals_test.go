@@ -0,0 +1,63 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AppraisalsShouldCreateAndGetByCode(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	appraisalsRepo := repositories.NewAppraisals(db)
+
+	user := &repositories.User{ID: 4000, Name: "Appraiser"}
+	assert.NoError(t, userRepo.Add(ctx, user))
+
+	err = itemTypesRepo.UpsertItemTypes(ctx, []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01},
+		{TypeID: 35, TypeName: "Pyerite", Volume: 0.01},
+	})
+	assert.NoError(t, err)
+
+	types, err := itemTypesRepo.GetItemTypesByNames(ctx, []string{"tritanium", "PYERITE", "Unobtainium"})
+	assert.NoError(t, err)
+	assert.Len(t, types, 2)
+	assert.Equal(t, int64(34), types["tritanium"].TypeID)
+	assert.Equal(t, int64(35), types["pyerite"].TypeID)
+
+	buy, sell := 4.0, 6.0
+	appraisal := &models.Appraisal{
+		UserID:     user.ID,
+		RegionID:   10000002,
+		Unresolved: []string{"Unobtainium"},
+		Items: []*models.AppraisalItem{
+			{TypeID: 35, TypeName: "Pyerite", Quantity: 10, SellPrice: &sell, Volume: 0.01},
+			{TypeID: 34, TypeName: "Tritanium", Quantity: 1000, BuyPrice: &buy, SellPrice: &sell, Volume: 0.01},
+		},
+	}
+	assert.NoError(t, appraisalsRepo.Create(ctx, appraisal))
+	assert.Len(t, appraisal.Code, 10)
+	assert.False(t, appraisal.CreatedAt.IsZero())
+
+	saved, err := appraisalsRepo.GetByCode(ctx, appraisal.Code)
+	assert.NoError(t, err)
+	assert.Equal(t, user.ID, saved.UserID)
+	assert.Equal(t, []string{"Unobtainium"}, saved.Unresolved)
+	assert.Len(t, saved.Items, 2)
+
+	// Most valuable line first
+	assert.Equal(t, int64(34), saved.Items[0].TypeID)
+	assert.Equal(t, buy, *saved.Items[0].BuyPrice)
+	assert.Nil(t, saved.Items[1].BuyPrice)
+
+	_, err = appraisalsRepo.GetByCode(ctx, "missing")
+	assert.ErrorIs(t, err, repositories.ErrAppraisalNotFound)
+}
@@ -3,7 +3,10 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -72,8 +75,17 @@ func NewAssets(db *sql.DB) *Assets {
 }
 
 func (r *Assets) GetUserAssets(ctx context.Context, user int64) (*AssetsResponse, error) {
+	return r.GetFilteredUserAssets(ctx, user, nil)
+}
+
+// GetFilteredUserAssets is GetUserAssets limited to item types matching the filter. Containers,
+// hangars and structures left without matching items are dropped.
+func (r *Assets) GetFilteredUserAssets(ctx context.Context, user int64, filter *models.ItemTypeFilter) (*AssetsResponse, error) {
 	response := &AssetsResponse{}
 
+	filterSQL, filterArgs := itemTypeFilterSQL("assetTypes.type_id", filter, 2)
+	itemArgs := append([]any{user}, filterArgs...)
+
 	stationsQuery := `
 SELECT distinct
     characterAssets.location_id,
@@ -139,7 +151,7 @@ SELECT
     assetTypes.type_id,
     assetTypes.type_name,
     characterAssets.quantity,
-    assetTypes.volume * characterAssets.quantity as "volume",
+    (CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) * characterAssets.quantity as "volume",
     stockpile.desired_quantity,
     (characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
     market.sell_price as unit_price,
@@ -190,9 +202,9 @@ WHERE
         location_type='station'
         OR (location_flag='Hangar' and location_type='item')
         OR (location_flag='Deliveries' and location_type='item')
-    );`
+    )` + filterSQL + `;`
 
-	items, err := r.db.QueryContext(ctx, hangaredItemsQuery, user)
+	items, err := r.db.QueryContext(ctx, hangaredItemsQuery, itemArgs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query hangared assets from database")
 	}
@@ -292,7 +304,7 @@ SELECT
     assetTypes.type_id,
     assetTypes.type_name,
     characterAssets.quantity,
-    assetTypes.volume * characterAssets.quantity as "volume",
+    (CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) * characterAssets.quantity as "volume",
     characterAssets.location_id,
     stockpile.desired_quantity,
     (characterAssets.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
@@ -334,11 +346,11 @@ ON
 WHERE
     characterAssets.user_id=$1
     AND characterAssets.location_type='item'
-    AND NOT (characterAssets.is_singleton=true AND assetTypes.type_name like '%Container')
+    AND NOT (characterAssets.is_singleton=true AND assetTypes.type_name like '%Container')` + filterSQL + `
 ORDER BY
     characterAssets.item_id;`
 
-	itemsInContainers, err := r.db.QueryContext(ctx, itemsInContainersQuery, user)
+	itemsInContainers, err := r.db.QueryContext(ctx, itemsInContainersQuery, itemArgs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query items in containers items from database")
 	}
@@ -519,7 +531,7 @@ SELECT
 	assetTypes.type_id,
 	assetTypes.type_name,
 	corporation_assets.quantity,
-	assetTypes.volume * corporation_assets.quantity as "volume",
+	(CASE WHEN corporation_assets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) * corporation_assets.quantity as "volume",
 	stockpile.desired_quantity,
 	(corporation_assets.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
 	market.sell_price as unit_price,
@@ -558,9 +570,9 @@ WHERE
 	corporation_assets.user_id=$1
 	AND NOT (is_singleton=true AND assetTypes.type_name like '%Container')
 	AND location_type='station'
-	AND location_flag like 'CorpSAG%';`
+	AND location_flag like 'CorpSAG%'` + filterSQL + `;`
 
-	corpHangaredItems, err := r.db.QueryContext(ctx, corpHangaredItemsQuery, user)
+	corpHangaredItems, err := r.db.QueryContext(ctx, corpHangaredItemsQuery, itemArgs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query corp hangared assets from database")
 	}
@@ -717,7 +729,7 @@ SELECT
 	assetTypes.type_id,
 	assetTypes.type_name,
 	corporation_assets.quantity,
-	assetTypes.volume * corporation_assets.quantity as "volume",
+	(CASE WHEN corporation_assets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) * corporation_assets.quantity as "volume",
 	corporation_assets.location_id,
 	stockpile.desired_quantity,
 	(corporation_assets.quantity - COALESCE(stockpile.desired_quantity, 0)) as stockpile_delta,
@@ -760,11 +772,11 @@ ON
 WHERE
 	corporation_assets.user_id=$1
 	AND corporation_assets.location_type='item'
-	AND NOT (corporation_assets.is_singleton=true AND assetTypes.type_name like '%Container')
+	AND NOT (corporation_assets.is_singleton=true AND assetTypes.type_name like '%Container')` + filterSQL + `
 ORDER BY
 	corporation_assets.item_id;`
 
-	corpItemsInContainers, err := r.db.QueryContext(ctx, corpItemsInContainersQuery, user)
+	corpItemsInContainers, err := r.db.QueryContext(ctx, corpItemsInContainersQuery, itemArgs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query corp items in containers from database")
 	}
@@ -863,9 +875,46 @@ ORDER BY
 		}
 	}
 
+	if !filter.IsEmpty() {
+		pruneEmptyAssets(response)
+	}
+
 	return response, nil
 }
 
+// pruneEmptyAssets drops containers, corporation hangars and structures that hold no assets
+func pruneEmptyAssets(response *AssetsResponse) {
+	pruneContainers := func(containers []*AssetContainer) []*AssetContainer {
+		kept := []*AssetContainer{}
+		for _, container := range containers {
+			if len(container.Assets) > 0 {
+				kept = append(kept, container)
+			}
+		}
+		return kept
+	}
+
+	structures := []*AssetStructure{}
+	for _, structure := range response.Structures {
+		structure.HangarContainers = pruneContainers(structure.HangarContainers)
+
+		hangars := []*CorporationHanger{}
+		for _, hangar := range structure.CorporationHangers {
+			hangar.HangarContainers = pruneContainers(hangar.HangarContainers)
+			if len(hangar.Assets) > 0 || len(hangar.HangarContainers) > 0 {
+				hangars = append(hangars, hangar)
+			}
+		}
+		structure.CorporationHangers = hangars
+
+		if len(structure.HangarAssets) > 0 || len(structure.HangarContainers) > 0 || len(structure.Deliveries) > 0 ||
+			len(structure.AssetSafety) > 0 || len(structure.CorporationHangers) > 0 {
+			structures = append(structures, structure)
+		}
+	}
+	response.Structures = structures
+}
+
 type StockpileItem struct {
 	Name            string   `json:"name"`
 	TypeID          int64    `json:"typeId"`
@@ -880,13 +929,122 @@ type StockpileItem struct {
 	StructureName   string   `json:"structureName"`
 	SolarSystem     string   `json:"solarSystem"`
 	Region          string   `json:"region"`
+	LocationID      int64    `json:"locationId"`
 	ContainerName   *string  `json:"containerName"`
+	Scope           string   `json:"scope"`
+	MarkerID        int64    `json:"markerId"`
+	models.StockpileConsumption
 }
 
 type StockpilesResponse struct {
 	Items []*StockpileItem `json:"items"`
 }
 
+// aggregateStockpileDeficitsQuery finds solar system, region and anywhere markers whose type the
+// user holds less of, summed across every hangar, container and division in scope, than desired.
+// Stacks are limited to the marker's owner unless its owner_type is 'all'. Columns match the
+// location branches of GetStockpileDeficits, with the scope's name as the structure.
+const aggregateStockpileDeficitsQuery = `
+			SELECT
+				assetTypes.type_name as name,
+				stockpile.type_id,
+				COALESCE(SUM(held.quantity), 0)::BIGINT as quantity,
+				COALESCE(SUM(held.quantity * CASE WHEN held.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END), 0) as volume,
+				stockpile.owner_type,
+				COALESCE(characters.name, corps.name, 'All owners') as owner_name,
+				stockpile.owner_id,
+				stockpile.desired_quantity,
+				(COALESCE(SUM(held.quantity), 0) - stockpile.desired_quantity)::BIGINT as stockpile_delta,
+				(stockpile.desired_quantity - COALESCE(SUM(held.quantity), 0))::BIGINT * COALESCE(market.buy_price, 0) as deficit_value,
+				CASE stockpile.scope
+					WHEN 'solar_system' THEN COALESCE(systems.name, 'Unknown system')
+					WHEN 'region' THEN COALESCE(regions.name, 'Unknown region')
+					ELSE 'Anywhere'
+				END as structure_name,
+				COALESCE(systems.name, '') as solar_system,
+				COALESCE(regions.name, '') as region,
+				stockpile.location_id,
+				NULL::text as container_name,
+				stockpile.scope,
+				stockpile.id as marker_id
+			FROM stockpile_markers stockpile
+			INNER JOIN asset_item_types assetTypes ON assetTypes.type_id = stockpile.type_id
+			LEFT JOIN characters ON (stockpile.owner_type = 'character' AND characters.id = stockpile.owner_id)
+			LEFT JOIN player_corporations corps ON (
+				stockpile.owner_type = 'corporation'
+				AND corps.id = stockpile.owner_id
+				AND corps.user_id = stockpile.user_id
+			)
+			LEFT JOIN solar_systems systems ON (stockpile.scope = 'solar_system' AND systems.solar_system_id = stockpile.location_id)
+			LEFT JOIN constellations ON constellations.constellation_id = systems.constellation_id
+			LEFT JOIN regions ON regions.region_id = (
+				CASE WHEN stockpile.scope = 'region' THEN stockpile.location_id ELSE constellations.region_id END
+			)
+			LEFT JOIN market_prices market ON (market.type_id = stockpile.type_id AND market.region_id = 10000002)
+			LEFT JOIN (
+				-- Personal hangar items
+				SELECT characterAssets.type_id, characterAssets.quantity, characterAssets.is_singleton,
+					'character' as owner_type, characterAssets.character_id as owner_id,
+					stations.solar_system_id, constellations.region_id
+				FROM character_assets characterAssets
+				INNER JOIN stations ON stations.station_id = characterAssets.location_id
+				INNER JOIN solar_systems ON solar_systems.solar_system_id = stations.solar_system_id
+				INNER JOIN constellations ON constellations.constellation_id = solar_systems.constellation_id
+				WHERE characterAssets.user_id = $1
+					AND characterAssets.location_type = 'station'
+					AND characterAssets.location_flag IN ('Hangar', 'Deliveries', 'AssetSafety')
+
+				UNION ALL
+
+				-- Personal container items
+				SELECT characterAssets.type_id, characterAssets.quantity, characterAssets.is_singleton,
+					'character' as owner_type, characterAssets.character_id as owner_id,
+					stations.solar_system_id, constellations.region_id
+				FROM character_assets characterAssets
+				INNER JOIN asset_item_types itemTypes ON itemTypes.type_id = characterAssets.type_id
+				INNER JOIN character_assets containers ON containers.item_id = characterAssets.location_id
+				INNER JOIN stations ON stations.station_id = containers.location_id
+				INNER JOIN solar_systems ON solar_systems.solar_system_id = stations.solar_system_id
+				INNER JOIN constellations ON constellations.constellation_id = solar_systems.constellation_id
+				WHERE characterAssets.user_id = $1
+					AND characterAssets.location_type = 'item'
+					AND NOT (characterAssets.is_singleton = true AND itemTypes.type_name LIKE '%Container')
+
+				UNION ALL
+
+				-- Corporation hangar and container items
+				SELECT loc.type_id, ca.quantity, ca.is_singleton,
+					'corporation' as owner_type, loc.corporation_id as owner_id,
+					loc.solar_system_id, loc.region_id
+				FROM corporation_asset_locations loc
+				INNER JOIN corporation_assets ca ON (
+					ca.item_id = loc.item_id
+					AND ca.corporation_id = loc.corporation_id
+					AND ca.user_id = loc.user_id
+				)
+				INNER JOIN asset_item_types itemTypes ON itemTypes.type_id = loc.type_id
+				WHERE loc.user_id = $1
+					AND loc.station_id IS NOT NULL
+					AND (
+						(loc.location_type = 'station' AND loc.location_flag LIKE 'CorpSAG%')
+						OR (loc.location_type = 'item' AND loc.container_location_flag LIKE 'CorpSAG%')
+					)
+					AND NOT (ca.is_singleton = true AND itemTypes.type_name LIKE '%Container')
+			) held ON (
+				held.type_id = stockpile.type_id
+				AND (stockpile.owner_type = 'all' OR (held.owner_type = stockpile.owner_type AND held.owner_id = stockpile.owner_id))
+				AND (
+					stockpile.scope = 'anywhere'
+					OR (stockpile.scope = 'solar_system' AND held.solar_system_id = stockpile.location_id)
+					OR (stockpile.scope = 'region' AND held.region_id = stockpile.location_id)
+				)
+			)
+			WHERE stockpile.user_id = $1
+				AND stockpile.scope <> 'location'
+			GROUP BY stockpile.id, assetTypes.type_name, assetTypes.volume, characters.name, corps.name,
+				systems.name, regions.name, market.buy_price
+			HAVING COALESCE(SUM(held.quantity), 0) < stockpile.desired_quantity`
+
 func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*StockpilesResponse, error) {
 	response := &StockpilesResponse{
 		Items: []*StockpileItem{},
@@ -901,7 +1059,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				characterAssets.type_id,
 				characterAssets.quantity,
-				(characterAssets.quantity * assetTypes.volume) as volume,
+				(characterAssets.quantity * CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'character' as owner_type,
 				characters.name as owner_name,
 				characters.id as owner_id,
@@ -911,7 +1069,10 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				stations.name as structure_name,
 				systems.name as solar_system,
 				regions.name as region,
-				NULL::text as container_name
+				stations.station_id as location_id,
+				NULL::text as container_name,
+				'location' as scope,
+				stockpile.id as marker_id
 			FROM character_assets characterAssets
 			INNER JOIN characters ON characters.id = characterAssets.character_id
 			INNER JOIN asset_item_types assetTypes ON assetTypes.type_id = characterAssets.type_id
@@ -920,7 +1081,8 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			INNER JOIN constellations ON systems.constellation_id = constellations.constellation_id
 			INNER JOIN regions ON constellations.region_id = regions.region_id
 			LEFT JOIN stockpile_markers stockpile ON (
-				stockpile.type_id = characterAssets.type_id
+				stockpile.scope = 'location'
+				AND stockpile.type_id = characterAssets.type_id
 				AND stockpile.location_id = characterAssets.location_id
 				AND stockpile.container_id IS NULL
 				AND stockpile.owner_id = characterAssets.character_id
@@ -938,7 +1100,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				characterAssets.type_id,
 				characterAssets.quantity,
-				(characterAssets.quantity * assetTypes.volume) as volume,
+				(characterAssets.quantity * CASE WHEN characterAssets.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'character' as owner_type,
 				characters.name as owner_name,
 				characters.id as owner_id,
@@ -948,7 +1110,10 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				stations.name as structure_name,
 				systems.name as solar_system,
 				regions.name as region,
-				containerTypes.type_name as container_name
+				stations.station_id as location_id,
+				containerTypes.type_name as container_name,
+				'location' as scope,
+				stockpile.id as marker_id
 			FROM character_assets characterAssets
 			INNER JOIN characters ON characters.id = characterAssets.character_id
 			INNER JOIN asset_item_types assetTypes ON assetTypes.type_id = characterAssets.type_id
@@ -959,7 +1124,8 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			INNER JOIN constellations ON systems.constellation_id = constellations.constellation_id
 			INNER JOIN regions ON constellations.region_id = regions.region_id
 			LEFT JOIN stockpile_markers stockpile ON (
-				stockpile.type_id = characterAssets.type_id
+				stockpile.scope = 'location'
+				AND stockpile.type_id = characterAssets.type_id
 				AND stockpile.container_id = characterAssets.location_id
 				AND stockpile.owner_id = characterAssets.character_id
 			)
@@ -976,7 +1142,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				loc.type_id,
 				ca.quantity,
-				(ca.quantity * assetTypes.volume) as volume,
+				(ca.quantity * CASE WHEN ca.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'corporation' as owner_type,
 				corps.name as owner_name,
 				corps.id as owner_id,
@@ -986,7 +1152,10 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				loc.station_name as structure_name,
 				loc.solar_system_name as solar_system,
 				loc.region_name as region,
-				COALESCE(divisions.name, loc.location_flag) as container_name
+				loc.station_id as location_id,
+				COALESCE(divisions.name, loc.location_flag) as container_name,
+				'location' as scope,
+				stockpile.id as marker_id
 			FROM corporation_asset_locations loc
 			INNER JOIN corporation_assets ca ON (
 				ca.item_id = loc.item_id
@@ -1002,7 +1171,8 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				AND divisions.division_type = 'hangar'
 			)
 			LEFT JOIN stockpile_markers stockpile ON (
-				stockpile.type_id = loc.type_id
+				stockpile.scope = 'location'
+				AND stockpile.type_id = loc.type_id
 				AND stockpile.location_id = loc.location_id
 				AND stockpile.division_number = loc.division_number
 				AND stockpile.container_id IS NULL
@@ -1022,7 +1192,7 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				assetTypes.type_name as name,
 				loc.type_id,
 				ca.quantity,
-				(ca.quantity * assetTypes.volume) as volume,
+				(ca.quantity * CASE WHEN ca.is_singleton THEN assetTypes.volume ELSE COALESCE(assetTypes.packaged_volume, assetTypes.volume) END) as volume,
 				'corporation' as owner_type,
 				corps.name as owner_name,
 				corps.id as owner_id,
@@ -1032,7 +1202,10 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				loc.station_name as structure_name,
 				loc.solar_system_name as solar_system,
 				loc.region_name as region,
-				COALESCE(divisions.name, loc.container_location_flag) || ' - ' || containerTypes.type_name as container_name
+				loc.station_id as location_id,
+				COALESCE(divisions.name, loc.container_location_flag) || ' - ' || containerTypes.type_name as container_name,
+				'location' as scope,
+				stockpile.id as marker_id
 			FROM corporation_asset_locations loc
 			INNER JOIN corporation_assets ca ON (
 				ca.item_id = loc.item_id
@@ -1049,7 +1222,8 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				AND divisions.division_type = 'hangar'
 			)
 			LEFT JOIN stockpile_markers stockpile ON (
-				stockpile.type_id = loc.type_id
+				stockpile.scope = 'location'
+				AND stockpile.type_id = loc.type_id
 				AND stockpile.division_number = loc.division_number
 				AND stockpile.container_id = loc.container_id
 				AND stockpile.owner_id = loc.corporation_id
@@ -1061,6 +1235,11 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 				AND loc.station_id IS NOT NULL
 				AND NOT (ca.is_singleton = true AND assetTypes.type_name LIKE '%Container')
 				AND (ca.quantity - COALESCE(stockpile.desired_quantity, 0)) < 0
+
+			UNION ALL
+
+			-- Solar system, region and anywhere markers, compared to the sum of every matching stack
+			` + aggregateStockpileDeficitsQuery + `
 		)
 		SELECT * FROM all_deficits
 		ORDER BY deficit_value DESC NULLS LAST, structure_name, name
@@ -1088,7 +1267,10 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 			&item.StructureName,
 			&item.SolarSystem,
 			&item.Region,
+			&item.LocationID,
 			&item.ContainerName,
+			&item.Scope,
+			&item.MarkerID,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan stockpile item")
@@ -1104,6 +1286,223 @@ func (r *Assets) GetStockpileDeficits(ctx context.Context, user int64) (*Stockpi
 	return response, nil
 }
 
+// stockpileHeldStacks is a "held" CTE of every stack of typeIDs the user ($1) holds in a station
+// hangar, container or corporation division, keyed the way location stockpile markers are:
+// station, container and division. Assembled containers themselves are left out.
+func stockpileHeldStacks(typeIDs string) string {
+	return fmt.Sprintf(`held AS (
+			SELECT stacks.*, stations.solar_system_id, constellations.region_id
+			FROM (
+				-- Personal hangar items
+				SELECT characterAssets.type_id, characterAssets.quantity, characterAssets.is_singleton,
+					'character' as owner_type, characterAssets.character_id as owner_id,
+					characterAssets.location_id as station_id,
+					NULL::BIGINT as container_id, NULL::INT as division_number,
+					NULL::text as container_name
+				FROM character_assets characterAssets
+				WHERE characterAssets.user_id = $1
+					AND characterAssets.type_id = ANY(%[1]s)
+					AND characterAssets.location_type = 'station'
+					AND characterAssets.location_flag IN ('Hangar', 'Deliveries', 'AssetSafety')
+
+				UNION ALL
+
+				-- Personal container items
+				SELECT characterAssets.type_id, characterAssets.quantity, characterAssets.is_singleton,
+					'character' as owner_type, characterAssets.character_id as owner_id,
+					containers.location_id as station_id,
+					characterAssets.location_id as container_id, NULL::INT as division_number,
+					containerTypes.type_name as container_name
+				FROM character_assets characterAssets
+				INNER JOIN asset_item_types itemTypes ON itemTypes.type_id = characterAssets.type_id
+				INNER JOIN character_assets containers ON containers.item_id = characterAssets.location_id
+				INNER JOIN asset_item_types containerTypes ON containerTypes.type_id = containers.type_id
+				WHERE characterAssets.user_id = $1
+					AND characterAssets.type_id = ANY(%[1]s)
+					AND characterAssets.location_type = 'item'
+					AND NOT (characterAssets.is_singleton = true AND itemTypes.type_name LIKE '%%Container')
+
+				UNION ALL
+
+				-- Corporation hangar items
+				SELECT loc.type_id, ca.quantity, ca.is_singleton,
+					'corporation' as owner_type, loc.corporation_id as owner_id,
+					loc.station_id,
+					NULL::BIGINT as container_id, loc.division_number,
+					COALESCE(divisions.name, loc.location_flag) as container_name
+				FROM corporation_asset_locations loc
+				INNER JOIN corporation_assets ca ON (
+					ca.item_id = loc.item_id
+					AND ca.corporation_id = loc.corporation_id
+					AND ca.user_id = loc.user_id
+				)
+				LEFT JOIN corporation_divisions divisions ON (
+					divisions.division_number = loc.division_number
+					AND divisions.corporation_id = loc.corporation_id
+					AND divisions.user_id = loc.user_id
+					AND divisions.division_type = 'hangar'
+				)
+				WHERE loc.user_id = $1
+					AND loc.type_id = ANY(%[1]s)
+					AND loc.location_type = 'station'
+					AND loc.location_flag LIKE 'CorpSAG%%'
+					AND loc.station_id IS NOT NULL
+
+				UNION ALL
+
+				-- Corporation container items
+				SELECT loc.type_id, ca.quantity, ca.is_singleton,
+					'corporation' as owner_type, loc.corporation_id as owner_id,
+					loc.station_id,
+					loc.container_id, loc.division_number,
+					COALESCE(divisions.name, loc.container_location_flag) || ' - ' || containerTypes.type_name as container_name
+				FROM corporation_asset_locations loc
+				INNER JOIN corporation_assets ca ON (
+					ca.item_id = loc.item_id
+					AND ca.corporation_id = loc.corporation_id
+					AND ca.user_id = loc.user_id
+				)
+				INNER JOIN asset_item_types itemTypes ON itemTypes.type_id = loc.type_id
+				INNER JOIN asset_item_types containerTypes ON containerTypes.type_id = loc.container_type_id
+				LEFT JOIN corporation_divisions divisions ON (
+					divisions.division_number = loc.division_number
+					AND divisions.corporation_id = loc.corporation_id
+					AND divisions.user_id = loc.user_id
+					AND divisions.division_type = 'hangar'
+				)
+				WHERE loc.user_id = $1
+					AND loc.type_id = ANY(%[1]s)
+					AND loc.location_type = 'item'
+					AND loc.container_location_flag LIKE 'CorpSAG%%'
+					AND loc.station_id IS NOT NULL
+					AND NOT (ca.is_singleton = true AND itemTypes.type_name LIKE '%%Container')
+			) stacks
+			INNER JOIN stations ON stations.station_id = stacks.station_id
+			INNER JOIN solar_systems ON solar_systems.solar_system_id = stations.solar_system_id
+			INNER JOIN constellations ON constellations.constellation_id = solar_systems.constellation_id
+		)`, typeIDs)
+}
+
+// StockpileSurplus is stock of one type in one hangar, container or corporation division beyond
+// what a location stockpile marker there wants kept. Quantity is what is held, Reserved the
+// marker's desired quantity (0 without one) and Surplus the difference.
+type StockpileSurplus struct {
+	TypeID        int64   `json:"typeId"`
+	Name          string  `json:"name"`
+	UnitVolume    float64 `json:"unitVolume"`
+	OwnerType     string  `json:"ownerType"`
+	OwnerID       int64   `json:"ownerId"`
+	OwnerName     string  `json:"ownerName"`
+	LocationID    int64   `json:"locationId"`
+	StructureName string  `json:"structureName"`
+	SolarSystemID int64   `json:"solarSystemId"`
+	SolarSystem   string  `json:"solarSystem"`
+	RegionID      int64   `json:"regionId"`
+	Region        string  `json:"region"`
+	ContainerName *string `json:"containerName"`
+	Quantity      int64   `json:"quantity"`
+	Reserved      int64   `json:"reserved"`
+	Surplus       int64   `json:"surplus"`
+}
+
+// GetStockpileSurpluses returns every hangar, container and corporation division holding more of
+// one of typeIDs than its location stockpile marker wants, largest surplus first within a type.
+// Aggregate markers do not reserve stock, since they do not pin it to any one place.
+func (r *Assets) GetStockpileSurpluses(ctx context.Context, user int64, typeIDs []int64) ([]*StockpileSurplus, error) {
+	surpluses := []*StockpileSurplus{}
+	if len(typeIDs) == 0 {
+		return surpluses, nil
+	}
+
+	query := `
+		WITH ` + stockpileHeldStacks("$2") + `
+		SELECT
+			held.type_id,
+			assetTypes.type_name,
+			COALESCE(assetTypes.packaged_volume, assetTypes.volume),
+			held.owner_type,
+			held.owner_id,
+			COALESCE(characters.name, corps.name, '') as owner_name,
+			held.station_id,
+			stations.name,
+			systems.solar_system_id,
+			systems.name,
+			regions.region_id,
+			regions.name,
+			held.container_name,
+			SUM(held.quantity)::BIGINT as quantity,
+			COALESCE(stockpile.desired_quantity, 0) as reserved
+		FROM held
+		INNER JOIN asset_item_types assetTypes ON assetTypes.type_id = held.type_id
+		INNER JOIN stations ON stations.station_id = held.station_id
+		INNER JOIN solar_systems systems ON systems.solar_system_id = held.solar_system_id
+		INNER JOIN regions ON regions.region_id = held.region_id
+		LEFT JOIN characters ON (held.owner_type = 'character' AND characters.id = held.owner_id)
+		LEFT JOIN player_corporations corps ON (
+			held.owner_type = 'corporation'
+			AND corps.id = held.owner_id
+			AND corps.user_id = $1
+		)
+		LEFT JOIN stockpile_markers stockpile ON (
+			stockpile.user_id = $1
+			AND stockpile.scope = 'location'
+			AND stockpile.type_id = held.type_id
+			AND stockpile.owner_type = held.owner_type
+			AND stockpile.owner_id = held.owner_id
+			AND stockpile.division_number IS NOT DISTINCT FROM held.division_number
+			AND (
+				(held.container_id IS NOT NULL AND stockpile.container_id = held.container_id)
+				OR (held.container_id IS NULL AND stockpile.container_id IS NULL AND stockpile.location_id = held.station_id)
+			)
+		)
+		GROUP BY held.type_id, assetTypes.type_name, assetTypes.volume, assetTypes.packaged_volume, held.owner_type, held.owner_id,
+			characters.name, corps.name, held.station_id, stations.name, systems.solar_system_id, systems.name,
+			regions.region_id, regions.name, held.container_id, held.division_number, held.container_name,
+			stockpile.desired_quantity
+		HAVING SUM(held.quantity) > COALESCE(stockpile.desired_quantity, 0)
+		ORDER BY held.type_id, SUM(held.quantity) - COALESCE(stockpile.desired_quantity, 0) DESC, held.station_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, user, pq.Array(typeIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile surpluses")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := &StockpileSurplus{}
+		err = rows.Scan(
+			&item.TypeID,
+			&item.Name,
+			&item.UnitVolume,
+			&item.OwnerType,
+			&item.OwnerID,
+			&item.OwnerName,
+			&item.LocationID,
+			&item.StructureName,
+			&item.SolarSystemID,
+			&item.SolarSystem,
+			&item.RegionID,
+			&item.Region,
+			&item.ContainerName,
+			&item.Quantity,
+			&item.Reserved,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile surplus")
+		}
+
+		item.Surplus = item.Quantity - item.Reserved
+		surpluses = append(surpluses, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating stockpile surplus rows")
+	}
+
+	return surpluses, nil
+}
+
 func (r *Assets) GetUserAssetsSummary(ctx context.Context, user int64) (*AssetsSummary, error) {
 	query := `
 	SELECT
@@ -1241,6 +1640,15 @@ func (r *Assets) GetUserAssetsSummary(ctx context.Context, user int64) (*AssetsS
 			containerAssets.user_id = $1
 			AND containerAssets.location_type = 'item'
 			AND containerItem.location_flag LIKE 'CorpSAG%'
+
+		UNION ALL
+
+		-- Solar system, region and anywhere markers
+		SELECT
+			0 as total_value,
+			aggregate_deficits.deficit_value
+		FROM (` + aggregateStockpileDeficitsQuery + `
+		) aggregate_deficits
 	) all_assets
 	`
 
@@ -1681,3 +1681,54 @@ func Test_AssetsShouldReturnZeroSummaryForNoAssets(t *testing.T) {
 func ptrFloat64(v float64) *float64 {
 	return &v
 }
+
+func Test_AssetsShouldFilterByItemMetadata(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+	setupItemMetadata(t, repositories.NewItemMetadata(db), repositories.NewItemTypeRepository(db))
+
+	userRepository := repositories.NewUserRepository(db)
+	characterRepository := repositories.NewCharacterRepository(db)
+	characterAssetsRepository := repositories.NewCharacterAssets(db)
+	assetsRepository := repositories.NewAssets(db)
+
+	testUser := &repositories.User{ID: 42, Name: "Ibn Kabab"}
+	err = userRepository.Add(context.Background(), testUser)
+	assert.NoError(t, err)
+
+	testCharacter := &repositories.Character{ID: 1337, Name: "Crushim deez nuts", UserID: 42}
+	err = characterRepository.Add(context.Background(), testCharacter)
+	assert.NoError(t, err)
+
+	characterAssets := []*models.EveAsset{
+		{ItemID: 1001, LocationID: 60003760, LocationType: "station", Quantity: 100, TypeID: 34, LocationFlag: "Hangar"},
+		{ItemID: 1002, LocationID: 60003760, LocationType: "station", Quantity: 2, TypeID: 2048, LocationFlag: "Hangar"},
+		{ItemID: 1003, LocationID: 60003761, LocationType: "station", Quantity: 1, TypeID: 2046, LocationFlag: "Hangar"},
+	}
+	err = characterAssetsRepository.UpdateAssets(context.Background(), testCharacter.ID, testUser.ID, characterAssets)
+	assert.NoError(t, err)
+
+	response, err := assetsRepository.GetFilteredUserAssets(context.Background(), testUser.ID, &models.ItemTypeFilter{CategoryIDs: []int64{4}})
+	assert.NoError(t, err)
+
+	// Only the station holding minerals is left
+	assert.Len(t, response.Structures, 1)
+	assert.Equal(t, int64(60003760), response.Structures[0].ID)
+	assert.Len(t, response.Structures[0].HangarAssets, 1)
+	assert.Equal(t, int64(34), response.Structures[0].HangarAssets[0].TypeID)
+
+	response, err = assetsRepository.GetFilteredUserAssets(context.Background(), testUser.ID, &models.ItemTypeFilter{MarketGroupIDs: []int64{9}})
+	assert.NoError(t, err)
+	assert.Len(t, response.Structures, 2)
+
+	unfiltered, err := assetsRepository.GetUserAssets(context.Background(), testUser.ID)
+	assert.NoError(t, err)
+	assert.Len(t, unfiltered.Structures, 2)
+	hangarAssets := 0
+	for _, structure := range unfiltered.Structures {
+		hangarAssets += len(structure.HangarAssets)
+	}
+	assert.Equal(t, 3, hangarAssets)
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrBuildRequestNotFound is returned when a build request does not exist
+	ErrBuildRequestNotFound = errors.New("build request not found")
+
+	// ErrBuildRequestStatusChanged is returned when a build request left the expected status before it could be updated
+	ErrBuildRequestStatusChanged = errors.New("build request status changed")
+)
+
+type BuildRequests struct {
+	db *sql.DB
+}
+
+func NewBuildRequests(db *sql.DB) *BuildRequests {
+	return &BuildRequests{db: db}
+}
+
+const buildRequestColumns = `
+			br.id,
+			br.requester_user_id,
+			br.builder_user_id,
+			br.type_id,
+			COALESCE(it.type_name, ''),
+			br.quantity,
+			br.notes,
+			br.status,
+			br.created_at,
+			br.updated_at
+`
+
+func scanBuildRequest(scanner interface{ Scan(...any) error }) (*models.BuildRequest, error) {
+	request := &models.BuildRequest{}
+	err := scanner.Scan(
+		&request.ID,
+		&request.RequesterUserID,
+		&request.BuilderUserID,
+		&request.TypeID,
+		&request.TypeName,
+		&request.Quantity,
+		&request.Notes,
+		&request.Status,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	return request, err
+}
+
+// Create stores a new pending build request
+func (r *BuildRequests) Create(ctx context.Context, request *models.BuildRequest) error {
+	query := `
+		INSERT INTO build_requests (requester_user_id, builder_user_id, type_id, quantity, notes, status)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		request.RequesterUserID,
+		request.BuilderUserID,
+		request.TypeID,
+		request.Quantity,
+		request.Notes,
+		models.ServiceRequestPending,
+	).Scan(&request.ID, &request.Status, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create build request")
+	}
+
+	return nil
+}
+
+// GetByID returns a build request with its type name
+func (r *BuildRequests) GetByID(ctx context.Context, id int64) (*models.BuildRequest, error) {
+	query := `
+		SELECT` + buildRequestColumns + `
+		FROM build_requests br
+		LEFT JOIN asset_item_types it ON it.type_id = br.type_id
+		WHERE br.id = $1
+	`
+
+	request, err := scanBuildRequest(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrBuildRequestNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get build request")
+	}
+
+	return request, nil
+}
+
+// GetByUser returns the build requests a user sent or received, newest first
+func (r *BuildRequests) GetByUser(ctx context.Context, userID int64) ([]*models.BuildRequest, error) {
+	query := `
+		SELECT` + buildRequestColumns + `
+		FROM build_requests br
+		LEFT JOIN asset_item_types it ON it.type_id = br.type_id
+		WHERE br.requester_user_id = $1 OR br.builder_user_id = $1
+		ORDER BY br.created_at DESC, br.id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query build requests")
+	}
+	defer rows.Close()
+
+	requests := []*models.BuildRequest{}
+	for rows.Next() {
+		request, err := scanBuildRequest(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan build request")
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// UpdateStatus moves a build request from one status to another. It returns
+// ErrBuildRequestStatusChanged if the request is no longer in fromStatus.
+func (r *BuildRequests) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	query := `
+		UPDATE build_requests
+		SET status = $3, updated_at = NOW()
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, fromStatus, toStatus)
+	if err != nil {
+		return errors.Wrap(err, "failed to update build request status")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrBuildRequestStatusChanged
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_BuildRequestsShouldCreateListAndUpdate(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+	repo := repositories.NewBuildRequests(db)
+
+	requester := &repositories.User{ID: 3200, Name: "Requester"}
+	builder := &repositories.User{ID: 3201, Name: "Builder"}
+	outsider := &repositories.User{ID: 3202, Name: "Outsider"}
+	for _, u := range []*repositories.User{requester, builder, outsider} {
+		assert.NoError(t, userRepo.Add(ctx, u))
+	}
+
+	err = itemTypesRepo.UpsertItemTypes(ctx, []models.EveInventoryType{
+		{TypeID: 587, TypeName: "Rifter", Volume: 27289},
+	})
+	assert.NoError(t, err)
+
+	notes := "T1 fit please"
+	request := &models.BuildRequest{
+		RequesterUserID: requester.ID,
+		BuilderUserID:   builder.ID,
+		TypeID:          587,
+		Quantity:        5,
+		Notes:           &notes,
+	}
+	err = repo.Create(ctx, request)
+	assert.NoError(t, err)
+	assert.NotZero(t, request.ID)
+	assert.Equal(t, models.ServiceRequestPending, request.Status)
+
+	retrieved, err := repo.GetByID(ctx, request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Rifter", retrieved.TypeName)
+	assert.Equal(t, int64(5), retrieved.Quantity)
+	assert.Equal(t, &notes, retrieved.Notes)
+
+	// Both sides see the request
+	for _, userID := range []int64{requester.ID, builder.ID} {
+		requests, err := repo.GetByUser(ctx, userID)
+		assert.NoError(t, err)
+		assert.Len(t, requests, 1)
+	}
+	requests, err := repo.GetByUser(ctx, outsider.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, requests)
+
+	err = repo.UpdateStatus(ctx, request.ID, models.ServiceRequestPending, models.ServiceRequestAccepted)
+	assert.NoError(t, err)
+
+	// A stale status no longer matches
+	err = repo.UpdateStatus(ctx, request.ID, models.ServiceRequestPending, models.ServiceRequestRejected)
+	assert.ErrorIs(t, err, repositories.ErrBuildRequestStatusChanged)
+
+	retrieved, err = repo.GetByID(ctx, request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ServiceRequestAccepted, retrieved.Status)
+
+	_, err = repo.GetByID(ctx, request.ID+1000)
+	assert.ErrorIs(t, err, repositories.ErrBuildRequestNotFound)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -16,19 +17,34 @@ func NewBuyOrders(db *sql.DB) *BuyOrders {
 	return &BuyOrders{db: db}
 }
 
-// Create creates a new buy order
+// Create creates a new buy order. MaxPricePerUnit is stored as the fixed max price
+// and is updated to the pricing rule's current price on return when one is set.
+// Orders with a StockpileMarkerID are marked as managed by the stockpile sync.
 func (r *BuyOrders) Create(ctx context.Context, order *models.BuyOrder) error {
 	query := `
-		INSERT INTO buy_orders (
-			buyer_user_id,
-			type_id,
-			quantity_desired,
-			max_price_per_unit,
-			notes,
-			is_active
-		) VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, created_at, updated_at
-	`
+		WITH bo AS (
+			INSERT INTO buy_orders (
+				buyer_user_id,
+				type_id,
+				quantity_desired,
+				max_price_per_unit,
+				notes,
+				is_active,
+				pricing_basis,
+				pricing_percent,
+				pricing_floor,
+				pricing_ceiling,
+				stockpile_marker_id,
+				auto_managed
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			RETURNING *
+		)
+		SELECT bo.id, bo.created_at, bo.updated_at, ` + pricingRulePrice("bo", "max_price_per_unit") + `
+		FROM bo
+	` + pricingRuleJoins("bo")
+
+	basis, percent, floor, ceiling := pricingRuleArgs(order.PricingRule)
+	order.FixedMaxPricePerUnit = order.MaxPricePerUnit
 
 	err := r.db.QueryRowContext(
 		ctx,
@@ -39,7 +55,13 @@ func (r *BuyOrders) Create(ctx context.Context, order *models.BuyOrder) error {
 		order.MaxPricePerUnit,
 		order.Notes,
 		order.IsActive,
-	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt)
+		basis,
+		percent,
+		floor,
+		ceiling,
+		order.StockpileMarkerID,
+		order.StockpileMarkerID != nil,
+	).Scan(&order.ID, &order.CreatedAt, &order.UpdatedAt, &order.MaxPricePerUnit)
 
 	if err != nil {
 		return errors.Wrap(err, "failed to create buy order")
@@ -57,17 +79,25 @@ func (r *BuyOrders) GetByID(ctx context.Context, id int64) (*models.BuyOrder, er
 			bo.type_id,
 			it.type_name,
 			bo.quantity_desired,
+			` + pricingRulePrice("bo", "max_price_per_unit") + ` AS effective_max_price_per_unit,
 			bo.max_price_per_unit,
+			bo.pricing_basis,
+			bo.pricing_percent,
+			bo.pricing_floor,
+			bo.pricing_ceiling,
 			bo.notes,
 			bo.is_active,
 			bo.created_at,
-			bo.updated_at
+			bo.updated_at,
+			bo.stockpile_marker_id
 		FROM buy_orders bo
 		LEFT JOIN asset_item_types it ON bo.type_id = it.type_id
+	` + pricingRuleJoins("bo") + `
 		WHERE bo.id = $1
 	`
 
 	order := &models.BuyOrder{}
+	var rule pricingRuleColumns
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&order.ID,
 		&order.BuyerUserID,
@@ -75,10 +105,16 @@ func (r *BuyOrders) GetByID(ctx context.Context, id int64) (*models.BuyOrder, er
 		&order.TypeName,
 		&order.QuantityDesired,
 		&order.MaxPricePerUnit,
+		&order.FixedMaxPricePerUnit,
+		&rule.basis,
+		&rule.percent,
+		&rule.floor,
+		&rule.ceiling,
 		&order.Notes,
 		&order.IsActive,
 		&order.CreatedAt,
 		&order.UpdatedAt,
+		&order.StockpileMarkerID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -87,6 +123,7 @@ func (r *BuyOrders) GetByID(ctx context.Context, id int64) (*models.BuyOrder, er
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get buy order")
 	}
+	order.PricingRule = rule.rule()
 
 	return order, nil
 }
@@ -100,13 +137,20 @@ func (r *BuyOrders) GetByUser(ctx context.Context, userID int64) ([]*models.BuyO
 			bo.type_id,
 			it.type_name,
 			bo.quantity_desired,
+			` + pricingRulePrice("bo", "max_price_per_unit") + ` AS effective_max_price_per_unit,
 			bo.max_price_per_unit,
+			bo.pricing_basis,
+			bo.pricing_percent,
+			bo.pricing_floor,
+			bo.pricing_ceiling,
 			bo.notes,
 			bo.is_active,
 			bo.created_at,
-			bo.updated_at
+			bo.updated_at,
+			bo.stockpile_marker_id
 		FROM buy_orders bo
 		LEFT JOIN asset_item_types it ON bo.type_id = it.type_id
+	` + pricingRuleJoins("bo") + `
 		WHERE bo.buyer_user_id = $1
 		ORDER BY bo.created_at DESC
 	`
@@ -120,6 +164,7 @@ func (r *BuyOrders) GetByUser(ctx context.Context, userID int64) ([]*models.BuyO
 	orders := []*models.BuyOrder{}
 	for rows.Next() {
 		order := &models.BuyOrder{}
+		var rule pricingRuleColumns
 		err := rows.Scan(
 			&order.ID,
 			&order.BuyerUserID,
@@ -127,45 +172,60 @@ func (r *BuyOrders) GetByUser(ctx context.Context, userID int64) ([]*models.BuyO
 			&order.TypeName,
 			&order.QuantityDesired,
 			&order.MaxPricePerUnit,
+			&order.FixedMaxPricePerUnit,
+			&rule.basis,
+			&rule.percent,
+			&rule.floor,
+			&rule.ceiling,
 			&order.Notes,
 			&order.IsActive,
 			&order.CreatedAt,
 			&order.UpdatedAt,
+			&order.StockpileMarkerID,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan buy order")
 		}
+		order.PricingRule = rule.rule()
 		orders = append(orders, order)
 	}
 
 	return orders, nil
 }
 
-// GetDemandForSeller returns active buy orders from users who have granted seller the for_sale_browse permission
-func (r *BuyOrders) GetDemandForSeller(ctx context.Context, sellerUserID int64) ([]*models.BuyOrder, error) {
+// GetActiveByBuyers returns active buy orders placed by any of the given buyers
+func (r *BuyOrders) GetActiveByBuyers(ctx context.Context, buyerUserIDs []int64) ([]*models.BuyOrder, error) {
+	if len(buyerUserIDs) == 0 {
+		return []*models.BuyOrder{}, nil
+	}
+
 	query := `
-		SELECT DISTINCT
+		SELECT
 			bo.id,
 			bo.buyer_user_id,
 			bo.type_id,
 			it.type_name,
 			bo.quantity_desired,
+			` + pricingRulePrice("bo", "max_price_per_unit") + ` AS effective_max_price_per_unit,
 			bo.max_price_per_unit,
+			bo.pricing_basis,
+			bo.pricing_percent,
+			bo.pricing_floor,
+			bo.pricing_ceiling,
 			bo.notes,
 			bo.is_active,
 			bo.created_at,
-			bo.updated_at
+			bo.updated_at,
+			bo.stockpile_marker_id
 		FROM buy_orders bo
 		LEFT JOIN asset_item_types it ON bo.type_id = it.type_id
-		INNER JOIN contact_permissions cp ON cp.granting_user_id = bo.buyer_user_id
-			AND cp.receiving_user_id = $1
-			AND cp.service_type = 'for_sale_browse'
-			AND cp.can_access = true
-		WHERE bo.is_active = true
+	` + pricingRuleJoins("bo") + `
+		WHERE bo.buyer_user_id = ANY($1) AND bo.is_active = true
+			AND ` + pricingRulePriced("bo", "max_price_per_unit") + `
 		ORDER BY bo.created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, sellerUserID)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(buyerUserIDs))
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to query demand")
 	}
@@ -174,6 +234,7 @@ func (r *BuyOrders) GetDemandForSeller(ctx context.Context, sellerUserID int64)
 	orders := []*models.BuyOrder{}
 	for rows.Next() {
 		order := &models.BuyOrder{}
+		var rule pricingRuleColumns
 		err := rows.Scan(
 			&order.ID,
 			&order.BuyerUserID,
@@ -181,33 +242,51 @@ func (r *BuyOrders) GetDemandForSeller(ctx context.Context, sellerUserID int64)
 			&order.TypeName,
 			&order.QuantityDesired,
 			&order.MaxPricePerUnit,
+			&order.FixedMaxPricePerUnit,
+			&rule.basis,
+			&rule.percent,
+			&rule.floor,
+			&rule.ceiling,
 			&order.Notes,
 			&order.IsActive,
 			&order.CreatedAt,
 			&order.UpdatedAt,
+			&order.StockpileMarkerID,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan buy order")
 		}
+		order.PricingRule = rule.rule()
 		orders = append(orders, order)
 	}
 
 	return orders, nil
 }
 
-// Update updates a buy order
+// Update updates a buy order, replacing its pricing rule
 func (r *BuyOrders) Update(ctx context.Context, order *models.BuyOrder) error {
 	query := `
-		UPDATE buy_orders
-		SET
-			quantity_desired = $2,
-			max_price_per_unit = $3,
-			notes = $4,
-			is_active = $5,
-			updated_at = NOW()
-		WHERE id = $1
-		RETURNING updated_at
-	`
+		WITH bo AS (
+			UPDATE buy_orders
+			SET
+				quantity_desired = $2,
+				max_price_per_unit = $3,
+				notes = $4,
+				is_active = $5,
+				pricing_basis = $6,
+				pricing_percent = $7,
+				pricing_floor = $8,
+				pricing_ceiling = $9,
+				updated_at = NOW()
+			WHERE id = $1
+			RETURNING *
+		)
+		SELECT bo.updated_at, ` + pricingRulePrice("bo", "max_price_per_unit") + `
+		FROM bo
+	` + pricingRuleJoins("bo")
+
+	basis, percent, floor, ceiling := pricingRuleArgs(order.PricingRule)
+	order.FixedMaxPricePerUnit = order.MaxPricePerUnit
 
 	err := r.db.QueryRowContext(
 		ctx,
@@ -217,7 +296,11 @@ func (r *BuyOrders) Update(ctx context.Context, order *models.BuyOrder) error {
 		order.MaxPricePerUnit,
 		order.Notes,
 		order.IsActive,
-	).Scan(&order.UpdatedAt)
+		basis,
+		percent,
+		floor,
+		ceiling,
+	).Scan(&order.UpdatedAt, &order.MaxPricePerUnit)
 
 	if err == sql.ErrNoRows {
 		return errors.New("buy order not found")
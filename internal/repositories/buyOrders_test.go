@@ -193,15 +193,13 @@ func Test_BuyOrders_Delete(t *testing.T) {
 	assert.False(t, retrieved.IsActive)
 }
 
-func Test_BuyOrders_GetDemandForSeller(t *testing.T) {
+func Test_BuyOrders_GetActiveByBuyers(t *testing.T) {
 	db, err := setupDatabase()
 	assert.NoError(t, err)
 
 	userRepo := repositories.NewUserRepository(db)
 	charRepo := repositories.NewCharacterRepository(db)
 	itemTypesRepo := repositories.NewItemTypeRepository(db)
-	contactsRepo := repositories.NewContacts(db)
-	permRepo := repositories.NewContactPermissions(db)
 	buyOrdersRepo := repositories.NewBuyOrders(db)
 
 	// Create buyer and seller
@@ -222,25 +220,6 @@ func Test_BuyOrders_GetDemandForSeller(t *testing.T) {
 	err = charRepo.Add(context.Background(), sellerChar)
 	assert.NoError(t, err)
 
-	// Create contact relationship
-	contact, err := contactsRepo.Create(context.Background(), 5040, 5041)
-	assert.NoError(t, err)
-
-	// Accept contact
-	_, err = contactsRepo.UpdateStatus(context.Background(), contact.ID, 5041, "accepted")
-	assert.NoError(t, err)
-
-	// Grant permission from buyer to seller (buyer grants seller permission to see buyer's buy orders)
-	perm := &models.ContactPermission{
-		ContactID:       contact.ID,
-		GrantingUserID:  5040,
-		ReceivingUserID: 5041,
-		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
-	}
-	err = permRepo.Upsert(context.Background(), perm)
-	assert.NoError(t, err)
-
 	// Create item types
 	itemTypes := []models.EveInventoryType{
 		{TypeID: 65, TypeName: "Tritanium", Volume: 0.01},
@@ -281,8 +260,18 @@ func Test_BuyOrders_GetDemandForSeller(t *testing.T) {
 	err = buyOrdersRepo.Create(context.Background(), order3)
 	assert.NoError(t, err)
 
-	// Get demand for seller
-	demand, err := buyOrdersRepo.GetDemandForSeller(context.Background(), 5041)
+	// Orders from buyers outside the list are excluded
+	otherOrder := &models.BuyOrder{
+		BuyerUserID:     5041,
+		TypeID:          66,
+		QuantityDesired: 1000,
+		MaxPricePerUnit: 12,
+		IsActive:        true,
+	}
+	err = buyOrdersRepo.Create(context.Background(), otherOrder)
+	assert.NoError(t, err)
+
+	demand, err := buyOrdersRepo.GetActiveByBuyers(context.Background(), []int64{5040})
 	assert.NoError(t, err)
 	assert.Len(t, demand, 2) // Only active orders
 
@@ -292,6 +281,11 @@ func Test_BuyOrders_GetDemandForSeller(t *testing.T) {
 		assert.NotEmpty(t, order.TypeName)
 		assert.True(t, order.IsActive)
 	}
+
+	// No buyers means no demand
+	demand, err = buyOrdersRepo.GetActiveByBuyers(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, demand)
 }
 
 func Test_BuyOrders_GetByID_NotFound(t *testing.T) {
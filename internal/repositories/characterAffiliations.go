@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type CharacterAffiliations struct {
+	db *sql.DB
+}
+
+func NewCharacterAffiliations(db *sql.DB) *CharacterAffiliations {
+	return &CharacterAffiliations{db: db}
+}
+
+// GetCharacterIDs returns every registered character
+func (r *CharacterAffiliations) GetCharacterIDs(ctx context.Context) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT id FROM characters ORDER BY id`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query character ids")
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		err = rows.Scan(&id)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan character id")
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// Upsert records the current corporation and alliance of each character
+func (r *CharacterAffiliations) Upsert(ctx context.Context, affiliations []*models.CharacterAffiliation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO character_affiliations (character_id, corporation_id, alliance_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (character_id)
+		DO UPDATE SET
+			corporation_id = EXCLUDED.corporation_id,
+			alliance_id = EXCLUDED.alliance_id,
+			updated_at = NOW()
+	`
+
+	for _, affiliation := range affiliations {
+		_, err = tx.ExecContext(ctx, query, affiliation.CharacterID, affiliation.CorporationID, affiliation.AllianceID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to upsert affiliation for character %d", affiliation.CharacterID)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit character affiliations")
+	}
+
+	return nil
+}
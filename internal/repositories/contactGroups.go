@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrContactGroupNotFound is returned when a group does not exist or belongs to another user
+	ErrContactGroupNotFound = errors.New("contact group not found")
+	// ErrContactGroupMemberNotFound is returned when removing a contact that is not in the group
+	ErrContactGroupMemberNotFound = errors.New("contact group member not found")
+)
+
+type ContactGroups struct {
+	db *sql.DB
+}
+
+func NewContactGroups(db *sql.DB) *ContactGroups {
+	return &ContactGroups{db: db}
+}
+
+// GetByUser returns a user's contact groups with their member contact IDs
+func (r *ContactGroups) GetByUser(ctx context.Context, userID int64) ([]*models.ContactGroup, error) {
+	query := `
+		SELECT
+			g.id,
+			g.user_id,
+			g.name,
+			COALESCE(array_agg(m.contact_id ORDER BY m.contact_id) FILTER (WHERE m.contact_id IS NOT NULL), '{}'),
+			g.created_at,
+			g.updated_at
+		FROM contact_groups g
+		LEFT JOIN contact_group_members m ON m.group_id = g.id
+		WHERE g.user_id = $1
+		GROUP BY g.id
+		ORDER BY g.name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact groups")
+	}
+	defer rows.Close()
+
+	groups := []*models.ContactGroup{}
+	for rows.Next() {
+		var group models.ContactGroup
+		var members pq.Int64Array
+		err = rows.Scan(
+			&group.ID,
+			&group.UserID,
+			&group.Name,
+			&members,
+			&group.CreatedAt,
+			&group.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact group")
+		}
+		group.MemberContactIDs = []int64(members)
+		groups = append(groups, &group)
+	}
+
+	return groups, nil
+}
+
+// Create adds a new empty group for group.UserID
+func (r *ContactGroups) Create(ctx context.Context, group *models.ContactGroup) error {
+	query := `
+		INSERT INTO contact_groups (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, group.UserID, group.Name).Scan(&group.ID, &group.CreatedAt, &group.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create contact group")
+	}
+	group.MemberContactIDs = []int64{}
+
+	return nil
+}
+
+// Rename changes the name of a group owned by userID
+func (r *ContactGroups) Rename(ctx context.Context, groupID, userID int64, name string) error {
+	query := `
+		UPDATE contact_groups
+		SET name = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, groupID, userID, name)
+	if err != nil {
+		return errors.Wrap(err, "failed to rename contact group")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrContactGroupNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a group along with its members and group-level grants
+func (r *ContactGroups) Delete(ctx context.Context, groupID, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM contact_groups WHERE id = $1 AND user_id = $2`, groupID, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete contact group")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrContactGroupNotFound
+	}
+
+	return nil
+}
+
+// AddMember adds one of userID's accepted contacts to the group, giving it the group's grants
+func (r *ContactGroups) AddMember(ctx context.Context, groupID, userID, contactID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	var isContact bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM contacts
+			WHERE id = $1 AND status = 'accepted' AND (requester_user_id = $2 OR recipient_user_id = $2)
+		)
+	`, contactID, userID).Scan(&isContact)
+	if err != nil {
+		return errors.Wrap(err, "failed to check contact")
+	}
+	if !isContact {
+		return ErrContactNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO contact_group_members (group_id, contact_id)
+		VALUES ($1, $2)
+		ON CONFLICT (group_id, contact_id) DO NOTHING
+	`, groupID, contactID)
+	if err != nil {
+		return errors.Wrap(err, "failed to add contact group member")
+	}
+
+	return tx.Commit()
+}
+
+// RemoveMember removes a contact from the group, withdrawing access it only had through the group
+func (r *ContactGroups) RemoveMember(ctx context.Context, groupID, userID, contactID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, groupID, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM contact_group_members WHERE group_id = $1 AND contact_id = $2
+	`, groupID, contactID)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove contact group member")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrContactGroupMemberNotFound
+	}
+
+	return tx.Commit()
+}
+
+// GetPermissions returns the services set at group level
+func (r *ContactGroups) GetPermissions(ctx context.Context, groupID, userID int64) ([]*models.ContactGroupPermission, error) {
+	query := `
+		SELECT gp.group_id, gp.service_type, gp.can_access
+		FROM contact_group_permissions gp
+		INNER JOIN contact_groups g ON g.id = gp.group_id
+		WHERE gp.group_id = $1 AND g.user_id = $2
+		ORDER BY gp.service_type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact group permissions")
+	}
+	defer rows.Close()
+
+	permissions := []*models.ContactGroupPermission{}
+	for rows.Next() {
+		var perm models.ContactGroupPermission
+		err = rows.Scan(&perm.GroupID, &perm.ServiceType, &perm.CanAccess)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact group permission")
+		}
+		permissions = append(permissions, &perm)
+	}
+
+	return permissions, nil
+}
+
+// SetPermission grants or revokes a service for every member of the group
+func (r *ContactGroups) SetPermission(ctx context.Context, userID int64, perm *models.ContactGroupPermission) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, perm.GroupID, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO contact_group_permissions (group_id, service_type, can_access, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (group_id, service_type)
+		DO UPDATE SET
+			can_access = EXCLUDED.can_access,
+			updated_at = NOW()
+	`, perm.GroupID, perm.ServiceType, perm.CanAccess)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert contact group permission")
+	}
+
+	return tx.Commit()
+}
+
+// GetMemberUserIDs returns the users on the other side of the group's contacts
+func (r *ContactGroups) GetMemberUserIDs(ctx context.Context, groupID, userID int64) ([]int64, error) {
+	query := `
+		SELECT CASE WHEN c.requester_user_id = g.user_id THEN c.recipient_user_id ELSE c.requester_user_id END
+		FROM contact_groups g
+		INNER JOIN contact_group_members m ON m.group_id = g.id
+		INNER JOIN contacts c ON c.id = m.contact_id
+		WHERE g.id = $1 AND g.user_id = $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, groupID, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact group members")
+	}
+	defer rows.Close()
+
+	userIDs := []int64{}
+	for rows.Next() {
+		var memberID int64
+		err = rows.Scan(&memberID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact group member")
+		}
+		userIDs = append(userIDs, memberID)
+	}
+
+	return userIDs, nil
+}
+
+func (r *ContactGroups) checkOwner(ctx context.Context, tx *sql.Tx, groupID, userID int64) error {
+	var owned bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM contact_groups WHERE id = $1 AND user_id = $2)
+	`, groupID, userID).Scan(&owned)
+	if err != nil {
+		return errors.Wrap(err, "failed to check contact group")
+	}
+	if !owned {
+		return ErrContactGroupNotFound
+	}
+	return nil
+}
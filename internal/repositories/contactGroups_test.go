@@ -0,0 +1,144 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContactGroupsShouldGrantToMembers(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+	permissionsRepo := repositories.NewContactPermissions(db)
+	groupsRepo := repositories.NewContactGroups(db)
+
+	owner := &repositories.User{ID: 1900, Name: "Owner"}
+	hauler := &repositories.User{ID: 1901, Name: "Hauler"}
+	for _, u := range []*repositories.User{owner, hauler} {
+		assert.NoError(t, userRepo.Add(ctx, u))
+	}
+
+	contact, err := contactsRepo.Create(ctx, owner.ID, hauler.ID)
+	assert.NoError(t, err)
+
+	haulers := &models.ContactGroup{UserID: owner.ID, Name: "Haulers"}
+	assert.NoError(t, groupsRepo.Create(ctx, haulers))
+	trusted := &models.ContactGroup{UserID: owner.ID, Name: "Trusted"}
+	assert.NoError(t, groupsRepo.Create(ctx, trusted))
+
+	// Pending contacts cannot be grouped
+	err = groupsRepo.AddMember(ctx, haulers.ID, owner.ID, contact.ID)
+	assert.ErrorIs(t, err, repositories.ErrContactNotFound)
+
+	_, err = contactsRepo.UpdateStatus(ctx, contact.ID, hauler.ID, "accepted")
+	assert.NoError(t, err)
+
+	assert.NoError(t, groupsRepo.AddMember(ctx, haulers.ID, owner.ID, contact.ID))
+	assert.NoError(t, groupsRepo.AddMember(ctx, trusted.ID, owner.ID, contact.ID))
+
+	// Granting at group level reaches the member
+	assert.NoError(t, groupsRepo.SetPermission(ctx, owner.ID, &models.ContactGroupPermission{GroupID: haulers.ID, ServiceType: "stockpile_view", CanAccess: true}))
+	assert.NoError(t, groupsRepo.SetPermission(ctx, owner.ID, &models.ContactGroupPermission{GroupID: trusted.ID, ServiceType: "stockpile_view", CanAccess: true}))
+
+	canView, err := permissionsRepo.CheckPermission(ctx, owner.ID, hauler.ID, "stockpile_view")
+	assert.NoError(t, err)
+	assert.True(t, canView)
+
+	grantingUsers, err := permissionsRepo.GetUserPermissionsForService(ctx, hauler.ID, "stockpile_view")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{owner.ID}, grantingUsers)
+
+	members, err := groupsRepo.GetMemberUserIDs(ctx, haulers.ID, owner.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{hauler.ID}, members)
+
+	// Leaving one group keeps access another group still grants
+	assert.NoError(t, groupsRepo.RemoveMember(ctx, haulers.ID, owner.ID, contact.ID))
+	canView, err = permissionsRepo.CheckPermission(ctx, owner.ID, hauler.ID, "stockpile_view")
+	assert.NoError(t, err)
+	assert.True(t, canView)
+
+	// Deleting the last granting group withdraws it
+	assert.NoError(t, groupsRepo.Delete(ctx, trusted.ID, owner.ID))
+	canView, err = permissionsRepo.CheckPermission(ctx, owner.ID, hauler.ID, "stockpile_view")
+	assert.NoError(t, err)
+	assert.False(t, canView)
+
+	groups, err := groupsRepo.GetByUser(ctx, owner.ID)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, "Haulers", groups[0].Name)
+	assert.Empty(t, groups[0].MemberContactIDs)
+
+	// Other users cannot touch the group
+	err = groupsRepo.Rename(ctx, haulers.ID, hauler.ID, "Mine")
+	assert.ErrorIs(t, err, repositories.ErrContactGroupNotFound)
+}
+
+func Test_ContactGroupsShouldKeepDirectGrantsWhenLeavingGroup(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+	permissionsRepo := repositories.NewContactPermissions(db)
+	groupsRepo := repositories.NewContactGroups(db)
+
+	owner := &repositories.User{ID: 1910, Name: "Owner"}
+	friend := &repositories.User{ID: 1911, Name: "Friend"}
+	for _, u := range []*repositories.User{owner, friend} {
+		assert.NoError(t, userRepo.Add(ctx, u))
+	}
+
+	contact, err := contactsRepo.Create(ctx, owner.ID, friend.ID)
+	assert.NoError(t, err)
+	_, err = contactsRepo.UpdateStatus(ctx, contact.ID, friend.ID, "accepted")
+	assert.NoError(t, err)
+
+	// Granted directly before the group existed
+	assert.NoError(t, permissionsRepo.Upsert(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  owner.ID,
+		ReceivingUserID: friend.ID,
+		ServiceType:     "asset_view",
+		CanAccess:       boolPtr(true),
+	}))
+
+	group := &models.ContactGroup{UserID: owner.ID, Name: "Corp Mates"}
+	assert.NoError(t, groupsRepo.Create(ctx, group))
+	assert.NoError(t, groupsRepo.AddMember(ctx, group.ID, owner.ID, contact.ID))
+	assert.NoError(t, groupsRepo.SetPermission(ctx, owner.ID, &models.ContactGroupPermission{GroupID: group.ID, ServiceType: "asset_view", CanAccess: true}))
+	assert.NoError(t, groupsRepo.SetPermission(ctx, owner.ID, &models.ContactGroupPermission{GroupID: group.ID, ServiceType: "stockpile_view", CanAccess: true}))
+
+	// Revoking at group level and leaving the group only withdraws what the group gave
+	assert.NoError(t, groupsRepo.SetPermission(ctx, owner.ID, &models.ContactGroupPermission{GroupID: group.ID, ServiceType: "asset_view", CanAccess: false}))
+	canView, err := permissionsRepo.CheckPermission(ctx, owner.ID, friend.ID, "asset_view")
+	assert.NoError(t, err)
+	assert.True(t, canView)
+
+	assert.NoError(t, groupsRepo.RemoveMember(ctx, group.ID, owner.ID, contact.ID))
+
+	canView, err = permissionsRepo.CheckPermission(ctx, owner.ID, friend.ID, "asset_view")
+	assert.NoError(t, err)
+	assert.True(t, canView)
+
+	canView, err = permissionsRepo.CheckPermission(ctx, owner.ID, friend.ID, "stockpile_view")
+	assert.NoError(t, err)
+	assert.False(t, canView)
+
+	permissions, err := permissionsRepo.GetByContact(ctx, contact.ID, owner.ID)
+	assert.NoError(t, err)
+	for _, perm := range permissions {
+		if perm.GrantingUserID == owner.ID && perm.ServiceType == "asset_view" {
+			assert.Equal(t, boolPtr(true), perm.CanAccess)
+		}
+	}
+}
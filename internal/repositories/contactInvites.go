@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// ErrContactInviteNotFound is returned when an invite does not exist or was sent by another user
+var ErrContactInviteNotFound = errors.New("contact invite not found")
+
+type ContactInvites struct {
+	db *sql.DB
+}
+
+func NewContactInvites(db *sql.DB) *ContactInvites {
+	return &ContactInvites{db: db}
+}
+
+// Create stores an invitation, refreshing the character name if it was already invited
+func (r *ContactInvites) Create(ctx context.Context, invite *models.ContactInvite) error {
+	query := `
+		INSERT INTO contact_invites (requester_user_id, character_id, character_name)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (requester_user_id, character_id)
+		DO UPDATE SET
+			character_name = COALESCE(EXCLUDED.character_name, contact_invites.character_name)
+		RETURNING id, character_name, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, invite.RequesterUserID, invite.CharacterID, invite.CharacterName).Scan(
+		&invite.ID,
+		&invite.CharacterName,
+		&invite.CreatedAt,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create contact invite")
+	}
+
+	return nil
+}
+
+// GetByRequester returns the outstanding invitations a user has sent
+func (r *ContactInvites) GetByRequester(ctx context.Context, userID int64) ([]*models.ContactInvite, error) {
+	query := `
+		SELECT id, requester_user_id, character_id, character_name, created_at
+		FROM contact_invites
+		WHERE requester_user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query contact invites")
+	}
+	defer rows.Close()
+
+	invites := []*models.ContactInvite{}
+	for rows.Next() {
+		var invite models.ContactInvite
+		err = rows.Scan(
+			&invite.ID,
+			&invite.RequesterUserID,
+			&invite.CharacterID,
+			&invite.CharacterName,
+			&invite.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan contact invite")
+		}
+		invites = append(invites, &invite)
+	}
+
+	return invites, nil
+}
+
+// Delete cancels an invitation sent by userID
+func (r *ContactInvites) Delete(ctx context.Context, id, userID int64) error {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM contact_invites WHERE id = $1 AND requester_user_id = $2
+	`, id, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete contact invite")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrContactInviteNotFound
+	}
+
+	return nil
+}
+
+// ActivateForCharacter turns invitations for characterID into pending contact requests to userID,
+// now that the character belongs to a registered user. Returns the number of requests created.
+func (r *ContactInvites) ActivateForCharacter(ctx context.Context, characterID, userID int64) (int64, error) {
+	query := `
+		WITH invites AS (
+			DELETE FROM contact_invites
+			WHERE character_id = $1
+			RETURNING requester_user_id
+		)
+		INSERT INTO contacts (requester_user_id, recipient_user_id, status, requested_at)
+		SELECT DISTINCT i.requester_user_id, $2::bigint, 'pending', NOW()
+		FROM invites i
+		WHERE i.requester_user_id != $2
+		  AND NOT EXISTS (
+			SELECT 1 FROM contacts c
+			WHERE c.requester_user_id = $2 AND c.recipient_user_id = i.requester_user_id
+		  )
+		ON CONFLICT (requester_user_id, recipient_user_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, characterID, userID)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to activate contact invites")
+	}
+
+	activated, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return activated, nil
+}
@@ -0,0 +1,88 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ContactInvitesShouldActivateOnRegistration(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+	invitesRepo := repositories.NewContactInvites(db)
+
+	requester := &repositories.User{ID: 2000, Name: "Requester"}
+	assert.NoError(t, userRepo.Add(ctx, requester))
+
+	name := "Newcomer"
+	invite := &models.ContactInvite{RequesterUserID: requester.ID, CharacterID: 2001, CharacterName: &name}
+	assert.NoError(t, invitesRepo.Create(ctx, invite))
+	assert.NotZero(t, invite.ID)
+
+	// Inviting again by ID keeps the resolved name
+	again := &models.ContactInvite{RequesterUserID: requester.ID, CharacterID: 2001}
+	assert.NoError(t, invitesRepo.Create(ctx, again))
+	assert.Equal(t, invite.ID, again.ID)
+	assert.Equal(t, name, *again.CharacterName)
+
+	invites, err := invitesRepo.GetByRequester(ctx, requester.ID)
+	assert.NoError(t, err)
+	assert.Len(t, invites, 1)
+
+	// The invited character registers as their own user
+	newcomer := &repositories.User{ID: 2001, Name: "Newcomer"}
+	assert.NoError(t, userRepo.Add(ctx, newcomer))
+
+	activated, err := invitesRepo.ActivateForCharacter(ctx, 2001, newcomer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), activated)
+
+	contacts, err := contactsRepo.GetByUser(ctx, newcomer.ID)
+	assert.NoError(t, err)
+	assert.Len(t, contacts, 1)
+	assert.Equal(t, requester.ID, contacts[0].RequesterUserID)
+	assert.Equal(t, "pending", contacts[0].Status)
+
+	invites, err = invitesRepo.GetByRequester(ctx, requester.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, invites)
+
+	// Activation is idempotent once the invites are consumed
+	activated, err = invitesRepo.ActivateForCharacter(ctx, 2001, newcomer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), activated)
+}
+
+func Test_ContactInvitesShouldSkipSelfInvites(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+	invitesRepo := repositories.NewContactInvites(db)
+
+	user := &repositories.User{ID: 2010, Name: "Main"}
+	assert.NoError(t, userRepo.Add(ctx, user))
+
+	// The user invited their own alt before linking it
+	assert.NoError(t, invitesRepo.Create(ctx, &models.ContactInvite{RequesterUserID: user.ID, CharacterID: 2011}))
+
+	activated, err := invitesRepo.ActivateForCharacter(ctx, 2011, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), activated)
+
+	contacts, err := contactsRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, contacts)
+
+	err = invitesRepo.Delete(ctx, 1, 999999)
+	assert.ErrorIs(t, err, repositories.ErrContactInviteNotFound)
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
@@ -19,7 +20,7 @@ func NewContactPermissions(db *sql.DB) *ContactPermissions {
 // GetByContact returns all permissions for a specific contact
 func (r *ContactPermissions) GetByContact(ctx context.Context, contactID int64, userID int64) ([]*models.ContactPermission, error) {
 	query := `
-		SELECT id, contact_id, granting_user_id, receiving_user_id, service_type, can_access
+		SELECT id, contact_id, granting_user_id, receiving_user_id, service_type, can_access, station_ids, owner_ids
 		FROM contact_permissions
 		WHERE contact_id = $1 AND (granting_user_id = $2 OR receiving_user_id = $2)
 		ORDER BY service_type
@@ -41,6 +42,8 @@ func (r *ContactPermissions) GetByContact(ctx context.Context, contactID int64,
 			&perm.ReceivingUserID,
 			&perm.ServiceType,
 			&perm.CanAccess,
+			pq.Array(&perm.StationIDs),
+			pq.Array(&perm.OwnerIDs),
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan contact permission")
@@ -51,7 +54,7 @@ func (r *ContactPermissions) GetByContact(ctx context.Context, contactID int64,
 	return permissions, nil
 }
 
-// Upsert creates or updates a permission
+// Upsert creates or updates a permission; a nil CanAccess clears the user's decision
 func (r *ContactPermissions) Upsert(ctx context.Context, perm *models.ContactPermission) error {
 	query := `
 		INSERT INTO contact_permissions
@@ -77,19 +80,132 @@ func (r *ContactPermissions) Upsert(ctx context.Context, perm *models.ContactPer
 	return nil
 }
 
-// CheckPermission verifies if grantingUser allows receivingUser to access serviceType
-func (r *ContactPermissions) CheckPermission(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) (bool, error) {
+// SetScope limits what a scoped grant exposes to the given stations and owners without
+// touching the access decision; an empty list removes that limit
+func (r *ContactPermissions) SetScope(ctx context.Context, perm *models.ContactPermission) error {
+	query := `
+		INSERT INTO contact_permissions
+		(contact_id, granting_user_id, receiving_user_id, service_type, can_access, station_ids, owner_ids, updated_at)
+		VALUES ($1, $2, $3, $4, NULL, $5, $6, NOW())
+		ON CONFLICT (contact_id, granting_user_id, receiving_user_id, service_type)
+		DO UPDATE SET
+			station_ids = EXCLUDED.station_ids,
+			owner_ids = EXCLUDED.owner_ids,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		perm.ContactID,
+		perm.GrantingUserID,
+		perm.ReceivingUserID,
+		perm.ServiceType,
+		scopeArray(perm.StationIDs),
+		scopeArray(perm.OwnerIDs),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to set contact permission scope")
+	}
+
+	return nil
+}
+
+// GetScope returns the stations and owners grantingUser limited serviceType to for
+// receivingUser; nil means unrestricted
+func (r *ContactPermissions) GetScope(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) ([]int64, []int64, error) {
 	query := `
-		SELECT can_access
+		SELECT station_ids, owner_ids
 		FROM contact_permissions
 		WHERE granting_user_id = $1 AND receiving_user_id = $2 AND service_type = $3
+		ORDER BY id
+		LIMIT 1
 	`
 
-	var canAccess bool
-	err := r.db.QueryRowContext(ctx, query, grantingUserID, receivingUserID, serviceType).Scan(&canAccess)
+	var stationIDs, ownerIDs []int64
+	err := r.db.QueryRowContext(ctx, query, grantingUserID, receivingUserID, serviceType).
+		Scan(pq.Array(&stationIDs), pq.Array(&ownerIDs))
 	if err == sql.ErrNoRows {
-		return false, nil
+		return nil, nil, nil
 	}
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to get contact permission scope")
+	}
+
+	if len(stationIDs) == 0 {
+		stationIDs = nil
+	}
+	if len(ownerIDs) == 0 {
+		ownerIDs = nil
+	}
+
+	return stationIDs, ownerIDs, nil
+}
+
+// scopeArray stores an empty scope as NULL so it reads back as unrestricted
+func scopeArray(ids []int64) any {
+	if len(ids) == 0 {
+		return nil
+	}
+	return pq.Array(ids)
+}
+
+// affiliatedGrants selects corporation/alliance grants whose receiver matches the current
+// affiliation of one of the receiving user's characters (filtered on c.user_id)
+const affiliatedGrants = `
+		FROM affiliation_permissions ap
+		INNER JOIN character_affiliations ca ON (
+			(ap.receiver_type = 'corporation' AND ca.corporation_id = ap.receiver_id)
+			OR (ap.receiver_type = 'alliance' AND ca.alliance_id = ap.receiver_id)
+		)
+		INNER JOIN characters c ON c.id = ca.character_id
+`
+
+// groupGrants selects services granted to a contact through one of the granting user's
+// contact groups (filtered on g.user_id); these live apart from contact_permissions so
+// leaving a group never touches a direct grant
+const groupGrants = `
+		FROM contact_group_permissions gp
+		INNER JOIN contact_groups g ON g.id = gp.group_id
+		INNER JOIN contact_group_members m ON m.group_id = g.id
+		INNER JOIN contacts ct ON ct.id = m.contact_id AND ct.status = 'accepted'
+`
+
+// CheckPermission verifies if grantingUser allows receivingUser to access serviceType.
+// A per-contact row that denies access wins. Otherwise receivingUser has it if their own
+// row, one of their contact groups or one of their corporations or alliances grants it;
+// a row that was never set leaves the decision to those grants
+func (r *ContactPermissions) CheckPermission(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) (bool, error) {
+	query := `
+		SELECT NOT EXISTS (
+			SELECT 1
+			FROM contact_permissions
+			WHERE granting_user_id = $1 AND receiving_user_id = $2 AND service_type = $3 AND can_access = false
+		) AND (
+			EXISTS (
+				SELECT 1
+				FROM contact_permissions
+				WHERE granting_user_id = $1 AND receiving_user_id = $2 AND service_type = $3 AND can_access = true
+			)
+			OR EXISTS (
+				SELECT 1
+	` + groupGrants + `
+				WHERE g.user_id = $1
+				  AND $2 IN (ct.requester_user_id, ct.recipient_user_id)
+				  AND gp.service_type = $3
+				  AND gp.can_access = true
+			)
+			OR EXISTS (
+				SELECT 1
+	` + affiliatedGrants + `
+				WHERE ap.granting_user_id = $1
+				  AND c.user_id = $2
+				  AND ap.service_type = $3
+				  AND ap.can_access = true
+			)
+		)
+	`
+
+	var canAccess bool
+	err := r.db.QueryRowContext(ctx, query, grantingUserID, receivingUserID, serviceType).Scan(&canAccess)
 	if err != nil {
 		return false, errors.Wrap(err, "failed to check permission")
 	}
@@ -97,12 +213,43 @@ func (r *ContactPermissions) CheckPermission(ctx context.Context, grantingUserID
 	return canAccess, nil
 }
 
-// GetUserPermissionsForService returns all users who granted permission to viewerUserID for a specific service
+// GetUserPermissionsForService returns all users who granted permission to viewerUserID for a specific service,
+// directly, through a contact group or through viewerUserID's corporations and alliances. As in CheckPermission,
+// a per-contact row denying access excludes the granter whatever their other grants say
 func (r *ContactPermissions) GetUserPermissionsForService(ctx context.Context, viewerUserID int64, serviceType string) ([]int64, error) {
 	query := `
-		SELECT granting_user_id
-		FROM contact_permissions
-		WHERE receiving_user_id = $1 AND service_type = $2 AND can_access = true
+		SELECT grants.granting_user_id
+		FROM (
+			SELECT granting_user_id
+			FROM contact_permissions
+			WHERE receiving_user_id = $1 AND service_type = $2 AND can_access = true
+
+			UNION
+
+			SELECT g.user_id
+	` + groupGrants + `
+			WHERE $1 IN (ct.requester_user_id, ct.recipient_user_id)
+			  AND g.user_id != $1
+			  AND gp.service_type = $2
+			  AND gp.can_access = true
+
+			UNION
+
+			SELECT ap.granting_user_id
+	` + affiliatedGrants + `
+			WHERE c.user_id = $1
+			  AND ap.service_type = $2
+			  AND ap.can_access = true
+			  AND ap.granting_user_id != $1
+		) grants
+		WHERE NOT EXISTS (
+			SELECT 1
+			FROM contact_permissions cp
+			WHERE cp.granting_user_id = grants.granting_user_id
+			  AND cp.receiving_user_id = $1
+			  AND cp.service_type = $2
+			  AND cp.can_access = false
+		)
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, viewerUserID, serviceType)
@@ -124,19 +271,20 @@ func (r *ContactPermissions) GetUserPermissionsForService(ctx context.Context, v
 	return userIDs, nil
 }
 
-// InitializePermissionsForContact creates default (all denied) permissions when contact accepted
+// InitializePermissionsForContact creates unset permissions when contact accepted, so the
+// contact keeps any group and affiliation grants until a user decides otherwise
 func (r *ContactPermissions) InitializePermissionsForContact(ctx context.Context, tx *sql.Tx, contactID, userID1, userID2 int64) error {
-	// Default service types
-	serviceTypes := []string{"for_sale_browse"}
-
 	query := `
 		INSERT INTO contact_permissions
 		(contact_id, granting_user_id, receiving_user_id, service_type, can_access)
-		VALUES ($1, $2, $3, $4, false)
+		VALUES ($1, $2, $3, $4, NULL)
 		ON CONFLICT (contact_id, granting_user_id, receiving_user_id, service_type) DO NOTHING
 	`
 
-	for _, serviceType := range serviceTypes {
+	// Seed every registered service so the permission UI reflects what the backend enforces
+	for _, service := range models.ServiceTypes {
+		serviceType := service.Type
+
 		// User1 grants to User2
 		_, err := tx.ExecContext(ctx, query, contactID, userID1, userID2, serviceType)
 		if err != nil {
@@ -45,7 +45,7 @@ func Test_ContactPermissionsShouldUpsert(t *testing.T) {
 		GrantingUserID:  user1.ID,
 		ReceivingUserID: user2.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm)
 	assert.NoError(t, err)
@@ -90,13 +90,13 @@ func Test_ContactPermissionsShouldUpdateExisting(t *testing.T) {
 		GrantingUserID:  user1.ID,
 		ReceivingUserID: user2.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       false,
+		CanAccess:       boolPtr(false),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm)
 	assert.NoError(t, err)
 
 	// Update to true
-	perm.CanAccess = true
+	perm.CanAccess = boolPtr(true)
 	err = permissionsRepo.Upsert(context.Background(), perm)
 	assert.NoError(t, err)
 
@@ -140,7 +140,7 @@ func Test_ContactPermissionsShouldCheckPermission(t *testing.T) {
 		GrantingUserID:  user1.ID,
 		ReceivingUserID: user2.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm)
 	assert.NoError(t, err)
@@ -200,7 +200,7 @@ func Test_ContactPermissionsShouldGetUserPermissionsForService(t *testing.T) {
 		GrantingUserID:  user2.ID,
 		ReceivingUserID: user1.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm1)
 	assert.NoError(t, err)
@@ -211,7 +211,7 @@ func Test_ContactPermissionsShouldGetUserPermissionsForService(t *testing.T) {
 		GrantingUserID:  user3.ID,
 		ReceivingUserID: user1.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm2)
 	assert.NoError(t, err)
@@ -263,7 +263,7 @@ func Test_ContactPermissionsShouldInitializePermissionsForContact(t *testing.T)
 	err = tx.Commit()
 	assert.NoError(t, err)
 
-	// Verify both users have permissions (all set to false initially)
+	// Verify neither user has access (nothing decided yet)
 	hasPermission1, err := permissionsRepo.CheckPermission(context.Background(), user1.ID, user2.ID, "for_sale_browse")
 	assert.NoError(t, err)
 	assert.False(t, hasPermission1)
@@ -271,6 +271,14 @@ func Test_ContactPermissionsShouldInitializePermissionsForContact(t *testing.T)
 	hasPermission2, err := permissionsRepo.CheckPermission(context.Background(), user2.ID, user1.ID, "for_sale_browse")
 	assert.NoError(t, err)
 	assert.False(t, hasPermission2)
+
+	// Every registered service is seeded in both directions, left unset
+	permissions, err := permissionsRepo.GetByContact(context.Background(), contact.ID, user1.ID)
+	assert.NoError(t, err)
+	assert.Len(t, permissions, 2*len(models.ServiceTypes))
+	for _, perm := range permissions {
+		assert.Nil(t, perm.CanAccess)
+	}
 }
 
 func Test_ContactPermissionsShouldGetByContact(t *testing.T) {
@@ -307,7 +315,7 @@ func Test_ContactPermissionsShouldGetByContact(t *testing.T) {
 		GrantingUserID:  user1.ID,
 		ReceivingUserID: user2.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm1)
 	assert.NoError(t, err)
@@ -317,7 +325,7 @@ func Test_ContactPermissionsShouldGetByContact(t *testing.T) {
 		GrantingUserID:  user2.ID,
 		ReceivingUserID: user1.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       false,
+		CanAccess:       boolPtr(false),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm2)
 	assert.NoError(t, err)
@@ -331,10 +339,10 @@ func Test_ContactPermissionsShouldGetByContact(t *testing.T) {
 	foundGranted := false
 	foundNotGranted := false
 	for _, perm := range permissions {
-		if perm.GrantingUserID == user1.ID && perm.CanAccess {
+		if perm.GrantingUserID == user1.ID && *perm.CanAccess {
 			foundGranted = true
 		}
-		if perm.GrantingUserID == user2.ID && !perm.CanAccess {
+		if perm.GrantingUserID == user2.ID && !*perm.CanAccess {
 			foundNotGranted = true
 		}
 	}
@@ -376,7 +384,7 @@ func Test_ContactPermissionsShouldBeUnidirectional(t *testing.T) {
 		GrantingUserID:  user1.ID,
 		ReceivingUserID: user2.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       true,
+		CanAccess:       boolPtr(true),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm1)
 	assert.NoError(t, err)
@@ -387,7 +395,7 @@ func Test_ContactPermissionsShouldBeUnidirectional(t *testing.T) {
 		GrantingUserID:  user2.ID,
 		ReceivingUserID: user1.ID,
 		ServiceType:     "for_sale_browse",
-		CanAccess:       false,
+		CanAccess:       boolPtr(false),
 	}
 	err = permissionsRepo.Upsert(context.Background(), perm2)
 	assert.NoError(t, err)
@@ -401,3 +409,288 @@ func Test_ContactPermissionsShouldBeUnidirectional(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, hasPermission2to1)
 }
+
+func Test_ContactPermissionsShouldHonourAffiliationGrants(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	characterRepo := repositories.NewCharacterRepository(db)
+	affiliationsRepo := repositories.NewCharacterAffiliations(db)
+	affiliationPermsRepo := repositories.NewAffiliationPermissions(db)
+	permissionsRepo := repositories.NewContactPermissions(db)
+
+	granter := &repositories.User{ID: 1700, Name: "Granter"}
+	member := &repositories.User{ID: 1701, Name: "Corp Member"}
+	outsider := &repositories.User{ID: 1702, Name: "Outsider"}
+
+	for _, u := range []*repositories.User{granter, member, outsider} {
+		err = userRepo.Add(context.Background(), u)
+		assert.NoError(t, err)
+	}
+
+	err = characterRepo.Add(context.Background(), &repositories.Character{ID: 17000, Name: "Granter Char", UserID: granter.ID})
+	assert.NoError(t, err)
+	err = characterRepo.Add(context.Background(), &repositories.Character{ID: 17001, Name: "Member Char", UserID: member.ID})
+	assert.NoError(t, err)
+	err = characterRepo.Add(context.Background(), &repositories.Character{ID: 17002, Name: "Outsider Char", UserID: outsider.ID})
+	assert.NoError(t, err)
+
+	allianceID := int64(99000001)
+	err = affiliationsRepo.Upsert(context.Background(), []*models.CharacterAffiliation{
+		{CharacterID: 17001, CorporationID: 98000001, AllianceID: &allianceID},
+		{CharacterID: 17002, CorporationID: 98000002},
+	})
+	assert.NoError(t, err)
+
+	characterIDs, err := affiliationsRepo.GetCharacterIDs(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, characterIDs, int64(17001))
+
+	// No contact exists - only the corporation grant can allow access
+	err = affiliationPermsRepo.Upsert(context.Background(), &models.AffiliationPermission{
+		GrantingUserID: granter.ID,
+		ReceiverType:   "corporation",
+		ReceiverID:     98000001,
+		ServiceType:    "for_sale_browse",
+		CanAccess:      true,
+	})
+	assert.NoError(t, err)
+
+	hasPermission, err := permissionsRepo.CheckPermission(context.Background(), granter.ID, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.True(t, hasPermission)
+
+	hasPermission, err = permissionsRepo.CheckPermission(context.Background(), granter.ID, outsider.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.False(t, hasPermission)
+
+	grantingUsers, err := permissionsRepo.GetUserPermissionsForService(context.Background(), member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.Contains(t, grantingUsers, granter.ID)
+
+	// Revoking the grant removes access
+	grants, err := affiliationPermsRepo.GetByGrantingUser(context.Background(), granter.ID)
+	assert.NoError(t, err)
+	assert.Len(t, grants, 1)
+
+	err = affiliationPermsRepo.Delete(context.Background(), grants[0].ID, granter.ID)
+	assert.NoError(t, err)
+
+	hasPermission, err = permissionsRepo.CheckPermission(context.Background(), granter.ID, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.False(t, hasPermission)
+
+	err = affiliationPermsRepo.Delete(context.Background(), grants[0].ID, granter.ID)
+	assert.Error(t, err)
+}
+
+func Test_ContactPermissionsShouldCombineContactRowsWithAffiliationGrants(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	characterRepo := repositories.NewCharacterRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+	affiliationsRepo := repositories.NewCharacterAffiliations(db)
+	affiliationPermsRepo := repositories.NewAffiliationPermissions(db)
+	groupsRepo := repositories.NewContactGroups(db)
+	permissionsRepo := repositories.NewContactPermissions(db)
+
+	granter := &repositories.User{ID: 1710, Name: "Granter"}
+	member := &repositories.User{ID: 1711, Name: "Corp Member"}
+
+	for _, u := range []*repositories.User{granter, member} {
+		err = userRepo.Add(ctx, u)
+		assert.NoError(t, err)
+	}
+
+	err = characterRepo.Add(ctx, &repositories.Character{ID: 17100, Name: "Granter Char", UserID: granter.ID})
+	assert.NoError(t, err)
+	err = characterRepo.Add(ctx, &repositories.Character{ID: 17101, Name: "Member Char", UserID: member.ID})
+	assert.NoError(t, err)
+
+	err = affiliationsRepo.Upsert(ctx, []*models.CharacterAffiliation{
+		{CharacterID: 17101, CorporationID: 98000011},
+	})
+	assert.NoError(t, err)
+
+	err = affiliationPermsRepo.Upsert(ctx, &models.AffiliationPermission{
+		GrantingUserID: granter.ID,
+		ReceiverType:   "corporation",
+		ReceiverID:     98000011,
+		ServiceType:    "for_sale_browse",
+		CanAccess:      true,
+	})
+	assert.NoError(t, err)
+
+	// Becoming a contact keeps the corporation grant
+	contact, err := contactsRepo.Create(ctx, granter.ID, member.ID)
+	assert.NoError(t, err)
+	_, err = contactsRepo.UpdateStatus(ctx, contact.ID, member.ID, "accepted")
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	err = permissionsRepo.InitializePermissionsForContact(ctx, tx, contact.ID, granter.ID, member.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	hasPermission, err := permissionsRepo.CheckPermission(ctx, granter.ID, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.True(t, hasPermission)
+
+	grantingUsers, err := permissionsRepo.GetUserPermissionsForService(ctx, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{granter.ID}, grantingUsers)
+
+	// An explicit denial for the contact overrides the corporation grant
+	denial := &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  granter.ID,
+		ReceivingUserID: member.ID,
+		ServiceType:     "for_sale_browse",
+		CanAccess:       boolPtr(false),
+	}
+	err = permissionsRepo.Upsert(ctx, denial)
+	assert.NoError(t, err)
+
+	hasPermission, err = permissionsRepo.CheckPermission(ctx, granter.ID, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.False(t, hasPermission)
+
+	grantingUsers, err = permissionsRepo.GetUserPermissionsForService(ctx, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.NotContains(t, grantingUsers, granter.ID)
+
+	// ...and a group grant the same way
+	group := &models.ContactGroup{UserID: granter.ID, Name: "Customers"}
+	assert.NoError(t, groupsRepo.Create(ctx, group))
+	assert.NoError(t, groupsRepo.AddMember(ctx, group.ID, granter.ID, contact.ID))
+	assert.NoError(t, groupsRepo.SetPermission(ctx, granter.ID, &models.ContactGroupPermission{GroupID: group.ID, ServiceType: "for_sale_browse", CanAccess: true}))
+
+	hasPermission, err = permissionsRepo.CheckPermission(ctx, granter.ID, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.False(t, hasPermission)
+
+	grantingUsers, err = permissionsRepo.GetUserPermissionsForService(ctx, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.NotContains(t, grantingUsers, granter.ID)
+
+	// Clearing the decision hands it back to the group and corporation grants
+	denial.CanAccess = nil
+	err = permissionsRepo.Upsert(ctx, denial)
+	assert.NoError(t, err)
+
+	hasPermission, err = permissionsRepo.CheckPermission(ctx, granter.ID, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.True(t, hasPermission)
+
+	grantingUsers, err = permissionsRepo.GetUserPermissionsForService(ctx, member.ID, "for_sale_browse")
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{granter.ID}, grantingUsers)
+
+	// A contact grant works where there is no corporation grant
+	err = permissionsRepo.Upsert(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  granter.ID,
+		ReceivingUserID: member.ID,
+		ServiceType:     "stockpile_view",
+		CanAccess:       boolPtr(true),
+	})
+	assert.NoError(t, err)
+
+	hasPermission, err = permissionsRepo.CheckPermission(ctx, granter.ID, member.ID, "stockpile_view")
+	assert.NoError(t, err)
+	assert.True(t, hasPermission)
+}
+
+// Helper function to create bool pointers
+func Test_ContactPermissionsShouldStoreScopeApartFromAccess(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+	permissionsRepo := repositories.NewContactPermissions(db)
+
+	granter := &repositories.User{ID: 1720, Name: "Granter"}
+	viewer := &repositories.User{ID: 1721, Name: "Viewer"}
+	for _, u := range []*repositories.User{granter, viewer} {
+		assert.NoError(t, userRepo.Add(ctx, u))
+	}
+
+	contact, err := contactsRepo.Create(ctx, granter.ID, viewer.ID)
+	assert.NoError(t, err)
+
+	// No row yet is unrestricted
+	stations, owners, err := permissionsRepo.GetScope(ctx, granter.ID, viewer.ID, models.ServiceAssetView)
+	assert.NoError(t, err)
+	assert.Nil(t, stations)
+	assert.Nil(t, owners)
+
+	err = permissionsRepo.Upsert(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  granter.ID,
+		ReceivingUserID: viewer.ID,
+		ServiceType:     models.ServiceAssetView,
+		CanAccess:       boolPtr(true),
+	})
+	assert.NoError(t, err)
+
+	err = permissionsRepo.SetScope(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  granter.ID,
+		ReceivingUserID: viewer.ID,
+		ServiceType:     models.ServiceAssetView,
+		StationIDs:      []int64{60003760},
+		OwnerIDs:        []int64{2001, 98000001},
+	})
+	assert.NoError(t, err)
+
+	stations, owners, err = permissionsRepo.GetScope(ctx, granter.ID, viewer.ID, models.ServiceAssetView)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{60003760}, stations)
+	assert.Equal(t, []int64{2001, 98000001}, owners)
+
+	// Scoping leaves the access decision alone, and toggling access keeps the scope
+	hasPermission, err := permissionsRepo.CheckPermission(ctx, granter.ID, viewer.ID, models.ServiceAssetView)
+	assert.NoError(t, err)
+	assert.True(t, hasPermission)
+
+	err = permissionsRepo.Upsert(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  granter.ID,
+		ReceivingUserID: viewer.ID,
+		ServiceType:     models.ServiceAssetView,
+		CanAccess:       boolPtr(false),
+	})
+	assert.NoError(t, err)
+
+	permissions, err := permissionsRepo.GetByContact(ctx, contact.ID, granter.ID)
+	assert.NoError(t, err)
+	assert.Len(t, permissions, 1)
+	assert.Equal(t, boolPtr(false), permissions[0].CanAccess)
+	assert.Equal(t, []int64{60003760}, permissions[0].StationIDs)
+
+	// Empty lists lift the limit
+	err = permissionsRepo.SetScope(ctx, &models.ContactPermission{
+		ContactID:       contact.ID,
+		GrantingUserID:  granter.ID,
+		ReceivingUserID: viewer.ID,
+		ServiceType:     models.ServiceAssetView,
+		StationIDs:      []int64{},
+	})
+	assert.NoError(t, err)
+
+	stations, owners, err = permissionsRepo.GetScope(ctx, granter.ID, viewer.ID, models.ServiceAssetView)
+	assert.NoError(t, err)
+	assert.Nil(t, stations)
+	assert.Nil(t, owners)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
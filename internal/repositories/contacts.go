@@ -9,6 +9,13 @@ import (
 	"github.com/pkg/errors"
 )
 
+var (
+	// ErrContactNotFound is returned when a contact does not exist or the user is not part of it
+	ErrContactNotFound = errors.New("contact not found")
+	// ErrCharacterNotFound is returned when no registered user has the character
+	ErrCharacterNotFound = errors.New("character not found")
+)
+
 type Contacts struct {
 	db *sql.DB
 }
@@ -64,6 +71,45 @@ func (r *Contacts) GetByUser(ctx context.Context, userID int64) ([]*models.Conta
 	return contacts, nil
 }
 
+// GetByID returns a contact the user is part of
+func (r *Contacts) GetByID(ctx context.Context, contactID int64, userID int64) (*models.Contact, error) {
+	query := `
+		SELECT
+			c.id,
+			c.requester_user_id,
+			c.recipient_user_id,
+			u_req.name AS requester_name,
+			u_rec.name AS recipient_name,
+			c.status,
+			c.requested_at,
+			c.responded_at
+		FROM contacts c
+		JOIN users u_req ON c.requester_user_id = u_req.id
+		JOIN users u_rec ON c.recipient_user_id = u_rec.id
+		WHERE c.id = $1 AND (c.requester_user_id = $2 OR c.recipient_user_id = $2)
+	`
+
+	var contact models.Contact
+	err := r.db.QueryRowContext(ctx, query, contactID, userID).Scan(
+		&contact.ID,
+		&contact.RequesterUserID,
+		&contact.RecipientUserID,
+		&contact.RequesterName,
+		&contact.RecipientName,
+		&contact.Status,
+		&contact.RequestedAt,
+		&contact.RespondedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrContactNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get contact")
+	}
+
+	return &contact, nil
+}
+
 // Create sends a contact request
 func (r *Contacts) Create(ctx context.Context, requesterID, recipientID int64) (*models.Contact, error) {
 	query := `
@@ -153,7 +199,7 @@ func (r *Contacts) GetUserIDByCharacterName(ctx context.Context, characterName s
 	var userID int64
 	err := r.db.QueryRowContext(ctx, query, characterName).Scan(&userID)
 	if err == sql.ErrNoRows {
-		return 0, errors.New("character not found - they may not have added their character to this tool yet")
+		return 0, ErrCharacterNotFound
 	}
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to find character")
@@ -174,7 +220,7 @@ func (r *Contacts) GetUserIDByCharacterID(ctx context.Context, characterID int64
 	var userID int64
 	err := r.db.QueryRowContext(ctx, query, characterID).Scan(&userID)
 	if err == sql.ErrNoRows {
-		return 0, errors.New("character not found in database")
+		return 0, ErrCharacterNotFound
 	}
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to find character by ID")
@@ -182,3 +228,42 @@ func (r *Contacts) GetUserIDByCharacterID(ctx context.Context, characterID int64
 
 	return userID, nil
 }
+
+// SearchCharacters finds registered characters by name (case-insensitive, partial match)
+func (r *Contacts) SearchCharacters(ctx context.Context, query string, limit int) ([]*models.CharacterSearchResult, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+
+	searchQuery := `
+		SELECT id, name
+		FROM characters
+		WHERE LOWER(name) LIKE LOWER($1)
+		ORDER BY
+			CASE
+				WHEN LOWER(name) = LOWER($3) THEN 1
+				WHEN LOWER(name) LIKE LOWER($3) || '%' THEN 2
+				ELSE 3
+			END,
+			name
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, searchQuery, "%"+query+"%", limit, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to search characters")
+	}
+	defer rows.Close()
+
+	results := []*models.CharacterSearchResult{}
+	for rows.Next() {
+		result := &models.CharacterSearchResult{Registered: true}
+		err = rows.Scan(&result.CharacterID, &result.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan character")
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
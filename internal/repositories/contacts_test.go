@@ -336,3 +336,33 @@ func Test_ContactsShouldNotUpdateStatusIfNotRecipient(t *testing.T) {
 	_, err = contactsRepo.UpdateStatus(context.Background(), contact.ID, user3.ID, "accepted")
 	assert.Error(t, err)
 }
+
+func Test_ContactsShouldGetByIDForParticipantsOnly(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	userRepo := repositories.NewUserRepository(db)
+	contactsRepo := repositories.NewContacts(db)
+
+	user1 := &repositories.User{ID: 180, Name: "User 1"}
+	user2 := &repositories.User{ID: 181, Name: "User 2"}
+	user3 := &repositories.User{ID: 182, Name: "User 3"}
+
+	for _, u := range []*repositories.User{user1, user2, user3} {
+		err = userRepo.Add(context.Background(), u)
+		assert.NoError(t, err)
+	}
+
+	contact, err := contactsRepo.Create(context.Background(), user1.ID, user2.ID)
+	assert.NoError(t, err)
+
+	found, err := contactsRepo.GetByID(context.Background(), contact.ID, user2.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, contact.ID, found.ID)
+	assert.Equal(t, "User 1", found.RequesterName)
+	assert.Equal(t, "User 2", found.RecipientName)
+
+	_, err = contactsRepo.GetByID(context.Background(), contact.ID, user3.ID)
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, repositories.ErrContactNotFound)
+}
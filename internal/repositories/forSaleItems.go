@@ -9,6 +9,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrForSaleItemNotFound is returned when no listing has the given ID
+var ErrForSaleItemNotFound = errors.New("for-sale item not found")
+
 type ForSaleItems struct {
 	db *sql.DB
 }
@@ -51,9 +54,18 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 			f.container_id,
 			f.division_number,
 			f.quantity_available,
+			f.quantity_available * COALESCE(t.packaged_volume, t.volume) AS volume,
+			` + pricingRulePrice("f", "price_per_unit") + ` AS effective_price_per_unit,
 			f.price_per_unit,
+			f.pricing_basis,
+			f.pricing_percent,
+			f.pricing_floor,
+			f.pricing_ceiling,
+			f.build_cost_per_unit,
 			f.notes,
 			f.is_active,
+			f.held_quantity,
+			f.stock_checked_at,
 			f.created_at,
 			f.updated_at
 		FROM for_sale_items f
@@ -62,6 +74,7 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 		LEFT JOIN player_corporations corp ON f.owner_type = 'corporation' AND f.owner_id = corp.id
 		LEFT JOIN solar_systems s ON f.location_id = s.solar_system_id
 		LEFT JOIN stations st ON f.location_id = st.station_id
+	` + pricingRuleJoins("f") + `
 		WHERE f.user_id = $1 AND f.is_active = true
 		ORDER BY f.created_at DESC
 	`
@@ -75,6 +88,7 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 	var items []*models.ForSaleItem
 	for rows.Next() {
 		var item models.ForSaleItem
+		var rule pricingRuleColumns
 		err = rows.Scan(
 			&item.ID,
 			&item.UserID,
@@ -88,15 +102,25 @@ func (r *ForSaleItems) GetByUser(ctx context.Context, userID int64) ([]*models.F
 			&item.ContainerID,
 			&item.DivisionNumber,
 			&item.QuantityAvailable,
+			&item.Volume,
 			&item.PricePerUnit,
+			&item.FixedPricePerUnit,
+			&rule.basis,
+			&rule.percent,
+			&rule.floor,
+			&rule.ceiling,
+			&item.BuildCostPerUnit,
 			&item.Notes,
 			&item.IsActive,
+			&item.HeldQuantity,
+			&item.StockCheckedAt,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan for-sale item")
 		}
+		item.PricingRule = rule.rule()
 		items = append(items, &item)
 	}
 
@@ -127,7 +151,13 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 			f.container_id,
 			f.division_number,
 			f.quantity_available,
+			f.quantity_available * COALESCE(t.packaged_volume, t.volume) AS volume,
+			` + pricingRulePrice("f", "price_per_unit") + ` AS effective_price_per_unit,
 			f.price_per_unit,
+			f.pricing_basis,
+			f.pricing_percent,
+			f.pricing_floor,
+			f.pricing_ceiling,
 			f.notes,
 			f.is_active,
 			f.created_at,
@@ -138,7 +168,9 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 		LEFT JOIN player_corporations corp ON f.owner_type = 'corporation' AND f.owner_id = corp.id
 		LEFT JOIN solar_systems s ON f.location_id = s.solar_system_id
 		LEFT JOIN stations st ON f.location_id = st.station_id
+	` + pricingRuleJoins("f") + `
 		WHERE f.user_id = ANY($1) AND f.is_active = true
+			AND ` + pricingRulePriced("f", "price_per_unit") + `
 		ORDER BY f.created_at DESC
 	`
 
@@ -151,6 +183,7 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 	var items []*models.ForSaleItem
 	for rows.Next() {
 		var item models.ForSaleItem
+		var rule pricingRuleColumns
 		err = rows.Scan(
 			&item.ID,
 			&item.UserID,
@@ -164,7 +197,13 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 			&item.ContainerID,
 			&item.DivisionNumber,
 			&item.QuantityAvailable,
+			&item.Volume,
 			&item.PricePerUnit,
+			&item.FixedPricePerUnit,
+			&rule.basis,
+			&rule.percent,
+			&rule.floor,
+			&rule.ceiling,
 			&item.Notes,
 			&item.IsActive,
 			&item.CreatedAt,
@@ -173,31 +212,82 @@ func (r *ForSaleItems) GetBrowsableItems(ctx context.Context, buyerUserID int64,
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan browsable item")
 		}
+		item.PricingRule = rule.rule()
 		items = append(items, &item)
 	}
 
 	return items, nil
 }
 
-// Upsert creates or updates a for-sale listing
+// Upsert creates or updates a for-sale listing. PricePerUnit is stored as the fixed
+// price; when a pricing rule is set it is the fallback for types without market data,
+// and PricePerUnit is updated to the rule's current price on return, along with Volume.
 func (r *ForSaleItems) Upsert(ctx context.Context, item *models.ForSaleItem) error {
+	return upsertForSaleItem(ctx, r.db, item)
+}
+
+// UpsertMany creates or updates several listings in a single transaction
+func (r *ForSaleItems) UpsertMany(ctx context.Context, items []*models.ForSaleItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	for _, item := range items {
+		err = upsertForSaleItem(ctx, tx, item)
+		if err != nil {
+			return errors.Wrapf(err, "failed to upsert listing for type %d", item.TypeID)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrap(err, "failed to commit listings")
+	}
+
+	return nil
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func upsertForSaleItem(ctx context.Context, db rowQuerier, item *models.ForSaleItem) error {
 	query := `
-		INSERT INTO for_sale_items
-		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number,
-		 quantity_available, price_per_unit, notes, is_active, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
-		ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0), COALESCE(division_number, 0))
-		WHERE is_active = true
-		DO UPDATE SET
-			quantity_available = EXCLUDED.quantity_available,
-			price_per_unit = EXCLUDED.price_per_unit,
-			notes = EXCLUDED.notes,
-			is_active = EXCLUDED.is_active,
-			updated_at = NOW()
-		RETURNING id, created_at, updated_at
-	`
+		WITH f AS (
+			INSERT INTO for_sale_items
+			(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number,
+			 quantity_available, price_per_unit, notes, is_active,
+			 pricing_basis, pricing_percent, pricing_floor, pricing_ceiling, build_cost_per_unit, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, NOW())
+			ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0), COALESCE(division_number, 0))
+			WHERE is_active = true
+			DO UPDATE SET
+				quantity_available = EXCLUDED.quantity_available,
+				price_per_unit = EXCLUDED.price_per_unit,
+				notes = EXCLUDED.notes,
+				is_active = EXCLUDED.is_active,
+				pricing_basis = EXCLUDED.pricing_basis,
+				pricing_percent = EXCLUDED.pricing_percent,
+				pricing_floor = EXCLUDED.pricing_floor,
+				pricing_ceiling = EXCLUDED.pricing_ceiling,
+				build_cost_per_unit = EXCLUDED.build_cost_per_unit,
+				updated_at = NOW()
+			RETURNING *
+		)
+		SELECT
+			f.id, f.created_at, f.updated_at, ` + pricingRulePrice("f", "price_per_unit") + `,
+			f.quantity_available * COALESCE(t.packaged_volume, t.volume, 0)
+		FROM f
+		LEFT JOIN asset_item_types t ON f.type_id = t.type_id
+	` + pricingRuleJoins("f")
 
-	err := r.db.QueryRowContext(ctx, query,
+	basis, percent, floor, ceiling := pricingRuleArgs(item.PricingRule)
+	item.FixedPricePerUnit = item.PricePerUnit
+
+	err := db.QueryRowContext(ctx, query,
 		item.UserID,
 		item.TypeID,
 		item.OwnerType,
@@ -209,7 +299,12 @@ func (r *ForSaleItems) Upsert(ctx context.Context, item *models.ForSaleItem) err
 		item.PricePerUnit,
 		item.Notes,
 		item.IsActive,
-	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt)
+		basis,
+		percent,
+		floor,
+		ceiling,
+		item.BuildCostPerUnit,
+	).Scan(&item.ID, &item.CreatedAt, &item.UpdatedAt, &item.PricePerUnit, &item.Volume)
 
 	if err != nil {
 		return errors.Wrap(err, "failed to upsert for-sale item")
@@ -222,7 +317,7 @@ func (r *ForSaleItems) Upsert(ctx context.Context, item *models.ForSaleItem) err
 func (r *ForSaleItems) Delete(ctx context.Context, itemID int64, userID int64) error {
 	query := `
 		UPDATE for_sale_items
-		SET is_active = false, updated_at = NOW()
+		SET is_active = false, deleted_at = NOW(), updated_at = NOW()
 		WHERE id = $1 AND user_id = $2
 	`
 
@@ -243,6 +338,47 @@ func (r *ForSaleItems) Delete(ctx context.Context, itemID int64, userID int64) e
 	return nil
 }
 
+// RestoreQuantity gives quantity back to a listing (within transaction), reactivating it if the
+// reservation had sold it out. Listings the seller removed are left alone, as are sold out listings
+// whose slot has since been taken by a new active listing. It reports whether the quantity was restored.
+func (r *ForSaleItems) RestoreQuantity(ctx context.Context, tx *sql.Tx, itemID int64, quantity int64) (bool, error) {
+	query := `
+		UPDATE for_sale_items f
+		SET quantity_available = CASE
+		                           WHEN f.is_active THEN f.quantity_available + $2
+		                           ELSE $2
+		                         END,
+		    is_active = true,
+		    updated_at = NOW()
+		WHERE f.id = $1
+		  AND f.deleted_at IS NULL
+		  AND (f.is_active OR NOT EXISTS (
+		    SELECT 1 FROM for_sale_items other
+		    WHERE other.is_active
+		      AND other.id <> f.id
+		      AND other.user_id = f.user_id
+		      AND other.type_id = f.type_id
+		      AND other.owner_type = f.owner_type
+		      AND other.owner_id = f.owner_id
+		      AND other.location_id = f.location_id
+		      AND COALESCE(other.container_id, 0) = COALESCE(f.container_id, 0)
+		      AND COALESCE(other.division_number, 0) = COALESCE(f.division_number, 0)
+		  ))
+	`
+
+	result, err := tx.ExecContext(ctx, query, itemID, quantity)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to restore for-sale item quantity")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
 // UpdateQuantity decreases quantity after purchase (within transaction)
 func (r *ForSaleItems) UpdateQuantity(ctx context.Context, tx *sql.Tx, itemID int64, newQuantity int64) error {
 	// When newQuantity <= 0, only mark as inactive without updating quantity
@@ -295,21 +431,33 @@ func (r *ForSaleItems) GetByID(ctx context.Context, itemID int64) (*models.ForSa
 			f.container_id,
 			f.division_number,
 			f.quantity_available,
+			f.quantity_available * COALESCE(t.packaged_volume, t.volume) AS volume,
+			` + pricingRulePrice("f", "price_per_unit") + ` AS effective_price_per_unit,
 			f.price_per_unit,
+			f.pricing_basis,
+			f.pricing_percent,
+			f.pricing_floor,
+			f.pricing_ceiling,
+			f.build_cost_per_unit,
 			f.notes,
 			f.is_active,
+			f.held_quantity,
+			f.stock_checked_at,
 			f.created_at,
-			f.updated_at
+			f.updated_at,
+			f.deleted_at
 		FROM for_sale_items f
 		JOIN asset_item_types t ON f.type_id = t.type_id
 		LEFT JOIN characters c ON f.owner_type = 'character' AND f.owner_id = c.id
 		LEFT JOIN player_corporations corp ON f.owner_type = 'corporation' AND f.owner_id = corp.id
 		LEFT JOIN solar_systems s ON f.location_id = s.solar_system_id
 		LEFT JOIN stations st ON f.location_id = st.station_id
+	` + pricingRuleJoins("f") + `
 		WHERE f.id = $1
 	`
 
 	var item models.ForSaleItem
+	var rule pricingRuleColumns
 	err := r.db.QueryRowContext(ctx, query, itemID).Scan(
 		&item.ID,
 		&item.UserID,
@@ -323,19 +471,213 @@ func (r *ForSaleItems) GetByID(ctx context.Context, itemID int64) (*models.ForSa
 		&item.ContainerID,
 		&item.DivisionNumber,
 		&item.QuantityAvailable,
+		&item.Volume,
 		&item.PricePerUnit,
+		&item.FixedPricePerUnit,
+		&rule.basis,
+		&rule.percent,
+		&rule.floor,
+		&rule.ceiling,
+		&item.BuildCostPerUnit,
 		&item.Notes,
 		&item.IsActive,
+		&item.HeldQuantity,
+		&item.StockCheckedAt,
 		&item.CreatedAt,
 		&item.UpdatedAt,
+		&item.DeletedAt,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, errors.New("for-sale item not found")
+		return nil, ErrForSaleItemNotFound
 	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get for-sale item")
 	}
+	item.PricingRule = rule.rule()
 
 	return &item, nil
 }
+
+// listingStockJoins resolves, for each listing row f, the quantity its owner
+// actually holds at the listing's location/container/division and the quantity
+// already reserved by pending purchases that has not yet left the hangar
+const listingStockJoins = `
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(SUM(held.quantity), 0) AS quantity
+			FROM (
+				-- Character hangar
+				SELECT ca.quantity
+				FROM character_assets ca
+				WHERE f.owner_type = 'character'
+				  AND f.container_id IS NULL
+				  AND ca.user_id = f.user_id
+				  AND ca.character_id = f.owner_id
+				  AND ca.type_id = f.type_id
+				  AND ca.location_id = f.location_id
+				  AND ca.location_flag IN ('Hangar', 'Deliveries')
+
+				UNION ALL
+
+				-- Character container
+				SELECT ca.quantity
+				FROM character_assets ca
+				INNER JOIN character_assets containers ON (
+					containers.item_id = ca.location_id
+					AND containers.character_id = ca.character_id
+					AND containers.user_id = ca.user_id
+				)
+				WHERE f.owner_type = 'character'
+				  AND f.container_id IS NOT NULL
+				  AND ca.user_id = f.user_id
+				  AND ca.character_id = f.owner_id
+				  AND ca.type_id = f.type_id
+				  AND ca.location_id = f.container_id
+				  AND containers.location_id = f.location_id
+
+				UNION ALL
+
+				-- Corporation hangar division (no division listed matches any division)
+				SELECT ca.quantity
+				FROM corporation_assets ca
+				WHERE f.owner_type = 'corporation'
+				  AND f.container_id IS NULL
+				  AND ca.user_id = f.user_id
+				  AND ca.corporation_id = f.owner_id
+				  AND ca.type_id = f.type_id
+				  AND ca.location_id = f.location_id
+				  AND ca.location_flag LIKE 'CorpSAG%'
+				  AND (f.division_number IS NULL OR ca.location_flag = 'CorpSAG' || f.division_number)
+
+				UNION ALL
+
+				-- Corporation container (using view for location resolution)
+				SELECT ca.quantity
+				FROM corporation_asset_locations loc
+				INNER JOIN corporation_assets ca ON (
+					ca.item_id = loc.item_id
+					AND ca.corporation_id = loc.corporation_id
+					AND ca.user_id = loc.user_id
+				)
+				WHERE f.owner_type = 'corporation'
+				  AND f.container_id IS NOT NULL
+				  AND loc.user_id = f.user_id
+				  AND loc.corporation_id = f.owner_id
+				  AND loc.type_id = f.type_id
+				  AND loc.container_id = f.container_id
+				  AND loc.station_id = f.location_id
+			) held
+		) stock ON true
+		LEFT JOIN LATERAL (
+			SELECT COALESCE(SUM(pt.quantity_purchased), 0) AS quantity
+			FROM purchase_transactions pt
+			WHERE pt.for_sale_item_id = f.id
+			  AND pt.status = 'pending'
+		) reserved ON true
+`
+
+// GetAvailableStock returns how many units of the listing's type the owner could
+// still list at its location: held quantity minus pending reservations on the listing
+func (r *ForSaleItems) GetAvailableStock(ctx context.Context, item *models.ForSaleItem) (int64, error) {
+	var itemID *int64
+	if item.ID != 0 {
+		itemID = &item.ID
+	}
+
+	query := `
+		SELECT stock.quantity - reserved.quantity
+		FROM (
+			SELECT
+				$1::bigint AS id,
+				$2::bigint AS user_id,
+				$3::bigint AS type_id,
+				$4::varchar AS owner_type,
+				$5::bigint AS owner_id,
+				$6::bigint AS location_id,
+				$7::bigint AS container_id,
+				$8::int AS division_number
+		) f
+	` + listingStockJoins
+
+	var available int64
+	err := r.db.QueryRowContext(ctx, query,
+		itemID,
+		item.UserID,
+		item.TypeID,
+		item.OwnerType,
+		item.OwnerID,
+		item.LocationID,
+		item.ContainerID,
+		item.DivisionNumber,
+	).Scan(&available)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get available stock")
+	}
+
+	return available, nil
+}
+
+// GetStockLevels returns held and reserved quantities for all of a user's active listings
+func (r *ForSaleItems) GetStockLevels(ctx context.Context, userID int64) ([]*models.ForSaleStockLevel, error) {
+	query := `
+		SELECT
+			f.id,
+			f.quantity_available,
+			stock.quantity,
+			reserved.quantity
+		FROM for_sale_items f
+	` + listingStockJoins + `
+		WHERE f.user_id = $1 AND f.is_active = true
+		ORDER BY f.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query for-sale stock levels")
+	}
+	defer rows.Close()
+
+	levels := []*models.ForSaleStockLevel{}
+	for rows.Next() {
+		var level models.ForSaleStockLevel
+		err = rows.Scan(
+			&level.ForSaleItemID,
+			&level.QuantityAvailable,
+			&level.HeldQuantity,
+			&level.ReservedQuantity,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan for-sale stock level")
+		}
+		levels = append(levels, &level)
+	}
+
+	return levels, nil
+}
+
+// ApplyStockLevel records the held quantity from an asset sync and adjusts the listing.
+// newQuantity <= 0 leaves quantity untouched (see UpdateQuantity); isActive=false deactivates it.
+func (r *ForSaleItems) ApplyStockLevel(ctx context.Context, itemID int64, heldQuantity int64, newQuantity int64, isActive bool) error {
+	query := `
+		UPDATE for_sale_items
+		SET held_quantity = $2::bigint,
+		    stock_checked_at = NOW(),
+		    quantity_available = CASE
+		                           WHEN $3::bigint > 0 THEN $3::bigint
+		                           ELSE quantity_available
+		                         END,
+		    is_active = $4::boolean,
+		    updated_at = CASE
+		                   WHEN ($3::bigint > 0 AND $3::bigint <> quantity_available) OR is_active <> $4::boolean THEN NOW()
+		                   ELSE updated_at
+		                 END
+		WHERE id = $1 AND is_active = true
+	`
+
+	_, err := r.db.ExecContext(ctx, query, itemID, heldQuantity, newQuantity, isActive)
+	if err != nil {
+		return errors.Wrap(err, "failed to apply for-sale stock level")
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
@@ -97,9 +98,49 @@ func Test_ForSaleItemsShouldGetByUser(t *testing.T) {
 	assert.Equal(t, "Test Character", items[0].OwnerName)
 	assert.Equal(t, "Jita", items[0].LocationName)
 	assert.Equal(t, int64(2000), items[0].QuantityAvailable)
+	assert.InDelta(t, 20.0, items[0].Volume, 0.0001)
 	assert.Equal(t, int64(100), items[0].PricePerUnit)
 }
 
+func Test_ForSaleItemsShouldUsePackagedVolume(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupForSaleTestData(t, db, 2110, 21100, 34, 30000142)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	itemTypesRepo := repositories.NewItemTypeRepository(db)
+
+	packaged := 2500.0
+	err = itemTypesRepo.UpsertItemTypes(context.Background(), []models.EveInventoryType{
+		{TypeID: 587, TypeName: "Rifter", Volume: 27289, PackagedVolume: &packaged},
+	})
+	assert.NoError(t, err)
+
+	item := &models.ForSaleItem{
+		UserID:            2110,
+		TypeID:            587,
+		OwnerType:         "character",
+		OwnerID:           21100,
+		LocationID:        30000142,
+		QuantityAvailable: 3,
+		PricePerUnit:      500000,
+		IsActive:          true,
+	}
+
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, 7500.0, item.Volume)
+
+	items, err := forSaleRepo.GetByUser(context.Background(), 2110)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, 7500.0, items[0].Volume)
+
+	retrieved, err := forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 7500.0, retrieved.Volume)
+}
+
 func Test_ForSaleItemsShouldDelete(t *testing.T) {
 	db, err := setupDatabase()
 	assert.NoError(t, err)
@@ -227,3 +268,352 @@ func Test_ForSaleItemsUpdateQuantityPartial_ShouldUpdateQuantity(t *testing.T) {
 	assert.True(t, updated.IsActive)
 	assert.Equal(t, int64(250), updated.QuantityAvailable)
 }
+
+func Test_ForSaleItemsStockLevels_ShouldCompareAgainstAssets(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupForSaleTestData(t, db, 2400, 24000, 38, 30000146)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	assetsRepo := repositories.NewCharacterAssets(db)
+
+	// 300 units in the hangar, 50 more in a different station
+	err = assetsRepo.UpdateAssets(context.Background(), 24000, 2400, []*models.EveAsset{
+		{ItemID: 1, LocationFlag: "Hangar", LocationID: 30000146, LocationType: "station", Quantity: 200, TypeID: 38},
+		{ItemID: 2, LocationFlag: "Hangar", LocationID: 30000146, LocationType: "station", Quantity: 100, TypeID: 38},
+		{ItemID: 3, LocationFlag: "Hangar", LocationID: 60003760, LocationType: "station", Quantity: 50, TypeID: 38},
+	})
+	assert.NoError(t, err)
+
+	item := &models.ForSaleItem{
+		UserID:            2400,
+		TypeID:            38,
+		OwnerType:         "character",
+		OwnerID:           24000,
+		LocationID:        30000146,
+		QuantityAvailable: 500,
+		PricePerUnit:      10,
+		IsActive:          true,
+	}
+
+	available, err := forSaleRepo.GetAvailableStock(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(300), available)
+
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+
+	levels, err := forSaleRepo.GetStockLevels(context.Background(), 2400)
+	assert.NoError(t, err)
+	assert.Len(t, levels, 1)
+	assert.Equal(t, item.ID, levels[0].ForSaleItemID)
+	assert.Equal(t, int64(500), levels[0].QuantityAvailable)
+	assert.Equal(t, int64(300), levels[0].HeldQuantity)
+	assert.Equal(t, int64(0), levels[0].ReservedQuantity)
+
+	// Trim to held stock
+	err = forSaleRepo.ApplyStockLevel(context.Background(), item.ID, 300, 300, true)
+	assert.NoError(t, err)
+
+	retrieved, err := forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(300), retrieved.QuantityAvailable)
+	assert.True(t, retrieved.IsActive)
+	assert.NotNil(t, retrieved.HeldQuantity)
+	assert.Equal(t, int64(300), *retrieved.HeldQuantity)
+	assert.NotNil(t, retrieved.StockCheckedAt)
+
+	// Items gone - deactivate
+	err = forSaleRepo.ApplyStockLevel(context.Background(), item.ID, 0, 300, false)
+	assert.NoError(t, err)
+
+	retrieved, err = forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.False(t, retrieved.IsActive)
+}
+
+func Test_ForSaleItemsStockLevels_CorporationListingWithoutDivisionMatchesAnyDivision(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupForSaleTestData(t, db, 2410, 24100, 34, 30000142)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	playerCorpsRepo := repositories.NewPlayerCorporations(db)
+	corpAssetsRepo := repositories.NewCorporationAssets(db)
+
+	corp := repositories.PlayerCorporation{
+		ID:              2410001,
+		UserID:          2410,
+		Name:            "Division Corp",
+		EsiToken:        "token",
+		EsiRefreshToken: "refresh",
+		EsiExpiresOn:    time.Now().Add(time.Hour),
+	}
+	err = playerCorpsRepo.Upsert(context.Background(), corp)
+	assert.NoError(t, err)
+
+	err = corpAssetsRepo.Upsert(context.Background(), corp.ID, 2410, []*models.EveAsset{
+		{ItemID: 2410101, LocationFlag: "CorpSAG1", LocationID: 60003760, LocationType: "station", Quantity: 100, TypeID: 34},
+		{ItemID: 2410102, LocationFlag: "CorpSAG3", LocationID: 60003760, LocationType: "station", Quantity: 50, TypeID: 34},
+		{ItemID: 2410103, LocationFlag: "CorpDeliveries", LocationID: 60003760, LocationType: "station", Quantity: 25, TypeID: 34},
+	})
+	assert.NoError(t, err)
+
+	division := 3
+	item := &models.ForSaleItem{
+		UserID:            2410,
+		TypeID:            34,
+		OwnerType:         "corporation",
+		OwnerID:           corp.ID,
+		LocationID:        60003760,
+		DivisionNumber:    &division,
+		QuantityAvailable: 200,
+		PricePerUnit:      10,
+		IsActive:          true,
+	}
+
+	available, err := forSaleRepo.GetAvailableStock(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(50), available)
+
+	// No division listed - every hangar division backs the listing
+	item.DivisionNumber = nil
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+
+	levels, err := forSaleRepo.GetStockLevels(context.Background(), 2410)
+	assert.NoError(t, err)
+	assert.Len(t, levels, 1)
+	assert.Equal(t, item.ID, levels[0].ForSaleItemID)
+	assert.Equal(t, int64(150), levels[0].HeldQuantity)
+}
+
+func Test_ForSaleItemsPricingRule_ShouldEvaluateAgainstMarketPrices(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupForSaleTestData(t, db, 2500, 25000, 39, 30000147)
+	forSaleRepo := repositories.NewForSaleItems(db)
+	marketPricesRepo := repositories.NewMarketPrices(db)
+
+	buy, sell := 900.0, 1100.0
+	err = marketPricesRepo.UpsertPrices(context.Background(), []models.MarketPrice{
+		{TypeID: 39, RegionID: 10000002, BuyPrice: &buy, SellPrice: &sell},
+	})
+	assert.NoError(t, err)
+
+	ceiling := int64(980)
+	item := &models.ForSaleItem{
+		UserID:            2500,
+		TypeID:            39,
+		OwnerType:         "character",
+		OwnerID:           25000,
+		LocationID:        30000147,
+		QuantityAvailable: 10,
+		PricePerUnit:      500,
+		PricingRule:       &models.PricingRule{Basis: "split", Percent: 95},
+		IsActive:          true,
+	}
+
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(950), item.PricePerUnit)
+	assert.Equal(t, int64(500), item.FixedPricePerUnit)
+
+	browsable, err := forSaleRepo.GetBrowsableItems(context.Background(), 1, []int64{2500})
+	assert.NoError(t, err)
+	assert.Len(t, browsable, 1)
+	assert.Equal(t, int64(950), browsable[0].PricePerUnit)
+	assert.Equal(t, "split", browsable[0].PricingRule.Basis)
+
+	// Clamped to the ceiling
+	item.PricingRule = &models.PricingRule{Basis: "sell", Percent: 100, Ceiling: &ceiling}
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+
+	retrieved, err := forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(980), retrieved.PricePerUnit)
+	assert.Equal(t, int64(500), retrieved.FixedPricePerUnit)
+
+	// Without a rule the fixed price applies
+	item.PricingRule = nil
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+
+	retrieved, err = forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(500), retrieved.PricePerUnit)
+	assert.Nil(t, retrieved.PricingRule)
+
+	// A zero Jita price keeps the fixed price instead of repricing to 0 ISK
+	zero := 0.0
+	err = marketPricesRepo.UpsertPrices(context.Background(), []models.MarketPrice{
+		{TypeID: 39, RegionID: 10000002, BuyPrice: &zero, SellPrice: &sell},
+	})
+	assert.NoError(t, err)
+
+	for _, basis := range []string{"buy", "split"} {
+		item.PricingRule = &models.PricingRule{Basis: basis, Percent: 100}
+		err = forSaleRepo.Upsert(context.Background(), item)
+		assert.NoError(t, err)
+
+		retrieved, err = forSaleRepo.GetByID(context.Background(), item.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(500), retrieved.PricePerUnit, basis)
+	}
+
+	// The fallback is still held to the rule's floor
+	floor := int64(700)
+	item.PricingRule = &models.PricingRule{Basis: "buy", Percent: 100, Floor: &floor}
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+
+	retrieved, err = forSaleRepo.GetByID(context.Background(), item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(700), retrieved.PricePerUnit)
+
+	// A rule saved with nothing to fall back on is not offered rather than sold for free
+	item.PricePerUnit = 0
+	item.PricingRule = &models.PricingRule{Basis: "buy", Percent: 100}
+	err = forSaleRepo.Upsert(context.Background(), item)
+	assert.NoError(t, err)
+
+	browsable, err = forSaleRepo.GetBrowsableItems(context.Background(), 1, []int64{2500})
+	assert.NoError(t, err)
+	assert.Empty(t, browsable)
+}
+
+func Test_ForSaleItemsUpsertMany_ShouldCreateAllOrNothing(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupForSaleTestData(t, db, 2600, 26000, 40, 30000148)
+	forSaleRepo := repositories.NewForSaleItems(db)
+
+	items := []*models.ForSaleItem{
+		{UserID: 2600, TypeID: 40, OwnerType: "character", OwnerID: 26000, LocationID: 30000148, QuantityAvailable: 10, PricePerUnit: 100, IsActive: true},
+		{UserID: 2600, TypeID: 40, OwnerType: "character", OwnerID: 26000, LocationID: 60003760, QuantityAvailable: 20, PricePerUnit: 100, IsActive: true},
+	}
+
+	err = forSaleRepo.UpsertMany(context.Background(), items)
+	assert.NoError(t, err)
+	assert.NotZero(t, items[0].ID)
+	assert.NotZero(t, items[1].ID)
+
+	listings, err := forSaleRepo.GetByUser(context.Background(), 2600)
+	assert.NoError(t, err)
+	assert.Len(t, listings, 2)
+
+	// A failing row (zero quantity violates the positive quantity constraint) rolls back the whole batch
+	failing := []*models.ForSaleItem{
+		{UserID: 2600, TypeID: 40, OwnerType: "character", OwnerID: 26000, LocationID: 30000148, QuantityAvailable: 99, PricePerUnit: 100, IsActive: true},
+		{UserID: 2600, TypeID: 40, OwnerType: "character", OwnerID: 26000, LocationID: 60008494, QuantityAvailable: 0, PricePerUnit: 100, IsActive: true},
+	}
+	err = forSaleRepo.UpsertMany(context.Background(), failing)
+	assert.Error(t, err)
+
+	retrieved, err := forSaleRepo.GetByID(context.Background(), items[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), retrieved.QuantityAvailable)
+}
+
+func Test_ForSaleItems_RestoreQuantity(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	item, err := setupPurchaseTestData(t, db, 3200, 3201, 60, 30000170)
+	assert.NoError(t, err)
+
+	repo := repositories.NewForSaleItems(db)
+
+	restore := func(itemID, quantity int64) bool {
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		restored, err := repo.RestoreQuantity(ctx, tx, itemID, quantity)
+		assert.NoError(t, err)
+		assert.NoError(t, tx.Commit())
+		return restored
+	}
+
+	// A reservation for all 1000 sells the listing out
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.UpdateQuantity(ctx, tx, item.ID, 0))
+	assert.NoError(t, tx.Commit())
+
+	soldOut, err := repo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.False(t, soldOut.IsActive)
+	assert.Nil(t, soldOut.DeletedAt)
+
+	// Expiring it brings the listing back with only the reserved quantity
+	assert.True(t, restore(item.ID, 1000))
+	restoredItem, err := repo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.True(t, restoredItem.IsActive)
+	assert.Equal(t, int64(1000), restoredItem.QuantityAvailable)
+
+	// Active listings get the quantity added
+	assert.True(t, restore(item.ID, 50))
+	restoredItem, err = repo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1050), restoredItem.QuantityAvailable)
+
+	// Removed listings stay removed
+	assert.NoError(t, repo.Delete(ctx, item.ID, 3201))
+	deleted, err := repo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+	assert.False(t, restore(item.ID, 10))
+
+	deleted, err = repo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.False(t, deleted.IsActive)
+
+	_, err = repo.GetByID(ctx, 999999)
+	assert.ErrorIs(t, err, repositories.ErrForSaleItemNotFound)
+}
+
+func Test_ForSaleItems_RestoreQuantitySkipsReplacedListing(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	item, err := setupPurchaseTestData(t, db, 3210, 3211, 61, 30000171)
+	assert.NoError(t, err)
+
+	repo := repositories.NewForSaleItems(db)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.UpdateQuantity(ctx, tx, item.ID, 0))
+	assert.NoError(t, tx.Commit())
+
+	// The seller lists the same stack again before the reservation expires
+	replacement := &models.ForSaleItem{
+		UserID:            3211,
+		TypeID:            61,
+		OwnerType:         "character",
+		OwnerID:           32110,
+		LocationID:        30000171,
+		QuantityAvailable: 200,
+		PricePerUnit:      100,
+		IsActive:          true,
+	}
+	assert.NoError(t, repo.Upsert(ctx, replacement))
+
+	tx, err = db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	restored, err := repo.RestoreQuantity(ctx, tx, item.ID, 1000)
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.False(t, restored)
+
+	original, err := repo.GetByID(ctx, item.ID)
+	assert.NoError(t, err)
+	assert.False(t, original.IsActive)
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrHaulingRequestNotFound is returned when a hauling request does not exist
+	ErrHaulingRequestNotFound = errors.New("hauling request not found")
+
+	// ErrHaulingRequestStatusChanged is returned when a hauling request left the expected status before it could be updated
+	ErrHaulingRequestStatusChanged = errors.New("hauling request status changed")
+)
+
+type HaulingRequests struct {
+	db *sql.DB
+}
+
+func NewHaulingRequests(db *sql.DB) *HaulingRequests {
+	return &HaulingRequests{db: db}
+}
+
+const haulingRequestColumns = `
+			id,
+			requester_user_id,
+			hauler_user_id,
+			origin_location_id,
+			destination_location_id,
+			volume_m3,
+			collateral,
+			notes,
+			status,
+			created_at,
+			updated_at
+`
+
+func scanHaulingRequest(scanner interface{ Scan(...any) error }) (*models.HaulingRequest, error) {
+	request := &models.HaulingRequest{}
+	err := scanner.Scan(
+		&request.ID,
+		&request.RequesterUserID,
+		&request.HaulerUserID,
+		&request.OriginLocationID,
+		&request.DestinationLocationID,
+		&request.VolumeM3,
+		&request.Collateral,
+		&request.Notes,
+		&request.Status,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	return request, err
+}
+
+// Create stores a new pending hauling request
+func (r *HaulingRequests) Create(ctx context.Context, request *models.HaulingRequest) error {
+	query := `
+		INSERT INTO hauling_requests (
+			requester_user_id,
+			hauler_user_id,
+			origin_location_id,
+			destination_location_id,
+			volume_m3,
+			collateral,
+			notes,
+			status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		request.RequesterUserID,
+		request.HaulerUserID,
+		request.OriginLocationID,
+		request.DestinationLocationID,
+		request.VolumeM3,
+		request.Collateral,
+		request.Notes,
+		models.ServiceRequestPending,
+	).Scan(&request.ID, &request.Status, &request.CreatedAt, &request.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create hauling request")
+	}
+
+	return nil
+}
+
+// GetByID returns a hauling request
+func (r *HaulingRequests) GetByID(ctx context.Context, id int64) (*models.HaulingRequest, error) {
+	query := `
+		SELECT` + haulingRequestColumns + `
+		FROM hauling_requests
+		WHERE id = $1
+	`
+
+	request, err := scanHaulingRequest(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrHaulingRequestNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get hauling request")
+	}
+
+	return request, nil
+}
+
+// GetByUser returns the hauling requests a user sent or received, newest first
+func (r *HaulingRequests) GetByUser(ctx context.Context, userID int64) ([]*models.HaulingRequest, error) {
+	query := `
+		SELECT` + haulingRequestColumns + `
+		FROM hauling_requests
+		WHERE requester_user_id = $1 OR hauler_user_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query hauling requests")
+	}
+	defer rows.Close()
+
+	requests := []*models.HaulingRequest{}
+	for rows.Next() {
+		request, err := scanHaulingRequest(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan hauling request")
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+// UpdateStatus moves a hauling request from one status to another. It returns
+// ErrHaulingRequestStatusChanged if the request is no longer in fromStatus.
+func (r *HaulingRequests) UpdateStatus(ctx context.Context, id int64, fromStatus, toStatus string) error {
+	query := `
+		UPDATE hauling_requests
+		SET status = $3, updated_at = NOW()
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, fromStatus, toStatus)
+	if err != nil {
+		return errors.Wrap(err, "failed to update hauling request status")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrHaulingRequestStatusChanged
+	}
+
+	return nil
+}
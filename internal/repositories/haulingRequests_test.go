@@ -0,0 +1,58 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HaulingRequestsShouldCreateListAndUpdate(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	repo := repositories.NewHaulingRequests(db)
+
+	requester := &repositories.User{ID: 3210, Name: "Requester"}
+	hauler := &repositories.User{ID: 3211, Name: "Hauler"}
+	for _, u := range []*repositories.User{requester, hauler} {
+		assert.NoError(t, userRepo.Add(ctx, u))
+	}
+
+	request := &models.HaulingRequest{
+		RequesterUserID:       requester.ID,
+		HaulerUserID:          hauler.ID,
+		OriginLocationID:      60003760,
+		DestinationLocationID: 60008494,
+		VolumeM3:              12500.5,
+		Collateral:            1000000000,
+	}
+	err = repo.Create(ctx, request)
+	assert.NoError(t, err)
+	assert.NotZero(t, request.ID)
+	assert.Equal(t, models.ServiceRequestPending, request.Status)
+
+	requests, err := repo.GetByUser(ctx, hauler.ID)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, 12500.5, requests[0].VolumeM3)
+	assert.Equal(t, int64(1000000000), requests[0].Collateral)
+	assert.Nil(t, requests[0].Notes)
+
+	err = repo.UpdateStatus(ctx, request.ID, models.ServiceRequestPending, models.ServiceRequestCancelled)
+	assert.NoError(t, err)
+
+	err = repo.UpdateStatus(ctx, request.ID, models.ServiceRequestPending, models.ServiceRequestAccepted)
+	assert.ErrorIs(t, err, repositories.ErrHaulingRequestStatusChanged)
+
+	retrieved, err := repo.GetByID(ctx, request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ServiceRequestCancelled, retrieved.Status)
+
+	_, err = repo.GetByID(ctx, request.ID+1000)
+	assert.ErrorIs(t, err, repositories.ErrHaulingRequestNotFound)
+}
@@ -0,0 +1,286 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+// ItemMetadata stores the categories, groups, market groups and meta groups item types belong to
+type ItemMetadata struct {
+	db *sql.DB
+}
+
+func NewItemMetadata(db *sql.DB) *ItemMetadata {
+	return &ItemMetadata{
+		db: db,
+	}
+}
+
+func (r *ItemMetadata) UpsertCategories(ctx context.Context, categories []models.ItemCategory) error {
+	upsertQuery := `
+insert into
+	item_categories
+	(
+		category_id,
+		name,
+		published
+	)
+	values
+		($1,$2,$3)
+on conflict
+	(category_id)
+do update set
+	name = EXCLUDED.name,
+	published = EXCLUDED.published;
+`
+
+	return r.upsert(ctx, "category", upsertQuery, len(categories), func(i int) []any {
+		return []any{categories[i].ID, categories[i].Name, categories[i].Published}
+	})
+}
+
+func (r *ItemMetadata) UpsertGroups(ctx context.Context, groups []models.ItemGroup) error {
+	upsertQuery := `
+insert into
+	item_groups
+	(
+		group_id,
+		category_id,
+		name,
+		published
+	)
+	values
+		($1,$2,$3,$4)
+on conflict
+	(group_id)
+do update set
+	category_id = EXCLUDED.category_id,
+	name = EXCLUDED.name,
+	published = EXCLUDED.published;
+`
+
+	return r.upsert(ctx, "group", upsertQuery, len(groups), func(i int) []any {
+		return []any{groups[i].ID, groups[i].CategoryID, groups[i].Name, groups[i].Published}
+	})
+}
+
+func (r *ItemMetadata) UpsertMarketGroups(ctx context.Context, marketGroups []models.MarketGroup) error {
+	upsertQuery := `
+insert into
+	market_groups
+	(
+		market_group_id,
+		parent_group_id,
+		name,
+		has_types
+	)
+	values
+		($1,$2,$3,$4)
+on conflict
+	(market_group_id)
+do update set
+	parent_group_id = EXCLUDED.parent_group_id,
+	name = EXCLUDED.name,
+	has_types = EXCLUDED.has_types;
+`
+
+	return r.upsert(ctx, "market group", upsertQuery, len(marketGroups), func(i int) []any {
+		return []any{marketGroups[i].ID, marketGroups[i].ParentID, marketGroups[i].Name, marketGroups[i].HasTypes}
+	})
+}
+
+func (r *ItemMetadata) UpsertMetaGroups(ctx context.Context, metaGroups []models.MetaGroup) error {
+	upsertQuery := `
+insert into
+	meta_groups
+	(
+		meta_group_id,
+		name
+	)
+	values
+		($1,$2)
+on conflict
+	(meta_group_id)
+do update set
+	name = EXCLUDED.name;
+`
+
+	return r.upsert(ctx, "meta group", upsertQuery, len(metaGroups), func(i int) []any {
+		return []any{metaGroups[i].ID, metaGroups[i].Name}
+	})
+}
+
+// upsert runs query once per row in a single transaction
+func (r *ItemMetadata) upsert(ctx context.Context, name, query string, count int, row func(i int) []any) error {
+	if count == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "failed to begin transaction for item %s upsert", name)
+	}
+	defer tx.Rollback()
+
+	smt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return errors.Wrapf(err, "failed to prepare for item %s upsert", name)
+	}
+
+	for i := 0; i < count; i++ {
+		_, err = smt.ExecContext(ctx, row(i)...)
+		if err != nil {
+			return errors.Wrapf(err, "failed to execute item %s upsert", name)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.Wrapf(err, "failed to commit item %s transaction", name)
+	}
+
+	return nil
+}
+
+// GetCategories returns the published categories by name
+func (r *ItemMetadata) GetCategories(ctx context.Context) ([]*models.ItemCategory, error) {
+	query := `
+		SELECT category_id, name, published
+		FROM item_categories
+		WHERE published
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query item categories")
+	}
+	defer rows.Close()
+
+	categories := []*models.ItemCategory{}
+	for rows.Next() {
+		category := &models.ItemCategory{}
+		err = rows.Scan(&category.ID, &category.Name, &category.Published)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan item category")
+		}
+		categories = append(categories, category)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating item categories")
+	}
+
+	return categories, nil
+}
+
+// GetGroups returns the published groups by name, limited to the categories given if any
+func (r *ItemMetadata) GetGroups(ctx context.Context, categoryIDs []int64) ([]*models.ItemGroup, error) {
+	query := `
+		SELECT group_id, category_id, name, published
+		FROM item_groups
+		WHERE published
+			AND (cardinality($1::BIGINT[]) = 0 OR category_id = ANY($1))
+		ORDER BY name
+	`
+
+	if categoryIDs == nil {
+		categoryIDs = []int64{}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(categoryIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query item groups")
+	}
+	defer rows.Close()
+
+	groups := []*models.ItemGroup{}
+	for rows.Next() {
+		group := &models.ItemGroup{}
+		err = rows.Scan(&group.ID, &group.CategoryID, &group.Name, &group.Published)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan item group")
+		}
+		groups = append(groups, group)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating item groups")
+	}
+
+	return groups, nil
+}
+
+// GetMarketGroups returns the whole market group tree as a flat list, parents before children
+func (r *ItemMetadata) GetMarketGroups(ctx context.Context) ([]*models.MarketGroup, error) {
+	query := `
+		WITH RECURSIVE tree AS (
+			SELECT market_group_id, parent_group_id, name, has_types, 0 AS depth
+			FROM market_groups
+			WHERE parent_group_id IS NULL
+			UNION ALL
+			SELECT children.market_group_id, children.parent_group_id, children.name, children.has_types, tree.depth + 1
+			FROM market_groups children
+			INNER JOIN tree ON children.parent_group_id = tree.market_group_id
+		)
+		SELECT market_group_id, parent_group_id, name, has_types
+		FROM tree
+		ORDER BY depth, name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query market groups")
+	}
+	defer rows.Close()
+
+	marketGroups := []*models.MarketGroup{}
+	for rows.Next() {
+		marketGroup := &models.MarketGroup{}
+		err = rows.Scan(&marketGroup.ID, &marketGroup.ParentID, &marketGroup.Name, &marketGroup.HasTypes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan market group")
+		}
+		marketGroups = append(marketGroups, marketGroup)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating market groups")
+	}
+
+	return marketGroups, nil
+}
+
+func (r *ItemMetadata) GetMetaGroups(ctx context.Context) ([]*models.MetaGroup, error) {
+	query := `
+		SELECT meta_group_id, name
+		FROM meta_groups
+		ORDER BY meta_group_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query meta groups")
+	}
+	defer rows.Close()
+
+	metaGroups := []*models.MetaGroup{}
+	for rows.Next() {
+		metaGroup := &models.MetaGroup{}
+		err = rows.Scan(&metaGroup.ID, &metaGroup.Name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan meta group")
+		}
+		metaGroups = append(metaGroups, metaGroup)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating meta groups")
+	}
+
+	return metaGroups, nil
+}
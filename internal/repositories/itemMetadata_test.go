@@ -0,0 +1,127 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupItemMetadata(t *testing.T, metadataRepo *repositories.ItemMetadata, itemTypeRepo *repositories.ItemTypeRepository) {
+	ctx := context.Background()
+
+	err := metadataRepo.UpsertCategories(ctx, []models.ItemCategory{
+		{ID: 4, Name: "Material", Published: true},
+		{ID: 7, Name: "Module", Published: true},
+		{ID: 99, Name: "Unpublished", Published: false},
+	})
+	assert.NoError(t, err)
+
+	err = metadataRepo.UpsertGroups(ctx, []models.ItemGroup{
+		{ID: 18, CategoryID: 4, Name: "Mineral", Published: true},
+		{ID: 60, CategoryID: 7, Name: "Damage Control", Published: true},
+	})
+	assert.NoError(t, err)
+
+	shipEquipment := int64(9)
+	hullRepairs := int64(615)
+	err = metadataRepo.UpsertMarketGroups(ctx, []models.MarketGroup{
+		{ID: 9, Name: "Ship Equipment"},
+		{ID: 615, ParentID: &shipEquipment, Name: "Hull & Armor"},
+		{ID: 1054, ParentID: &hullRepairs, Name: "Damage Controls", HasTypes: true},
+	})
+	assert.NoError(t, err)
+
+	err = metadataRepo.UpsertMetaGroups(ctx, []models.MetaGroup{
+		{ID: 1, Name: "Tech I"},
+		{ID: 2, Name: "Tech II"},
+	})
+	assert.NoError(t, err)
+
+	mineral := int64(18)
+	damageControl := int64(60)
+	damageControls := int64(1054)
+	techI := int64(1)
+	techII := int64(2)
+	damageControlI := int64(2046)
+	packaged := 5.0
+	err = itemTypeRepo.UpsertItemTypes(ctx, []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, GroupID: &mineral, Published: true},
+		{TypeID: 35, TypeName: "Pyerite", Volume: 0.01, GroupID: &mineral, Published: true},
+		{TypeID: 2046, TypeName: "Damage Control I", Volume: 5, GroupID: &damageControl, MarketGroupID: &damageControls, MetaGroupID: &techI, Published: true, PackagedVolume: &packaged},
+		{TypeID: 2048, TypeName: "Damage Control II", Volume: 5, GroupID: &damageControl, MarketGroupID: &damageControls, MetaGroupID: &techII, ParentTypeID: &damageControlI, Published: true, PackagedVolume: &packaged},
+	})
+	assert.NoError(t, err)
+}
+
+func Test_ItemMetadataShouldUpsertAndGet(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	metadataRepo := repositories.NewItemMetadata(db)
+	setupItemMetadata(t, metadataRepo, repositories.NewItemTypeRepository(db))
+
+	categories, err := metadataRepo.GetCategories(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, "Material", categories[0].Name)
+	assert.Equal(t, "Module", categories[1].Name)
+
+	groups, err := metadataRepo.GetGroups(context.Background(), []int64{4})
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Equal(t, int64(18), groups[0].ID)
+
+	groups, err = metadataRepo.GetGroups(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+
+	marketGroups, err := metadataRepo.GetMarketGroups(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, marketGroups, 3)
+	assert.Equal(t, int64(9), marketGroups[0].ID)
+	assert.Nil(t, marketGroups[0].ParentID)
+	assert.Equal(t, int64(1054), marketGroups[2].ID)
+	assert.True(t, marketGroups[2].HasTypes)
+
+	metaGroups, err := metadataRepo.GetMetaGroups(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, metaGroups, 2)
+	assert.Equal(t, "Tech II", metaGroups[1].Name)
+}
+
+func Test_ItemTypeShouldSearchByMetadata(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	setupItemMetadata(t, repositories.NewItemMetadata(db), itemTypeRepo)
+
+	// All minerals, by category
+	items, err := itemTypeRepo.SearchItemTypes(context.Background(), "", &models.ItemTypeFilter{CategoryIDs: []int64{4}}, 20)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	for _, item := range items {
+		assert.Equal(t, int64(4), *item.CategoryID)
+	}
+
+	// A parent market group includes everything beneath it
+	items, err = itemTypeRepo.SearchItemTypes(context.Background(), "damage", &models.ItemTypeFilter{MarketGroupIDs: []int64{9}}, 20)
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+
+	// Tech II only
+	items, err = itemTypeRepo.SearchItemTypes(context.Background(), "", &models.ItemTypeFilter{MetaGroupIDs: []int64{2}}, 20)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, int64(2048), items[0].TypeID)
+	assert.Equal(t, int64(2046), *items[0].ParentTypeID)
+	assert.Equal(t, 5.0, *items[0].PackagedVolume)
+
+	// Filters are combined
+	items, err = itemTypeRepo.SearchItemTypes(context.Background(), "", &models.ItemTypeFilter{GroupIDs: []int64{18}, MetaGroupIDs: []int64{2}}, 20)
+	assert.NoError(t, err)
+	assert.Len(t, items, 0)
+}
@@ -3,11 +3,17 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
+// ErrItemTypeNotFound is returned when no item type has the given name
+var ErrItemTypeNotFound = errors.New("item type not found")
+
 type ItemTypeRepository struct {
 	db *sql.DB
 }
@@ -30,16 +36,28 @@ insert into
 		type_id,
 		type_name,
 		volume,
-		icon_id
+		icon_id,
+		group_id,
+		market_group_id,
+		meta_group_id,
+		parent_type_id,
+		published,
+		packaged_volume
 	)
 	values
-		($1,$2,$3,$4)
+		($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
 on conflict
 	(type_id)
 do update set
 	type_name = EXCLUDED.type_name,
 	volume = EXCLUDED.volume,
-	icon_id = EXCLUDED.icon_id
+	icon_id = EXCLUDED.icon_id,
+	group_id = EXCLUDED.group_id,
+	market_group_id = EXCLUDED.market_group_id,
+	meta_group_id = EXCLUDED.meta_group_id,
+	parent_type_id = EXCLUDED.parent_type_id,
+	published = EXCLUDED.published,
+	packaged_volume = EXCLUDED.packaged_volume
 `
 
 	tx, err := r.db.BeginTx(ctx, nil)
@@ -59,6 +77,12 @@ do update set
 			itemType.TypeName,
 			itemType.Volume,
 			itemType.IconID,
+			itemType.GroupID,
+			itemType.MarketGroupID,
+			itemType.MetaGroupID,
+			itemType.ParentTypeID,
+			itemType.Published,
+			itemType.PackagedVolume,
 		)
 		if err != nil {
 			return errors.Wrap(err, "failed to execute item type upsert")
@@ -73,27 +97,32 @@ do update set
 	return nil
 }
 
-// SearchItemTypes searches for item types by name (case-insensitive, partial match)
-func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string, limit int) ([]models.EveInventoryType, error) {
+// SearchItemTypes searches for item types by name (case-insensitive, partial match), narrowed to
+// the filter. An empty query matches every type of the filter.
+func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string, filter *models.ItemTypeFilter, limit int) ([]models.EveInventoryType, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 
+	filterSQL, filterArgs := itemTypeFilterSQL("itemTypes.type_id", filter, 4)
+
 	searchQuery := `
-		SELECT type_id, type_name, volume, icon_id
-		FROM asset_item_types
-		WHERE LOWER(type_name) LIKE LOWER($1)
+		SELECT ` + itemTypeColumns + `
+		FROM asset_item_types itemTypes
+		LEFT JOIN item_groups itemGroups ON itemGroups.group_id = itemTypes.group_id
+		WHERE LOWER(itemTypes.type_name) LIKE LOWER($1)` + filterSQL + `
 		ORDER BY
 			CASE
-				WHEN LOWER(type_name) = LOWER($3) THEN 1
-				WHEN LOWER(type_name) LIKE LOWER($3) || '%' THEN 2
+				WHEN LOWER(itemTypes.type_name) = LOWER($3) THEN 1
+				WHEN LOWER(itemTypes.type_name) LIKE LOWER($3) || '%' THEN 2
 				ELSE 3
 			END,
-			type_name
+			itemTypes.type_name
 		LIMIT $2
 	`
 
-	rows, err := r.db.QueryContext(ctx, searchQuery, "%"+query+"%", limit, query)
+	args := append([]any{"%" + query + "%", limit, query}, filterArgs...)
+	rows, err := r.db.QueryContext(ctx, searchQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to search item types")
 	}
@@ -101,10 +130,9 @@ func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string,
 
 	var items []models.EveInventoryType
 	for rows.Next() {
-		var item models.EveInventoryType
-		err := rows.Scan(&item.TypeID, &item.TypeName, &item.Volume, &item.IconID)
+		item, err := scanItemType(rows)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to scan item type")
+			return nil, err
 		}
 		items = append(items, item)
 	}
@@ -115,25 +143,159 @@ func (r *ItemTypeRepository) SearchItemTypes(ctx context.Context, query string,
 // GetItemTypeByName gets an exact item type by name
 func (r *ItemTypeRepository) GetItemTypeByName(ctx context.Context, typeName string) (*models.EveInventoryType, error) {
 	query := `
-		SELECT type_id, type_name, volume, icon_id
-		FROM asset_item_types
-		WHERE type_name = $1
+		SELECT ` + itemTypeColumns + `
+		FROM asset_item_types itemTypes
+		LEFT JOIN item_groups itemGroups ON itemGroups.group_id = itemTypes.group_id
+		WHERE itemTypes.type_name = $1
+	`
+
+	item, err := scanItemType(r.db.QueryRowContext(ctx, query, typeName))
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrItemTypeNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get item type")
+	}
+
+	return &item, nil
+}
+
+// GetItemTypesByNames resolves names case-insensitively, keyed by the lower-cased name
+func (r *ItemTypeRepository) GetItemTypesByNames(ctx context.Context, names []string) (map[string]models.EveInventoryType, error) {
+	itemTypes := map[string]models.EveInventoryType{}
+	if len(names) == 0 {
+		return itemTypes, nil
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+
+	query := `
+		SELECT ` + itemTypeColumns + `
+		FROM asset_item_types itemTypes
+		LEFT JOIN item_groups itemGroups ON itemGroups.group_id = itemTypes.group_id
+		WHERE LOWER(itemTypes.type_name) = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(lowered))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query item types by name")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanItemType(rows)
+		if err != nil {
+			return nil, err
+		}
+		itemTypes[strings.ToLower(item.TypeName)] = item
+	}
+
+	return itemTypes, nil
+}
+
+// GetItemTypesByIDs looks up item types keyed by type ID; unknown IDs are left out
+func (r *ItemTypeRepository) GetItemTypesByIDs(ctx context.Context, typeIDs []int64) (map[int64]models.EveInventoryType, error) {
+	itemTypes := map[int64]models.EveInventoryType{}
+	if len(typeIDs) == 0 {
+		return itemTypes, nil
+	}
+
+	query := `
+		SELECT ` + itemTypeColumns + `
+		FROM asset_item_types itemTypes
+		LEFT JOIN item_groups itemGroups ON itemGroups.group_id = itemTypes.group_id
+		WHERE itemTypes.type_id = ANY($1)
 	`
 
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(typeIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query item types by id")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanItemType(rows)
+		if err != nil {
+			return nil, err
+		}
+		itemTypes[item.TypeID] = item
+	}
+
+	return itemTypes, nil
+}
+
+// itemTypeColumns are read by scanItemType, from asset_item_types as itemTypes joined to its
+// item_groups row as itemGroups
+const itemTypeColumns = `itemTypes.type_id, itemTypes.type_name, itemTypes.volume, itemTypes.icon_id,
+			itemTypes.group_id, itemGroups.category_id, itemTypes.market_group_id, itemTypes.meta_group_id,
+			itemTypes.parent_type_id, itemTypes.published, itemTypes.packaged_volume`
+
+func scanItemType(row interface{ Scan(dest ...any) error }) (models.EveInventoryType, error) {
 	var item models.EveInventoryType
-	err := r.db.QueryRowContext(ctx, query, typeName).Scan(
+	err := row.Scan(
 		&item.TypeID,
 		&item.TypeName,
 		&item.Volume,
 		&item.IconID,
+		&item.GroupID,
+		&item.CategoryID,
+		&item.MarketGroupID,
+		&item.MetaGroupID,
+		&item.ParentTypeID,
+		&item.Published,
+		&item.PackagedVolume,
 	)
+	if err != nil {
+		return item, errors.Wrap(err, "failed to scan item type")
+	}
+	return item, nil
+}
 
-	if err == sql.ErrNoRows {
-		return nil, errors.New("item type not found")
+// itemTypeFilterSQL is an "AND ..." clause limiting typeColumn to the filter's types, with its
+// parameters numbered from firstParam. Market groups include every group below them. An empty
+// filter gives no clause.
+func itemTypeFilterSQL(typeColumn string, filter *models.ItemTypeFilter, firstParam int) (string, []any) {
+	if filter.IsEmpty() {
+		return "", nil
 	}
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get item type")
+
+	conditions := []string{}
+	args := []any{}
+	param := func(ids []int64) string {
+		args = append(args, pq.Array(ids))
+		return fmt.Sprintf("$%d", firstParam+len(args)-1)
 	}
 
-	return &item, nil
+	if len(filter.GroupIDs) > 0 {
+		conditions = append(conditions, "filterTypes.group_id = ANY("+param(filter.GroupIDs)+")")
+	}
+	if len(filter.CategoryIDs) > 0 {
+		conditions = append(conditions, "filterGroups.category_id = ANY("+param(filter.CategoryIDs)+")")
+	}
+	if len(filter.MarketGroupIDs) > 0 {
+		conditions = append(conditions, `filterTypes.market_group_id IN (
+				WITH RECURSIVE tree AS (
+					SELECT market_group_id FROM market_groups WHERE market_group_id = ANY(`+param(filter.MarketGroupIDs)+`)
+					UNION
+					SELECT children.market_group_id FROM market_groups children
+					INNER JOIN tree ON children.parent_group_id = tree.market_group_id
+				)
+				SELECT market_group_id FROM tree
+			)`)
+	}
+	if len(filter.MetaGroupIDs) > 0 {
+		conditions = append(conditions, "filterTypes.meta_group_id = ANY("+param(filter.MetaGroupIDs)+")")
+	}
+
+	return fmt.Sprintf(`
+		AND %s IN (
+			SELECT filterTypes.type_id
+			FROM asset_item_types filterTypes
+			LEFT JOIN item_groups filterGroups ON filterGroups.group_id = filterTypes.group_id
+			WHERE %s
+		)`, typeColumn, strings.Join(conditions, " AND ")), args
 }
@@ -71,3 +71,22 @@ func Test_ItemTypeShouldHandleEmptyUpsert(t *testing.T) {
 	err = itemTypeRepo.UpsertItemTypes(context.Background(), nil)
 	assert.NoError(t, err)
 }
+
+func Test_ItemTypeShouldGetItemTypesByIDs(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+
+	err = itemTypeRepo.UpsertItemTypes(context.Background(), []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01},
+		{TypeID: 35, TypeName: "Pyerite", Volume: 0.0032},
+	})
+	assert.NoError(t, err)
+
+	itemTypes, err := itemTypeRepo.GetItemTypesByIDs(context.Background(), []int64{34, 35, 999999})
+	assert.NoError(t, err)
+	assert.Len(t, itemTypes, 2)
+	assert.Equal(t, "Pyerite", itemTypes[35].TypeName)
+	assert.Equal(t, 0.01, itemTypes[34].Volume)
+}
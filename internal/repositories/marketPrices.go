@@ -46,6 +46,26 @@ do update set
 	updated_at = NOW()
 `
 
+	// Keep one snapshot per type and day so sales can be valued at listing time
+	historyQuery := `
+insert into
+	market_price_history
+	(
+		type_id,
+		region_id,
+		recorded_on,
+		buy_price,
+		sell_price
+	)
+	values
+		($1,$2,CURRENT_DATE,$3,$4)
+on conflict
+	(type_id, region_id, recorded_on)
+do update set
+	buy_price = EXCLUDED.buy_price,
+	sell_price = EXCLUDED.sell_price
+`
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return errors.Wrap(err, "failed to begin transaction for market prices upsert")
@@ -57,6 +77,11 @@ do update set
 		return errors.Wrap(err, "failed to prepare for market prices upsert")
 	}
 
+	historySmt, err := tx.PrepareContext(ctx, historyQuery)
+	if err != nil {
+		return errors.Wrap(err, "failed to prepare for market price history upsert")
+	}
+
 	for _, price := range prices {
 		_, err = smt.ExecContext(ctx,
 			price.TypeID,
@@ -68,6 +93,16 @@ do update set
 		if err != nil {
 			return errors.Wrap(err, "failed to execute market price upsert")
 		}
+
+		_, err = historySmt.ExecContext(ctx,
+			price.TypeID,
+			price.RegionID,
+			price.BuyPrice,
+			price.SellPrice,
+		)
+		if err != nil {
+			return errors.Wrap(err, "failed to execute market price history upsert")
+		}
 	}
 
 	err = tx.Commit()
@@ -116,3 +116,36 @@ func Test_MarketPricesShouldHandleEmptyUpsert(t *testing.T) {
 	err = marketPricesRepo.UpsertPrices(context.Background(), []models.MarketPrice{})
 	assert.NoError(t, err)
 }
+
+func Test_MarketPricesShouldKeepDailyHistory(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	err = itemTypeRepo.UpsertItemTypes(context.Background(), []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, IconID: nil},
+	})
+	assert.NoError(t, err)
+
+	marketPricesRepo := repositories.NewMarketPrices(db)
+
+	buyPrice := 5.45
+	prices := []models.MarketPrice{{TypeID: 34, RegionID: 10000002, BuyPrice: &buyPrice}}
+	assert.NoError(t, marketPricesRepo.UpsertPrices(context.Background(), prices))
+
+	// A second refresh on the same day replaces that day's snapshot
+	buyPrice = 5.60
+	assert.NoError(t, marketPricesRepo.UpsertPrices(context.Background(), prices))
+
+	// Clearing current prices leaves history intact
+	assert.NoError(t, marketPricesRepo.DeleteAllForRegion(context.Background(), 10000002))
+
+	var count int
+	var recorded float64
+	err = db.QueryRow(`
+		SELECT COUNT(*), MAX(buy_price) FROM market_price_history WHERE type_id = 34 AND region_id = 10000002
+	`).Scan(&count, &recorded)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 5.60, recorded)
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type NotificationSettings struct {
+	db *sql.DB
+}
+
+func NewNotificationSettings(db *sql.DB) *NotificationSettings {
+	return &NotificationSettings{db: db}
+}
+
+// Get returns the user's notification settings, or nil if the user has not set any
+func (r *NotificationSettings) Get(ctx context.Context, userID int64) (*models.NotificationSettings, error) {
+	query := `
+		SELECT user_id, webhook_url, mail_character_id
+		FROM notification_settings
+		WHERE user_id = $1
+	`
+
+	var settings models.NotificationSettings
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&settings.UserID, &settings.WebhookURL, &settings.MailCharacterID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get notification settings")
+	}
+
+	return &settings, nil
+}
+
+// Upsert creates or updates the user's notification settings
+func (r *NotificationSettings) Upsert(ctx context.Context, settings *models.NotificationSettings) error {
+	query := `
+		INSERT INTO notification_settings (user_id, webhook_url, mail_character_id, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			webhook_url = EXCLUDED.webhook_url,
+			mail_character_id = EXCLUDED.mail_character_id,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, settings.UserID, settings.WebhookURL, settings.MailCharacterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert notification settings")
+	}
+
+	return nil
+}
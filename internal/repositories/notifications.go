@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type Notifications struct {
+	db *sql.DB
+}
+
+func NewNotifications(db *sql.DB) *Notifications {
+	return &Notifications{db: db}
+}
+
+// Create stores an in-app notification, setting its ID and creation time
+func (r *Notifications) Create(ctx context.Context, notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (user_id, kind, title, message, stockpile_marker_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		notification.UserID,
+		notification.Kind,
+		notification.Title,
+		notification.Message,
+		notification.StockpileMarkerID,
+	).Scan(&notification.ID, &notification.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create notification")
+	}
+
+	return nil
+}
+
+// GetByUser returns the user's most recent notifications, newest first
+func (r *Notifications) GetByUser(ctx context.Context, userID int64, unreadOnly bool, limit int) ([]*models.Notification, error) {
+	query := `
+		SELECT id, user_id, kind, title, message, stockpile_marker_id, created_at, read_at
+		FROM notifications
+		WHERE user_id = $1
+			AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query notifications")
+	}
+	defer rows.Close()
+
+	notifications := []*models.Notification{}
+	for rows.Next() {
+		var notification models.Notification
+		err = rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Kind,
+			&notification.Title,
+			&notification.Message,
+			&notification.StockpileMarkerID,
+			&notification.CreatedAt,
+			&notification.ReadAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan notification")
+		}
+		notifications = append(notifications, &notification)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating notifications")
+	}
+
+	return notifications, nil
+}
+
+// MarkRead marks one of the user's notifications as read, reporting whether it was found
+func (r *Notifications) MarkRead(ctx context.Context, userID, id int64) (bool, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, NOW())
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark notification read")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rows > 0, nil
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (r *Notifications) MarkAllRead(ctx context.Context, userID int64) error {
+	query := `
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_id = $1 AND read_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to mark notifications read")
+	}
+
+	return nil
+}
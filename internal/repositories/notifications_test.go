@@ -0,0 +1,99 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Notifications_CreateListAndRead(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User'), (2, 'Other User')`)
+	require.NoError(t, err)
+
+	repo := repositories.NewNotifications(db)
+
+	first := &models.Notification{UserID: 1, Kind: models.NotificationKindStockpileAlert, Title: "Tritanium is low", Message: "Holding 0"}
+	second := &models.Notification{UserID: 1, Kind: models.NotificationKindStockpileAlert, Title: "Pyerite is low", Message: "Holding 5"}
+	other := &models.Notification{UserID: 2, Kind: models.NotificationKindStockpileAlert, Title: "Not mine", Message: ""}
+	for _, n := range []*models.Notification{first, second, other} {
+		require.NoError(t, repo.Create(ctx, n))
+		assert.NotZero(t, n.ID)
+	}
+
+	notifications, err := repo.GetByUser(ctx, 1, false, 50)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, second.ID, notifications[0].ID)
+	assert.Nil(t, notifications[0].ReadAt)
+
+	found, err := repo.MarkRead(ctx, 1, first.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	// Another user's notification can't be marked
+	found, err = repo.MarkRead(ctx, 1, other.ID)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	unread, err := repo.GetByUser(ctx, 1, true, 50)
+	require.NoError(t, err)
+	require.Len(t, unread, 1)
+	assert.Equal(t, second.ID, unread[0].ID)
+
+	require.NoError(t, repo.MarkAllRead(ctx, 1))
+
+	unread, err = repo.GetByUser(ctx, 1, true, 50)
+	require.NoError(t, err)
+	assert.Len(t, unread, 0)
+
+	limited, err := repo.GetByUser(ctx, 1, false, 1)
+	require.NoError(t, err)
+	assert.Len(t, limited, 1)
+}
+
+func Test_NotificationSettings_GetAndUpsert(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	repo := repositories.NewNotificationSettings(db)
+
+	settings, err := repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, settings)
+
+	webhook := "https://discord.com/api/webhooks/1/abc"
+	characterID := int64(12345)
+	require.NoError(t, repo.Upsert(ctx, &models.NotificationSettings{UserID: 1, WebhookURL: &webhook, MailCharacterID: &characterID}))
+
+	settings, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, webhook, *settings.WebhookURL)
+	assert.Equal(t, characterID, *settings.MailCharacterID)
+
+	require.NoError(t, repo.Upsert(ctx, &models.NotificationSettings{UserID: 1}))
+
+	settings, err = repo.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, settings.WebhookURL)
+	assert.Nil(t, settings.MailCharacterID)
+}
@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+)
+
+// pricingRuleJoins resolves the Jita reference price a pricing rule on the
+// given table alias is based on. Rows without a rule get a NULL base price.
+func pricingRuleJoins(alias string) string {
+	return fmt.Sprintf(`
+		LEFT JOIN market_prices rule_market ON (rule_market.type_id = %[1]s.type_id AND rule_market.region_id = 10000002)
+		LEFT JOIN LATERAL (
+			SELECT CASE %[1]s.pricing_basis
+				WHEN 'buy' THEN NULLIF(rule_market.buy_price, 0)
+				WHEN 'sell' THEN NULLIF(rule_market.sell_price, 0)
+				WHEN 'split' THEN (NULLIF(rule_market.buy_price, 0) + NULLIF(rule_market.sell_price, 0)) / 2
+			END AS base_price
+		) rule_base ON true
+	`, alias)
+}
+
+// pricingRulePrice evaluates the rule on alias, clamped to its floor and ceiling.
+// Without a rule, or without a Jita price for the basis (missing or 0), the fixed price column
+// is used rather than repricing to nothing, still held to the rule's floor and ceiling.
+func pricingRulePrice(alias, fixedColumn string) string {
+	return fmt.Sprintf(`CASE
+				WHEN rule_base.base_price IS NULL THEN LEAST(GREATEST(%[1]s.%[2]s, %[1]s.pricing_floor), %[1]s.pricing_ceiling)
+				ELSE LEAST(GREATEST(ROUND(rule_base.base_price * %[1]s.pricing_percent / 100)::bigint, %[1]s.pricing_floor), %[1]s.pricing_ceiling)
+			END`, alias, fixedColumn)
+}
+
+// pricingRulePriced filters out rows whose rule has nothing to price them at, which only
+// rows saved before a fallback price was required can hit
+func pricingRulePriced(alias, fixedColumn string) string {
+	return fmt.Sprintf(`(%[1]s.pricing_basis IS NULL OR %[2]s > 0)`, alias, pricingRulePrice(alias, fixedColumn))
+}
+
+// pricingRuleColumns scans the nullable pricing rule columns of a row
+type pricingRuleColumns struct {
+	basis   sql.NullString
+	percent sql.NullFloat64
+	floor   sql.NullInt64
+	ceiling sql.NullInt64
+}
+
+func (c *pricingRuleColumns) rule() *models.PricingRule {
+	if !c.basis.Valid {
+		return nil
+	}
+
+	rule := &models.PricingRule{
+		Basis:   c.basis.String,
+		Percent: c.percent.Float64,
+	}
+	if c.floor.Valid {
+		rule.Floor = &c.floor.Int64
+	}
+	if c.ceiling.Valid {
+		rule.Ceiling = &c.ceiling.Int64
+	}
+
+	return rule
+}
+
+// pricingRuleArgs flattens an optional rule into column values for inserts and updates
+func pricingRuleArgs(rule *models.PricingRule) (basis *string, percent *float64, floor *int64, ceiling *int64) {
+	if rule == nil {
+		return nil, nil, nil, nil
+	}
+	return &rule.Basis, &rule.Percent, rule.Floor, rule.Ceiling
+}
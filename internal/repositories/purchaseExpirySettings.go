@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type PurchaseExpirySettings struct {
+	db *sql.DB
+}
+
+func NewPurchaseExpirySettings(db *sql.DB) *PurchaseExpirySettings {
+	return &PurchaseExpirySettings{db: db}
+}
+
+// Get returns the seller's expiry settings, or nil if the seller has not set any
+func (r *PurchaseExpirySettings) Get(ctx context.Context, userID int64) (*models.PurchaseExpirySettings, error) {
+	query := `
+		SELECT user_id, timeout_hours
+		FROM purchase_expiry_settings
+		WHERE user_id = $1
+	`
+
+	var settings models.PurchaseExpirySettings
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&settings.UserID, &settings.TimeoutHours)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchase expiry settings")
+	}
+
+	return &settings, nil
+}
+
+// Upsert creates or updates the seller's expiry settings
+func (r *PurchaseExpirySettings) Upsert(ctx context.Context, settings *models.PurchaseExpirySettings) error {
+	query := `
+		INSERT INTO purchase_expiry_settings (user_id, timeout_hours, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id)
+		DO UPDATE SET
+			timeout_hours = EXCLUDED.timeout_hours,
+			updated_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, settings.UserID, settings.TimeoutHours)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert purchase expiry settings")
+	}
+
+	return nil
+}
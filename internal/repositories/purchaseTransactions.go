@@ -74,62 +74,45 @@ func (r *PurchaseTransactions) UpdateContractKeys(ctx context.Context, purchaseI
 
 // GetByBuyer returns purchase history for buyer
 func (r *PurchaseTransactions) GetByBuyer(ctx context.Context, buyerUserID int64) ([]*models.PurchaseTransaction, error) {
-	query := `
-		SELECT
-			pt.id,
-			pt.for_sale_item_id,
-			pt.buyer_user_id,
-			pt.seller_user_id,
-			pt.type_id,
-			t.type_name,
-			pt.quantity_purchased,
-			pt.price_per_unit,
-			pt.total_price,
-			pt.status,
-			pt.contract_key,
-			pt.transaction_notes,
-			pt.purchased_at
-		FROM purchase_transactions pt
-		JOIN asset_item_types t ON pt.type_id = t.type_id
-		WHERE pt.buyer_user_id = $1
-		ORDER BY pt.purchased_at DESC
-	`
-
-	rows, err := r.db.QueryContext(ctx, query, buyerUserID)
+	var transactions []*models.PurchaseTransaction
+	err := r.StreamByBuyer(ctx, buyerUserID, func(tx *models.PurchaseTransaction) error {
+		transactions = append(transactions, tx)
+		return nil
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to query buyer purchase history")
+		return nil, err
 	}
-	defer rows.Close()
 
+	return transactions, nil
+}
+
+// GetBySeller returns sales history for seller
+func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int64) ([]*models.PurchaseTransaction, error) {
 	var transactions []*models.PurchaseTransaction
-	for rows.Next() {
-		var tx models.PurchaseTransaction
-		err = rows.Scan(
-			&tx.ID,
-			&tx.ForSaleItemID,
-			&tx.BuyerUserID,
-			&tx.SellerUserID,
-			&tx.TypeID,
-			&tx.TypeName,
-			&tx.QuantityPurchased,
-			&tx.PricePerUnit,
-			&tx.TotalPrice,
-			&tx.Status,
-			&tx.ContractKey,
-			&tx.TransactionNotes,
-			&tx.PurchasedAt,
-		)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to scan purchase transaction")
-		}
-		transactions = append(transactions, &tx)
+	err := r.StreamBySeller(ctx, sellerUserID, func(tx *models.PurchaseTransaction) error {
+		transactions = append(transactions, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return transactions, nil
 }
 
-// GetBySeller returns sales history for seller
-func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int64) ([]*models.PurchaseTransaction, error) {
+// StreamByBuyer calls fn for each of the buyer's purchases, newest first, without loading them all
+func (r *PurchaseTransactions) StreamByBuyer(ctx context.Context, buyerUserID int64, fn func(*models.PurchaseTransaction) error) error {
+	err := r.streamHistory(ctx, "pt.buyer_user_id", buyerUserID, fn)
+	return errors.Wrap(err, "failed to query buyer purchase history")
+}
+
+// StreamBySeller calls fn for each of the seller's sales, newest first, without loading them all
+func (r *PurchaseTransactions) StreamBySeller(ctx context.Context, sellerUserID int64, fn func(*models.PurchaseTransaction) error) error {
+	err := r.streamHistory(ctx, "pt.seller_user_id", sellerUserID, fn)
+	return errors.Wrap(err, "failed to query seller sales history")
+}
+
+func (r *PurchaseTransactions) streamHistory(ctx context.Context, userColumn string, userID int64, fn func(*models.PurchaseTransaction) error) error {
 	query := `
 		SELECT
 			pt.id,
@@ -144,20 +127,21 @@ func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int
 			pt.status,
 			pt.contract_key,
 			pt.transaction_notes,
+			pt.cancellation_reason,
+			pt.cancelled_at,
 			pt.purchased_at
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
-		WHERE pt.seller_user_id = $1
+		WHERE ` + userColumn + ` = $1
 		ORDER BY pt.purchased_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, sellerUserID)
+	rows, err := r.db.QueryContext(ctx, query, userID)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to query seller sales history")
+		return err
 	}
 	defer rows.Close()
 
-	var transactions []*models.PurchaseTransaction
 	for rows.Next() {
 		var tx models.PurchaseTransaction
 		err = rows.Scan(
@@ -173,15 +157,21 @@ func (r *PurchaseTransactions) GetBySeller(ctx context.Context, sellerUserID int
 			&tx.Status,
 			&tx.ContractKey,
 			&tx.TransactionNotes,
+			&tx.CancellationReason,
+			&tx.CancelledAt,
 			&tx.PurchasedAt,
 		)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to scan purchase transaction")
+			return errors.Wrap(err, "failed to scan purchase transaction")
+		}
+
+		err = fn(&tx)
+		if err != nil {
+			return err
 		}
-		transactions = append(transactions, &tx)
 	}
 
-	return transactions, nil
+	return rows.Err()
 }
 
 // GetPendingForSeller returns pending purchase requests for seller
@@ -266,6 +256,8 @@ func (r *PurchaseTransactions) GetByID(ctx context.Context, purchaseID int64) (*
 			pt.status,
 			pt.contract_key,
 			pt.transaction_notes,
+			pt.cancellation_reason,
+			pt.cancelled_at,
 			pt.purchased_at
 		FROM purchase_transactions pt
 		JOIN asset_item_types t ON pt.type_id = t.type_id
@@ -286,6 +278,8 @@ func (r *PurchaseTransactions) GetByID(ctx context.Context, purchaseID int64) (*
 		&tx.Status,
 		&tx.ContractKey,
 		&tx.TransactionNotes,
+		&tx.CancellationReason,
+		&tx.CancelledAt,
 		&tx.PurchasedAt,
 	)
 
@@ -303,7 +297,8 @@ func (r *PurchaseTransactions) GetByID(ctx context.Context, purchaseID int64) (*
 func (r *PurchaseTransactions) UpdateStatus(ctx context.Context, purchaseID int64, newStatus string) error {
 	query := `
 		UPDATE purchase_transactions
-		SET status = $2
+		SET status = $2,
+		    completed_at = CASE WHEN $2 = 'completed' THEN NOW() ELSE completed_at END
 		WHERE id = $1
 	`
 
@@ -323,3 +318,110 @@ func (r *PurchaseTransactions) UpdateStatus(ctx context.Context, purchaseID int6
 
 	return nil
 }
+
+// GetStalePurchases returns open purchases older than the seller's expiry timeout.
+// Sellers without their own setting use defaultTimeoutHours; a timeout of 0 disables expiry.
+func (r *PurchaseTransactions) GetStalePurchases(ctx context.Context, defaultTimeoutHours int) ([]*models.PurchaseTransaction, error) {
+	query := `
+		SELECT
+			pt.id,
+			pt.for_sale_item_id,
+			pt.buyer_user_id,
+			pt.seller_user_id,
+			pt.type_id,
+			pt.quantity_purchased,
+			pt.status,
+			pt.purchased_at
+		FROM purchase_transactions pt
+		LEFT JOIN purchase_expiry_settings s ON s.user_id = pt.seller_user_id
+		WHERE pt.status IN ('pending', 'contract_created')
+		  AND COALESCE(s.timeout_hours, $1) > 0
+		  AND pt.purchased_at < NOW() - make_interval(hours => COALESCE(s.timeout_hours, $1))
+		ORDER BY pt.purchased_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, defaultTimeoutHours)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stale purchases")
+	}
+	defer rows.Close()
+
+	var transactions []*models.PurchaseTransaction
+	for rows.Next() {
+		var tx models.PurchaseTransaction
+		err = rows.Scan(
+			&tx.ID,
+			&tx.ForSaleItemID,
+			&tx.BuyerUserID,
+			&tx.SellerUserID,
+			&tx.TypeID,
+			&tx.QuantityPurchased,
+			&tx.Status,
+			&tx.PurchasedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stale purchase")
+		}
+		transactions = append(transactions, &tx)
+	}
+
+	return transactions, nil
+}
+
+// ErrPurchaseNotOpen is returned when cancelling a purchase that does not exist or is no longer open
+var ErrPurchaseNotOpen = errors.New("purchase transaction not found or no longer open")
+
+// CancelWithReason cancels an open purchase on the system's behalf and records why (within transaction)
+func (r *PurchaseTransactions) CancelWithReason(ctx context.Context, tx *sql.Tx, purchaseID int64, reason string) error {
+	query := `
+		UPDATE purchase_transactions
+		SET status = 'cancelled',
+		    cancellation_reason = $2,
+		    cancelled_at = NOW(),
+		    cancelled_by = 'system'
+		WHERE id = $1 AND status IN ('pending', 'contract_created')
+	`
+
+	result, err := tx.ExecContext(ctx, query, purchaseID, reason)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel purchase")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return ErrPurchaseNotOpen
+	}
+
+	return nil
+}
+
+// CancelByParticipant cancels an open purchase on behalf of its buyer or seller (within transaction)
+func (r *PurchaseTransactions) CancelByParticipant(ctx context.Context, tx *sql.Tx, purchaseID int64, cancelledBy string) error {
+	query := `
+		UPDATE purchase_transactions
+		SET status = 'cancelled',
+		    cancelled_at = NOW(),
+		    cancelled_by = $2
+		WHERE id = $1 AND status IN ('pending', 'contract_created')
+	`
+
+	result, err := tx.ExecContext(ctx, query, purchaseID, cancelledBy)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel purchase")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+
+	if rowsAffected == 0 {
+		return ErrPurchaseNotOpen
+	}
+
+	return nil
+}
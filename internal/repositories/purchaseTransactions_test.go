@@ -3,10 +3,12 @@ package repositories_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -309,6 +311,57 @@ func Test_PurchaseTransactions_GetBySeller(t *testing.T) {
 	assert.Equal(t, "Test Item", transactions[0].TypeName)
 }
 
+func Test_PurchaseTransactions_StreamBySeller(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	item, err := setupPurchaseTestData(t, db, 3120, 3121, 52, 30000162)
+	assert.NoError(t, err)
+
+	repo := repositories.NewPurchaseTransactions(db)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	for i := 0; i < 3; i++ {
+		purchase := &models.PurchaseTransaction{
+			ForSaleItemID:     item.ID,
+			BuyerUserID:       3120,
+			SellerUserID:      3121,
+			TypeID:            52,
+			QuantityPurchased: int64(10 + i),
+			PricePerUnit:      100,
+			TotalPrice:        int64((10 + i) * 100),
+			Status:            "completed",
+		}
+		err = repo.Create(context.Background(), tx, purchase)
+		assert.NoError(t, err)
+	}
+
+	err = tx.Commit()
+	assert.NoError(t, err)
+
+	seen := 0
+	err = repo.StreamBySeller(context.Background(), 3121, func(purchase *models.PurchaseTransaction) error {
+		seen++
+		assert.Equal(t, "Test Item", purchase.TypeName)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, seen)
+
+	// An error from the callback stops the stream
+	seen = 0
+	err = repo.StreamBySeller(context.Background(), 3121, func(purchase *models.PurchaseTransaction) error {
+		seen++
+		return errors.New("client went away")
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "client went away")
+	assert.Equal(t, 1, seen)
+}
+
 func Test_PurchaseTransactions_GetPendingForSeller(t *testing.T) {
 	db, err := setupDatabase()
 	assert.NoError(t, err)
@@ -399,3 +452,125 @@ func Test_PurchaseTransactions_UpdateContractKeys_EmptyArray(t *testing.T) {
 	err = repo.UpdateContractKeys(context.Background(), []int64{}, "some-key")
 	assert.NoError(t, err)
 }
+
+func Test_PurchaseTransactions_GetStalePurchases(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	item, err := setupPurchaseTestData(t, db, 3100, 3101, 50, 30000160)
+	assert.NoError(t, err)
+
+	repo := repositories.NewPurchaseTransactions(db)
+	settingsRepo := repositories.NewPurchaseExpirySettings(db)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	statuses := []string{"pending", "contract_created", "completed", "pending"}
+	purchases := []*models.PurchaseTransaction{}
+	for _, status := range statuses {
+		purchase := &models.PurchaseTransaction{
+			ForSaleItemID:     item.ID,
+			BuyerUserID:       3100,
+			SellerUserID:      3101,
+			TypeID:            50,
+			QuantityPurchased: 10,
+			PricePerUnit:      100,
+			TotalPrice:        1000,
+			Status:            status,
+		}
+		err = repo.Create(context.Background(), tx, purchase)
+		assert.NoError(t, err)
+		purchases = append(purchases, purchase)
+	}
+	err = tx.Commit()
+	assert.NoError(t, err)
+
+	// Backdate the first three purchases past the default timeout, leave the last one fresh
+	_, err = db.ExecContext(context.Background(),
+		"UPDATE purchase_transactions SET purchased_at = NOW() - INTERVAL '100 hours' WHERE id = ANY($1)",
+		pq.Array([]int64{purchases[0].ID, purchases[1].ID, purchases[2].ID}))
+	assert.NoError(t, err)
+
+	stale, err := repo.GetStalePurchases(context.Background(), 72)
+	assert.NoError(t, err)
+	assert.Len(t, stale, 2)
+	staleIDs := []int64{stale[0].ID, stale[1].ID}
+	assert.Contains(t, staleIDs, purchases[0].ID)
+	assert.Contains(t, staleIDs, purchases[1].ID)
+
+	// Seller-specific timeout overrides the default
+	err = settingsRepo.Upsert(context.Background(), &models.PurchaseExpirySettings{UserID: 3101, TimeoutHours: 200})
+	assert.NoError(t, err)
+
+	stale, err = repo.GetStalePurchases(context.Background(), 72)
+	assert.NoError(t, err)
+	assert.Len(t, stale, 0)
+
+	// Zero disables expiry entirely
+	err = settingsRepo.Upsert(context.Background(), &models.PurchaseExpirySettings{UserID: 3101, TimeoutHours: 0})
+	assert.NoError(t, err)
+
+	stale, err = repo.GetStalePurchases(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, stale, 0)
+
+	settings, err := settingsRepo.Get(context.Background(), 3101)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, settings.TimeoutHours)
+
+	settings, err = settingsRepo.Get(context.Background(), 3100)
+	assert.NoError(t, err)
+	assert.Nil(t, settings)
+}
+
+func Test_PurchaseTransactions_CancelWithReason(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	item, err := setupPurchaseTestData(t, db, 3110, 3111, 51, 30000161)
+	assert.NoError(t, err)
+
+	repo := repositories.NewPurchaseTransactions(db)
+
+	tx, err := db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+
+	purchase := &models.PurchaseTransaction{
+		ForSaleItemID:     item.ID,
+		BuyerUserID:       3110,
+		SellerUserID:      3111,
+		TypeID:            51,
+		QuantityPurchased: 10,
+		PricePerUnit:      100,
+		TotalPrice:        1000,
+		Status:            "pending",
+	}
+	err = repo.Create(context.Background(), tx, purchase)
+	assert.NoError(t, err)
+	err = tx.Commit()
+	assert.NoError(t, err)
+
+	tx, err = db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	err = repo.CancelWithReason(context.Background(), tx, purchase.ID, "expired")
+	assert.NoError(t, err)
+	err = tx.Commit()
+	assert.NoError(t, err)
+
+	retrieved, err := repo.GetByID(context.Background(), purchase.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "cancelled", retrieved.Status)
+	assert.NotNil(t, retrieved.CancellationReason)
+	assert.Equal(t, "expired", *retrieved.CancellationReason)
+	assert.NotNil(t, retrieved.CancelledAt)
+
+	// Already cancelled purchases are not touched again
+	tx, err = db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	defer tx.Rollback()
+	err = repo.CancelWithReason(context.Background(), tx, purchase.ID, "expired")
+	assert.Error(t, err)
+}
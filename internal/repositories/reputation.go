@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type Reputation struct {
+	db *sql.DB
+}
+
+func NewReputation(db *sql.DB) *Reputation {
+	return &Reputation{db: db}
+}
+
+// GetForUsers derives trading reputation from closed purchases. Every requested
+// user gets an entry, with zero stats if they have no purchase history.
+func (r *Reputation) GetForUsers(ctx context.Context, userIDs []int64) (map[int64]*models.Reputation, error) {
+	reputations := map[int64]*models.Reputation{}
+	if len(userIDs) == 0 {
+		return reputations, nil
+	}
+
+	for _, userID := range userIDs {
+		reputations[userID] = &models.Reputation{UserID: userID}
+	}
+
+	query := `
+		SELECT
+			p.user_id,
+			p.role,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE p.status = 'completed'),
+			COUNT(*) FILTER (WHERE p.status = 'cancelled' AND p.cancelled_by = 'buyer'),
+			COUNT(*) FILTER (WHERE p.status = 'cancelled' AND p.cancelled_by = 'seller'),
+			COUNT(*) FILTER (WHERE p.status = 'cancelled' AND p.cancelled_by = 'system'),
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (p.completed_at - p.purchased_at)) / 3600)
+				FILTER (WHERE p.status = 'completed' AND p.completed_at IS NOT NULL)
+		FROM (
+			SELECT seller_user_id AS user_id, 'seller' AS role, status, cancelled_by, purchased_at, completed_at
+			FROM purchase_transactions
+			WHERE seller_user_id = ANY($1) AND status IN ('completed', 'cancelled')
+
+			UNION ALL
+
+			SELECT buyer_user_id AS user_id, 'buyer' AS role, status, cancelled_by, purchased_at, completed_at
+			FROM purchase_transactions
+			WHERE buyer_user_id = ANY($1) AND status IN ('completed', 'cancelled')
+		) p
+		GROUP BY p.user_id, p.role
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query reputation")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int64
+		var role string
+		var total, completed, byBuyer, bySeller, expired int64
+		var medianHours sql.NullFloat64
+
+		err = rows.Scan(&userID, &role, &total, &completed, &byBuyer, &bySeller, &expired, &medianHours)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan reputation")
+		}
+
+		stats := models.ReputationStats{
+			Transactions:          total,
+			Completed:             completed,
+			CompletionRate:        rate(completed, total),
+			CancelledByBuyerRate:  rate(byBuyer, total),
+			CancelledBySellerRate: rate(bySeller, total),
+			ExpiredRate:           rate(expired, total),
+		}
+		if medianHours.Valid {
+			stats.MedianCompletionHours = &medianHours.Float64
+		}
+
+		reputation, ok := reputations[userID]
+		if !ok {
+			continue
+		}
+		if role == "seller" {
+			reputation.AsSeller = stats
+		} else {
+			reputation.AsBuyer = stats
+		}
+	}
+
+	return reputations, nil
+}
+
+func rate(count, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Reputation_ShouldDeriveFromClosedPurchases(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	item, err := setupPurchaseTestData(t, db, 3500, 3501, 45, 30000155)
+	assert.NoError(t, err)
+
+	purchasesRepo := repositories.NewPurchaseTransactions(db)
+	reputationRepo := repositories.NewReputation(db)
+
+	createPurchase := func() *models.PurchaseTransaction {
+		tx, err := db.BeginTx(context.Background(), nil)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		purchase := &models.PurchaseTransaction{
+			ForSaleItemID:     item.ID,
+			BuyerUserID:       3500,
+			SellerUserID:      3501,
+			TypeID:            45,
+			QuantityPurchased: 1,
+			PricePerUnit:      100,
+			TotalPrice:        100,
+			Status:            "pending",
+		}
+		assert.NoError(t, purchasesRepo.Create(context.Background(), tx, purchase))
+		assert.NoError(t, tx.Commit())
+		return purchase
+	}
+
+	cancel := func(purchaseID int64, by string) {
+		tx, err := db.BeginTx(context.Background(), nil)
+		assert.NoError(t, err)
+		defer tx.Rollback()
+
+		if by == "system" {
+			assert.NoError(t, purchasesRepo.CancelWithReason(context.Background(), tx, purchaseID, "expired"))
+		} else {
+			assert.NoError(t, purchasesRepo.CancelByParticipant(context.Background(), tx, purchaseID, by))
+		}
+		assert.NoError(t, tx.Commit())
+	}
+
+	// Two completed, one cancelled by each side and one expired
+	for i := 0; i < 2; i++ {
+		purchase := createPurchase()
+		assert.NoError(t, purchasesRepo.UpdateStatus(context.Background(), purchase.ID, "contract_created"))
+		assert.NoError(t, purchasesRepo.UpdateStatus(context.Background(), purchase.ID, "completed"))
+	}
+	cancel(createPurchase().ID, "buyer")
+	cancel(createPurchase().ID, "seller")
+	cancel(createPurchase().ID, "system")
+
+	// Still open - not counted
+	createPurchase()
+
+	reputations, err := reputationRepo.GetForUsers(context.Background(), []int64{3500, 3501, 9999})
+	assert.NoError(t, err)
+	assert.Len(t, reputations, 3)
+
+	seller := reputations[3501].AsSeller
+	assert.Equal(t, int64(5), seller.Transactions)
+	assert.Equal(t, int64(2), seller.Completed)
+	assert.InDelta(t, 0.4, seller.CompletionRate, 0.0001)
+	assert.InDelta(t, 0.2, seller.CancelledByBuyerRate, 0.0001)
+	assert.InDelta(t, 0.2, seller.CancelledBySellerRate, 0.0001)
+	assert.InDelta(t, 0.2, seller.ExpiredRate, 0.0001)
+	assert.NotNil(t, seller.MedianCompletionHours)
+	assert.Equal(t, int64(0), reputations[3501].AsBuyer.Transactions)
+
+	buyer := reputations[3500].AsBuyer
+	assert.Equal(t, int64(5), buyer.Transactions)
+	assert.InDelta(t, 0.4, buyer.CompletionRate, 0.0001)
+
+	// No history
+	assert.Equal(t, int64(0), reputations[9999].AsSeller.Transactions)
+	assert.Nil(t, reputations[9999].AsSeller.MedianCompletionHours)
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"strconv"
 	"time"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
@@ -17,88 +18,164 @@ func NewSalesAnalytics(db *sql.DB) *SalesAnalytics {
 	return &SalesAnalytics{db: db}
 }
 
-// GetSalesMetrics returns aggregated sales metrics for a given time period
-func (r *SalesAnalytics) GetSalesMetrics(ctx context.Context, sellerUserID int64, periodDays int) (*models.SalesMetrics, error) {
-	var startDate time.Time
-	if periodDays > 0 {
-		startDate = time.Now().AddDate(0, 0, -periodDays)
-	}
+// Top item orderings accepted by GetTopItems
+const (
+	TopItemsByRevenue = "revenue"
+	TopItemsByProfit  = "profit"
+	TopItemsByMargin  = "margin"
+)
 
-	metrics := &models.SalesMetrics{}
+var topItemsOrderings = map[string]string{
+	TopItemsByRevenue: "revenue DESC",
+	TopItemsByProfit:  "(revenue - uncosted_revenue - total_cost) DESC",
+	TopItemsByMargin:  "(revenue - uncosted_revenue - total_cost)::DOUBLE PRECISION / NULLIF(revenue - uncosted_revenue, 0) DESC NULLS LAST, revenue DESC",
+}
 
-	// Get overall metrics
-	query := `
-		SELECT
-			COALESCE(SUM(total_price), 0) as total_revenue,
-			COUNT(*) as total_transactions,
-			COALESCE(SUM(quantity_purchased), 0) as total_quantity_sold,
-			COUNT(DISTINCT type_id) as unique_item_types,
-			COUNT(DISTINCT buyer_user_id) as unique_buyers
-		FROM purchase_transactions
-		WHERE seller_user_id = $1
-			AND status = 'completed'
+// IsValidTopItemsOrdering reports whether sortBy is a supported GetTopItems ordering
+func IsValidTopItemsOrdering(sortBy string) bool {
+	_, ok := topItemsOrderings[sortBy]
+	return ok
+}
+
+// costedSalesQuery opens a "sales" CTE with the seller's completed sales and a per-unit
+// cost basis for each one. The basis is, in order of preference: the build cost the seller
+// entered on the listing, the average price the seller paid for the type on the marketplace
+// before the sale, or the Jita buy price on the day the item was listed. Sales with none
+// of these have a NULL cost. filters is appended to the WHERE clause.
+func costedSalesQuery(filters string) string {
+	return `
+		WITH sales AS (
+			SELECT
+				pt.type_id,
+				pt.buyer_user_id,
+				pt.quantity_purchased,
+				pt.total_price,
+				pt.price_per_unit,
+				pt.purchased_at,
+				COALESCE(fsi.build_cost_per_unit, acquired.cost_per_unit, jita.buy_price) AS cost_per_unit
+			FROM purchase_transactions pt
+			LEFT JOIN for_sale_items fsi ON fsi.id = pt.for_sale_item_id
+			LEFT JOIN LATERAL (
+				SELECT SUM(bought.total_price)::DOUBLE PRECISION / NULLIF(SUM(bought.quantity_purchased), 0) AS cost_per_unit
+				FROM purchase_transactions bought
+				WHERE bought.buyer_user_id = pt.seller_user_id
+					AND bought.type_id = pt.type_id
+					AND bought.status = 'completed'
+					AND bought.purchased_at <= pt.purchased_at
+			) acquired ON true
+			LEFT JOIN LATERAL (
+				SELECT h.buy_price
+				FROM market_price_history h
+				WHERE h.type_id = pt.type_id
+					AND h.region_id = 10000002
+					AND h.recorded_on <= COALESCE(fsi.created_at, pt.purchased_at)::DATE
+				ORDER BY h.recorded_on DESC
+				LIMIT 1
+			) jita ON true
+			WHERE pt.seller_user_id = $1
+				AND pt.status = 'completed'
+				` + filters + `
+		)
 	`
+}
 
-	args := []interface{}{sellerUserID}
-	if periodDays > 0 {
-		query += " AND purchased_at >= $2"
-		args = append(args, startDate)
+// costColumns aggregates the cost and the revenue without a cost basis over rows of "sales"
+const costColumns = `
+			COALESCE(ROUND(SUM(s.quantity_purchased * s.cost_per_unit)), 0)::BIGINT as total_cost,
+			COALESCE(SUM(s.total_price) FILTER (WHERE s.cost_per_unit IS NULL), 0) as uncosted_revenue`
+
+// grossProfit returns the profit and margin over the revenue that has a cost basis
+func grossProfit(revenue, uncostedRevenue, cost int64) (int64, float64) {
+	costedRevenue := revenue - uncostedRevenue
+	profit := costedRevenue - cost
+	if costedRevenue <= 0 {
+		return profit, 0
 	}
+	return profit, float64(profit) / float64(costedRevenue)
+}
 
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(
-		&metrics.TotalRevenue,
-		&metrics.TotalTransactions,
-		&metrics.TotalQuantitySold,
-		&metrics.UniqueItemTypes,
-		&metrics.UniqueBuyers,
-	)
+// GetSalesMetrics returns aggregated sales metrics and a bucketed time series for a range
+func (r *SalesAnalytics) GetSalesMetrics(ctx context.Context, sellerUserID int64, rng AnalyticsRange) (*models.SalesMetrics, error) {
+	summary, err := r.getSalesSummary(ctx, sellerUserID, rng)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get sales metrics")
+		return nil, err
 	}
 
-	// Get time series data (daily aggregates)
-	timeSeriesQuery := `
-		SELECT
-			DATE(purchased_at) as date,
-			COALESCE(SUM(total_price), 0) as revenue,
-			COUNT(*) as transactions,
-			COALESCE(SUM(quantity_purchased), 0) as quantity_sold
-		FROM purchase_transactions
-		WHERE seller_user_id = $1
-			AND status = 'completed'
-	`
+	metrics := &models.SalesMetrics{
+		TotalRevenue:      summary.TotalRevenue,
+		TotalTransactions: summary.TotalTransactions,
+		TotalQuantitySold: summary.TotalQuantitySold,
+		UniqueItemTypes:   summary.UniqueItemTypes,
+		UniqueBuyers:      summary.UniqueBuyers,
+		TotalCost:         summary.TotalCost,
+		GrossProfit:       summary.GrossProfit,
+		ProfitMargin:      summary.ProfitMargin,
+		UncostedRevenue:   summary.UncostedRevenue,
+		Bucket:            rng.bucket(),
+	}
 
-	timeSeriesArgs := []interface{}{sellerUserID}
-	if periodDays > 0 {
-		timeSeriesQuery += " AND purchased_at >= $2"
-		timeSeriesArgs = append(timeSeriesArgs, startDate)
+	if prev, ok := rng.previous(); ok && rng.ComparePrevious {
+		metrics.Previous, err = r.getSalesSummary(ctx, sellerUserID, prev)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get previous period")
+		}
 	}
 
-	timeSeriesQuery += `
-		GROUP BY DATE(purchased_at)
-		ORDER BY DATE(purchased_at) ASC
+	filters, args := rng.filters([]interface{}{sellerUserID})
+	bucket, args := rng.bucketExpression("s.purchased_at", args)
+
+	timeSeriesQuery := costedSalesQuery(filters) + `
+		SELECT
+			` + bucket + ` as bucket,
+			COALESCE(SUM(s.total_price), 0) as revenue,
+			COUNT(*) as transactions,
+			COALESCE(SUM(s.quantity_purchased), 0) as quantity_sold,` + costColumns + `
+		FROM sales s
+		GROUP BY 1
+		ORDER BY 1 ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, timeSeriesQuery, timeSeriesArgs...)
+	rows, err := r.db.QueryContext(ctx, timeSeriesQuery, args...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get time series data")
 	}
 	defer rows.Close()
 
-	metrics.TimeSeriesData = []models.TimeSeriesData{}
+	var first *time.Time
+	byBucket := map[string]models.TimeSeriesData{}
 	for rows.Next() {
 		var ts models.TimeSeriesData
-		var date time.Time
-		err = rows.Scan(&date, &ts.Revenue, &ts.Transactions, &ts.QuantitySold)
+		var start time.Time
+		var uncostedRevenue int64
+		err = rows.Scan(&start, &ts.Revenue, &ts.Transactions, &ts.QuantitySold, &ts.Cost, &uncostedRevenue)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan time series data")
 		}
-		ts.Date = date.Format("2006-01-02")
+		if first == nil {
+			first = &start
+		}
+		ts.Date = rng.bucketLabel(start)
+		ts.GrossProfit, _ = grossProfit(ts.Revenue, uncostedRevenue, ts.Cost)
+		byBucket[ts.Date] = ts
+	}
+
+	labels, err := rng.bucketLabels(first)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zero-fill buckets without sales so charts keep an even time axis
+	metrics.TimeSeriesData = []models.TimeSeriesData{}
+	for _, label := range labels {
+		ts, ok := byBucket[label]
+		if !ok {
+			ts = models.TimeSeriesData{Date: label}
+		}
 		metrics.TimeSeriesData = append(metrics.TimeSeriesData, ts)
 	}
 
 	// Get top items
-	topItems, err := r.GetTopItems(ctx, sellerUserID, periodDays, 10)
+	topItems, err := r.GetTopItems(ctx, sellerUserID, rng, 10, TopItemsByRevenue)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get top items")
 	}
@@ -107,36 +184,81 @@ func (r *SalesAnalytics) GetSalesMetrics(ctx context.Context, sellerUserID int64
 	return metrics, nil
 }
 
-// GetTopItems returns the top selling items by revenue
-func (r *SalesAnalytics) GetTopItems(ctx context.Context, sellerUserID int64, periodDays int, limit int) ([]models.ItemSalesData, error) {
-	var startDate time.Time
-	if periodDays > 0 {
-		startDate = time.Now().AddDate(0, 0, -periodDays)
-	}
+func (r *SalesAnalytics) getSalesSummary(ctx context.Context, sellerUserID int64, rng AnalyticsRange) (*models.SalesSummary, error) {
+	filters, args := rng.filters([]interface{}{sellerUserID})
 
-	query := `
+	query := costedSalesQuery(filters) + `
 		SELECT
-			pt.type_id,
+			COALESCE(SUM(s.total_price), 0) as total_revenue,
+			COUNT(*) as total_transactions,
+			COALESCE(SUM(s.quantity_purchased), 0) as total_quantity_sold,
+			COUNT(DISTINCT s.type_id) as unique_item_types,
+			COUNT(DISTINCT s.buyer_user_id) as unique_buyers,` + costColumns + `
+		FROM sales s
+	`
+
+	summary := &models.SalesSummary{}
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(
+		&summary.TotalRevenue,
+		&summary.TotalTransactions,
+		&summary.TotalQuantitySold,
+		&summary.UniqueItemTypes,
+		&summary.UniqueBuyers,
+		&summary.TotalCost,
+		&summary.UncostedRevenue,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sales metrics")
+	}
+	summary.GrossProfit, summary.ProfitMargin = grossProfit(summary.TotalRevenue, summary.UncostedRevenue, summary.TotalCost)
+
+	return summary, nil
+}
+
+// itemSalesColumns aggregates rows of "sales" joined to asset_item_types t per item type
+const itemSalesColumns = `
+			s.type_id,
 			t.type_name,
-			COALESCE(SUM(pt.quantity_purchased), 0) as quantity_sold,
-			COALESCE(SUM(pt.total_price), 0) as revenue,
+			COALESCE(SUM(s.quantity_purchased), 0) as quantity_sold,
+			COALESCE(SUM(s.total_price), 0) as revenue,
 			COUNT(*) as transaction_count,
-			COALESCE(AVG(pt.price_per_unit), 0)::BIGINT as avg_price_per_unit
-		FROM purchase_transactions pt
-		JOIN asset_item_types t ON pt.type_id = t.type_id
-		WHERE pt.seller_user_id = $1
-			AND pt.status = 'completed'
-	`
+			COALESCE(AVG(s.price_per_unit), 0)::BIGINT as avg_price_per_unit,` + costColumns
+
+func scanItemSalesData(row interface{ Scan(...any) error }, item *models.ItemSalesData) error {
+	err := row.Scan(
+		&item.TypeID,
+		&item.TypeName,
+		&item.QuantitySold,
+		&item.Revenue,
+		&item.TransactionCount,
+		&item.AveragePricePerUnit,
+		&item.TotalCost,
+		&item.UncostedRevenue,
+	)
+	if err != nil {
+		return err
+	}
+	item.GrossProfit, item.ProfitMargin = grossProfit(item.Revenue, item.UncostedRevenue, item.TotalCost)
+	return nil
+}
 
-	args := []interface{}{sellerUserID}
-	if periodDays > 0 {
-		query += " AND pt.purchased_at >= $2"
-		args = append(args, startDate)
+// GetTopItems returns the top selling items ordered by revenue, gross profit or margin
+func (r *SalesAnalytics) GetTopItems(ctx context.Context, sellerUserID int64, rng AnalyticsRange, limit int, sortBy string) ([]models.ItemSalesData, error) {
+	ordering, ok := topItemsOrderings[sortBy]
+	if !ok {
+		return nil, errors.Errorf("unknown top items ordering %q", sortBy)
 	}
 
-	query += `
-		GROUP BY pt.type_id, t.type_name
-		ORDER BY revenue DESC
+	filters, args := rng.filters([]interface{}{sellerUserID})
+
+	query := costedSalesQuery(filters) + `
+		SELECT * FROM (
+			SELECT` + itemSalesColumns + `
+			FROM sales s
+			JOIN asset_item_types t ON s.type_id = t.type_id
+			GROUP BY s.type_id, t.type_name
+		) items
+		ORDER BY ` + ordering + `
 		LIMIT $` + getNextParamNum(args)
 
 	args = append(args, limit)
@@ -150,14 +272,7 @@ func (r *SalesAnalytics) GetTopItems(ctx context.Context, sellerUserID int64, pe
 	items := []models.ItemSalesData{}
 	for rows.Next() {
 		var item models.ItemSalesData
-		err = rows.Scan(
-			&item.TypeID,
-			&item.TypeName,
-			&item.QuantitySold,
-			&item.Revenue,
-			&item.TransactionCount,
-			&item.AveragePricePerUnit,
-		)
+		err = scanItemSalesData(rows, &item)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan item sales data")
 		}
@@ -168,16 +283,14 @@ func (r *SalesAnalytics) GetTopItems(ctx context.Context, sellerUserID int64, pe
 }
 
 // GetBuyerAnalytics returns analytics about buyers
-func (r *SalesAnalytics) GetBuyerAnalytics(ctx context.Context, sellerUserID int64, periodDays int, limit int) ([]models.BuyerAnalytics, error) {
-	var startDate time.Time
-	if periodDays > 0 {
-		startDate = time.Now().AddDate(0, 0, -periodDays)
-	}
-
+func (r *SalesAnalytics) GetBuyerAnalytics(ctx context.Context, sellerUserID int64, rng AnalyticsRange, limit int) ([]models.BuyerAnalytics, error) {
 	query := `
 		SELECT
 			pt.buyer_user_id,
-			COALESCE(MAX(c.name), CONCAT('User ', pt.buyer_user_id)) as buyer_name,
+			COALESCE(
+				(SELECT c.name FROM characters c WHERE c.user_id = pt.buyer_user_id ORDER BY c.id LIMIT 1),
+				CONCAT('User ', pt.buyer_user_id)
+			) as buyer_name,
 			COALESCE(SUM(pt.total_price), 0) as total_spent,
 			COUNT(*) as total_purchases,
 			COALESCE(SUM(pt.quantity_purchased), 0) as total_quantity,
@@ -185,18 +298,12 @@ func (r *SalesAnalytics) GetBuyerAnalytics(ctx context.Context, sellerUserID int
 			MAX(pt.purchased_at) as last_purchase_date,
 			CASE WHEN COUNT(*) > 1 THEN true ELSE false END as repeat_customer
 		FROM purchase_transactions pt
-		LEFT JOIN characters c ON pt.buyer_user_id = c.user_id
 		WHERE pt.seller_user_id = $1
 			AND pt.status = 'completed'
 	`
 
-	args := []interface{}{sellerUserID}
-	if periodDays > 0 {
-		query += " AND pt.purchased_at >= $2"
-		args = append(args, startDate)
-	}
-
-	query += `
+	filters, args := rng.filters([]interface{}{sellerUserID})
+	query += filters + `
 		GROUP BY pt.buyer_user_id
 		ORDER BY total_spent DESC
 		LIMIT $` + getNextParamNum(args)
@@ -232,65 +339,312 @@ func (r *SalesAnalytics) GetBuyerAnalytics(ctx context.Context, sellerUserID int
 }
 
 // GetItemSalesHistory returns sales history for a specific item type
-func (r *SalesAnalytics) GetItemSalesHistory(ctx context.Context, sellerUserID int64, typeID int64, periodDays int) (*models.ItemSalesData, error) {
-	var startDate time.Time
-	if periodDays > 0 {
-		startDate = time.Now().AddDate(0, 0, -periodDays)
+func (r *SalesAnalytics) GetItemSalesHistory(ctx context.Context, sellerUserID int64, typeID int64, rng AnalyticsRange) (*models.ItemSalesData, error) {
+	filters, args := rng.filters([]interface{}{sellerUserID, typeID})
+	filters = " AND pt.type_id = $2" + filters
+
+	query := costedSalesQuery(filters) + `
+		SELECT` + itemSalesColumns + `
+		FROM sales s
+		JOIN asset_item_types t ON s.type_id = t.type_id
+		GROUP BY s.type_id, t.type_name
+	`
+
+	var item models.ItemSalesData
+	err := scanItemSalesData(r.db.QueryRowContext(ctx, query, args...), &item)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.New("no sales data found for this item")
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get item sales history")
 	}
 
-	query := `
+	return &item, nil
+}
+
+// pricedPurchasesQuery opens a "purchases" CTE with the buyer's completed purchases and the
+// Jita sell price on the day of each purchase, from the latest snapshot on or before it.
+// Purchases made before any snapshot have a NULL jita_price. filters is appended to the
+// WHERE clause.
+func pricedPurchasesQuery(filters string) string {
+	return `
+		WITH purchases AS (
+			SELECT
+				pt.type_id,
+				pt.seller_user_id,
+				pt.quantity_purchased,
+				pt.total_price,
+				pt.price_per_unit,
+				pt.purchased_at,
+				jita.sell_price AS jita_price
+			FROM purchase_transactions pt
+			LEFT JOIN LATERAL (
+				SELECT h.sell_price
+				FROM market_price_history h
+				WHERE h.type_id = pt.type_id
+					AND h.region_id = 10000002
+					AND h.recorded_on <= pt.purchased_at::DATE
+				ORDER BY h.recorded_on DESC
+				LIMIT 1
+			) jita ON true
+			WHERE pt.buyer_user_id = $1
+				AND pt.status = 'completed'
+				` + filters + `
+		)
+	`
+}
+
+// jitaColumns aggregates the Jita value and the spend without a Jita price over rows of "purchases"
+const jitaColumns = `
+			COALESCE(ROUND(SUM(p.quantity_purchased * p.jita_price)), 0)::BIGINT as jita_value,
+			COALESCE(SUM(p.total_price) FILTER (WHERE p.jita_price IS NULL), 0) as unpriced_spend`
+
+// jitaComparison returns what the buyer saved against Jita and the ratio of the priced
+// spend to its Jita value
+func jitaComparison(spent, unpricedSpend, jitaValue int64) (int64, float64) {
+	pricedSpend := spent - unpricedSpend
+	savings := jitaValue - pricedSpend
+	if jitaValue <= 0 {
+		return savings, 0
+	}
+	return savings, float64(pricedSpend) / float64(jitaValue)
+}
+
+// GetPurchaseMetrics returns aggregated spending metrics and a bucketed time series for a buyer over a range
+func (r *SalesAnalytics) GetPurchaseMetrics(ctx context.Context, buyerUserID int64, rng AnalyticsRange) (*models.PurchaseMetrics, error) {
+	summary, err := r.getPurchaseSummary(ctx, buyerUserID, rng)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := &models.PurchaseMetrics{
+		TotalSpent:          summary.TotalSpent,
+		TotalPurchases:      summary.TotalPurchases,
+		TotalQuantityBought: summary.TotalQuantityBought,
+		UniqueItemTypes:     summary.UniqueItemTypes,
+		UniqueSellers:       summary.UniqueSellers,
+		JitaValue:           summary.JitaValue,
+		SavingsVsJita:       summary.SavingsVsJita,
+		PriceVsJita:         summary.PriceVsJita,
+		UnpricedSpend:       summary.UnpricedSpend,
+		Bucket:              rng.bucket(),
+	}
+
+	if prev, ok := rng.previous(); ok && rng.ComparePrevious {
+		metrics.Previous, err = r.getPurchaseSummary(ctx, buyerUserID, prev)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get previous period")
+		}
+	}
+
+	filters, args := rng.filters([]interface{}{buyerUserID})
+	bucket, args := rng.bucketExpression("p.purchased_at", args)
+
+	timeSeriesQuery := pricedPurchasesQuery(filters) + `
 		SELECT
-			pt.type_id,
-			t.type_name,
-			COALESCE(SUM(pt.quantity_purchased), 0) as quantity_sold,
-			COALESCE(SUM(pt.total_price), 0) as revenue,
-			COUNT(*) as transaction_count,
-			COALESCE(AVG(pt.price_per_unit), 0)::BIGINT as avg_price_per_unit
-		FROM purchase_transactions pt
-		JOIN asset_item_types t ON pt.type_id = t.type_id
-		WHERE pt.seller_user_id = $1
-			AND pt.type_id = $2
-			AND pt.status = 'completed'
+			` + bucket + ` as bucket,
+			COALESCE(SUM(p.total_price), 0) as spent,
+			COUNT(*) as purchases,
+			COALESCE(SUM(p.quantity_purchased), 0) as quantity_bought,
+			COALESCE(ROUND(SUM(p.quantity_purchased * p.jita_price)), 0)::BIGINT as jita_value
+		FROM purchases p
+		GROUP BY 1
+		ORDER BY 1 ASC
 	`
 
-	args := []interface{}{sellerUserID, typeID}
-	if periodDays > 0 {
-		query += " AND pt.purchased_at >= $3"
-		args = append(args, startDate)
+	rows, err := r.db.QueryContext(ctx, timeSeriesQuery, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchase time series data")
+	}
+	defer rows.Close()
+
+	var first *time.Time
+	byBucket := map[string]models.PurchaseTimeSeriesData{}
+	for rows.Next() {
+		var ts models.PurchaseTimeSeriesData
+		var start time.Time
+		err = rows.Scan(&start, &ts.Spent, &ts.Purchases, &ts.QuantityBought, &ts.JitaValue)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan purchase time series data")
+		}
+		if first == nil {
+			first = &start
+		}
+		ts.Date = rng.bucketLabel(start)
+		byBucket[ts.Date] = ts
+	}
+
+	labels, err := rng.bucketLabels(first)
+	if err != nil {
+		return nil, err
 	}
 
-	query += " GROUP BY pt.type_id, t.type_name"
+	metrics.TimeSeriesData = []models.PurchaseTimeSeriesData{}
+	for _, label := range labels {
+		ts, ok := byBucket[label]
+		if !ok {
+			ts = models.PurchaseTimeSeriesData{Date: label}
+		}
+		metrics.TimeSeriesData = append(metrics.TimeSeriesData, ts)
+	}
 
-	var item models.ItemSalesData
+	topItems, err := r.GetTopItemsPurchased(ctx, buyerUserID, rng, 10)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get top items purchased")
+	}
+	metrics.TopItems = topItems
+
+	topSellers, err := r.GetSellerAnalytics(ctx, buyerUserID, rng, 10)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get seller analytics")
+	}
+	metrics.TopSellers = topSellers
+
+	return metrics, nil
+}
+
+func (r *SalesAnalytics) getPurchaseSummary(ctx context.Context, buyerUserID int64, rng AnalyticsRange) (*models.PurchaseSummary, error) {
+	filters, args := rng.filters([]interface{}{buyerUserID})
+
+	query := pricedPurchasesQuery(filters) + `
+		SELECT
+			COALESCE(SUM(p.total_price), 0) as total_spent,
+			COUNT(*) as total_purchases,
+			COALESCE(SUM(p.quantity_purchased), 0) as total_quantity_bought,
+			COUNT(DISTINCT p.type_id) as unique_item_types,
+			COUNT(DISTINCT p.seller_user_id) as unique_sellers,` + jitaColumns + `
+		FROM purchases p
+	`
+
+	summary := &models.PurchaseSummary{}
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(
-		&item.TypeID,
-		&item.TypeName,
-		&item.QuantitySold,
-		&item.Revenue,
-		&item.TransactionCount,
-		&item.AveragePricePerUnit,
+		&summary.TotalSpent,
+		&summary.TotalPurchases,
+		&summary.TotalQuantityBought,
+		&summary.UniqueItemTypes,
+		&summary.UniqueSellers,
+		&summary.JitaValue,
+		&summary.UnpricedSpend,
 	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get purchase metrics")
+	}
+	summary.SavingsVsJita, summary.PriceVsJita = jitaComparison(summary.TotalSpent, summary.UnpricedSpend, summary.JitaValue)
 
-	if err == sql.ErrNoRows {
-		return nil, errors.New("no sales data found for this item")
+	return summary, nil
+}
+
+// GetTopItemsPurchased returns the items a buyer spent the most on, with the average price
+// paid next to the average Jita price at purchase time
+func (r *SalesAnalytics) GetTopItemsPurchased(ctx context.Context, buyerUserID int64, rng AnalyticsRange, limit int) ([]models.ItemPurchaseData, error) {
+	filters, args := rng.filters([]interface{}{buyerUserID})
+
+	query := pricedPurchasesQuery(filters) + `
+		SELECT
+			p.type_id,
+			t.type_name,
+			COALESCE(SUM(p.quantity_purchased), 0) as quantity_bought,
+			COALESCE(SUM(p.total_price), 0) as total_spent,
+			COUNT(*) as purchase_count,
+			COALESCE(SUM(p.total_price) / NULLIF(SUM(p.quantity_purchased), 0), 0)::BIGINT as avg_price_paid,
+			COALESCE(
+				SUM(p.quantity_purchased * p.jita_price) / NULLIF(SUM(p.quantity_purchased) FILTER (WHERE p.jita_price IS NOT NULL), 0),
+				0
+			)::BIGINT as avg_jita_price,` + jitaColumns + `
+		FROM purchases p
+		JOIN asset_item_types t ON p.type_id = t.type_id
+		GROUP BY p.type_id, t.type_name
+		ORDER BY total_spent DESC
+		LIMIT $` + getNextParamNum(args)
+
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get top items purchased")
 	}
+	defer rows.Close()
+
+	items := []models.ItemPurchaseData{}
+	for rows.Next() {
+		var item models.ItemPurchaseData
+		err = rows.Scan(
+			&item.TypeID,
+			&item.TypeName,
+			&item.QuantityBought,
+			&item.TotalSpent,
+			&item.PurchaseCount,
+			&item.AveragePricePaid,
+			&item.AverageJitaPrice,
+			&item.JitaValue,
+			&item.UnpricedSpend,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan item purchase data")
+		}
+		item.SavingsVsJita, item.PriceVsJita = jitaComparison(item.TotalSpent, item.UnpricedSpend, item.JitaValue)
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetSellerAnalytics returns the sellers a buyer spent the most with and how their
+// prices compared to Jita
+func (r *SalesAnalytics) GetSellerAnalytics(ctx context.Context, buyerUserID int64, rng AnalyticsRange, limit int) ([]models.SellerAnalytics, error) {
+	filters, args := rng.filters([]interface{}{buyerUserID})
+
+	query := pricedPurchasesQuery(filters) + `
+		SELECT
+			p.seller_user_id,
+			COALESCE(
+				(SELECT c.name FROM characters c WHERE c.user_id = p.seller_user_id ORDER BY c.id LIMIT 1),
+				CONCAT('User ', p.seller_user_id)
+			) as seller_name,
+			COALESCE(SUM(p.total_price), 0) as total_spent,
+			COUNT(*) as total_purchases,
+			COALESCE(SUM(p.quantity_purchased), 0) as total_quantity,
+			MIN(p.purchased_at) as first_purchase_date,
+			MAX(p.purchased_at) as last_purchase_date,` + jitaColumns + `
+		FROM purchases p
+		GROUP BY p.seller_user_id
+		ORDER BY total_spent DESC
+		LIMIT $` + getNextParamNum(args)
+
+	args = append(args, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get item sales history")
+		return nil, errors.Wrap(err, "failed to get seller analytics")
 	}
+	defer rows.Close()
 
-	return &item, nil
+	sellers := []models.SellerAnalytics{}
+	for rows.Next() {
+		var seller models.SellerAnalytics
+		err = rows.Scan(
+			&seller.SellerUserID,
+			&seller.SellerName,
+			&seller.TotalSpent,
+			&seller.TotalPurchases,
+			&seller.TotalQuantity,
+			&seller.FirstPurchaseDate,
+			&seller.LastPurchaseDate,
+			&seller.JitaValue,
+			&seller.UnpricedSpend,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan seller analytics")
+		}
+		seller.SavingsVsJita, seller.PriceVsJita = jitaComparison(seller.TotalSpent, seller.UnpricedSpend, seller.JitaValue)
+		sellers = append(sellers, seller)
+	}
+
+	return sellers, nil
 }
 
 // Helper function to get next parameter number for SQL query
 func getNextParamNum(args []interface{}) string {
-	switch len(args) {
-	case 0:
-		return "1"
-	case 1:
-		return "2"
-	case 2:
-		return "3"
-	default:
-		return "4"
-	}
+	return strconv.Itoa(len(args) + 1)
 }
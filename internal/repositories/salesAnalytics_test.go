@@ -71,7 +71,7 @@ func TestSalesAnalytics_GetSalesMetrics(t *testing.T) {
 	analyticsRepo := repositories.NewSalesAnalytics(db)
 
 	t.Run("Get metrics for all time", func(t *testing.T) {
-		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, 0)
+		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, repositories.LastDays(0))
 		require.NoError(t, err)
 
 		assert.Equal(t, int64(200000), metrics.TotalRevenue)
@@ -84,7 +84,7 @@ func TestSalesAnalytics_GetSalesMetrics(t *testing.T) {
 	})
 
 	t.Run("Get metrics for 30 days", func(t *testing.T) {
-		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, 30)
+		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, repositories.LastDays(30))
 		require.NoError(t, err)
 
 		assert.Equal(t, int64(200000), metrics.TotalRevenue)
@@ -161,7 +161,7 @@ func TestSalesAnalytics_GetTopItems(t *testing.T) {
 	analyticsRepo := repositories.NewSalesAnalytics(db)
 
 	t.Run("Get top items", func(t *testing.T) {
-		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, 0, 10)
+		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, repositories.LastDays(0), 10, repositories.TopItemsByRevenue)
 		require.NoError(t, err)
 
 		assert.Len(t, items, 2) // 2 unique item types
@@ -182,7 +182,7 @@ func TestSalesAnalytics_GetTopItems(t *testing.T) {
 	})
 
 	t.Run("Limit top items", func(t *testing.T) {
-		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, 0, 1)
+		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, repositories.LastDays(0), 1, repositories.TopItemsByRevenue)
 		require.NoError(t, err)
 
 		assert.Len(t, items, 1)
@@ -214,6 +214,11 @@ func TestSalesAnalytics_GetBuyerAnalytics(t *testing.T) {
 	require.NoError(t, userRepo.Add(ctx, buyer1))
 	require.NoError(t, userRepo.Add(ctx, buyer2))
 
+	// Buyer1 has an alt, which must not multiply their totals
+	characterRepo := repositories.NewCharacterRepository(db)
+	require.NoError(t, characterRepo.Add(ctx, &repositories.Character{ID: 9990060, Name: "Buyer3 Main", UserID: buyer1.ID}))
+	require.NoError(t, characterRepo.Add(ctx, &repositories.Character{ID: 9990061, Name: "Buyer3 Alt", UserID: buyer1.ID}))
+
 	// Create purchase transactions
 	txRepo := repositories.NewPurchaseTransactions(db)
 
@@ -266,13 +271,14 @@ func TestSalesAnalytics_GetBuyerAnalytics(t *testing.T) {
 	analyticsRepo := repositories.NewSalesAnalytics(db)
 
 	t.Run("Get buyer analytics", func(t *testing.T) {
-		buyers, err := analyticsRepo.GetBuyerAnalytics(ctx, sellerUser.ID, 0, 10)
+		buyers, err := analyticsRepo.GetBuyerAnalytics(ctx, sellerUser.ID, repositories.LastDays(0), 10)
 		require.NoError(t, err)
 
 		assert.Len(t, buyers, 2)
 
 		// First buyer should be buyer1 (higher total spent)
 		assert.Equal(t, buyer1.ID, buyers[0].BuyerUserID)
+		assert.Equal(t, "Buyer3 Main", buyers[0].BuyerName)
 		assert.Equal(t, int64(200000), buyers[0].TotalSpent)
 		assert.Equal(t, int64(2), buyers[0].TotalPurchases)
 		assert.Equal(t, int64(150), buyers[0].TotalQuantity)
@@ -280,6 +286,7 @@ func TestSalesAnalytics_GetBuyerAnalytics(t *testing.T) {
 
 		// Second buyer should be buyer2
 		assert.Equal(t, buyer2.ID, buyers[1].BuyerUserID)
+		assert.Equal(t, "User 999007", buyers[1].BuyerName)
 		assert.Equal(t, int64(25000), buyers[1].TotalSpent)
 		assert.Equal(t, int64(1), buyers[1].TotalPurchases)
 		assert.Equal(t, int64(25), buyers[1].TotalQuantity)
@@ -287,7 +294,7 @@ func TestSalesAnalytics_GetBuyerAnalytics(t *testing.T) {
 	})
 
 	t.Run("Limit buyer analytics", func(t *testing.T) {
-		buyers, err := analyticsRepo.GetBuyerAnalytics(ctx, sellerUser.ID, 0, 1)
+		buyers, err := analyticsRepo.GetBuyerAnalytics(ctx, sellerUser.ID, repositories.LastDays(0), 1)
 		require.NoError(t, err)
 
 		assert.Len(t, buyers, 1)
@@ -364,7 +371,7 @@ func TestSalesAnalytics_GetItemSalesHistory(t *testing.T) {
 	analyticsRepo := repositories.NewSalesAnalytics(db)
 
 	t.Run("Get item sales history for Tritanium", func(t *testing.T) {
-		item, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 34, 0)
+		item, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 34, repositories.LastDays(0))
 		require.NoError(t, err)
 
 		assert.Equal(t, int64(34), item.TypeID)
@@ -376,7 +383,7 @@ func TestSalesAnalytics_GetItemSalesHistory(t *testing.T) {
 	})
 
 	t.Run("Get item sales history for Pyerite", func(t *testing.T) {
-		item, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 35, 0)
+		item, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 35, repositories.LastDays(0))
 		require.NoError(t, err)
 
 		assert.Equal(t, int64(35), item.TypeID)
@@ -388,8 +395,370 @@ func TestSalesAnalytics_GetItemSalesHistory(t *testing.T) {
 	})
 
 	t.Run("Get item sales history for non-existent item", func(t *testing.T) {
-		_, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 999, 0)
+		_, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 999, repositories.LastDays(0))
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no sales data found")
 	})
 }
+
+func TestSalesAnalytics_ProfitFromCostBasis(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	itemTypes := []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, IconID: nil},
+		{TypeID: 35, TypeName: "Pyerite", Volume: 0.0032, IconID: nil},
+		{TypeID: 36, TypeName: "Mexallon", Volume: 0.01, IconID: nil},
+		{TypeID: 37, TypeName: "Isogen", Volume: 0.01, IconID: nil},
+	}
+	require.NoError(t, itemTypeRepo.UpsertItemTypes(ctx, itemTypes))
+
+	userRepo := repositories.NewUserRepository(db)
+	sellerUser := &repositories.User{ID: 999020, Name: "ProfitSeller"}
+	buyerUser := &repositories.User{ID: 999021, Name: "ProfitBuyer"}
+	supplierUser := &repositories.User{ID: 999022, Name: "ProfitSupplier"}
+	require.NoError(t, userRepo.Add(ctx, sellerUser))
+	require.NoError(t, userRepo.Add(ctx, buyerUser))
+	require.NoError(t, userRepo.Add(ctx, supplierUser))
+
+	// Tritanium is costed from the build cost entered on the listing
+	buildCost := int64(6)
+	listing := &models.ForSaleItem{
+		UserID:            sellerUser.ID,
+		TypeID:            34,
+		OwnerType:         "character",
+		OwnerID:           sellerUser.ID,
+		LocationID:        60003760,
+		QuantityAvailable: 1000,
+		PricePerUnit:      10,
+		BuildCostPerUnit:  &buildCost,
+		IsActive:          true,
+	}
+	require.NoError(t, repositories.NewForSaleItems(db).Upsert(ctx, listing))
+
+	// Mexallon falls back to the Jita buy price before it was sold
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO market_price_history (type_id, region_id, recorded_on, buy_price, sell_price)
+		VALUES (36, 10000002, CURRENT_DATE - 1, 3, 4)
+	`)
+	require.NoError(t, err)
+
+	txRepo := repositories.NewPurchaseTransactions(db)
+	transactions := []*models.PurchaseTransaction{
+		// Pyerite bought by the seller on the marketplace at 8
+		{ForSaleItemID: 500, BuyerUserID: sellerUser.ID, SellerUserID: supplierUser.ID, TypeID: 35, QuantityPurchased: 100, PricePerUnit: 8, TotalPrice: 800, Status: "completed"},
+		{ForSaleItemID: listing.ID, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 34, QuantityPurchased: 100, PricePerUnit: 10, TotalPrice: 1000, Status: "completed"},
+		{ForSaleItemID: 501, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 35, QuantityPurchased: 50, PricePerUnit: 20, TotalPrice: 1000, Status: "completed"},
+		{ForSaleItemID: 502, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 36, QuantityPurchased: 10, PricePerUnit: 5, TotalPrice: 50, Status: "completed"},
+		// Isogen has no cost basis at all
+		{ForSaleItemID: 503, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 37, QuantityPurchased: 50, PricePerUnit: 10, TotalPrice: 500, Status: "completed"},
+	}
+
+	dbTx, err := db.Begin()
+	require.NoError(t, err)
+	for _, tx := range transactions {
+		require.NoError(t, txRepo.Create(ctx, dbTx, tx))
+	}
+	require.NoError(t, dbTx.Commit())
+
+	analyticsRepo := repositories.NewSalesAnalytics(db)
+
+	t.Run("Metrics include cost and profit", func(t *testing.T) {
+		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, repositories.LastDays(0))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2550), metrics.TotalRevenue)
+		assert.Equal(t, int64(1030), metrics.TotalCost)
+		assert.Equal(t, int64(500), metrics.UncostedRevenue)
+		assert.Equal(t, int64(1020), metrics.GrossProfit)
+		assert.InDelta(t, 1020.0/2050.0, metrics.ProfitMargin, 0.0001)
+
+		require.Len(t, metrics.TimeSeriesData, 1)
+		assert.Equal(t, int64(1030), metrics.TimeSeriesData[0].Cost)
+		assert.Equal(t, int64(1020), metrics.TimeSeriesData[0].GrossProfit)
+	})
+
+	t.Run("Top items by margin", func(t *testing.T) {
+		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, repositories.LastDays(0), 10, repositories.TopItemsByMargin)
+		require.NoError(t, err)
+		require.Len(t, items, 4)
+
+		assert.Equal(t, int64(35), items[0].TypeID)
+		assert.Equal(t, int64(400), items[0].TotalCost)
+		assert.Equal(t, int64(600), items[0].GrossProfit)
+		assert.InDelta(t, 0.6, items[0].ProfitMargin, 0.0001)
+
+		// Items without a cost basis sort last
+		assert.Equal(t, int64(37), items[3].TypeID)
+		assert.Equal(t, int64(500), items[3].UncostedRevenue)
+		assert.Equal(t, float64(0), items[3].ProfitMargin)
+	})
+
+	t.Run("Top items by profit", func(t *testing.T) {
+		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, repositories.LastDays(0), 2, repositories.TopItemsByProfit)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		assert.Equal(t, int64(35), items[0].TypeID)
+		assert.Equal(t, int64(34), items[1].TypeID)
+		assert.Equal(t, int64(400), items[1].GrossProfit)
+	})
+
+	t.Run("Item history uses the Jita fallback", func(t *testing.T) {
+		item, err := analyticsRepo.GetItemSalesHistory(ctx, sellerUser.ID, 36, repositories.LastDays(0))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(30), item.TotalCost)
+		assert.Equal(t, int64(20), item.GrossProfit)
+	})
+
+	t.Run("Unknown ordering", func(t *testing.T) {
+		_, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, repositories.LastDays(0), 10, "turnover")
+		assert.Error(t, err)
+	})
+}
+
+func TestSalesAnalytics_PurchaseMetricsComparedToJita(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	itemTypes := []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, IconID: nil},
+		{TypeID: 35, TypeName: "Pyerite", Volume: 0.0032, IconID: nil},
+	}
+	require.NoError(t, itemTypeRepo.UpsertItemTypes(ctx, itemTypes))
+
+	userRepo := repositories.NewUserRepository(db)
+	buyerUser := &repositories.User{ID: 999030, Name: "SpendBuyer"}
+	cheapSeller := &repositories.User{ID: 999031, Name: "CheapSeller"}
+	dearSeller := &repositories.User{ID: 999032, Name: "DearSeller"}
+	for _, u := range []*repositories.User{buyerUser, cheapSeller, dearSeller} {
+		require.NoError(t, userRepo.Add(ctx, u))
+	}
+
+	// An alt must not multiply the seller's totals
+	characterRepo := repositories.NewCharacterRepository(db)
+	require.NoError(t, characterRepo.Add(ctx, &repositories.Character{ID: 9990320, Name: "Dear Main", UserID: dearSeller.ID}))
+	require.NoError(t, characterRepo.Add(ctx, &repositories.Character{ID: 9990321, Name: "Dear Alt", UserID: dearSeller.ID}))
+
+	// Tritanium sold at 10 in Jita; Pyerite has no history
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO market_price_history (type_id, region_id, recorded_on, buy_price, sell_price)
+		VALUES (34, 10000002, CURRENT_DATE - 2, 8, 10)
+	`)
+	require.NoError(t, err)
+
+	txRepo := repositories.NewPurchaseTransactions(db)
+	transactions := []*models.PurchaseTransaction{
+		{ForSaleItemID: 600, BuyerUserID: buyerUser.ID, SellerUserID: cheapSeller.ID, TypeID: 34, QuantityPurchased: 100, PricePerUnit: 8, TotalPrice: 800, Status: "completed"},
+		{ForSaleItemID: 601, BuyerUserID: buyerUser.ID, SellerUserID: dearSeller.ID, TypeID: 34, QuantityPurchased: 100, PricePerUnit: 12, TotalPrice: 1200, Status: "completed"},
+		{ForSaleItemID: 602, BuyerUserID: buyerUser.ID, SellerUserID: dearSeller.ID, TypeID: 35, QuantityPurchased: 10, PricePerUnit: 30, TotalPrice: 300, Status: "completed"},
+		// Cancelled purchases are ignored
+		{ForSaleItemID: 603, BuyerUserID: buyerUser.ID, SellerUserID: cheapSeller.ID, TypeID: 34, QuantityPurchased: 100, PricePerUnit: 1, TotalPrice: 100, Status: "cancelled"},
+	}
+
+	dbTx, err := db.Begin()
+	require.NoError(t, err)
+	for _, tx := range transactions {
+		require.NoError(t, txRepo.Create(ctx, dbTx, tx))
+	}
+	require.NoError(t, dbTx.Commit())
+
+	analyticsRepo := repositories.NewSalesAnalytics(db)
+
+	t.Run("Overall spend", func(t *testing.T) {
+		metrics, err := analyticsRepo.GetPurchaseMetrics(ctx, buyerUser.ID, repositories.LastDays(0))
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(2300), metrics.TotalSpent)
+		assert.Equal(t, int64(3), metrics.TotalPurchases)
+		assert.Equal(t, int64(210), metrics.TotalQuantityBought)
+		assert.Equal(t, int64(2), metrics.UniqueItemTypes)
+		assert.Equal(t, int64(2), metrics.UniqueSellers)
+		assert.Equal(t, int64(2000), metrics.JitaValue)
+		assert.Equal(t, int64(300), metrics.UnpricedSpend)
+		assert.Equal(t, int64(0), metrics.SavingsVsJita)
+		assert.InDelta(t, 1.0, metrics.PriceVsJita, 0.0001)
+		assert.Len(t, metrics.TimeSeriesData, 1)
+		assert.Len(t, metrics.TopItems, 2)
+		assert.Len(t, metrics.TopSellers, 2)
+	})
+
+	t.Run("Top items purchased", func(t *testing.T) {
+		items, err := analyticsRepo.GetTopItemsPurchased(ctx, buyerUser.ID, repositories.LastDays(0), 10)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+
+		assert.Equal(t, int64(34), items[0].TypeID)
+		assert.Equal(t, int64(10), items[0].AveragePricePaid)
+		assert.Equal(t, int64(10), items[0].AverageJitaPrice)
+
+		assert.Equal(t, int64(35), items[1].TypeID)
+		assert.Equal(t, int64(0), items[1].AverageJitaPrice)
+		assert.Equal(t, int64(300), items[1].UnpricedSpend)
+	})
+
+	t.Run("Sellers compared to Jita", func(t *testing.T) {
+		sellers, err := analyticsRepo.GetSellerAnalytics(ctx, buyerUser.ID, repositories.LastDays(0), 10)
+		require.NoError(t, err)
+		require.Len(t, sellers, 2)
+
+		assert.Equal(t, dearSeller.ID, sellers[0].SellerUserID)
+		assert.Equal(t, "Dear Main", sellers[0].SellerName)
+		assert.Equal(t, int64(1500), sellers[0].TotalSpent)
+		assert.Equal(t, int64(2), sellers[0].TotalPurchases)
+		assert.Equal(t, int64(110), sellers[0].TotalQuantity)
+		assert.Equal(t, int64(1000), sellers[0].JitaValue)
+		assert.Equal(t, int64(300), sellers[0].UnpricedSpend)
+		assert.Equal(t, int64(-200), sellers[0].SavingsVsJita)
+		assert.InDelta(t, 1.2, sellers[0].PriceVsJita, 0.0001)
+
+		assert.Equal(t, cheapSeller.ID, sellers[1].SellerUserID)
+		assert.Equal(t, int64(200), sellers[1].SavingsVsJita)
+		assert.InDelta(t, 0.8, sellers[1].PriceVsJita, 0.0001)
+	})
+}
+
+func TestSalesAnalytics_RangesAndBuckets(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+
+	itemTypeRepo := repositories.NewItemTypeRepository(db)
+	require.NoError(t, itemTypeRepo.UpsertItemTypes(ctx, []models.EveInventoryType{
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, IconID: nil},
+	}))
+
+	userRepo := repositories.NewUserRepository(db)
+	sellerUser := &repositories.User{ID: 999040, Name: "RangeSeller"}
+	buyerUser := &repositories.User{ID: 999041, Name: "RangeBuyer"}
+	require.NoError(t, userRepo.Add(ctx, sellerUser))
+	require.NoError(t, userRepo.Add(ctx, buyerUser))
+
+	txRepo := repositories.NewPurchaseTransactions(db)
+	transactions := []*models.PurchaseTransaction{
+		{ForSaleItemID: 700, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 34, QuantityPurchased: 10, PricePerUnit: 10, TotalPrice: 100, Status: "completed"},
+		{ForSaleItemID: 701, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 34, QuantityPurchased: 20, PricePerUnit: 10, TotalPrice: 200, Status: "completed"},
+		{ForSaleItemID: 702, BuyerUserID: buyerUser.ID, SellerUserID: sellerUser.ID, TypeID: 34, QuantityPurchased: 40, PricePerUnit: 10, TotalPrice: 400, Status: "completed"},
+	}
+
+	dbTx, err := db.Begin()
+	require.NoError(t, err)
+	for _, tx := range transactions {
+		require.NoError(t, txRepo.Create(ctx, dbTx, tx))
+	}
+	require.NoError(t, dbTx.Commit())
+
+	// Two sales in the week of 2026-03-02 and one in the week before
+	purchasedAt := map[int64]string{
+		700: "2026-02-25 12:00:00",
+		701: "2026-03-02 23:30:00",
+		702: "2026-03-04 08:00:00",
+	}
+	for forSaleItemID, at := range purchasedAt {
+		_, err = db.ExecContext(ctx, `UPDATE purchase_transactions SET purchased_at = $1 WHERE for_sale_item_id = $2`, at, forSaleItemID)
+		require.NoError(t, err)
+	}
+
+	analyticsRepo := repositories.NewSalesAnalytics(db)
+
+	date := func(value string) *time.Time {
+		t, _ := time.Parse(time.RFC3339, value)
+		return &t
+	}
+
+	t.Run("Daily buckets are zero-filled", func(t *testing.T) {
+		rng := repositories.AnalyticsRange{
+			From:   date("2026-03-01T00:00:00Z"),
+			To:     date("2026-03-05T00:00:00Z"),
+			Bucket: repositories.BucketDay,
+		}
+
+		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, rng)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(600), metrics.TotalRevenue)
+		require.Len(t, metrics.TimeSeriesData, 4)
+		assert.Equal(t, "2026-03-01", metrics.TimeSeriesData[0].Date)
+		assert.Equal(t, int64(0), metrics.TimeSeriesData[0].Revenue)
+		assert.Equal(t, int64(200), metrics.TimeSeriesData[1].Revenue)
+		assert.Equal(t, int64(0), metrics.TimeSeriesData[2].Revenue)
+		assert.Equal(t, int64(400), metrics.TimeSeriesData[3].Revenue)
+		assert.Nil(t, metrics.Previous)
+	})
+
+	t.Run("Buckets follow the requested timezone", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+
+		rng := repositories.AnalyticsRange{
+			From:     date("2026-03-01T23:00:00Z"),
+			To:       date("2026-03-04T23:00:00Z"),
+			Bucket:   repositories.BucketDay,
+			Location: berlin,
+		}
+
+		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, rng)
+		require.NoError(t, err)
+
+		// 23:30 EVE time on the 2nd is already the 3rd in Berlin
+		require.Len(t, metrics.TimeSeriesData, 3)
+		assert.Equal(t, "2026-03-02", metrics.TimeSeriesData[0].Date)
+		assert.Equal(t, int64(0), metrics.TimeSeriesData[0].Revenue)
+		assert.Equal(t, int64(200), metrics.TimeSeriesData[1].Revenue)
+		assert.Equal(t, int64(400), metrics.TimeSeriesData[2].Revenue)
+	})
+
+	t.Run("Weekly buckets compared to the previous period", func(t *testing.T) {
+		rng := repositories.AnalyticsRange{
+			From:            date("2026-03-02T00:00:00Z"),
+			To:              date("2026-03-09T00:00:00Z"),
+			Bucket:          repositories.BucketWeek,
+			ComparePrevious: true,
+		}
+
+		metrics, err := analyticsRepo.GetSalesMetrics(ctx, sellerUser.ID, rng)
+		require.NoError(t, err)
+
+		assert.Equal(t, "week", metrics.Bucket)
+		require.Len(t, metrics.TimeSeriesData, 1)
+		assert.Equal(t, "2026-03-02", metrics.TimeSeriesData[0].Date)
+		assert.Equal(t, int64(600), metrics.TimeSeriesData[0].Revenue)
+		require.NotNil(t, metrics.Previous)
+		assert.Equal(t, int64(100), metrics.Previous.TotalRevenue)
+		assert.Equal(t, int64(1), metrics.Previous.TotalTransactions)
+
+		purchases, err := analyticsRepo.GetPurchaseMetrics(ctx, buyerUser.ID, rng)
+		require.NoError(t, err)
+		assert.Equal(t, int64(600), purchases.TotalSpent)
+		require.NotNil(t, purchases.Previous)
+		assert.Equal(t, int64(100), purchases.Previous.TotalSpent)
+	})
+
+	t.Run("Ranges filter every query", func(t *testing.T) {
+		rng := repositories.AnalyticsRange{
+			From: date("2026-03-03T00:00:00Z"),
+			To:   date("2026-03-05T00:00:00Z"),
+		}
+
+		items, err := analyticsRepo.GetTopItems(ctx, sellerUser.ID, rng, 10, repositories.TopItemsByRevenue)
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, int64(400), items[0].Revenue)
+
+		buyers, err := analyticsRepo.GetBuyerAnalytics(ctx, sellerUser.ID, rng, 10)
+		require.NoError(t, err)
+		require.Len(t, buyers, 1)
+		assert.Equal(t, int64(400), buyers[0].TotalSpent)
+	})
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type StockpileAlerts struct {
+	db *sql.DB
+}
+
+func NewStockpileAlerts(db *sql.DB) *StockpileAlerts {
+	return &StockpileAlerts{db: db}
+}
+
+// GetStates returns every marker of the user with an alert configured or still triggered, with the
+// quantity held against it under the same rules as GetStockpileDeficits. The deficit is valued at
+// the Jita buy price.
+func (r *StockpileAlerts) GetStates(ctx context.Context, userID int64) ([]*models.StockpileAlertState, error) {
+	query := `
+		WITH ` + stockpileHeldStacks(`ARRAY(
+			SELECT type_id FROM stockpile_markers
+			WHERE user_id = $1
+				AND (alert_below_quantity IS NOT NULL OR alert_deficit_value IS NOT NULL OR alert_triggered)
+		)`) + `
+		SELECT
+			stockpile.id,
+			stockpile.type_id,
+			COALESCE(assetTypes.type_name, ''),
+			stockpile.scope,
+			CASE stockpile.scope
+				WHEN 'solar_system' THEN COALESCE(systems.name, 'Unknown system')
+				WHEN 'region' THEN COALESCE(regions.name, 'Unknown region')
+				WHEN 'anywhere' THEN 'Anywhere'
+				ELSE COALESCE(stations.name, 'Unknown location')
+			END as location_name,
+			COALESCE(characters.name, corps.name, 'All owners') as owner_name,
+			stockpile.desired_quantity,
+			COALESCE(SUM(held.quantity), 0)::BIGINT as held_quantity,
+			GREATEST(stockpile.desired_quantity - COALESCE(SUM(held.quantity), 0), 0)::BIGINT * COALESCE(market.buy_price, 0) as deficit_value,
+			stockpile.alert_below_quantity,
+			stockpile.alert_deficit_value,
+			stockpile.alert_triggered
+		FROM stockpile_markers stockpile
+		LEFT JOIN asset_item_types assetTypes ON assetTypes.type_id = stockpile.type_id
+		LEFT JOIN characters ON (stockpile.owner_type = 'character' AND characters.id = stockpile.owner_id)
+		LEFT JOIN player_corporations corps ON (
+			stockpile.owner_type = 'corporation'
+			AND corps.id = stockpile.owner_id
+			AND corps.user_id = stockpile.user_id
+		)
+		LEFT JOIN stations ON (stockpile.scope = 'location' AND stations.station_id = stockpile.location_id)
+		LEFT JOIN solar_systems systems ON (stockpile.scope = 'solar_system' AND systems.solar_system_id = stockpile.location_id)
+		LEFT JOIN regions ON (stockpile.scope = 'region' AND regions.region_id = stockpile.location_id)
+		LEFT JOIN market_prices market ON (market.type_id = stockpile.type_id AND market.region_id = 10000002)
+		LEFT JOIN held ON (
+			held.type_id = stockpile.type_id
+			AND (stockpile.owner_type = 'all' OR (held.owner_type = stockpile.owner_type AND held.owner_id = stockpile.owner_id))
+			AND CASE stockpile.scope
+				WHEN 'solar_system' THEN held.solar_system_id = stockpile.location_id
+				WHEN 'region' THEN held.region_id = stockpile.location_id
+				WHEN 'anywhere' THEN true
+				ELSE held.division_number IS NOT DISTINCT FROM stockpile.division_number
+					AND (
+						(stockpile.container_id IS NOT NULL AND held.container_id = stockpile.container_id)
+						OR (stockpile.container_id IS NULL AND held.container_id IS NULL AND held.station_id = stockpile.location_id)
+					)
+			END
+		)
+		WHERE stockpile.user_id = $1
+			AND (stockpile.alert_below_quantity IS NOT NULL OR stockpile.alert_deficit_value IS NOT NULL OR stockpile.alert_triggered)
+		GROUP BY stockpile.id, assetTypes.type_name, systems.name, regions.name, stations.name,
+			characters.name, corps.name, market.buy_price
+		ORDER BY stockpile.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile alert states")
+	}
+	defer rows.Close()
+
+	states := []*models.StockpileAlertState{}
+	for rows.Next() {
+		state := &models.StockpileAlertState{}
+		err = rows.Scan(
+			&state.MarkerID,
+			&state.TypeID,
+			&state.TypeName,
+			&state.Scope,
+			&state.LocationName,
+			&state.OwnerName,
+			&state.DesiredQuantity,
+			&state.HeldQuantity,
+			&state.DeficitValue,
+			&state.AlertBelowQuantity,
+			&state.AlertDeficitValue,
+			&state.Triggered,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile alert state")
+		}
+		states = append(states, state)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating stockpile alert states")
+	}
+
+	return states, nil
+}
+
+// SetTriggered records whether the marker's alert has fired, so it is only sent again after the
+// stockpile recovers
+func (r *StockpileAlerts) SetTriggered(ctx context.Context, markerID int64, triggered bool) error {
+	query := `
+		UPDATE stockpile_markers
+		SET alert_triggered = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, markerID, triggered)
+	if err != nil {
+		return errors.Wrap(err, "failed to set stockpile alert state")
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StockpileAlerts_StatesAndTriggers(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO character_assets
+		(character_id, user_id, item_id, update_key, is_blueprint_copy, is_singleton,
+		 location_id, location_type, quantity, type_id, location_flag)
+		VALUES
+			(12345, 1, 2001, 'key1', false, false, 60003760, 'station', 300, 34, 'Hangar')
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO market_prices (type_id, region_id, buy_price, sell_price, daily_volume, updated_at)
+		VALUES (34, 10000002, 5.0, 6.0, 1000, NOW())
+	`)
+	require.NoError(t, err)
+
+	below := int64(500)
+	markersRepo := repositories.NewStockpileMarkers(db)
+	markers := []*models.StockpileMarker{
+		{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000, AlertBelowQuantity: &below},
+		// No alert configured
+		{UserID: 1, TypeID: 35, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000},
+	}
+	for _, marker := range markers {
+		require.NoError(t, markersRepo.Upsert(ctx, marker))
+	}
+
+	repo := repositories.NewStockpileAlerts(db)
+	states, err := repo.GetStates(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+
+	state := states[0]
+	assert.Equal(t, int64(34), state.TypeID)
+	assert.Equal(t, "Test Character", state.OwnerName)
+	assert.Equal(t, int64(300), state.HeldQuantity)
+	assert.Equal(t, 3500.0, state.DeficitValue)
+	assert.Equal(t, below, *state.AlertBelowQuantity)
+	assert.False(t, state.Triggered)
+
+	require.NoError(t, repo.SetTriggered(ctx, state.MarkerID, true))
+
+	states, err = repo.GetStates(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, states[0].Triggered)
+
+	// Changing the threshold re-arms the alert
+	changed := int64(400)
+	markers[0].AlertBelowQuantity = &changed
+	require.NoError(t, markersRepo.Upsert(ctx, markers[0]))
+
+	states, err = repo.GetStates(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, states[0].Triggered)
+}
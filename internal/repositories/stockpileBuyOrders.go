@@ -0,0 +1,166 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type StockpileBuyOrders struct {
+	db *sql.DB
+}
+
+func NewStockpileBuyOrders(db *sql.DB) *StockpileBuyOrders {
+	return &StockpileBuyOrders{db: db}
+}
+
+// GetTargets returns every marker of the user opted in to an automatic buy order, with the
+// quantity held against it under the same rules as GetStockpileDeficits, the Jita buy price
+// and its linked order
+func (r *StockpileBuyOrders) GetTargets(ctx context.Context, userID int64) ([]*models.StockpileBuyOrderTarget, error) {
+	query := `
+		WITH ` + stockpileHeldStacks("ARRAY(SELECT type_id FROM stockpile_markers WHERE user_id = $1 AND auto_buy_order)") + `
+		SELECT
+			stockpile.id,
+			stockpile.type_id,
+			stockpile.desired_quantity,
+			COALESCE(SUM(held.quantity), 0)::BIGINT as held_quantity,
+			stockpile.buy_order_markup_percent,
+			COALESCE(jita.buy_price, 0),
+			bo.id,
+			bo.quantity_desired,
+			bo.max_price_per_unit,
+			bo.pricing_basis,
+			bo.pricing_percent,
+			bo.pricing_floor,
+			bo.pricing_ceiling,
+			bo.notes,
+			bo.is_active
+		FROM stockpile_markers stockpile
+		LEFT JOIN held ON (
+			held.type_id = stockpile.type_id
+			AND (stockpile.owner_type = 'all' OR (held.owner_type = stockpile.owner_type AND held.owner_id = stockpile.owner_id))
+			AND CASE stockpile.scope
+				WHEN 'solar_system' THEN held.solar_system_id = stockpile.location_id
+				WHEN 'region' THEN held.region_id = stockpile.location_id
+				WHEN 'anywhere' THEN true
+				ELSE held.division_number IS NOT DISTINCT FROM stockpile.division_number
+					AND (
+						(stockpile.container_id IS NOT NULL AND held.container_id = stockpile.container_id)
+						OR (stockpile.container_id IS NULL AND held.container_id IS NULL AND held.station_id = stockpile.location_id)
+					)
+			END
+		)
+		LEFT JOIN market_prices jita ON (jita.type_id = stockpile.type_id AND jita.region_id = 10000002)
+		LEFT JOIN buy_orders bo ON bo.stockpile_marker_id = stockpile.id
+		WHERE stockpile.user_id = $1
+			AND stockpile.auto_buy_order
+		GROUP BY stockpile.id, jita.buy_price, bo.id
+		ORDER BY stockpile.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile buy order targets")
+	}
+	defer rows.Close()
+
+	targets := []*models.StockpileBuyOrderTarget{}
+	for rows.Next() {
+		target := &models.StockpileBuyOrderTarget{}
+		var (
+			orderID       sql.NullInt64
+			orderQuantity sql.NullInt64
+			orderPrice    sql.NullInt64
+			orderNotes    sql.NullString
+			orderActive   sql.NullBool
+			rule          pricingRuleColumns
+		)
+		err = rows.Scan(
+			&target.MarkerID,
+			&target.TypeID,
+			&target.DesiredQuantity,
+			&target.HeldQuantity,
+			&target.MarkupPercent,
+			&target.JitaBuyPrice,
+			&orderID,
+			&orderQuantity,
+			&orderPrice,
+			&rule.basis,
+			&rule.percent,
+			&rule.floor,
+			&rule.ceiling,
+			&orderNotes,
+			&orderActive,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile buy order target")
+		}
+
+		if orderID.Valid {
+			markerID := target.MarkerID
+			target.BuyOrder = &models.BuyOrder{
+				ID:                orderID.Int64,
+				BuyerUserID:       userID,
+				TypeID:            target.TypeID,
+				QuantityDesired:   orderQuantity.Int64,
+				MaxPricePerUnit:   orderPrice.Int64,
+				PricingRule:       rule.rule(),
+				IsActive:          orderActive.Bool,
+				StockpileMarkerID: &markerID,
+			}
+			if orderNotes.Valid {
+				target.BuyOrder.Notes = &orderNotes.String
+			}
+		}
+
+		targets = append(targets, target)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating stockpile buy order targets")
+	}
+
+	return targets, nil
+}
+
+// CloseOrphaned deactivates the user's automatic buy orders whose marker was deleted or opted
+// out and returns their IDs
+func (r *StockpileBuyOrders) CloseOrphaned(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+		UPDATE buy_orders bo
+		SET is_active = false, updated_at = NOW()
+		WHERE bo.buyer_user_id = $1
+			AND bo.auto_managed
+			AND bo.is_active
+			AND NOT EXISTS (
+				SELECT 1 FROM stockpile_markers stockpile
+				WHERE stockpile.id = bo.stockpile_marker_id
+					AND stockpile.auto_buy_order
+			)
+		RETURNING bo.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to close orphaned stockpile buy orders")
+	}
+	defer rows.Close()
+
+	closed := []int64{}
+	for rows.Next() {
+		var id int64
+		if err = rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan closed stockpile buy order")
+		}
+		closed = append(closed, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating closed stockpile buy orders")
+	}
+
+	return closed, nil
+}
@@ -0,0 +1,103 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StockpileBuyOrders_TargetsAndOrphans(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO character_assets
+		(character_id, user_id, item_id, update_key, is_blueprint_copy, is_singleton,
+		 location_id, location_type, quantity, type_id, location_flag)
+		VALUES
+			(12345, 1, 2001, 'key1', false, false, 60003760, 'station', 300, 34, 'Hangar'),
+			(12345, 1, 2002, 'key2', false, false, 60003761, 'station', 200, 34, 'Hangar')
+	`)
+	require.NoError(t, err)
+
+	markersRepo := repositories.NewStockpileMarkers(db)
+	markers := []*models.StockpileMarker{
+		{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000, AutoBuyOrder: true, BuyOrderMarkupPercent: 5},
+		{UserID: 1, TypeID: 34, OwnerType: models.StockpileOwnerAll, Scope: models.StockpileScopeSolarSystem, LocationID: 30000142, DesiredQuantity: 400, AutoBuyOrder: true},
+		// Not opted in
+		{UserID: 1, TypeID: 35, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000},
+	}
+	for _, marker := range markers {
+		require.NoError(t, markersRepo.Upsert(ctx, marker))
+	}
+
+	buyPrice := 4.5
+	err = repositories.NewMarketPrices(db).UpsertPrices(ctx, []models.MarketPrice{
+		{TypeID: 34, RegionID: 10000002, BuyPrice: &buyPrice},
+	})
+	require.NoError(t, err)
+
+	repo := repositories.NewStockpileBuyOrders(db)
+	targets, err := repo.GetTargets(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+	assert.Equal(t, 4.5, targets[0].JitaBuyPrice)
+
+	location := targets[0]
+	assert.Equal(t, int64(1000), location.DesiredQuantity)
+	assert.Equal(t, int64(300), location.HeldQuantity)
+	assert.Equal(t, 5.0, location.MarkupPercent)
+	assert.Nil(t, location.BuyOrder)
+
+	system := targets[1]
+	assert.Equal(t, int64(500), system.HeldQuantity)
+
+	// Linking an order shows up on the next read
+	buyOrdersRepo := repositories.NewBuyOrders(db)
+	order := &models.BuyOrder{
+		BuyerUserID:       1,
+		TypeID:            34,
+		QuantityDesired:   700,
+		PricingRule:       &models.PricingRule{Basis: "buy", Percent: 105},
+		IsActive:          true,
+		StockpileMarkerID: &location.MarkerID,
+	}
+	require.NoError(t, buyOrdersRepo.Create(ctx, order))
+
+	targets, err = repo.GetTargets(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, targets[0].BuyOrder)
+	assert.Equal(t, order.ID, targets[0].BuyOrder.ID)
+	assert.Equal(t, int64(700), targets[0].BuyOrder.QuantityDesired)
+	assert.Equal(t, 105.0, targets[0].BuyOrder.PricingRule.Percent)
+
+	// Opting the marker out orphans its order
+	markers[0].AutoBuyOrder = false
+	require.NoError(t, markersRepo.Upsert(ctx, markers[0]))
+
+	closed, err := repo.CloseOrphaned(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{order.ID}, closed)
+
+	saved, err := buyOrdersRepo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.IsActive)
+	assert.Equal(t, location.MarkerID, *saved.StockpileMarkerID)
+}
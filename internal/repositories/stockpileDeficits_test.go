@@ -446,3 +446,225 @@ func Test_StockpileDeficits_ContainerRecursion(t *testing.T) {
 	assert.True(t, foundProteins, "Should find Proteins deficit")
 	assert.True(t, foundWater, "Should find Water deficit")
 }
+
+func Test_StockpileDeficits_AggregateScopes(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO asset_item_types (type_id, type_name, volume)
+		VALUES
+			(34, 'Tritanium', 0.01),
+			(36, 'Mexallon', 0.01),
+			(3293, 'Medium Standard Container', 33.0)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `INSERT INTO regions (region_id, name) VALUES (10000002, 'The Forge')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO constellations (constellation_id, name, region_id)
+		VALUES (20000020, 'Kimotoro', 10000002)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO solar_systems (solar_system_id, name, constellation_id, security)
+		VALUES (30000142, 'Jita', 20000020, 1.0), (30000144, 'Perimeter', 20000020, 1.0)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO stations (station_id, name, solar_system_id, corporation_id, is_npc_station)
+		VALUES
+			(60003760, 'Jita IV - Moon 4 - Caldari Navy Assembly Plant', 30000142, 1000035, true),
+			(60003761, 'Jita IV - Moon 5', 30000142, 1000035, true),
+			(60000001, 'Perimeter Station', 30000144, 1000035, true)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO market_prices (type_id, region_id, buy_price, sell_price, daily_volume, updated_at)
+		VALUES (34, 10000002, 5.0, 5.5, 1000000, NOW()), (36, 10000002, 100.0, 110.0, 1000, NOW())
+	`)
+	require.NoError(t, err)
+
+	// Jita holds 3000 in a hangar, 4000 in a container and 1000 at a second station; Perimeter holds 2000
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO character_assets
+		(character_id, user_id, item_id, update_key, is_blueprint_copy, is_singleton,
+		 location_id, location_type, quantity, type_id, location_flag)
+		VALUES
+			(12345, 1, 2001, 'key1', false, false, 60003760, 'station', 3000, 34, 'Hangar'),
+			(12345, 1, 2002, 'key2', false, true, 60003760, 'station', 1, 3293, 'Hangar'),
+			(12345, 1, 2003, 'key3', false, false, 2002, 'item', 4000, 34, 'Unlocked'),
+			(12345, 1, 2004, 'key4', false, false, 60003761, 'station', 1000, 34, 'Hangar'),
+			(12345, 1, 2005, 'key5', false, false, 60000001, 'station', 2000, 34, 'Hangar')
+	`)
+	require.NoError(t, err)
+
+	stockpileMarkersRepo := repositories.NewStockpileMarkers(db)
+	markers := []*models.StockpileMarker{
+		// Jita has 8000 of 10000 across three stacks
+		{UserID: 1, TypeID: 34, Scope: models.StockpileScopeSolarSystem, OwnerType: models.StockpileOwnerAll, LocationID: 30000142, DesiredQuantity: 10000},
+		// The Forge has exactly 10000, so no deficit
+		{UserID: 1, TypeID: 34, Scope: models.StockpileScopeRegion, OwnerType: models.StockpileOwnerAll, LocationID: 10000002, DesiredQuantity: 10000},
+		// No Mexallon anywhere still counts as a deficit
+		{UserID: 1, TypeID: 36, Scope: models.StockpileScopeAnywhere, OwnerType: "character", OwnerID: 12345, DesiredQuantity: 500},
+		// The hangar stack alone is below a location marker for the same type
+		{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 3500},
+	}
+	for _, marker := range markers {
+		require.NoError(t, stockpileMarkersRepo.Upsert(ctx, marker))
+	}
+
+	assetsRepo := repositories.NewAssets(db)
+	result, err := assetsRepo.GetStockpileDeficits(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 3)
+
+	byScope := map[string]*repositories.StockpileItem{}
+	for _, item := range result.Items {
+		byScope[item.Scope] = item
+	}
+
+	jita := byScope[models.StockpileScopeSolarSystem]
+	require.NotNil(t, jita)
+	assert.Equal(t, "Jita", jita.StructureName)
+	assert.Equal(t, "The Forge", jita.Region)
+	assert.Equal(t, "All owners", jita.OwnerName)
+	assert.Equal(t, int64(8000), jita.Quantity)
+	assert.Equal(t, int64(-2000), jita.StockpileDelta)
+	assert.InDelta(t, 10000.0, jita.DeficitValue, 0.01)
+
+	anywhere := byScope[models.StockpileScopeAnywhere]
+	require.NotNil(t, anywhere)
+	assert.Equal(t, "Anywhere", anywhere.StructureName)
+	assert.Equal(t, "Test Character", anywhere.OwnerName)
+	assert.Equal(t, int64(0), anywhere.Quantity)
+	assert.Equal(t, int64(-500), anywhere.StockpileDelta)
+
+	location := byScope[models.StockpileScopeLocation]
+	require.NotNil(t, location)
+	assert.Equal(t, int64(3000), location.Quantity)
+	assert.Equal(t, int64(-500), location.StockpileDelta)
+
+	summary, err := assetsRepo.GetUserAssetsSummary(ctx, 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 2000*5.0+500*100.0+500*5.0, summary.TotalDeficit, 0.01)
+
+	// Markers of each scope are kept apart
+	saved, err := stockpileMarkersRepo.GetByUser(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, saved, 4)
+}
+
+func Test_StockpileDeficits_PackagedVolume(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	// A Rifter is 27289 m3 assembled and 2500 m3 packaged; Tritanium has no separate packaged volume
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO asset_item_types (type_id, type_name, volume, packaged_volume)
+		VALUES (587, 'Rifter', 27289, 2500), (34, 'Tritanium', 0.01, NULL)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `INSERT INTO regions (region_id, name) VALUES (10000002, 'The Forge')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO constellations (constellation_id, name, region_id)
+		VALUES (20000020, 'Kimotoro', 10000002)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO solar_systems (solar_system_id, name, constellation_id, security)
+		VALUES (30000142, 'Jita', 20000020, 1.0)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO stations (station_id, name, solar_system_id, corporation_id, is_npc_station)
+		VALUES
+			(60003760, 'Jita IV - Moon 4 - Caldari Navy Assembly Plant', 30000142, 1000035, true),
+			(60003761, 'Jita IV - Moon 5', 30000142, 1000035, true)
+	`)
+	require.NoError(t, err)
+
+	// Three packaged Rifters and some Tritanium at one station, one assembled Rifter at the other
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO character_assets
+		(character_id, user_id, item_id, update_key, is_blueprint_copy, is_singleton,
+		 location_id, location_type, quantity, type_id, location_flag)
+		VALUES
+			(12345, 1, 3001, 'key1', false, false, 60003760, 'station', 3, 587, 'Hangar'),
+			(12345, 1, 3002, 'key2', false, true, 60003761, 'station', 1, 587, 'Hangar'),
+			(12345, 1, 3003, 'key3', false, false, 60003760, 'station', 1000, 34, 'Hangar')
+	`)
+	require.NoError(t, err)
+
+	stockpileMarkersRepo := repositories.NewStockpileMarkers(db)
+	markers := []*models.StockpileMarker{
+		{UserID: 1, TypeID: 587, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 10},
+		{UserID: 1, TypeID: 587, Scope: models.StockpileScopeSolarSystem, OwnerType: models.StockpileOwnerAll, LocationID: 30000142, DesiredQuantity: 10},
+		{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 5000},
+	}
+	for _, marker := range markers {
+		require.NoError(t, stockpileMarkersRepo.Upsert(ctx, marker))
+	}
+
+	assetsRepo := repositories.NewAssets(db)
+	result, err := assetsRepo.GetStockpileDeficits(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, result.Items, 3)
+
+	for _, item := range result.Items {
+		switch {
+		case item.TypeID == 34:
+			assert.InDelta(t, 10.0, item.Volume, 0.0001)
+		case item.Scope == models.StockpileScopeLocation:
+			assert.InDelta(t, 7500.0, item.Volume, 0.0001)
+		default:
+			// The assembled Rifter keeps its assembled volume
+			assert.InDelta(t, 7500.0+27289.0, item.Volume, 0.0001)
+		}
+	}
+
+	response, err := assetsRepo.GetUserAssets(ctx, 1)
+	require.NoError(t, err)
+	volumes := map[int64]float64{}
+	for _, structure := range response.Structures {
+		for _, asset := range structure.HangarAssets {
+			if asset.TypeID == 587 {
+				volumes[structure.ID] = asset.Volume
+			}
+		}
+	}
+	assert.InDelta(t, 7500.0, volumes[60003760], 0.0001)
+	assert.InDelta(t, 27289.0, volumes[60003761], 0.0001)
+}
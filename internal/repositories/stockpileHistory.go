@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type StockpileHistory struct {
+	db *sql.DB
+}
+
+func NewStockpileHistory(db *sql.DB) *StockpileHistory {
+	return &StockpileHistory{db: db}
+}
+
+// Record stores one sample per marker of the user, holding the quantity held against it under the
+// same rules as GetStockpileDeficits. It returns the number of samples written.
+func (r *StockpileHistory) Record(ctx context.Context, userID int64, recordedAt time.Time) (int64, error) {
+	query := `
+		WITH ` + stockpileHeldStacks(`ARRAY(SELECT type_id FROM stockpile_markers WHERE user_id = $1)`) + `
+		INSERT INTO stockpile_history (stockpile_marker_id, user_id, recorded_at, quantity)
+		SELECT
+			stockpile.id,
+			stockpile.user_id,
+			$2,
+			COALESCE(SUM(held.quantity), 0)::BIGINT
+		FROM stockpile_markers stockpile
+		LEFT JOIN held ON (
+			held.type_id = stockpile.type_id
+			AND (stockpile.owner_type = 'all' OR (held.owner_type = stockpile.owner_type AND held.owner_id = stockpile.owner_id))
+			AND CASE stockpile.scope
+				WHEN 'solar_system' THEN held.solar_system_id = stockpile.location_id
+				WHEN 'region' THEN held.region_id = stockpile.location_id
+				WHEN 'anywhere' THEN true
+				ELSE held.division_number IS NOT DISTINCT FROM stockpile.division_number
+					AND (
+						(stockpile.container_id IS NOT NULL AND held.container_id = stockpile.container_id)
+						OR (stockpile.container_id IS NULL AND held.container_id IS NULL AND held.station_id = stockpile.location_id)
+					)
+			END
+		)
+		WHERE stockpile.user_id = $1
+		GROUP BY stockpile.id, stockpile.user_id
+		ON CONFLICT (stockpile_marker_id, recorded_at) DO UPDATE SET quantity = EXCLUDED.quantity
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, recordedAt)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to record stockpile history")
+	}
+
+	recorded, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get recorded stockpile history count")
+	}
+
+	return recorded, nil
+}
+
+// DeleteBefore drops the user's samples recorded before the cutoff
+func (r *StockpileHistory) DeleteBefore(ctx context.Context, userID int64, before time.Time) error {
+	query := `
+		DELETE FROM stockpile_history
+		WHERE user_id = $1 AND recorded_at < $2
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, before)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete old stockpile history")
+	}
+
+	return nil
+}
+
+// GetSince returns the user's samples recorded at or after since, grouped by marker and oldest first
+func (r *StockpileHistory) GetSince(ctx context.Context, userID int64, since time.Time) (map[int64][]*models.StockpileHistorySample, error) {
+	query := `
+		SELECT stockpile_marker_id, recorded_at, quantity
+		FROM stockpile_history
+		WHERE user_id = $1 AND recorded_at >= $2
+		ORDER BY stockpile_marker_id, recorded_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile history")
+	}
+	defer rows.Close()
+
+	samples := map[int64][]*models.StockpileHistorySample{}
+	for rows.Next() {
+		sample := &models.StockpileHistorySample{}
+		err = rows.Scan(&sample.MarkerID, &sample.RecordedAt, &sample.Quantity)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile history sample")
+		}
+		samples[sample.MarkerID] = append(samples[sample.MarkerID], sample)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating stockpile history")
+	}
+
+	return samples, nil
+}
+
+// GetByMarker returns one of the user's markers' samples recorded at or after since, oldest first.
+// A marker of another user has no samples.
+func (r *StockpileHistory) GetByMarker(ctx context.Context, markerID, userID int64, since time.Time) ([]*models.StockpileHistorySample, error) {
+	query := `
+		SELECT stockpile_marker_id, recorded_at, quantity
+		FROM stockpile_history
+		WHERE stockpile_marker_id = $1 AND user_id = $2 AND recorded_at >= $3
+		ORDER BY recorded_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, markerID, userID, since)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile marker history")
+	}
+	defer rows.Close()
+
+	samples := []*models.StockpileHistorySample{}
+	for rows.Next() {
+		sample := &models.StockpileHistorySample{}
+		err = rows.Scan(&sample.MarkerID, &sample.RecordedAt, &sample.Quantity)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile history sample")
+		}
+		samples = append(samples, sample)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating stockpile marker history")
+	}
+
+	return samples, nil
+}
@@ -0,0 +1,101 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StockpileHistory_RecordAndRead(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User'), (2, 'Other User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO character_assets
+		(character_id, user_id, item_id, update_key, is_blueprint_copy, is_singleton,
+		 location_id, location_type, quantity, type_id, location_flag)
+		VALUES
+			(12345, 1, 2001, 'key1', false, false, 60003760, 'station', 300, 34, 'Hangar'),
+			(12345, 1, 2002, 'key1', false, false, 60003761, 'station', 200, 34, 'Hangar')
+	`)
+	require.NoError(t, err)
+
+	markersRepo := repositories.NewStockpileMarkers(db)
+	station := &models.StockpileMarker{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000}
+	system := &models.StockpileMarker{UserID: 1, TypeID: 34, Scope: models.StockpileScopeSolarSystem, OwnerType: "all", LocationID: 30000142, DesiredQuantity: 1000}
+	empty := &models.StockpileMarker{UserID: 1, TypeID: 35, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000}
+	for _, marker := range []*models.StockpileMarker{station, system, empty} {
+		require.NoError(t, markersRepo.Upsert(ctx, marker))
+	}
+
+	repo := repositories.NewStockpileHistory(db)
+
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	recorded, err := repo.Record(ctx, 1, start)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), recorded)
+
+	_, err = db.ExecContext(ctx, `UPDATE character_assets SET quantity = 100 WHERE item_id = 2001`)
+	require.NoError(t, err)
+
+	_, err = repo.Record(ctx, 1, start.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	samples, err := repo.GetSince(ctx, 1, start)
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+
+	assert.Equal(t, []int64{300, 100}, sampleQuantities(samples[station.ID]))
+	assert.Equal(t, []int64{500, 300}, sampleQuantities(samples[system.ID]))
+	assert.Equal(t, []int64{0, 0}, sampleQuantities(samples[empty.ID]))
+	assert.True(t, samples[station.ID][0].RecordedAt.Equal(start))
+
+	markerSamples, err := repo.GetByMarker(ctx, station.ID, 1, start.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{100}, sampleQuantities(markerSamples))
+
+	markerSamples, err = repo.GetByMarker(ctx, station.ID, 2, start)
+	require.NoError(t, err)
+	assert.Empty(t, markerSamples)
+
+	require.NoError(t, repo.DeleteBefore(ctx, 1, start.Add(time.Hour)))
+
+	samples, err = repo.GetSince(ctx, 1, start)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{100}, sampleQuantities(samples[station.ID]))
+
+	// Samples go with their marker
+	deleted, err := markersRepo.DeleteByID(ctx, station.ID, 1)
+	require.NoError(t, err)
+	require.True(t, deleted)
+
+	samples, err = repo.GetSince(ctx, 1, start)
+	require.NoError(t, err)
+	assert.NotContains(t, samples, station.ID)
+}
+
+func sampleQuantities(samples []*models.StockpileHistorySample) []int64 {
+	quantities := []int64{}
+	for _, sample := range samples {
+		quantities = append(quantities, sample.Quantity)
+	}
+	return quantities
+}
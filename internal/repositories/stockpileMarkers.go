@@ -3,11 +3,17 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"strings"
 
 	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
+// ErrStockpileMarkerExists is returned when a marker is moved onto the item and target of
+// another of the user's markers
+var ErrStockpileMarkerExists = errors.New("a stockpile marker already exists for that item and target")
+
 type StockpileMarkers struct {
 	db *sql.DB
 }
@@ -18,11 +24,13 @@ func NewStockpileMarkers(db *sql.DB) *StockpileMarkers {
 
 func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*models.StockpileMarker, error) {
 	query := `
-		SELECT user_id, type_id, owner_type, owner_id, location_id,
-		       container_id, division_number, desired_quantity, notes
+		SELECT id, user_id, type_id, owner_type, owner_id, location_id,
+		       container_id, division_number, desired_quantity, notes,
+		       scope, template_application_id, auto_buy_order, buy_order_markup_percent,
+		       alert_below_quantity, alert_deficit_value
 		FROM stockpile_markers
 		WHERE user_id = $1
-		ORDER BY type_id, location_id
+		ORDER BY type_id, scope, location_id
 	`
 
 	rows, err := r.db.QueryContext(ctx, query, userID)
@@ -35,6 +43,7 @@ func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*mode
 	for rows.Next() {
 		var marker models.StockpileMarker
 		err = rows.Scan(
+			&marker.ID,
 			&marker.UserID,
 			&marker.TypeID,
 			&marker.OwnerType,
@@ -44,6 +53,12 @@ func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*mode
 			&marker.DivisionNumber,
 			&marker.DesiredQuantity,
 			&marker.Notes,
+			&marker.Scope,
+			&marker.TemplateApplicationID,
+			&marker.AutoBuyOrder,
+			&marker.BuyOrderMarkupPercent,
+			&marker.AlertBelowQuantity,
+			&marker.AlertDeficitValue,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to scan stockpile marker")
@@ -57,16 +72,26 @@ func (r *StockpileMarkers) GetByUser(ctx context.Context, userID int64) ([]*mode
 func (r *StockpileMarkers) Upsert(ctx context.Context, marker *models.StockpileMarker) error {
 	query := `
 		INSERT INTO stockpile_markers
-		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number, desired_quantity, notes, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
-		ON CONFLICT (user_id, type_id, owner_type, owner_id, location_id, COALESCE(container_id, 0::BIGINT), COALESCE(division_number, 0))
+		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number, desired_quantity, notes, scope,
+		 auto_buy_order, buy_order_markup_percent, alert_below_quantity, alert_deficit_value, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NOW())
+		ON CONFLICT (user_id, type_id, scope, owner_type, owner_id, location_id, COALESCE(container_id, 0::BIGINT), COALESCE(division_number, 0))
 		DO UPDATE SET
 			desired_quantity = EXCLUDED.desired_quantity,
 			notes = EXCLUDED.notes,
+			auto_buy_order = EXCLUDED.auto_buy_order,
+			buy_order_markup_percent = EXCLUDED.buy_order_markup_percent,
+			alert_below_quantity = EXCLUDED.alert_below_quantity,
+			alert_deficit_value = EXCLUDED.alert_deficit_value,
+			-- Changed thresholds are checked afresh on the next sync
+			alert_triggered = stockpile_markers.alert_triggered
+				AND stockpile_markers.alert_below_quantity IS NOT DISTINCT FROM EXCLUDED.alert_below_quantity
+				AND stockpile_markers.alert_deficit_value IS NOT DISTINCT FROM EXCLUDED.alert_deficit_value,
 			updated_at = NOW()
+		RETURNING id
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	err := r.db.QueryRowContext(ctx, query,
 		marker.UserID,
 		marker.TypeID,
 		marker.OwnerType,
@@ -76,7 +101,12 @@ func (r *StockpileMarkers) Upsert(ctx context.Context, marker *models.StockpileM
 		marker.DivisionNumber,
 		marker.DesiredQuantity,
 		marker.Notes,
-	)
+		stockpileScope(marker.Scope),
+		marker.AutoBuyOrder,
+		marker.BuyOrderMarkupPercent,
+		marker.AlertBelowQuantity,
+		marker.AlertDeficitValue,
+	).Scan(&marker.ID)
 	if err != nil {
 		return errors.Wrap(err, "failed to upsert stockpile marker")
 	}
@@ -94,6 +124,7 @@ func (r *StockpileMarkers) Delete(ctx context.Context, marker *models.StockpileM
 		  AND location_id = $5
 		  AND COALESCE(container_id, 0::BIGINT) = COALESCE($6, 0::BIGINT)
 		  AND COALESCE(division_number, 0) = COALESCE($7, 0)
+		  AND scope = $8
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -104,6 +135,7 @@ func (r *StockpileMarkers) Delete(ctx context.Context, marker *models.StockpileM
 		marker.LocationID,
 		marker.ContainerID,
 		marker.DivisionNumber,
+		stockpileScope(marker.Scope),
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to delete stockpile marker")
@@ -111,3 +143,197 @@ func (r *StockpileMarkers) Delete(ctx context.Context, marker *models.StockpileM
 
 	return nil
 }
+
+// GetByID returns one of the user's markers, or nil if the user has no marker with that ID
+func (r *StockpileMarkers) GetByID(ctx context.Context, id int64, userID int64) (*models.StockpileMarker, error) {
+	query := `
+		SELECT id, user_id, type_id, owner_type, owner_id, location_id,
+		       container_id, division_number, desired_quantity, notes,
+		       scope, template_application_id, auto_buy_order, buy_order_markup_percent,
+		       alert_below_quantity, alert_deficit_value
+		FROM stockpile_markers
+		WHERE id = $1 AND user_id = $2
+	`
+
+	var marker models.StockpileMarker
+	err := r.db.QueryRowContext(ctx, query, id, userID).Scan(
+		&marker.ID,
+		&marker.UserID,
+		&marker.TypeID,
+		&marker.OwnerType,
+		&marker.OwnerID,
+		&marker.LocationID,
+		&marker.ContainerID,
+		&marker.DivisionNumber,
+		&marker.DesiredQuantity,
+		&marker.Notes,
+		&marker.Scope,
+		&marker.TemplateApplicationID,
+		&marker.AutoBuyOrder,
+		&marker.BuyOrderMarkupPercent,
+		&marker.AlertBelowQuantity,
+		&marker.AlertDeficitValue,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get stockpile marker")
+	}
+
+	return &marker, nil
+}
+
+// Update replaces the item, target and settings of the marker with marker.ID. Unlike Upsert it
+// can move a marker to another target. It reports false if the user has no marker with that ID
+// and returns ErrStockpileMarkerExists if another marker already covers the new target.
+func (r *StockpileMarkers) Update(ctx context.Context, marker *models.StockpileMarker) (bool, error) {
+	query := `
+		UPDATE stockpile_markers
+		SET
+			type_id = $3,
+			owner_type = $4,
+			owner_id = $5,
+			location_id = $6,
+			container_id = $7,
+			division_number = $8,
+			desired_quantity = $9,
+			notes = $10,
+			scope = $11,
+			auto_buy_order = $12,
+			buy_order_markup_percent = $13,
+			alert_below_quantity = $14,
+			alert_deficit_value = $15,
+			-- A moved marker or changed thresholds are checked afresh on the next sync
+			alert_triggered = alert_triggered
+				AND (type_id, scope, owner_type, owner_id, location_id, container_id, division_number)
+					IS NOT DISTINCT FROM ($3, $11, $4, $5, $6, $7, $8)
+				AND alert_below_quantity IS NOT DISTINCT FROM $14
+				AND alert_deficit_value IS NOT DISTINCT FROM $15,
+			updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING template_application_id
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		marker.ID,
+		marker.UserID,
+		marker.TypeID,
+		marker.OwnerType,
+		marker.OwnerID,
+		marker.LocationID,
+		marker.ContainerID,
+		marker.DivisionNumber,
+		marker.DesiredQuantity,
+		marker.Notes,
+		stockpileScope(marker.Scope),
+		marker.AutoBuyOrder,
+		marker.BuyOrderMarkupPercent,
+		marker.AlertBelowQuantity,
+		marker.AlertDeficitValue,
+	).Scan(&marker.TemplateApplicationID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if pqErr, ok := errors.Cause(err).(*pq.Error); ok && pqErr.Code == "23505" {
+		return false, ErrStockpileMarkerExists
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to update stockpile marker")
+	}
+
+	return true, nil
+}
+
+// DeleteByID removes one of the user's markers. It reports false if the user has no marker with
+// that ID.
+func (r *StockpileMarkers) DeleteByID(ctx context.Context, id int64, userID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM stockpile_markers WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to delete stockpile marker")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// CheckTarget checks that the marker's owner is one of the user's characters or corporations,
+// that its location is a station or structure where the owner has assets, or a known solar
+// system or region to match its scope, and that its container is one of the owner's items at
+// that location. It returns why the target is invalid, or "" if it is valid.
+func (r *StockpileMarkers) CheckTarget(ctx context.Context, marker *models.StockpileMarker) (string, error) {
+	query := `
+		SELECT
+			CASE $2::TEXT
+				WHEN 'character' THEN EXISTS (SELECT 1 FROM characters WHERE id = $3 AND user_id = $1)
+				WHEN 'corporation' THEN EXISTS (SELECT 1 FROM player_corporations WHERE id = $3 AND user_id = $1)
+				ELSE true
+			END AS owner_valid,
+			CASE $4::TEXT
+				WHEN 'location' THEN
+					($2 = 'character' AND EXISTS (
+						SELECT 1 FROM character_assets WHERE user_id = $1 AND character_id = $3 AND location_id = $5
+					))
+					OR ($2 = 'corporation' AND EXISTS (
+						SELECT 1 FROM corporation_asset_locations WHERE user_id = $1 AND corporation_id = $3 AND station_id = $5
+					))
+				WHEN 'solar_system' THEN EXISTS (SELECT 1 FROM solar_systems WHERE solar_system_id = $5)
+				WHEN 'region' THEN EXISTS (SELECT 1 FROM regions WHERE region_id = $5)
+				ELSE true
+			END AS location_valid,
+			$6::BIGINT IS NULL
+				OR ($2 = 'character' AND EXISTS (
+					SELECT 1 FROM character_assets WHERE user_id = $1 AND character_id = $3 AND item_id = $6
+				))
+				OR ($2 = 'corporation' AND EXISTS (
+					SELECT 1 FROM corporation_assets WHERE user_id = $1 AND corporation_id = $3 AND item_id = $6
+				)) AS container_owned,
+			$6::BIGINT IS NULL
+				OR ($2 = 'character' AND EXISTS (
+					SELECT 1 FROM character_assets WHERE user_id = $1 AND character_id = $3 AND item_id = $6 AND location_id = $5
+				))
+				OR ($2 = 'corporation' AND EXISTS (
+					SELECT 1 FROM corporation_asset_locations WHERE user_id = $1 AND corporation_id = $3 AND item_id = $6 AND station_id = $5
+				)) AS container_at_location
+	`
+
+	var ownerValid, locationValid, containerOwned, containerAtLocation bool
+	err := r.db.QueryRowContext(ctx, query,
+		marker.UserID,
+		marker.OwnerType,
+		marker.OwnerID,
+		stockpileScope(marker.Scope),
+		marker.LocationID,
+		marker.ContainerID,
+	).Scan(&ownerValid, &locationValid, &containerOwned, &containerAtLocation)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to check stockpile marker target")
+	}
+
+	switch {
+	case !ownerValid:
+		return "owner is not one of your characters or corporations", nil
+	case !locationValid && stockpileScope(marker.Scope) == models.StockpileScopeLocation:
+		return "owner has no assets at this location", nil
+	case !locationValid:
+		return "unknown " + strings.ReplaceAll(stockpileScope(marker.Scope), "_", " "), nil
+	case !containerOwned:
+		return "container is not one of the owner's items", nil
+	case !containerAtLocation:
+		return "container is not at the marker's location", nil
+	}
+
+	return "", nil
+}
+
+// stockpileScope treats markers saved without a scope as covering their exact location
+func stockpileScope(scope string) string {
+	if scope == "" {
+		return models.StockpileScopeLocation
+	}
+	return scope
+}
@@ -372,3 +372,142 @@ func Test_StockpileMarkersShouldIsolateByUser(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func Test_StockpileMarkersShouldManageByID(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepo := repositories.NewUserRepository(db)
+	stockpileRepo := repositories.NewStockpileMarkers(db)
+
+	testUser := &repositories.User{ID: 42, Name: "Test User"}
+	err = userRepo.Add(context.Background(), testUser)
+	assert.NoError(t, err)
+
+	otherUser := &repositories.User{ID: 43, Name: "Other User"}
+	err = userRepo.Add(context.Background(), otherUser)
+	assert.NoError(t, err)
+
+	tritanium := &models.StockpileMarker{UserID: testUser.ID, TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 10000}
+	err = stockpileRepo.Upsert(context.Background(), tritanium)
+	assert.NoError(t, err)
+	assert.NotZero(t, tritanium.ID)
+
+	pyerite := &models.StockpileMarker{UserID: testUser.ID, TypeID: 35, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 5000}
+	err = stockpileRepo.Upsert(context.Background(), pyerite)
+	assert.NoError(t, err)
+
+	// Upserting the same target again keeps the row and its id
+	again := &models.StockpileMarker{UserID: testUser.ID, TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 20000}
+	err = stockpileRepo.Upsert(context.Background(), again)
+	assert.NoError(t, err)
+	assert.Equal(t, tritanium.ID, again.ID)
+
+	found, err := stockpileRepo.GetByID(context.Background(), tritanium.ID, testUser.ID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, found) {
+		assert.Equal(t, tritanium.ID, found.ID)
+		assert.Equal(t, int64(20000), found.DesiredQuantity)
+	}
+
+	found, err = stockpileRepo.GetByID(context.Background(), tritanium.ID, otherUser.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+
+	// Move the marker to another station
+	moved := &models.StockpileMarker{ID: tritanium.ID, UserID: testUser.ID, TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003761, DesiredQuantity: 15000}
+	updated, err := stockpileRepo.Update(context.Background(), moved)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	found, err = stockpileRepo.GetByID(context.Background(), tritanium.ID, testUser.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(60003761), found.LocationID)
+	assert.Equal(t, int64(15000), found.DesiredQuantity)
+
+	// Moving onto another marker's target is a conflict
+	clash := &models.StockpileMarker{ID: pyerite.ID, UserID: testUser.ID, TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003761, DesiredQuantity: 1}
+	updated, err = stockpileRepo.Update(context.Background(), clash)
+	assert.ErrorIs(t, err, repositories.ErrStockpileMarkerExists)
+	assert.False(t, updated)
+
+	// Other users cannot update or delete the marker
+	moved.UserID = otherUser.ID
+	updated, err = stockpileRepo.Update(context.Background(), moved)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	deleted, err := stockpileRepo.DeleteByID(context.Background(), tritanium.ID, otherUser.ID)
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+
+	deleted, err = stockpileRepo.DeleteByID(context.Background(), tritanium.ID, testUser.ID)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	markers, err := stockpileRepo.GetByUser(context.Background(), testUser.ID)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 1)
+	assert.Equal(t, pyerite.ID, markers[0].ID)
+}
+
+func Test_StockpileMarkersShouldCheckTarget(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	userRepo := repositories.NewUserRepository(db)
+	characterRepo := repositories.NewCharacterRepository(db)
+	stockpileRepo := repositories.NewStockpileMarkers(db)
+
+	testUser := &repositories.User{ID: 42, Name: "Test User"}
+	err = userRepo.Add(context.Background(), testUser)
+	assert.NoError(t, err)
+
+	err = characterRepo.Add(context.Background(), &repositories.Character{ID: 1337, Name: "Test Character", UserID: testUser.ID})
+	assert.NoError(t, err)
+
+	err = characterRepo.Add(context.Background(), &repositories.Character{ID: 1338, Name: "Empty Alt", UserID: testUser.ID})
+	assert.NoError(t, err)
+
+	// A container in the first station, and loose Tritanium in the second
+	err = repositories.NewCharacterAssets(db).UpdateAssets(context.Background(), 1337, testUser.ID, []*models.EveAsset{
+		{ItemID: 5001, IsSingleton: true, LocationID: 60003760, LocationType: "station", LocationFlag: "Hangar", Quantity: 1, TypeID: 3293},
+		{ItemID: 5002, LocationID: 5001, LocationType: "item", LocationFlag: "Unlocked", Quantity: 100, TypeID: 34},
+		{ItemID: 5004, LocationID: 60003761, LocationType: "station", LocationFlag: "Hangar", Quantity: 100, TypeID: 34},
+	})
+	assert.NoError(t, err)
+
+	ownedContainerID := int64(5001)
+	containerID := int64(5003)
+	tests := []struct {
+		name    string
+		marker  *models.StockpileMarker
+		problem string
+	}{
+		{"valid station", &models.StockpileMarker{OwnerType: "character", OwnerID: 1337, LocationID: 60003760}, ""},
+		{"valid container", &models.StockpileMarker{OwnerType: "character", OwnerID: 1337, LocationID: 60003760, ContainerID: &ownedContainerID}, ""},
+		{"valid solar system", &models.StockpileMarker{Scope: models.StockpileScopeSolarSystem, OwnerType: "character", OwnerID: 1337, LocationID: 30000142}, ""},
+		{"valid region", &models.StockpileMarker{Scope: models.StockpileScopeRegion, OwnerType: "character", OwnerID: 1337, LocationID: 10000002}, ""},
+		{"someone else's character", &models.StockpileMarker{OwnerType: "character", OwnerID: 9999, LocationID: 60003760}, "owner is not one of your characters or corporations"},
+		{"unknown corporation", &models.StockpileMarker{OwnerType: "corporation", OwnerID: 2001, LocationID: 60003760}, "owner is not one of your characters or corporations"},
+		{"unknown station", &models.StockpileMarker{OwnerType: "character", OwnerID: 1337, LocationID: 60000001}, "owner has no assets at this location"},
+		{"station where only another character has assets", &models.StockpileMarker{OwnerType: "character", OwnerID: 1338, LocationID: 60003760}, "owner has no assets at this location"},
+		{"unknown solar system", &models.StockpileMarker{Scope: models.StockpileScopeSolarSystem, OwnerType: "character", OwnerID: 1337, LocationID: 30000001}, "unknown solar system"},
+		{"container not owned", &models.StockpileMarker{OwnerType: "character", OwnerID: 1337, LocationID: 60003760, ContainerID: &containerID}, "container is not one of the owner's items"},
+		{"container elsewhere", &models.StockpileMarker{OwnerType: "character", OwnerID: 1337, LocationID: 60003761, ContainerID: &ownedContainerID}, "container is not at the marker's location"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.marker.UserID = testUser.ID
+			tt.marker.TypeID = 34
+			problem, err := stockpileRepo.CheckTarget(context.Background(), tt.marker)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.problem, problem)
+		})
+	}
+}
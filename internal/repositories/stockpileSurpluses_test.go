@@ -0,0 +1,79 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StockpileSurpluses_SubtractsLocationMarkers(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User')`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO characters (id, user_id, name, esi_token, esi_refresh_token, esi_token_expires_on)
+		VALUES (12345, 1, 'Test Character', 'token', 'refresh', NOW())
+	`)
+	require.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	// 3000 in the Jita hangar, 4000 in a container there and 500 at the second station
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO character_assets
+		(character_id, user_id, item_id, update_key, is_blueprint_copy, is_singleton,
+		 location_id, location_type, quantity, type_id, location_flag)
+		VALUES
+			(12345, 1, 2001, 'key1', false, false, 60003760, 'station', 3000, 34, 'Hangar'),
+			(12345, 1, 2002, 'key2', false, true, 60003760, 'station', 1, 3293, 'Hangar'),
+			(12345, 1, 2003, 'key3', false, false, 2002, 'item', 4000, 34, 'Unlocked'),
+			(12345, 1, 2004, 'key4', false, false, 60003761, 'station', 500, 34, 'Hangar'),
+			(12345, 1, 2005, 'key5', false, false, 60003761, 'station', 900, 35, 'Hangar')
+	`)
+	require.NoError(t, err)
+
+	containerID := int64(2002)
+	stockpileMarkersRepo := repositories.NewStockpileMarkers(db)
+	markers := []*models.StockpileMarker{
+		{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, DesiredQuantity: 1000},
+		{UserID: 1, TypeID: 34, OwnerType: "character", OwnerID: 12345, LocationID: 60003760, ContainerID: &containerID, DesiredQuantity: 5000},
+		// Aggregate markers don't hold stock in place
+		{UserID: 1, TypeID: 34, OwnerType: models.StockpileOwnerAll, Scope: models.StockpileScopeAnywhere, DesiredQuantity: 100000},
+	}
+	for _, marker := range markers {
+		require.NoError(t, stockpileMarkersRepo.Upsert(ctx, marker))
+	}
+
+	assetsRepo := repositories.NewAssets(db)
+	surpluses, err := assetsRepo.GetStockpileSurpluses(ctx, 1, []int64{34})
+	require.NoError(t, err)
+	require.Len(t, surpluses, 2)
+
+	hangar := surpluses[0]
+	assert.Equal(t, int64(60003760), hangar.LocationID)
+	assert.Nil(t, hangar.ContainerName)
+	assert.Equal(t, int64(3000), hangar.Quantity)
+	assert.Equal(t, int64(1000), hangar.Reserved)
+	assert.Equal(t, int64(2000), hangar.Surplus)
+	assert.Equal(t, "Test Character", hangar.OwnerName)
+	assert.Equal(t, int64(30000142), hangar.SolarSystemID)
+	assert.Equal(t, int64(10000002), hangar.RegionID)
+	assert.InDelta(t, 0.01, hangar.UnitVolume, 0.0001)
+
+	second := surpluses[1]
+	assert.Equal(t, int64(60003761), second.LocationID)
+	assert.Equal(t, int64(500), second.Surplus)
+
+	empty, err := assetsRepo.GetStockpileSurpluses(ctx, 1, []int64{})
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
@@ -0,0 +1,427 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrStockpileTemplateNotFound is returned when a template does not exist or belongs to another user
+	ErrStockpileTemplateNotFound = errors.New("stockpile template not found")
+	// ErrStockpileTemplateApplicationNotFound is returned when removing an application the template does not have
+	ErrStockpileTemplateApplicationNotFound = errors.New("stockpile template application not found")
+	// ErrStockpileTemplateConflict is returned when a template would generate a marker where one already
+	// exists, either made by hand or by another application
+	ErrStockpileTemplateConflict = errors.New("a stockpile marker from elsewhere already covers one of the template's items at that location")
+)
+
+type StockpileTemplates struct {
+	db *sql.DB
+}
+
+func NewStockpileTemplates(db *sql.DB) *StockpileTemplates {
+	return &StockpileTemplates{db: db}
+}
+
+// GetByUser returns a user's templates with their items and applications
+func (r *StockpileTemplates) GetByUser(ctx context.Context, userID int64) ([]*models.StockpileTemplate, error) {
+	return r.get(ctx, userID, nil)
+}
+
+// GetByID returns one of userID's templates
+func (r *StockpileTemplates) GetByID(ctx context.Context, templateID, userID int64) (*models.StockpileTemplate, error) {
+	templates, err := r.get(ctx, userID, &templateID)
+	if err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return nil, errors.New("stockpile template not found")
+	}
+	return templates[0], nil
+}
+
+func (r *StockpileTemplates) get(ctx context.Context, userID int64, templateID *int64) ([]*models.StockpileTemplate, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, name, description, created_at, updated_at
+		FROM stockpile_templates
+		WHERE user_id = $1 AND ($2::BIGINT IS NULL OR id = $2)
+		ORDER BY name
+	`, userID, templateID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile templates")
+	}
+	defer rows.Close()
+
+	templates := []*models.StockpileTemplate{}
+	byID := map[int64]*models.StockpileTemplate{}
+	for rows.Next() {
+		template := &models.StockpileTemplate{
+			Items:        []*models.StockpileTemplateItem{},
+			Applications: []*models.StockpileTemplateApplication{},
+		}
+		err = rows.Scan(
+			&template.ID,
+			&template.UserID,
+			&template.Name,
+			&template.Description,
+			&template.CreatedAt,
+			&template.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile template")
+		}
+		templates = append(templates, template)
+		byID[template.ID] = template
+	}
+	rows.Close()
+
+	if len(templates) == 0 {
+		return templates, nil
+	}
+
+	itemRows, err := r.db.QueryContext(ctx, `
+		SELECT i.template_id, i.type_id, t.type_name, i.quantity
+		FROM stockpile_template_items i
+		INNER JOIN stockpile_templates st ON st.id = i.template_id
+		INNER JOIN asset_item_types t ON t.type_id = i.type_id
+		WHERE st.user_id = $1 AND ($2::BIGINT IS NULL OR st.id = $2)
+		ORDER BY t.type_name
+	`, userID, templateID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile template items")
+	}
+	defer itemRows.Close()
+
+	for itemRows.Next() {
+		var id int64
+		var item models.StockpileTemplateItem
+		err = itemRows.Scan(&id, &item.TypeID, &item.TypeName, &item.Quantity)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile template item")
+		}
+		byID[id].Items = append(byID[id].Items, &item)
+	}
+	itemRows.Close()
+
+	appRows, err := r.db.QueryContext(ctx, `
+		SELECT a.id, a.template_id, a.owner_type, a.owner_id, a.location_id,
+		       a.container_id, a.division_number, a.multiplier, a.created_at
+		FROM stockpile_template_applications a
+		INNER JOIN stockpile_templates st ON st.id = a.template_id
+		WHERE st.user_id = $1 AND ($2::BIGINT IS NULL OR st.id = $2)
+		ORDER BY a.created_at, a.id
+	`, userID, templateID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query stockpile template applications")
+	}
+	defer appRows.Close()
+
+	for appRows.Next() {
+		var application models.StockpileTemplateApplication
+		err = appRows.Scan(
+			&application.ID,
+			&application.TemplateID,
+			&application.OwnerType,
+			&application.OwnerID,
+			&application.LocationID,
+			&application.ContainerID,
+			&application.DivisionNumber,
+			&application.Multiplier,
+			&application.CreatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan stockpile template application")
+		}
+		byID[application.TemplateID].Applications = append(byID[application.TemplateID].Applications, &application)
+	}
+
+	return templates, nil
+}
+
+// Create adds a template and its items for template.UserID
+func (r *StockpileTemplates) Create(ctx context.Context, template *models.StockpileTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stockpile_templates (user_id, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, template.UserID, template.Name, template.Description).Scan(&template.ID, &template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create stockpile template")
+	}
+
+	err = r.insertItems(ctx, tx, template.ID, template.Items)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Update replaces a template's name, description and items and regenerates the markers of every location it is applied to.
+// It returns ErrStockpileTemplateConflict if an added type is already covered by another marker at one of them.
+func (r *StockpileTemplates) Update(ctx context.Context, template *models.StockpileTemplate) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, template.ID, template.UserID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stockpile_templates
+		SET name = $2, description = $3, updated_at = NOW()
+		WHERE id = $1
+	`, template.ID, template.Name, template.Description)
+	if err != nil {
+		return errors.Wrap(err, "failed to update stockpile template")
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM stockpile_template_items WHERE template_id = $1`, template.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to clear stockpile template items")
+	}
+
+	err = r.insertItems(ctx, tx, template.ID, template.Items)
+	if err != nil {
+		return err
+	}
+
+	err = r.syncMarkers(ctx, tx, template.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete removes a template along with every marker it generated
+func (r *StockpileTemplates) Delete(ctx context.Context, templateID, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, templateID, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM stockpile_markers m
+		USING stockpile_template_applications a
+		WHERE m.template_application_id = a.id AND a.template_id = $1
+	`, templateID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stockpile template markers")
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM stockpile_templates WHERE id = $1`, templateID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stockpile template")
+	}
+
+	return tx.Commit()
+}
+
+// Apply stamps the template onto a location, or changes the multiplier where it is already applied.
+// It returns ErrStockpileTemplateConflict if another marker already covers one of the template's types there.
+func (r *StockpileTemplates) Apply(ctx context.Context, userID int64, application *models.StockpileTemplateApplication) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, application.TemplateID, userID)
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO stockpile_template_applications
+		(template_id, user_id, owner_type, owner_id, location_id, container_id, division_number, multiplier)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (template_id, owner_type, owner_id, location_id, COALESCE(container_id, 0::BIGINT), COALESCE(division_number, 0))
+		DO UPDATE SET
+			multiplier = EXCLUDED.multiplier,
+			updated_at = NOW()
+		RETURNING id, created_at
+	`,
+		application.TemplateID,
+		userID,
+		application.OwnerType,
+		application.OwnerID,
+		application.LocationID,
+		application.ContainerID,
+		application.DivisionNumber,
+		application.Multiplier,
+	).Scan(&application.ID, &application.CreatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert stockpile template application")
+	}
+
+	err = r.syncMarkers(ctx, tx, application.TemplateID, &application.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Unapply removes the template from one location along with the markers it generated there
+func (r *StockpileTemplates) Unapply(ctx context.Context, templateID, applicationID, userID int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	err = r.checkOwner(ctx, tx, templateID, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM stockpile_markers WHERE template_application_id = $1
+	`, applicationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stockpile template markers")
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM stockpile_template_applications WHERE id = $1 AND template_id = $2
+	`, applicationID, templateID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete stockpile template application")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "failed to get rows affected")
+	}
+	if rowsAffected == 0 {
+		return ErrStockpileTemplateApplicationNotFound
+	}
+
+	return tx.Commit()
+}
+
+func (r *StockpileTemplates) insertItems(ctx context.Context, tx *sql.Tx, templateID int64, items []*models.StockpileTemplateItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	typeIDs := make([]int64, len(items))
+	quantities := make([]int64, len(items))
+	for i, item := range items {
+		typeIDs[i] = item.TypeID
+		quantities[i] = item.Quantity
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO stockpile_template_items (template_id, type_id, quantity)
+		SELECT $1, type_id, quantity
+		FROM UNNEST($2::BIGINT[], $3::BIGINT[]) AS i(type_id, quantity)
+	`, templateID, pq.Array(typeIDs), pq.Array(quantities))
+	if err != nil {
+		return errors.Wrap(err, "failed to insert stockpile template items")
+	}
+
+	return nil
+}
+
+func (r *StockpileTemplates) checkOwner(ctx context.Context, tx *sql.Tx, templateID, userID int64) error {
+	var owned bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM stockpile_templates WHERE id = $1 AND user_id = $2)
+	`, templateID, userID).Scan(&owned)
+	if err != nil {
+		return errors.Wrap(err, "failed to check stockpile template")
+	}
+	if !owned {
+		return ErrStockpileTemplateNotFound
+	}
+	return nil
+}
+
+// syncMarkers makes the markers at each of the template's applications (or only applicationID when given)
+// match its items: one marker per type at quantity x multiplier, with markers for removed types deleted.
+// An application only ever touches markers it generated; if a marker made by hand or by another
+// application is in the way it fails with ErrStockpileTemplateConflict.
+func (r *StockpileTemplates) syncMarkers(ctx context.Context, tx *sql.Tx, templateID int64, applicationID *int64) error {
+	var conflict bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1
+			FROM stockpile_template_applications a
+			INNER JOIN stockpile_template_items i ON i.template_id = a.template_id
+			INNER JOIN stockpile_markers m ON m.user_id = a.user_id
+				AND m.type_id = i.type_id
+				AND m.scope = 'location'
+				AND m.owner_type = a.owner_type
+				AND m.owner_id = a.owner_id
+				AND m.location_id = a.location_id
+				AND COALESCE(m.container_id, 0::BIGINT) = COALESCE(a.container_id, 0::BIGINT)
+				AND COALESCE(m.division_number, 0) = COALESCE(a.division_number, 0)
+			WHERE a.template_id = $1 AND ($2::BIGINT IS NULL OR a.id = $2)
+			  AND m.template_application_id IS DISTINCT FROM a.id
+		)
+	`, templateID, applicationID).Scan(&conflict)
+	if err != nil {
+		return errors.Wrap(err, "failed to check for conflicting stockpile markers")
+	}
+	if conflict {
+		return ErrStockpileTemplateConflict
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stockpile_markers
+		(user_id, type_id, owner_type, owner_id, location_id, container_id, division_number,
+		 desired_quantity, template_application_id, updated_at)
+		SELECT a.user_id, i.type_id, a.owner_type, a.owner_id, a.location_id, a.container_id, a.division_number,
+		       i.quantity * a.multiplier, a.id, NOW()
+		FROM stockpile_template_applications a
+		INNER JOIN stockpile_template_items i ON i.template_id = a.template_id
+		WHERE a.template_id = $1 AND ($2::BIGINT IS NULL OR a.id = $2)
+		ON CONFLICT (user_id, type_id, scope, owner_type, owner_id, location_id, COALESCE(container_id, 0::BIGINT), COALESCE(division_number, 0))
+		DO UPDATE SET
+			desired_quantity = EXCLUDED.desired_quantity,
+			updated_at = NOW()
+		WHERE stockpile_markers.template_application_id = EXCLUDED.template_application_id
+	`, templateID, applicationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate stockpile template markers")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM stockpile_markers m
+		USING stockpile_template_applications a
+		WHERE m.template_application_id = a.id
+		  AND a.template_id = $1
+		  AND ($2::BIGINT IS NULL OR a.id = $2)
+		  AND NOT EXISTS (
+			SELECT 1 FROM stockpile_template_items i
+			WHERE i.template_id = a.template_id AND i.type_id = m.type_id
+		  )
+	`, templateID, applicationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove stale stockpile template markers")
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func markerQuantities(markers []*models.StockpileMarker, locationID int64) map[int64]int64 {
+	quantities := map[int64]int64{}
+	for _, marker := range markers {
+		if marker.LocationID == locationID {
+			quantities[marker.TypeID] = marker.DesiredQuantity
+		}
+	}
+	return quantities
+}
+
+func Test_StockpileTemplatesShouldApplyAndPropagateEdits(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	markersRepo := repositories.NewStockpileMarkers(db)
+	templatesRepo := repositories.NewStockpileTemplates(db)
+
+	user := &repositories.User{ID: 4100, Name: "Logistics"}
+	assert.NoError(t, userRepo.Add(ctx, user))
+
+	template := &models.StockpileTemplate{
+		UserID: user.ID,
+		Name:   "Minerals",
+		Items: []*models.StockpileTemplateItem{
+			{TypeID: 34, Quantity: 1000},
+			{TypeID: 35, Quantity: 500},
+		},
+	}
+	assert.NoError(t, templatesRepo.Create(ctx, template))
+	assert.NotZero(t, template.ID)
+
+	first := &models.StockpileTemplateApplication{TemplateID: template.ID, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, Multiplier: 2}
+	assert.NoError(t, templatesRepo.Apply(ctx, user.ID, first))
+	division := 1
+	second := &models.StockpileTemplateApplication{TemplateID: template.ID, OwnerType: "corporation", OwnerID: 2001, LocationID: 60003761, DivisionNumber: &division, Multiplier: 1}
+	assert.NoError(t, templatesRepo.Apply(ctx, user.ID, second))
+
+	markers, err := markersRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 4)
+	assert.Equal(t, map[int64]int64{34: 2000, 35: 1000}, markerQuantities(markers, 60003760))
+	assert.Equal(t, map[int64]int64{34: 1000, 35: 500}, markerQuantities(markers, 60003761))
+	for _, marker := range markers {
+		assert.NotNil(t, marker.TemplateApplicationID)
+	}
+
+	// Editing the template updates every location: Tritanium changes, Pyerite is dropped, Mexallon is added
+	template.Items = []*models.StockpileTemplateItem{
+		{TypeID: 34, Quantity: 3000},
+		{TypeID: 36, Quantity: 10},
+	}
+	assert.NoError(t, templatesRepo.Update(ctx, template))
+
+	markers, err = markersRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{34: 6000, 36: 20}, markerQuantities(markers, 60003760))
+	assert.Equal(t, map[int64]int64{34: 3000, 36: 10}, markerQuantities(markers, 60003761))
+
+	// Re-applying to the same place changes the multiplier instead of adding a second application
+	again := &models.StockpileTemplateApplication{TemplateID: template.ID, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, Multiplier: 1}
+	assert.NoError(t, templatesRepo.Apply(ctx, user.ID, again))
+	assert.Equal(t, first.ID, again.ID)
+
+	saved, err := templatesRepo.GetByID(ctx, template.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, saved.Items, 2)
+	assert.Equal(t, "Mexallon", saved.Items[0].TypeName)
+	assert.Len(t, saved.Applications, 2)
+
+	// Removing one application only removes its markers
+	assert.NoError(t, templatesRepo.Unapply(ctx, template.ID, second.ID, user.ID))
+	markers, err = markersRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int64{34: 3000, 36: 10}, markerQuantities(markers, 60003760))
+	assert.Empty(t, markerQuantities(markers, 60003761))
+
+	err = templatesRepo.Unapply(ctx, template.ID, second.ID, user.ID)
+	assert.ErrorIs(t, err, repositories.ErrStockpileTemplateApplicationNotFound)
+
+	// Other users cannot touch the template
+	err = templatesRepo.Delete(ctx, template.ID, 9999)
+	assert.ErrorIs(t, err, repositories.ErrStockpileTemplateNotFound)
+
+	assert.NoError(t, templatesRepo.Delete(ctx, template.ID, user.ID))
+	markers, err = markersRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, markers)
+
+	templates, err := templatesRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func Test_StockpileTemplatesShouldRejectOverlappingMarkers(t *testing.T) {
+	db, err := setupDatabase()
+	assert.NoError(t, err)
+
+	setupTestUniverse(t, db)
+
+	ctx := context.Background()
+	userRepo := repositories.NewUserRepository(db)
+	markersRepo := repositories.NewStockpileMarkers(db)
+	templatesRepo := repositories.NewStockpileTemplates(db)
+
+	user := &repositories.User{ID: 4110, Name: "Logistics"}
+	assert.NoError(t, userRepo.Add(ctx, user))
+
+	manual := &models.StockpileMarker{
+		UserID: user.ID, TypeID: 34, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, DesiredQuantity: 1,
+	}
+	assert.NoError(t, markersRepo.Upsert(ctx, manual))
+
+	minerals := &models.StockpileTemplate{UserID: user.ID, Name: "Minerals", Items: []*models.StockpileTemplateItem{
+		{TypeID: 34, Quantity: 1000},
+		{TypeID: 35, Quantity: 500},
+	}}
+	assert.NoError(t, templatesRepo.Create(ctx, minerals))
+	pyerite := &models.StockpileTemplate{UserID: user.ID, Name: "Pyerite", Items: []*models.StockpileTemplateItem{
+		{TypeID: 35, Quantity: 100},
+	}}
+	assert.NoError(t, templatesRepo.Create(ctx, pyerite))
+
+	// A hand-made marker is never taken over
+	err = templatesRepo.Apply(ctx, user.ID, &models.StockpileTemplateApplication{
+		TemplateID: minerals.ID, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, Multiplier: 1,
+	})
+	assert.ErrorIs(t, err, repositories.ErrStockpileTemplateConflict)
+
+	saved, err := templatesRepo.GetByID(ctx, minerals.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, saved.Applications)
+
+	// Nor is a marker another template generated
+	application := &models.StockpileTemplateApplication{
+		TemplateID: pyerite.ID, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, Multiplier: 1,
+	}
+	assert.NoError(t, templatesRepo.Apply(ctx, user.ID, application))
+
+	minerals.Items = []*models.StockpileTemplateItem{{TypeID: 35, Quantity: 500}}
+	assert.NoError(t, templatesRepo.Update(ctx, minerals))
+	err = templatesRepo.Apply(ctx, user.ID, &models.StockpileTemplateApplication{
+		TemplateID: minerals.ID, OwnerType: "character", OwnerID: 1337, LocationID: 60003760, Multiplier: 1,
+	})
+	assert.ErrorIs(t, err, repositories.ErrStockpileTemplateConflict)
+
+	// Editing an applied template onto a hand-made marker is rejected as a whole
+	pyerite.Items = []*models.StockpileTemplateItem{{TypeID: 34, Quantity: 5}, {TypeID: 35, Quantity: 100}}
+	err = templatesRepo.Update(ctx, pyerite)
+	assert.ErrorIs(t, err, repositories.ErrStockpileTemplateConflict)
+
+	saved, err = templatesRepo.GetByID(ctx, pyerite.ID, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, saved.Items, 1)
+
+	// Removing the template leaves the hand-made marker alone
+	assert.NoError(t, templatesRepo.Unapply(ctx, pyerite.ID, application.ID, user.ID))
+	markers, err := markersRepo.GetByUser(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Len(t, markers, 1)
+	assert.Equal(t, manual.ID, markers[0].ID)
+	assert.Equal(t, int64(1), markers[0].DesiredQuantity)
+	assert.Nil(t, markers[0].TemplateApplicationID)
+}
@@ -0,0 +1,342 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type Webhooks struct {
+	db *sql.DB
+}
+
+func NewWebhooks(db *sql.DB) *Webhooks {
+	return &Webhooks{db: db}
+}
+
+// GetByUser returns the user's webhooks, oldest first, with their secrets masked
+func (r *Webhooks) GetByUser(ctx context.Context, userID int64) ([]*models.Webhook, error) {
+	query := `
+		SELECT id, user_id, url, secret, event_types, is_active, created_at, updated_at
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query webhooks")
+	}
+	defer rows.Close()
+
+	webhooks := []*models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		err = rows.Scan(
+			&webhook.ID,
+			&webhook.UserID,
+			&webhook.URL,
+			&webhook.Secret,
+			pq.Array(&webhook.EventTypes),
+			&webhook.IsActive,
+			&webhook.CreatedAt,
+			&webhook.UpdatedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook")
+		}
+		if webhook.EventTypes == nil {
+			webhook.EventTypes = []string{}
+		}
+		webhook.Secret = maskWebhookSecret(webhook.Secret)
+		webhooks = append(webhooks, &webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating webhooks")
+	}
+
+	return webhooks, nil
+}
+
+// Create stores a new webhook, setting its ID and timestamps
+func (r *Webhooks) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (user_id, url, secret, event_types, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.UserID,
+		webhook.URL,
+		webhook.Secret,
+		pq.Array(webhook.EventTypes),
+		webhook.IsActive,
+	).Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook")
+	}
+
+	return nil
+}
+
+// Update changes the URL, event types and active flag of one of the user's webhooks, loading the
+// rest of it, with the secret masked, into webhook. It reports false if the user has no such webhook.
+func (r *Webhooks) Update(ctx context.Context, webhook *models.Webhook) (bool, error) {
+	query := `
+		UPDATE webhooks
+		SET url = $3, event_types = $4, is_active = $5, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING secret, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		webhook.URL,
+		pq.Array(webhook.EventTypes),
+		webhook.IsActive,
+	).Scan(&webhook.Secret, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to update webhook")
+	}
+	webhook.Secret = maskWebhookSecret(webhook.Secret)
+
+	return true, nil
+}
+
+// RotateSecret replaces the secret of one of the user's webhooks with webhook.Secret, loading the
+// rest of it into webhook. It reports false if the user has no such webhook.
+func (r *Webhooks) RotateSecret(ctx context.Context, webhook *models.Webhook) (bool, error) {
+	query := `
+		UPDATE webhooks
+		SET secret = $3, updated_at = NOW()
+		WHERE id = $1 AND user_id = $2
+		RETURNING url, event_types, is_active, created_at, updated_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.ID,
+		webhook.UserID,
+		webhook.Secret,
+	).Scan(&webhook.URL, pq.Array(&webhook.EventTypes), &webhook.IsActive, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to rotate webhook secret")
+	}
+	if webhook.EventTypes == nil {
+		webhook.EventTypes = []string{}
+	}
+
+	return true, nil
+}
+
+// Delete removes one of the user's webhooks along with its delivery log. It reports false if
+// the user has no such webhook.
+func (r *Webhooks) Delete(ctx context.Context, id int64, userID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to delete webhook")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Enqueue queues an event for every active webhook of the user subscribed to its type and
+// returns how many deliveries were queued
+func (r *Webhooks) Enqueue(ctx context.Context, userID int64, eventID string, eventType string, payload []byte) (int64, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT id, $2, $3, $4
+		FROM webhooks
+		WHERE user_id = $1
+			AND is_active
+			AND (cardinality(event_types) = 0 OR $3 = ANY(event_types))
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, eventID, eventType, payload)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to enqueue webhook deliveries")
+	}
+
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return queued, nil
+}
+
+// EnqueueForWebhook queues an event for one of the user's webhooks regardless of its
+// subscriptions. It reports false if the user has no such webhook.
+func (r *Webhooks) EnqueueForWebhook(ctx context.Context, webhookID int64, userID int64, eventID string, eventType string, payload []byte) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		SELECT id, $3, $4, $5
+		FROM webhooks
+		WHERE id = $1 AND user_id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, webhookID, userID, eventID, eventType, payload)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to enqueue webhook delivery")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get rows affected")
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetDueDeliveries returns pending deliveries whose next attempt is due, oldest first, with the
+// URL and secret of their webhook
+func (r *Webhooks) GetDueDeliveries(ctx context.Context, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT
+			d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at,
+			w.url, w.secret
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = 'pending'
+			AND d.next_attempt_at <= NOW()
+			AND w.is_active
+		ORDER BY d.next_attempt_at, d.id
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query due webhook deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook delivery")
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt. Deliveries left pending are tried
+// again at nextAttemptAt.
+func (r *Webhooks) RecordAttempt(ctx context.Context, deliveryID int64, status string, statusCode *int, lastError *string, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET
+			status = $2,
+			attempts = attempts + 1,
+			last_status_code = $3,
+			last_error = $4,
+			next_attempt_at = $5,
+			delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() ELSE delivered_at END
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query, deliveryID, status, statusCode, lastError, nextAttemptAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to record webhook delivery attempt")
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the most recent deliveries of one of the user's webhooks, newest first
+func (r *Webhooks) GetDeliveries(ctx context.Context, webhookID int64, userID int64, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT
+			d.id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+			d.next_attempt_at, d.last_status_code, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.webhook_id = $1 AND w.user_id = $2
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, webhookID, userID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query webhook deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err = rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook delivery")
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// maskWebhookSecret hides all but the last four characters of a secret, which is only shown in
+// full when it is created or rotated
+func maskWebhookSecret(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
@@ -0,0 +1,162 @@
+package repositories_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Webhooks_CRUD(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User'), (2, 'Other User')`)
+	require.NoError(t, err)
+
+	repo := repositories.NewWebhooks(db)
+
+	webhook := &models.Webhook{UserID: 1, URL: "https://example.com/hook", Secret: "s3cret", EventTypes: []string{"purchase.created"}, IsActive: true}
+	require.NoError(t, repo.Create(ctx, webhook))
+	assert.NotZero(t, webhook.ID)
+
+	webhooks, err := repo.GetByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, []string{"purchase.created"}, webhooks[0].EventTypes)
+	assert.Equal(t, "****cret", webhooks[0].Secret)
+
+	// Another user can't change it
+	found, err := repo.Update(ctx, &models.Webhook{ID: webhook.ID, UserID: 2, URL: "https://evil.example.com", EventTypes: []string{}})
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	updated := &models.Webhook{ID: webhook.ID, UserID: 1, URL: "https://example.com/other", EventTypes: []string{}, IsActive: false}
+	found, err = repo.Update(ctx, updated)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "****cret", updated.Secret)
+
+	webhooks, err = repo.GetByUser(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, webhooks, 1)
+	assert.Equal(t, "https://example.com/other", webhooks[0].URL)
+	assert.Equal(t, []string{}, webhooks[0].EventTypes)
+	assert.False(t, webhooks[0].IsActive)
+
+	// Only the owner can rotate the secret, and the new one is kept for signing
+	found, err = repo.RotateSecret(ctx, &models.Webhook{ID: webhook.ID, UserID: 2, Secret: "stolen"})
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	rotated := &models.Webhook{ID: webhook.ID, UserID: 1, Secret: "n3w-s3cret"}
+	found, err = repo.RotateSecret(ctx, rotated)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "https://example.com/other", rotated.URL)
+	assert.Equal(t, "n3w-s3cret", rotated.Secret)
+
+	var stored string
+	err = db.QueryRowContext(ctx, `SELECT secret FROM webhooks WHERE id = $1`, webhook.ID).Scan(&stored)
+	require.NoError(t, err)
+	assert.Equal(t, "n3w-s3cret", stored)
+
+	found, err = repo.Delete(ctx, webhook.ID, 2)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	found, err = repo.Delete(ctx, webhook.ID, 1)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	webhooks, err = repo.GetByUser(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 0)
+}
+
+func Test_Webhooks_DeliveryLifecycle(t *testing.T) {
+	db, err := setupDatabase()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	_, err = db.ExecContext(ctx, `INSERT INTO users (id, name) VALUES (1, 'Test User'), (2, 'Other User')`)
+	require.NoError(t, err)
+
+	repo := repositories.NewWebhooks(db)
+
+	all := &models.Webhook{UserID: 1, URL: "https://example.com/all", Secret: "a", EventTypes: []string{}, IsActive: true}
+	purchases := &models.Webhook{UserID: 1, URL: "https://example.com/purchases", Secret: "b", EventTypes: []string{"purchase.created"}, IsActive: true}
+	inactive := &models.Webhook{UserID: 1, URL: "https://example.com/off", Secret: "c", EventTypes: []string{}, IsActive: false}
+	other := &models.Webhook{UserID: 2, URL: "https://example.com/other", Secret: "d", EventTypes: []string{}, IsActive: true}
+	for _, w := range []*models.Webhook{all, purchases, inactive, other} {
+		require.NoError(t, repo.Create(ctx, w))
+	}
+
+	queued, err := repo.Enqueue(ctx, 1, "evt1", "purchase.created", []byte(`{"id":"evt1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), queued)
+
+	queued, err = repo.Enqueue(ctx, 1, "evt2", "listing.created", []byte(`{"id":"evt2"}`))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), queued)
+
+	// Pings go to the chosen webhook only, and only for its owner
+	found, err := repo.EnqueueForWebhook(ctx, other.ID, 1, "evt3", "ping", []byte(`{"id":"evt3"}`))
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	due, err := repo.GetDueDeliveries(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 3)
+	assert.Equal(t, "evt1", due[0].EventID)
+	assert.NotEmpty(t, due[0].URL)
+	assert.NotEmpty(t, due[0].Secret)
+	assert.JSONEq(t, `{"id":"evt1"}`, string(due[0].Payload))
+
+	deliveredID, retryingID := due[0].ID, due[1].ID
+
+	statusCode := 200
+	require.NoError(t, repo.RecordAttempt(ctx, deliveredID, models.WebhookDeliveryDelivered, &statusCode, nil, time.Now()))
+
+	failedCode := 500
+	failure := "server error"
+	require.NoError(t, repo.RecordAttempt(ctx, retryingID, models.WebhookDeliveryPending, &failedCode, &failure, time.Now().Add(time.Hour)))
+
+	due, err = repo.GetDueDeliveries(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+
+	byID := map[int64]*models.WebhookDelivery{}
+	for _, w := range []*models.Webhook{all, purchases} {
+		deliveries, err := repo.GetDeliveries(ctx, w.ID, 1, 50)
+		require.NoError(t, err)
+		for _, d := range deliveries {
+			byID[d.ID] = d
+		}
+	}
+	require.Len(t, byID, 3)
+
+	delivered := byID[deliveredID]
+	assert.Equal(t, models.WebhookDeliveryDelivered, delivered.Status)
+	assert.Equal(t, 1, delivered.Attempts)
+	assert.NotNil(t, delivered.DeliveredAt)
+	assert.Equal(t, 200, *delivered.LastStatusCode)
+
+	retrying := byID[retryingID]
+	assert.Equal(t, models.WebhookDeliveryPending, retrying.Status)
+	assert.Equal(t, 1, retrying.Attempts)
+	assert.Equal(t, "server error", *retrying.LastError)
+	assert.Nil(t, retrying.DeliveredAt)
+
+	// Another user can't read the log
+	deliveries, err := repo.GetDeliveries(ctx, all.ID, 2, 50)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 0)
+}
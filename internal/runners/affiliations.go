@@ -0,0 +1,55 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+type AffiliationsUpdater interface {
+	UpdateAffiliations(ctx context.Context) error
+}
+
+type AffiliationsRunner struct {
+	updater       AffiliationsUpdater
+	interval      time.Duration
+	tickerFactory TickerFactory
+}
+
+func NewAffiliationsRunner(updater AffiliationsUpdater, interval time.Duration) *AffiliationsRunner {
+	return &AffiliationsRunner{
+		updater:  updater,
+		interval: interval,
+		tickerFactory: func(d time.Duration) Ticker {
+			return &realTicker{time.NewTicker(d)}
+		},
+	}
+}
+
+// WithTickerFactory allows injecting a custom ticker factory for testing
+func (r *AffiliationsRunner) WithTickerFactory(factory TickerFactory) *AffiliationsRunner {
+	r.tickerFactory = factory
+	return r
+}
+
+func (r *AffiliationsRunner) Run(ctx context.Context) error {
+	ticker := r.tickerFactory(r.interval)
+	defer ticker.Stop()
+
+	// Refresh immediately on startup
+	if err := r.updater.UpdateAffiliations(ctx); err != nil {
+		log.Error("failed to update character affiliations on startup", "error", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if err := r.updater.UpdateAffiliations(ctx); err != nil {
+				log.Error("failed to update character affiliations", "error", err)
+			}
+		}
+	}
+}
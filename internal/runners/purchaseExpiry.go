@@ -0,0 +1,55 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+type PurchaseExpiryUpdater interface {
+	ExpireStalePurchases(ctx context.Context) error
+}
+
+type PurchaseExpiryRunner struct {
+	updater       PurchaseExpiryUpdater
+	interval      time.Duration
+	tickerFactory TickerFactory
+}
+
+func NewPurchaseExpiryRunner(updater PurchaseExpiryUpdater, interval time.Duration) *PurchaseExpiryRunner {
+	return &PurchaseExpiryRunner{
+		updater:  updater,
+		interval: interval,
+		tickerFactory: func(d time.Duration) Ticker {
+			return &realTicker{time.NewTicker(d)}
+		},
+	}
+}
+
+// WithTickerFactory allows injecting a custom ticker factory for testing
+func (r *PurchaseExpiryRunner) WithTickerFactory(factory TickerFactory) *PurchaseExpiryRunner {
+	r.tickerFactory = factory
+	return r
+}
+
+func (r *PurchaseExpiryRunner) Run(ctx context.Context) error {
+	ticker := r.tickerFactory(r.interval)
+	defer ticker.Stop()
+
+	// Sweep immediately on startup
+	if err := r.updater.ExpireStalePurchases(ctx); err != nil {
+		log.Error("failed to expire stale purchases on startup", "error", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if err := r.updater.ExpireStalePurchases(ctx); err != nil {
+				log.Error("failed to expire stale purchases", "error", err)
+			}
+		}
+	}
+}
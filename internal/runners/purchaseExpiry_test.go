@@ -0,0 +1,108 @@
+package runners_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/runners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockPurchaseExpiryUpdater mocks the PurchaseExpiryUpdater interface
+type MockPurchaseExpiryUpdater struct {
+	mock.Mock
+}
+
+func (m *MockPurchaseExpiryUpdater) ExpireStalePurchases(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func Test_PurchaseExpiryRunner_ExpiresOnStartup(t *testing.T) {
+	mockUpdater := new(MockPurchaseExpiryUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewPurchaseExpiryRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	mockUpdater.On("ExpireStalePurchases", mock.Anything).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runner.Run(ctx)
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
+
+func Test_PurchaseExpiryRunner_ExpiresPeriodically(t *testing.T) {
+	mockUpdater := new(MockPurchaseExpiryUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewPurchaseExpiryRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	// Expect 3 calls: 1 on startup + 2 scheduled
+	mockUpdater.On("ExpireStalePurchases", mock.Anything).Return(nil).Times(3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	err := <-done
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
+
+func Test_PurchaseExpiryRunner_ContinuesOnError(t *testing.T) {
+	mockUpdater := new(MockPurchaseExpiryUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewPurchaseExpiryRunner(mockUpdater, 15*time.Minute).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	mockUpdater.On("ExpireStalePurchases", mock.Anything).Return(errors.New("startup error")).Once()
+	mockUpdater.On("ExpireStalePurchases", mock.Anything).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	err := <-done
+
+	// Runner should not return error even if a sweep fails
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package runners
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+)
+
+type WebhookDeliveriesUpdater interface {
+	DeliverDue(ctx context.Context) error
+}
+
+type WebhookDeliveriesRunner struct {
+	updater       WebhookDeliveriesUpdater
+	interval      time.Duration
+	tickerFactory TickerFactory
+}
+
+func NewWebhookDeliveriesRunner(updater WebhookDeliveriesUpdater, interval time.Duration) *WebhookDeliveriesRunner {
+	return &WebhookDeliveriesRunner{
+		updater:  updater,
+		interval: interval,
+		tickerFactory: func(d time.Duration) Ticker {
+			return &realTicker{time.NewTicker(d)}
+		},
+	}
+}
+
+// WithTickerFactory allows injecting a custom ticker factory for testing
+func (r *WebhookDeliveriesRunner) WithTickerFactory(factory TickerFactory) *WebhookDeliveriesRunner {
+	r.tickerFactory = factory
+	return r
+}
+
+func (r *WebhookDeliveriesRunner) Run(ctx context.Context) error {
+	ticker := r.tickerFactory(r.interval)
+	defer ticker.Stop()
+
+	// Deliver anything queued while the server was down
+	if err := r.updater.DeliverDue(ctx); err != nil {
+		log.Error("failed to deliver webhooks on startup", "error", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+			if err := r.updater.DeliverDue(ctx); err != nil {
+				log.Error("failed to deliver webhooks", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package runners_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/runners"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookDeliveriesUpdater mocks the WebhookDeliveriesUpdater interface
+type MockWebhookDeliveriesUpdater struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveriesUpdater) DeliverDue(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func Test_WebhookDeliveriesRunner_DeliversOnStartup(t *testing.T) {
+	mockUpdater := new(MockWebhookDeliveriesUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewWebhookDeliveriesRunner(mockUpdater, 15*time.Second).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	mockUpdater.On("DeliverDue", mock.Anything).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := runner.Run(ctx)
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
+
+func Test_WebhookDeliveriesRunner_DeliversPeriodically(t *testing.T) {
+	mockUpdater := new(MockWebhookDeliveriesUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewWebhookDeliveriesRunner(mockUpdater, 15*time.Second).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	// Expect 3 calls: 1 on startup + 2 scheduled
+	mockUpdater.On("DeliverDue", mock.Anything).Return(nil).Times(3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	err := <-done
+
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
+
+func Test_WebhookDeliveriesRunner_ContinuesOnError(t *testing.T) {
+	mockUpdater := new(MockWebhookDeliveriesUpdater)
+	mockTicker := NewMockTicker()
+
+	runner := runners.NewWebhookDeliveriesRunner(mockUpdater, 15*time.Second).
+		WithTickerFactory(func(d time.Duration) runners.Ticker {
+			return mockTicker
+		})
+
+	mockUpdater.On("DeliverDue", mock.Anything).Return(errors.New("startup error")).Once()
+	mockUpdater.On("DeliverDue", mock.Anything).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- runner.Run(ctx)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	mockTicker.Tick()
+	time.Sleep(10 * time.Millisecond)
+
+	cancel()
+	err := <-done
+
+	// Runner should not return error even if a delivery sweep fails
+	assert.NoError(t, err)
+	mockUpdater.AssertExpectations(t)
+}
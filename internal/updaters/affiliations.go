@@ -0,0 +1,57 @@
+package updaters
+
+import (
+	"context"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type CharacterAffiliationsRepository interface {
+	GetCharacterIDs(ctx context.Context) ([]int64, error)
+	Upsert(ctx context.Context, affiliations []*models.CharacterAffiliation) error
+}
+
+type AffiliationsEsiClient interface {
+	GetCharacterAffiliations(ctx context.Context, characterIDs []int64) ([]*models.CharacterAffiliation, error)
+}
+
+type Affiliations struct {
+	repository CharacterAffiliationsRepository
+	esiClient  AffiliationsEsiClient
+}
+
+func NewAffiliations(repository CharacterAffiliationsRepository, esiClient AffiliationsEsiClient) *Affiliations {
+	return &Affiliations{
+		repository: repository,
+		esiClient:  esiClient,
+	}
+}
+
+// UpdateAffiliations refreshes the corporation and alliance of every registered character
+// so corporation/alliance permission grants follow members as they move
+func (u *Affiliations) UpdateAffiliations(ctx context.Context) error {
+	characterIDs, err := u.repository.GetCharacterIDs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get character ids")
+	}
+
+	if len(characterIDs) == 0 {
+		return nil
+	}
+
+	affiliations, err := u.esiClient.GetCharacterAffiliations(ctx, characterIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to get character affiliations from ESI")
+	}
+
+	err = u.repository.Upsert(ctx, affiliations)
+	if err != nil {
+		return errors.Wrap(err, "failed to save character affiliations")
+	}
+
+	log.Info("updated character affiliations", "characters", len(affiliations))
+
+	return nil
+}
@@ -0,0 +1,103 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: CharacterAffiliationsRepository,AffiliationsEsiClient)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCharacterAffiliationsRepository is a mock of CharacterAffiliationsRepository interface.
+type MockCharacterAffiliationsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCharacterAffiliationsRepositoryMockRecorder
+}
+
+// MockCharacterAffiliationsRepositoryMockRecorder is the mock recorder for MockCharacterAffiliationsRepository.
+type MockCharacterAffiliationsRepositoryMockRecorder struct {
+	mock *MockCharacterAffiliationsRepository
+}
+
+// NewMockCharacterAffiliationsRepository creates a new mock instance.
+func NewMockCharacterAffiliationsRepository(ctrl *gomock.Controller) *MockCharacterAffiliationsRepository {
+	mock := &MockCharacterAffiliationsRepository{ctrl: ctrl}
+	mock.recorder = &MockCharacterAffiliationsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCharacterAffiliationsRepository) EXPECT() *MockCharacterAffiliationsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetCharacterIDs mocks base method.
+func (m *MockCharacterAffiliationsRepository) GetCharacterIDs(arg0 context.Context) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCharacterIDs", arg0)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCharacterIDs indicates an expected call of GetCharacterIDs.
+func (mr *MockCharacterAffiliationsRepositoryMockRecorder) GetCharacterIDs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacterIDs", reflect.TypeOf((*MockCharacterAffiliationsRepository)(nil).GetCharacterIDs), arg0)
+}
+
+// Upsert mocks base method.
+func (m *MockCharacterAffiliationsRepository) Upsert(arg0 context.Context, arg1 []*models.CharacterAffiliation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockCharacterAffiliationsRepositoryMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockCharacterAffiliationsRepository)(nil).Upsert), arg0, arg1)
+}
+
+// MockAffiliationsEsiClient is a mock of AffiliationsEsiClient interface.
+type MockAffiliationsEsiClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAffiliationsEsiClientMockRecorder
+}
+
+// MockAffiliationsEsiClientMockRecorder is the mock recorder for MockAffiliationsEsiClient.
+type MockAffiliationsEsiClientMockRecorder struct {
+	mock *MockAffiliationsEsiClient
+}
+
+// NewMockAffiliationsEsiClient creates a new mock instance.
+func NewMockAffiliationsEsiClient(ctrl *gomock.Controller) *MockAffiliationsEsiClient {
+	mock := &MockAffiliationsEsiClient{ctrl: ctrl}
+	mock.recorder = &MockAffiliationsEsiClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAffiliationsEsiClient) EXPECT() *MockAffiliationsEsiClientMockRecorder {
+	return m.recorder
+}
+
+// GetCharacterAffiliations mocks base method.
+func (m *MockAffiliationsEsiClient) GetCharacterAffiliations(arg0 context.Context, arg1 []int64) ([]*models.CharacterAffiliation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCharacterAffiliations", arg0, arg1)
+	ret0, _ := ret[0].([]*models.CharacterAffiliation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCharacterAffiliations indicates an expected call of GetCharacterAffiliations.
+func (mr *MockAffiliationsEsiClientMockRecorder) GetCharacterAffiliations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCharacterAffiliations", reflect.TypeOf((*MockAffiliationsEsiClient)(nil).GetCharacterAffiliations), arg0, arg1)
+}
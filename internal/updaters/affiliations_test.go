@@ -0,0 +1,66 @@
+package updaters_test
+
+//go:generate mockgen -destination=affiliations_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters CharacterAffiliationsRepository,AffiliationsEsiClient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_AffiliationsShouldRefreshAllCharacters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockCharacterAffiliationsRepository(ctrl)
+	mockEsi := NewMockAffiliationsEsiClient(ctrl)
+
+	allianceID := int64(99000001)
+	affiliations := []*models.CharacterAffiliation{
+		{CharacterID: 1001, CorporationID: 98000001, AllianceID: &allianceID},
+		{CharacterID: 1002, CorporationID: 98000002},
+	}
+
+	mockRepo.EXPECT().GetCharacterIDs(gomock.Any()).Return([]int64{1001, 1002}, nil)
+	mockEsi.EXPECT().GetCharacterAffiliations(gomock.Any(), []int64{1001, 1002}).Return(affiliations, nil)
+	mockRepo.EXPECT().Upsert(gomock.Any(), affiliations).Return(nil)
+
+	updater := updaters.NewAffiliations(mockRepo, mockEsi)
+	err := updater.UpdateAffiliations(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_AffiliationsShouldSkipEsiWithoutCharacters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockCharacterAffiliationsRepository(ctrl)
+	mockEsi := NewMockAffiliationsEsiClient(ctrl)
+
+	mockRepo.EXPECT().GetCharacterIDs(gomock.Any()).Return([]int64{}, nil)
+
+	updater := updaters.NewAffiliations(mockRepo, mockEsi)
+	err := updater.UpdateAffiliations(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_AffiliationsShouldReturnErrorWhenEsiFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockCharacterAffiliationsRepository(ctrl)
+	mockEsi := NewMockAffiliationsEsiClient(ctrl)
+
+	mockRepo.EXPECT().GetCharacterIDs(gomock.Any()).Return([]int64{1001}, nil)
+	mockEsi.EXPECT().GetCharacterAffiliations(gomock.Any(), []int64{1001}).Return(nil, errors.New("esi down"))
+
+	updater := updaters.NewAffiliations(mockRepo, mockEsi)
+	err := updater.UpdateAffiliations(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get character affiliations from ESI")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	log "github.com/annymsMthd/industry-tool/internal/logging"
 	"github.com/annymsMthd/industry-tool/internal/models"
 	"github.com/annymsMthd/industry-tool/internal/repositories"
 
@@ -42,6 +43,9 @@ type EsiClient interface {
 	GetCorporationDivisions(ctx context.Context, corpID int64, token, refresh string, expire time.Time) (*models.CorporationDivisions, error)
 }
 
+// AssetSyncHook runs after a user's assets have been refreshed from ESI
+type AssetSyncHook func(ctx context.Context, userID int64) error
+
 type Assets struct {
 	characterRepository               CharacterRepository
 	characterAssetsRepository         CharacterAssetsRepository
@@ -49,6 +53,7 @@ type Assets struct {
 	playerCorporationsRepository      PlayerCorporationRepository
 	playerCorporationAssetsRepository PlayerCorporationAssetsRepository
 	esiClient                         EsiClient
+	syncHooks                         []AssetSyncHook
 }
 
 func NewAssets(
@@ -68,6 +73,12 @@ func NewAssets(
 	}
 }
 
+// WithSyncHook registers a hook to run after every successful asset sync
+func (u *Assets) WithSyncHook(hook AssetSyncHook) *Assets {
+	u.syncHooks = append(u.syncHooks, hook)
+	return u
+}
+
 func (u *Assets) UpdateUserAssets(ctx context.Context, userID int64) error {
 	characters, err := u.characterRepository.GetAll(ctx, userID)
 	if err != nil {
@@ -173,5 +184,12 @@ func (u *Assets) UpdateUserAssets(ctx context.Context, userID int64) error {
 		}
 	}
 
+	// Assets are already stored at this point, so a failing hook shouldn't fail the sync
+	for _, hook := range u.syncHooks {
+		if err := hook(ctx, userID); err != nil {
+			log.Error("asset sync hook failed", "user_id", userID, "error", err)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,160 @@
+package updaters
+
+import (
+	"context"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type BuyOrderMatchPermissions interface {
+	GetUserPermissionsForService(ctx context.Context, viewerUserID int64, serviceType string) ([]int64, error)
+	CheckPermission(ctx context.Context, grantingUserID, receivingUserID int64, serviceType string) (bool, error)
+}
+
+type BuyOrderMatchListings interface {
+	GetBrowsableItems(ctx context.Context, buyerUserID int64, sellerUserIDs []int64) ([]*models.ForSaleItem, error)
+}
+
+type BuyOrderMatchOrders interface {
+	GetActiveByBuyers(ctx context.Context, buyerUserIDs []int64) ([]*models.BuyOrder, error)
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event *events.Event)
+}
+
+// BuyOrderMatches tells buyers and sellers when a new or changed buy order or listing can be
+// filled by the other side of the marketplace
+type BuyOrderMatches struct {
+	permissions BuyOrderMatchPermissions
+	listings    BuyOrderMatchListings
+	orders      BuyOrderMatchOrders
+	publisher   EventPublisher
+}
+
+func NewBuyOrderMatches(permissions BuyOrderMatchPermissions, listings BuyOrderMatchListings, orders BuyOrderMatchOrders, publisher EventPublisher) *BuyOrderMatches {
+	return &BuyOrderMatches{
+		permissions: permissions,
+		listings:    listings,
+		orders:      orders,
+		publisher:   publisher,
+	}
+}
+
+// Handle is subscribed to the event bus and looks for matches on buy order and listing changes
+func (u *BuyOrderMatches) Handle(ctx context.Context, event *events.Event) error {
+	switch event.Type {
+	case events.BuyOrderCreated, events.BuyOrderUpdated:
+		order, ok := event.Data.(*models.BuyOrder)
+		if !ok || !order.IsActive {
+			return nil
+		}
+		return u.matchBuyOrder(ctx, order)
+	case events.ListingCreated, events.ListingUpdated:
+		listing, ok := event.Data.(*models.ForSaleItem)
+		if !ok || !listing.IsActive || listing.QuantityAvailable <= 0 {
+			return nil
+		}
+		return u.matchListing(ctx, listing)
+	}
+	return nil
+}
+
+// matchBuyOrder tells the buyer about listings they can browse that fill the order
+func (u *BuyOrderMatches) matchBuyOrder(ctx context.Context, order *models.BuyOrder) error {
+	sellerIDs, err := u.permissions.GetUserPermissionsForService(ctx, order.BuyerUserID, models.ServiceForSaleBrowse)
+	if err != nil {
+		return errors.Wrap(err, "failed to get sellers visible to buyer")
+	}
+	if len(sellerIDs) == 0 {
+		return nil
+	}
+
+	items, err := u.listings.GetBrowsableItems(ctx, order.BuyerUserID, sellerIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to get browsable listings")
+	}
+
+	matched := []*models.ForSaleItem{}
+	for _, item := range items {
+		if buyOrderMatchesListing(order, item) {
+			matched = append(matched, item)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	u.publisher.Publish(ctx, events.New(events.BuyOrderMatched, order.BuyerUserID, &models.BuyOrderMatch{
+		BuyOrders: []*models.BuyOrder{order},
+		Listings:  matched,
+	}))
+	return nil
+}
+
+// matchListing tells the seller about buy orders the listing fills, and tells each buyer who
+// can browse the seller's listings about the listing
+func (u *BuyOrderMatches) matchListing(ctx context.Context, listing *models.ForSaleItem) error {
+	buyerIDs, err := u.permissions.GetUserPermissionsForService(ctx, listing.UserID, models.ServiceBuyOrderBrowse)
+	if err != nil {
+		return errors.Wrap(err, "failed to get buyers visible to seller")
+	}
+	if len(buyerIDs) == 0 {
+		return nil
+	}
+
+	orders, err := u.orders.GetActiveByBuyers(ctx, buyerIDs)
+	if err != nil {
+		return errors.Wrap(err, "failed to get active buy orders")
+	}
+
+	matched := []*models.BuyOrder{}
+	byBuyer := map[int64][]*models.BuyOrder{}
+	buyers := []int64{}
+	for _, order := range orders {
+		if !buyOrderMatchesListing(order, listing) {
+			continue
+		}
+		matched = append(matched, order)
+		if _, ok := byBuyer[order.BuyerUserID]; !ok {
+			buyers = append(buyers, order.BuyerUserID)
+		}
+		byBuyer[order.BuyerUserID] = append(byBuyer[order.BuyerUserID], order)
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	listings := []*models.ForSaleItem{listing}
+	u.publisher.Publish(ctx, events.New(events.BuyOrderMatched, listing.UserID, &models.BuyOrderMatch{
+		BuyOrders: matched,
+		Listings:  listings,
+	}))
+
+	for _, buyerID := range buyers {
+		canBrowse, err := u.permissions.CheckPermission(ctx, listing.UserID, buyerID, models.ServiceForSaleBrowse)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check whether user %d can browse the listing", buyerID)
+		}
+		if !canBrowse {
+			continue
+		}
+		u.publisher.Publish(ctx, events.New(events.BuyOrderMatched, buyerID, &models.BuyOrderMatch{
+			BuyOrders: byBuyer[buyerID],
+			Listings:  listings,
+		}))
+	}
+
+	return nil
+}
+
+func buyOrderMatchesListing(order *models.BuyOrder, listing *models.ForSaleItem) bool {
+	return order.IsActive &&
+		listing.IsActive &&
+		order.BuyerUserID != listing.UserID &&
+		order.TypeID == listing.TypeID &&
+		listing.QuantityAvailable > 0 &&
+		listing.PricePerUnit <= order.MaxPricePerUnit
+}
@@ -0,0 +1,178 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: BuyOrderMatchPermissions,BuyOrderMatchListings,BuyOrderMatchOrders,EventPublisher)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	events "github.com/annymsMthd/industry-tool/internal/events"
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBuyOrderMatchPermissions is a mock of BuyOrderMatchPermissions interface.
+type MockBuyOrderMatchPermissions struct {
+	ctrl     *gomock.Controller
+	recorder *MockBuyOrderMatchPermissionsMockRecorder
+}
+
+// MockBuyOrderMatchPermissionsMockRecorder is the mock recorder for MockBuyOrderMatchPermissions.
+type MockBuyOrderMatchPermissionsMockRecorder struct {
+	mock *MockBuyOrderMatchPermissions
+}
+
+// NewMockBuyOrderMatchPermissions creates a new mock instance.
+func NewMockBuyOrderMatchPermissions(ctrl *gomock.Controller) *MockBuyOrderMatchPermissions {
+	mock := &MockBuyOrderMatchPermissions{ctrl: ctrl}
+	mock.recorder = &MockBuyOrderMatchPermissionsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBuyOrderMatchPermissions) EXPECT() *MockBuyOrderMatchPermissionsMockRecorder {
+	return m.recorder
+}
+
+// CheckPermission mocks base method.
+func (m *MockBuyOrderMatchPermissions) CheckPermission(arg0 context.Context, arg1, arg2 int64, arg3 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckPermission", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckPermission indicates an expected call of CheckPermission.
+func (mr *MockBuyOrderMatchPermissionsMockRecorder) CheckPermission(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckPermission", reflect.TypeOf((*MockBuyOrderMatchPermissions)(nil).CheckPermission), arg0, arg1, arg2, arg3)
+}
+
+// GetUserPermissionsForService mocks base method.
+func (m *MockBuyOrderMatchPermissions) GetUserPermissionsForService(arg0 context.Context, arg1 int64, arg2 string) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissionsForService", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissionsForService indicates an expected call of GetUserPermissionsForService.
+func (mr *MockBuyOrderMatchPermissionsMockRecorder) GetUserPermissionsForService(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissionsForService", reflect.TypeOf((*MockBuyOrderMatchPermissions)(nil).GetUserPermissionsForService), arg0, arg1, arg2)
+}
+
+// MockBuyOrderMatchListings is a mock of BuyOrderMatchListings interface.
+type MockBuyOrderMatchListings struct {
+	ctrl     *gomock.Controller
+	recorder *MockBuyOrderMatchListingsMockRecorder
+}
+
+// MockBuyOrderMatchListingsMockRecorder is the mock recorder for MockBuyOrderMatchListings.
+type MockBuyOrderMatchListingsMockRecorder struct {
+	mock *MockBuyOrderMatchListings
+}
+
+// NewMockBuyOrderMatchListings creates a new mock instance.
+func NewMockBuyOrderMatchListings(ctrl *gomock.Controller) *MockBuyOrderMatchListings {
+	mock := &MockBuyOrderMatchListings{ctrl: ctrl}
+	mock.recorder = &MockBuyOrderMatchListingsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBuyOrderMatchListings) EXPECT() *MockBuyOrderMatchListingsMockRecorder {
+	return m.recorder
+}
+
+// GetBrowsableItems mocks base method.
+func (m *MockBuyOrderMatchListings) GetBrowsableItems(arg0 context.Context, arg1 int64, arg2 []int64) ([]*models.ForSaleItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBrowsableItems", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*models.ForSaleItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBrowsableItems indicates an expected call of GetBrowsableItems.
+func (mr *MockBuyOrderMatchListingsMockRecorder) GetBrowsableItems(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBrowsableItems", reflect.TypeOf((*MockBuyOrderMatchListings)(nil).GetBrowsableItems), arg0, arg1, arg2)
+}
+
+// MockBuyOrderMatchOrders is a mock of BuyOrderMatchOrders interface.
+type MockBuyOrderMatchOrders struct {
+	ctrl     *gomock.Controller
+	recorder *MockBuyOrderMatchOrdersMockRecorder
+}
+
+// MockBuyOrderMatchOrdersMockRecorder is the mock recorder for MockBuyOrderMatchOrders.
+type MockBuyOrderMatchOrdersMockRecorder struct {
+	mock *MockBuyOrderMatchOrders
+}
+
+// NewMockBuyOrderMatchOrders creates a new mock instance.
+func NewMockBuyOrderMatchOrders(ctrl *gomock.Controller) *MockBuyOrderMatchOrders {
+	mock := &MockBuyOrderMatchOrders{ctrl: ctrl}
+	mock.recorder = &MockBuyOrderMatchOrdersMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBuyOrderMatchOrders) EXPECT() *MockBuyOrderMatchOrdersMockRecorder {
+	return m.recorder
+}
+
+// GetActiveByBuyers mocks base method.
+func (m *MockBuyOrderMatchOrders) GetActiveByBuyers(arg0 context.Context, arg1 []int64) ([]*models.BuyOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveByBuyers", arg0, arg1)
+	ret0, _ := ret[0].([]*models.BuyOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveByBuyers indicates an expected call of GetActiveByBuyers.
+func (mr *MockBuyOrderMatchOrdersMockRecorder) GetActiveByBuyers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveByBuyers", reflect.TypeOf((*MockBuyOrderMatchOrders)(nil).GetActiveByBuyers), arg0, arg1)
+}
+
+// MockEventPublisher is a mock of EventPublisher interface.
+type MockEventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventPublisherMockRecorder
+}
+
+// MockEventPublisherMockRecorder is the mock recorder for MockEventPublisher.
+type MockEventPublisherMockRecorder struct {
+	mock *MockEventPublisher
+}
+
+// NewMockEventPublisher creates a new mock instance.
+func NewMockEventPublisher(ctrl *gomock.Controller) *MockEventPublisher {
+	mock := &MockEventPublisher{ctrl: ctrl}
+	mock.recorder = &MockEventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventPublisher) EXPECT() *MockEventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockEventPublisher) Publish(arg0 context.Context, arg1 *events.Event) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Publish", arg0, arg1)
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockEventPublisherMockRecorder) Publish(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockEventPublisher)(nil).Publish), arg0, arg1)
+}
@@ -0,0 +1,129 @@
+package updaters_test
+
+//go:generate mockgen -destination=buyOrderMatches_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters BuyOrderMatchPermissions,BuyOrderMatchListings,BuyOrderMatchOrders,EventPublisher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuyOrderMatchesShouldTellBuyerAboutMatchingListings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPermissions := NewMockBuyOrderMatchPermissions(ctrl)
+	mockListings := NewMockBuyOrderMatchListings(ctrl)
+	mockOrders := NewMockBuyOrderMatchOrders(ctrl)
+	mockPublisher := NewMockEventPublisher(ctrl)
+
+	order := &models.BuyOrder{ID: 1, BuyerUserID: 10, TypeID: 34, QuantityDesired: 1000, MaxPricePerUnit: 6, IsActive: true}
+	cheap := &models.ForSaleItem{ID: 100, UserID: 20, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 5, IsActive: true}
+	exact := &models.ForSaleItem{ID: 101, UserID: 30, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 6, IsActive: true}
+	pricey := &models.ForSaleItem{ID: 102, UserID: 20, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 7, IsActive: true}
+	otherType := &models.ForSaleItem{ID: 103, UserID: 20, TypeID: 35, QuantityAvailable: 500, PricePerUnit: 1, IsActive: true}
+
+	mockPermissions.EXPECT().GetUserPermissionsForService(gomock.Any(), int64(10), models.ServiceForSaleBrowse).Return([]int64{20, 30}, nil)
+	mockListings.EXPECT().GetBrowsableItems(gomock.Any(), int64(10), []int64{20, 30}).Return([]*models.ForSaleItem{cheap, exact, pricey, otherType}, nil)
+
+	var published *events.Event
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, event *events.Event) {
+		published = event
+	})
+
+	u := updaters.NewBuyOrderMatches(mockPermissions, mockListings, mockOrders, mockPublisher)
+	err := u.Handle(context.Background(), events.New(events.BuyOrderCreated, 10, order))
+	require.NoError(t, err)
+
+	require.NotNil(t, published)
+	assert.Equal(t, events.BuyOrderMatched, published.Type)
+	assert.Equal(t, int64(10), published.UserID)
+	match := published.Data.(*models.BuyOrderMatch)
+	assert.Equal(t, []*models.BuyOrder{order}, match.BuyOrders)
+	assert.Equal(t, []*models.ForSaleItem{cheap, exact}, match.Listings)
+}
+
+func Test_BuyOrderMatchesShouldStayQuietWithoutMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPermissions := NewMockBuyOrderMatchPermissions(ctrl)
+	mockListings := NewMockBuyOrderMatchListings(ctrl)
+	mockOrders := NewMockBuyOrderMatchOrders(ctrl)
+	mockPublisher := NewMockEventPublisher(ctrl)
+
+	order := &models.BuyOrder{ID: 1, BuyerUserID: 10, TypeID: 34, MaxPricePerUnit: 4, IsActive: true}
+
+	mockPermissions.EXPECT().GetUserPermissionsForService(gomock.Any(), int64(10), models.ServiceForSaleBrowse).Return([]int64{20}, nil)
+	mockListings.EXPECT().GetBrowsableItems(gomock.Any(), int64(10), []int64{20}).Return([]*models.ForSaleItem{
+		{ID: 100, UserID: 20, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 5, IsActive: true},
+	}, nil)
+
+	u := updaters.NewBuyOrderMatches(mockPermissions, mockListings, mockOrders, mockPublisher)
+	require.NoError(t, u.Handle(context.Background(), events.New(events.BuyOrderCreated, 10, order)))
+
+	// Inactive orders and unrelated events aren't looked at
+	inactive := &models.BuyOrder{ID: 2, BuyerUserID: 10, TypeID: 34, MaxPricePerUnit: 100}
+	require.NoError(t, u.Handle(context.Background(), events.New(events.BuyOrderUpdated, 10, inactive)))
+	require.NoError(t, u.Handle(context.Background(), events.New(events.PurchaseCreated, 10, &models.PurchaseTransaction{})))
+}
+
+func Test_BuyOrderMatchesShouldTellSellerAndBuyersAboutMatchingOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPermissions := NewMockBuyOrderMatchPermissions(ctrl)
+	mockListings := NewMockBuyOrderMatchListings(ctrl)
+	mockOrders := NewMockBuyOrderMatchOrders(ctrl)
+	mockPublisher := NewMockEventPublisher(ctrl)
+
+	listing := &models.ForSaleItem{ID: 100, UserID: 20, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 5, IsActive: true}
+	buyer10 := &models.BuyOrder{ID: 1, BuyerUserID: 10, TypeID: 34, MaxPricePerUnit: 5, IsActive: true}
+	buyer11 := &models.BuyOrder{ID: 2, BuyerUserID: 11, TypeID: 34, MaxPricePerUnit: 9, IsActive: true}
+	tooLow := &models.BuyOrder{ID: 3, BuyerUserID: 12, TypeID: 34, MaxPricePerUnit: 4, IsActive: true}
+
+	mockPermissions.EXPECT().GetUserPermissionsForService(gomock.Any(), int64(20), models.ServiceBuyOrderBrowse).Return([]int64{10, 11, 12}, nil)
+	mockOrders.EXPECT().GetActiveByBuyers(gomock.Any(), []int64{10, 11, 12}).Return([]*models.BuyOrder{buyer10, buyer11, tooLow}, nil)
+	mockPermissions.EXPECT().CheckPermission(gomock.Any(), int64(20), int64(10), models.ServiceForSaleBrowse).Return(true, nil)
+	mockPermissions.EXPECT().CheckPermission(gomock.Any(), int64(20), int64(11), models.ServiceForSaleBrowse).Return(false, nil)
+
+	published := map[int64]*models.BuyOrderMatch{}
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).Times(2).Do(func(ctx context.Context, event *events.Event) {
+		assert.Equal(t, events.BuyOrderMatched, event.Type)
+		published[event.UserID] = event.Data.(*models.BuyOrderMatch)
+	})
+
+	u := updaters.NewBuyOrderMatches(mockPermissions, mockListings, mockOrders, mockPublisher)
+	err := u.Handle(context.Background(), events.New(events.ListingCreated, 20, listing))
+	require.NoError(t, err)
+
+	require.Len(t, published, 2)
+	assert.Equal(t, []*models.BuyOrder{buyer10, buyer11}, published[20].BuyOrders)
+	assert.Equal(t, []*models.ForSaleItem{listing}, published[20].Listings)
+	// Buyer 11 can't browse the seller's listings, so only buyer 10 hears about it
+	assert.Equal(t, []*models.BuyOrder{buyer10}, published[10].BuyOrders)
+}
+
+func Test_BuyOrderMatchesShouldReturnPermissionErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPermissions := NewMockBuyOrderMatchPermissions(ctrl)
+	mockListings := NewMockBuyOrderMatchListings(ctrl)
+	mockOrders := NewMockBuyOrderMatchOrders(ctrl)
+	mockPublisher := NewMockEventPublisher(ctrl)
+
+	listing := &models.ForSaleItem{ID: 100, UserID: 20, TypeID: 34, QuantityAvailable: 500, PricePerUnit: 5, IsActive: true}
+	mockPermissions.EXPECT().GetUserPermissionsForService(gomock.Any(), int64(20), models.ServiceBuyOrderBrowse).Return(nil, errors.New("db down"))
+
+	u := updaters.NewBuyOrderMatches(mockPermissions, mockListings, mockOrders, mockPublisher)
+	err := u.Handle(context.Background(), events.New(events.ListingUpdated, 20, listing))
+	assert.ErrorContains(t, err, "db down")
+}
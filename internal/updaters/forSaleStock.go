@@ -0,0 +1,67 @@
+package updaters
+
+import (
+	"context"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type ForSaleStockRepository interface {
+	GetStockLevels(ctx context.Context, userID int64) ([]*models.ForSaleStockLevel, error)
+	ApplyStockLevel(ctx context.Context, itemID int64, heldQuantity int64, newQuantity int64, isActive bool) error
+}
+
+type ForSaleStock struct {
+	repository ForSaleStockRepository
+}
+
+func NewForSaleStock(repository ForSaleStockRepository) *ForSaleStock {
+	return &ForSaleStock{
+		repository: repository,
+	}
+}
+
+// ReconcileUserListings checks a user's active listings against their synced assets.
+// Listings promising more than is held (less pending reservations) are reduced to what
+// is left, and listings with nothing left at the location are deactivated. A listing
+// whose held stock is fully reserved stays up, capped at what is still held.
+func (u *ForSaleStock) ReconcileUserListings(ctx context.Context, userID int64) error {
+	levels, err := u.repository.GetStockLevels(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get for-sale stock levels")
+	}
+
+	reduced := 0
+	deactivated := 0
+	for _, level := range levels {
+		available := level.HeldQuantity - level.ReservedQuantity
+		if available <= 0 && level.ReservedQuantity > 0 {
+			// Reserved units count as still held: once they are handed over in contracts
+			// the hangar only holds the rest, so it all backs the listing
+			available = level.HeldQuantity
+		}
+
+		newQuantity := level.QuantityAvailable
+		isActive := true
+		if available <= 0 {
+			isActive = false
+			deactivated++
+		} else if level.QuantityAvailable > available {
+			newQuantity = available
+			reduced++
+		}
+
+		err = u.repository.ApplyStockLevel(ctx, level.ForSaleItemID, level.HeldQuantity, newQuantity, isActive)
+		if err != nil {
+			return errors.Wrapf(err, "failed to apply stock level for listing %d", level.ForSaleItemID)
+		}
+	}
+
+	if reduced > 0 || deactivated > 0 {
+		log.Info("reconciled for-sale listings with assets", "user_id", userID, "reduced", reduced, "deactivated", deactivated)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: ForSaleStockRepository)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockForSaleStockRepository is a mock of ForSaleStockRepository interface.
+type MockForSaleStockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockForSaleStockRepositoryMockRecorder
+}
+
+// MockForSaleStockRepositoryMockRecorder is the mock recorder for MockForSaleStockRepository.
+type MockForSaleStockRepositoryMockRecorder struct {
+	mock *MockForSaleStockRepository
+}
+
+// NewMockForSaleStockRepository creates a new mock instance.
+func NewMockForSaleStockRepository(ctrl *gomock.Controller) *MockForSaleStockRepository {
+	mock := &MockForSaleStockRepository{ctrl: ctrl}
+	mock.recorder = &MockForSaleStockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForSaleStockRepository) EXPECT() *MockForSaleStockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApplyStockLevel mocks base method.
+func (m *MockForSaleStockRepository) ApplyStockLevel(arg0 context.Context, arg1, arg2, arg3 int64, arg4 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyStockLevel", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplyStockLevel indicates an expected call of ApplyStockLevel.
+func (mr *MockForSaleStockRepositoryMockRecorder) ApplyStockLevel(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyStockLevel", reflect.TypeOf((*MockForSaleStockRepository)(nil).ApplyStockLevel), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetStockLevels mocks base method.
+func (m *MockForSaleStockRepository) GetStockLevels(arg0 context.Context, arg1 int64) ([]*models.ForSaleStockLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStockLevels", arg0, arg1)
+	ret0, _ := ret[0].([]*models.ForSaleStockLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStockLevels indicates an expected call of GetStockLevels.
+func (mr *MockForSaleStockRepositoryMockRecorder) GetStockLevels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStockLevels", reflect.TypeOf((*MockForSaleStockRepository)(nil).GetStockLevels), arg0, arg1)
+}
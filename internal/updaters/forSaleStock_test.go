@@ -0,0 +1,85 @@
+package updaters_test
+
+//go:generate mockgen -destination=forSaleStock_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters ForSaleStockRepository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ForSaleStockShouldReconcileListings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockForSaleStockRepository(ctrl)
+
+	userID := int64(42)
+	levels := []*models.ForSaleStockLevel{
+		// Fully backed by stock - untouched
+		{ForSaleItemID: 1, QuantityAvailable: 100, HeldQuantity: 500, ReservedQuantity: 0},
+		// Sold elsewhere - reduce to what is left
+		{ForSaleItemID: 2, QuantityAvailable: 100, HeldQuantity: 60, ReservedQuantity: 0},
+		// Pending reservations still sitting in the hangar count against the listing
+		{ForSaleItemID: 3, QuantityAvailable: 100, HeldQuantity: 120, ReservedQuantity: 50},
+		// Items have left the location - deactivate
+		{ForSaleItemID: 4, QuantityAvailable: 100, HeldQuantity: 0, ReservedQuantity: 0},
+		// Everything held is already reserved - reserved stock still counts as held
+		{ForSaleItemID: 5, QuantityAvailable: 10, HeldQuantity: 30, ReservedQuantity: 30},
+		// Reserved units already handed over - cap at what is left in the hangar
+		{ForSaleItemID: 6, QuantityAvailable: 50, HeldQuantity: 20, ReservedQuantity: 40},
+		// Nothing left at all, even with reservations - deactivate
+		{ForSaleItemID: 7, QuantityAvailable: 10, HeldQuantity: 0, ReservedQuantity: 40},
+	}
+
+	mockRepo.EXPECT().GetStockLevels(gomock.Any(), userID).Return(levels, nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(1), int64(500), int64(100), true).Return(nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(2), int64(60), int64(60), true).Return(nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(3), int64(120), int64(70), true).Return(nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(4), int64(0), int64(100), false).Return(nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(5), int64(30), int64(10), true).Return(nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(6), int64(20), int64(20), true).Return(nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(7), int64(0), int64(10), false).Return(nil)
+
+	updater := updaters.NewForSaleStock(mockRepo)
+	err := updater.ReconcileUserListings(context.Background(), userID)
+	assert.NoError(t, err)
+}
+
+func Test_ForSaleStockShouldReturnErrorWhenStockLevelsFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockForSaleStockRepository(ctrl)
+
+	mockRepo.EXPECT().GetStockLevels(gomock.Any(), int64(42)).Return(nil, errors.New("database error"))
+
+	updater := updaters.NewForSaleStock(mockRepo)
+	err := updater.ReconcileUserListings(context.Background(), 42)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get for-sale stock levels")
+}
+
+func Test_ForSaleStockShouldReturnErrorWhenApplyFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockForSaleStockRepository(ctrl)
+
+	levels := []*models.ForSaleStockLevel{
+		{ForSaleItemID: 7, QuantityAvailable: 10, HeldQuantity: 5, ReservedQuantity: 0},
+	}
+
+	mockRepo.EXPECT().GetStockLevels(gomock.Any(), int64(42)).Return(levels, nil)
+	mockRepo.EXPECT().ApplyStockLevel(gomock.Any(), int64(7), int64(5), int64(5), true).Return(errors.New("database error"))
+
+	updater := updaters.NewForSaleStock(mockRepo)
+	err := updater.ReconcileUserListings(context.Background(), 42)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "listing 7")
+}
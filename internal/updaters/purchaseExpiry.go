@@ -0,0 +1,117 @@
+package updaters
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/repositories"
+	"github.com/pkg/errors"
+)
+
+type PurchaseExpiryRepository interface {
+	GetStalePurchases(ctx context.Context, defaultTimeoutHours int) ([]*models.PurchaseTransaction, error)
+	CancelWithReason(ctx context.Context, tx *sql.Tx, purchaseID int64, reason string) error
+}
+
+type PurchaseExpiryForSaleRepository interface {
+	RestoreQuantity(ctx context.Context, tx *sql.Tx, itemID int64, quantity int64) (bool, error)
+}
+
+type PurchaseExpiry struct {
+	db                  *sql.DB
+	purchasesRepo       PurchaseExpiryRepository
+	forSaleRepo         PurchaseExpiryForSaleRepository
+	defaultTimeoutHours int
+	publisher           EventPublisher
+}
+
+func NewPurchaseExpiry(db *sql.DB, purchasesRepo PurchaseExpiryRepository, forSaleRepo PurchaseExpiryForSaleRepository, defaultTimeoutHours int) *PurchaseExpiry {
+	return &PurchaseExpiry{
+		db:                  db,
+		purchasesRepo:       purchasesRepo,
+		forSaleRepo:         forSaleRepo,
+		defaultTimeoutHours: defaultTimeoutHours,
+	}
+}
+
+// WithEvents publishes a purchase.cancelled event to both parties of each expired purchase
+func (u *PurchaseExpiry) WithEvents(publisher EventPublisher) *PurchaseExpiry {
+	u.publisher = publisher
+	return u
+}
+
+// ExpireStalePurchases cancels open purchases that have outlived their seller's
+// timeout and returns the reserved quantity to the listing
+func (u *PurchaseExpiry) ExpireStalePurchases(ctx context.Context) error {
+	purchases, err := u.purchasesRepo.GetStalePurchases(ctx, u.defaultTimeoutHours)
+	if err != nil {
+		return errors.Wrap(err, "failed to get stale purchases")
+	}
+
+	expired := 0
+	for _, purchase := range purchases {
+		err = u.expirePurchase(ctx, purchase)
+		if errors.Is(err, repositories.ErrPurchaseNotOpen) {
+			// Completed or cancelled by a participant since it was read
+			continue
+		}
+		if err != nil {
+			log.Error("failed to expire purchase", "purchase_id", purchase.ID, "error", err)
+			continue
+		}
+		expired++
+
+		if u.publisher != nil {
+			u.publisher.Publish(ctx, events.New(events.PurchaseCancelled, purchase.BuyerUserID, purchase))
+			u.publisher.Publish(ctx, events.New(events.PurchaseCancelled, purchase.SellerUserID, purchase))
+		}
+	}
+
+	if expired > 0 {
+		log.Info("expired stale purchases", "count", expired)
+	}
+
+	return nil
+}
+
+func (u *PurchaseExpiry) expirePurchase(ctx context.Context, purchase *models.PurchaseTransaction) error {
+	tx, err := u.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback()
+
+	// Cancel first, as CancelPurchase does, so both paths lock the purchase before the listing
+	// and a purchase closed in the meantime can't restore quantity twice
+	reason := fmt.Sprintf("expired: still %s after the seller's timeout", purchase.Status)
+	err = u.purchasesRepo.CancelWithReason(ctx, tx, purchase.ID, reason)
+	if err != nil {
+		return errors.Wrap(err, "failed to cancel purchase")
+	}
+
+	// Return the reserved quantity, bringing back listings the reservation sold out. Listings
+	// the seller removed or replaced in the meantime are left alone.
+	restored, err := u.forSaleRepo.RestoreQuantity(ctx, tx, purchase.ForSaleItemID, purchase.QuantityPurchased)
+	if err != nil {
+		return errors.Wrap(err, "failed to restore quantity")
+	}
+	if !restored {
+		log.Info("listing removed or replaced, quantity not restored", "purchase_id", purchase.ID, "for_sale_item_id", purchase.ForSaleItemID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "failed to commit transaction")
+	}
+
+	now := time.Now()
+	purchase.Status = "cancelled"
+	purchase.CancellationReason = &reason
+	purchase.CancelledAt = &now
+
+	return nil
+}
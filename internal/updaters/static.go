@@ -14,12 +14,25 @@ type FuzzWorksClient interface {
 	GetConstellations(ctx context.Context) ([]models.Constellation, error)
 	GetSolarSystems(ctx context.Context) ([]models.SolarSystem, error)
 	GetNPCStations(ctx context.Context) ([]models.Station, error)
+	GetItemCategories(ctx context.Context) ([]models.ItemCategory, error)
+	GetItemGroups(ctx context.Context) ([]models.ItemGroup, error)
+	GetMarketGroups(ctx context.Context) ([]models.MarketGroup, error)
+	GetMetaGroups(ctx context.Context) ([]models.MetaGroup, error)
+	GetMetaTypes(ctx context.Context) (map[int64]models.ItemMetaType, error)
+	GetPackagedVolumes(ctx context.Context) (map[int64]float64, error)
 }
 
 type ItemTypeRepository interface {
 	UpsertItemTypes(ctx context.Context, itemTypes []models.EveInventoryType) error
 }
 
+type ItemMetadataRepository interface {
+	UpsertCategories(ctx context.Context, categories []models.ItemCategory) error
+	UpsertGroups(ctx context.Context, groups []models.ItemGroup) error
+	UpsertMarketGroups(ctx context.Context, marketGroups []models.MarketGroup) error
+	UpsertMetaGroups(ctx context.Context, metaGroups []models.MetaGroup) error
+}
+
 type RegionRepository interface {
 	Upsert(ctx context.Context, regions []models.Region) error
 }
@@ -39,6 +52,7 @@ type StationRepository interface {
 type Static struct {
 	client                  FuzzWorksClient
 	itemTypeRepository      ItemTypeRepository
+	itemMetadataRepository  ItemMetadataRepository
 	regionRepository        RegionRepository
 	constellationRepository ConstellationRepository
 	solarSystemRepository   SolarSystemRepository
@@ -48,6 +62,7 @@ type Static struct {
 func NewStatic(
 	client FuzzWorksClient,
 	itemTypeRepository ItemTypeRepository,
+	itemMetadataRepository ItemMetadataRepository,
 	regionRepository RegionRepository,
 	constellationRepository ConstellationRepository,
 	solarSystemRepository SolarSystemRepository,
@@ -55,6 +70,7 @@ func NewStatic(
 	return &Static{
 		client:                  client,
 		itemTypeRepository:      itemTypeRepository,
+		itemMetadataRepository:  itemMetadataRepository,
 		regionRepository:        regionRepository,
 		constellationRepository: constellationRepository,
 		solarSystemRepository:   solarSystemRepository,
@@ -103,11 +119,37 @@ func (u *Static) Update(ctx context.Context) error {
 		return errors.Wrap(err, "failed to upsert stations to repository")
 	}
 
+	err = u.updateItemMetadata(ctx)
+	if err != nil {
+		return err
+	}
+
 	items, err := u.client.GetInventoryTypes(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to get inventory types from client")
 	}
 
+	metaTypes, err := u.client.GetMetaTypes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get meta types from fuzzworks")
+	}
+
+	packagedVolumes, err := u.client.GetPackagedVolumes(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get packaged volumes from fuzzworks")
+	}
+
+	for i := range items {
+		if metaType, ok := metaTypes[items[i].TypeID]; ok {
+			metaGroupID := metaType.MetaGroupID
+			items[i].MetaGroupID = &metaGroupID
+			items[i].ParentTypeID = metaType.ParentTypeID
+		}
+		if volume, ok := packagedVolumes[items[i].TypeID]; ok {
+			items[i].PackagedVolume = &volume
+		}
+	}
+
 	err = u.itemTypeRepository.UpsertItemTypes(ctx, items)
 	if err != nil {
 		return errors.Wrap(err, "failed to upsert items to itemTypeRepository")
@@ -115,3 +157,49 @@ func (u *Static) Update(ctx context.Context) error {
 
 	return nil
 }
+
+// updateItemMetadata imports the categories, groups, market groups and meta groups item types are
+// filtered by
+func (u *Static) updateItemMetadata(ctx context.Context) error {
+	categories, err := u.client.GetItemCategories(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get item categories from fuzzworks")
+	}
+
+	err = u.itemMetadataRepository.UpsertCategories(ctx, categories)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert item categories to repository")
+	}
+
+	groups, err := u.client.GetItemGroups(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get item groups from fuzzworks")
+	}
+
+	err = u.itemMetadataRepository.UpsertGroups(ctx, groups)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert item groups to repository")
+	}
+
+	marketGroups, err := u.client.GetMarketGroups(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get market groups from fuzzworks")
+	}
+
+	err = u.itemMetadataRepository.UpsertMarketGroups(ctx, marketGroups)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert market groups to repository")
+	}
+
+	metaGroups, err := u.client.GetMetaGroups(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to get meta groups from fuzzworks")
+	}
+
+	err = u.itemMetadataRepository.UpsertMetaGroups(ctx, metaGroups)
+	if err != nil {
+		return errors.Wrap(err, "failed to upsert meta groups to repository")
+	}
+
+	return nil
+}
@@ -0,0 +1,465 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: FuzzWorksClient,ItemTypeRepository,ItemMetadataRepository,RegionRepository,ConstellationRepository,SolarSystemRepository,StationRepository)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockFuzzWorksClient is a mock of FuzzWorksClient interface.
+type MockFuzzWorksClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockFuzzWorksClientMockRecorder
+}
+
+// MockFuzzWorksClientMockRecorder is the mock recorder for MockFuzzWorksClient.
+type MockFuzzWorksClientMockRecorder struct {
+	mock *MockFuzzWorksClient
+}
+
+// NewMockFuzzWorksClient creates a new mock instance.
+func NewMockFuzzWorksClient(ctrl *gomock.Controller) *MockFuzzWorksClient {
+	mock := &MockFuzzWorksClient{ctrl: ctrl}
+	mock.recorder = &MockFuzzWorksClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFuzzWorksClient) EXPECT() *MockFuzzWorksClientMockRecorder {
+	return m.recorder
+}
+
+// GetConstellations mocks base method.
+func (m *MockFuzzWorksClient) GetConstellations(arg0 context.Context) ([]models.Constellation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConstellations", arg0)
+	ret0, _ := ret[0].([]models.Constellation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConstellations indicates an expected call of GetConstellations.
+func (mr *MockFuzzWorksClientMockRecorder) GetConstellations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConstellations", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetConstellations), arg0)
+}
+
+// GetInventoryTypes mocks base method.
+func (m *MockFuzzWorksClient) GetInventoryTypes(arg0 context.Context) ([]models.EveInventoryType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventoryTypes", arg0)
+	ret0, _ := ret[0].([]models.EveInventoryType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventoryTypes indicates an expected call of GetInventoryTypes.
+func (mr *MockFuzzWorksClientMockRecorder) GetInventoryTypes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventoryTypes", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetInventoryTypes), arg0)
+}
+
+// GetItemCategories mocks base method.
+func (m *MockFuzzWorksClient) GetItemCategories(arg0 context.Context) ([]models.ItemCategory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemCategories", arg0)
+	ret0, _ := ret[0].([]models.ItemCategory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemCategories indicates an expected call of GetItemCategories.
+func (mr *MockFuzzWorksClientMockRecorder) GetItemCategories(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemCategories", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetItemCategories), arg0)
+}
+
+// GetItemGroups mocks base method.
+func (m *MockFuzzWorksClient) GetItemGroups(arg0 context.Context) ([]models.ItemGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemGroups", arg0)
+	ret0, _ := ret[0].([]models.ItemGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemGroups indicates an expected call of GetItemGroups.
+func (mr *MockFuzzWorksClientMockRecorder) GetItemGroups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemGroups", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetItemGroups), arg0)
+}
+
+// GetMarketGroups mocks base method.
+func (m *MockFuzzWorksClient) GetMarketGroups(arg0 context.Context) ([]models.MarketGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMarketGroups", arg0)
+	ret0, _ := ret[0].([]models.MarketGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMarketGroups indicates an expected call of GetMarketGroups.
+func (mr *MockFuzzWorksClientMockRecorder) GetMarketGroups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMarketGroups", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetMarketGroups), arg0)
+}
+
+// GetMetaGroups mocks base method.
+func (m *MockFuzzWorksClient) GetMetaGroups(arg0 context.Context) ([]models.MetaGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetaGroups", arg0)
+	ret0, _ := ret[0].([]models.MetaGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetaGroups indicates an expected call of GetMetaGroups.
+func (mr *MockFuzzWorksClientMockRecorder) GetMetaGroups(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetaGroups", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetMetaGroups), arg0)
+}
+
+// GetMetaTypes mocks base method.
+func (m *MockFuzzWorksClient) GetMetaTypes(arg0 context.Context) (map[int64]models.ItemMetaType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMetaTypes", arg0)
+	ret0, _ := ret[0].(map[int64]models.ItemMetaType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMetaTypes indicates an expected call of GetMetaTypes.
+func (mr *MockFuzzWorksClientMockRecorder) GetMetaTypes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetaTypes", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetMetaTypes), arg0)
+}
+
+// GetNPCStations mocks base method.
+func (m *MockFuzzWorksClient) GetNPCStations(arg0 context.Context) ([]models.Station, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNPCStations", arg0)
+	ret0, _ := ret[0].([]models.Station)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNPCStations indicates an expected call of GetNPCStations.
+func (mr *MockFuzzWorksClientMockRecorder) GetNPCStations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNPCStations", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetNPCStations), arg0)
+}
+
+// GetPackagedVolumes mocks base method.
+func (m *MockFuzzWorksClient) GetPackagedVolumes(arg0 context.Context) (map[int64]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPackagedVolumes", arg0)
+	ret0, _ := ret[0].(map[int64]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPackagedVolumes indicates an expected call of GetPackagedVolumes.
+func (mr *MockFuzzWorksClientMockRecorder) GetPackagedVolumes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackagedVolumes", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetPackagedVolumes), arg0)
+}
+
+// GetRegions mocks base method.
+func (m *MockFuzzWorksClient) GetRegions(arg0 context.Context) ([]models.Region, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRegions", arg0)
+	ret0, _ := ret[0].([]models.Region)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRegions indicates an expected call of GetRegions.
+func (mr *MockFuzzWorksClientMockRecorder) GetRegions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegions", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetRegions), arg0)
+}
+
+// GetSolarSystems mocks base method.
+func (m *MockFuzzWorksClient) GetSolarSystems(arg0 context.Context) ([]models.SolarSystem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSolarSystems", arg0)
+	ret0, _ := ret[0].([]models.SolarSystem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSolarSystems indicates an expected call of GetSolarSystems.
+func (mr *MockFuzzWorksClientMockRecorder) GetSolarSystems(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSolarSystems", reflect.TypeOf((*MockFuzzWorksClient)(nil).GetSolarSystems), arg0)
+}
+
+// MockItemTypeRepository is a mock of ItemTypeRepository interface.
+type MockItemTypeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockItemTypeRepositoryMockRecorder
+}
+
+// MockItemTypeRepositoryMockRecorder is the mock recorder for MockItemTypeRepository.
+type MockItemTypeRepositoryMockRecorder struct {
+	mock *MockItemTypeRepository
+}
+
+// NewMockItemTypeRepository creates a new mock instance.
+func NewMockItemTypeRepository(ctrl *gomock.Controller) *MockItemTypeRepository {
+	mock := &MockItemTypeRepository{ctrl: ctrl}
+	mock.recorder = &MockItemTypeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItemTypeRepository) EXPECT() *MockItemTypeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// UpsertItemTypes mocks base method.
+func (m *MockItemTypeRepository) UpsertItemTypes(arg0 context.Context, arg1 []models.EveInventoryType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertItemTypes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertItemTypes indicates an expected call of UpsertItemTypes.
+func (mr *MockItemTypeRepositoryMockRecorder) UpsertItemTypes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertItemTypes", reflect.TypeOf((*MockItemTypeRepository)(nil).UpsertItemTypes), arg0, arg1)
+}
+
+// MockItemMetadataRepository is a mock of ItemMetadataRepository interface.
+type MockItemMetadataRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockItemMetadataRepositoryMockRecorder
+}
+
+// MockItemMetadataRepositoryMockRecorder is the mock recorder for MockItemMetadataRepository.
+type MockItemMetadataRepositoryMockRecorder struct {
+	mock *MockItemMetadataRepository
+}
+
+// NewMockItemMetadataRepository creates a new mock instance.
+func NewMockItemMetadataRepository(ctrl *gomock.Controller) *MockItemMetadataRepository {
+	mock := &MockItemMetadataRepository{ctrl: ctrl}
+	mock.recorder = &MockItemMetadataRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockItemMetadataRepository) EXPECT() *MockItemMetadataRepositoryMockRecorder {
+	return m.recorder
+}
+
+// UpsertCategories mocks base method.
+func (m *MockItemMetadataRepository) UpsertCategories(arg0 context.Context, arg1 []models.ItemCategory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCategories", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertCategories indicates an expected call of UpsertCategories.
+func (mr *MockItemMetadataRepositoryMockRecorder) UpsertCategories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCategories", reflect.TypeOf((*MockItemMetadataRepository)(nil).UpsertCategories), arg0, arg1)
+}
+
+// UpsertGroups mocks base method.
+func (m *MockItemMetadataRepository) UpsertGroups(arg0 context.Context, arg1 []models.ItemGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertGroups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertGroups indicates an expected call of UpsertGroups.
+func (mr *MockItemMetadataRepositoryMockRecorder) UpsertGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertGroups", reflect.TypeOf((*MockItemMetadataRepository)(nil).UpsertGroups), arg0, arg1)
+}
+
+// UpsertMarketGroups mocks base method.
+func (m *MockItemMetadataRepository) UpsertMarketGroups(arg0 context.Context, arg1 []models.MarketGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertMarketGroups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertMarketGroups indicates an expected call of UpsertMarketGroups.
+func (mr *MockItemMetadataRepositoryMockRecorder) UpsertMarketGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertMarketGroups", reflect.TypeOf((*MockItemMetadataRepository)(nil).UpsertMarketGroups), arg0, arg1)
+}
+
+// UpsertMetaGroups mocks base method.
+func (m *MockItemMetadataRepository) UpsertMetaGroups(arg0 context.Context, arg1 []models.MetaGroup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertMetaGroups", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertMetaGroups indicates an expected call of UpsertMetaGroups.
+func (mr *MockItemMetadataRepositoryMockRecorder) UpsertMetaGroups(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertMetaGroups", reflect.TypeOf((*MockItemMetadataRepository)(nil).UpsertMetaGroups), arg0, arg1)
+}
+
+// MockRegionRepository is a mock of RegionRepository interface.
+type MockRegionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRegionRepositoryMockRecorder
+}
+
+// MockRegionRepositoryMockRecorder is the mock recorder for MockRegionRepository.
+type MockRegionRepositoryMockRecorder struct {
+	mock *MockRegionRepository
+}
+
+// NewMockRegionRepository creates a new mock instance.
+func NewMockRegionRepository(ctrl *gomock.Controller) *MockRegionRepository {
+	mock := &MockRegionRepository{ctrl: ctrl}
+	mock.recorder = &MockRegionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRegionRepository) EXPECT() *MockRegionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockRegionRepository) Upsert(arg0 context.Context, arg1 []models.Region) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockRegionRepositoryMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockRegionRepository)(nil).Upsert), arg0, arg1)
+}
+
+// MockConstellationRepository is a mock of ConstellationRepository interface.
+type MockConstellationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockConstellationRepositoryMockRecorder
+}
+
+// MockConstellationRepositoryMockRecorder is the mock recorder for MockConstellationRepository.
+type MockConstellationRepositoryMockRecorder struct {
+	mock *MockConstellationRepository
+}
+
+// NewMockConstellationRepository creates a new mock instance.
+func NewMockConstellationRepository(ctrl *gomock.Controller) *MockConstellationRepository {
+	mock := &MockConstellationRepository{ctrl: ctrl}
+	mock.recorder = &MockConstellationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConstellationRepository) EXPECT() *MockConstellationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockConstellationRepository) Upsert(arg0 context.Context, arg1 []models.Constellation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockConstellationRepositoryMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockConstellationRepository)(nil).Upsert), arg0, arg1)
+}
+
+// MockSolarSystemRepository is a mock of SolarSystemRepository interface.
+type MockSolarSystemRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSolarSystemRepositoryMockRecorder
+}
+
+// MockSolarSystemRepositoryMockRecorder is the mock recorder for MockSolarSystemRepository.
+type MockSolarSystemRepositoryMockRecorder struct {
+	mock *MockSolarSystemRepository
+}
+
+// NewMockSolarSystemRepository creates a new mock instance.
+func NewMockSolarSystemRepository(ctrl *gomock.Controller) *MockSolarSystemRepository {
+	mock := &MockSolarSystemRepository{ctrl: ctrl}
+	mock.recorder = &MockSolarSystemRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSolarSystemRepository) EXPECT() *MockSolarSystemRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockSolarSystemRepository) Upsert(arg0 context.Context, arg1 []models.SolarSystem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSolarSystemRepositoryMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSolarSystemRepository)(nil).Upsert), arg0, arg1)
+}
+
+// MockStationRepository is a mock of StationRepository interface.
+type MockStationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStationRepositoryMockRecorder
+}
+
+// MockStationRepositoryMockRecorder is the mock recorder for MockStationRepository.
+type MockStationRepositoryMockRecorder struct {
+	mock *MockStationRepository
+}
+
+// NewMockStationRepository creates a new mock instance.
+func NewMockStationRepository(ctrl *gomock.Controller) *MockStationRepository {
+	mock := &MockStationRepository{ctrl: ctrl}
+	mock.recorder = &MockStationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStationRepository) EXPECT() *MockStationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Upsert mocks base method.
+func (m *MockStationRepository) Upsert(arg0 context.Context, arg1 []models.Station) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockStationRepositoryMockRecorder) Upsert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockStationRepository)(nil).Upsert), arg0, arg1)
+}
@@ -1,9 +1,125 @@
 package updaters_test
 
+//go:generate mockgen -destination=static_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters FuzzWorksClient,ItemTypeRepository,ItemMetadataRepository,RegionRepository,ConstellationRepository,SolarSystemRepository,StationRepository
+
 import (
+	"context"
 	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_ItemType(t *testing.T) {
 
 }
+
+type staticMocks struct {
+	client         *MockFuzzWorksClient
+	itemTypes      *MockItemTypeRepository
+	itemMetadata   *MockItemMetadataRepository
+	regions        *MockRegionRepository
+	constellations *MockConstellationRepository
+	systems        *MockSolarSystemRepository
+	stations       *MockStationRepository
+}
+
+func newStaticMocks(ctrl *gomock.Controller) *staticMocks {
+	return &staticMocks{
+		client:         NewMockFuzzWorksClient(ctrl),
+		itemTypes:      NewMockItemTypeRepository(ctrl),
+		itemMetadata:   NewMockItemMetadataRepository(ctrl),
+		regions:        NewMockRegionRepository(ctrl),
+		constellations: NewMockConstellationRepository(ctrl),
+		systems:        NewMockSolarSystemRepository(ctrl),
+		stations:       NewMockStationRepository(ctrl),
+	}
+}
+
+func (m *staticMocks) updater() *updaters.Static {
+	return updaters.NewStatic(m.client, m.itemTypes, m.itemMetadata, m.regions, m.constellations, m.systems, m.stations)
+}
+
+func (m *staticMocks) expectUniverse() {
+	m.client.EXPECT().GetRegions(gomock.Any()).Return([]models.Region{}, nil)
+	m.regions.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+	m.client.EXPECT().GetConstellations(gomock.Any()).Return([]models.Constellation{}, nil)
+	m.constellations.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+	m.client.EXPECT().GetSolarSystems(gomock.Any()).Return([]models.SolarSystem{}, nil)
+	m.systems.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+	m.client.EXPECT().GetNPCStations(gomock.Any()).Return([]models.Station{}, nil)
+	m.stations.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(nil)
+}
+
+func Test_StaticShouldImportItemMetadata(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := newStaticMocks(ctrl)
+	mocks.expectUniverse()
+
+	categories := []models.ItemCategory{{ID: 7, Name: "Module", Published: true}}
+	groups := []models.ItemGroup{{ID: 55, CategoryID: 7, Name: "Projectile Weapon", Published: true}}
+	marketGroups := []models.MarketGroup{{ID: 10, Name: "Ship Equipment"}}
+	metaGroups := []models.MetaGroup{{ID: 2, Name: "Tech II"}}
+
+	mocks.client.EXPECT().GetItemCategories(gomock.Any()).Return(categories, nil)
+	mocks.itemMetadata.EXPECT().UpsertCategories(gomock.Any(), categories).Return(nil)
+	mocks.client.EXPECT().GetItemGroups(gomock.Any()).Return(groups, nil)
+	mocks.itemMetadata.EXPECT().UpsertGroups(gomock.Any(), groups).Return(nil)
+	mocks.client.EXPECT().GetMarketGroups(gomock.Any()).Return(marketGroups, nil)
+	mocks.itemMetadata.EXPECT().UpsertMarketGroups(gomock.Any(), marketGroups).Return(nil)
+	mocks.client.EXPECT().GetMetaGroups(gomock.Any()).Return(metaGroups, nil)
+	mocks.itemMetadata.EXPECT().UpsertMetaGroups(gomock.Any(), metaGroups).Return(nil)
+
+	groupID := int64(55)
+	parentTypeID := int64(484)
+	mocks.client.EXPECT().GetInventoryTypes(gomock.Any()).Return([]models.EveInventoryType{
+		{TypeID: 2889, TypeName: "200mm AutoCannon II", Volume: 5, GroupID: &groupID, Published: true},
+		{TypeID: 587, TypeName: "Rifter", Volume: 27289, Published: true},
+		{TypeID: 34, TypeName: "Tritanium", Volume: 0.01, Published: true},
+	}, nil)
+	mocks.client.EXPECT().GetMetaTypes(gomock.Any()).Return(map[int64]models.ItemMetaType{
+		2889: {TypeID: 2889, ParentTypeID: &parentTypeID, MetaGroupID: 2},
+	}, nil)
+	mocks.client.EXPECT().GetPackagedVolumes(gomock.Any()).Return(map[int64]float64{587: 2500}, nil)
+
+	var upserted []models.EveInventoryType
+	mocks.itemTypes.EXPECT().
+		UpsertItemTypes(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, items []models.EveInventoryType) error {
+			upserted = items
+			return nil
+		})
+
+	err := mocks.updater().Update(context.Background())
+	assert.NoError(t, err)
+
+	if assert.Len(t, upserted, 3) {
+		assert.Equal(t, int64(2), *upserted[0].MetaGroupID)
+		assert.Equal(t, parentTypeID, *upserted[0].ParentTypeID)
+		assert.Nil(t, upserted[0].PackagedVolume)
+
+		assert.Nil(t, upserted[1].MetaGroupID)
+		assert.Equal(t, 2500.0, *upserted[1].PackagedVolume)
+
+		assert.Nil(t, upserted[2].MetaGroupID)
+		assert.Nil(t, upserted[2].PackagedVolume)
+	}
+}
+
+func Test_StaticShouldStopWhenMetadataFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mocks := newStaticMocks(ctrl)
+	mocks.expectUniverse()
+
+	mocks.client.EXPECT().GetItemCategories(gomock.Any()).Return(nil, errors.New("timeout"))
+
+	err := mocks.updater().Update(context.Background())
+	assert.ErrorContains(t, err, "failed to get item categories from fuzzworks")
+}
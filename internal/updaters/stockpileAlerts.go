@@ -0,0 +1,117 @@
+package updaters
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+type StockpileAlertsRepository interface {
+	GetStates(ctx context.Context, userID int64) ([]*models.StockpileAlertState, error)
+	SetTriggered(ctx context.Context, markerID int64, triggered bool) error
+}
+
+type NotificationSettingsRepository interface {
+	Get(ctx context.Context, userID int64) (*models.NotificationSettings, error)
+}
+
+type NotificationDispatcher interface {
+	Dispatch(ctx context.Context, settings *models.NotificationSettings, notification *models.Notification) error
+}
+
+type StockpileAlerts struct {
+	repository         StockpileAlertsRepository
+	settingsRepository NotificationSettingsRepository
+	dispatcher         NotificationDispatcher
+}
+
+func NewStockpileAlerts(repository StockpileAlertsRepository, settingsRepository NotificationSettingsRepository, dispatcher NotificationDispatcher) *StockpileAlerts {
+	return &StockpileAlerts{
+		repository:         repository,
+		settingsRepository: settingsRepository,
+		dispatcher:         dispatcher,
+	}
+}
+
+// CheckUserAlerts notifies the user of every marker that crossed its alert threshold since the
+// last sync. A marker alerts once per crossing and is re-armed when the stockpile recovers.
+func (u *StockpileAlerts) CheckUserAlerts(ctx context.Context, userID int64) error {
+	states, err := u.repository.GetStates(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get stockpile alert states")
+	}
+
+	var settings *models.NotificationSettings
+	sent := 0
+	for _, state := range states {
+		reasons := stockpileAlertReasons(state)
+		firing := len(reasons) > 0
+		if firing == state.Triggered {
+			continue
+		}
+
+		if firing {
+			if settings == nil {
+				settings, err = u.settingsRepository.Get(ctx, userID)
+				if err != nil {
+					return errors.Wrap(err, "failed to get notification settings")
+				}
+				if settings == nil {
+					settings = &models.NotificationSettings{UserID: userID}
+				}
+			}
+
+			markerID := state.MarkerID
+			notification := &models.Notification{
+				UserID:            userID,
+				Kind:              models.NotificationKindStockpileAlert,
+				Title:             fmt.Sprintf("%s is low at %s", state.TypeName, state.LocationName),
+				Message:           stockpileAlertMessage(state, reasons),
+				StockpileMarkerID: &markerID,
+			}
+
+			// Delivery is best effort; the alert is still marked sent so a broken webhook
+			// doesn't repeat it on every sync
+			if err := u.dispatcher.Dispatch(ctx, settings, notification); err != nil {
+				log.Warn("stockpile alert delivery failed", "user_id", userID, "marker_id", state.MarkerID, "error", err)
+			}
+			sent++
+		}
+
+		if err := u.repository.SetTriggered(ctx, state.MarkerID, firing); err != nil {
+			return errors.Wrapf(err, "failed to update alert state of stockpile marker %d", state.MarkerID)
+		}
+	}
+
+	if sent > 0 {
+		log.Info("sent stockpile alerts", "user_id", userID, "count", sent)
+	}
+
+	return nil
+}
+
+// stockpileAlertReasons describes each configured threshold the marker is past, if any
+func stockpileAlertReasons(state *models.StockpileAlertState) []string {
+	reasons := []string{}
+	if state.AlertBelowQuantity != nil && state.HeldQuantity < *state.AlertBelowQuantity {
+		reasons = append(reasons, fmt.Sprintf("holding %d, below the alert threshold of %d", state.HeldQuantity, *state.AlertBelowQuantity))
+	}
+	if state.AlertDeficitValue != nil && state.DeficitValue > *state.AlertDeficitValue {
+		reasons = append(reasons, fmt.Sprintf("deficit worth %.0f ISK, above the alert limit of %.0f ISK", state.DeficitValue, *state.AlertDeficitValue))
+	}
+	return reasons
+}
+
+func stockpileAlertMessage(state *models.StockpileAlertState, reasons []string) string {
+	return fmt.Sprintf("%s (%s) holds %d of %d desired: %s.",
+		state.LocationName,
+		state.OwnerName,
+		state.HeldQuantity,
+		state.DesiredQuantity,
+		strings.Join(reasons, "; "),
+	)
+}
@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: StockpileAlertsRepository,NotificationSettingsRepository,NotificationDispatcher)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStockpileAlertsRepository is a mock of StockpileAlertsRepository interface.
+type MockStockpileAlertsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockpileAlertsRepositoryMockRecorder
+}
+
+// MockStockpileAlertsRepositoryMockRecorder is the mock recorder for MockStockpileAlertsRepository.
+type MockStockpileAlertsRepositoryMockRecorder struct {
+	mock *MockStockpileAlertsRepository
+}
+
+// NewMockStockpileAlertsRepository creates a new mock instance.
+func NewMockStockpileAlertsRepository(ctrl *gomock.Controller) *MockStockpileAlertsRepository {
+	mock := &MockStockpileAlertsRepository{ctrl: ctrl}
+	mock.recorder = &MockStockpileAlertsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockpileAlertsRepository) EXPECT() *MockStockpileAlertsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetStates mocks base method.
+func (m *MockStockpileAlertsRepository) GetStates(arg0 context.Context, arg1 int64) ([]*models.StockpileAlertState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStates", arg0, arg1)
+	ret0, _ := ret[0].([]*models.StockpileAlertState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStates indicates an expected call of GetStates.
+func (mr *MockStockpileAlertsRepositoryMockRecorder) GetStates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStates", reflect.TypeOf((*MockStockpileAlertsRepository)(nil).GetStates), arg0, arg1)
+}
+
+// SetTriggered mocks base method.
+func (m *MockStockpileAlertsRepository) SetTriggered(arg0 context.Context, arg1 int64, arg2 bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTriggered", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTriggered indicates an expected call of SetTriggered.
+func (mr *MockStockpileAlertsRepositoryMockRecorder) SetTriggered(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTriggered", reflect.TypeOf((*MockStockpileAlertsRepository)(nil).SetTriggered), arg0, arg1, arg2)
+}
+
+// MockNotificationSettingsRepository is a mock of NotificationSettingsRepository interface.
+type MockNotificationSettingsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationSettingsRepositoryMockRecorder
+}
+
+// MockNotificationSettingsRepositoryMockRecorder is the mock recorder for MockNotificationSettingsRepository.
+type MockNotificationSettingsRepositoryMockRecorder struct {
+	mock *MockNotificationSettingsRepository
+}
+
+// NewMockNotificationSettingsRepository creates a new mock instance.
+func NewMockNotificationSettingsRepository(ctrl *gomock.Controller) *MockNotificationSettingsRepository {
+	mock := &MockNotificationSettingsRepository{ctrl: ctrl}
+	mock.recorder = &MockNotificationSettingsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationSettingsRepository) EXPECT() *MockNotificationSettingsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockNotificationSettingsRepository) Get(arg0 context.Context, arg1 int64) (*models.NotificationSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*models.NotificationSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockNotificationSettingsRepositoryMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockNotificationSettingsRepository)(nil).Get), arg0, arg1)
+}
+
+// MockNotificationDispatcher is a mock of NotificationDispatcher interface.
+type MockNotificationDispatcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotificationDispatcherMockRecorder
+}
+
+// MockNotificationDispatcherMockRecorder is the mock recorder for MockNotificationDispatcher.
+type MockNotificationDispatcherMockRecorder struct {
+	mock *MockNotificationDispatcher
+}
+
+// NewMockNotificationDispatcher creates a new mock instance.
+func NewMockNotificationDispatcher(ctrl *gomock.Controller) *MockNotificationDispatcher {
+	mock := &MockNotificationDispatcher{ctrl: ctrl}
+	mock.recorder = &MockNotificationDispatcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotificationDispatcher) EXPECT() *MockNotificationDispatcherMockRecorder {
+	return m.recorder
+}
+
+// Dispatch mocks base method.
+func (m *MockNotificationDispatcher) Dispatch(arg0 context.Context, arg1 *models.NotificationSettings, arg2 *models.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Dispatch", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Dispatch indicates an expected call of Dispatch.
+func (mr *MockNotificationDispatcherMockRecorder) Dispatch(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Dispatch", reflect.TypeOf((*MockNotificationDispatcher)(nil).Dispatch), arg0, arg1, arg2)
+}
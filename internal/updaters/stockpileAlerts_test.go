@@ -0,0 +1,103 @@
+package updaters_test
+
+//go:generate mockgen -destination=stockpileAlerts_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters StockpileAlertsRepository,NotificationSettingsRepository,NotificationDispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StockpileAlertsShouldNotifyOnCrossing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileAlertsRepository(ctrl)
+	mockSettings := NewMockNotificationSettingsRepository(ctrl)
+	mockDispatcher := NewMockNotificationDispatcher(ctrl)
+
+	userID := int64(42)
+	below := int64(500)
+	limit := 1000000.0
+	states := []*models.StockpileAlertState{
+		// Newly below threshold - alert
+		{MarkerID: 1, TypeName: "Tritanium", LocationName: "Jita IV - Moon 4", OwnerName: "Pilot",
+			DesiredQuantity: 1000, HeldQuantity: 100, AlertBelowQuantity: &below},
+		// Still below and already alerted - quiet
+		{MarkerID: 2, TypeName: "Pyerite", DesiredQuantity: 1000, HeldQuantity: 100, AlertBelowQuantity: &below, Triggered: true},
+		// Recovered - re-arm
+		{MarkerID: 3, TypeName: "Mexallon", DesiredQuantity: 1000, HeldQuantity: 900, AlertBelowQuantity: &below, Triggered: true},
+		// Deficit value over the limit - alert
+		{MarkerID: 4, TypeName: "Zydrine", LocationName: "The Forge", OwnerName: "All owners",
+			DesiredQuantity: 1000, HeldQuantity: 600, DeficitValue: 2000000, AlertDeficitValue: &limit},
+		// Fine - quiet
+		{MarkerID: 5, TypeName: "Isogen", DesiredQuantity: 1000, HeldQuantity: 800, AlertBelowQuantity: &below},
+	}
+
+	webhook := "https://discord.example/webhook"
+	settings := &models.NotificationSettings{UserID: userID, WebhookURL: &webhook}
+
+	mockRepo.EXPECT().GetStates(gomock.Any(), userID).Return(states, nil)
+	mockSettings.EXPECT().Get(gomock.Any(), userID).Return(settings, nil).Times(1)
+
+	sent := []*models.Notification{}
+	mockDispatcher.EXPECT().Dispatch(gomock.Any(), settings, gomock.Any()).DoAndReturn(func(ctx context.Context, s *models.NotificationSettings, n *models.Notification) error {
+		sent = append(sent, n)
+		return nil
+	}).Times(2)
+
+	mockRepo.EXPECT().SetTriggered(gomock.Any(), int64(1), true).Return(nil)
+	mockRepo.EXPECT().SetTriggered(gomock.Any(), int64(3), false).Return(nil)
+	mockRepo.EXPECT().SetTriggered(gomock.Any(), int64(4), true).Return(nil)
+
+	updater := updaters.NewStockpileAlerts(mockRepo, mockSettings, mockDispatcher)
+	err := updater.CheckUserAlerts(context.Background(), userID)
+	require.NoError(t, err)
+
+	require.Len(t, sent, 2)
+	assert.Equal(t, models.NotificationKindStockpileAlert, sent[0].Kind)
+	assert.Equal(t, int64(1), *sent[0].StockpileMarkerID)
+	assert.Equal(t, "Tritanium is low at Jita IV - Moon 4", sent[0].Title)
+	assert.Equal(t, "Jita IV - Moon 4 (Pilot) holds 100 of 1000 desired: holding 100, below the alert threshold of 500.", sent[0].Message)
+	assert.Contains(t, sent[1].Message, "deficit worth 2000000 ISK, above the alert limit of 1000000 ISK")
+}
+
+func Test_StockpileAlertsShouldMarkSentWhenDeliveryFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileAlertsRepository(ctrl)
+	mockSettings := NewMockNotificationSettingsRepository(ctrl)
+	mockDispatcher := NewMockNotificationDispatcher(ctrl)
+
+	below := int64(500)
+	mockRepo.EXPECT().GetStates(gomock.Any(), int64(42)).Return([]*models.StockpileAlertState{
+		{MarkerID: 1, DesiredQuantity: 1000, HeldQuantity: 0, AlertBelowQuantity: &below},
+	}, nil)
+	mockSettings.EXPECT().Get(gomock.Any(), int64(42)).Return(nil, nil)
+	mockDispatcher.EXPECT().Dispatch(gomock.Any(), &models.NotificationSettings{UserID: 42}, gomock.Any()).Return(errors.New("webhook returned statusCode 404"))
+	mockRepo.EXPECT().SetTriggered(gomock.Any(), int64(1), true).Return(nil)
+
+	updater := updaters.NewStockpileAlerts(mockRepo, mockSettings, mockDispatcher)
+	assert.NoError(t, updater.CheckUserAlerts(context.Background(), 42))
+}
+
+func Test_StockpileAlertsShouldReturnErrorWhenStatesFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileAlertsRepository(ctrl)
+
+	mockRepo.EXPECT().GetStates(gomock.Any(), int64(42)).Return(nil, errors.New("database error"))
+
+	updater := updaters.NewStockpileAlerts(mockRepo, NewMockNotificationSettingsRepository(ctrl), NewMockNotificationDispatcher(ctrl))
+	err := updater.CheckUserAlerts(context.Background(), 42)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get stockpile alert states")
+}
@@ -0,0 +1,214 @@
+package updaters
+
+import (
+	"context"
+	"math"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+// stockpileBuyOrderNotes is set on buy orders created for a stockpile marker
+const stockpileBuyOrderNotes = "Automatic stockpile replenishment"
+
+type StockpileBuyOrdersRepository interface {
+	GetTargets(ctx context.Context, userID int64) ([]*models.StockpileBuyOrderTarget, error)
+	CloseOrphaned(ctx context.Context, userID int64) ([]int64, error)
+}
+
+type StockpileBuyOrderWriter interface {
+	Create(ctx context.Context, order *models.BuyOrder) error
+	GetByID(ctx context.Context, id int64) (*models.BuyOrder, error)
+	Update(ctx context.Context, order *models.BuyOrder) error
+}
+
+type StockpileBuyOrders struct {
+	repository StockpileBuyOrdersRepository
+	buyOrders  StockpileBuyOrderWriter
+	publisher  EventPublisher
+}
+
+func NewStockpileBuyOrders(repository StockpileBuyOrdersRepository, buyOrders StockpileBuyOrderWriter) *StockpileBuyOrders {
+	return &StockpileBuyOrders{
+		repository: repository,
+		buyOrders:  buyOrders,
+	}
+}
+
+// WithEvents publishes buy_order.created and buy_order.updated for the orders a sync opens,
+// resizes, reprices or closes, as the buy orders controller does for orders changed by hand
+func (u *StockpileBuyOrders) WithEvents(publisher EventPublisher) *StockpileBuyOrders {
+	u.publisher = publisher
+	return u
+}
+
+// SyncUserBuyOrders keeps one buy order per opted-in stockpile marker wanting exactly the
+// marker's current deficit. Orders are opened when a deficit appears, resized as it changes and
+// closed once the stockpile is full or the marker is removed or opted out.
+func (u *StockpileBuyOrders) SyncUserBuyOrders(ctx context.Context, userID int64) error {
+	targets, err := u.repository.GetTargets(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get stockpile buy order targets")
+	}
+
+	created := 0
+	updated := 0
+	closed := 0
+	unpriced := 0
+	for _, target := range targets {
+		deficit := target.DesiredQuantity - target.HeldQuantity
+		order := target.BuyOrder
+
+		if order == nil {
+			if deficit <= 0 {
+				continue
+			}
+
+			rule := stockpileBuyOrderRule(target, nil)
+			maxPrice := stockpileBuyOrderFallback(target, rule)
+			if !stockpileBuyOrderPriced(maxPrice, rule) {
+				// Opened once Jita has a buy price to base it on
+				unpriced++
+				continue
+			}
+
+			markerID := target.MarkerID
+			notes := stockpileBuyOrderNotes
+			order = &models.BuyOrder{
+				BuyerUserID:       userID,
+				TypeID:            target.TypeID,
+				QuantityDesired:   deficit,
+				MaxPricePerUnit:   maxPrice,
+				PricingRule:       rule,
+				Notes:             &notes,
+				IsActive:          true,
+				StockpileMarkerID: &markerID,
+			}
+			if err := u.buyOrders.Create(ctx, order); err != nil {
+				return errors.Wrapf(err, "failed to create buy order for stockpile marker %d", target.MarkerID)
+			}
+			u.publish(ctx, userID, events.BuyOrderCreated, order)
+			created++
+			continue
+		}
+
+		if deficit <= 0 {
+			if !order.IsActive {
+				continue
+			}
+			// The quantity is left as it was, since orders must want at least one unit
+			order.IsActive = false
+			if err := u.buyOrders.Update(ctx, order); err != nil {
+				return errors.Wrapf(err, "failed to close buy order %d", order.ID)
+			}
+			u.publish(ctx, userID, events.BuyOrderUpdated, order)
+			closed++
+			continue
+		}
+
+		rule := stockpileBuyOrderRule(target, order.PricingRule)
+		sameRule := sameStockpileBuyOrderRule(order.PricingRule, rule)
+
+		// The fallback price is refreshed when the markup changes or there was none yet,
+		// rather than on every move of the Jita price
+		maxPrice := order.MaxPricePerUnit
+		if fallback := stockpileBuyOrderFallback(target, rule); fallback > 0 && (maxPrice <= 0 || !sameRule) {
+			maxPrice = fallback
+		}
+
+		if !stockpileBuyOrderPriced(maxPrice, rule) {
+			unpriced++
+			if !order.IsActive {
+				continue
+			}
+			order.IsActive = false
+			if err := u.buyOrders.Update(ctx, order); err != nil {
+				return errors.Wrapf(err, "failed to close unpriced buy order %d", order.ID)
+			}
+			u.publish(ctx, userID, events.BuyOrderUpdated, order)
+			closed++
+			continue
+		}
+
+		if order.IsActive && order.QuantityDesired == deficit && order.MaxPricePerUnit == maxPrice && sameRule {
+			continue
+		}
+
+		order.IsActive = true
+		order.QuantityDesired = deficit
+		order.MaxPricePerUnit = maxPrice
+		order.PricingRule = rule
+		if err := u.buyOrders.Update(ctx, order); err != nil {
+			return errors.Wrapf(err, "failed to update buy order %d", order.ID)
+		}
+		u.publish(ctx, userID, events.BuyOrderUpdated, order)
+		updated++
+	}
+
+	orphaned, err := u.repository.CloseOrphaned(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to close orphaned stockpile buy orders")
+	}
+	if u.publisher != nil {
+		// The orders are already closed, so a failed lookup only loses the event
+		for _, id := range orphaned {
+			order, err := u.buyOrders.GetByID(ctx, id)
+			if err != nil {
+				log.Error("failed to load closed stockpile buy order for its event", "buy_order_id", id, "error", err)
+				continue
+			}
+			u.publish(ctx, userID, events.BuyOrderUpdated, order)
+		}
+	}
+
+	if created > 0 || updated > 0 || closed > 0 || len(orphaned) > 0 {
+		log.Info("synced stockpile buy orders", "user_id", userID, "created", created, "updated", updated, "closed", closed+len(orphaned))
+	}
+	if unpriced > 0 {
+		log.Info("skipped stockpile buy orders without a Jita buy price", "user_id", userID, "count", unpriced)
+	}
+
+	return nil
+}
+
+func (u *StockpileBuyOrders) publish(ctx context.Context, userID int64, eventType string, order *models.BuyOrder) {
+	if u.publisher == nil {
+		return
+	}
+	u.publisher.Publish(ctx, events.New(eventType, userID, order))
+}
+
+// stockpileBuyOrderRule prices an order at the Jita buy price plus the marker's markup, keeping
+// any floor or ceiling set on the existing rule
+func stockpileBuyOrderRule(target *models.StockpileBuyOrderTarget, existing *models.PricingRule) *models.PricingRule {
+	rule := &models.PricingRule{
+		Basis:   "buy",
+		Percent: 100 + target.MarkupPercent,
+	}
+	if existing != nil {
+		rule.Floor = existing.Floor
+		rule.Ceiling = existing.Ceiling
+	}
+	return rule
+}
+
+// stockpileBuyOrderFallback is the rule evaluated against the current Jita buy price, stored as
+// the fixed price the order falls back on if Jita later has none; 0 when there is no price now
+func stockpileBuyOrderFallback(target *models.StockpileBuyOrderTarget, rule *models.PricingRule) int64 {
+	return int64(math.Round(target.JitaBuyPrice * rule.Percent / 100))
+}
+
+// stockpileBuyOrderPriced reports whether an order has a fallback price or floor, so it is never
+// offered at 0 ISK while Jita has no buy price
+func stockpileBuyOrderPriced(maxPrice int64, rule *models.PricingRule) bool {
+	return maxPrice > 0 || (rule.Floor != nil && *rule.Floor > 0)
+}
+
+func sameStockpileBuyOrderRule(a, b *models.PricingRule) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Basis == b.Basis && a.Percent == b.Percent
+}
@@ -0,0 +1,210 @@
+package updaters_test
+
+//go:generate mockgen -destination=stockpileBuyOrders_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters StockpileBuyOrdersRepository,StockpileBuyOrderWriter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StockpileBuyOrdersShouldTrackDeficits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileBuyOrdersRepository(ctrl)
+	mockWriter := NewMockStockpileBuyOrderWriter(ctrl)
+
+	userID := int64(42)
+	ceiling := int64(9)
+	targets := []*models.StockpileBuyOrderTarget{
+		// New deficit - open an order
+		{MarkerID: 1, TypeID: 34, DesiredQuantity: 1000, HeldQuantity: 400, MarkupPercent: 5, JitaBuyPrice: 5},
+		// Stockpile full and no order yet - nothing to do
+		{MarkerID: 2, TypeID: 35, DesiredQuantity: 100, HeldQuantity: 150},
+		// Deficit shrank - resize, keeping the user's ceiling
+		{MarkerID: 3, TypeID: 36, DesiredQuantity: 500, HeldQuantity: 300, MarkupPercent: 10, JitaBuyPrice: 5,
+			BuyOrder: &models.BuyOrder{ID: 30, QuantityDesired: 450, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 110, Ceiling: &ceiling}}},
+		// Refilled - close
+		{MarkerID: 4, TypeID: 37, DesiredQuantity: 500, HeldQuantity: 500,
+			BuyOrder: &models.BuyOrder{ID: 40, QuantityDesired: 20, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+		// Already in line - untouched
+		{MarkerID: 5, TypeID: 38, DesiredQuantity: 50, HeldQuantity: 0, JitaBuyPrice: 5,
+			BuyOrder: &models.BuyOrder{ID: 50, QuantityDesired: 50, MaxPricePerUnit: 5, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+		// Closed earlier and short again - reopen
+		{MarkerID: 6, TypeID: 39, DesiredQuantity: 50, HeldQuantity: 10, JitaBuyPrice: 5,
+			BuyOrder: &models.BuyOrder{ID: 60, QuantityDesired: 5, IsActive: false,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+	}
+
+	mockRepo.EXPECT().GetTargets(gomock.Any(), userID).Return(targets, nil)
+
+	mockWriter.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *models.BuyOrder) error {
+		assert.Equal(t, userID, order.BuyerUserID)
+		assert.Equal(t, int64(34), order.TypeID)
+		assert.Equal(t, int64(600), order.QuantityDesired)
+		assert.True(t, order.IsActive)
+		require.NotNil(t, order.StockpileMarkerID)
+		assert.Equal(t, int64(1), *order.StockpileMarkerID)
+		assert.Equal(t, &models.PricingRule{Basis: "buy", Percent: 105}, order.PricingRule)
+		assert.Equal(t, int64(5), order.MaxPricePerUnit)
+		return nil
+	})
+
+	updates := map[int64]*models.BuyOrder{}
+	mockWriter.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *models.BuyOrder) error {
+		updates[order.ID] = order
+		return nil
+	}).Times(3)
+
+	mockRepo.EXPECT().CloseOrphaned(gomock.Any(), userID).Return([]int64{70}, nil)
+
+	updater := updaters.NewStockpileBuyOrders(mockRepo, mockWriter)
+	err := updater.SyncUserBuyOrders(context.Background(), userID)
+	require.NoError(t, err)
+
+	require.Len(t, updates, 3)
+	assert.Equal(t, int64(200), updates[30].QuantityDesired)
+	assert.Equal(t, &ceiling, updates[30].PricingRule.Ceiling)
+	assert.Equal(t, int64(6), updates[30].MaxPricePerUnit)
+	assert.False(t, updates[40].IsActive)
+	assert.Equal(t, int64(20), updates[40].QuantityDesired)
+	assert.True(t, updates[60].IsActive)
+	assert.Equal(t, int64(40), updates[60].QuantityDesired)
+	assert.Equal(t, int64(5), updates[60].MaxPricePerUnit)
+}
+
+func Test_StockpileBuyOrdersShouldRepriceWhenMarkupChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileBuyOrdersRepository(ctrl)
+	mockWriter := NewMockStockpileBuyOrderWriter(ctrl)
+
+	mockRepo.EXPECT().GetTargets(gomock.Any(), int64(42)).Return([]*models.StockpileBuyOrderTarget{
+		{MarkerID: 1, TypeID: 34, DesiredQuantity: 100, HeldQuantity: 0, MarkupPercent: 15, JitaBuyPrice: 20,
+			BuyOrder: &models.BuyOrder{ID: 10, QuantityDesired: 100, MaxPricePerUnit: 21, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 105}}},
+	}, nil)
+	mockWriter.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *models.BuyOrder) error {
+		assert.Equal(t, 115.0, order.PricingRule.Percent)
+		assert.Equal(t, int64(23), order.MaxPricePerUnit)
+		return nil
+	})
+	mockRepo.EXPECT().CloseOrphaned(gomock.Any(), int64(42)).Return([]int64{}, nil)
+
+	updater := updaters.NewStockpileBuyOrders(mockRepo, mockWriter)
+	assert.NoError(t, updater.SyncUserBuyOrders(context.Background(), 42))
+}
+
+func Test_StockpileBuyOrdersShouldNotOfferOrdersWithoutAPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileBuyOrdersRepository(ctrl)
+	mockWriter := NewMockStockpileBuyOrderWriter(ctrl)
+
+	floor := int64(4)
+	mockRepo.EXPECT().GetTargets(gomock.Any(), int64(42)).Return([]*models.StockpileBuyOrderTarget{
+		// No Jita price and no order yet - not opened
+		{MarkerID: 1, TypeID: 34, DesiredQuantity: 100, HeldQuantity: 0},
+		// No Jita price and an order with no fallback - closed
+		{MarkerID: 2, TypeID: 35, DesiredQuantity: 100, HeldQuantity: 0,
+			BuyOrder: &models.BuyOrder{ID: 20, QuantityDesired: 100, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+		// No Jita price but a stored fallback - kept as it is
+		{MarkerID: 3, TypeID: 36, DesiredQuantity: 100, HeldQuantity: 0,
+			BuyOrder: &models.BuyOrder{ID: 30, QuantityDesired: 100, MaxPricePerUnit: 7, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+		// No Jita price but the user's floor - resized
+		{MarkerID: 4, TypeID: 37, DesiredQuantity: 100, HeldQuantity: 50,
+			BuyOrder: &models.BuyOrder{ID: 40, QuantityDesired: 100, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100, Floor: &floor}}},
+	}, nil)
+
+	updates := map[int64]*models.BuyOrder{}
+	mockWriter.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *models.BuyOrder) error {
+		updates[order.ID] = order
+		return nil
+	}).Times(2)
+	mockRepo.EXPECT().CloseOrphaned(gomock.Any(), int64(42)).Return([]int64{}, nil)
+
+	updater := updaters.NewStockpileBuyOrders(mockRepo, mockWriter)
+	require.NoError(t, updater.SyncUserBuyOrders(context.Background(), 42))
+
+	require.Len(t, updates, 2)
+	assert.False(t, updates[20].IsActive)
+	assert.True(t, updates[40].IsActive)
+	assert.Equal(t, int64(50), updates[40].QuantityDesired)
+	assert.Equal(t, int64(0), updates[40].MaxPricePerUnit)
+}
+
+func Test_StockpileBuyOrdersShouldPublishOrderChanges(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileBuyOrdersRepository(ctrl)
+	mockWriter := NewMockStockpileBuyOrderWriter(ctrl)
+	mockPublisher := NewMockEventPublisher(ctrl)
+
+	mockRepo.EXPECT().GetTargets(gomock.Any(), int64(42)).Return([]*models.StockpileBuyOrderTarget{
+		// Opened
+		{MarkerID: 1, TypeID: 34, DesiredQuantity: 100, HeldQuantity: 0, JitaBuyPrice: 5},
+		// Refilled - closed
+		{MarkerID: 2, TypeID: 35, DesiredQuantity: 100, HeldQuantity: 100,
+			BuyOrder: &models.BuyOrder{ID: 20, QuantityDesired: 10, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+		// Already in line - no event
+		{MarkerID: 3, TypeID: 36, DesiredQuantity: 50, HeldQuantity: 0, JitaBuyPrice: 5,
+			BuyOrder: &models.BuyOrder{ID: 30, QuantityDesired: 50, MaxPricePerUnit: 5, IsActive: true,
+				PricingRule: &models.PricingRule{Basis: "buy", Percent: 100}}},
+	}, nil)
+	mockWriter.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, order *models.BuyOrder) error {
+		order.ID = 10
+		order.BuyerUserID = 42
+		return nil
+	})
+	mockWriter.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().CloseOrphaned(gomock.Any(), int64(42)).Return([]int64{70}, nil)
+	mockWriter.EXPECT().GetByID(gomock.Any(), int64(70)).Return(&models.BuyOrder{ID: 70, BuyerUserID: 42}, nil)
+
+	published := map[int64]string{}
+	mockPublisher.EXPECT().Publish(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, event *events.Event) {
+		assert.Equal(t, int64(42), event.UserID)
+		published[event.Data.(*models.BuyOrder).ID] = event.Type
+	}).Times(3)
+
+	updater := updaters.NewStockpileBuyOrders(mockRepo, mockWriter).WithEvents(mockPublisher)
+	require.NoError(t, updater.SyncUserBuyOrders(context.Background(), 42))
+
+	assert.Equal(t, map[int64]string{
+		10: events.BuyOrderCreated,
+		20: events.BuyOrderUpdated,
+		70: events.BuyOrderUpdated,
+	}, published)
+}
+
+func Test_StockpileBuyOrdersShouldReturnErrorWhenTargetsFail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockStockpileBuyOrdersRepository(ctrl)
+	mockWriter := NewMockStockpileBuyOrderWriter(ctrl)
+
+	mockRepo.EXPECT().GetTargets(gomock.Any(), int64(42)).Return(nil, errors.New("database error"))
+
+	updater := updaters.NewStockpileBuyOrders(mockRepo, mockWriter)
+	err := updater.SyncUserBuyOrders(context.Background(), 42)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to get stockpile buy order targets")
+}
@@ -0,0 +1,50 @@
+package updaters
+
+import (
+	"context"
+	"time"
+
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/pkg/errors"
+)
+
+// stockpileHistoryRetention is how long stockpile samples are kept. It comfortably covers the
+// window burn rates are computed over.
+const stockpileHistoryRetention = 90 * 24 * time.Hour
+
+type StockpileHistoryRepository interface {
+	Record(ctx context.Context, userID int64, recordedAt time.Time) (int64, error)
+	DeleteBefore(ctx context.Context, userID int64, before time.Time) error
+}
+
+type StockpileHistory struct {
+	repository StockpileHistoryRepository
+}
+
+func NewStockpileHistory(repository StockpileHistoryRepository) *StockpileHistory {
+	return &StockpileHistory{
+		repository: repository,
+	}
+}
+
+// RecordUserHistory samples the quantity held against each of the user's stockpile markers and
+// drops samples older than the retention period
+func (u *StockpileHistory) RecordUserHistory(ctx context.Context, userID int64) error {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	recorded, err := u.repository.Record(ctx, userID, now)
+	if err != nil {
+		return errors.Wrap(err, "failed to record stockpile history")
+	}
+
+	err = u.repository.DeleteBefore(ctx, userID, now.Add(-stockpileHistoryRetention))
+	if err != nil {
+		return errors.Wrap(err, "failed to prune stockpile history")
+	}
+
+	if recorded > 0 {
+		log.Info("recorded stockpile history", "user_id", userID, "count", recorded)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: StockpileHistoryRepository)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStockpileHistoryRepository is a mock of StockpileHistoryRepository interface.
+type MockStockpileHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockpileHistoryRepositoryMockRecorder
+}
+
+// MockStockpileHistoryRepositoryMockRecorder is the mock recorder for MockStockpileHistoryRepository.
+type MockStockpileHistoryRepositoryMockRecorder struct {
+	mock *MockStockpileHistoryRepository
+}
+
+// NewMockStockpileHistoryRepository creates a new mock instance.
+func NewMockStockpileHistoryRepository(ctrl *gomock.Controller) *MockStockpileHistoryRepository {
+	mock := &MockStockpileHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockStockpileHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockpileHistoryRepository) EXPECT() *MockStockpileHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteBefore mocks base method.
+func (m *MockStockpileHistoryRepository) DeleteBefore(arg0 context.Context, arg1 int64, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBefore", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBefore indicates an expected call of DeleteBefore.
+func (mr *MockStockpileHistoryRepositoryMockRecorder) DeleteBefore(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBefore", reflect.TypeOf((*MockStockpileHistoryRepository)(nil).DeleteBefore), arg0, arg1, arg2)
+}
+
+// Record mocks base method.
+func (m *MockStockpileHistoryRepository) Record(arg0 context.Context, arg1 int64, arg2 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockStockpileHistoryRepositoryMockRecorder) Record(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockStockpileHistoryRepository)(nil).Record), arg0, arg1, arg2)
+}
@@ -0,0 +1,56 @@
+package updaters_test
+
+//go:generate mockgen -destination=stockpileHistory_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters StockpileHistoryRepository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_StockpileHistoryShouldRecordAndPrune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := NewMockStockpileHistoryRepository(ctrl)
+
+	var recordedAt time.Time
+	repository.EXPECT().
+		Record(gomock.Any(), int64(42), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID int64, at time.Time) (int64, error) {
+			recordedAt = at
+			return 3, nil
+		})
+	repository.EXPECT().
+		DeleteBefore(gomock.Any(), int64(42), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID int64, before time.Time) error {
+			assert.Equal(t, 90*24*time.Hour, recordedAt.Sub(before))
+			return nil
+		})
+
+	updater := updaters.NewStockpileHistory(repository)
+	err := updater.RecordUserHistory(context.Background(), 42)
+
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), recordedAt, time.Minute)
+}
+
+func Test_StockpileHistoryShouldReturnRecordErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repository := NewMockStockpileHistoryRepository(ctrl)
+	repository.EXPECT().
+		Record(gomock.Any(), int64(42), gomock.Any()).
+		Return(int64(0), errors.New("connection refused"))
+
+	updater := updaters.NewStockpileHistory(repository)
+	err := updater.RecordUserHistory(context.Background(), 42)
+
+	assert.ErrorContains(t, err, "failed to record stockpile history")
+}
@@ -0,0 +1,159 @@
+package updaters
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	log "github.com/annymsMthd/industry-tool/internal/logging"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// webhookMaxAttempts is how many times a delivery is tried before it is marked failed
+	webhookMaxAttempts = 6
+	// webhookRetryBase is the wait after the first failed attempt, doubling after each one after
+	webhookRetryBase = 30 * time.Second
+	// webhookDeliveryBatch caps how many deliveries are sent per sweep
+	webhookDeliveryBatch = 100
+)
+
+type WebhookDeliveriesRepository interface {
+	Enqueue(ctx context.Context, userID int64, eventID string, eventType string, payload []byte) (int64, error)
+	GetDueDeliveries(ctx context.Context, limit int) ([]*models.WebhookDelivery, error)
+	RecordAttempt(ctx context.Context, deliveryID int64, status string, statusCode *int, lastError *string, nextAttemptAt time.Time) error
+}
+
+type WebhookHTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookDeliveries queues bus events for the user's webhooks and sends them, retrying failed
+// deliveries with exponential backoff
+type WebhookDeliveries struct {
+	repository WebhookDeliveriesRepository
+	client     WebhookHTTPClient
+}
+
+func NewWebhookDeliveries(repository WebhookDeliveriesRepository, client WebhookHTTPClient) *WebhookDeliveries {
+	return &WebhookDeliveries{
+		repository: repository,
+		client:     client,
+	}
+}
+
+// Enqueue is subscribed to the event bus and queues the event for every matching webhook of
+// its user. Sending happens on the next DeliverDue sweep so a slow endpoint never holds up the
+// request that caused the event.
+func (u *WebhookDeliveries) Enqueue(ctx context.Context, event *events.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal event")
+	}
+
+	_, err = u.repository.Enqueue(ctx, event.UserID, event.ID, event.Type, payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enqueue %s event", event.Type)
+	}
+
+	return nil
+}
+
+// DeliverDue sends every pending delivery whose next attempt is due
+func (u *WebhookDeliveries) DeliverDue(ctx context.Context) error {
+	deliveries, err := u.repository.GetDueDeliveries(ctx, webhookDeliveryBatch)
+	if err != nil {
+		return errors.Wrap(err, "failed to get due webhook deliveries")
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		statusCode, sendErr := u.send(ctx, delivery)
+
+		attempts := delivery.Attempts + 1
+		status := models.WebhookDeliveryDelivered
+		nextAttemptAt := time.Now()
+		var lastError *string
+		if sendErr != nil {
+			message := sendErr.Error()
+			lastError = &message
+			status = models.WebhookDeliveryPending
+			nextAttemptAt = nextAttemptAt.Add(webhookRetryDelay(attempts))
+			if attempts >= webhookMaxAttempts {
+				status = models.WebhookDeliveryFailed
+			}
+			log.Warn("webhook delivery failed", "delivery_id", delivery.ID, "webhook_id", delivery.WebhookID, "attempts", attempts, "error", sendErr)
+		} else {
+			delivered++
+		}
+
+		err = u.repository.RecordAttempt(ctx, delivery.ID, status, statusCode, lastError, nextAttemptAt)
+		if err != nil {
+			return errors.Wrapf(err, "failed to record attempt for webhook delivery %d", delivery.ID)
+		}
+	}
+
+	if delivered > 0 {
+		log.Info("delivered webhooks", "count", delivered)
+	}
+
+	return nil
+}
+
+func (u *WebhookDeliveries) send(ctx context.Context, delivery *models.WebhookDelivery) (*int, error) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "industry-tool-webhooks")
+	req.Header.Set("X-Industry-Tool-Event", delivery.EventType)
+	req.Header.Set("X-Industry-Tool-Delivery", delivery.EventID)
+	req.Header.Set("X-Industry-Tool-Timestamp", timestamp)
+	req.Header.Set("X-Industry-Tool-Signature", SignWebhookPayload(delivery.Secret, timestamp, delivery.Payload))
+
+	res, err := u.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to post webhook")
+	}
+	defer res.Body.Close()
+
+	statusCode := res.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		errText, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return &statusCode, errors.New(fmt.Sprintf("webhook returned statusCode %d, %s", statusCode, errText))
+	}
+
+	return &statusCode, nil
+}
+
+// SignWebhookPayload returns the X-Industry-Tool-Signature header for a payload: the hex
+// HMAC-SHA256 of "<timestamp>.<payload>" keyed with the webhook secret. Receivers recompute it
+// to check the request came from us and reject old timestamps to stop replays.
+func SignWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay is how long to wait before retrying a delivery that has failed attempts times
+func webhookRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	return webhookRetryBase << (attempts - 1)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/annymsMthd/industry-tool/internal/updaters (interfaces: WebhookDeliveriesRepository)
+
+// Package updaters_test is a generated GoMock package.
+package updaters_test
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/annymsMthd/industry-tool/internal/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWebhookDeliveriesRepository is a mock of WebhookDeliveriesRepository interface.
+type MockWebhookDeliveriesRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookDeliveriesRepositoryMockRecorder
+}
+
+// MockWebhookDeliveriesRepositoryMockRecorder is the mock recorder for MockWebhookDeliveriesRepository.
+type MockWebhookDeliveriesRepositoryMockRecorder struct {
+	mock *MockWebhookDeliveriesRepository
+}
+
+// NewMockWebhookDeliveriesRepository creates a new mock instance.
+func NewMockWebhookDeliveriesRepository(ctrl *gomock.Controller) *MockWebhookDeliveriesRepository {
+	mock := &MockWebhookDeliveriesRepository{ctrl: ctrl}
+	mock.recorder = &MockWebhookDeliveriesRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhookDeliveriesRepository) EXPECT() *MockWebhookDeliveriesRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Enqueue mocks base method.
+func (m *MockWebhookDeliveriesRepository) Enqueue(arg0 context.Context, arg1 int64, arg2, arg3 string, arg4 []byte) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enqueue", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Enqueue indicates an expected call of Enqueue.
+func (mr *MockWebhookDeliveriesRepositoryMockRecorder) Enqueue(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enqueue", reflect.TypeOf((*MockWebhookDeliveriesRepository)(nil).Enqueue), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetDueDeliveries mocks base method.
+func (m *MockWebhookDeliveriesRepository) GetDueDeliveries(arg0 context.Context, arg1 int) ([]*models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDueDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]*models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDueDeliveries indicates an expected call of GetDueDeliveries.
+func (mr *MockWebhookDeliveriesRepositoryMockRecorder) GetDueDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDueDeliveries", reflect.TypeOf((*MockWebhookDeliveriesRepository)(nil).GetDueDeliveries), arg0, arg1)
+}
+
+// RecordAttempt mocks base method.
+func (m *MockWebhookDeliveriesRepository) RecordAttempt(arg0 context.Context, arg1 int64, arg2 string, arg3 *int, arg4 *string, arg5 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttempt", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAttempt indicates an expected call of RecordAttempt.
+func (mr *MockWebhookDeliveriesRepositoryMockRecorder) RecordAttempt(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttempt", reflect.TypeOf((*MockWebhookDeliveriesRepository)(nil).RecordAttempt), arg0, arg1, arg2, arg3, arg4, arg5)
+}
@@ -0,0 +1,158 @@
+package updaters_test
+
+//go:generate mockgen -destination=webhookDeliveries_mocks_test.go -package=updaters_test github.com/annymsMthd/industry-tool/internal/updaters WebhookDeliveriesRepository
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/annymsMthd/industry-tool/internal/events"
+	"github.com/annymsMthd/industry-tool/internal/models"
+	"github.com/annymsMthd/industry-tool/internal/updaters"
+	"github.com/golang/mock/gomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WebhookDeliveriesShouldEnqueueEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWebhookDeliveriesRepository(ctrl)
+
+	event := events.New(events.PurchaseCreated, 42, &models.PurchaseTransaction{ID: 7, QuantityPurchased: 100})
+
+	mockRepo.EXPECT().Enqueue(gomock.Any(), int64(42), event.ID, events.PurchaseCreated, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, userID int64, eventID, eventType string, payload []byte) (int64, error) {
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(payload, &body))
+			assert.Equal(t, event.ID, body["id"])
+			assert.Equal(t, events.PurchaseCreated, body["type"])
+			// The user ID is the routing key, not part of the payload
+			assert.NotContains(t, body, "userId")
+			data := body["data"].(map[string]any)
+			assert.Equal(t, float64(7), data["id"])
+			return 1, nil
+		})
+
+	u := updaters.NewWebhookDeliveries(mockRepo, http.DefaultClient)
+	err := u.Enqueue(context.Background(), event)
+	assert.NoError(t, err)
+}
+
+func Test_WebhookDeliveriesShouldSendSignedPayloads(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWebhookDeliveriesRepository(ctrl)
+
+	payload := []byte(`{"id":"evt1","type":"purchase.created","data":{}}`)
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, payload, body)
+		timestamp := r.Header.Get("X-Industry-Tool-Timestamp")
+		assert.Equal(t, updaters.SignWebhookPayload("s3cret", timestamp, body), r.Header.Get("X-Industry-Tool-Signature"))
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockRepo.EXPECT().GetDueDeliveries(gomock.Any(), gomock.Any()).Return([]*models.WebhookDelivery{
+		{ID: 1, WebhookID: 3, EventID: "evt1", EventType: events.PurchaseCreated, Payload: payload, URL: server.URL, Secret: "s3cret"},
+	}, nil)
+	mockRepo.EXPECT().RecordAttempt(gomock.Any(), int64(1), models.WebhookDeliveryDelivered, gomock.Any(), nil, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64, status string, statusCode *int, lastError *string, next time.Time) error {
+			require.NotNil(t, statusCode)
+			assert.Equal(t, http.StatusNoContent, *statusCode)
+			return nil
+		})
+
+	u := updaters.NewWebhookDeliveries(mockRepo, server.Client())
+	err := u.DeliverDue(context.Background())
+	require.NoError(t, err)
+
+	req := <-received
+	assert.Equal(t, events.PurchaseCreated, req.Header.Get("X-Industry-Tool-Event"))
+	assert.Equal(t, "evt1", req.Header.Get("X-Industry-Tool-Delivery"))
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+}
+
+func Test_WebhookDeliveriesShouldBackOffAndGiveUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWebhookDeliveriesRepository(ctrl)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("boom"))
+	}))
+	defer server.Close()
+
+	mockRepo.EXPECT().GetDueDeliveries(gomock.Any(), gomock.Any()).Return([]*models.WebhookDelivery{
+		// First failure - retry in 30 seconds
+		{ID: 1, EventID: "evt1", Payload: []byte(`{}`), URL: server.URL, Secret: "a", Attempts: 0},
+		// Third failure - retry in 2 minutes
+		{ID: 2, EventID: "evt2", Payload: []byte(`{}`), URL: server.URL, Secret: "a", Attempts: 2},
+		// Sixth failure - give up
+		{ID: 3, EventID: "evt3", Payload: []byte(`{}`), URL: server.URL, Secret: "a", Attempts: 5},
+	}, nil)
+
+	start := time.Now()
+	expectRetry := func(id int64, status string, delay time.Duration) {
+		mockRepo.EXPECT().RecordAttempt(gomock.Any(), id, status, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, id int64, status string, statusCode *int, lastError *string, next time.Time) error {
+				require.NotNil(t, statusCode)
+				assert.Equal(t, http.StatusInternalServerError, *statusCode)
+				require.NotNil(t, lastError)
+				assert.Contains(t, *lastError, "boom")
+				assert.WithinDuration(t, start.Add(delay), next, 5*time.Second)
+				return nil
+			})
+	}
+	expectRetry(1, models.WebhookDeliveryPending, 30*time.Second)
+	expectRetry(2, models.WebhookDeliveryPending, 2*time.Minute)
+	expectRetry(3, models.WebhookDeliveryFailed, 16*time.Minute)
+
+	u := updaters.NewWebhookDeliveries(mockRepo, server.Client())
+	err := u.DeliverDue(context.Background())
+	assert.NoError(t, err)
+}
+
+func Test_WebhookDeliveriesShouldRecordConnectionErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWebhookDeliveriesRepository(ctrl)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	mockRepo.EXPECT().GetDueDeliveries(gomock.Any(), gomock.Any()).Return([]*models.WebhookDelivery{
+		{ID: 1, EventID: "evt1", Payload: []byte(`{}`), URL: url, Secret: "a"},
+	}, nil)
+	mockRepo.EXPECT().RecordAttempt(gomock.Any(), int64(1), models.WebhookDeliveryPending, nil, gomock.Not(gomock.Nil()), gomock.Any()).Return(nil)
+
+	u := updaters.NewWebhookDeliveries(mockRepo, http.DefaultClient)
+	assert.NoError(t, u.DeliverDue(context.Background()))
+}
+
+func Test_WebhookDeliveriesShouldReturnRepositoryErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := NewMockWebhookDeliveriesRepository(ctrl)
+	mockRepo.EXPECT().GetDueDeliveries(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+	u := updaters.NewWebhookDeliveries(mockRepo, http.DefaultClient)
+	err := u.DeliverDue(context.Background())
+	assert.ErrorContains(t, err, "db down")
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 	"time"
@@ -32,6 +34,14 @@ type HandlerArgs struct {
 	User    *int64
 }
 
+// StreamResponse is returned by handlers that write their own body, such as file exports.
+// Headers are sent on the first write, so an error before any output still becomes a 500.
+type StreamResponse struct {
+	ContentType string
+	Filename    string
+	Write       func(w io.Writer) error
+}
+
 type AuthAccess int
 
 const (
@@ -86,6 +96,11 @@ func (r *Router) RegisterRestAPIRoute(path string, access AuthAccess, handler fu
 			return
 		}
 
+		if stream, ok := o.(*StreamResponse); ok {
+			writeStream(w, stream)
+			return
+		}
+
 		bytes, err := json.Marshal(o)
 		if err != nil {
 			log.Error("json format issue occurred", "error", err.Error())
@@ -106,6 +121,46 @@ func (r *Router) RegisterRestAPIRoute(path string, access AuthAccess, handler fu
 	}
 }
 
+func writeStream(w http.ResponseWriter, stream *StreamResponse) {
+	sw := &streamWriter{w: w, stream: stream}
+	err := stream.Write(sw)
+	if err != nil {
+		log.Error("stream error occurred", "error", err.Error(), "started", sw.started)
+		if !sw.started {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(err.Error()))
+		}
+		return
+	}
+
+	sw.start()
+}
+
+// streamWriter delays the response headers until the body starts
+type streamWriter struct {
+	w       http.ResponseWriter
+	stream  *StreamResponse
+	started bool
+}
+
+func (s *streamWriter) start() {
+	if s.started {
+		return
+	}
+	s.started = true
+
+	s.w.Header().Set("Content-Type", s.stream.ContentType)
+	if s.stream.Filename != "" {
+		s.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": s.stream.Filename}))
+	}
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.start()
+	return s.w.Write(p)
+}
+
 func (r *Router) RegisterMiddleware(middleware mux.MiddlewareFunc) {
 	r.router.Use(middleware)
 }